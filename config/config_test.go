@@ -0,0 +1,38 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidate(t *testing.T) {
+	tests := map[string]struct {
+		policy      string
+		interval    int
+		expectedErr bool
+	}{
+		"success with reject policy":             {policy: "reject", interval: 60},
+		"success with allow policy":              {policy: "allow", interval: 60},
+		"error with unknown policy":              {policy: "rejct", interval: 60, expectedErr: true},
+		"error with empty policy":                {policy: "", interval: 60, expectedErr: true},
+		"error with zero scheduler interval":     {policy: "reject", interval: 0, expectedErr: true},
+		"error with negative scheduler interval": {policy: "reject", interval: -5, expectedErr: true},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			cfg := New()
+			cfg.BookDuplicatePolicy = test.policy
+			cfg.SchedulerIntervalMinutes = test.interval
+
+			err := cfg.Validate()
+
+			if test.expectedErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,138 @@
+package controllers
+
+import (
+	"fmt"
+	"github/brunojoenk/golang-test/config"
+	"github/brunojoenk/golang-test/models/dtos"
+	apikeyservice "github/brunojoenk/golang-test/services/apikey"
+	"github/brunojoenk/golang-test/utils"
+	"net/http"
+	"strconv"
+
+	"github.com/pkg/errors"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+type IApiKeyController interface {
+	CreateApiKey(c echo.Context) error
+	GetAllApiKeys(c echo.Context) error
+	RevokeApiKey(c echo.Context) error
+}
+
+type apiKeyController struct {
+	apiKeyService apikeyservice.IApiKeyService
+}
+
+// NewApiKeyController Controller Constructor
+func NewApiKeyController(db *gorm.DB, cfg *config.Config) IApiKeyController {
+	return &apiKeyController{apiKeyService: apikeyservice.NewApiKeyService(db, cfg)}
+}
+
+// CreateApiKey godoc
+// @Summary Create an api key.
+// @Description Create an api key. The plain key is only returned on this response.
+// @Tags ApiKeys
+// @Accept json
+// @Produce json
+// @Param X-API-Key header string true "admin api key"
+// @Param request body dtos.ApiKeyRequestCreate true "query params"
+// @Success 201 {object} dtos.ApiKeyCreatedResponse
+// @Failure 400 {object} string
+// @Failure 401 {object} string
+// @Failure 403 {object} string
+// @Failure 500 {object} string
+// @Router /apikey [post]
+func (a *apiKeyController) CreateApiKey(c echo.Context) error {
+
+	apiKeyRequestCreate := new(dtos.ApiKeyRequestCreate)
+	if err := c.Bind(apiKeyRequestCreate); err != nil {
+		c.Logger().Warn("Error on bind body to create api key: %s", err.Error())
+		return c.JSON(http.StatusBadRequest, fmt.Sprintf("Request body to create an api key is invalid: %s", err.Error()))
+	}
+
+	apiKey, err := a.apiKeyService.CreateApiKey(*apiKeyRequestCreate)
+
+	if err != nil {
+		if errors.Is(err, utils.ErrApiKeyScope) {
+			return c.JSON(http.StatusBadRequest, err.Error())
+		}
+		c.Logger().Error("Error on create api key: %s", err.Error())
+		return c.JSON(http.StatusInternalServerError, "Error on create api key. Please, contact system admin")
+	}
+
+	return c.JSON(http.StatusCreated, apiKey)
+}
+
+// GetAllApiKeys godoc
+// @Summary Show all the api keys with paginations.
+// @Description Show all the api keys with paginations. Revoked keys are hidden unless include_revoked is set.
+// @Tags ApiKeys
+// @Accept */*
+// @Produce json
+// @Param X-API-Key header string true "admin api key"
+// @Param   owner     query     string     false  "search api keys by owner"     example(string)
+// @Param   include_revoked     query     bool     false  "include revoked api keys"     example(false)
+// @Param   page     query     int     false  "page list"     example(1) minimum(1)
+// @Param   limit     query     int     false  "page size"     example(1) minimum(1) maximum(100)
+// @Success 200 {object} dtos.ApiKeyResponseMetadata
+// @Failure 400 {object} string
+// @Failure 401 {object} string
+// @Failure 403 {object} string
+// @Failure 500 {object} string
+// @Router /apikeys [get]
+func (a *apiKeyController) GetAllApiKeys(c echo.Context) error {
+	var filter dtos.GetApiKeysFilter
+	err := c.Bind(&filter)
+	if err != nil {
+		c.Logger().Warn("Error on bind query to filter all api keys: %s", err.Error())
+		return c.JSON(http.StatusBadRequest, fmt.Sprintf("Invalid query parameters: %s", err.Error()))
+	}
+
+	apiKeysResponse, err := a.apiKeyService.GetAllApiKeys(filter)
+
+	if err != nil {
+		c.Logger().Error("Error on get all api keys: %s", err.Error())
+		return c.JSON(http.StatusInternalServerError, "Error on get all api keys. Please, contact admin")
+	}
+
+	return c.JSON(http.StatusOK, apiKeysResponse)
+}
+
+// RevokeApiKey godoc
+// @Summary Revoke an api key.
+// @Description Revoke an api key.
+// @Tags ApiKeys
+// @Accept */*
+// @Produce json
+// @Param X-API-Key header string true "admin api key"
+// @Param id   path int true "Api key ID"
+// @Success 204 {object} string
+// @Failure 400 {object} string
+// @Failure 401 {object} string
+// @Failure 403 {object} string
+// @Failure 404 {object} string
+// @Failure 500 {object} string
+// @Router /apikey/{id} [delete]
+func (a *apiKeyController) RevokeApiKey(c echo.Context) error {
+
+	id, err := strconv.Atoi(c.Param("id"))
+
+	if err != nil {
+		c.Logger().Warn("Error on parse parameters id on revoke api key %s", err.Error())
+		return c.JSON(http.StatusBadRequest, "Invalid query parameter id")
+	}
+
+	err = a.apiKeyService.RevokeApiKey(id)
+
+	if err != nil {
+		if errors.Is(err, utils.ErrApiKeyIdNotFound) {
+			return c.JSON(http.StatusNotFound, err.Error())
+		}
+		c.Logger().Error("Error on revoke api key: %s", err.Error())
+		return c.JSON(http.StatusInternalServerError, "Error on revoke api key. Please, contact system admin")
+	}
+
+	return c.JSON(http.StatusNoContent, "Revoked")
+}
@@ -0,0 +1,112 @@
+package controllers
+
+import (
+	"errors"
+	"github/brunojoenk/golang-test/models/dtos"
+	"github/brunojoenk/golang-test/utils"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	apikeyservicemock "github/brunojoenk/golang-test/services/apikey/mock"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateApiKey(t *testing.T) {
+	bodyRequest := strings.NewReader(`{"name":"batch","owner":"jobs","scopes":["read"]}`)
+	apiKeyRequestCreate := dtos.ApiKeyRequestCreate{Name: "batch", Owner: "jobs", Scopes: []string{"read"}}
+
+	apiKeyServiceMock := new(apikeyservicemock.ApiKeyServiceMock)
+	apiKeyServiceMock.On("CreateApiKey", apiKeyRequestCreate).Return(dtos.ApiKeyCreatedResponse{Key: "gt_key"}, nil)
+
+	apiKeyControllerTest := apiKeyController{apiKeyService: apiKeyServiceMock}
+
+	request, err := http.NewRequest("POST", "/apikey", bodyRequest)
+	request.Header.Add("Content-type", "application/json")
+	recorder := httptest.NewRecorder()
+	e := echo.New()
+	e.POST("/apikey", apiKeyControllerTest.CreateApiKey)
+	e.ServeHTTP(recorder, request)
+
+	require.NoError(t, err)
+	require.Equal(t, http.StatusCreated, recorder.Code)
+	require.Contains(t, recorder.Body.String(), `"key":"gt_key"`)
+}
+
+func TestCreateApiKeyErrorOnScope(t *testing.T) {
+	apiKeyServiceMock := new(apikeyservicemock.ApiKeyServiceMock)
+	apiKeyServiceMock.On("CreateApiKey", dtos.ApiKeyRequestCreate{}).Return(dtos.ApiKeyCreatedResponse{}, utils.ErrApiKeyScope)
+
+	apiKeyControllerTest := apiKeyController{apiKeyService: apiKeyServiceMock}
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/apikey", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	apiKeyControllerTest.CreateApiKey(c)
+
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestGetAllApiKeys(t *testing.T) {
+	apiKeyServiceMock := new(apikeyservicemock.ApiKeyServiceMock)
+	apiKeyServiceMock.On("GetAllApiKeys", dtos.GetApiKeysFilter{Owner: "jobs"}).Return(dtos.ApiKeyResponseMetadata{}, nil)
+
+	apiKeyControllerTest := apiKeyController{apiKeyService: apiKeyServiceMock}
+
+	request, err := http.NewRequest("GET", "/apikeys?owner=jobs", nil)
+	recorder := httptest.NewRecorder()
+	e := echo.New()
+	e.GET("/apikeys", apiKeyControllerTest.GetAllApiKeys)
+	e.ServeHTTP(recorder, request)
+
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, recorder.Code)
+}
+
+func TestRevokeApiKey(t *testing.T) {
+	tests := map[string]struct {
+		id                    string
+		expectedErrorOnRevoke error
+		expectedStatus        int
+	}{
+		"success on revoke api key": {
+			id:             "3",
+			expectedStatus: http.StatusNoContent,
+		},
+		"error on revoke api key (invalid id)": {
+			id:             "a",
+			expectedStatus: http.StatusBadRequest,
+		},
+		"error on revoke api key (not found)": {
+			id:                    "3",
+			expectedErrorOnRevoke: utils.ErrApiKeyIdNotFound,
+			expectedStatus:        http.StatusNotFound,
+		},
+		"error on revoke api key (service)": {
+			id:                    "3",
+			expectedErrorOnRevoke: errors.New("error occurred"),
+			expectedStatus:        http.StatusInternalServerError,
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			apiKeyServiceMock := new(apikeyservicemock.ApiKeyServiceMock)
+			apiKeyServiceMock.On("RevokeApiKey", 3).Return(tc.expectedErrorOnRevoke)
+
+			apiKeyControllerTest := apiKeyController{apiKeyService: apiKeyServiceMock}
+
+			request, _ := http.NewRequest("DELETE", "/apikey/"+tc.id, nil)
+			recorder := httptest.NewRecorder()
+			e := echo.New()
+			e.DELETE("/apikey/:id", apiKeyControllerTest.RevokeApiKey)
+			e.ServeHTTP(recorder, request)
+
+			require.Equal(t, tc.expectedStatus, recorder.Code)
+		})
+	}
+}
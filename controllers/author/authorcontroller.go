@@ -1,11 +1,14 @@
 package controllers
 
 import (
+	"errors"
 	"fmt"
 	"github/brunojoenk/golang-test/models/dtos"
 	authorservice "github/brunojoenk/golang-test/services/author"
+	"github/brunojoenk/golang-test/utils"
 	"net/http"
 	"os"
+	"strconv"
 
 	_ "github/brunojoenk/golang-test/docs"
 
@@ -16,6 +19,12 @@ import (
 type IAuthorController interface {
 	GetAllAuthors(c echo.Context) error
 	ReadCsvHandler(c echo.Context) error
+	GetDuplicateAuthors(c echo.Context) error
+	MergeAuthors(c echo.Context) error
+	GetAuthorAliases(c echo.Context) error
+	CreateAuthorAlias(c echo.Context) error
+	UpdateAuthorAlias(c echo.Context) error
+	DeleteAuthorAlias(c echo.Context) error
 }
 
 type authorController struct {
@@ -33,9 +42,12 @@ func NewAuthorController(d *gorm.DB) *authorController {
 // @Tags Authors
 // @Accept */*
 // @Produce json
-// @Param   name     query     string     false  "search authors by name"     example(string)
+// @Param   name     query     string     false  "search authors by name or alias"     example(string)
+// @Param   nationality     query     string     false  "search authors by nationality (ISO 3166-1 alpha-2)"     example(BR)
+// @Param   born_before     query     string     false  "search authors born before the date (YYYY-MM-DD or YYYY)"     example(1950)
+// @Param   born_after     query     string     false  "search authors born after the date (YYYY-MM-DD or YYYY)"     example(1900-06-30)
 // @Param   page     query     int     false  "page list"     example(1) minimum(1)
-// @Param   limit     query     int     false  "page size"     example(1) minimum(1)
+// @Param   limit     query     int     false  "page size"     example(1) minimum(1) maximum(100)
 // @Success 200 {object} dtos.AuthorResponseMetadata
 // @Failure 400 {object} string
 // @Failure 500 {object} string
@@ -51,6 +63,9 @@ func (a *authorController) GetAllAuthors(c echo.Context) error {
 
 	authorsResponse, err := a.authorService.GetAllAuthors(filter)
 	if err != nil {
+		if errors.Is(err, utils.ErrInvalidCountryCode) || errors.Is(err, utils.ErrInvalidDate) {
+			return c.JSON(http.StatusBadRequest, err.Error())
+		}
 		c.Logger().Error("Error get all author: %s", err.Error())
 		return c.JSON(http.StatusInternalServerError, err.Error())
 	}
@@ -60,11 +75,12 @@ func (a *authorController) GetAllAuthors(c echo.Context) error {
 
 // Import authors from authors.csv godoc
 // @Summary Import authors from authors.csv.
-// @Description Import authors from authors.csv.
+// @Description Import authors from authors.csv. When the first line has a "name" column, each line is an author and the columns birth_date, death_date, nationality, biography, website, orcid, viaf and wikidata_id fill its profile.
 // @Tags Authors
 // @Accept */*
 // @Produce json
 // @Success 200 {array} dtos.AuthorResponseMetadata
+// @Failure 429 {object} string
 // @Router /authors/import [post]
 func (a *authorController) ReadCsvHandler(c echo.Context) error {
 
@@ -83,3 +99,234 @@ func (a *authorController) ReadCsvHandler(c echo.Context) error {
 		Total: totalAuthorsAdded,
 	})
 }
+
+// GetDuplicateAuthors godoc
+// @Summary Show probable duplicate authors.
+// @Description Show pairs of authors sharing the last name whose names are similar (e.g. "J. R. R. Tolkien" and "John Ronald Reuel Tolkien"), the most similar first.
+// @Tags Authors
+// @Accept */*
+// @Produce json
+// @Param   min_score     query     number     false  "minimum similarity, from 0 to 1 (default 0.85)"     example(0.9)
+// @Param   page     query     int     false  "page list"     example(1) minimum(1)
+// @Param   limit     query     int     false  "page size"     example(1) minimum(1) maximum(100)
+// @Success 200 {object} dtos.AuthorDuplicatesResponseMetadata
+// @Failure 400 {object} string
+// @Failure 500 {object} string
+// @Router /authors/duplicates [get]
+func (a *authorController) GetDuplicateAuthors(c echo.Context) error {
+
+	var filter dtos.GetAuthorDuplicatesFilter
+	err := c.Bind(&filter)
+	if err != nil {
+		c.Logger().Warn("Error on bind query to get duplicate authors: %s", err.Error())
+		return c.JSON(http.StatusBadRequest, "Invalid parameter")
+	}
+
+	duplicatesResponse, err := a.authorService.GetDuplicateAuthors(filter)
+	if err != nil {
+		c.Logger().Error("Error get duplicate authors: %s", err.Error())
+		return c.JSON(http.StatusInternalServerError, "Error on get duplicate authors. Please, contact admin")
+	}
+
+	return c.JSON(http.StatusOK, duplicatesResponse)
+}
+
+// MergeAuthors godoc
+// @Summary Merge an author into another.
+// @Description Fold the source author into the author of the path: its books are linked to the target, its name is kept as an alias of the target and the source is deleted.
+// @Tags Authors
+// @Accept json
+// @Produce json
+// @Param id   path int true "Target author ID"
+// @Param request body dtos.AuthorMergeRequest true "source author"
+// @Success 200 {object} dtos.AuthorResponse
+// @Failure 400 {object} string
+// @Failure 404 {object} string
+// @Failure 500 {object} string
+// @Router /authors/{id}/merge [post]
+func (a *authorController) MergeAuthors(c echo.Context) error {
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Logger().Warn("Error on parse parameters id on merge authors %s", err.Error())
+		return c.JSON(http.StatusBadRequest, "Invalid query parameter id")
+	}
+
+	authorMergeRequest := new(dtos.AuthorMergeRequest)
+	if err := c.Bind(authorMergeRequest); err != nil {
+		c.Logger().Warn("Error on parse body on merge authors %s", err.Error())
+		return c.JSON(http.StatusBadRequest, fmt.Sprintf("Error on parse body to merge authors: %s", err.Error()))
+	}
+
+	authorMerged, err := a.authorService.MergeAuthors(id, authorMergeRequest.SourceId)
+	if err != nil {
+		if errors.Is(err, utils.ErrAuthorMergeSameId) {
+			return c.JSON(http.StatusBadRequest, err.Error())
+		}
+		if errors.Is(err, utils.ErrAuthorIdNotFound) {
+			return c.JSON(http.StatusNotFound, err.Error())
+		}
+		c.Logger().Error("Error on merge authors %s", err.Error())
+		return c.JSON(http.StatusInternalServerError, "Error on merge authors. Please contact system admin")
+	}
+
+	return c.JSON(http.StatusOK, authorMerged)
+}
+
+// GetAuthorAliases godoc
+// @Summary Show the aliases of an author.
+// @Description Show the aliases (pen names, transliterations, names of merged authors) of an author.
+// @Tags Authors
+// @Accept */*
+// @Produce json
+// @Param id   path int true "Author ID"
+// @Success 200 {array} dtos.AuthorAliasResponse
+// @Failure 400 {object} string
+// @Failure 404 {object} string
+// @Failure 500 {object} string
+// @Router /authors/{id}/aliases [get]
+func (a *authorController) GetAuthorAliases(c echo.Context) error {
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Logger().Warn("Error on parse parameters id on get author aliases %s", err.Error())
+		return c.JSON(http.StatusBadRequest, "Invalid query parameter id")
+	}
+
+	aliases, err := a.authorService.GetAuthorAliases(id)
+	if err != nil {
+		if errors.Is(err, utils.ErrAuthorIdNotFound) {
+			return c.JSON(http.StatusNotFound, err.Error())
+		}
+		c.Logger().Error("Error on get author aliases %s", err.Error())
+		return c.JSON(http.StatusInternalServerError, "Error on get author aliases. Please contact system admin")
+	}
+
+	return c.JSON(http.StatusOK, aliases)
+}
+
+// CreateAuthorAlias godoc
+// @Summary Create an alias of an author.
+// @Description Create an alias of an author. The import resolves names matching an alias to its author.
+// @Tags Authors
+// @Accept json
+// @Produce json
+// @Param id   path int true "Author ID"
+// @Param request body dtos.AuthorAliasRequest true "alias"
+// @Success 201 {object} dtos.AuthorAliasResponse
+// @Failure 400 {object} string
+// @Failure 404 {object} string
+// @Failure 409 {object} string
+// @Failure 500 {object} string
+// @Router /authors/{id}/aliases [post]
+func (a *authorController) CreateAuthorAlias(c echo.Context) error {
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Logger().Warn("Error on parse parameters id on create author alias %s", err.Error())
+		return c.JSON(http.StatusBadRequest, "Invalid query parameter id")
+	}
+
+	aliasRequest := new(dtos.AuthorAliasRequest)
+	if err := c.Bind(aliasRequest); err != nil {
+		c.Logger().Warn("Error on parse body on create author alias %s", err.Error())
+		return c.JSON(http.StatusBadRequest, fmt.Sprintf("Error on parse body to create author alias: %s", err.Error()))
+	}
+
+	alias, err := a.authorService.CreateAuthorAlias(id, *aliasRequest)
+	if err != nil {
+		return a.aliasErrorResponse(c, "create", err)
+	}
+
+	return c.JSON(http.StatusCreated, alias)
+}
+
+// UpdateAuthorAlias godoc
+// @Summary Update an alias of an author.
+// @Description Update an alias of an author.
+// @Tags Authors
+// @Accept json
+// @Produce json
+// @Param id   path int true "Author ID"
+// @Param aliasId   path int true "Alias ID"
+// @Param request body dtos.AuthorAliasRequest true "alias"
+// @Success 200 {object} dtos.AuthorAliasResponse
+// @Failure 400 {object} string
+// @Failure 404 {object} string
+// @Failure 409 {object} string
+// @Failure 500 {object} string
+// @Router /authors/{id}/aliases/{aliasId} [put]
+func (a *authorController) UpdateAuthorAlias(c echo.Context) error {
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Logger().Warn("Error on parse parameters id on update author alias %s", err.Error())
+		return c.JSON(http.StatusBadRequest, "Invalid query parameter id")
+	}
+
+	aliasId, err := strconv.Atoi(c.Param("aliasId"))
+	if err != nil {
+		c.Logger().Warn("Error on parse parameters alias id on update author alias %s", err.Error())
+		return c.JSON(http.StatusBadRequest, "Invalid query parameter alias id")
+	}
+
+	aliasRequest := new(dtos.AuthorAliasRequest)
+	if err := c.Bind(aliasRequest); err != nil {
+		c.Logger().Warn("Error on parse body on update author alias %s", err.Error())
+		return c.JSON(http.StatusBadRequest, fmt.Sprintf("Error on parse body to update author alias: %s", err.Error()))
+	}
+
+	alias, err := a.authorService.UpdateAuthorAlias(id, aliasId, *aliasRequest)
+	if err != nil {
+		return a.aliasErrorResponse(c, "update", err)
+	}
+
+	return c.JSON(http.StatusOK, alias)
+}
+
+// DeleteAuthorAlias godoc
+// @Summary Delete an alias of an author.
+// @Description Delete an alias of an author.
+// @Tags Authors
+// @Accept */*
+// @Produce json
+// @Param id   path int true "Author ID"
+// @Param aliasId   path int true "Alias ID"
+// @Success 204 {object} string
+// @Failure 400 {object} string
+// @Failure 404 {object} string
+// @Failure 500 {object} string
+// @Router /authors/{id}/aliases/{aliasId} [delete]
+func (a *authorController) DeleteAuthorAlias(c echo.Context) error {
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Logger().Warn("Error on parse parameters id on delete author alias %s", err.Error())
+		return c.JSON(http.StatusBadRequest, "Invalid query parameter id")
+	}
+
+	aliasId, err := strconv.Atoi(c.Param("aliasId"))
+	if err != nil {
+		c.Logger().Warn("Error on parse parameters alias id on delete author alias %s", err.Error())
+		return c.JSON(http.StatusBadRequest, "Invalid query parameter alias id")
+	}
+
+	if err := a.authorService.DeleteAuthorAlias(id, aliasId); err != nil {
+		return a.aliasErrorResponse(c, "delete", err)
+	}
+
+	return c.JSON(http.StatusNoContent, "Deleted")
+}
+
+func (a *authorController) aliasErrorResponse(c echo.Context, action string, err error) error {
+	switch {
+	case errors.Is(err, utils.ErrAuthorAliasNameEmpty):
+		return c.JSON(http.StatusBadRequest, err.Error())
+	case errors.Is(err, utils.ErrAuthorIdNotFound), errors.Is(err, utils.ErrAuthorAliasIdNotFound):
+		return c.JSON(http.StatusNotFound, err.Error())
+	case errors.Is(err, utils.ErrAuthorAliasConflict):
+		return c.JSON(http.StatusConflict, err.Error())
+	}
+	c.Logger().Error("Error on %s author alias %s", action, err.Error())
+	return c.JSON(http.StatusInternalServerError, fmt.Sprintf("Error on %s author alias. Please contact system admin", action))
+}
@@ -8,9 +8,12 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"strings"
 	"testing"
 	"time"
 
+	"github/brunojoenk/golang-test/utils"
+
 	authorservicemock "github/brunojoenk/golang-test/services/author/mock"
 
 	"encoding/json"
@@ -61,6 +64,24 @@ func TestGetAllAuthorsErrorOnFilter(t *testing.T) {
 
 }
 
+func TestGetAllAuthorsErrorOnProfileFilter(t *testing.T) {
+	authorServiceMock := new(authorservicemock.AuthorServiceyMock)
+	authorServiceMock.On("GetAllAuthors", dtos.GetAuthorsFilter{Nationality: "Brazil"}).Return(dtos.AuthorResponseMetadata{}, utils.ErrInvalidCountryCode)
+	authorServiceMock.On("GetAllAuthors", dtos.GetAuthorsFilter{BornBefore: "1950s"}).Return(dtos.AuthorResponseMetadata{}, utils.ErrInvalidDate)
+
+	authorControllerTest := authorController{authorService: authorServiceMock}
+
+	for _, query := range []string{"nationality=Brazil", "born_before=1950s"} {
+		request, _ := http.NewRequest("GET", "/authors?"+query, nil)
+		recorder := httptest.NewRecorder()
+		e := echo.New()
+		e.GET("/authors", authorControllerTest.GetAllAuthors)
+		e.ServeHTTP(recorder, request)
+
+		require.Equal(t, http.StatusBadRequest, recorder.Code)
+	}
+}
+
 func TestGetAllAuthorsErrorOnService(t *testing.T) {
 	errExpected := errors.New("error occurred")
 	authorServiceMock := new(authorservicemock.AuthorServiceyMock)
@@ -117,3 +138,331 @@ func TestImportReadCsvHandlerError(t *testing.T) {
 	//require.ErrorIs(t, errExpected, err)
 	require.Equal(t, http.StatusInternalServerError, rec.Code)
 }
+
+func TestGetDuplicateAuthors(t *testing.T) {
+	duplicatesResponse := dtos.AuthorDuplicatesResponseMetadata{
+		Candidates: []dtos.AuthorDuplicateCandidate{{
+			Author: dtos.AuthorResponse{Id: 1, Name: "J. R. R. Tolkien"},
+			Other:  dtos.AuthorResponse{Id: 2, Name: "John Ronald Reuel Tolkien"},
+			Score:  0.95,
+		}},
+		Pagination: dtos.Pagination{Page: 1, Limit: 5},
+	}
+	filter := dtos.GetAuthorDuplicatesFilter{MinScore: 0.9, Pagination: dtos.Pagination{Page: 1, Limit: 5}}
+
+	authorServiceMock := new(authorservicemock.AuthorServiceyMock)
+	authorServiceMock.On("GetDuplicateAuthors", filter).Return(duplicatesResponse, nil)
+
+	authorControllerTest := authorController{authorService: authorServiceMock}
+
+	request, err := http.NewRequest("GET", "/authors/duplicates?min_score=0.9&page=1&limit=5", nil)
+	recorder := httptest.NewRecorder()
+	e := echo.New()
+	e.GET("/authors/duplicates", authorControllerTest.GetDuplicateAuthors)
+	e.ServeHTTP(recorder, request)
+
+	respExpected, _ := json.Marshal(duplicatesResponse)
+	require.Equal(t, fmt.Sprintf("%s%s", respExpected, "\n"), recorder.Body.String())
+
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, recorder.Code)
+}
+
+func TestGetDuplicateAuthorsErrorOnFilter(t *testing.T) {
+	request, _ := http.NewRequest("GET", "/authors/duplicates?min_score=a", nil)
+	recorder := httptest.NewRecorder()
+	e := echo.New()
+	authorControllerTest := authorController{}
+	e.GET("/authors/duplicates", authorControllerTest.GetDuplicateAuthors)
+	e.ServeHTTP(recorder, request)
+
+	require.Equal(t, http.StatusBadRequest, recorder.Code)
+}
+
+func TestGetDuplicateAuthorsErrorOnService(t *testing.T) {
+	authorServiceMock := new(authorservicemock.AuthorServiceyMock)
+	authorServiceMock.On("GetDuplicateAuthors", dtos.GetAuthorDuplicatesFilter{}).Return(dtos.AuthorDuplicatesResponseMetadata{}, errors.New("error occurred"))
+
+	authorControllerTest := authorController{authorService: authorServiceMock}
+
+	request, _ := http.NewRequest("GET", "/authors/duplicates", nil)
+	recorder := httptest.NewRecorder()
+	e := echo.New()
+	e.GET("/authors/duplicates", authorControllerTest.GetDuplicateAuthors)
+	e.ServeHTTP(recorder, request)
+
+	require.Equal(t, http.StatusInternalServerError, recorder.Code)
+}
+
+func TestMergeAuthors(t *testing.T) {
+	tests := map[string]struct {
+		id                   string
+		body                 string
+		expectedErrorOnMerge error
+		expectedStatus       int
+	}{
+		"success on merge authors": {
+			id:             "1",
+			body:           `{"source_id":2}`,
+			expectedStatus: http.StatusOK,
+		},
+		"error on merge authors (invalid id)": {
+			id:             "a",
+			body:           `{"source_id":2}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		"error on merge authors (invalid body)": {
+			id:             "1",
+			body:           `{source_id: 2}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		"error on merge authors (same id)": {
+			id:                   "1",
+			body:                 `{"source_id":2}`,
+			expectedErrorOnMerge: utils.ErrAuthorMergeSameId,
+			expectedStatus:       http.StatusBadRequest,
+		},
+		"error on merge authors (not found)": {
+			id:                   "1",
+			body:                 `{"source_id":2}`,
+			expectedErrorOnMerge: utils.ErrAuthorIdNotFound,
+			expectedStatus:       http.StatusNotFound,
+		},
+		"error on merge authors (service)": {
+			id:                   "1",
+			body:                 `{"source_id":2}`,
+			expectedErrorOnMerge: errors.New("error occurred"),
+			expectedStatus:       http.StatusInternalServerError,
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			authorServiceMock := new(authorservicemock.AuthorServiceyMock)
+			authorServiceMock.On("MergeAuthors", 1, 2).Return(dtos.AuthorResponse{Id: 1}, tc.expectedErrorOnMerge)
+
+			authorControllerTest := authorController{authorService: authorServiceMock}
+
+			request, _ := http.NewRequest("POST", fmt.Sprintf("/authors/%s/merge", tc.id), strings.NewReader(tc.body))
+			request.Header.Add("Content-type", "application/json")
+			recorder := httptest.NewRecorder()
+			e := echo.New()
+			e.POST("/authors/:id/merge", authorControllerTest.MergeAuthors)
+			e.ServeHTTP(recorder, request)
+
+			require.Equal(t, tc.expectedStatus, recorder.Code)
+		})
+	}
+}
+
+func TestGetAuthorAliases(t *testing.T) {
+	tests := map[string]struct {
+		id                     string
+		expectedErrorOnService error
+		expectedStatus         int
+	}{
+		"success on get author aliases": {
+			id:             "1",
+			expectedStatus: http.StatusOK,
+		},
+		"error on get author aliases (invalid id)": {
+			id:             "a",
+			expectedStatus: http.StatusBadRequest,
+		},
+		"error on get author aliases (author not found)": {
+			id:                     "1",
+			expectedErrorOnService: utils.ErrAuthorIdNotFound,
+			expectedStatus:         http.StatusNotFound,
+		},
+		"error on get author aliases (service)": {
+			id:                     "1",
+			expectedErrorOnService: errors.New("error occurred"),
+			expectedStatus:         http.StatusInternalServerError,
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			aliases := []dtos.AuthorAliasResponse{{Id: 3, AuthorId: 1, Name: "Richard Bachman"}}
+			authorServiceMock := new(authorservicemock.AuthorServiceyMock)
+			authorServiceMock.On("GetAuthorAliases", 1).Return(aliases, tc.expectedErrorOnService)
+
+			authorControllerTest := authorController{authorService: authorServiceMock}
+
+			request, _ := http.NewRequest("GET", fmt.Sprintf("/authors/%s/aliases", tc.id), nil)
+			recorder := httptest.NewRecorder()
+			e := echo.New()
+			e.GET("/authors/:id/aliases", authorControllerTest.GetAuthorAliases)
+			e.ServeHTTP(recorder, request)
+
+			require.Equal(t, tc.expectedStatus, recorder.Code)
+			if tc.expectedStatus == http.StatusOK {
+				respExpected, _ := json.Marshal(aliases)
+				require.Equal(t, fmt.Sprintf("%s%s", respExpected, "\n"), recorder.Body.String())
+			}
+		})
+	}
+}
+
+func TestCreateAuthorAlias(t *testing.T) {
+	tests := map[string]struct {
+		id                     string
+		body                   string
+		expectedErrorOnService error
+		expectedStatus         int
+	}{
+		"success on create author alias": {
+			id:             "1",
+			body:           `{"name":"Richard Bachman"}`,
+			expectedStatus: http.StatusCreated,
+		},
+		"error on create author alias (invalid id)": {
+			id:             "a",
+			body:           `{"name":"Richard Bachman"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		"error on create author alias (invalid body)": {
+			id:             "1",
+			body:           `{name: Richard}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		"error on create author alias (empty name)": {
+			id:                     "1",
+			body:                   `{"name":"Richard Bachman"}`,
+			expectedErrorOnService: utils.ErrAuthorAliasNameEmpty,
+			expectedStatus:         http.StatusBadRequest,
+		},
+		"error on create author alias (author not found)": {
+			id:                     "1",
+			body:                   `{"name":"Richard Bachman"}`,
+			expectedErrorOnService: utils.ErrAuthorIdNotFound,
+			expectedStatus:         http.StatusNotFound,
+		},
+		"error on create author alias (conflict)": {
+			id:                     "1",
+			body:                   `{"name":"Richard Bachman"}`,
+			expectedErrorOnService: utils.ErrAuthorAliasConflict,
+			expectedStatus:         http.StatusConflict,
+		},
+		"error on create author alias (service)": {
+			id:                     "1",
+			body:                   `{"name":"Richard Bachman"}`,
+			expectedErrorOnService: errors.New("error occurred"),
+			expectedStatus:         http.StatusInternalServerError,
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			authorServiceMock := new(authorservicemock.AuthorServiceyMock)
+			authorServiceMock.On("CreateAuthorAlias", 1, dtos.AuthorAliasRequest{Name: "Richard Bachman"}).
+				Return(dtos.AuthorAliasResponse{Id: 3, AuthorId: 1, Name: "Richard Bachman"}, tc.expectedErrorOnService)
+
+			authorControllerTest := authorController{authorService: authorServiceMock}
+
+			request, _ := http.NewRequest("POST", fmt.Sprintf("/authors/%s/aliases", tc.id), strings.NewReader(tc.body))
+			request.Header.Add("Content-type", "application/json")
+			recorder := httptest.NewRecorder()
+			e := echo.New()
+			e.POST("/authors/:id/aliases", authorControllerTest.CreateAuthorAlias)
+			e.ServeHTTP(recorder, request)
+
+			require.Equal(t, tc.expectedStatus, recorder.Code)
+		})
+	}
+}
+
+func TestUpdateAuthorAlias(t *testing.T) {
+	tests := map[string]struct {
+		id                     string
+		aliasId                string
+		expectedErrorOnService error
+		expectedStatus         int
+	}{
+		"success on update author alias": {
+			id:             "1",
+			aliasId:        "3",
+			expectedStatus: http.StatusOK,
+		},
+		"error on update author alias (invalid id)": {
+			id:             "a",
+			aliasId:        "3",
+			expectedStatus: http.StatusBadRequest,
+		},
+		"error on update author alias (invalid alias id)": {
+			id:             "1",
+			aliasId:        "a",
+			expectedStatus: http.StatusBadRequest,
+		},
+		"error on update author alias (alias not found)": {
+			id:                     "1",
+			aliasId:                "3",
+			expectedErrorOnService: utils.ErrAuthorAliasIdNotFound,
+			expectedStatus:         http.StatusNotFound,
+		},
+		"error on update author alias (service)": {
+			id:                     "1",
+			aliasId:                "3",
+			expectedErrorOnService: errors.New("error occurred"),
+			expectedStatus:         http.StatusInternalServerError,
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			authorServiceMock := new(authorservicemock.AuthorServiceyMock)
+			authorServiceMock.On("UpdateAuthorAlias", 1, 3, dtos.AuthorAliasRequest{Name: "Richard Bachman"}).
+				Return(dtos.AuthorAliasResponse{Id: 3, AuthorId: 1, Name: "Richard Bachman"}, tc.expectedErrorOnService)
+
+			authorControllerTest := authorController{authorService: authorServiceMock}
+
+			request, _ := http.NewRequest("PUT", fmt.Sprintf("/authors/%s/aliases/%s", tc.id, tc.aliasId), strings.NewReader(`{"name":"Richard Bachman"}`))
+			request.Header.Add("Content-type", "application/json")
+			recorder := httptest.NewRecorder()
+			e := echo.New()
+			e.PUT("/authors/:id/aliases/:aliasId", authorControllerTest.UpdateAuthorAlias)
+			e.ServeHTTP(recorder, request)
+
+			require.Equal(t, tc.expectedStatus, recorder.Code)
+		})
+	}
+}
+
+func TestDeleteAuthorAlias(t *testing.T) {
+	tests := map[string]struct {
+		aliasId                string
+		expectedErrorOnService error
+		expectedStatus         int
+	}{
+		"success on delete author alias": {
+			aliasId:        "3",
+			expectedStatus: http.StatusNoContent,
+		},
+		"error on delete author alias (invalid alias id)": {
+			aliasId:        "a",
+			expectedStatus: http.StatusBadRequest,
+		},
+		"error on delete author alias (alias not found)": {
+			aliasId:                "3",
+			expectedErrorOnService: utils.ErrAuthorAliasIdNotFound,
+			expectedStatus:         http.StatusNotFound,
+		},
+		"error on delete author alias (service)": {
+			aliasId:                "3",
+			expectedErrorOnService: errors.New("error occurred"),
+			expectedStatus:         http.StatusInternalServerError,
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			authorServiceMock := new(authorservicemock.AuthorServiceyMock)
+			authorServiceMock.On("DeleteAuthorAlias", 1, 3).Return(tc.expectedErrorOnService)
+
+			authorControllerTest := authorController{authorService: authorServiceMock}
+
+			request, _ := http.NewRequest("DELETE", fmt.Sprintf("/authors/1/aliases/%s", tc.aliasId), nil)
+			recorder := httptest.NewRecorder()
+			e := echo.New()
+			e.DELETE("/authors/:id/aliases/:aliasId", authorControllerTest.DeleteAuthorAlias)
+			e.ServeHTTP(recorder, request)
+
+			require.Equal(t, tc.expectedStatus, recorder.Code)
+		})
+	}
+}
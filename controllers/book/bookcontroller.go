@@ -2,10 +2,13 @@ package controllers
 
 import (
 	"fmt"
+	"github/brunojoenk/golang-test/config"
 	"github/brunojoenk/golang-test/models/dtos"
 	bookservice "github/brunojoenk/golang-test/services/book"
+	"github/brunojoenk/golang-test/storage"
 	"github/brunojoenk/golang-test/utils"
 	"net/http"
+	"net/url"
 	"strconv"
 
 	"github.com/pkg/errors"
@@ -19,7 +22,12 @@ type IBookController interface {
 	GetAllBooks(c echo.Context) error
 	DeleteBook(c echo.Context) error
 	GetBook(c echo.Context) error
+	GetBookByIsbn(c echo.Context) error
 	UpdateBook(c echo.Context) error
+	GetDuplicateBooks(c echo.Context) error
+	MergeBooks(c echo.Context) error
+	AddBookTags(c echo.Context) error
+	RemoveBookTag(c echo.Context) error
 }
 
 type bookController struct {
@@ -27,19 +35,23 @@ type bookController struct {
 }
 
 // NewBookController Controller Constructor
-func NewBookController(db *gorm.DB) IBookController {
-	return &bookController{bookService: bookservice.NewBookService(db)}
+func NewBookController(db *gorm.DB, cfg *config.Config, store storage.BlobStore) IBookController {
+	return &bookController{bookService: bookservice.NewBookService(db, cfg, store)}
 }
 
 // CreateBook godoc
 // @Summary Create a book.
-// @Description Create a book.
+// @Description Create a book. Authors are {"id", "role"} objects, role one of author (default), editor, translator or illustrator, listed in credit order. Plain author ids are accepted too. Without work_id the book joins the work with the same normalized name and authors, or a new one.
 // @Tags Books
 // @Accept json
 // @Produce json
+// @Param Accept-Language header string false "preferred languages of the book titles, e.g. pt-BR, en;q=0.8"
+// @Param Idempotency-Key header string false "retries with the same key and body replay the first response"
 // @Param request body dtos.BookRequestCreate true "query params"
 // @Success 201 {object} string
 // @Failure 400 {object} string
+// @Failure 409 {object} dtos.BookDuplicatedResponse "duplicated book, or ISBN of another book"
+// @Failure 422 {object} string
 // @Failure 500 {object} string
 // @Router /book [post]
 func (b *bookController) CreateBook(c echo.Context) error {
@@ -56,10 +68,38 @@ func (b *bookController) CreateBook(c echo.Context) error {
 		if errors.Is(err, utils.ErrAuthorIdNotFound) {
 			return c.JSON(http.StatusBadRequest, "Author id not found to create book with author")
 		}
+		if errors.Is(err, utils.ErrPublisherIdNotFound) {
+			return c.JSON(http.StatusBadRequest, "Publisher id not found to create book with publisher")
+		}
+		if errors.Is(err, utils.ErrGenreIdNotFound) {
+			return c.JSON(http.StatusBadRequest, "Genre id not found to create book with genre")
+		}
+		if errors.Is(err, utils.ErrSeriesIdNotFound) {
+			return c.JSON(http.StatusBadRequest, "Series id not found to create book with series")
+		}
+		if errors.Is(err, utils.ErrWorkIdNotFound) {
+			return c.JSON(http.StatusBadRequest, "Work id not found to create book with work")
+		}
+		if errors.Is(err, utils.ErrInvalidSeriesPosition) || errors.Is(err, utils.ErrInvalidAuthorRole) || errors.Is(err, utils.ErrBookAuthorRepeated) {
+			return c.JSON(http.StatusBadRequest, err.Error())
+		}
+		if errors.Is(err, utils.ErrInvalidIsbn) {
+			return c.JSON(http.StatusBadRequest, err.Error())
+		}
+		if errors.Is(err, utils.ErrInvalidLanguage) || errors.Is(err, utils.ErrInvalidLocale) || errors.Is(err, utils.ErrBookTitleEmpty) || errors.Is(err, utils.ErrBookTitleRepeated) {
+			return c.JSON(http.StatusBadRequest, err.Error())
+		}
+		if errors.Is(err, utils.ErrBookIsbnExists) {
+			return c.JSON(http.StatusConflict, err.Error())
+		}
+		if duplicated := new(utils.BookDuplicatedError); errors.As(err, duplicated) {
+			return c.JSON(http.StatusConflict, dtos.BookDuplicatedResponse{Msg: err.Error(), ExistingBookId: duplicated.ExistingBookId})
+		}
 		c.Logger().Error("Error on create book: %s", err.Error())
 		return c.JSON(http.StatusInternalServerError, "Error on create book. Please, contact system admin")
 	}
 
+	book.Localize(c.Request().Header.Get("Accept-Language"))
 	return c.JSON(http.StatusCreated, book)
 }
 
@@ -69,14 +109,28 @@ func (b *bookController) CreateBook(c echo.Context) error {
 // @Tags Books
 // @Accept */*
 // @Produce json
-// @Param   name     query     string     false  "search book by name"     example(string)
+// @Param Accept-Language header string false "preferred languages of the book titles, e.g. pt-BR, en;q=0.8"
+// @Param   name     query     string     false  "search book by name or translated title"     example(string)
 // @Param   edition     query     string     false  "search book by edition"     example(string)
 // @Param   publication_year     query     int     false  "search book by publication year"     example(1) minimum(1)
-// @Param   author     query     string     false  "search book by author"     example(string)
+// @Param   author     query     string     false  "search book by author name or alias"     example(string)
+// @Param   isbn     query     string     false  "search book by ISBN-10 or ISBN-13"     example(978-0-261-10320-7)
+// @Param   publisher     query     string     false  "search book by publisher name"     example(string)
+// @Param   publisher_id     query     int     false  "search book by publisher ID"     example(1) minimum(1)
+// @Param   genre     query     string     false  "search book by genre name, including its subgenres"     example(string)
+// @Param   series     query     string     false  "search book by series name"     example(string)
+// @Param   series_id     query     int     false  "search book by series ID"     example(1) minimum(1)
+// @Param   language     query     string     false  "search book by ISO 639 language"     example(en)
+// @Param   available     query     bool     false  "only books with a copy available to borrow"     example(true)
+// @Param   tags     query     string     false  "search book by tags, comma separated"     example(dystopia,classic)
+// @Param   tags_match     query     string     false  "books with any (default) or all of the tags"     Enums(any, all)
+// @Param   min_rating     query     number     false  "books with an average rating of at least this, from 0 to 5"     example(4)
+// @Param   sort     query     string     false  "sort by name (default) or by rating, the best rated first"     Enums(name, rating)
 // @Param   page     query     int     false  "page list"     example(1) minimum(1)
-// @Param   limit     query     int     false  "page size"     example(1) minimum(1)
+// @Param   limit     query     int     false  "page size"     example(1) minimum(1) maximum(100)
 // @Success 200 {object} dtos.BookResponseMetadata
 // @Failure 400 {object} string
+// @Failure 429 {object} string
 // @Failure 500 {object} string
 // @Router /books [get]
 func (b *bookController) GetAllBooks(c echo.Context) error {
@@ -90,10 +144,15 @@ func (b *bookController) GetAllBooks(c echo.Context) error {
 	booksResponse, err := b.bookService.GetAllBooks(filter)
 
 	if err != nil {
+		if errors.Is(err, utils.ErrInvalidIsbn) || errors.Is(err, utils.ErrInvalidTag) || errors.Is(err, utils.ErrInvalidTagsMatch) || errors.Is(err, utils.ErrInvalidLanguage) ||
+			errors.Is(err, utils.ErrInvalidMinRating) || errors.Is(err, utils.ErrInvalidBookSort) {
+			return c.JSON(http.StatusBadRequest, err.Error())
+		}
 		c.Logger().Error("Error on get all books: %s", err.Error())
 		return c.JSON(http.StatusInternalServerError, "Error on get all books. Please, contact admin")
 	}
 
+	booksResponse.Localize(c.Request().Header.Get("Accept-Language"))
 	return c.JSON(http.StatusOK, booksResponse)
 }
 
@@ -106,6 +165,7 @@ func (b *bookController) GetAllBooks(c echo.Context) error {
 // @Param id   path int true "Book ID"
 // @Success 200 {object} string
 // @Failure 400 {object} string
+// @Failure 409 {object} string
 // @Failure 500 {object} string
 // @Router /book/{id} [delete]
 func (b *bookController) DeleteBook(c echo.Context) error {
@@ -120,6 +180,9 @@ func (b *bookController) DeleteBook(c echo.Context) error {
 	err = b.bookService.DeleteBook(id)
 
 	if err != nil {
+		if errors.Is(err, utils.ErrBookHasCopies) {
+			return c.JSON(http.StatusConflict, err.Error())
+		}
 		c.Logger().Error("Error on delete book: %s", err.Error())
 		return c.JSON(http.StatusInternalServerError, "Erro on delete book. Please, contact system admin")
 	}
@@ -133,6 +196,7 @@ func (b *bookController) DeleteBook(c echo.Context) error {
 // @Tags Books
 // @Accept */*
 // @Produce json
+// @Param Accept-Language header string false "preferred languages of the book titles, e.g. pt-BR, en;q=0.8"
 // @Param id   path int true "Book ID"
 // @Success 200 {object} dtos.BookResponse
 // @Failure 400 {object} string
@@ -157,19 +221,54 @@ func (b *bookController) GetBook(c echo.Context) error {
 		return c.JSON(http.StatusInternalServerError, "Error on get book. Please contact system admin")
 	}
 
+	bookResponse.Localize(c.Request().Header.Get("Accept-Language"))
+	return c.JSON(http.StatusOK, bookResponse)
+}
+
+// GetBookByIsbn godoc
+// @Summary Get a book by ISBN.
+// @Description Get a book by its ISBN-10 or ISBN-13. Hyphens and spaces are ignored.
+// @Tags Books
+// @Accept */*
+// @Produce json
+// @Param Accept-Language header string false "preferred languages of the book titles, e.g. pt-BR, en;q=0.8"
+// @Param isbn   path string true "ISBN-10 or ISBN-13"
+// @Success 200 {object} dtos.BookResponse
+// @Failure 400 {object} string
+// @Failure 404 {object} string
+// @Failure 500 {object} string
+// @Router /books/isbn/{isbn} [get]
+func (b *bookController) GetBookByIsbn(c echo.Context) error {
+
+	bookResponse, err := b.bookService.GetBookByIsbn(c.Param("isbn"))
+
+	if err != nil {
+		if errors.Is(err, utils.ErrInvalidIsbn) {
+			return c.JSON(http.StatusBadRequest, err.Error())
+		}
+		if errors.Is(err, utils.ErrBookIsbnNotFound) {
+			return c.JSON(http.StatusNotFound, err.Error())
+		}
+		c.Logger().Error("Error on get book by isbn %s", err.Error())
+		return c.JSON(http.StatusInternalServerError, "Error on get book. Please contact system admin")
+	}
+
+	bookResponse.Localize(c.Request().Header.Get("Accept-Language"))
 	return c.JSON(http.StatusOK, bookResponse)
 }
 
 // UpdateBook godoc
 // @Summary Update a book.
-// @Description Update a book.
+// @Description Update a book. A work_id moves the book to that work, otherwise it keeps its work.
 // @Tags Books
 // @Accept */*
 // @Produce json
+// @Param Accept-Language header string false "preferred languages of the book titles, e.g. pt-BR, en;q=0.8"
 // @Param id   path int true "Book ID"
 // @Param request body dtos.BookRequestUpdate true "query params"
 // @Success 200 {object} string
 // @Failure 400 {object} string
+// @Failure 409 {object} dtos.BookDuplicatedResponse "duplicated book, or ISBN of another book"
 // @Failure 500 {object} string
 // @Router /book/{id} [put]
 func (b *bookController) UpdateBook(c echo.Context) error {
@@ -190,12 +289,196 @@ func (b *bookController) UpdateBook(c echo.Context) error {
 	bookUpdated, err := b.bookService.UpdateBook(id, *bookRequestUpdate)
 
 	if err != nil {
-		if errors.Is(err, utils.ErrAuthorIdNotFound) {
+		if errors.Is(err, utils.ErrAuthorIdNotFound) || errors.Is(err, utils.ErrPublisherIdNotFound) || errors.Is(err, utils.ErrGenreIdNotFound) ||
+			errors.Is(err, utils.ErrSeriesIdNotFound) || errors.Is(err, utils.ErrInvalidSeriesPosition) || errors.Is(err, utils.ErrInvalidIsbn) ||
+			errors.Is(err, utils.ErrInvalidAuthorRole) || errors.Is(err, utils.ErrBookAuthorRepeated) || errors.Is(err, utils.ErrWorkIdNotFound) ||
+			errors.Is(err, utils.ErrInvalidLanguage) || errors.Is(err, utils.ErrInvalidLocale) || errors.Is(err, utils.ErrBookTitleEmpty) || errors.Is(err, utils.ErrBookTitleRepeated) {
 			return c.JSON(http.StatusBadRequest, err.Error())
 		}
+		if errors.Is(err, utils.ErrBookIsbnExists) {
+			return c.JSON(http.StatusConflict, err.Error())
+		}
+		if duplicated := new(utils.BookDuplicatedError); errors.As(err, duplicated) {
+			return c.JSON(http.StatusConflict, dtos.BookDuplicatedResponse{Msg: err.Error(), ExistingBookId: duplicated.ExistingBookId})
+		}
 		c.Logger().Error("Error on update book %s", err.Error())
 		return c.JSON(http.StatusInternalServerError, "Error on update book. Please contact system admin")
 	}
 
+	bookUpdated.Localize(c.Request().Header.Get("Accept-Language"))
 	return c.JSON(http.StatusOK, bookUpdated)
 }
+
+// GetDuplicateBooks godoc
+// @Summary Show probable duplicate books.
+// @Description Show clusters of books sharing the same normalized name (case, accents, punctuation and spacing are ignored).
+// @Tags Books
+// @Accept */*
+// @Produce json
+// @Param   page     query     int     false  "page list"     example(1) minimum(1)
+// @Param   limit     query     int     false  "page size (clusters)"     example(1) minimum(1) maximum(100)
+// @Success 200 {object} dtos.BookDuplicatesResponseMetadata
+// @Failure 400 {object} string
+// @Failure 500 {object} string
+// @Router /books/duplicates [get]
+func (b *bookController) GetDuplicateBooks(c echo.Context) error {
+	var pagination dtos.Pagination
+	err := c.Bind(&pagination)
+	if err != nil {
+		c.Logger().Warn("Error on bind query to get duplicate books: %s", err.Error())
+		return c.JSON(http.StatusBadRequest, fmt.Sprintf("Invalid query parameters: %s", err.Error()))
+	}
+
+	duplicatesResponse, err := b.bookService.GetDuplicateBooks(pagination)
+
+	if err != nil {
+		c.Logger().Error("Error on get duplicate books: %s", err.Error())
+		return c.JSON(http.StatusInternalServerError, "Error on get duplicate books. Please, contact admin")
+	}
+
+	return c.JSON(http.StatusOK, duplicatesResponse)
+}
+
+// MergeBooks godoc
+// @Summary Merge a book into another.
+// @Description Fold the source book into the book of the path: its authors are moved to the target, blank fields of the target are filled from the source and the source is deleted.
+// @Tags Books
+// @Accept json
+// @Produce json
+// @Param Accept-Language header string false "preferred languages of the book titles, e.g. pt-BR, en;q=0.8"
+// @Param id   path int true "Target book ID"
+// @Param request body dtos.BookMergeRequest true "source book"
+// @Success 200 {object} dtos.BookResponse
+// @Failure 400 {object} string
+// @Failure 404 {object} string
+// @Failure 409 {object} dtos.BookDuplicatedResponse "another book has the authors of both, or the ISBN"
+// @Failure 500 {object} string
+// @Router /books/{id}/merge [post]
+func (b *bookController) MergeBooks(c echo.Context) error {
+
+	id, err := strconv.Atoi(c.Param("id"))
+
+	if err != nil {
+		c.Logger().Warn("Error on parse parameters id on merge books %s", err.Error())
+		return c.JSON(http.StatusBadRequest, "Invalid query parameter id")
+	}
+
+	bookMergeRequest := new(dtos.BookMergeRequest)
+	if err := c.Bind(bookMergeRequest); err != nil {
+		c.Logger().Warn("Error on parse body on merge books %s", err.Error())
+		return c.JSON(http.StatusBadRequest, fmt.Sprintf("Error on parse body to merge books: %s", err.Error()))
+	}
+
+	bookMerged, err := b.bookService.MergeBooks(id, bookMergeRequest.SourceId)
+
+	if err != nil {
+		if errors.Is(err, utils.ErrBookMergeSameId) {
+			return c.JSON(http.StatusBadRequest, err.Error())
+		}
+		if errors.Is(err, utils.ErrBookIdNotFound) {
+			return c.JSON(http.StatusNotFound, err.Error())
+		}
+		if errors.Is(err, utils.ErrBookIsbnExists) {
+			return c.JSON(http.StatusConflict, err.Error())
+		}
+		if duplicated := new(utils.BookDuplicatedError); errors.As(err, duplicated) {
+			return c.JSON(http.StatusConflict, dtos.BookDuplicatedResponse{Msg: err.Error(), ExistingBookId: duplicated.ExistingBookId})
+		}
+		c.Logger().Error("Error on merge books %s", err.Error())
+		return c.JSON(http.StatusInternalServerError, "Error on merge books. Please contact system admin")
+	}
+
+	bookMerged.Localize(c.Request().Header.Get("Accept-Language"))
+	return c.JSON(http.StatusOK, bookMerged)
+}
+
+// AddBookTags godoc
+// @Summary Add tags to a book.
+// @Description Add tags to a book. Tags are stored in lower case and created when they do not exist yet.
+// @Tags Books
+// @Accept json
+// @Produce json
+// @Param Accept-Language header string false "preferred languages of the book titles, e.g. pt-BR, en;q=0.8"
+// @Param id   path int true "Book ID"
+// @Param request body dtos.BookTagsRequest true "tags"
+// @Success 200 {object} dtos.BookResponse
+// @Failure 400 {object} string
+// @Failure 404 {object} string
+// @Failure 500 {object} string
+// @Router /books/{id}/tags [post]
+func (b *bookController) AddBookTags(c echo.Context) error {
+
+	id, err := strconv.Atoi(c.Param("id"))
+
+	if err != nil {
+		c.Logger().Warn("Error on parse parameters id on add book tags %s", err.Error())
+		return c.JSON(http.StatusBadRequest, "Invalid query parameter id")
+	}
+
+	bookTagsRequest := new(dtos.BookTagsRequest)
+	if err := c.Bind(bookTagsRequest); err != nil {
+		c.Logger().Warn("Error on parse body on add book tags %s", err.Error())
+		return c.JSON(http.StatusBadRequest, fmt.Sprintf("Error on parse body to add book tags: %s", err.Error()))
+	}
+
+	book, err := b.bookService.AddBookTags(id, *bookTagsRequest)
+
+	if err != nil {
+		if errors.Is(err, utils.ErrInvalidTag) {
+			return c.JSON(http.StatusBadRequest, err.Error())
+		}
+		if errors.Is(err, utils.ErrBookIdNotFound) {
+			return c.JSON(http.StatusNotFound, err.Error())
+		}
+		c.Logger().Error("Error on add book tags %s", err.Error())
+		return c.JSON(http.StatusInternalServerError, "Error on add book tags. Please contact system admin")
+	}
+
+	book.Localize(c.Request().Header.Get("Accept-Language"))
+	return c.JSON(http.StatusOK, book)
+}
+
+// RemoveBookTag godoc
+// @Summary Remove a tag from a book.
+// @Description Remove a tag from a book.
+// @Tags Books
+// @Accept */*
+// @Produce json
+// @Param id   path int true "Book ID"
+// @Param tag   path string true "Tag"
+// @Success 204 {object} string
+// @Failure 400 {object} string
+// @Failure 404 {object} string
+// @Failure 500 {object} string
+// @Router /books/{id}/tags/{tag} [delete]
+func (b *bookController) RemoveBookTag(c echo.Context) error {
+
+	id, err := strconv.Atoi(c.Param("id"))
+
+	if err != nil {
+		c.Logger().Warn("Error on parse parameters id on remove book tag %s", err.Error())
+		return c.JSON(http.StatusBadRequest, "Invalid query parameter id")
+	}
+
+	// The router matches on the raw path when the request has escaped characters, e.g. "c%2B%2B"
+	tag := c.Param("tag")
+	if c.Request().URL.RawPath != "" {
+		if tag, err = url.PathUnescape(tag); err != nil {
+			c.Logger().Warn("Error on parse parameters tag on remove book tag %s", err.Error())
+			return c.JSON(http.StatusBadRequest, "Invalid query parameter tag")
+		}
+	}
+
+	if err := b.bookService.RemoveBookTag(id, tag); err != nil {
+		if errors.Is(err, utils.ErrInvalidTag) {
+			return c.JSON(http.StatusBadRequest, err.Error())
+		}
+		if errors.Is(err, utils.ErrBookIdNotFound) || errors.Is(err, utils.ErrBookTagNotFound) {
+			return c.JSON(http.StatusNotFound, err.Error())
+		}
+		c.Logger().Error("Error on remove book tag %s", err.Error())
+		return c.JSON(http.StatusInternalServerError, "Error on remove book tag. Please contact system admin")
+	}
+
+	return c.JSON(http.StatusNoContent, "Deleted")
+}
@@ -112,7 +112,7 @@ func TestGetBook(t *testing.T) {
 	e.ServeHTTP(recorder, request)
 
 	books := dtos.BookResponse{
-		Id: bookId, Name: bookName, Edition: bookEdition, PublicationYear: publicationYear, Authors: authorName,
+		Id: bookId, Name: bookName, Title: bookName, Edition: bookEdition, PublicationYear: publicationYear, Authors: authorName,
 	}
 	respExpected, _ := json.Marshal(books)
 	require.Equal(t, fmt.Sprintf("%s%s", respExpected, "\n"), recorder.Body.String())
@@ -122,6 +122,41 @@ func TestGetBook(t *testing.T) {
 
 }
 
+func TestGetBookLocalizedTitle(t *testing.T) {
+	tests := map[string]struct {
+		acceptLanguage string
+		expectedTitle  string
+	}{
+		"translated title":             {acceptLanguage: "pt-PT, en;q=0.8", expectedTitle: "O Hobbit"},
+		"name in the language of book": {acceptLanguage: "en-GB, pt;q=0.8", expectedTitle: "The Hobbit"},
+		"name when no title fits":      {acceptLanguage: "fr", expectedTitle: "The Hobbit"},
+		"name without header":          {expectedTitle: "The Hobbit"},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			bookServiceMock := new(bookservicemock.BookServiceMock)
+			bookServiceMock.On("GetBook", 1).Return(dtos.BookResponse{
+				Id: 1, Name: "The Hobbit", Language: "en", Titles: map[string]string{"pt-BR": "O Hobbit", "es": "El Hobbit"},
+			}, nil)
+
+			bookControllerTest := bookController{bookService: bookServiceMock}
+
+			request, _ := http.NewRequest("GET", "/book/1", nil)
+			request.Header.Set("Accept-Language", tc.acceptLanguage)
+			recorder := httptest.NewRecorder()
+			e := echo.New()
+			e.GET("/book/:id", bookControllerTest.GetBook)
+			e.ServeHTTP(recorder, request)
+
+			require.Equal(t, http.StatusOK, recorder.Code)
+			var book dtos.BookResponse
+			require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &book))
+			require.Equal(t, tc.expectedTitle, book.Title)
+			require.Equal(t, "The Hobbit", book.Name)
+		})
+	}
+}
+
 func TestGetBookErrorParameterId(t *testing.T) {
 	bookServiceMock := new(bookservicemock.BookServiceMock)
 	bookControllerTest := bookController{bookService: bookServiceMock}
@@ -269,6 +304,24 @@ func TestDeleteBookErrorOnInvalidParameterId(t *testing.T) {
 
 }
 
+func TestDeleteBookWhenBookHasCopies(t *testing.T) {
+	bookId := 12
+
+	bookServiceMock := new(bookservicemock.BookServiceMock)
+	bookServiceMock.On("DeleteBook", bookId).Return(utils.ErrBookHasCopies)
+
+	bookControllerTest := bookController{bookService: bookServiceMock}
+
+	request, err := http.NewRequest("DELETE", fmt.Sprintf("/book/%v", bookId), nil)
+	recorder := httptest.NewRecorder()
+	e := echo.New()
+	e.DELETE("/book/:id", bookControllerTest.DeleteBook)
+	e.ServeHTTP(recorder, request)
+
+	require.NoError(t, err)
+	require.Equal(t, http.StatusConflict, recorder.Code)
+}
+
 func TestDeleteBookErrorOnService(t *testing.T) {
 	bookId := 12
 
@@ -294,7 +347,7 @@ func TestUpdateBook(t *testing.T) {
 	bookId := 12
 
 	bodyRequest := strings.NewReader(`{"name":"Harry Potter 2","edition":"Segunda edição","publication_year":2022,"authors":[5]}`)
-	bookRequestUpdate := dtos.BookRequestUpdate{Name: "Harry Potter 2", Edition: "Segunda edição", PublicationYear: 2022, Authors: []int{5}}
+	bookRequestUpdate := dtos.BookRequestUpdate{Name: "Harry Potter 2", Edition: "Segunda edição", PublicationYear: 2022, Authors: []dtos.BookAuthorRequest{{Id: 5}}}
 
 	bookServiceMock := new(bookservicemock.BookServiceMock)
 	bookServiceMock.On("UpdateBook", bookId, bookRequestUpdate).Return(dtos.BookResponse{}, nil)
@@ -350,7 +403,7 @@ func TestUpdateBookErrorOnService(t *testing.T) {
 	bookId := 12
 
 	bodyRequest := strings.NewReader(`{"name":"Harry Potter 2","edition":"Segunda edição","publication_year":2022,"authors":[5]}`)
-	bookRequestUpdate := dtos.BookRequestUpdate{Name: "Harry Potter 2", Edition: "Segunda edição", PublicationYear: 2022, Authors: []int{5}}
+	bookRequestUpdate := dtos.BookRequestUpdate{Name: "Harry Potter 2", Edition: "Segunda edição", PublicationYear: 2022, Authors: []dtos.BookAuthorRequest{{Id: 5}}}
 
 	bookServiceMock := new(bookservicemock.BookServiceMock)
 	bookServiceMock.On("UpdateBook", bookId, bookRequestUpdate).Return(dtos.BookResponse{}, errors.New("error occurred"))
@@ -374,7 +427,7 @@ func TestUpdateBookErrorWhenAuthorIdNotFound(t *testing.T) {
 	bookId := 12
 
 	bodyRequest := strings.NewReader(`{"name":"Harry Potter 2","edition":"Segunda edição","publication_year":2022,"authors":[5]}`)
-	bookRequestUpdate := dtos.BookRequestUpdate{Name: "Harry Potter 2", Edition: "Segunda edição", PublicationYear: 2022, Authors: []int{5}}
+	bookRequestUpdate := dtos.BookRequestUpdate{Name: "Harry Potter 2", Edition: "Segunda edição", PublicationYear: 2022, Authors: []dtos.BookAuthorRequest{{Id: 5}}}
 
 	bookServiceMock := new(bookservicemock.BookServiceMock)
 	bookServiceMock.On("UpdateBook", bookId, bookRequestUpdate).Return(dtos.BookResponse{}, utils.ErrAuthorIdNotFound)
@@ -393,3 +446,518 @@ func TestUpdateBookErrorWhenAuthorIdNotFound(t *testing.T) {
 	require.Equal(t, http.StatusBadRequest, recorder.Code)
 
 }
+
+func TestCreateBookWhenBookIsDuplicated(t *testing.T) {
+	bookServiceMock := new(bookservicemock.BookServiceMock)
+	bookServiceMock.On("CreateBook", dtos.BookRequestCreate{}).Return(dtos.BookResponse{}, utils.BookDuplicatedError{ExistingBookId: 9})
+
+	bookControllerTest := bookController{bookService: bookServiceMock}
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/book", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	bookControllerTest.CreateBook(c)
+
+	require.Equal(t, http.StatusConflict, rec.Code)
+	require.Contains(t, rec.Body.String(), `"existing_book_id":9`)
+}
+
+func TestUpdateBookWhenBookIsDuplicated(t *testing.T) {
+	bookId := 12
+
+	bodyRequest := strings.NewReader(`{"name":"Harry Potter 2","edition":"Segunda edição","publication_year":2022,"authors":[5]}`)
+	bookRequestUpdate := dtos.BookRequestUpdate{Name: "Harry Potter 2", Edition: "Segunda edição", PublicationYear: 2022, Authors: []dtos.BookAuthorRequest{{Id: 5}}}
+
+	bookServiceMock := new(bookservicemock.BookServiceMock)
+	bookServiceMock.On("UpdateBook", bookId, bookRequestUpdate).Return(dtos.BookResponse{}, utils.BookDuplicatedError{ExistingBookId: 9})
+
+	bookControllerTest := bookController{bookService: bookServiceMock}
+
+	request, err := http.NewRequest("PUT", fmt.Sprintf("/book/%v", bookId), bodyRequest)
+	request.Header.Add("Content-type", "application/json")
+
+	recorder := httptest.NewRecorder()
+	e := echo.New()
+	e.PUT("/book/:id", bookControllerTest.UpdateBook)
+	e.ServeHTTP(recorder, request)
+
+	require.NoError(t, err)
+	require.Equal(t, http.StatusConflict, recorder.Code)
+}
+
+func TestGetDuplicateBooks(t *testing.T) {
+	duplicatesResponse := dtos.BookDuplicatesResponseMetadata{
+		Clusters:   []dtos.BookDuplicateCluster{{NormalizedName: "harry potter", Books: []dtos.BookResponse{{Id: 1}, {Id: 2}}}},
+		Pagination: dtos.Pagination{Page: 2, Limit: 5},
+	}
+
+	bookServiceMock := new(bookservicemock.BookServiceMock)
+	bookServiceMock.On("GetDuplicateBooks", dtos.Pagination{Page: 2, Limit: 5}).Return(duplicatesResponse, nil)
+
+	bookControllerTest := bookController{bookService: bookServiceMock}
+
+	request, err := http.NewRequest("GET", "/books/duplicates?page=2&limit=5", nil)
+	recorder := httptest.NewRecorder()
+	e := echo.New()
+	e.GET("/books/duplicates", bookControllerTest.GetDuplicateBooks)
+	e.ServeHTTP(recorder, request)
+
+	respExpected, _ := json.Marshal(duplicatesResponse)
+	require.Equal(t, fmt.Sprintf("%s%s", respExpected, "\n"), recorder.Body.String())
+
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, recorder.Code)
+}
+
+func TestGetDuplicateBooksErrorOnService(t *testing.T) {
+	bookServiceMock := new(bookservicemock.BookServiceMock)
+	bookServiceMock.On("GetDuplicateBooks", dtos.Pagination{}).Return(dtos.BookDuplicatesResponseMetadata{}, errors.New("error occurred"))
+
+	bookControllerTest := bookController{bookService: bookServiceMock}
+
+	request, _ := http.NewRequest("GET", "/books/duplicates", nil)
+	recorder := httptest.NewRecorder()
+	e := echo.New()
+	e.GET("/books/duplicates", bookControllerTest.GetDuplicateBooks)
+	e.ServeHTTP(recorder, request)
+
+	require.Equal(t, http.StatusInternalServerError, recorder.Code)
+}
+
+func TestMergeBooks(t *testing.T) {
+	tests := map[string]struct {
+		id                   string
+		body                 string
+		expectedErrorOnMerge error
+		expectedStatus       int
+	}{
+		"success on merge books": {
+			id:             "1",
+			body:           `{"source_id":2}`,
+			expectedStatus: http.StatusOK,
+		},
+		"error on merge books (invalid id)": {
+			id:             "a",
+			body:           `{"source_id":2}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		"error on merge books (invalid body)": {
+			id:             "1",
+			body:           `{source_id: 2}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		"error on merge books (same id)": {
+			id:                   "1",
+			body:                 `{"source_id":2}`,
+			expectedErrorOnMerge: utils.ErrBookMergeSameId,
+			expectedStatus:       http.StatusBadRequest,
+		},
+		"error on merge books (not found)": {
+			id:                   "1",
+			body:                 `{"source_id":2}`,
+			expectedErrorOnMerge: utils.ErrBookIdNotFound,
+			expectedStatus:       http.StatusNotFound,
+		},
+		"error on merge books (duplicated)": {
+			id:                   "1",
+			body:                 `{"source_id":2}`,
+			expectedErrorOnMerge: utils.BookDuplicatedError{ExistingBookId: 3},
+			expectedStatus:       http.StatusConflict,
+		},
+		"error on merge books (isbn exists)": {
+			id:                   "1",
+			body:                 `{"source_id":2}`,
+			expectedErrorOnMerge: utils.ErrBookIsbnExists,
+			expectedStatus:       http.StatusConflict,
+		},
+		"error on merge books (service)": {
+			id:                   "1",
+			body:                 `{"source_id":2}`,
+			expectedErrorOnMerge: errors.New("error occurred"),
+			expectedStatus:       http.StatusInternalServerError,
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			bookServiceMock := new(bookservicemock.BookServiceMock)
+			bookServiceMock.On("MergeBooks", 1, 2).Return(dtos.BookResponse{Id: 1}, tc.expectedErrorOnMerge)
+
+			bookControllerTest := bookController{bookService: bookServiceMock}
+
+			request, _ := http.NewRequest("POST", fmt.Sprintf("/books/%s/merge", tc.id), strings.NewReader(tc.body))
+			request.Header.Add("Content-type", "application/json")
+			recorder := httptest.NewRecorder()
+			e := echo.New()
+			e.POST("/books/:id/merge", bookControllerTest.MergeBooks)
+			e.ServeHTTP(recorder, request)
+
+			require.Equal(t, tc.expectedStatus, recorder.Code)
+		})
+	}
+}
+
+func TestGetBookByIsbn(t *testing.T) {
+	tests := map[string]struct {
+		expectedErrorOnGet error
+		expectedStatus     int
+	}{
+		"success on get book by isbn": {
+			expectedStatus: http.StatusOK,
+		},
+		"error on get book by isbn (invalid isbn)": {
+			expectedErrorOnGet: utils.ErrInvalidIsbn,
+			expectedStatus:     http.StatusBadRequest,
+		},
+		"error on get book by isbn (not found)": {
+			expectedErrorOnGet: utils.ErrBookIsbnNotFound,
+			expectedStatus:     http.StatusNotFound,
+		},
+		"error on get book by isbn (service)": {
+			expectedErrorOnGet: errors.New("error occurred"),
+			expectedStatus:     http.StatusInternalServerError,
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			bookServiceMock := new(bookservicemock.BookServiceMock)
+			bookServiceMock.On("GetBookByIsbn", "978-0-261-10320-7").Return(dtos.BookResponse{Id: 1, Isbn13: "9780261103207"}, tc.expectedErrorOnGet)
+
+			bookControllerTest := bookController{bookService: bookServiceMock}
+
+			request, _ := http.NewRequest("GET", "/books/isbn/978-0-261-10320-7", nil)
+			recorder := httptest.NewRecorder()
+			e := echo.New()
+			e.GET("/books/isbn/:isbn", bookControllerTest.GetBookByIsbn)
+			e.ServeHTTP(recorder, request)
+
+			require.Equal(t, tc.expectedStatus, recorder.Code)
+			if tc.expectedErrorOnGet == nil {
+				require.Contains(t, recorder.Body.String(), `"isbn13":"9780261103207"`)
+			}
+		})
+	}
+}
+
+func TestCreateBookIsbnErrors(t *testing.T) {
+	tests := map[string]struct {
+		expectedErrorOnCreate error
+		expectedStatus        int
+	}{
+		"error on create book (invalid isbn)": {
+			expectedErrorOnCreate: utils.ErrInvalidIsbn,
+			expectedStatus:        http.StatusBadRequest,
+		},
+		"error on create book (isbn of another book)": {
+			expectedErrorOnCreate: utils.ErrBookIsbnExists,
+			expectedStatus:        http.StatusConflict,
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			bookServiceMock := new(bookservicemock.BookServiceMock)
+			bookServiceMock.On("CreateBook", dtos.BookRequestCreate{Isbn: "123"}).Return(dtos.BookResponse{}, tc.expectedErrorOnCreate)
+
+			bookControllerTest := bookController{bookService: bookServiceMock}
+
+			request, _ := http.NewRequest("POST", "/book", strings.NewReader(`{"isbn":"123"}`))
+			request.Header.Add("Content-type", "application/json")
+			recorder := httptest.NewRecorder()
+			e := echo.New()
+			e.POST("/book", bookControllerTest.CreateBook)
+			e.ServeHTTP(recorder, request)
+
+			require.Equal(t, tc.expectedStatus, recorder.Code)
+		})
+	}
+}
+
+func TestUpdateBookIsbnErrors(t *testing.T) {
+	tests := map[string]struct {
+		expectedErrorOnUpdate error
+		expectedStatus        int
+	}{
+		"error on update book (invalid isbn)": {
+			expectedErrorOnUpdate: utils.ErrInvalidIsbn,
+			expectedStatus:        http.StatusBadRequest,
+		},
+		"error on update book (isbn of another book)": {
+			expectedErrorOnUpdate: utils.ErrBookIsbnExists,
+			expectedStatus:        http.StatusConflict,
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			bookServiceMock := new(bookservicemock.BookServiceMock)
+			bookServiceMock.On("UpdateBook", 12, dtos.BookRequestUpdate{Isbn: "123"}).Return(dtos.BookResponse{}, tc.expectedErrorOnUpdate)
+
+			bookControllerTest := bookController{bookService: bookServiceMock}
+
+			request, _ := http.NewRequest("PUT", "/book/12", strings.NewReader(`{"isbn":"123"}`))
+			request.Header.Add("Content-type", "application/json")
+			recorder := httptest.NewRecorder()
+			e := echo.New()
+			e.PUT("/book/:id", bookControllerTest.UpdateBook)
+			e.ServeHTTP(recorder, request)
+
+			require.Equal(t, tc.expectedStatus, recorder.Code)
+		})
+	}
+}
+
+func TestCreateBookWhenPublisherIdIsNotFound(t *testing.T) {
+	bookServiceMock := new(bookservicemock.BookServiceMock)
+	bookServiceMock.On("CreateBook", dtos.BookRequestCreate{PublisherId: 7}).Return(dtos.BookResponse{}, utils.ErrPublisherIdNotFound)
+
+	bookControllerTest := bookController{bookService: bookServiceMock}
+
+	request, _ := http.NewRequest("POST", "/book", strings.NewReader(`{"publisher_id":7}`))
+	request.Header.Add("Content-type", "application/json")
+	recorder := httptest.NewRecorder()
+	e := echo.New()
+	e.POST("/book", bookControllerTest.CreateBook)
+	e.ServeHTTP(recorder, request)
+
+	require.Equal(t, http.StatusBadRequest, recorder.Code)
+}
+
+func TestCreateBookWhenGenreIdIsNotFound(t *testing.T) {
+	bookServiceMock := new(bookservicemock.BookServiceMock)
+	bookServiceMock.On("CreateBook", dtos.BookRequestCreate{Genres: []int{3}}).Return(dtos.BookResponse{}, utils.ErrGenreIdNotFound)
+
+	bookControllerTest := bookController{bookService: bookServiceMock}
+
+	request, _ := http.NewRequest("POST", "/book", strings.NewReader(`{"genres":[3]}`))
+	request.Header.Add("Content-type", "application/json")
+	recorder := httptest.NewRecorder()
+	e := echo.New()
+	e.POST("/book", bookControllerTest.CreateBook)
+	e.ServeHTTP(recorder, request)
+
+	require.Equal(t, http.StatusBadRequest, recorder.Code)
+}
+
+func TestCreateBookAuthorsWithRoles(t *testing.T) {
+	bookRequestCreate := dtos.BookRequestCreate{Name: "The Hobbit", Authors: []dtos.BookAuthorRequest{{Id: 5}, {Id: 6, Role: "illustrator"}}}
+	bookServiceMock := new(bookservicemock.BookServiceMock)
+	bookServiceMock.On("CreateBook", bookRequestCreate).Return(dtos.BookResponse{}, nil)
+	bookServiceMock.On("CreateBook", dtos.BookRequestCreate{Name: "The Hobbit", Authors: []dtos.BookAuthorRequest{{Id: 5, Role: "narrator"}}}).
+		Return(dtos.BookResponse{}, utils.ErrInvalidAuthorRole)
+
+	bookControllerTest := bookController{bookService: bookServiceMock}
+	e := echo.New()
+	e.POST("/book", bookControllerTest.CreateBook)
+
+	request, _ := http.NewRequest("POST", "/book", strings.NewReader(`{"name":"The Hobbit","authors":[5,{"id":6,"role":"illustrator"}]}`))
+	request.Header.Add("Content-type", "application/json")
+	recorder := httptest.NewRecorder()
+	e.ServeHTTP(recorder, request)
+
+	require.Equal(t, http.StatusCreated, recorder.Code)
+	bookServiceMock.AssertCalled(t, "CreateBook", bookRequestCreate)
+
+	request, _ = http.NewRequest("POST", "/book", strings.NewReader(`{"name":"The Hobbit","authors":[{"id":5,"role":"narrator"}]}`))
+	request.Header.Add("Content-type", "application/json")
+	recorder = httptest.NewRecorder()
+	e.ServeHTTP(recorder, request)
+
+	require.Equal(t, http.StatusBadRequest, recorder.Code)
+}
+
+func TestCreateBookWhenSeriesIsInvalid(t *testing.T) {
+	tests := map[string]struct {
+		body          string
+		request       dtos.BookRequestCreate
+		expectedError error
+	}{
+		"series id not found": {
+			body:          `{"series_id":4}`,
+			request:       dtos.BookRequestCreate{SeriesId: 4},
+			expectedError: utils.ErrSeriesIdNotFound,
+		},
+		"invalid series position": {
+			body:          `{"series_position":-1}`,
+			request:       dtos.BookRequestCreate{SeriesPosition: -1},
+			expectedError: utils.ErrInvalidSeriesPosition,
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			bookServiceMock := new(bookservicemock.BookServiceMock)
+			bookServiceMock.On("CreateBook", tc.request).Return(dtos.BookResponse{}, tc.expectedError)
+
+			bookControllerTest := bookController{bookService: bookServiceMock}
+
+			request, _ := http.NewRequest("POST", "/book", strings.NewReader(tc.body))
+			request.Header.Add("Content-type", "application/json")
+			recorder := httptest.NewRecorder()
+			e := echo.New()
+			e.POST("/book", bookControllerTest.CreateBook)
+			e.ServeHTTP(recorder, request)
+
+			require.Equal(t, http.StatusBadRequest, recorder.Code)
+		})
+	}
+}
+
+func TestCreateBookWhenWorkIsNotFound(t *testing.T) {
+	bookServiceMock := new(bookservicemock.BookServiceMock)
+	bookServiceMock.On("CreateBook", dtos.BookRequestCreate{WorkId: 9}).Return(dtos.BookResponse{}, utils.ErrWorkIdNotFound)
+
+	bookControllerTest := bookController{bookService: bookServiceMock}
+
+	request, _ := http.NewRequest("POST", "/book", strings.NewReader(`{"work_id":9}`))
+	request.Header.Add("Content-type", "application/json")
+	recorder := httptest.NewRecorder()
+	e := echo.New()
+	e.POST("/book", bookControllerTest.CreateBook)
+	e.ServeHTTP(recorder, request)
+
+	require.Equal(t, http.StatusBadRequest, recorder.Code)
+	require.Contains(t, recorder.Body.String(), "Work id not found")
+}
+
+func TestCreateBookWhenLanguageIsInvalid(t *testing.T) {
+	tests := map[string]struct {
+		body          string
+		request       dtos.BookRequestCreate
+		expectedError error
+	}{
+		"invalid language": {
+			body:          `{"language":"english"}`,
+			request:       dtos.BookRequestCreate{Language: "english"},
+			expectedError: utils.ErrInvalidLanguage,
+		},
+		"invalid locale": {
+			body:          `{"titles":{"brazil":"O Hobbit"}}`,
+			request:       dtos.BookRequestCreate{Titles: map[string]string{"brazil": "O Hobbit"}},
+			expectedError: utils.ErrInvalidLocale,
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			bookServiceMock := new(bookservicemock.BookServiceMock)
+			bookServiceMock.On("CreateBook", tc.request).Return(dtos.BookResponse{}, tc.expectedError)
+
+			bookControllerTest := bookController{bookService: bookServiceMock}
+
+			request, _ := http.NewRequest("POST", "/book", strings.NewReader(tc.body))
+			request.Header.Add("Content-type", "application/json")
+			recorder := httptest.NewRecorder()
+			e := echo.New()
+			e.POST("/book", bookControllerTest.CreateBook)
+			e.ServeHTTP(recorder, request)
+
+			require.Equal(t, http.StatusBadRequest, recorder.Code)
+		})
+	}
+}
+
+func TestAddBookTags(t *testing.T) {
+	tests := map[string]struct {
+		id                 string
+		body               string
+		expectedErrorOnAdd error
+		expectedStatus     int
+	}{
+		"success on add book tags": {
+			id:             "1",
+			body:           `{"tags":["Dystopia"]}`,
+			expectedStatus: http.StatusOK,
+		},
+		"error on add book tags (invalid id)": {
+			id:             "a",
+			body:           `{"tags":["Dystopia"]}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		"error on add book tags (body)": {
+			id:             "1",
+			body:           `{"tags":"Dystopia"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		"error on add book tags (invalid tag)": {
+			id:                 "1",
+			body:               `{"tags":["Dystopia"]}`,
+			expectedErrorOnAdd: utils.ErrInvalidTag,
+			expectedStatus:     http.StatusBadRequest,
+		},
+		"error on add book tags (book not found)": {
+			id:                 "1",
+			body:               `{"tags":["Dystopia"]}`,
+			expectedErrorOnAdd: utils.ErrBookIdNotFound,
+			expectedStatus:     http.StatusNotFound,
+		},
+		"error on add book tags (service)": {
+			id:                 "1",
+			body:               `{"tags":["Dystopia"]}`,
+			expectedErrorOnAdd: errors.New("error occurred"),
+			expectedStatus:     http.StatusInternalServerError,
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			bookServiceMock := new(bookservicemock.BookServiceMock)
+			bookServiceMock.On("AddBookTags", 1, dtos.BookTagsRequest{Tags: []string{"Dystopia"}}).Return(dtos.BookResponse{Id: 1, Tags: []string{"dystopia"}}, tc.expectedErrorOnAdd)
+
+			bookControllerTest := bookController{bookService: bookServiceMock}
+
+			request, _ := http.NewRequest("POST", "/books/"+tc.id+"/tags", strings.NewReader(tc.body))
+			request.Header.Add("Content-type", "application/json")
+			recorder := httptest.NewRecorder()
+			e := echo.New()
+			e.POST("/books/:id/tags", bookControllerTest.AddBookTags)
+			e.ServeHTTP(recorder, request)
+
+			require.Equal(t, tc.expectedStatus, recorder.Code)
+		})
+	}
+}
+
+func TestRemoveBookTag(t *testing.T) {
+	tests := map[string]struct {
+		path                  string
+		expectedErrorOnRemove error
+		expectedStatus        int
+	}{
+		"success on remove book tag": {
+			path:           "/books/1/tags/space%20opera",
+			expectedStatus: http.StatusNoContent,
+		},
+		"success on remove book tag (escaped)": {
+			path:           "/books/1/tags/space%20opera%2B",
+			expectedStatus: http.StatusNoContent,
+		},
+		"error on remove book tag (invalid id)": {
+			path:           "/books/a/tags/space%20opera",
+			expectedStatus: http.StatusBadRequest,
+		},
+		"error on remove book tag (book does not have it)": {
+			path:                  "/books/1/tags/space%20opera",
+			expectedErrorOnRemove: utils.ErrBookTagNotFound,
+			expectedStatus:        http.StatusNotFound,
+		},
+		"error on remove book tag (service)": {
+			path:                  "/books/1/tags/space%20opera",
+			expectedErrorOnRemove: errors.New("error occurred"),
+			expectedStatus:        http.StatusInternalServerError,
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			bookServiceMock := new(bookservicemock.BookServiceMock)
+			bookServiceMock.On("RemoveBookTag", 1, "space opera").Return(tc.expectedErrorOnRemove)
+			bookServiceMock.On("RemoveBookTag", 1, "space opera+").Return(tc.expectedErrorOnRemove)
+
+			bookControllerTest := bookController{bookService: bookServiceMock}
+
+			request, _ := http.NewRequest("DELETE", tc.path, nil)
+			recorder := httptest.NewRecorder()
+			e := echo.New()
+			e.DELETE("/books/:id/tags/:tag", bookControllerTest.RemoveBookTag)
+			e.ServeHTTP(recorder, request)
+
+			require.Equal(t, tc.expectedStatus, recorder.Code)
+		})
+	}
+}
@@ -0,0 +1,259 @@
+package controllers
+
+import (
+	"fmt"
+	"github/brunojoenk/golang-test/config"
+	bookfileservice "github/brunojoenk/golang-test/services/bookfile"
+	"github/brunojoenk/golang-test/storage"
+	"github/brunojoenk/golang-test/utils"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/pkg/errors"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+type IBookFileController interface {
+	CreateBookFile(c echo.Context) error
+	GetBookFiles(c echo.Context) error
+	GetBookFile(c echo.Context) error
+	DeleteBookFile(c echo.Context) error
+	GetDownloadLink(c echo.Context) error
+	DownloadBookFile(c echo.Context) error
+}
+
+type bookFileController struct {
+	bookFileService bookfileservice.IBookFileService
+}
+
+// NewBookFileController Controller Constructor
+func NewBookFileController(db *gorm.DB, cfg *config.Config, store storage.BlobStore) IBookFileController {
+	return &bookFileController{bookFileService: bookfileservice.NewBookFileService(db, cfg, store)}
+}
+
+// CreateBookFile godoc
+// @Summary Attach a PDF or EPUB file to a book.
+// @Description Attach a digital file, sent as the request body, to a book. The format is read from the file itself, and files larger than BOOK_FILE_MAX_BYTES (50 MiB by default) are refused. The page count of a PDF, and the title, authors, language and ISBN of an EPUB, are read on upload and compared with the book; the fields that differ are listed as mismatches. With prefill=true the language and ISBN the book has empty are filled from the file.
+// @Tags Books
+// @Accept application/pdf
+// @Accept application/epub+zip
+// @Produce json
+// @Param id   path int true "Book ID"
+// @Param   name     query     string     false  "name the file is downloaded as"     example(pride-and-prejudice.epub)
+// @Param   prefill     query     bool     false  "fill the empty language and ISBN of the book from the file"
+// @Param file body string true "PDF or EPUB file"
+// @Success 201 {object} dtos.BookFileUploadResponse
+// @Failure 400 {object} string
+// @Failure 404 {object} string
+// @Failure 413 {object} string
+// @Failure 415 {object} string
+// @Failure 500 {object} string
+// @Router /books/{id}/files [post]
+func (bf *bookFileController) CreateBookFile(c echo.Context) error {
+
+	bookId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Logger().Warn("Error on parse parameters id on create book file %s", err.Error())
+		return c.JSON(http.StatusBadRequest, "Invalid query parameter id")
+	}
+
+	prefill := false
+	if value := c.QueryParam("prefill"); value != "" {
+		if prefill, err = strconv.ParseBool(value); err != nil {
+			return c.JSON(http.StatusBadRequest, "Invalid query parameter prefill")
+		}
+	}
+
+	file, err := bf.bookFileService.CreateBookFile(bookId, c.QueryParam("name"), c.Request().Body, prefill)
+	if err != nil {
+		return bf.errorResponse(c, "create", err)
+	}
+
+	return c.JSON(http.StatusCreated, file)
+}
+
+// GetBookFiles godoc
+// @Summary Show the files of a book.
+// @Description Show the PDF and EPUB files attached to a book, the first attached first.
+// @Tags Books
+// @Accept */*
+// @Produce json
+// @Param id   path int true "Book ID"
+// @Success 200 {array} dtos.BookFileResponse
+// @Failure 400 {object} string
+// @Failure 404 {object} string
+// @Failure 500 {object} string
+// @Router /books/{id}/files [get]
+func (bf *bookFileController) GetBookFiles(c echo.Context) error {
+
+	bookId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Logger().Warn("Error on parse parameters id on get book files %s", err.Error())
+		return c.JSON(http.StatusBadRequest, "Invalid query parameter id")
+	}
+
+	files, err := bf.bookFileService.GetBookFiles(bookId)
+	if err != nil {
+		return bf.errorResponse(c, "get", err)
+	}
+
+	return c.JSON(http.StatusOK, files)
+}
+
+// GetBookFile godoc
+// @Summary Show a file of a book.
+// @Description Show a file attached to a book with the metadata read from it.
+// @Tags Books
+// @Accept */*
+// @Produce json
+// @Param id   path int true "Book ID"
+// @Param fileId   path int true "File ID"
+// @Success 200 {object} dtos.BookFileResponse
+// @Failure 400 {object} string
+// @Failure 404 {object} string
+// @Failure 500 {object} string
+// @Router /books/{id}/files/{fileId} [get]
+func (bf *bookFileController) GetBookFile(c echo.Context) error {
+
+	bookId, id, err := fileIds(c)
+	if err != nil {
+		c.Logger().Warn("Error on parse parameters on get book file %s", err.Error())
+		return c.JSON(http.StatusBadRequest, "Invalid query parameters id and fileId")
+	}
+
+	file, err := bf.bookFileService.GetBookFile(bookId, id)
+	if err != nil {
+		return bf.errorResponse(c, "get", err)
+	}
+
+	return c.JSON(http.StatusOK, file)
+}
+
+// DeleteBookFile godoc
+// @Summary Delete a file of a book.
+// @Description Delete a file attached to a book. Its download links stop working.
+// @Tags Books
+// @Accept */*
+// @Param id   path int true "Book ID"
+// @Param fileId   path int true "File ID"
+// @Success 204
+// @Failure 400 {object} string
+// @Failure 404 {object} string
+// @Failure 500 {object} string
+// @Router /books/{id}/files/{fileId} [delete]
+func (bf *bookFileController) DeleteBookFile(c echo.Context) error {
+
+	bookId, id, err := fileIds(c)
+	if err != nil {
+		c.Logger().Warn("Error on parse parameters on delete book file %s", err.Error())
+		return c.JSON(http.StatusBadRequest, "Invalid query parameters id and fileId")
+	}
+
+	if err := bf.bookFileService.DeleteBookFile(bookId, id); err != nil {
+		return bf.errorResponse(c, "delete", err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// GetDownloadLink godoc
+// @Summary Get a download link of a file of a book.
+// @Description Get a signed link to download a file of a book. Anyone holding the link can download the file, without an api key, for DOWNLOAD_LINK_MINUTES minutes (15 by default).
+// @Tags Books
+// @Accept */*
+// @Produce json
+// @Param id   path int true "Book ID"
+// @Param fileId   path int true "File ID"
+// @Success 200 {object} dtos.DownloadLinkResponse
+// @Failure 400 {object} string
+// @Failure 404 {object} string
+// @Failure 500 {object} string
+// @Router /books/{id}/files/{fileId}/link [get]
+func (bf *bookFileController) GetDownloadLink(c echo.Context) error {
+
+	bookId, id, err := fileIds(c)
+	if err != nil {
+		c.Logger().Warn("Error on parse parameters on get download link %s", err.Error())
+		return c.JSON(http.StatusBadRequest, "Invalid query parameters id and fileId")
+	}
+
+	link, err := bf.bookFileService.GetDownloadLink(bookId, id)
+	if err != nil {
+		return bf.errorResponse(c, "get download link of", err)
+	}
+
+	return c.JSON(http.StatusOK, link)
+}
+
+// DownloadBookFile godoc
+// @Summary Download a file of a book.
+// @Description Download a file of a book through a signed link from /books/{id}/files/{fileId}/link. No api key is needed. Range requests are supported, to resume a download or read part of the file.
+// @Tags Books
+// @Accept */*
+// @Produce application/pdf
+// @Produce application/epub+zip
+// @Param fileId   path int true "File ID"
+// @Param   expires     query     int     true  "expiry of the link, in Unix seconds"
+// @Param   signature     query     string     true  "signature of the link"
+// @Success 200 {file} file
+// @Success 206 {file} file
+// @Failure 400 {object} string
+// @Failure 403 {object} string
+// @Failure 404 {object} string
+// @Failure 416 {object} string
+// @Failure 500 {object} string
+// @Router /downloads/{fileId} [get]
+func (bf *bookFileController) DownloadBookFile(c echo.Context) error {
+
+	id, err := strconv.Atoi(c.Param("fileId"))
+	if err != nil {
+		c.Logger().Warn("Error on parse parameters fileId on download book file %s", err.Error())
+		return c.JSON(http.StatusBadRequest, "Invalid query parameter fileId")
+	}
+
+	download, err := bf.bookFileService.OpenDownload(id, c.QueryParam("expires"), c.QueryParam("signature"))
+	if err != nil {
+		return bf.errorResponse(c, "download", err)
+	}
+	defer download.Body.Close()
+
+	header := c.Response().Header()
+	header.Set(echo.HeaderContentType, download.ContentType)
+	header.Set(echo.HeaderContentDisposition, mime.FormatMediaType("attachment", map[string]string{"filename": download.Name}))
+	header.Set(echo.HeaderCacheControl, "private")
+	// Answers range, conditional and HEAD requests
+	http.ServeContent(c.Response(), c.Request(), "", download.ModTime, download.Body)
+	return nil
+}
+
+func fileIds(c echo.Context) (int, int, error) {
+	bookId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return 0, 0, err
+	}
+	id, err := strconv.Atoi(c.Param("fileId"))
+	if err != nil {
+		return 0, 0, err
+	}
+	return bookId, id, nil
+}
+
+func (bf *bookFileController) errorResponse(c echo.Context, action string, err error) error {
+	switch {
+	case errors.Is(err, utils.ErrInvalidBookFile):
+		return c.JSON(http.StatusBadRequest, err.Error())
+	case errors.Is(err, utils.ErrInvalidDownloadLink):
+		return c.JSON(http.StatusForbidden, err.Error())
+	case errors.Is(err, utils.ErrBookIdNotFound), errors.Is(err, utils.ErrBookFileNotFound):
+		return c.JSON(http.StatusNotFound, err.Error())
+	case errors.Is(err, utils.ErrBookFileTooLarge):
+		return c.JSON(http.StatusRequestEntityTooLarge, err.Error())
+	case errors.Is(err, utils.ErrInvalidBookFileType):
+		return c.JSON(http.StatusUnsupportedMediaType, err.Error())
+	}
+	c.Logger().Error("Error on %s book file %s", action, err.Error())
+	return c.JSON(http.StatusInternalServerError, fmt.Sprintf("Error on %s book file. Please contact system admin", action))
+}
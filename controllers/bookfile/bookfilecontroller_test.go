@@ -0,0 +1,395 @@
+package controllers
+
+import (
+	"errors"
+	"github/brunojoenk/golang-test/models/dtos"
+	bookfileservicemock "github/brunojoenk/golang-test/services/bookfile/mock"
+	"github/brunojoenk/golang-test/storage"
+	"github/brunojoenk/golang-test/utils"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateBookFile(t *testing.T) {
+	tests := map[string]struct {
+		path                  string
+		expectedPrefill       bool
+		expectedErrorOnCreate error
+		expectedStatus        int
+		expectedBodyToContain string
+	}{
+		"success on create book file": {
+			path:                  "/books/7/files?name=book.epub",
+			expectedStatus:        http.StatusCreated,
+			expectedBodyToContain: `"mismatches":[{"field":"title","book":"Emma","file":"Pride and Prejudice"}]`,
+		},
+		"success on create book file with prefill": {
+			path:                  "/books/7/files?name=book.epub&prefill=true",
+			expectedPrefill:       true,
+			expectedStatus:        http.StatusCreated,
+			expectedBodyToContain: `"prefilled":["language"]`,
+		},
+		"error on create book file (invalid id)": {
+			path:           "/books/a/files",
+			expectedStatus: http.StatusBadRequest,
+		},
+		"error on create book file (invalid prefill)": {
+			path:                  "/books/7/files?prefill=maybe",
+			expectedStatus:        http.StatusBadRequest,
+			expectedBodyToContain: "Invalid query parameter prefill",
+		},
+		"error on create book file (book not found)": {
+			path:                  "/books/7/files?name=book.epub",
+			expectedErrorOnCreate: utils.ErrBookIdNotFound,
+			expectedStatus:        http.StatusNotFound,
+		},
+		"error on create book file (too large)": {
+			path:                  "/books/7/files?name=book.epub",
+			expectedErrorOnCreate: utils.ErrBookFileTooLarge,
+			expectedStatus:        http.StatusRequestEntityTooLarge,
+		},
+		"error on create book file (not a pdf or epub)": {
+			path:                  "/books/7/files?name=book.epub",
+			expectedErrorOnCreate: utils.ErrInvalidBookFileType,
+			expectedStatus:        http.StatusUnsupportedMediaType,
+		},
+		"error on create book file (corrupted)": {
+			path:                  "/books/7/files?name=book.epub",
+			expectedErrorOnCreate: utils.ErrInvalidBookFile,
+			expectedStatus:        http.StatusBadRequest,
+		},
+		"error on create book file (service)": {
+			path:                  "/books/7/files?name=book.epub",
+			expectedErrorOnCreate: errors.New("error occurred"),
+			expectedStatus:        http.StatusInternalServerError,
+			expectedBodyToContain: "Error on create book file. Please contact system admin",
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			prefilled := []string{}
+			if tc.expectedPrefill {
+				prefilled = []string{"language"}
+			}
+			bookFileServiceMock := new(bookfileservicemock.BookFileServiceMock)
+			bookFileServiceMock.On("CreateBookFile", 7, "book.epub", mock.Anything, tc.expectedPrefill).Return(dtos.BookFileUploadResponse{
+				BookFileResponse: dtos.BookFileResponse{Id: 3, BookId: 7, Name: "book.epub", Format: "epub"},
+				Mismatches:       []dtos.BookFileMismatch{{Field: "title", Book: "Emma", File: "Pride and Prejudice"}},
+				Prefilled:        prefilled,
+			}, tc.expectedErrorOnCreate)
+
+			bookFileControllerTest := bookFileController{bookFileService: bookFileServiceMock}
+
+			request, _ := http.NewRequest("POST", tc.path, strings.NewReader("PK"))
+			request.Header.Set(echo.HeaderContentType, "application/epub+zip")
+			recorder := httptest.NewRecorder()
+			e := echo.New()
+			e.POST("/books/:id/files", bookFileControllerTest.CreateBookFile)
+			e.ServeHTTP(recorder, request)
+
+			require.Equal(t, tc.expectedStatus, recorder.Code)
+			require.Contains(t, recorder.Body.String(), tc.expectedBodyToContain)
+		})
+	}
+}
+
+func TestGetBookFiles(t *testing.T) {
+	tests := map[string]struct {
+		path                  string
+		expectedErrorOnGet    error
+		expectedStatus        int
+		expectedBodyToContain string
+	}{
+		"success on get book files": {
+			path:                  "/books/7/files",
+			expectedStatus:        http.StatusOK,
+			expectedBodyToContain: `"name":"book.pdf"`,
+		},
+		"error on get book files (invalid id)": {
+			path:           "/books/a/files",
+			expectedStatus: http.StatusBadRequest,
+		},
+		"error on get book files (book not found)": {
+			path:               "/books/7/files",
+			expectedErrorOnGet: utils.ErrBookIdNotFound,
+			expectedStatus:     http.StatusNotFound,
+		},
+		"error on get book files (service)": {
+			path:                  "/books/7/files",
+			expectedErrorOnGet:    errors.New("error occurred"),
+			expectedStatus:        http.StatusInternalServerError,
+			expectedBodyToContain: "Error on get book file. Please contact system admin",
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			bookFileServiceMock := new(bookfileservicemock.BookFileServiceMock)
+			bookFileServiceMock.On("GetBookFiles", 7).Return([]dtos.BookFileResponse{{Id: 3, BookId: 7, Name: "book.pdf"}}, tc.expectedErrorOnGet)
+
+			bookFileControllerTest := bookFileController{bookFileService: bookFileServiceMock}
+
+			request, _ := http.NewRequest("GET", tc.path, nil)
+			recorder := httptest.NewRecorder()
+			e := echo.New()
+			e.GET("/books/:id/files", bookFileControllerTest.GetBookFiles)
+			e.ServeHTTP(recorder, request)
+
+			require.Equal(t, tc.expectedStatus, recorder.Code)
+			require.Contains(t, recorder.Body.String(), tc.expectedBodyToContain)
+		})
+	}
+}
+
+func TestGetBookFile(t *testing.T) {
+	tests := map[string]struct {
+		path                  string
+		expectedErrorOnGet    error
+		expectedStatus        int
+		expectedBodyToContain string
+	}{
+		"success on get book file": {
+			path:                  "/books/7/files/3",
+			expectedStatus:        http.StatusOK,
+			expectedBodyToContain: `"pages":12`,
+		},
+		"error on get book file (invalid file id)": {
+			path:                  "/books/7/files/a",
+			expectedStatus:        http.StatusBadRequest,
+			expectedBodyToContain: "Invalid query parameters id and fileId",
+		},
+		"error on get book file (file not found)": {
+			path:                  "/books/7/files/3",
+			expectedErrorOnGet:    utils.ErrBookFileNotFound,
+			expectedStatus:        http.StatusNotFound,
+			expectedBodyToContain: utils.ErrBookFileNotFound.Error(),
+		},
+		"error on get book file (service)": {
+			path:               "/books/7/files/3",
+			expectedErrorOnGet: errors.New("error occurred"),
+			expectedStatus:     http.StatusInternalServerError,
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			bookFileServiceMock := new(bookfileservicemock.BookFileServiceMock)
+			bookFileServiceMock.On("GetBookFile", 7, 3).Return(dtos.BookFileResponse{Id: 3, BookId: 7, Pages: 12}, tc.expectedErrorOnGet)
+
+			bookFileControllerTest := bookFileController{bookFileService: bookFileServiceMock}
+
+			request, _ := http.NewRequest("GET", tc.path, nil)
+			recorder := httptest.NewRecorder()
+			e := echo.New()
+			e.GET("/books/:id/files/:fileId", bookFileControllerTest.GetBookFile)
+			e.ServeHTTP(recorder, request)
+
+			require.Equal(t, tc.expectedStatus, recorder.Code)
+			require.Contains(t, recorder.Body.String(), tc.expectedBodyToContain)
+		})
+	}
+}
+
+func TestDeleteBookFile(t *testing.T) {
+	tests := map[string]struct {
+		path                  string
+		expectedErrorOnDelete error
+		expectedStatus        int
+	}{
+		"success on delete book file": {
+			path:           "/books/7/files/3",
+			expectedStatus: http.StatusNoContent,
+		},
+		"error on delete book file (invalid id)": {
+			path:           "/books/a/files/3",
+			expectedStatus: http.StatusBadRequest,
+		},
+		"error on delete book file (file not found)": {
+			path:                  "/books/7/files/3",
+			expectedErrorOnDelete: utils.ErrBookFileNotFound,
+			expectedStatus:        http.StatusNotFound,
+		},
+		"error on delete book file (service)": {
+			path:                  "/books/7/files/3",
+			expectedErrorOnDelete: errors.New("error occurred"),
+			expectedStatus:        http.StatusInternalServerError,
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			bookFileServiceMock := new(bookfileservicemock.BookFileServiceMock)
+			bookFileServiceMock.On("DeleteBookFile", 7, 3).Return(tc.expectedErrorOnDelete)
+
+			bookFileControllerTest := bookFileController{bookFileService: bookFileServiceMock}
+
+			request, _ := http.NewRequest("DELETE", tc.path, nil)
+			recorder := httptest.NewRecorder()
+			e := echo.New()
+			e.DELETE("/books/:id/files/:fileId", bookFileControllerTest.DeleteBookFile)
+			e.ServeHTTP(recorder, request)
+
+			require.Equal(t, tc.expectedStatus, recorder.Code)
+		})
+	}
+}
+
+func TestGetDownloadLink(t *testing.T) {
+	tests := map[string]struct {
+		path                  string
+		expectedErrorOnGet    error
+		expectedStatus        int
+		expectedBodyToContain string
+	}{
+		"success on get download link": {
+			path:                  "/books/7/files/3/link",
+			expectedStatus:        http.StatusOK,
+			expectedBodyToContain: `"url":"/downloads/3?expires=1664619300\u0026signature=abc"`,
+		},
+		"error on get download link (invalid id)": {
+			path:           "/books/7/files/a/link",
+			expectedStatus: http.StatusBadRequest,
+		},
+		"error on get download link (file not found)": {
+			path:               "/books/7/files/3/link",
+			expectedErrorOnGet: utils.ErrBookFileNotFound,
+			expectedStatus:     http.StatusNotFound,
+		},
+		"error on get download link (service)": {
+			path:                  "/books/7/files/3/link",
+			expectedErrorOnGet:    errors.New("error occurred"),
+			expectedStatus:        http.StatusInternalServerError,
+			expectedBodyToContain: "Error on get download link of book file. Please contact system admin",
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			bookFileServiceMock := new(bookfileservicemock.BookFileServiceMock)
+			bookFileServiceMock.On("GetDownloadLink", 7, 3).Return(dtos.DownloadLinkResponse{
+				Url:       "/downloads/3?expires=1664619300&signature=abc",
+				ExpiresAt: time.Unix(1664619300, 0).UTC(),
+			}, tc.expectedErrorOnGet)
+
+			bookFileControllerTest := bookFileController{bookFileService: bookFileServiceMock}
+
+			request, _ := http.NewRequest("GET", tc.path, nil)
+			recorder := httptest.NewRecorder()
+			e := echo.New()
+			e.GET("/books/:id/files/:fileId/link", bookFileControllerTest.GetDownloadLink)
+			e.ServeHTTP(recorder, request)
+
+			require.Equal(t, tc.expectedStatus, recorder.Code)
+			require.Contains(t, recorder.Body.String(), tc.expectedBodyToContain)
+		})
+	}
+}
+
+func TestDownloadBookFile(t *testing.T) {
+	store := &storage.LocalBlobStore{Root: t.TempDir()}
+	require.NoError(t, store.Put("files/7/1.pdf", strings.NewReader("%PDF-1.7 content"), 16, "application/pdf"))
+	modTime := time.Date(2022, 10, 1, 10, 0, 0, 0, time.UTC)
+
+	tests := map[string]struct {
+		method                string
+		path                  string
+		rangeHeader           string
+		expectedErrorOnOpen   error
+		expectedStatus        int
+		expectedBody          string
+		expectedContentRange  string
+		expectedContentLength string
+	}{
+		"success on download book file": {
+			method:                "GET",
+			path:                  "/downloads/3?expires=1664619300&signature=abc",
+			expectedStatus:        http.StatusOK,
+			expectedBody:          "%PDF-1.7 content",
+			expectedContentLength: "16",
+		},
+		"success on download book file (range)": {
+			method:                "GET",
+			path:                  "/downloads/3?expires=1664619300&signature=abc",
+			rangeHeader:           "bytes=9-",
+			expectedStatus:        http.StatusPartialContent,
+			expectedBody:          "content",
+			expectedContentRange:  "bytes 9-15/16",
+			expectedContentLength: "7",
+		},
+		"success on download book file (head)": {
+			method:                "HEAD",
+			path:                  "/downloads/3?expires=1664619300&signature=abc",
+			expectedStatus:        http.StatusOK,
+			expectedContentLength: "16",
+		},
+		"error on download book file (range past the end)": {
+			method:               "GET",
+			path:                 "/downloads/3?expires=1664619300&signature=abc",
+			rangeHeader:          "bytes=20-30",
+			expectedStatus:       http.StatusRequestedRangeNotSatisfiable,
+			expectedBody:         "invalid range: failed to overlap\n",
+			expectedContentRange: "bytes */16",
+		},
+		"error on download book file (invalid id)": {
+			method:         "GET",
+			path:           "/downloads/a?expires=1664619300&signature=abc",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `"Invalid query parameter fileId"` + "\n",
+		},
+		"error on download book file (invalid or expired link)": {
+			method:              "GET",
+			path:                "/downloads/3?expires=1664619300&signature=abc",
+			expectedErrorOnOpen: utils.ErrInvalidDownloadLink,
+			expectedStatus:      http.StatusForbidden,
+			expectedBody:        `"` + utils.ErrInvalidDownloadLink.Error() + `"` + "\n",
+		},
+		"error on download book file (file deleted)": {
+			method:              "GET",
+			path:                "/downloads/3?expires=1664619300&signature=abc",
+			expectedErrorOnOpen: utils.ErrBookFileNotFound,
+			expectedStatus:      http.StatusNotFound,
+			expectedBody:        `"` + utils.ErrBookFileNotFound.Error() + `"` + "\n",
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			bookFileServiceMock := new(bookfileservicemock.BookFileServiceMock)
+			bookFileServiceMock.On("OpenDownload", 3, "1664619300", "abc").Return(dtos.BookFileDownload{
+				Body:        storage.NewBlobReader(store, "files/7/1.pdf", 16),
+				Name:        "Dom Casmurro.pdf",
+				ContentType: "application/pdf",
+				ModTime:     modTime,
+			}, tc.expectedErrorOnOpen)
+
+			bookFileControllerTest := bookFileController{bookFileService: bookFileServiceMock}
+
+			request, _ := http.NewRequest(tc.method, tc.path, nil)
+			if tc.rangeHeader != "" {
+				request.Header.Set("Range", tc.rangeHeader)
+			}
+			recorder := httptest.NewRecorder()
+			e := echo.New()
+			e.GET("/downloads/:fileId", bookFileControllerTest.DownloadBookFile)
+			e.HEAD("/downloads/:fileId", bookFileControllerTest.DownloadBookFile)
+			e.ServeHTTP(recorder, request)
+
+			require.Equal(t, tc.expectedStatus, recorder.Code)
+			body, _ := io.ReadAll(recorder.Body)
+			require.Equal(t, tc.expectedBody, string(body))
+			require.Equal(t, tc.expectedContentRange, recorder.Header().Get("Content-Range"))
+			if tc.expectedContentLength != "" {
+				require.Equal(t, tc.expectedContentLength, recorder.Header().Get(echo.HeaderContentLength))
+			}
+			if tc.expectedStatus == http.StatusOK || tc.expectedStatus == http.StatusPartialContent {
+				require.Equal(t, "application/pdf", recorder.Header().Get(echo.HeaderContentType))
+				require.Equal(t, `attachment; filename="Dom Casmurro.pdf"`, recorder.Header().Get(echo.HeaderContentDisposition))
+				require.Equal(t, "bytes", recorder.Header().Get("Accept-Ranges"))
+				require.Equal(t, modTime.Format(http.TimeFormat), recorder.Header().Get("Last-Modified"))
+			}
+		})
+	}
+}
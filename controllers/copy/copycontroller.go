@@ -0,0 +1,229 @@
+package controllers
+
+import (
+	"fmt"
+	"github/brunojoenk/golang-test/config"
+	"github/brunojoenk/golang-test/models/dtos"
+	copyservice "github/brunojoenk/golang-test/services/copy"
+	"github/brunojoenk/golang-test/utils"
+	"net/http"
+	"strconv"
+
+	"github.com/pkg/errors"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+type ICopyController interface {
+	CreateCopy(c echo.Context) error
+	GetBookCopies(c echo.Context) error
+	GetCopy(c echo.Context) error
+	UpdateCopy(c echo.Context) error
+	DeleteCopy(c echo.Context) error
+}
+
+type copyController struct {
+	copyService copyservice.ICopyService
+}
+
+// NewCopyController Controller Constructor
+func NewCopyController(db *gorm.DB, cfg *config.Config) ICopyController {
+	return &copyController{copyService: copyservice.NewCopyService(db, cfg)}
+}
+
+// CreateCopy godoc
+// @Summary Add a copy of a book.
+// @Description Add a physical copy of a book. Barcodes are unique, status defaults to available and condition to good.
+// @Tags Copies
+// @Accept json
+// @Produce json
+// @Param id   path int true "Book ID"
+// @Param request body dtos.CopyRequest true "copy"
+// @Success 201 {object} dtos.CopyResponse
+// @Failure 400 {object} string
+// @Failure 404 {object} string
+// @Failure 409 {object} string
+// @Failure 500 {object} string
+// @Router /books/{id}/copies [post]
+func (cc *copyController) CreateCopy(c echo.Context) error {
+
+	bookId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Logger().Warn("Error on parse parameters id on create copy %s", err.Error())
+		return c.JSON(http.StatusBadRequest, "Invalid query parameter id")
+	}
+
+	copyRequest := new(dtos.CopyRequest)
+	if err := c.Bind(copyRequest); err != nil {
+		c.Logger().Warn("Error on bind body to create copy: %s", err.Error())
+		return c.JSON(http.StatusBadRequest, fmt.Sprintf("Request body to create a copy is invalid: %s", err.Error()))
+	}
+
+	bookCopy, err := cc.copyService.CreateCopy(bookId, *copyRequest)
+	if err != nil {
+		return cc.errorResponse(c, "create", err)
+	}
+
+	return c.JSON(http.StatusCreated, bookCopy)
+}
+
+// GetBookCopies godoc
+// @Summary Show the copies of a book with paginations.
+// @Description Show the copies of a book with paginations.
+// @Tags Copies
+// @Accept */*
+// @Produce json
+// @Param id   path int true "Book ID"
+// @Param   status     query     string     false  "search copies by status"     Enums(available, on_loan, on_hold, lost, withdrawn)
+// @Param   branch     query     string     false  "search copies by branch"     example(string)
+// @Param   page     query     int     false  "page list"     example(1) minimum(1)
+// @Param   limit     query     int     false  "page size"     example(1) minimum(1) maximum(100)
+// @Success 200 {object} dtos.CopyResponseMetadata
+// @Failure 400 {object} string
+// @Failure 404 {object} string
+// @Failure 500 {object} string
+// @Router /books/{id}/copies [get]
+func (cc *copyController) GetBookCopies(c echo.Context) error {
+
+	bookId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Logger().Warn("Error on parse parameters id on get copies %s", err.Error())
+		return c.JSON(http.StatusBadRequest, "Invalid query parameter id")
+	}
+
+	var filter dtos.GetCopiesFilter
+	if err := c.Bind(&filter); err != nil {
+		c.Logger().Warn("Error on bind query to filter copies: %s", err.Error())
+		return c.JSON(http.StatusBadRequest, fmt.Sprintf("Invalid query parameters: %s", err.Error()))
+	}
+
+	copies, err := cc.copyService.GetBookCopies(bookId, filter)
+	if err != nil {
+		return cc.errorResponse(c, "get all", err)
+	}
+
+	return c.JSON(http.StatusOK, copies)
+}
+
+// GetCopy godoc
+// @Summary Get a copy of a book.
+// @Description Get a copy of a book.
+// @Tags Copies
+// @Accept */*
+// @Produce json
+// @Param id   path int true "Book ID"
+// @Param copyId   path int true "Copy ID"
+// @Success 200 {object} dtos.CopyResponse
+// @Failure 400 {object} string
+// @Failure 404 {object} string
+// @Failure 500 {object} string
+// @Router /books/{id}/copies/{copyId} [get]
+func (cc *copyController) GetCopy(c echo.Context) error {
+
+	bookId, id, err := copyIds(c)
+	if err != nil {
+		c.Logger().Warn("Error on parse parameters on get copy %s", err.Error())
+		return c.JSON(http.StatusBadRequest, "Invalid query parameters id and copyId")
+	}
+
+	bookCopy, err := cc.copyService.GetCopy(bookId, id)
+	if err != nil {
+		return cc.errorResponse(c, "get", err)
+	}
+
+	return c.JSON(http.StatusOK, bookCopy)
+}
+
+// UpdateCopy godoc
+// @Summary Update a copy of a book.
+// @Description Update a copy of a book, e.g. to move it to another branch or mark it lost. An empty status or condition keeps the current one. The status on_loan is set by checking the copy out and in, and on_hold by the hold queue of the book.
+// @Tags Copies
+// @Accept json
+// @Produce json
+// @Param id   path int true "Book ID"
+// @Param copyId   path int true "Copy ID"
+// @Param request body dtos.CopyRequest true "copy"
+// @Success 200 {object} dtos.CopyResponse
+// @Failure 400 {object} string
+// @Failure 404 {object} string
+// @Failure 409 {object} string
+// @Failure 500 {object} string
+// @Router /books/{id}/copies/{copyId} [put]
+func (cc *copyController) UpdateCopy(c echo.Context) error {
+
+	bookId, id, err := copyIds(c)
+	if err != nil {
+		c.Logger().Warn("Error on parse parameters on update copy %s", err.Error())
+		return c.JSON(http.StatusBadRequest, "Invalid query parameters id and copyId")
+	}
+
+	copyRequest := new(dtos.CopyRequest)
+	if err := c.Bind(copyRequest); err != nil {
+		c.Logger().Warn("Error on parse body on update copy %s", err.Error())
+		return c.JSON(http.StatusBadRequest, fmt.Sprintf("Error on parse body to update copy: %s", err.Error()))
+	}
+
+	bookCopy, err := cc.copyService.UpdateCopy(bookId, id, *copyRequest)
+	if err != nil {
+		return cc.errorResponse(c, "update", err)
+	}
+
+	return c.JSON(http.StatusOK, bookCopy)
+}
+
+// DeleteCopy godoc
+// @Summary Delete a copy of a book.
+// @Description Delete a copy of a book that is not on loan.
+// @Tags Copies
+// @Accept */*
+// @Produce json
+// @Param id   path int true "Book ID"
+// @Param copyId   path int true "Copy ID"
+// @Success 204 {object} string
+// @Failure 400 {object} string
+// @Failure 404 {object} string
+// @Failure 409 {object} string
+// @Failure 500 {object} string
+// @Router /books/{id}/copies/{copyId} [delete]
+func (cc *copyController) DeleteCopy(c echo.Context) error {
+
+	bookId, id, err := copyIds(c)
+	if err != nil {
+		c.Logger().Warn("Error on parse parameters on delete copy %s", err.Error())
+		return c.JSON(http.StatusBadRequest, "Invalid query parameters id and copyId")
+	}
+
+	if err := cc.copyService.DeleteCopy(bookId, id); err != nil {
+		return cc.errorResponse(c, "delete", err)
+	}
+
+	return c.JSON(http.StatusNoContent, "Deleted")
+}
+
+func copyIds(c echo.Context) (int, int, error) {
+	bookId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return 0, 0, err
+	}
+	id, err := strconv.Atoi(c.Param("copyId"))
+	if err != nil {
+		return 0, 0, err
+	}
+	return bookId, id, nil
+}
+
+func (cc *copyController) errorResponse(c echo.Context, action string, err error) error {
+	switch {
+	case errors.Is(err, utils.ErrInvalidCopyBarcode), errors.Is(err, utils.ErrInvalidCopyStatus),
+		errors.Is(err, utils.ErrInvalidCopyCondition), errors.Is(err, utils.ErrInvalidDate):
+		return c.JSON(http.StatusBadRequest, err.Error())
+	case errors.Is(err, utils.ErrBookIdNotFound), errors.Is(err, utils.ErrCopyIdNotFound):
+		return c.JSON(http.StatusNotFound, err.Error())
+	case errors.Is(err, utils.ErrCopyBarcodeExists), errors.Is(err, utils.ErrCopyStatusOnLoan), errors.Is(err, utils.ErrCopyOnLoan),
+		errors.Is(err, utils.ErrCopyStatusOnHold):
+		return c.JSON(http.StatusConflict, err.Error())
+	}
+	c.Logger().Error("Error on %s copy %s", action, err.Error())
+	return c.JSON(http.StatusInternalServerError, fmt.Sprintf("Error on %s copy. Please contact system admin", action))
+}
@@ -0,0 +1,238 @@
+package controllers
+
+import (
+	"errors"
+	"github/brunojoenk/golang-test/models/dtos"
+	"github/brunojoenk/golang-test/utils"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	copyservicemock "github/brunojoenk/golang-test/services/copy/mock"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateCopy(t *testing.T) {
+	tests := map[string]struct {
+		id                    string
+		body                  string
+		expectedErrorOnCreate error
+		expectedStatus        int
+	}{
+		"success on create copy": {
+			id:             "1",
+			body:           `{"barcode":"0001","branch":"Central"}`,
+			expectedStatus: http.StatusCreated,
+		},
+		"error on create copy (invalid id)": {
+			id:             "a",
+			body:           `{"barcode":"0001","branch":"Central"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		"error on create copy (body)": {
+			id:             "1",
+			body:           `{"barcode":1}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		"error on create copy (invalid status)": {
+			id:                    "1",
+			body:                  `{"barcode":"0001","branch":"Central"}`,
+			expectedErrorOnCreate: utils.ErrInvalidCopyStatus,
+			expectedStatus:        http.StatusBadRequest,
+		},
+		"error on create copy (book not found)": {
+			id:                    "1",
+			body:                  `{"barcode":"0001","branch":"Central"}`,
+			expectedErrorOnCreate: utils.ErrBookIdNotFound,
+			expectedStatus:        http.StatusNotFound,
+		},
+		"error on create copy (barcode exists)": {
+			id:                    "1",
+			body:                  `{"barcode":"0001","branch":"Central"}`,
+			expectedErrorOnCreate: utils.ErrCopyBarcodeExists,
+			expectedStatus:        http.StatusConflict,
+		},
+		"error on create copy (service)": {
+			id:                    "1",
+			body:                  `{"barcode":"0001","branch":"Central"}`,
+			expectedErrorOnCreate: errors.New("error occurred"),
+			expectedStatus:        http.StatusInternalServerError,
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			copyServiceMock := new(copyservicemock.CopyServiceMock)
+			copyServiceMock.On("CreateCopy", 1, dtos.CopyRequest{Barcode: "0001", Branch: "Central"}).Return(dtos.CopyResponse{Id: 5, BookId: 1}, tc.expectedErrorOnCreate)
+
+			copyControllerTest := copyController{copyService: copyServiceMock}
+
+			request, _ := http.NewRequest("POST", "/books/"+tc.id+"/copies", strings.NewReader(tc.body))
+			request.Header.Add("Content-type", "application/json")
+			recorder := httptest.NewRecorder()
+			e := echo.New()
+			e.POST("/books/:id/copies", copyControllerTest.CreateCopy)
+			e.ServeHTTP(recorder, request)
+
+			require.Equal(t, tc.expectedStatus, recorder.Code)
+		})
+	}
+}
+
+func TestGetBookCopies(t *testing.T) {
+	copiesResponse := dtos.CopyResponseMetadata{
+		Copies:     []dtos.CopyResponse{{Id: 5, BookId: 1, Barcode: "0001", Status: dtos.CopyStatusAvailable}},
+		Pagination: dtos.Pagination{Page: 1, Limit: 10},
+	}
+	copyServiceMock := new(copyservicemock.CopyServiceMock)
+	copyServiceMock.On("GetBookCopies", 1, dtos.GetCopiesFilter{Status: dtos.CopyStatusAvailable}).Return(copiesResponse, nil)
+	copyServiceMock.On("GetBookCopies", 1, dtos.GetCopiesFilter{Status: "borrowed"}).Return(dtos.CopyResponseMetadata{}, utils.ErrInvalidCopyStatus)
+	copyServiceMock.On("GetBookCopies", 2, dtos.GetCopiesFilter{}).Return(dtos.CopyResponseMetadata{}, utils.ErrBookIdNotFound)
+
+	copyControllerTest := copyController{copyService: copyServiceMock}
+	e := echo.New()
+	e.GET("/books/:id/copies", copyControllerTest.GetBookCopies)
+
+	request, _ := http.NewRequest("GET", "/books/1/copies?status=available", nil)
+	recorder := httptest.NewRecorder()
+	e.ServeHTTP(recorder, request)
+
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Contains(t, recorder.Body.String(), `"barcode":"0001"`)
+
+	request, _ = http.NewRequest("GET", "/books/1/copies?status=borrowed", nil)
+	recorder = httptest.NewRecorder()
+	e.ServeHTTP(recorder, request)
+
+	require.Equal(t, http.StatusBadRequest, recorder.Code)
+
+	request, _ = http.NewRequest("GET", "/books/2/copies", nil)
+	recorder = httptest.NewRecorder()
+	e.ServeHTTP(recorder, request)
+
+	require.Equal(t, http.StatusNotFound, recorder.Code)
+}
+
+func TestGetCopy(t *testing.T) {
+	tests := map[string]struct {
+		path               string
+		expectedErrorOnGet error
+		expectedStatus     int
+	}{
+		"success on get copy": {
+			path:           "/books/1/copies/5",
+			expectedStatus: http.StatusOK,
+		},
+		"error on get copy (invalid copy id)": {
+			path:           "/books/1/copies/a",
+			expectedStatus: http.StatusBadRequest,
+		},
+		"error on get copy (not found)": {
+			path:               "/books/1/copies/5",
+			expectedErrorOnGet: utils.ErrCopyIdNotFound,
+			expectedStatus:     http.StatusNotFound,
+		},
+		"error on get copy (service)": {
+			path:               "/books/1/copies/5",
+			expectedErrorOnGet: errors.New("error occurred"),
+			expectedStatus:     http.StatusInternalServerError,
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			copyServiceMock := new(copyservicemock.CopyServiceMock)
+			copyServiceMock.On("GetCopy", 1, 5).Return(dtos.CopyResponse{Id: 5, BookId: 1, Barcode: "0001"}, tc.expectedErrorOnGet)
+
+			copyControllerTest := copyController{copyService: copyServiceMock}
+
+			request, _ := http.NewRequest("GET", tc.path, nil)
+			recorder := httptest.NewRecorder()
+			e := echo.New()
+			e.GET("/books/:id/copies/:copyId", copyControllerTest.GetCopy)
+			e.ServeHTTP(recorder, request)
+
+			require.Equal(t, tc.expectedStatus, recorder.Code)
+		})
+	}
+}
+
+func TestUpdateCopy(t *testing.T) {
+	tests := map[string]struct {
+		expectedErrorOnUpdate error
+		expectedStatus        int
+	}{
+		"success on update copy": {
+			expectedStatus: http.StatusOK,
+		},
+		"error on update copy (invalid condition)": {
+			expectedErrorOnUpdate: utils.ErrInvalidCopyCondition,
+			expectedStatus:        http.StatusBadRequest,
+		},
+		"error on update copy (not found)": {
+			expectedErrorOnUpdate: utils.ErrCopyIdNotFound,
+			expectedStatus:        http.StatusNotFound,
+		},
+		"error on update copy (barcode exists)": {
+			expectedErrorOnUpdate: utils.ErrCopyBarcodeExists,
+			expectedStatus:        http.StatusConflict,
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			copyServiceMock := new(copyservicemock.CopyServiceMock)
+			copyServiceMock.On("UpdateCopy", 1, 5, dtos.CopyRequest{Barcode: "0001", Status: dtos.CopyStatusLost}).Return(dtos.CopyResponse{Id: 5}, tc.expectedErrorOnUpdate)
+
+			copyControllerTest := copyController{copyService: copyServiceMock}
+
+			request, _ := http.NewRequest("PUT", "/books/1/copies/5", strings.NewReader(`{"barcode":"0001","status":"lost"}`))
+			request.Header.Add("Content-type", "application/json")
+			recorder := httptest.NewRecorder()
+			e := echo.New()
+			e.PUT("/books/:id/copies/:copyId", copyControllerTest.UpdateCopy)
+			e.ServeHTTP(recorder, request)
+
+			require.Equal(t, tc.expectedStatus, recorder.Code)
+		})
+	}
+}
+
+func TestDeleteCopy(t *testing.T) {
+	tests := map[string]struct {
+		expectedErrorOnDelete error
+		expectedStatus        int
+	}{
+		"success on delete copy": {
+			expectedStatus: http.StatusNoContent,
+		},
+		"error on delete copy (not found)": {
+			expectedErrorOnDelete: utils.ErrCopyIdNotFound,
+			expectedStatus:        http.StatusNotFound,
+		},
+		"error on delete copy (on loan)": {
+			expectedErrorOnDelete: utils.ErrCopyOnLoan,
+			expectedStatus:        http.StatusConflict,
+		},
+		"error on delete copy (service)": {
+			expectedErrorOnDelete: errors.New("error occurred"),
+			expectedStatus:        http.StatusInternalServerError,
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			copyServiceMock := new(copyservicemock.CopyServiceMock)
+			copyServiceMock.On("DeleteCopy", 1, 5).Return(tc.expectedErrorOnDelete)
+
+			copyControllerTest := copyController{copyService: copyServiceMock}
+
+			request, _ := http.NewRequest("DELETE", "/books/1/copies/5", nil)
+			recorder := httptest.NewRecorder()
+			e := echo.New()
+			e.DELETE("/books/:id/copies/:copyId", copyControllerTest.DeleteCopy)
+			e.ServeHTTP(recorder, request)
+
+			require.Equal(t, tc.expectedStatus, recorder.Code)
+		})
+	}
+}
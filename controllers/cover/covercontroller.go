@@ -0,0 +1,146 @@
+package controllers
+
+import (
+	"fmt"
+	"github/brunojoenk/golang-test/config"
+	coverservice "github/brunojoenk/golang-test/services/cover"
+	"github/brunojoenk/golang-test/storage"
+	"github/brunojoenk/golang-test/utils"
+	"net/http"
+	"strconv"
+
+	"github.com/pkg/errors"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+type ICoverController interface {
+	SaveCover(c echo.Context) error
+	GetCover(c echo.Context) error
+	DeleteCover(c echo.Context) error
+}
+
+type coverController struct {
+	coverService coverservice.ICoverService
+}
+
+// NewCoverController Controller Constructor
+func NewCoverController(db *gorm.DB, cfg *config.Config, store storage.BlobStore) ICoverController {
+	return &coverController{coverService: coverservice.NewCoverService(db, cfg, store)}
+}
+
+// SaveCover godoc
+// @Summary Upload the cover of a book.
+// @Description Upload a JPEG, PNG or WebP image, sent as the request body, as the cover of a book, replacing the one it had. The type is read from the image itself, and images larger than COVER_MAX_BYTES (5 MiB by default) or than 6000 pixels on a side are refused. Small (160 pixels) and medium (480 pixels) thumbnails are made on upload.
+// @Tags Books
+// @Accept image/jpeg
+// @Accept image/png
+// @Accept image/webp
+// @Produce json
+// @Param id   path int true "Book ID"
+// @Param cover body string true "JPEG, PNG or WebP image"
+// @Success 200 {object} dtos.CoverResponse
+// @Failure 400 {object} string
+// @Failure 404 {object} string
+// @Failure 413 {object} string
+// @Failure 415 {object} string
+// @Failure 500 {object} string
+// @Router /books/{id}/cover [put]
+func (cc *coverController) SaveCover(c echo.Context) error {
+
+	bookId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Logger().Warn("Error on parse parameters id on save cover %s", err.Error())
+		return c.JSON(http.StatusBadRequest, "Invalid query parameter id")
+	}
+
+	cover, err := cc.coverService.SaveCover(bookId, c.Request().Body)
+	if err != nil {
+		return cc.errorResponse(c, "save", err)
+	}
+
+	return c.JSON(http.StatusOK, cover)
+}
+
+// GetCover godoc
+// @Summary Show the cover of a book.
+// @Description Download the cover of a book as uploaded, or one of its thumbnails. Thumbnails of JPEG covers are JPEG, the others PNG. The ETag changes when the cover is replaced.
+// @Tags Books
+// @Accept */*
+// @Produce image/jpeg
+// @Produce image/png
+// @Produce image/webp
+// @Param id   path int true "Book ID"
+// @Param   size     query     string     false  "original by default"     Enums(original, small, medium)
+// @Success 200 {file} file
+// @Success 304 {object} string
+// @Failure 400 {object} string
+// @Failure 404 {object} string
+// @Failure 500 {object} string
+// @Router /books/{id}/cover [get]
+func (cc *coverController) GetCover(c echo.Context) error {
+
+	bookId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Logger().Warn("Error on parse parameters id on get cover %s", err.Error())
+		return c.JSON(http.StatusBadRequest, "Invalid query parameter id")
+	}
+
+	cover, err := cc.coverService.GetCover(bookId, c.QueryParam("size"))
+	if err != nil {
+		return cc.errorResponse(c, "get", err)
+	}
+	defer cover.Body.Close()
+
+	header := c.Response().Header()
+	header.Set(echo.HeaderCacheControl, "no-cache")
+	header.Set("ETag", cover.ETag)
+	if c.Request().Header.Get("If-None-Match") == cover.ETag {
+		return c.NoContent(http.StatusNotModified)
+	}
+
+	header.Set(echo.HeaderContentLength, strconv.FormatInt(cover.Size, 10))
+	return c.Stream(http.StatusOK, cover.ContentType, cover.Body)
+}
+
+// DeleteCover godoc
+// @Summary Delete the cover of a book.
+// @Description Delete the cover of a book and its thumbnails.
+// @Tags Books
+// @Accept */*
+// @Param id   path int true "Book ID"
+// @Success 204
+// @Failure 400 {object} string
+// @Failure 404 {object} string
+// @Failure 500 {object} string
+// @Router /books/{id}/cover [delete]
+func (cc *coverController) DeleteCover(c echo.Context) error {
+
+	bookId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Logger().Warn("Error on parse parameters id on delete cover %s", err.Error())
+		return c.JSON(http.StatusBadRequest, "Invalid query parameter id")
+	}
+
+	if err := cc.coverService.DeleteCover(bookId); err != nil {
+		return cc.errorResponse(c, "delete", err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (cc *coverController) errorResponse(c echo.Context, action string, err error) error {
+	switch {
+	case errors.Is(err, utils.ErrInvalidCoverImage), errors.Is(err, utils.ErrInvalidCoverSize):
+		return c.JSON(http.StatusBadRequest, err.Error())
+	case errors.Is(err, utils.ErrBookIdNotFound), errors.Is(err, utils.ErrCoverNotFound):
+		return c.JSON(http.StatusNotFound, err.Error())
+	case errors.Is(err, utils.ErrCoverTooLarge):
+		return c.JSON(http.StatusRequestEntityTooLarge, err.Error())
+	case errors.Is(err, utils.ErrInvalidCoverType):
+		return c.JSON(http.StatusUnsupportedMediaType, err.Error())
+	}
+	c.Logger().Error("Error on %s cover %s", action, err.Error())
+	return c.JSON(http.StatusInternalServerError, fmt.Sprintf("Error on %s cover. Please contact system admin", action))
+}
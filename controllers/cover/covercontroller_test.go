@@ -0,0 +1,208 @@
+package controllers
+
+import (
+	"errors"
+	"github/brunojoenk/golang-test/models/dtos"
+	"github/brunojoenk/golang-test/utils"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	coverservicemock "github/brunojoenk/golang-test/services/cover/mock"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSaveCover(t *testing.T) {
+	tests := map[string]struct {
+		path                  string
+		expectedErrorOnSave   error
+		expectedStatus        int
+		expectedBodyToContain string
+	}{
+		"success on save cover": {
+			path:                  "/books/7/cover",
+			expectedStatus:        http.StatusOK,
+			expectedBodyToContain: `"small":"/books/7/cover?size=small"`,
+		},
+		"error on save cover (invalid id)": {
+			path:           "/books/a/cover",
+			expectedStatus: http.StatusBadRequest,
+		},
+		"error on save cover (book not found)": {
+			path:                "/books/7/cover",
+			expectedErrorOnSave: utils.ErrBookIdNotFound,
+			expectedStatus:      http.StatusNotFound,
+		},
+		"error on save cover (too large)": {
+			path:                "/books/7/cover",
+			expectedErrorOnSave: utils.ErrCoverTooLarge,
+			expectedStatus:      http.StatusRequestEntityTooLarge,
+		},
+		"error on save cover (not an image)": {
+			path:                "/books/7/cover",
+			expectedErrorOnSave: utils.ErrInvalidCoverType,
+			expectedStatus:      http.StatusUnsupportedMediaType,
+		},
+		"error on save cover (corrupted image)": {
+			path:                "/books/7/cover",
+			expectedErrorOnSave: utils.ErrInvalidCoverImage,
+			expectedStatus:      http.StatusBadRequest,
+		},
+		"error on save cover (service)": {
+			path:                  "/books/7/cover",
+			expectedErrorOnSave:   errors.New("error occurred"),
+			expectedStatus:        http.StatusInternalServerError,
+			expectedBodyToContain: "Error on save cover. Please contact system admin",
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			coverServiceMock := new(coverservicemock.CoverServiceMock)
+			coverServiceMock.On("SaveCover", 7, mock.MatchedBy(func(body io.Reader) bool {
+				data, _ := io.ReadAll(body)
+				return string(data) == "image"
+			})).Return(dtos.CoverResponse{
+				BookId:      7,
+				ContentType: "image/png",
+				Urls:        map[string]string{"original": "/books/7/cover", "small": "/books/7/cover?size=small"},
+			}, tc.expectedErrorOnSave)
+
+			coverControllerTest := coverController{coverService: coverServiceMock}
+
+			request, _ := http.NewRequest("PUT", tc.path, strings.NewReader("image"))
+			request.Header.Set(echo.HeaderContentType, "image/png")
+			recorder := httptest.NewRecorder()
+			e := echo.New()
+			e.PUT("/books/:id/cover", coverControllerTest.SaveCover)
+			e.ServeHTTP(recorder, request)
+
+			require.Equal(t, tc.expectedStatus, recorder.Code)
+			require.Contains(t, recorder.Body.String(), tc.expectedBodyToContain)
+		})
+	}
+}
+
+func TestGetCover(t *testing.T) {
+	tests := map[string]struct {
+		path               string
+		ifNoneMatch        string
+		expectedErrorOnGet error
+		expectedStatus     int
+		expectedBody       string
+	}{
+		"success on get cover": {
+			path:           "/books/7/cover?size=small",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "image",
+		},
+		"success on get cover (not modified)": {
+			path:           "/books/7/cover?size=small",
+			ifNoneMatch:    `"1664618400-small"`,
+			expectedStatus: http.StatusNotModified,
+		},
+		"error on get cover (invalid id)": {
+			path:           "/books/a/cover",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `"Invalid query parameter id"` + "\n",
+		},
+		"error on get cover (invalid size)": {
+			path:               "/books/7/cover?size=small",
+			expectedErrorOnGet: utils.ErrInvalidCoverSize,
+			expectedStatus:     http.StatusBadRequest,
+			expectedBody:       `"` + utils.ErrInvalidCoverSize.Error() + `"` + "\n",
+		},
+		"error on get cover (book without cover)": {
+			path:               "/books/7/cover?size=small",
+			expectedErrorOnGet: utils.ErrCoverNotFound,
+			expectedStatus:     http.StatusNotFound,
+			expectedBody:       `"` + utils.ErrCoverNotFound.Error() + `"` + "\n",
+		},
+		"error on get cover (service)": {
+			path:               "/books/7/cover?size=small",
+			expectedErrorOnGet: errors.New("error occurred"),
+			expectedStatus:     http.StatusInternalServerError,
+			expectedBody:       `"Error on get cover. Please contact system admin"` + "\n",
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			coverServiceMock := new(coverservicemock.CoverServiceMock)
+			coverServiceMock.On("GetCover", 7, "small").Return(dtos.CoverImage{
+				Body:        io.NopCloser(strings.NewReader("image")),
+				ContentType: "image/png",
+				Size:        5,
+				ETag:        `"1664618400-small"`,
+			}, tc.expectedErrorOnGet)
+
+			coverControllerTest := coverController{coverService: coverServiceMock}
+
+			request, _ := http.NewRequest("GET", tc.path, nil)
+			if tc.ifNoneMatch != "" {
+				request.Header.Set("If-None-Match", tc.ifNoneMatch)
+			}
+			recorder := httptest.NewRecorder()
+			e := echo.New()
+			e.GET("/books/:id/cover", coverControllerTest.GetCover)
+			e.ServeHTTP(recorder, request)
+
+			require.Equal(t, tc.expectedStatus, recorder.Code)
+			require.Equal(t, tc.expectedBody, recorder.Body.String())
+			if tc.expectedStatus == http.StatusOK || tc.expectedStatus == http.StatusNotModified {
+				require.Equal(t, `"1664618400-small"`, recorder.Header().Get("ETag"))
+				require.Equal(t, "no-cache", recorder.Header().Get(echo.HeaderCacheControl))
+			}
+			if tc.expectedStatus == http.StatusOK {
+				require.Equal(t, "image/png", recorder.Header().Get(echo.HeaderContentType))
+				require.Equal(t, "5", recorder.Header().Get(echo.HeaderContentLength))
+			}
+		})
+	}
+}
+
+func TestDeleteCover(t *testing.T) {
+	tests := map[string]struct {
+		path                  string
+		expectedErrorOnDelete error
+		expectedStatus        int
+	}{
+		"success on delete cover": {
+			path:           "/books/7/cover",
+			expectedStatus: http.StatusNoContent,
+		},
+		"error on delete cover (invalid id)": {
+			path:           "/books/a/cover",
+			expectedStatus: http.StatusBadRequest,
+		},
+		"error on delete cover (book without cover)": {
+			path:                  "/books/7/cover",
+			expectedErrorOnDelete: utils.ErrCoverNotFound,
+			expectedStatus:        http.StatusNotFound,
+		},
+		"error on delete cover (service)": {
+			path:                  "/books/7/cover",
+			expectedErrorOnDelete: errors.New("error occurred"),
+			expectedStatus:        http.StatusInternalServerError,
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			coverServiceMock := new(coverservicemock.CoverServiceMock)
+			coverServiceMock.On("DeleteCover", 7).Return(tc.expectedErrorOnDelete)
+
+			coverControllerTest := coverController{coverService: coverServiceMock}
+
+			request, _ := http.NewRequest("DELETE", tc.path, nil)
+			recorder := httptest.NewRecorder()
+			e := echo.New()
+			e.DELETE("/books/:id/cover", coverControllerTest.DeleteCover)
+			e.ServeHTTP(recorder, request)
+
+			require.Equal(t, tc.expectedStatus, recorder.Code)
+		})
+	}
+}
@@ -0,0 +1,155 @@
+package controllers
+
+import (
+	"fmt"
+	"github/brunojoenk/golang-test/models/dtos"
+	genreservice "github/brunojoenk/golang-test/services/genre"
+	"github/brunojoenk/golang-test/utils"
+	"net/http"
+	"strconv"
+
+	"github.com/pkg/errors"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+type IGenreController interface {
+	CreateGenre(c echo.Context) error
+	GetGenreTree(c echo.Context) error
+	UpdateGenre(c echo.Context) error
+	DeleteGenre(c echo.Context) error
+}
+
+type genreController struct {
+	genreService genreservice.IGenreService
+}
+
+// NewGenreController Controller Constructor
+func NewGenreController(db *gorm.DB) IGenreController {
+	return &genreController{genreService: genreservice.NewGenreService(db)}
+}
+
+// CreateGenre godoc
+// @Summary Create a genre.
+// @Description Create a genre, as a subgenre when parent_id is given. Names are unique among the subgenres of the same parent.
+// @Tags Genres
+// @Accept json
+// @Produce json
+// @Param request body dtos.GenreRequest true "genre"
+// @Success 201 {object} dtos.GenreResponse
+// @Failure 400 {object} string
+// @Failure 409 {object} string
+// @Failure 500 {object} string
+// @Router /genre [post]
+func (g *genreController) CreateGenre(c echo.Context) error {
+
+	genreRequest := new(dtos.GenreRequest)
+	if err := c.Bind(genreRequest); err != nil {
+		c.Logger().Warn("Error on bind body to create genre: %s", err.Error())
+		return c.JSON(http.StatusBadRequest, fmt.Sprintf("Request body to create a genre is invalid: %s", err.Error()))
+	}
+
+	genre, err := g.genreService.CreateGenre(*genreRequest)
+	if err != nil {
+		return g.errorResponse(c, "create", err)
+	}
+
+	return c.JSON(http.StatusCreated, genre)
+}
+
+// GetGenreTree godoc
+// @Summary Show the genres taxonomy.
+// @Description Show the root genres with their subgenres nested in children.
+// @Tags Genres
+// @Accept */*
+// @Produce json
+// @Success 200 {array} dtos.GenreResponse
+// @Failure 500 {object} string
+// @Router /genres [get]
+func (g *genreController) GetGenreTree(c echo.Context) error {
+
+	genres, err := g.genreService.GetGenreTree()
+	if err != nil {
+		return g.errorResponse(c, "get all", err)
+	}
+
+	return c.JSON(http.StatusOK, genres)
+}
+
+// UpdateGenre godoc
+// @Summary Update a genre.
+// @Description Rename a genre or move it under another parent. A genre can not be moved under one of its subgenres.
+// @Tags Genres
+// @Accept json
+// @Produce json
+// @Param id   path int true "Genre ID"
+// @Param request body dtos.GenreRequest true "genre"
+// @Success 200 {object} dtos.GenreResponse
+// @Failure 400 {object} string
+// @Failure 404 {object} string
+// @Failure 409 {object} string
+// @Failure 500 {object} string
+// @Router /genre/{id} [put]
+func (g *genreController) UpdateGenre(c echo.Context) error {
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Logger().Warn("Error on parse parameters id on update genre %s", err.Error())
+		return c.JSON(http.StatusBadRequest, "Invalid query parameter id")
+	}
+
+	genreRequest := new(dtos.GenreRequest)
+	if err := c.Bind(genreRequest); err != nil {
+		c.Logger().Warn("Error on parse body on update genre %s", err.Error())
+		return c.JSON(http.StatusBadRequest, fmt.Sprintf("Error on parse body to update genre: %s", err.Error()))
+	}
+
+	genre, err := g.genreService.UpdateGenre(id, *genreRequest)
+	if err != nil {
+		return g.errorResponse(c, "update", err)
+	}
+
+	return c.JSON(http.StatusOK, genre)
+}
+
+// DeleteGenre godoc
+// @Summary Delete a genre.
+// @Description Delete a genre without subgenres. Its books are kept and lose the genre.
+// @Tags Genres
+// @Accept */*
+// @Produce json
+// @Param id   path int true "Genre ID"
+// @Success 204 {object} string
+// @Failure 400 {object} string
+// @Failure 404 {object} string
+// @Failure 409 {object} string
+// @Failure 500 {object} string
+// @Router /genre/{id} [delete]
+func (g *genreController) DeleteGenre(c echo.Context) error {
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Logger().Warn("Error on parse parameters id on delete genre %s", err.Error())
+		return c.JSON(http.StatusBadRequest, "Invalid query parameter id")
+	}
+
+	if err := g.genreService.DeleteGenre(id); err != nil {
+		return g.errorResponse(c, "delete", err)
+	}
+
+	return c.JSON(http.StatusNoContent, "Deleted")
+}
+
+func (g *genreController) errorResponse(c echo.Context, action string, err error) error {
+	switch {
+	case errors.Is(err, utils.ErrGenreNameEmpty), errors.Is(err, utils.ErrGenreParentIdNotFound), errors.Is(err, utils.ErrGenreCycle):
+		return c.JSON(http.StatusBadRequest, err.Error())
+	case errors.Is(err, utils.ErrGenreIdNotFound):
+		return c.JSON(http.StatusNotFound, err.Error())
+	case errors.Is(err, utils.ErrGenreNameExists), errors.Is(err, utils.ErrGenreHasChildren):
+		return c.JSON(http.StatusConflict, err.Error())
+	}
+	c.Logger().Error("Error on %s genre %s", action, err.Error())
+	return c.JSON(http.StatusInternalServerError, fmt.Sprintf("Error on %s genre. Please contact system admin", action))
+}
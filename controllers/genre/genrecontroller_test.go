@@ -0,0 +1,193 @@
+package controllers
+
+import (
+	"errors"
+	"github/brunojoenk/golang-test/models/dtos"
+	"github/brunojoenk/golang-test/utils"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	genreservicemock "github/brunojoenk/golang-test/services/genre/mock"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateGenre(t *testing.T) {
+	tests := map[string]struct {
+		body                  string
+		expectedErrorOnCreate error
+		expectedStatus        int
+	}{
+		"success on create genre": {
+			body:           `{"name":"Fantasy","parent_id":1}`,
+			expectedStatus: http.StatusCreated,
+		},
+		"error on create genre (body)": {
+			body:           `{"name":5}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		"error on create genre (empty name)": {
+			body:                  `{"name":"Fantasy","parent_id":1}`,
+			expectedErrorOnCreate: utils.ErrGenreNameEmpty,
+			expectedStatus:        http.StatusBadRequest,
+		},
+		"error on create genre (parent not found)": {
+			body:                  `{"name":"Fantasy","parent_id":1}`,
+			expectedErrorOnCreate: utils.ErrGenreParentIdNotFound,
+			expectedStatus:        http.StatusBadRequest,
+		},
+		"error on create genre (name exists)": {
+			body:                  `{"name":"Fantasy","parent_id":1}`,
+			expectedErrorOnCreate: utils.ErrGenreNameExists,
+			expectedStatus:        http.StatusConflict,
+		},
+		"error on create genre (service)": {
+			body:                  `{"name":"Fantasy","parent_id":1}`,
+			expectedErrorOnCreate: errors.New("error occurred"),
+			expectedStatus:        http.StatusInternalServerError,
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			genreServiceMock := new(genreservicemock.GenreServiceMock)
+			genreServiceMock.On("CreateGenre", dtos.GenreRequest{Name: "Fantasy", ParentId: 1}).Return(dtos.GenreResponse{Id: 2}, tc.expectedErrorOnCreate)
+
+			genreControllerTest := genreController{genreService: genreServiceMock}
+
+			request, _ := http.NewRequest("POST", "/genre", strings.NewReader(tc.body))
+			request.Header.Add("Content-type", "application/json")
+			recorder := httptest.NewRecorder()
+			e := echo.New()
+			e.POST("/genre", genreControllerTest.CreateGenre)
+			e.ServeHTTP(recorder, request)
+
+			require.Equal(t, tc.expectedStatus, recorder.Code)
+		})
+	}
+}
+
+func TestGetGenreTree(t *testing.T) {
+	tests := map[string]struct {
+		expectedErrorOnGet error
+		expectedStatus     int
+	}{
+		"success on get genre tree": {
+			expectedStatus: http.StatusOK,
+		},
+		"error on get genre tree (service)": {
+			expectedErrorOnGet: errors.New("error occurred"),
+			expectedStatus:     http.StatusInternalServerError,
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			tree := []dtos.GenreResponse{{Id: 1, Name: "Fiction", Children: []dtos.GenreResponse{{Id: 2, Name: "Fantasy", ParentId: 1}}}}
+			genreServiceMock := new(genreservicemock.GenreServiceMock)
+			genreServiceMock.On("GetGenreTree").Return(tree, tc.expectedErrorOnGet)
+
+			genreControllerTest := genreController{genreService: genreServiceMock}
+
+			request, _ := http.NewRequest("GET", "/genres", nil)
+			recorder := httptest.NewRecorder()
+			e := echo.New()
+			e.GET("/genres", genreControllerTest.GetGenreTree)
+			e.ServeHTTP(recorder, request)
+
+			require.Equal(t, tc.expectedStatus, recorder.Code)
+			if tc.expectedErrorOnGet == nil {
+				require.Contains(t, recorder.Body.String(), `"children":[{"id":2,"name":"Fantasy","parent_id":1}]`)
+			}
+		})
+	}
+}
+
+func TestUpdateGenre(t *testing.T) {
+	tests := map[string]struct {
+		id                    string
+		expectedErrorOnUpdate error
+		expectedStatus        int
+	}{
+		"success on update genre": {
+			id:             "2",
+			expectedStatus: http.StatusOK,
+		},
+		"error on update genre (invalid id)": {
+			id:             "a",
+			expectedStatus: http.StatusBadRequest,
+		},
+		"error on update genre (cycle)": {
+			id:                    "2",
+			expectedErrorOnUpdate: utils.ErrGenreCycle,
+			expectedStatus:        http.StatusBadRequest,
+		},
+		"error on update genre (not found)": {
+			id:                    "2",
+			expectedErrorOnUpdate: utils.ErrGenreIdNotFound,
+			expectedStatus:        http.StatusNotFound,
+		},
+		"error on update genre (name exists)": {
+			id:                    "2",
+			expectedErrorOnUpdate: utils.ErrGenreNameExists,
+			expectedStatus:        http.StatusConflict,
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			genreServiceMock := new(genreservicemock.GenreServiceMock)
+			genreServiceMock.On("UpdateGenre", 2, dtos.GenreRequest{Name: "Fantasy", ParentId: 1}).Return(dtos.GenreResponse{Id: 2}, tc.expectedErrorOnUpdate)
+
+			genreControllerTest := genreController{genreService: genreServiceMock}
+
+			request, _ := http.NewRequest("PUT", "/genre/"+tc.id, strings.NewReader(`{"name":"Fantasy","parent_id":1}`))
+			request.Header.Add("Content-type", "application/json")
+			recorder := httptest.NewRecorder()
+			e := echo.New()
+			e.PUT("/genre/:id", genreControllerTest.UpdateGenre)
+			e.ServeHTTP(recorder, request)
+
+			require.Equal(t, tc.expectedStatus, recorder.Code)
+		})
+	}
+}
+
+func TestDeleteGenre(t *testing.T) {
+	tests := map[string]struct {
+		expectedErrorOnDelete error
+		expectedStatus        int
+	}{
+		"success on delete genre": {
+			expectedStatus: http.StatusNoContent,
+		},
+		"error on delete genre (not found)": {
+			expectedErrorOnDelete: utils.ErrGenreIdNotFound,
+			expectedStatus:        http.StatusNotFound,
+		},
+		"error on delete genre (has subgenres)": {
+			expectedErrorOnDelete: utils.ErrGenreHasChildren,
+			expectedStatus:        http.StatusConflict,
+		},
+		"error on delete genre (service)": {
+			expectedErrorOnDelete: errors.New("error occurred"),
+			expectedStatus:        http.StatusInternalServerError,
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			genreServiceMock := new(genreservicemock.GenreServiceMock)
+			genreServiceMock.On("DeleteGenre", 2).Return(tc.expectedErrorOnDelete)
+
+			genreControllerTest := genreController{genreService: genreServiceMock}
+
+			request, _ := http.NewRequest("DELETE", "/genre/2", nil)
+			recorder := httptest.NewRecorder()
+			e := echo.New()
+			e.DELETE("/genre/:id", genreControllerTest.DeleteGenre)
+			e.ServeHTTP(recorder, request)
+
+			require.Equal(t, tc.expectedStatus, recorder.Code)
+		})
+	}
+}
@@ -0,0 +1,164 @@
+package controllers
+
+import (
+	"fmt"
+	"github/brunojoenk/golang-test/config"
+	"github/brunojoenk/golang-test/models/dtos"
+	holdservice "github/brunojoenk/golang-test/services/hold"
+	"github/brunojoenk/golang-test/utils"
+	"net/http"
+	"strconv"
+
+	"github.com/pkg/errors"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+type IHoldController interface {
+	PlaceHold(c echo.Context) error
+	GetBookHolds(c echo.Context) error
+	GetHold(c echo.Context) error
+	CancelHold(c echo.Context) error
+}
+
+type holdController struct {
+	holdService holdservice.IHoldService
+}
+
+// NewHoldController Controller Constructor
+func NewHoldController(db *gorm.DB, cfg *config.Config) IHoldController {
+	return &holdController{holdService: holdservice.NewHoldService(db, cfg)}
+}
+
+// PlaceHold godoc
+// @Summary Place a hold on a book.
+// @Description Put a patron in line for the next copy of a book. When a copy is free already the hold is ready right away, with the copy put aside until the pickup expires.
+// @Tags Holds
+// @Accept json
+// @Produce json
+// @Param id   path int true "Book ID"
+// @Param request body dtos.HoldRequest true "hold"
+// @Success 201 {object} dtos.HoldResponse
+// @Failure 400 {object} string
+// @Failure 404 {object} string
+// @Failure 409 {object} string
+// @Failure 500 {object} string
+// @Router /books/{id}/holds [post]
+func (h *holdController) PlaceHold(c echo.Context) error {
+
+	bookId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Logger().Warn("Error on parse parameters id on place hold %s", err.Error())
+		return c.JSON(http.StatusBadRequest, "Invalid query parameter id")
+	}
+
+	holdRequest := new(dtos.HoldRequest)
+	if err := c.Bind(holdRequest); err != nil {
+		c.Logger().Warn("Error on bind body to place hold: %s", err.Error())
+		return c.JSON(http.StatusBadRequest, fmt.Sprintf("Request body to place a hold is invalid: %s", err.Error()))
+	}
+
+	hold, err := h.holdService.PlaceHold(bookId, *holdRequest)
+	if err != nil {
+		return h.errorResponse(c, "place", err)
+	}
+
+	return c.JSON(http.StatusCreated, hold)
+}
+
+// GetBookHolds godoc
+// @Summary Show the hold queue of a book.
+// @Description Show the open holds of a book in the order they were placed: the ready ones and the waiting ones with their position in line.
+// @Tags Holds
+// @Accept */*
+// @Produce json
+// @Param id   path int true "Book ID"
+// @Success 200 {object} dtos.HoldsResponse
+// @Failure 400 {object} string
+// @Failure 404 {object} string
+// @Failure 500 {object} string
+// @Router /books/{id}/holds [get]
+func (h *holdController) GetBookHolds(c echo.Context) error {
+
+	bookId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Logger().Warn("Error on parse parameters id on get book holds %s", err.Error())
+		return c.JSON(http.StatusBadRequest, "Invalid query parameter id")
+	}
+
+	holdsResponse, err := h.holdService.GetBookHolds(bookId)
+	if err != nil {
+		return h.errorResponse(c, "get holds of", err)
+	}
+
+	return c.JSON(http.StatusOK, holdsResponse)
+}
+
+// GetHold godoc
+// @Summary Get a hold.
+// @Description Get a hold with its position in line.
+// @Tags Holds
+// @Accept */*
+// @Produce json
+// @Param id   path int true "Hold ID"
+// @Success 200 {object} dtos.HoldResponse
+// @Failure 400 {object} string
+// @Failure 404 {object} string
+// @Failure 500 {object} string
+// @Router /holds/{id} [get]
+func (h *holdController) GetHold(c echo.Context) error {
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Logger().Warn("Error on parse parameters id on get hold %s", err.Error())
+		return c.JSON(http.StatusBadRequest, "Invalid query parameter id")
+	}
+
+	hold, err := h.holdService.GetHold(id)
+	if err != nil {
+		return h.errorResponse(c, "get", err)
+	}
+
+	return c.JSON(http.StatusOK, hold)
+}
+
+// CancelHold godoc
+// @Summary Cancel a hold.
+// @Description Take a hold out of the queue. A copy put aside for it goes to the next in line.
+// @Tags Holds
+// @Accept */*
+// @Produce json
+// @Param id   path int true "Hold ID"
+// @Success 200 {object} dtos.HoldResponse
+// @Failure 400 {object} string
+// @Failure 404 {object} string
+// @Failure 409 {object} string
+// @Failure 500 {object} string
+// @Router /holds/{id}/cancel [post]
+func (h *holdController) CancelHold(c echo.Context) error {
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Logger().Warn("Error on parse parameters id on cancel hold %s", err.Error())
+		return c.JSON(http.StatusBadRequest, "Invalid query parameter id")
+	}
+
+	hold, err := h.holdService.CancelHold(id)
+	if err != nil {
+		return h.errorResponse(c, "cancel", err)
+	}
+
+	return c.JSON(http.StatusOK, hold)
+}
+
+func (h *holdController) errorResponse(c echo.Context, action string, err error) error {
+	switch {
+	case errors.Is(err, utils.ErrBookIdNotFound), errors.Is(err, utils.ErrPatronIdNotFound), errors.Is(err, utils.ErrHoldIdNotFound):
+		return c.JSON(http.StatusNotFound, err.Error())
+	case errors.Is(err, utils.ErrHoldExists), errors.Is(err, utils.ErrHoldClosed):
+		return c.JSON(http.StatusConflict, err.Error())
+	}
+	c.Logger().Error("Error on %s hold %s", action, err.Error())
+	return c.JSON(http.StatusInternalServerError, fmt.Sprintf("Error on %s hold. Please contact system admin", action))
+}
@@ -0,0 +1,155 @@
+package controllers
+
+import (
+	"errors"
+	"github/brunojoenk/golang-test/models/dtos"
+	"github/brunojoenk/golang-test/utils"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	holdservicemock "github/brunojoenk/golang-test/services/hold/mock"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlaceHold(t *testing.T) {
+	tests := map[string]struct {
+		id                   string
+		body                 string
+		expectedErrorOnPlace error
+		expectedStatus       int
+	}{
+		"success on place hold": {
+			id:             "1",
+			body:           `{"patron_id":3}`,
+			expectedStatus: http.StatusCreated,
+		},
+		"error on place hold (invalid id)": {
+			id:             "a",
+			body:           `{"patron_id":3}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		"error on place hold (body)": {
+			id:             "1",
+			body:           `{"patron_id":"3"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		"error on place hold (book not found)": {
+			id:                   "1",
+			body:                 `{"patron_id":3}`,
+			expectedErrorOnPlace: utils.ErrBookIdNotFound,
+			expectedStatus:       http.StatusNotFound,
+		},
+		"error on place hold (already holding the book)": {
+			id:                   "1",
+			body:                 `{"patron_id":3}`,
+			expectedErrorOnPlace: utils.ErrHoldExists,
+			expectedStatus:       http.StatusConflict,
+		},
+		"error on place hold (service)": {
+			id:                   "1",
+			body:                 `{"patron_id":3}`,
+			expectedErrorOnPlace: errors.New("error occurred"),
+			expectedStatus:       http.StatusInternalServerError,
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			holdServiceMock := new(holdservicemock.HoldServiceMock)
+			holdServiceMock.On("PlaceHold", 1, dtos.HoldRequest{PatronId: 3}).Return(dtos.HoldResponse{Id: 4}, tc.expectedErrorOnPlace)
+
+			holdControllerTest := holdController{holdService: holdServiceMock}
+
+			request, _ := http.NewRequest("POST", "/books/"+tc.id+"/holds", strings.NewReader(tc.body))
+			request.Header.Add("Content-type", "application/json")
+			recorder := httptest.NewRecorder()
+			e := echo.New()
+			e.POST("/books/:id/holds", holdControllerTest.PlaceHold)
+			e.ServeHTTP(recorder, request)
+
+			require.Equal(t, tc.expectedStatus, recorder.Code)
+		})
+	}
+}
+
+func TestGetBookHolds(t *testing.T) {
+	holdsResponse := dtos.HoldsResponse{Holds: []dtos.HoldResponse{
+		{Id: 4, BookId: 1, PatronId: 3, Status: dtos.HoldStatusWaiting, Position: 1},
+	}}
+	holdServiceMock := new(holdservicemock.HoldServiceMock)
+	holdServiceMock.On("GetBookHolds", 1).Return(holdsResponse, nil)
+	holdServiceMock.On("GetBookHolds", 2).Return(dtos.HoldsResponse{}, utils.ErrBookIdNotFound)
+
+	holdControllerTest := holdController{holdService: holdServiceMock}
+	e := echo.New()
+	e.GET("/books/:id/holds", holdControllerTest.GetBookHolds)
+
+	request, _ := http.NewRequest("GET", "/books/1/holds", nil)
+	recorder := httptest.NewRecorder()
+	e.ServeHTTP(recorder, request)
+
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Contains(t, recorder.Body.String(), `"position":1`)
+
+	request, _ = http.NewRequest("GET", "/books/2/holds", nil)
+	recorder = httptest.NewRecorder()
+	e.ServeHTTP(recorder, request)
+
+	require.Equal(t, http.StatusNotFound, recorder.Code)
+}
+
+func TestGetHold(t *testing.T) {
+	holdServiceMock := new(holdservicemock.HoldServiceMock)
+	holdServiceMock.On("GetHold", 4).Return(dtos.HoldResponse{Id: 4}, nil)
+	holdServiceMock.On("GetHold", 5).Return(dtos.HoldResponse{}, utils.ErrHoldIdNotFound)
+
+	holdControllerTest := holdController{holdService: holdServiceMock}
+	e := echo.New()
+	e.GET("/holds/:id", holdControllerTest.GetHold)
+
+	for id, expectedStatus := range map[string]int{"4": http.StatusOK, "5": http.StatusNotFound, "a": http.StatusBadRequest} {
+		request, _ := http.NewRequest("GET", "/holds/"+id, nil)
+		recorder := httptest.NewRecorder()
+		e.ServeHTTP(recorder, request)
+
+		require.Equal(t, expectedStatus, recorder.Code)
+	}
+}
+
+func TestCancelHold(t *testing.T) {
+	tests := map[string]struct {
+		expectedErrorOnCancel error
+		expectedStatus        int
+	}{
+		"success on cancel hold": {
+			expectedStatus: http.StatusOK,
+		},
+		"error on cancel hold (not found)": {
+			expectedErrorOnCancel: utils.ErrHoldIdNotFound,
+			expectedStatus:        http.StatusNotFound,
+		},
+		"error on cancel hold (already closed)": {
+			expectedErrorOnCancel: utils.ErrHoldClosed,
+			expectedStatus:        http.StatusConflict,
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			holdServiceMock := new(holdservicemock.HoldServiceMock)
+			holdServiceMock.On("CancelHold", 4).Return(dtos.HoldResponse{Id: 4, Status: dtos.HoldStatusCancelled}, tc.expectedErrorOnCancel)
+
+			holdControllerTest := holdController{holdService: holdServiceMock}
+
+			request, _ := http.NewRequest("POST", "/holds/4/cancel", nil)
+			recorder := httptest.NewRecorder()
+			e := echo.New()
+			e.POST("/holds/:id/cancel", holdControllerTest.CancelHold)
+			e.ServeHTTP(recorder, request)
+
+			require.Equal(t, tc.expectedStatus, recorder.Code)
+		})
+	}
+}
@@ -0,0 +1,139 @@
+package controllers
+
+import (
+	"fmt"
+	"github/brunojoenk/golang-test/config"
+	"github/brunojoenk/golang-test/models/dtos"
+	ledgerservice "github/brunojoenk/golang-test/services/ledger"
+	"github/brunojoenk/golang-test/utils"
+	"net/http"
+	"strconv"
+
+	"github.com/pkg/errors"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+type ILedgerController interface {
+	GetPatronLedger(c echo.Context) error
+	RecordPayment(c echo.Context) error
+	RecordWaiver(c echo.Context) error
+}
+
+type ledgerController struct {
+	ledgerService ledgerservice.ILedgerService
+}
+
+// NewLedgerController Controller Constructor
+func NewLedgerController(db *gorm.DB, cfg *config.Config) ILedgerController {
+	return &ledgerController{ledgerService: ledgerservice.NewLedgerService(db, cfg)}
+}
+
+// GetPatronLedger godoc
+// @Summary Show the balance and ledger of a patron with paginations.
+// @Description Show what a patron owes, in cents, and the fines, payments and waivers that make it, newest first.
+// @Tags Ledger
+// @Accept */*
+// @Produce json
+// @Param id   path int true "Patron ID"
+// @Param   page     query     int     false  "page list"     example(1) minimum(1)
+// @Param   limit     query     int     false  "page size"     example(1) minimum(1) maximum(100)
+// @Success 200 {object} dtos.LedgerResponse
+// @Failure 400 {object} string
+// @Failure 404 {object} string
+// @Failure 500 {object} string
+// @Router /patrons/{id}/ledger [get]
+func (l *ledgerController) GetPatronLedger(c echo.Context) error {
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Logger().Warn("Error on parse parameters id on get patron ledger %s", err.Error())
+		return c.JSON(http.StatusBadRequest, "Invalid query parameter id")
+	}
+
+	var pagination dtos.Pagination
+	if err := c.Bind(&pagination); err != nil {
+		c.Logger().Warn("Error on bind query to get patron ledger: %s", err.Error())
+		return c.JSON(http.StatusBadRequest, fmt.Sprintf("Invalid query parameters: %s", err.Error()))
+	}
+
+	ledgerResponse, err := l.ledgerService.GetPatronLedger(id, pagination)
+	if err != nil {
+		return l.errorResponse(c, "get ledger of", err)
+	}
+
+	return c.JSON(http.StatusOK, ledgerResponse)
+}
+
+// RecordPayment godoc
+// @Summary Record a payment of a patron.
+// @Description Record a payment lowering the balance of a patron. The amount, in cents, can not be greater than the balance.
+// @Tags Ledger
+// @Accept json
+// @Produce json
+// @Param id   path int true "Patron ID"
+// @Param request body dtos.LedgerCreditRequest true "payment"
+// @Success 201 {object} dtos.LedgerEntryResponse
+// @Failure 400 {object} string
+// @Failure 404 {object} string
+// @Failure 409 {object} string
+// @Failure 500 {object} string
+// @Router /patrons/{id}/payments [post]
+func (l *ledgerController) RecordPayment(c echo.Context) error {
+	return l.credit(c, "payment", l.ledgerService.RecordPayment)
+}
+
+// RecordWaiver godoc
+// @Summary Waive fines of a patron.
+// @Description Forgive part or all of the balance of a patron. The amount, in cents, can not be greater than the balance.
+// @Tags Ledger
+// @Accept json
+// @Produce json
+// @Param id   path int true "Patron ID"
+// @Param request body dtos.LedgerCreditRequest true "waiver"
+// @Success 201 {object} dtos.LedgerEntryResponse
+// @Failure 400 {object} string
+// @Failure 404 {object} string
+// @Failure 409 {object} string
+// @Failure 500 {object} string
+// @Router /patrons/{id}/waivers [post]
+func (l *ledgerController) RecordWaiver(c echo.Context) error {
+	return l.credit(c, "waiver", l.ledgerService.RecordWaiver)
+}
+
+func (l *ledgerController) credit(c echo.Context, kind string,
+	record func(patronId int, creditRequest dtos.LedgerCreditRequest) (dtos.LedgerEntryResponse, error)) error {
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Logger().Warn("Error on parse parameters id on record %s %s", kind, err.Error())
+		return c.JSON(http.StatusBadRequest, "Invalid query parameter id")
+	}
+
+	creditRequest := new(dtos.LedgerCreditRequest)
+	if err := c.Bind(creditRequest); err != nil {
+		c.Logger().Warn("Error on bind body to record %s: %s", kind, err.Error())
+		return c.JSON(http.StatusBadRequest, fmt.Sprintf("Request body to record a %s is invalid: %s", kind, err.Error()))
+	}
+
+	entry, err := record(id, *creditRequest)
+	if err != nil {
+		return l.errorResponse(c, "record "+kind+" on", err)
+	}
+
+	return c.JSON(http.StatusCreated, entry)
+}
+
+func (l *ledgerController) errorResponse(c echo.Context, action string, err error) error {
+	switch {
+	case errors.Is(err, utils.ErrInvalidAmount):
+		return c.JSON(http.StatusBadRequest, err.Error())
+	case errors.Is(err, utils.ErrPatronIdNotFound):
+		return c.JSON(http.StatusNotFound, err.Error())
+	case errors.Is(err, utils.ErrAmountExceedsBalance):
+		return c.JSON(http.StatusConflict, err.Error())
+	}
+	c.Logger().Error("Error on %s ledger %s", action, err.Error())
+	return c.JSON(http.StatusInternalServerError, fmt.Sprintf("Error on %s ledger. Please contact system admin", action))
+}
@@ -0,0 +1,148 @@
+package controllers
+
+import (
+	"errors"
+	"github/brunojoenk/golang-test/models/dtos"
+	"github/brunojoenk/golang-test/utils"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	ledgerservicemock "github/brunojoenk/golang-test/services/ledger/mock"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetPatronLedger(t *testing.T) {
+	tests := map[string]struct {
+		id                 string
+		query              string
+		pagination         dtos.Pagination
+		expectedErrorOnGet error
+		expectedStatus     int
+	}{
+		"success on get patron ledger": {
+			id:             "3",
+			query:          "?page=2&limit=5",
+			pagination:     dtos.Pagination{Page: 2, Limit: 5},
+			expectedStatus: http.StatusOK,
+		},
+		"error on get patron ledger (invalid id)": {
+			id:             "a",
+			expectedStatus: http.StatusBadRequest,
+		},
+		"error on get patron ledger (patron not found)": {
+			id:                 "3",
+			expectedErrorOnGet: utils.ErrPatronIdNotFound,
+			expectedStatus:     http.StatusNotFound,
+		},
+		"error on get patron ledger (service)": {
+			id:                 "3",
+			expectedErrorOnGet: errors.New("error occurred"),
+			expectedStatus:     http.StatusInternalServerError,
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			ledgerServiceMock := new(ledgerservicemock.LedgerServiceMock)
+			ledgerServiceMock.On("GetPatronLedger", 3, tc.pagination).Return(dtos.LedgerResponse{PatronId: 3, BalanceCents: 75}, tc.expectedErrorOnGet)
+
+			ledgerControllerTest := ledgerController{ledgerService: ledgerServiceMock}
+
+			request, _ := http.NewRequest("GET", "/patrons/"+tc.id+"/ledger"+tc.query, nil)
+			recorder := httptest.NewRecorder()
+			e := echo.New()
+			e.GET("/patrons/:id/ledger", ledgerControllerTest.GetPatronLedger)
+			e.ServeHTTP(recorder, request)
+
+			require.Equal(t, tc.expectedStatus, recorder.Code)
+		})
+	}
+}
+
+func TestRecordPayment(t *testing.T) {
+	tests := map[string]struct {
+		id                    string
+		body                  string
+		expectedErrorOnRecord error
+		expectedStatus        int
+	}{
+		"success on record payment": {
+			id:             "3",
+			body:           `{"amount_cents":50,"note":"cash"}`,
+			expectedStatus: http.StatusCreated,
+		},
+		"error on record payment (invalid id)": {
+			id:             "a",
+			body:           `{"amount_cents":50,"note":"cash"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		"error on record payment (body)": {
+			id:             "3",
+			body:           `{"amount_cents":"50"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		"error on record payment (invalid amount)": {
+			id:                    "3",
+			body:                  `{"amount_cents":50,"note":"cash"}`,
+			expectedErrorOnRecord: utils.ErrInvalidAmount,
+			expectedStatus:        http.StatusBadRequest,
+		},
+		"error on record payment (patron not found)": {
+			id:                    "3",
+			body:                  `{"amount_cents":50,"note":"cash"}`,
+			expectedErrorOnRecord: utils.ErrPatronIdNotFound,
+			expectedStatus:        http.StatusNotFound,
+		},
+		"error on record payment (more than the balance)": {
+			id:                    "3",
+			body:                  `{"amount_cents":50,"note":"cash"}`,
+			expectedErrorOnRecord: utils.ErrAmountExceedsBalance,
+			expectedStatus:        http.StatusConflict,
+		},
+		"error on record payment (service)": {
+			id:                    "3",
+			body:                  `{"amount_cents":50,"note":"cash"}`,
+			expectedErrorOnRecord: errors.New("error occurred"),
+			expectedStatus:        http.StatusInternalServerError,
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			ledgerServiceMock := new(ledgerservicemock.LedgerServiceMock)
+			ledgerServiceMock.On("RecordPayment", 3, dtos.LedgerCreditRequest{AmountCents: 50, Note: "cash"}).
+				Return(dtos.LedgerEntryResponse{Id: 7, Kind: dtos.LedgerKindPayment, AmountCents: -50}, tc.expectedErrorOnRecord)
+
+			ledgerControllerTest := ledgerController{ledgerService: ledgerServiceMock}
+
+			request, _ := http.NewRequest("POST", "/patrons/"+tc.id+"/payments", strings.NewReader(tc.body))
+			request.Header.Add("Content-type", "application/json")
+			recorder := httptest.NewRecorder()
+			e := echo.New()
+			e.POST("/patrons/:id/payments", ledgerControllerTest.RecordPayment)
+			e.ServeHTTP(recorder, request)
+
+			require.Equal(t, tc.expectedStatus, recorder.Code)
+		})
+	}
+}
+
+func TestRecordWaiver(t *testing.T) {
+	ledgerServiceMock := new(ledgerservicemock.LedgerServiceMock)
+	ledgerServiceMock.On("RecordWaiver", 3, dtos.LedgerCreditRequest{AmountCents: 75}).
+		Return(dtos.LedgerEntryResponse{Id: 8, Kind: dtos.LedgerKindWaiver, AmountCents: -75}, nil)
+
+	ledgerControllerTest := ledgerController{ledgerService: ledgerServiceMock}
+
+	request, _ := http.NewRequest("POST", "/patrons/3/waivers", strings.NewReader(`{"amount_cents":75}`))
+	request.Header.Add("Content-type", "application/json")
+	recorder := httptest.NewRecorder()
+	e := echo.New()
+	e.POST("/patrons/:id/waivers", ledgerControllerTest.RecordWaiver)
+	e.ServeHTTP(recorder, request)
+
+	require.Equal(t, http.StatusCreated, recorder.Code)
+	require.Contains(t, recorder.Body.String(), `"kind":"waiver"`)
+}
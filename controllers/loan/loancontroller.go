@@ -0,0 +1,199 @@
+package controllers
+
+import (
+	"fmt"
+	"github/brunojoenk/golang-test/config"
+	"github/brunojoenk/golang-test/models/dtos"
+	loanservice "github/brunojoenk/golang-test/services/loan"
+	"github/brunojoenk/golang-test/utils"
+	"net/http"
+	"strconv"
+
+	"github.com/pkg/errors"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+type ILoanController interface {
+	CheckOut(c echo.Context) error
+	GetLoan(c echo.Context) error
+	CheckIn(c echo.Context) error
+	Renew(c echo.Context) error
+	GetPatronLoans(c echo.Context) error
+}
+
+type loanController struct {
+	loanService loanservice.ILoanService
+}
+
+// NewLoanController Controller Constructor
+func NewLoanController(db *gorm.DB, cfg *config.Config) ILoanController {
+	return &loanController{loanService: loanservice.NewLoanService(db, cfg)}
+}
+
+// CheckOut godoc
+// @Summary Check out a copy to a patron.
+// @Description Check out an available copy, found by its barcode, to a patron, or a copy on hold to the patron it is on hold for. Without due_at the copy is due after the configured loan period.
+// @Tags Loans
+// @Accept json
+// @Produce json
+// @Param request body dtos.CheckOutRequest true "check out"
+// @Success 201 {object} dtos.LoanResponse
+// @Failure 400 {object} string
+// @Failure 404 {object} string
+// @Failure 409 {object} string
+// @Failure 500 {object} string
+// @Router /loans [post]
+func (l *loanController) CheckOut(c echo.Context) error {
+
+	checkOutRequest := new(dtos.CheckOutRequest)
+	if err := c.Bind(checkOutRequest); err != nil {
+		c.Logger().Warn("Error on bind body to check out copy: %s", err.Error())
+		return c.JSON(http.StatusBadRequest, fmt.Sprintf("Request body to check out a copy is invalid: %s", err.Error()))
+	}
+
+	loan, err := l.loanService.CheckOut(*checkOutRequest)
+	if err != nil {
+		return l.errorResponse(c, "check out", err)
+	}
+
+	return c.JSON(http.StatusCreated, loan)
+}
+
+// GetLoan godoc
+// @Summary Get a loan.
+// @Description Get a loan.
+// @Tags Loans
+// @Accept */*
+// @Produce json
+// @Param id   path int true "Loan ID"
+// @Success 200 {object} dtos.LoanResponse
+// @Failure 400 {object} string
+// @Failure 404 {object} string
+// @Failure 500 {object} string
+// @Router /loans/{id} [get]
+func (l *loanController) GetLoan(c echo.Context) error {
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Logger().Warn("Error on parse parameters id on get loan %s", err.Error())
+		return c.JSON(http.StatusBadRequest, "Invalid query parameter id")
+	}
+
+	loan, err := l.loanService.GetLoan(id)
+	if err != nil {
+		return l.errorResponse(c, "get", err)
+	}
+
+	return c.JSON(http.StatusOK, loan)
+}
+
+// CheckIn godoc
+// @Summary Check in a loan.
+// @Description Return the copy of a loan, making it available again.
+// @Tags Loans
+// @Accept */*
+// @Produce json
+// @Param id   path int true "Loan ID"
+// @Success 200 {object} dtos.LoanResponse
+// @Failure 400 {object} string
+// @Failure 404 {object} string
+// @Failure 409 {object} string
+// @Failure 500 {object} string
+// @Router /loans/{id}/checkin [post]
+func (l *loanController) CheckIn(c echo.Context) error {
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Logger().Warn("Error on parse parameters id on check in loan %s", err.Error())
+		return c.JSON(http.StatusBadRequest, "Invalid query parameter id")
+	}
+
+	loan, err := l.loanService.CheckIn(id)
+	if err != nil {
+		return l.errorResponse(c, "check in", err)
+	}
+
+	return c.JSON(http.StatusOK, loan)
+}
+
+// Renew godoc
+// @Summary Renew a loan.
+// @Description Extend the due date of an open loan by the configured loan period, up to the configured renewal limit.
+// @Tags Loans
+// @Accept */*
+// @Produce json
+// @Param id   path int true "Loan ID"
+// @Success 200 {object} dtos.LoanResponse
+// @Failure 400 {object} string
+// @Failure 404 {object} string
+// @Failure 409 {object} string
+// @Failure 500 {object} string
+// @Router /loans/{id}/renew [post]
+func (l *loanController) Renew(c echo.Context) error {
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Logger().Warn("Error on parse parameters id on renew loan %s", err.Error())
+		return c.JSON(http.StatusBadRequest, "Invalid query parameter id")
+	}
+
+	loan, err := l.loanService.Renew(id)
+	if err != nil {
+		return l.errorResponse(c, "renew", err)
+	}
+
+	return c.JSON(http.StatusOK, loan)
+}
+
+// GetPatronLoans godoc
+// @Summary Show the loans of a patron with paginations.
+// @Description Show the loans of a patron ordered by due date. Without status only the open loans are listed.
+// @Tags Loans
+// @Accept */*
+// @Produce json
+// @Param id   path int true "Patron ID"
+// @Param   status     query     string     false  "loan status"     Enums(active, overdue, returned)
+// @Param   page     query     int     false  "page list"     example(1) minimum(1)
+// @Param   limit     query     int     false  "page size"     example(1) minimum(1) maximum(100)
+// @Success 200 {object} dtos.LoanResponseMetadata
+// @Failure 400 {object} string
+// @Failure 404 {object} string
+// @Failure 500 {object} string
+// @Router /patrons/{id}/loans [get]
+func (l *loanController) GetPatronLoans(c echo.Context) error {
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Logger().Warn("Error on parse parameters id on get patron loans %s", err.Error())
+		return c.JSON(http.StatusBadRequest, "Invalid query parameter id")
+	}
+
+	var filter dtos.GetLoansFilter
+	if err := c.Bind(&filter); err != nil {
+		c.Logger().Warn("Error on bind query to get patron loans: %s", err.Error())
+		return c.JSON(http.StatusBadRequest, fmt.Sprintf("Invalid query parameters: %s", err.Error()))
+	}
+
+	loansResponse, err := l.loanService.GetPatronLoans(id, filter)
+	if err != nil {
+		return l.errorResponse(c, "get loans of", err)
+	}
+
+	return c.JSON(http.StatusOK, loansResponse)
+}
+
+func (l *loanController) errorResponse(c echo.Context, action string, err error) error {
+	switch {
+	case errors.Is(err, utils.ErrInvalidDueDate), errors.Is(err, utils.ErrInvalidLoanStatus):
+		return c.JSON(http.StatusBadRequest, err.Error())
+	case errors.Is(err, utils.ErrPatronIdNotFound), errors.Is(err, utils.ErrLoanIdNotFound), errors.Is(err, utils.ErrCopyBarcodeNotFound):
+		return c.JSON(http.StatusNotFound, err.Error())
+	case errors.Is(err, utils.ErrCopyNotAvailable), errors.Is(err, utils.ErrCopyOnHold), errors.Is(err, utils.ErrLoanReturned),
+		errors.Is(err, utils.ErrLoanRenewalLimit), errors.Is(err, utils.ErrLoanChanged):
+		return c.JSON(http.StatusConflict, err.Error())
+	}
+	c.Logger().Error("Error on %s loan %s", action, err.Error())
+	return c.JSON(http.StatusInternalServerError, fmt.Sprintf("Error on %s loan. Please contact system admin", action))
+}
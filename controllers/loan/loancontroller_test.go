@@ -0,0 +1,229 @@
+package controllers
+
+import (
+	"errors"
+	"github/brunojoenk/golang-test/models/dtos"
+	"github/brunojoenk/golang-test/utils"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	loanservicemock "github/brunojoenk/golang-test/services/loan/mock"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckOut(t *testing.T) {
+	tests := map[string]struct {
+		body                    string
+		expectedErrorOnCheckOut error
+		expectedStatus          int
+	}{
+		"success on check out": {
+			body:           `{"barcode":"B-1","patron_id":3}`,
+			expectedStatus: http.StatusCreated,
+		},
+		"error on check out (body)": {
+			body:           `{"barcode":1}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		"error on check out (invalid due date)": {
+			body:                    `{"barcode":"B-1","patron_id":3}`,
+			expectedErrorOnCheckOut: utils.ErrInvalidDueDate,
+			expectedStatus:          http.StatusBadRequest,
+		},
+		"error on check out (barcode not found)": {
+			body:                    `{"barcode":"B-1","patron_id":3}`,
+			expectedErrorOnCheckOut: utils.ErrCopyBarcodeNotFound,
+			expectedStatus:          http.StatusNotFound,
+		},
+		"error on check out (patron not found)": {
+			body:                    `{"barcode":"B-1","patron_id":3}`,
+			expectedErrorOnCheckOut: utils.ErrPatronIdNotFound,
+			expectedStatus:          http.StatusNotFound,
+		},
+		"error on check out (copy not available)": {
+			body:                    `{"barcode":"B-1","patron_id":3}`,
+			expectedErrorOnCheckOut: utils.ErrCopyNotAvailable,
+			expectedStatus:          http.StatusConflict,
+		},
+		"error on check out (copy on hold for another patron)": {
+			body:                    `{"barcode":"B-1","patron_id":3}`,
+			expectedErrorOnCheckOut: utils.ErrCopyOnHold,
+			expectedStatus:          http.StatusConflict,
+		},
+		"error on check out (service)": {
+			body:                    `{"barcode":"B-1","patron_id":3}`,
+			expectedErrorOnCheckOut: errors.New("error occurred"),
+			expectedStatus:          http.StatusInternalServerError,
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			loanServiceMock := new(loanservicemock.LoanServiceMock)
+			loanServiceMock.On("CheckOut", dtos.CheckOutRequest{Barcode: "B-1", PatronId: 3}).Return(dtos.LoanResponse{Id: 7}, tc.expectedErrorOnCheckOut)
+
+			loanControllerTest := loanController{loanService: loanServiceMock}
+
+			request, _ := http.NewRequest("POST", "/loans", strings.NewReader(tc.body))
+			request.Header.Add("Content-type", "application/json")
+			recorder := httptest.NewRecorder()
+			e := echo.New()
+			e.POST("/loans", loanControllerTest.CheckOut)
+			e.ServeHTTP(recorder, request)
+
+			require.Equal(t, tc.expectedStatus, recorder.Code)
+		})
+	}
+}
+
+func TestGetLoan(t *testing.T) {
+	tests := map[string]struct {
+		id                 string
+		expectedErrorOnGet error
+		expectedStatus     int
+	}{
+		"success on get loan": {
+			id:             "7",
+			expectedStatus: http.StatusOK,
+		},
+		"error on get loan (invalid id)": {
+			id:             "a",
+			expectedStatus: http.StatusBadRequest,
+		},
+		"error on get loan (not found)": {
+			id:                 "7",
+			expectedErrorOnGet: utils.ErrLoanIdNotFound,
+			expectedStatus:     http.StatusNotFound,
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			loanServiceMock := new(loanservicemock.LoanServiceMock)
+			loanServiceMock.On("GetLoan", 7).Return(dtos.LoanResponse{Id: 7}, tc.expectedErrorOnGet)
+
+			loanControllerTest := loanController{loanService: loanServiceMock}
+
+			request, _ := http.NewRequest("GET", "/loans/"+tc.id, nil)
+			recorder := httptest.NewRecorder()
+			e := echo.New()
+			e.GET("/loans/:id", loanControllerTest.GetLoan)
+			e.ServeHTTP(recorder, request)
+
+			require.Equal(t, tc.expectedStatus, recorder.Code)
+		})
+	}
+}
+
+func TestCheckIn(t *testing.T) {
+	tests := map[string]struct {
+		expectedErrorOnCheckIn error
+		expectedStatus         int
+	}{
+		"success on check in": {
+			expectedStatus: http.StatusOK,
+		},
+		"error on check in (not found)": {
+			expectedErrorOnCheckIn: utils.ErrLoanIdNotFound,
+			expectedStatus:         http.StatusNotFound,
+		},
+		"error on check in (already returned)": {
+			expectedErrorOnCheckIn: utils.ErrLoanReturned,
+			expectedStatus:         http.StatusConflict,
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			loanServiceMock := new(loanservicemock.LoanServiceMock)
+			loanServiceMock.On("CheckIn", 7).Return(dtos.LoanResponse{Id: 7}, tc.expectedErrorOnCheckIn)
+
+			loanControllerTest := loanController{loanService: loanServiceMock}
+
+			request, _ := http.NewRequest("POST", "/loans/7/checkin", nil)
+			recorder := httptest.NewRecorder()
+			e := echo.New()
+			e.POST("/loans/:id/checkin", loanControllerTest.CheckIn)
+			e.ServeHTTP(recorder, request)
+
+			require.Equal(t, tc.expectedStatus, recorder.Code)
+		})
+	}
+}
+
+func TestRenew(t *testing.T) {
+	tests := map[string]struct {
+		expectedErrorOnRenew error
+		expectedStatus       int
+	}{
+		"success on renew": {
+			expectedStatus: http.StatusOK,
+		},
+		"error on renew (renewal limit)": {
+			expectedErrorOnRenew: utils.ErrLoanRenewalLimit,
+			expectedStatus:       http.StatusConflict,
+		},
+		"error on renew (changed)": {
+			expectedErrorOnRenew: utils.ErrLoanChanged,
+			expectedStatus:       http.StatusConflict,
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			loanServiceMock := new(loanservicemock.LoanServiceMock)
+			loanServiceMock.On("Renew", 7).Return(dtos.LoanResponse{Id: 7}, tc.expectedErrorOnRenew)
+
+			loanControllerTest := loanController{loanService: loanServiceMock}
+
+			request, _ := http.NewRequest("POST", "/loans/7/renew", nil)
+			recorder := httptest.NewRecorder()
+			e := echo.New()
+			e.POST("/loans/:id/renew", loanControllerTest.Renew)
+			e.ServeHTTP(recorder, request)
+
+			require.Equal(t, tc.expectedStatus, recorder.Code)
+		})
+	}
+}
+
+func TestGetPatronLoans(t *testing.T) {
+	tests := map[string]struct {
+		query              string
+		filter             dtos.GetLoansFilter
+		expectedErrorOnGet error
+		expectedStatus     int
+	}{
+		"success on get patron loans": {
+			query:          "?status=overdue&page=2",
+			filter:         dtos.GetLoansFilter{Status: dtos.LoanStatusOverdue, Pagination: dtos.Pagination{Page: 2}},
+			expectedStatus: http.StatusOK,
+		},
+		"error on get patron loans (invalid status)": {
+			query:              "?status=late",
+			filter:             dtos.GetLoansFilter{Status: "late"},
+			expectedErrorOnGet: utils.ErrInvalidLoanStatus,
+			expectedStatus:     http.StatusBadRequest,
+		},
+		"error on get patron loans (patron not found)": {
+			expectedErrorOnGet: utils.ErrPatronIdNotFound,
+			expectedStatus:     http.StatusNotFound,
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			loanServiceMock := new(loanservicemock.LoanServiceMock)
+			loanServiceMock.On("GetPatronLoans", 3, tc.filter).Return(dtos.LoanResponseMetadata{}, tc.expectedErrorOnGet)
+
+			loanControllerTest := loanController{loanService: loanServiceMock}
+
+			request, _ := http.NewRequest("GET", "/patrons/3/loans"+tc.query, nil)
+			recorder := httptest.NewRecorder()
+			e := echo.New()
+			e.GET("/patrons/:id/loans", loanControllerTest.GetPatronLoans)
+			e.ServeHTTP(recorder, request)
+
+			require.Equal(t, tc.expectedStatus, recorder.Code)
+		})
+	}
+}
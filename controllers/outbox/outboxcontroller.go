@@ -0,0 +1,62 @@
+package controllers
+
+import (
+	"fmt"
+	"github/brunojoenk/golang-test/config"
+	"github/brunojoenk/golang-test/models/dtos"
+	notificationservice "github/brunojoenk/golang-test/services/notification"
+	"github/brunojoenk/golang-test/utils"
+	"net/http"
+
+	"github.com/pkg/errors"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+type IOutboxController interface {
+	GetOutbox(c echo.Context) error
+}
+
+type outboxController struct {
+	notificationService notificationservice.INotificationService
+}
+
+// NewOutboxController Controller Constructor. The outbox is only listed here, the scheduler
+// delivers it
+func NewOutboxController(db *gorm.DB, cfg *config.Config) IOutboxController {
+	return &outboxController{notificationService: notificationservice.NewNotificationService(db, cfg, nil)}
+}
+
+// GetOutbox godoc
+// @Summary Show the notification outbox with paginations.
+// @Description Show the reminders and notices queued for patrons, newest first, with their delivery attempts and last error.
+// @Tags Outbox
+// @Accept */*
+// @Produce json
+// @Param   status     query     string     false  "message status"     Enums(pending, sent, failed)
+// @Param   page     query     int     false  "page list"     example(1) minimum(1)
+// @Param   limit     query     int     false  "page size"     example(1) minimum(1) maximum(100)
+// @Success 200 {object} dtos.OutboxResponseMetadata
+// @Failure 400 {object} string
+// @Failure 500 {object} string
+// @Router /outbox [get]
+func (o *outboxController) GetOutbox(c echo.Context) error {
+
+	var filter dtos.GetOutboxFilter
+	if err := c.Bind(&filter); err != nil {
+		c.Logger().Warn("Error on bind query to get outbox: %s", err.Error())
+		return c.JSON(http.StatusBadRequest, fmt.Sprintf("Invalid query parameters: %s", err.Error()))
+	}
+
+	outboxResponse, err := o.notificationService.GetOutbox(filter)
+	if err != nil {
+		if errors.Is(err, utils.ErrInvalidOutboxStatus) {
+			return c.JSON(http.StatusBadRequest, err.Error())
+		}
+		c.Logger().Error("Error on get outbox %s", err.Error())
+		return c.JSON(http.StatusInternalServerError, "Error on get outbox. Please contact system admin")
+	}
+
+	return c.JSON(http.StatusOK, outboxResponse)
+}
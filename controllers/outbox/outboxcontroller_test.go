@@ -0,0 +1,60 @@
+package controllers
+
+import (
+	"errors"
+	"github/brunojoenk/golang-test/models/dtos"
+	"github/brunojoenk/golang-test/utils"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	notificationservicemock "github/brunojoenk/golang-test/services/notification/mock"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetOutbox(t *testing.T) {
+	tests := map[string]struct {
+		query              string
+		filter             dtos.GetOutboxFilter
+		expectedErrorOnGet error
+		expectedStatus     int
+	}{
+		"success on get outbox": {
+			query:          "?status=failed&page=2",
+			filter:         dtos.GetOutboxFilter{Status: dtos.OutboxStatusFailed, Pagination: dtos.Pagination{Page: 2}},
+			expectedStatus: http.StatusOK,
+		},
+		"error on get outbox (invalid status)": {
+			query:              "?status=lost",
+			filter:             dtos.GetOutboxFilter{Status: "lost"},
+			expectedErrorOnGet: utils.ErrInvalidOutboxStatus,
+			expectedStatus:     http.StatusBadRequest,
+		},
+		"error on get outbox (invalid page)": {
+			query:          "?page=a",
+			expectedStatus: http.StatusBadRequest,
+		},
+		"error on get outbox (service)": {
+			expectedErrorOnGet: errors.New("error occurred"),
+			expectedStatus:     http.StatusInternalServerError,
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			notificationServiceMock := new(notificationservicemock.NotificationServiceMock)
+			notificationServiceMock.On("GetOutbox", tc.filter).Return(dtos.OutboxResponseMetadata{}, tc.expectedErrorOnGet)
+
+			outboxControllerTest := outboxController{notificationService: notificationServiceMock}
+
+			request, _ := http.NewRequest("GET", "/outbox"+tc.query, nil)
+			recorder := httptest.NewRecorder()
+			e := echo.New()
+			e.GET("/outbox", outboxControllerTest.GetOutbox)
+			e.ServeHTTP(recorder, request)
+
+			require.Equal(t, tc.expectedStatus, recorder.Code)
+		})
+	}
+}
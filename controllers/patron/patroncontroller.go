@@ -0,0 +1,194 @@
+package controllers
+
+import (
+	"fmt"
+	"github/brunojoenk/golang-test/models/dtos"
+	patronservice "github/brunojoenk/golang-test/services/patron"
+	"github/brunojoenk/golang-test/utils"
+	"net/http"
+	"strconv"
+
+	"github.com/pkg/errors"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+type IPatronController interface {
+	CreatePatron(c echo.Context) error
+	GetAllPatrons(c echo.Context) error
+	GetPatron(c echo.Context) error
+	UpdatePatron(c echo.Context) error
+	DeletePatron(c echo.Context) error
+}
+
+type patronController struct {
+	patronService patronservice.IPatronService
+}
+
+// NewPatronController Controller Constructor
+func NewPatronController(db *gorm.DB) IPatronController {
+	return &patronController{patronService: patronservice.NewPatronService(db)}
+}
+
+// CreatePatron godoc
+// @Summary Create a patron.
+// @Description Create a patron. Emails are unique ignoring case.
+// @Tags Patrons
+// @Accept json
+// @Produce json
+// @Param request body dtos.PatronRequest true "patron"
+// @Success 201 {object} dtos.PatronResponse
+// @Failure 400 {object} string
+// @Failure 409 {object} string
+// @Failure 500 {object} string
+// @Router /patrons [post]
+func (p *patronController) CreatePatron(c echo.Context) error {
+
+	patronRequest := new(dtos.PatronRequest)
+	if err := c.Bind(patronRequest); err != nil {
+		c.Logger().Warn("Error on bind body to create patron: %s", err.Error())
+		return c.JSON(http.StatusBadRequest, fmt.Sprintf("Request body to create a patron is invalid: %s", err.Error()))
+	}
+
+	patron, err := p.patronService.CreatePatron(*patronRequest)
+	if err != nil {
+		return p.errorResponse(c, "create", err)
+	}
+
+	return c.JSON(http.StatusCreated, patron)
+}
+
+// GetAllPatrons godoc
+// @Summary Show all the patrons with paginations.
+// @Description Show all the patrons with paginations.
+// @Tags Patrons
+// @Accept */*
+// @Produce json
+// @Param   name     query     string     false  "search patrons by name"     example(string)
+// @Param   page     query     int     false  "page list"     example(1) minimum(1)
+// @Param   limit     query     int     false  "page size"     example(1) minimum(1) maximum(100)
+// @Success 200 {object} dtos.PatronResponseMetadata
+// @Failure 400 {object} string
+// @Failure 500 {object} string
+// @Router /patrons [get]
+func (p *patronController) GetAllPatrons(c echo.Context) error {
+	var filter dtos.GetPatronsFilter
+	err := c.Bind(&filter)
+	if err != nil {
+		c.Logger().Warn("Error on bind query to filter all patrons: %s", err.Error())
+		return c.JSON(http.StatusBadRequest, fmt.Sprintf("Invalid query parameters: %s", err.Error()))
+	}
+
+	patronsResponse, err := p.patronService.GetAllPatrons(filter)
+	if err != nil {
+		return p.errorResponse(c, "get all", err)
+	}
+
+	return c.JSON(http.StatusOK, patronsResponse)
+}
+
+// GetPatron godoc
+// @Summary Get a patron.
+// @Description Get a patron.
+// @Tags Patrons
+// @Accept */*
+// @Produce json
+// @Param id   path int true "Patron ID"
+// @Success 200 {object} dtos.PatronResponse
+// @Failure 400 {object} string
+// @Failure 404 {object} string
+// @Failure 500 {object} string
+// @Router /patrons/{id} [get]
+func (p *patronController) GetPatron(c echo.Context) error {
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Logger().Warn("Error on parse parameters id on get patron %s", err.Error())
+		return c.JSON(http.StatusBadRequest, "Invalid query parameter id")
+	}
+
+	patron, err := p.patronService.GetPatron(id)
+	if err != nil {
+		return p.errorResponse(c, "get", err)
+	}
+
+	return c.JSON(http.StatusOK, patron)
+}
+
+// UpdatePatron godoc
+// @Summary Update a patron.
+// @Description Update a patron.
+// @Tags Patrons
+// @Accept json
+// @Produce json
+// @Param id   path int true "Patron ID"
+// @Param request body dtos.PatronRequest true "patron"
+// @Success 200 {object} dtos.PatronResponse
+// @Failure 400 {object} string
+// @Failure 404 {object} string
+// @Failure 409 {object} string
+// @Failure 500 {object} string
+// @Router /patrons/{id} [put]
+func (p *patronController) UpdatePatron(c echo.Context) error {
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Logger().Warn("Error on parse parameters id on update patron %s", err.Error())
+		return c.JSON(http.StatusBadRequest, "Invalid query parameter id")
+	}
+
+	patronRequest := new(dtos.PatronRequest)
+	if err := c.Bind(patronRequest); err != nil {
+		c.Logger().Warn("Error on parse body on update patron %s", err.Error())
+		return c.JSON(http.StatusBadRequest, fmt.Sprintf("Error on parse body to update patron: %s", err.Error()))
+	}
+
+	patron, err := p.patronService.UpdatePatron(id, *patronRequest)
+	if err != nil {
+		return p.errorResponse(c, "update", err)
+	}
+
+	return c.JSON(http.StatusOK, patron)
+}
+
+// DeletePatron godoc
+// @Summary Delete a patron.
+// @Description Delete a patron without copies on loan, along with the loans history of the patron.
+// @Tags Patrons
+// @Accept */*
+// @Produce json
+// @Param id   path int true "Patron ID"
+// @Success 204 {object} string
+// @Failure 400 {object} string
+// @Failure 404 {object} string
+// @Failure 409 {object} string
+// @Failure 500 {object} string
+// @Router /patrons/{id} [delete]
+func (p *patronController) DeletePatron(c echo.Context) error {
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Logger().Warn("Error on parse parameters id on delete patron %s", err.Error())
+		return c.JSON(http.StatusBadRequest, "Invalid query parameter id")
+	}
+
+	if err := p.patronService.DeletePatron(id); err != nil {
+		return p.errorResponse(c, "delete", err)
+	}
+
+	return c.JSON(http.StatusNoContent, "Deleted")
+}
+
+func (p *patronController) errorResponse(c echo.Context, action string, err error) error {
+	switch {
+	case errors.Is(err, utils.ErrPatronNameEmpty), errors.Is(err, utils.ErrInvalidEmail):
+		return c.JSON(http.StatusBadRequest, err.Error())
+	case errors.Is(err, utils.ErrPatronIdNotFound):
+		return c.JSON(http.StatusNotFound, err.Error())
+	case errors.Is(err, utils.ErrPatronEmailExists), errors.Is(err, utils.ErrPatronHasLoans):
+		return c.JSON(http.StatusConflict, err.Error())
+	}
+	c.Logger().Error("Error on %s patron %s", action, err.Error())
+	return c.JSON(http.StatusInternalServerError, fmt.Sprintf("Error on %s patron. Please contact system admin", action))
+}
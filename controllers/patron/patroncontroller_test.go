@@ -0,0 +1,174 @@
+package controllers
+
+import (
+	"errors"
+	"github/brunojoenk/golang-test/models/dtos"
+	"github/brunojoenk/golang-test/utils"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	patronservicemock "github/brunojoenk/golang-test/services/patron/mock"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreatePatron(t *testing.T) {
+	tests := map[string]struct {
+		body                  string
+		expectedErrorOnCreate error
+		expectedStatus        int
+	}{
+		"success on create patron": {
+			body:           `{"name":"Ana Lima","email":"ana@example.com"}`,
+			expectedStatus: http.StatusCreated,
+		},
+		"error on create patron (body)": {
+			body:           `{"name":5}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		"error on create patron (invalid email)": {
+			body:                  `{"name":"Ana Lima","email":"ana@example.com"}`,
+			expectedErrorOnCreate: utils.ErrInvalidEmail,
+			expectedStatus:        http.StatusBadRequest,
+		},
+		"error on create patron (email exists)": {
+			body:                  `{"name":"Ana Lima","email":"ana@example.com"}`,
+			expectedErrorOnCreate: utils.ErrPatronEmailExists,
+			expectedStatus:        http.StatusConflict,
+		},
+		"error on create patron (service)": {
+			body:                  `{"name":"Ana Lima","email":"ana@example.com"}`,
+			expectedErrorOnCreate: errors.New("error occurred"),
+			expectedStatus:        http.StatusInternalServerError,
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			patronServiceMock := new(patronservicemock.PatronServiceMock)
+			patronServiceMock.On("CreatePatron", dtos.PatronRequest{Name: "Ana Lima", Email: "ana@example.com"}).Return(dtos.PatronResponse{Id: 3}, tc.expectedErrorOnCreate)
+
+			patronControllerTest := patronController{patronService: patronServiceMock}
+
+			request, _ := http.NewRequest("POST", "/patrons", strings.NewReader(tc.body))
+			request.Header.Add("Content-type", "application/json")
+			recorder := httptest.NewRecorder()
+			e := echo.New()
+			e.POST("/patrons", patronControllerTest.CreatePatron)
+			e.ServeHTTP(recorder, request)
+
+			require.Equal(t, tc.expectedStatus, recorder.Code)
+		})
+	}
+}
+
+func TestGetAllPatrons(t *testing.T) {
+	patronsResponse := dtos.PatronResponseMetadata{
+		Patrons:    []dtos.PatronResponse{{Id: 3, Name: "Ana Lima", Email: "ana@example.com"}},
+		Pagination: dtos.Pagination{Page: 1, Limit: 10},
+	}
+	patronServiceMock := new(patronservicemock.PatronServiceMock)
+	patronServiceMock.On("GetAllPatrons", dtos.GetPatronsFilter{Name: "ana"}).Return(patronsResponse, nil)
+
+	patronControllerTest := patronController{patronService: patronServiceMock}
+	e := echo.New()
+	e.GET("/patrons", patronControllerTest.GetAllPatrons)
+
+	request, _ := http.NewRequest("GET", "/patrons?name=ana", nil)
+	recorder := httptest.NewRecorder()
+	e.ServeHTTP(recorder, request)
+
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Contains(t, recorder.Body.String(), `"email":"ana@example.com"`)
+}
+
+func TestGetPatron(t *testing.T) {
+	tests := map[string]struct {
+		id                 string
+		expectedErrorOnGet error
+		expectedStatus     int
+	}{
+		"success on get patron": {
+			id:             "3",
+			expectedStatus: http.StatusOK,
+		},
+		"error on get patron (invalid id)": {
+			id:             "a",
+			expectedStatus: http.StatusBadRequest,
+		},
+		"error on get patron (not found)": {
+			id:                 "3",
+			expectedErrorOnGet: utils.ErrPatronIdNotFound,
+			expectedStatus:     http.StatusNotFound,
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			patronServiceMock := new(patronservicemock.PatronServiceMock)
+			patronServiceMock.On("GetPatron", 3).Return(dtos.PatronResponse{Id: 3}, tc.expectedErrorOnGet)
+
+			patronControllerTest := patronController{patronService: patronServiceMock}
+
+			request, _ := http.NewRequest("GET", "/patrons/"+tc.id, nil)
+			recorder := httptest.NewRecorder()
+			e := echo.New()
+			e.GET("/patrons/:id", patronControllerTest.GetPatron)
+			e.ServeHTTP(recorder, request)
+
+			require.Equal(t, tc.expectedStatus, recorder.Code)
+		})
+	}
+}
+
+func TestUpdatePatron(t *testing.T) {
+	patronServiceMock := new(patronservicemock.PatronServiceMock)
+	patronServiceMock.On("UpdatePatron", 3, dtos.PatronRequest{Name: "Ana Lima", Email: "ana@example.com"}).Return(dtos.PatronResponse{Id: 3}, nil)
+
+	patronControllerTest := patronController{patronService: patronServiceMock}
+
+	request, _ := http.NewRequest("PUT", "/patrons/3", strings.NewReader(`{"name":"Ana Lima","email":"ana@example.com"}`))
+	request.Header.Add("Content-type", "application/json")
+	recorder := httptest.NewRecorder()
+	e := echo.New()
+	e.PUT("/patrons/:id", patronControllerTest.UpdatePatron)
+	e.ServeHTTP(recorder, request)
+
+	require.Equal(t, http.StatusOK, recorder.Code)
+}
+
+func TestDeletePatron(t *testing.T) {
+	tests := map[string]struct {
+		expectedErrorOnDelete error
+		expectedStatus        int
+	}{
+		"success on delete patron": {
+			expectedStatus: http.StatusNoContent,
+		},
+		"error on delete patron (not found)": {
+			expectedErrorOnDelete: utils.ErrPatronIdNotFound,
+			expectedStatus:        http.StatusNotFound,
+		},
+		"error on delete patron (copies on loan)": {
+			expectedErrorOnDelete: utils.ErrPatronHasLoans,
+			expectedStatus:        http.StatusConflict,
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			patronServiceMock := new(patronservicemock.PatronServiceMock)
+			patronServiceMock.On("DeletePatron", 3).Return(tc.expectedErrorOnDelete)
+
+			patronControllerTest := patronController{patronService: patronServiceMock}
+
+			request, _ := http.NewRequest("DELETE", "/patrons/3", nil)
+			recorder := httptest.NewRecorder()
+			e := echo.New()
+			e.DELETE("/patrons/:id", patronControllerTest.DeletePatron)
+			e.ServeHTTP(recorder, request)
+
+			require.Equal(t, tc.expectedStatus, recorder.Code)
+		})
+	}
+}
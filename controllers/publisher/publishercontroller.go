@@ -0,0 +1,234 @@
+package controllers
+
+import (
+	"fmt"
+	"github/brunojoenk/golang-test/models/dtos"
+	publisherservice "github/brunojoenk/golang-test/services/publisher"
+	"github/brunojoenk/golang-test/utils"
+	"net/http"
+	"strconv"
+
+	"github.com/pkg/errors"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+type IPublisherController interface {
+	CreatePublisher(c echo.Context) error
+	GetAllPublishers(c echo.Context) error
+	GetPublisher(c echo.Context) error
+	UpdatePublisher(c echo.Context) error
+	DeletePublisher(c echo.Context) error
+	GetPublisherBooks(c echo.Context) error
+}
+
+type publisherController struct {
+	publisherService publisherservice.IPublisherService
+}
+
+// NewPublisherController Controller Constructor
+func NewPublisherController(db *gorm.DB) IPublisherController {
+	return &publisherController{publisherService: publisherservice.NewPublisherService(db)}
+}
+
+// CreatePublisher godoc
+// @Summary Create a publisher.
+// @Description Create a publisher. Names are unique ignoring case, accents and punctuation.
+// @Tags Publishers
+// @Accept json
+// @Produce json
+// @Param request body dtos.PublisherRequest true "publisher"
+// @Success 201 {object} dtos.PublisherResponse
+// @Failure 400 {object} string
+// @Failure 409 {object} string
+// @Failure 500 {object} string
+// @Router /publisher [post]
+func (p *publisherController) CreatePublisher(c echo.Context) error {
+
+	publisherRequest := new(dtos.PublisherRequest)
+	if err := c.Bind(publisherRequest); err != nil {
+		c.Logger().Warn("Error on bind body to create publisher: %s", err.Error())
+		return c.JSON(http.StatusBadRequest, fmt.Sprintf("Request body to create a publisher is invalid: %s", err.Error()))
+	}
+
+	publisher, err := p.publisherService.CreatePublisher(*publisherRequest)
+	if err != nil {
+		return p.errorResponse(c, "create", err)
+	}
+
+	return c.JSON(http.StatusCreated, publisher)
+}
+
+// GetAllPublishers godoc
+// @Summary Show all the publishers with paginations.
+// @Description Show all the publishers with paginations.
+// @Tags Publishers
+// @Accept */*
+// @Produce json
+// @Param   name     query     string     false  "search publisher by name"     example(string)
+// @Param   country     query     string     false  "search publisher by country (ISO 3166-1 alpha-2)"     example(BR)
+// @Param   page     query     int     false  "page list"     example(1) minimum(1)
+// @Param   limit     query     int     false  "page size"     example(1) minimum(1) maximum(100)
+// @Success 200 {object} dtos.PublisherResponseMetadata
+// @Failure 400 {object} string
+// @Failure 500 {object} string
+// @Router /publishers [get]
+func (p *publisherController) GetAllPublishers(c echo.Context) error {
+	var filter dtos.GetPublishersFilter
+	err := c.Bind(&filter)
+	if err != nil {
+		c.Logger().Warn("Error on bind query to filter all publishers: %s", err.Error())
+		return c.JSON(http.StatusBadRequest, fmt.Sprintf("Invalid query parameters: %s", err.Error()))
+	}
+
+	publishersResponse, err := p.publisherService.GetAllPublishers(filter)
+	if err != nil {
+		return p.errorResponse(c, "get all", err)
+	}
+
+	return c.JSON(http.StatusOK, publishersResponse)
+}
+
+// GetPublisher godoc
+// @Summary Get a publisher.
+// @Description Get a publisher.
+// @Tags Publishers
+// @Accept */*
+// @Produce json
+// @Param id   path int true "Publisher ID"
+// @Success 200 {object} dtos.PublisherResponse
+// @Failure 400 {object} string
+// @Failure 404 {object} string
+// @Failure 500 {object} string
+// @Router /publisher/{id} [get]
+func (p *publisherController) GetPublisher(c echo.Context) error {
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Logger().Warn("Error on parse parameters id on get publisher %s", err.Error())
+		return c.JSON(http.StatusBadRequest, "Invalid query parameter id")
+	}
+
+	publisher, err := p.publisherService.GetPublisher(id)
+	if err != nil {
+		return p.errorResponse(c, "get", err)
+	}
+
+	return c.JSON(http.StatusOK, publisher)
+}
+
+// UpdatePublisher godoc
+// @Summary Update a publisher.
+// @Description Update a publisher.
+// @Tags Publishers
+// @Accept json
+// @Produce json
+// @Param id   path int true "Publisher ID"
+// @Param request body dtos.PublisherRequest true "publisher"
+// @Success 200 {object} dtos.PublisherResponse
+// @Failure 400 {object} string
+// @Failure 404 {object} string
+// @Failure 409 {object} string
+// @Failure 500 {object} string
+// @Router /publisher/{id} [put]
+func (p *publisherController) UpdatePublisher(c echo.Context) error {
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Logger().Warn("Error on parse parameters id on update publisher %s", err.Error())
+		return c.JSON(http.StatusBadRequest, "Invalid query parameter id")
+	}
+
+	publisherRequest := new(dtos.PublisherRequest)
+	if err := c.Bind(publisherRequest); err != nil {
+		c.Logger().Warn("Error on parse body on update publisher %s", err.Error())
+		return c.JSON(http.StatusBadRequest, fmt.Sprintf("Error on parse body to update publisher: %s", err.Error()))
+	}
+
+	publisher, err := p.publisherService.UpdatePublisher(id, *publisherRequest)
+	if err != nil {
+		return p.errorResponse(c, "update", err)
+	}
+
+	return c.JSON(http.StatusOK, publisher)
+}
+
+// DeletePublisher godoc
+// @Summary Delete a publisher.
+// @Description Delete a publisher without books.
+// @Tags Publishers
+// @Accept */*
+// @Produce json
+// @Param id   path int true "Publisher ID"
+// @Success 204 {object} string
+// @Failure 400 {object} string
+// @Failure 404 {object} string
+// @Failure 409 {object} string
+// @Failure 500 {object} string
+// @Router /publisher/{id} [delete]
+func (p *publisherController) DeletePublisher(c echo.Context) error {
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Logger().Warn("Error on parse parameters id on delete publisher %s", err.Error())
+		return c.JSON(http.StatusBadRequest, "Invalid query parameter id")
+	}
+
+	if err := p.publisherService.DeletePublisher(id); err != nil {
+		return p.errorResponse(c, "delete", err)
+	}
+
+	return c.JSON(http.StatusNoContent, "Deleted")
+}
+
+// GetPublisherBooks godoc
+// @Summary Show the books of a publisher.
+// @Description Show the books of a publisher with paginations.
+// @Tags Publishers
+// @Accept */*
+// @Produce json
+// @Param Accept-Language header string false "preferred languages of the book titles, e.g. pt-BR, en;q=0.8"
+// @Param id   path int true "Publisher ID"
+// @Param   page     query     int     false  "page list"     example(1) minimum(1)
+// @Param   limit     query     int     false  "page size"     example(1) minimum(1) maximum(100)
+// @Success 200 {object} dtos.BookResponseMetadata
+// @Failure 400 {object} string
+// @Failure 404 {object} string
+// @Failure 500 {object} string
+// @Router /publishers/{id}/books [get]
+func (p *publisherController) GetPublisherBooks(c echo.Context) error {
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Logger().Warn("Error on parse parameters id on get publisher books %s", err.Error())
+		return c.JSON(http.StatusBadRequest, "Invalid query parameter id")
+	}
+
+	var pagination dtos.Pagination
+	if err := c.Bind(&pagination); err != nil {
+		c.Logger().Warn("Error on bind query to get publisher books: %s", err.Error())
+		return c.JSON(http.StatusBadRequest, fmt.Sprintf("Invalid query parameters: %s", err.Error()))
+	}
+
+	booksResponse, err := p.publisherService.GetPublisherBooks(id, pagination)
+	if err != nil {
+		return p.errorResponse(c, "get books of", err)
+	}
+
+	booksResponse.Localize(c.Request().Header.Get("Accept-Language"))
+	return c.JSON(http.StatusOK, booksResponse)
+}
+
+func (p *publisherController) errorResponse(c echo.Context, action string, err error) error {
+	switch {
+	case errors.Is(err, utils.ErrPublisherNameEmpty), errors.Is(err, utils.ErrInvalidCountryCode), errors.Is(err, utils.ErrInvalidWebsite):
+		return c.JSON(http.StatusBadRequest, err.Error())
+	case errors.Is(err, utils.ErrPublisherIdNotFound):
+		return c.JSON(http.StatusNotFound, err.Error())
+	case errors.Is(err, utils.ErrPublisherNameExists), errors.Is(err, utils.ErrPublisherHasBooks):
+		return c.JSON(http.StatusConflict, err.Error())
+	}
+	c.Logger().Error("Error on %s publisher %s", action, err.Error())
+	return c.JSON(http.StatusInternalServerError, fmt.Sprintf("Error on %s publisher. Please contact system admin", action))
+}
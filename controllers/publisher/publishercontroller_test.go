@@ -0,0 +1,272 @@
+package controllers
+
+import (
+	"errors"
+	"github/brunojoenk/golang-test/models/dtos"
+	"github/brunojoenk/golang-test/utils"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	publisherservicemock "github/brunojoenk/golang-test/services/publisher/mock"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreatePublisher(t *testing.T) {
+	tests := map[string]struct {
+		body                  string
+		expectedErrorOnCreate error
+		expectedStatus        int
+	}{
+		"success on create publisher": {
+			body:           `{"name":"Rocco","country":"BR"}`,
+			expectedStatus: http.StatusCreated,
+		},
+		"error on create publisher (body)": {
+			body:           `{"name":5}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		"error on create publisher (empty name)": {
+			body:                  `{"name":"Rocco","country":"BR"}`,
+			expectedErrorOnCreate: utils.ErrPublisherNameEmpty,
+			expectedStatus:        http.StatusBadRequest,
+		},
+		"error on create publisher (invalid country)": {
+			body:                  `{"name":"Rocco","country":"BR"}`,
+			expectedErrorOnCreate: utils.ErrInvalidCountryCode,
+			expectedStatus:        http.StatusBadRequest,
+		},
+		"error on create publisher (name exists)": {
+			body:                  `{"name":"Rocco","country":"BR"}`,
+			expectedErrorOnCreate: utils.ErrPublisherNameExists,
+			expectedStatus:        http.StatusConflict,
+		},
+		"error on create publisher (service)": {
+			body:                  `{"name":"Rocco","country":"BR"}`,
+			expectedErrorOnCreate: errors.New("error occurred"),
+			expectedStatus:        http.StatusInternalServerError,
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			publisherServiceMock := new(publisherservicemock.PublisherServiceMock)
+			publisherServiceMock.On("CreatePublisher", dtos.PublisherRequest{Name: "Rocco", Country: "BR"}).Return(dtos.PublisherResponse{Id: 1}, tc.expectedErrorOnCreate)
+
+			publisherControllerTest := publisherController{publisherService: publisherServiceMock}
+
+			request, _ := http.NewRequest("POST", "/publisher", strings.NewReader(tc.body))
+			request.Header.Add("Content-type", "application/json")
+			recorder := httptest.NewRecorder()
+			e := echo.New()
+			e.POST("/publisher", publisherControllerTest.CreatePublisher)
+			e.ServeHTTP(recorder, request)
+
+			require.Equal(t, tc.expectedStatus, recorder.Code)
+		})
+	}
+}
+
+func TestGetAllPublishers(t *testing.T) {
+	publishersResponse := dtos.PublisherResponseMetadata{
+		Publishers: []dtos.PublisherResponse{{Id: 1, Name: "Rocco", Country: "BR"}},
+		Pagination: dtos.Pagination{Page: 1, Limit: 10},
+	}
+	publisherServiceMock := new(publisherservicemock.PublisherServiceMock)
+	publisherServiceMock.On("GetAllPublishers", dtos.GetPublishersFilter{Name: "rocco", Country: "br"}).Return(publishersResponse, nil)
+	publisherServiceMock.On("GetAllPublishers", dtos.GetPublishersFilter{Country: "xx"}).Return(dtos.PublisherResponseMetadata{}, utils.ErrInvalidCountryCode)
+
+	publisherControllerTest := publisherController{publisherService: publisherServiceMock}
+	e := echo.New()
+	e.GET("/publishers", publisherControllerTest.GetAllPublishers)
+
+	request, _ := http.NewRequest("GET", "/publishers?name=rocco&country=br", nil)
+	recorder := httptest.NewRecorder()
+	e.ServeHTTP(recorder, request)
+
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Contains(t, recorder.Body.String(), `"name":"Rocco"`)
+
+	request, _ = http.NewRequest("GET", "/publishers?country=xx", nil)
+	recorder = httptest.NewRecorder()
+	e.ServeHTTP(recorder, request)
+
+	require.Equal(t, http.StatusBadRequest, recorder.Code)
+}
+
+func TestGetPublisher(t *testing.T) {
+	tests := map[string]struct {
+		id                 string
+		expectedErrorOnGet error
+		expectedStatus     int
+	}{
+		"success on get publisher": {
+			id:             "1",
+			expectedStatus: http.StatusOK,
+		},
+		"error on get publisher (invalid id)": {
+			id:             "a",
+			expectedStatus: http.StatusBadRequest,
+		},
+		"error on get publisher (not found)": {
+			id:                 "1",
+			expectedErrorOnGet: utils.ErrPublisherIdNotFound,
+			expectedStatus:     http.StatusNotFound,
+		},
+		"error on get publisher (service)": {
+			id:                 "1",
+			expectedErrorOnGet: errors.New("error occurred"),
+			expectedStatus:     http.StatusInternalServerError,
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			publisherServiceMock := new(publisherservicemock.PublisherServiceMock)
+			publisherServiceMock.On("GetPublisher", 1).Return(dtos.PublisherResponse{Id: 1}, tc.expectedErrorOnGet)
+
+			publisherControllerTest := publisherController{publisherService: publisherServiceMock}
+
+			request, _ := http.NewRequest("GET", "/publisher/"+tc.id, nil)
+			recorder := httptest.NewRecorder()
+			e := echo.New()
+			e.GET("/publisher/:id", publisherControllerTest.GetPublisher)
+			e.ServeHTTP(recorder, request)
+
+			require.Equal(t, tc.expectedStatus, recorder.Code)
+		})
+	}
+}
+
+func TestUpdatePublisher(t *testing.T) {
+	tests := map[string]struct {
+		id                    string
+		expectedErrorOnUpdate error
+		expectedStatus        int
+	}{
+		"success on update publisher": {
+			id:             "1",
+			expectedStatus: http.StatusOK,
+		},
+		"error on update publisher (invalid id)": {
+			id:             "a",
+			expectedStatus: http.StatusBadRequest,
+		},
+		"error on update publisher (invalid website)": {
+			id:                    "1",
+			expectedErrorOnUpdate: utils.ErrInvalidWebsite,
+			expectedStatus:        http.StatusBadRequest,
+		},
+		"error on update publisher (not found)": {
+			id:                    "1",
+			expectedErrorOnUpdate: utils.ErrPublisherIdNotFound,
+			expectedStatus:        http.StatusNotFound,
+		},
+		"error on update publisher (name exists)": {
+			id:                    "1",
+			expectedErrorOnUpdate: utils.ErrPublisherNameExists,
+			expectedStatus:        http.StatusConflict,
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			publisherServiceMock := new(publisherservicemock.PublisherServiceMock)
+			publisherServiceMock.On("UpdatePublisher", 1, dtos.PublisherRequest{Name: "Rocco"}).Return(dtos.PublisherResponse{Id: 1}, tc.expectedErrorOnUpdate)
+
+			publisherControllerTest := publisherController{publisherService: publisherServiceMock}
+
+			request, _ := http.NewRequest("PUT", "/publisher/"+tc.id, strings.NewReader(`{"name":"Rocco"}`))
+			request.Header.Add("Content-type", "application/json")
+			recorder := httptest.NewRecorder()
+			e := echo.New()
+			e.PUT("/publisher/:id", publisherControllerTest.UpdatePublisher)
+			e.ServeHTTP(recorder, request)
+
+			require.Equal(t, tc.expectedStatus, recorder.Code)
+		})
+	}
+}
+
+func TestDeletePublisher(t *testing.T) {
+	tests := map[string]struct {
+		expectedErrorOnDelete error
+		expectedStatus        int
+	}{
+		"success on delete publisher": {
+			expectedStatus: http.StatusNoContent,
+		},
+		"error on delete publisher (not found)": {
+			expectedErrorOnDelete: utils.ErrPublisherIdNotFound,
+			expectedStatus:        http.StatusNotFound,
+		},
+		"error on delete publisher (has books)": {
+			expectedErrorOnDelete: utils.ErrPublisherHasBooks,
+			expectedStatus:        http.StatusConflict,
+		},
+		"error on delete publisher (service)": {
+			expectedErrorOnDelete: errors.New("error occurred"),
+			expectedStatus:        http.StatusInternalServerError,
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			publisherServiceMock := new(publisherservicemock.PublisherServiceMock)
+			publisherServiceMock.On("DeletePublisher", 1).Return(tc.expectedErrorOnDelete)
+
+			publisherControllerTest := publisherController{publisherService: publisherServiceMock}
+
+			request, _ := http.NewRequest("DELETE", "/publisher/1", nil)
+			recorder := httptest.NewRecorder()
+			e := echo.New()
+			e.DELETE("/publisher/:id", publisherControllerTest.DeletePublisher)
+			e.ServeHTTP(recorder, request)
+
+			require.Equal(t, tc.expectedStatus, recorder.Code)
+		})
+	}
+}
+
+func TestGetPublisherBooks(t *testing.T) {
+	tests := map[string]struct {
+		id                 string
+		expectedErrorOnGet error
+		expectedStatus     int
+	}{
+		"success on get publisher books": {
+			id:             "1",
+			expectedStatus: http.StatusOK,
+		},
+		"error on get publisher books (invalid id)": {
+			id:             "a",
+			expectedStatus: http.StatusBadRequest,
+		},
+		"error on get publisher books (not found)": {
+			id:                 "1",
+			expectedErrorOnGet: utils.ErrPublisherIdNotFound,
+			expectedStatus:     http.StatusNotFound,
+		},
+		"error on get publisher books (service)": {
+			id:                 "1",
+			expectedErrorOnGet: errors.New("error occurred"),
+			expectedStatus:     http.StatusInternalServerError,
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			booksResponse := dtos.BookResponseMetadata{Books: []dtos.BookResponse{{Id: 3, Publisher: "Rocco"}}, Pagination: dtos.Pagination{Page: 2, Limit: 5}}
+			publisherServiceMock := new(publisherservicemock.PublisherServiceMock)
+			publisherServiceMock.On("GetPublisherBooks", 1, dtos.Pagination{Page: 2, Limit: 5}).Return(booksResponse, tc.expectedErrorOnGet)
+
+			publisherControllerTest := publisherController{publisherService: publisherServiceMock}
+
+			request, _ := http.NewRequest("GET", "/publishers/"+tc.id+"/books?page=2&limit=5", nil)
+			recorder := httptest.NewRecorder()
+			e := echo.New()
+			e.GET("/publishers/:id/books", publisherControllerTest.GetPublisherBooks)
+			e.ServeHTTP(recorder, request)
+
+			require.Equal(t, tc.expectedStatus, recorder.Code)
+		})
+	}
+}
@@ -0,0 +1,418 @@
+package controllers
+
+import (
+	"fmt"
+	"github/brunojoenk/golang-test/middlewares"
+	"github/brunojoenk/golang-test/models/dtos"
+	readinglistservice "github/brunojoenk/golang-test/services/readinglist"
+	"github/brunojoenk/golang-test/utils"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+type IReadingListController interface {
+	CreateList(c echo.Context) error
+	GetLists(c echo.Context) error
+	GetList(c echo.Context) error
+	UpdateList(c echo.Context) error
+	DeleteList(c echo.Context) error
+	AddBook(c echo.Context) error
+	UpdateBookNote(c echo.Context) error
+	RemoveBook(c echo.Context) error
+	ReorderBooks(c echo.Context) error
+	ExportList(c echo.Context) error
+}
+
+type readingListController struct {
+	readingListService readinglistservice.IReadingListService
+}
+
+// NewReadingListController Controller Constructor
+func NewReadingListController(db *gorm.DB) IReadingListController {
+	return &readingListController{readingListService: readinglistservice.NewReadingListService(db)}
+}
+
+// CreateList godoc
+// @Summary Create a reading list.
+// @Description Create a reading list owned by the owner of the api key of the request. Lists are private unless the visibility is public.
+// @Tags Reading lists
+// @Accept json
+// @Produce json
+// @Param request body dtos.ReadingListRequest true "reading list"
+// @Success 201 {object} dtos.ReadingListResponse
+// @Failure 400 {object} string
+// @Failure 401 {object} string
+// @Failure 500 {object} string
+// @Router /lists [post]
+func (r *readingListController) CreateList(c echo.Context) error {
+
+	listRequest := new(dtos.ReadingListRequest)
+	if err := c.Bind(listRequest); err != nil {
+		c.Logger().Warn("Error on bind body to create reading list: %s", err.Error())
+		return c.JSON(http.StatusBadRequest, fmt.Sprintf("Request body to create a reading list is invalid: %s", err.Error()))
+	}
+
+	list, err := r.readingListService.CreateList(owner(c), *listRequest)
+	if err != nil {
+		return r.errorResponse(c, "create", err)
+	}
+
+	return c.JSON(http.StatusCreated, list)
+}
+
+// GetLists godoc
+// @Summary Show the reading lists with paginations.
+// @Description Show the public reading lists and the private ones of the caller, the last changed first.
+// @Tags Reading lists
+// @Accept */*
+// @Produce json
+// @Param   owner     query     string     false  "search lists by owner"     example(string)
+// @Param   page     query     int     false  "page list"     example(1) minimum(1)
+// @Param   limit     query     int     false  "page size"     example(1) minimum(1) maximum(100)
+// @Success 200 {object} dtos.ReadingListResponseMetadata
+// @Failure 400 {object} string
+// @Failure 500 {object} string
+// @Router /lists [get]
+func (r *readingListController) GetLists(c echo.Context) error {
+
+	var filter dtos.GetReadingListsFilter
+	if err := c.Bind(&filter); err != nil {
+		c.Logger().Warn("Error on bind query to filter reading lists: %s", err.Error())
+		return c.JSON(http.StatusBadRequest, fmt.Sprintf("Invalid query parameters: %s", err.Error()))
+	}
+
+	lists, err := r.readingListService.GetLists(owner(c), filter)
+	if err != nil {
+		return r.errorResponse(c, "get all", err)
+	}
+
+	return c.JSON(http.StatusOK, lists)
+}
+
+// GetList godoc
+// @Summary Get a reading list.
+// @Description Get a reading list with its books in order. A private list is only found by its owner.
+// @Tags Reading lists
+// @Accept */*
+// @Produce json
+// @Param id   path int true "Reading list ID"
+// @Param Accept-Language header string false "preferred languages of the book titles, e.g. pt-BR, en;q=0.8"
+// @Success 200 {object} dtos.ReadingListResponse
+// @Failure 400 {object} string
+// @Failure 404 {object} string
+// @Failure 500 {object} string
+// @Router /lists/{id} [get]
+func (r *readingListController) GetList(c echo.Context) error {
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Logger().Warn("Error on parse parameters id on get reading list %s", err.Error())
+		return c.JSON(http.StatusBadRequest, "Invalid query parameter id")
+	}
+
+	list, err := r.readingListService.GetList(owner(c), id)
+	if err != nil {
+		return r.errorResponse(c, "get", err)
+	}
+
+	list.Localize(c.Request().Header.Get("Accept-Language"))
+	return c.JSON(http.StatusOK, list)
+}
+
+// UpdateList godoc
+// @Summary Update a reading list.
+// @Description Change the name, description and visibility of a reading list of the caller.
+// @Tags Reading lists
+// @Accept json
+// @Produce json
+// @Param id   path int true "Reading list ID"
+// @Param request body dtos.ReadingListRequest true "reading list"
+// @Success 200 {object} dtos.ReadingListResponse
+// @Failure 400 {object} string
+// @Failure 401 {object} string
+// @Failure 403 {object} string
+// @Failure 404 {object} string
+// @Failure 500 {object} string
+// @Router /lists/{id} [put]
+func (r *readingListController) UpdateList(c echo.Context) error {
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Logger().Warn("Error on parse parameters id on update reading list %s", err.Error())
+		return c.JSON(http.StatusBadRequest, "Invalid query parameter id")
+	}
+
+	listRequest := new(dtos.ReadingListRequest)
+	if err := c.Bind(listRequest); err != nil {
+		c.Logger().Warn("Error on parse body on update reading list %s", err.Error())
+		return c.JSON(http.StatusBadRequest, fmt.Sprintf("Error on parse body to update reading list: %s", err.Error()))
+	}
+
+	list, err := r.readingListService.UpdateList(owner(c), id, *listRequest)
+	if err != nil {
+		return r.errorResponse(c, "update", err)
+	}
+
+	list.Localize(c.Request().Header.Get("Accept-Language"))
+	return c.JSON(http.StatusOK, list)
+}
+
+// DeleteList godoc
+// @Summary Delete a reading list.
+// @Description Delete a reading list of the caller.
+// @Tags Reading lists
+// @Accept */*
+// @Produce json
+// @Param id   path int true "Reading list ID"
+// @Success 204 {object} string
+// @Failure 400 {object} string
+// @Failure 401 {object} string
+// @Failure 403 {object} string
+// @Failure 404 {object} string
+// @Failure 500 {object} string
+// @Router /lists/{id} [delete]
+func (r *readingListController) DeleteList(c echo.Context) error {
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Logger().Warn("Error on parse parameters id on delete reading list %s", err.Error())
+		return c.JSON(http.StatusBadRequest, "Invalid query parameter id")
+	}
+
+	if err := r.readingListService.DeleteList(owner(c), id); err != nil {
+		return r.errorResponse(c, "delete", err)
+	}
+
+	return c.JSON(http.StatusNoContent, "Deleted")
+}
+
+// AddBook godoc
+// @Summary Add a book to a reading list.
+// @Description Add a book with an optional note to a reading list of the caller, at the position given or else at the end. The books from that position on move one down.
+// @Tags Reading lists
+// @Accept json
+// @Produce json
+// @Param id   path int true "Reading list ID"
+// @Param request body dtos.ReadingListBookRequest true "book"
+// @Success 201 {object} dtos.ReadingListResponse
+// @Failure 400 {object} string
+// @Failure 401 {object} string
+// @Failure 403 {object} string
+// @Failure 404 {object} string
+// @Failure 409 {object} string
+// @Failure 500 {object} string
+// @Router /lists/{id}/books [post]
+func (r *readingListController) AddBook(c echo.Context) error {
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Logger().Warn("Error on parse parameters id on add book to reading list %s", err.Error())
+		return c.JSON(http.StatusBadRequest, "Invalid query parameter id")
+	}
+
+	bookRequest := new(dtos.ReadingListBookRequest)
+	if err := c.Bind(bookRequest); err != nil {
+		c.Logger().Warn("Error on bind body to add book to reading list: %s", err.Error())
+		return c.JSON(http.StatusBadRequest, fmt.Sprintf("Request body to add a book to a reading list is invalid: %s", err.Error()))
+	}
+
+	list, err := r.readingListService.AddBook(owner(c), id, *bookRequest)
+	if err != nil {
+		return r.errorResponse(c, "add book to", err)
+	}
+
+	list.Localize(c.Request().Header.Get("Accept-Language"))
+	return c.JSON(http.StatusCreated, list)
+}
+
+// UpdateBookNote godoc
+// @Summary Update the note of a book on a reading list.
+// @Description Change the note of a book on a reading list of the caller. An empty note removes it.
+// @Tags Reading lists
+// @Accept json
+// @Produce json
+// @Param id   path int true "Reading list ID"
+// @Param bookId   path int true "Book ID"
+// @Param request body dtos.ReadingListNoteRequest true "note"
+// @Success 200 {object} dtos.ReadingListResponse
+// @Failure 400 {object} string
+// @Failure 401 {object} string
+// @Failure 403 {object} string
+// @Failure 404 {object} string
+// @Failure 500 {object} string
+// @Router /lists/{id}/books/{bookId} [put]
+func (r *readingListController) UpdateBookNote(c echo.Context) error {
+
+	id, bookId, err := listBookIds(c)
+	if err != nil {
+		c.Logger().Warn("Error on parse parameters on update note of reading list book %s", err.Error())
+		return c.JSON(http.StatusBadRequest, "Invalid query parameters id and bookId")
+	}
+
+	noteRequest := new(dtos.ReadingListNoteRequest)
+	if err := c.Bind(noteRequest); err != nil {
+		c.Logger().Warn("Error on parse body on update note of reading list book %s", err.Error())
+		return c.JSON(http.StatusBadRequest, fmt.Sprintf("Error on parse body to update note: %s", err.Error()))
+	}
+
+	list, err := r.readingListService.UpdateBookNote(owner(c), id, bookId, *noteRequest)
+	if err != nil {
+		return r.errorResponse(c, "update book of", err)
+	}
+
+	list.Localize(c.Request().Header.Get("Accept-Language"))
+	return c.JSON(http.StatusOK, list)
+}
+
+// RemoveBook godoc
+// @Summary Remove a book from a reading list.
+// @Description Remove a book from a reading list of the caller. The books after it move one up.
+// @Tags Reading lists
+// @Accept */*
+// @Produce json
+// @Param id   path int true "Reading list ID"
+// @Param bookId   path int true "Book ID"
+// @Success 204 {object} string
+// @Failure 400 {object} string
+// @Failure 401 {object} string
+// @Failure 403 {object} string
+// @Failure 404 {object} string
+// @Failure 500 {object} string
+// @Router /lists/{id}/books/{bookId} [delete]
+func (r *readingListController) RemoveBook(c echo.Context) error {
+
+	id, bookId, err := listBookIds(c)
+	if err != nil {
+		c.Logger().Warn("Error on parse parameters on remove book from reading list %s", err.Error())
+		return c.JSON(http.StatusBadRequest, "Invalid query parameters id and bookId")
+	}
+
+	if err := r.readingListService.RemoveBook(owner(c), id, bookId); err != nil {
+		return r.errorResponse(c, "remove book from", err)
+	}
+
+	return c.JSON(http.StatusNoContent, "Deleted")
+}
+
+// ReorderBooks godoc
+// @Summary Reorder the books of a reading list.
+// @Description Put the books of a reading list of the caller in the order of book_ids, which must have every book of the list once.
+// @Tags Reading lists
+// @Accept json
+// @Produce json
+// @Param id   path int true "Reading list ID"
+// @Param request body dtos.ReadingListOrderRequest true "order"
+// @Success 200 {object} dtos.ReadingListResponse
+// @Failure 400 {object} string
+// @Failure 401 {object} string
+// @Failure 403 {object} string
+// @Failure 404 {object} string
+// @Failure 500 {object} string
+// @Router /lists/{id}/order [put]
+func (r *readingListController) ReorderBooks(c echo.Context) error {
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Logger().Warn("Error on parse parameters id on reorder reading list %s", err.Error())
+		return c.JSON(http.StatusBadRequest, "Invalid query parameter id")
+	}
+
+	orderRequest := new(dtos.ReadingListOrderRequest)
+	if err := c.Bind(orderRequest); err != nil {
+		c.Logger().Warn("Error on parse body on reorder reading list %s", err.Error())
+		return c.JSON(http.StatusBadRequest, fmt.Sprintf("Error on parse body to reorder reading list: %s", err.Error()))
+	}
+
+	list, err := r.readingListService.ReorderBooks(owner(c), id, *orderRequest)
+	if err != nil {
+		return r.errorResponse(c, "reorder", err)
+	}
+
+	list.Localize(c.Request().Header.Get("Accept-Language"))
+	return c.JSON(http.StatusOK, list)
+}
+
+// ExportList godoc
+// @Summary Export a reading list.
+// @Description Download a reading list as json, the same as getting it, or as csv with a line per book: position, book_id, name, authors, isbn13, publication_year, note and added_at.
+// @Tags Reading lists
+// @Accept */*
+// @Produce json
+// @Produce text/csv
+// @Param id   path int true "Reading list ID"
+// @Param   format     query     string     false  "export format, json by default"     Enums(csv, json)
+// @Success 200 {file} file
+// @Failure 400 {object} string
+// @Failure 404 {object} string
+// @Failure 500 {object} string
+// @Router /lists/{id}/export [get]
+func (r *readingListController) ExportList(c echo.Context) error {
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Logger().Warn("Error on parse parameters id on export reading list %s", err.Error())
+		return c.JSON(http.StatusBadRequest, "Invalid query parameter id")
+	}
+
+	format := strings.ToLower(strings.TrimSpace(c.QueryParam("format")))
+	if format == "" {
+		format = dtos.ExportFormatJson
+	}
+
+	data, err := r.readingListService.ExportList(owner(c), id, format)
+	if err != nil {
+		return r.errorResponse(c, "export", err)
+	}
+
+	contentType := echo.MIMEApplicationJSONCharsetUTF8
+	if format == dtos.ExportFormatCsv {
+		contentType = "text/csv; charset=UTF-8"
+	}
+	c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="reading-list-%d.%s"`, id, format))
+	return c.Blob(http.StatusOK, contentType, data)
+}
+
+// owner is the user calling, the owner of the api key of the request, if any
+func owner(c echo.Context) string {
+	principal, ok := middlewares.GetPrincipal(c)
+	if !ok {
+		return ""
+	}
+	return principal.Owner
+}
+
+func listBookIds(c echo.Context) (int, int, error) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return 0, 0, err
+	}
+	bookId, err := strconv.Atoi(c.Param("bookId"))
+	if err != nil {
+		return 0, 0, err
+	}
+	return id, bookId, nil
+}
+
+func (r *readingListController) errorResponse(c echo.Context, action string, err error) error {
+	switch {
+	case errors.Is(err, utils.ErrInvalidListName), errors.Is(err, utils.ErrInvalidListVisibility), errors.Is(err, utils.ErrListNoteTooLong),
+		errors.Is(err, utils.ErrInvalidListOrder), errors.Is(err, utils.ErrInvalidExportFormat):
+		return c.JSON(http.StatusBadRequest, err.Error())
+	case errors.Is(err, utils.ErrListOwnerRequired):
+		return c.JSON(http.StatusUnauthorized, err.Error())
+	case errors.Is(err, utils.ErrListNotOwner):
+		return c.JSON(http.StatusForbidden, err.Error())
+	case errors.Is(err, utils.ErrListIdNotFound), errors.Is(err, utils.ErrBookIdNotFound), errors.Is(err, utils.ErrListBookNotFound):
+		return c.JSON(http.StatusNotFound, err.Error())
+	case errors.Is(err, utils.ErrListBookExists):
+		return c.JSON(http.StatusConflict, err.Error())
+	}
+	c.Logger().Error("Error on %s reading list %s", action, err.Error())
+	return c.JSON(http.StatusInternalServerError, fmt.Sprintf("Error on %s reading list. Please contact system admin", action))
+}
@@ -0,0 +1,417 @@
+package controllers
+
+import (
+	"errors"
+	"github/brunojoenk/golang-test/middlewares"
+	"github/brunojoenk/golang-test/models/dtos"
+	"github/brunojoenk/golang-test/utils"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	readinglistservicemock "github/brunojoenk/golang-test/services/readinglist/mock"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+)
+
+// withOwner authenticates the requests as the owner of an api key
+func withOwner(owner string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if owner != "" {
+				c.Set(middlewares.API_KEY_PRINCIPAL_KEY, dtos.ApiKeyPrincipal{Id: 1, Owner: owner, Scopes: []string{dtos.ScopeWrite}})
+			}
+			return next(c)
+		}
+	}
+}
+
+func TestCreateList(t *testing.T) {
+	tests := map[string]struct {
+		owner                 string
+		body                  string
+		expectedErrorOnCreate error
+		expectedStatus        int
+	}{
+		"success on create reading list": {
+			owner:          "ana",
+			body:           `{"name":"To read"}`,
+			expectedStatus: http.StatusCreated,
+		},
+		"error on create reading list (body)": {
+			owner:          "ana",
+			body:           `{"name":1}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		"error on create reading list (no api key)": {
+			body:                  `{"name":"To read"}`,
+			expectedErrorOnCreate: utils.ErrListOwnerRequired,
+			expectedStatus:        http.StatusUnauthorized,
+		},
+		"error on create reading list (invalid visibility)": {
+			owner:                 "ana",
+			body:                  `{"name":"To read"}`,
+			expectedErrorOnCreate: utils.ErrInvalidListVisibility,
+			expectedStatus:        http.StatusBadRequest,
+		},
+		"error on create reading list (service)": {
+			owner:                 "ana",
+			body:                  `{"name":"To read"}`,
+			expectedErrorOnCreate: errors.New("error occurred"),
+			expectedStatus:        http.StatusInternalServerError,
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			readingListServiceMock := new(readinglistservicemock.ReadingListServiceMock)
+			readingListServiceMock.On("CreateList", tc.owner, dtos.ReadingListRequest{Name: "To read"}).Return(dtos.ReadingListResponse{Id: 4}, tc.expectedErrorOnCreate)
+
+			readingListControllerTest := readingListController{readingListService: readingListServiceMock}
+
+			request, _ := http.NewRequest("POST", "/lists", strings.NewReader(tc.body))
+			request.Header.Add("Content-type", "application/json")
+			recorder := httptest.NewRecorder()
+			e := echo.New()
+			e.Use(withOwner(tc.owner))
+			e.POST("/lists", readingListControllerTest.CreateList)
+			e.ServeHTTP(recorder, request)
+
+			require.Equal(t, tc.expectedStatus, recorder.Code)
+		})
+	}
+}
+
+func TestGetLists(t *testing.T) {
+	listsResponse := dtos.ReadingListResponseMetadata{
+		Lists:      []dtos.ReadingListResponse{{Id: 4, Owner: "ana", Name: "Course syllabus", Visibility: dtos.ListVisibilityPublic, BookCount: 3}},
+		Pagination: dtos.Pagination{Page: 1, Limit: 10},
+	}
+	readingListServiceMock := new(readinglistservicemock.ReadingListServiceMock)
+	readingListServiceMock.On("GetLists", "bob", dtos.GetReadingListsFilter{Owner: "ana"}).Return(listsResponse, nil)
+
+	readingListControllerTest := readingListController{readingListService: readingListServiceMock}
+	e := echo.New()
+	e.Use(withOwner("bob"))
+	e.GET("/lists", readingListControllerTest.GetLists)
+
+	request, _ := http.NewRequest("GET", "/lists?owner=ana", nil)
+	recorder := httptest.NewRecorder()
+	e.ServeHTTP(recorder, request)
+
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Contains(t, recorder.Body.String(), `"book_count":3`)
+}
+
+func TestGetList(t *testing.T) {
+	tests := map[string]struct {
+		path               string
+		expectedErrorOnGet error
+		expectedStatus     int
+	}{
+		"success on get reading list": {
+			path:           "/lists/4",
+			expectedStatus: http.StatusOK,
+		},
+		"error on get reading list (invalid id)": {
+			path:           "/lists/a",
+			expectedStatus: http.StatusBadRequest,
+		},
+		"error on get reading list (not found)": {
+			path:               "/lists/4",
+			expectedErrorOnGet: utils.ErrListIdNotFound,
+			expectedStatus:     http.StatusNotFound,
+		},
+		"error on get reading list (service)": {
+			path:               "/lists/4",
+			expectedErrorOnGet: errors.New("error occurred"),
+			expectedStatus:     http.StatusInternalServerError,
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			readingListServiceMock := new(readinglistservicemock.ReadingListServiceMock)
+			readingListServiceMock.On("GetList", "", 4).Return(dtos.ReadingListResponse{Id: 4, Books: []dtos.ReadingListBookResponse{
+				{Position: 1, Book: dtos.BookResponse{Id: 1, Name: "The Hobbit", Language: "en", Titles: map[string]string{"pt-BR": "O Hobbit"}}},
+			}}, tc.expectedErrorOnGet)
+
+			readingListControllerTest := readingListController{readingListService: readingListServiceMock}
+
+			request, _ := http.NewRequest("GET", tc.path, nil)
+			request.Header.Add("Accept-Language", "pt-BR")
+			recorder := httptest.NewRecorder()
+			e := echo.New()
+			e.GET("/lists/:id", readingListControllerTest.GetList)
+			e.ServeHTTP(recorder, request)
+
+			require.Equal(t, tc.expectedStatus, recorder.Code)
+			if tc.expectedStatus == http.StatusOK {
+				require.Contains(t, recorder.Body.String(), `"title":"O Hobbit"`)
+			}
+		})
+	}
+}
+
+func TestUpdateList(t *testing.T) {
+	tests := map[string]struct {
+		expectedErrorOnUpdate error
+		expectedStatus        int
+	}{
+		"success on update reading list": {
+			expectedStatus: http.StatusOK,
+		},
+		"error on update reading list (invalid name)": {
+			expectedErrorOnUpdate: utils.ErrInvalidListName,
+			expectedStatus:        http.StatusBadRequest,
+		},
+		"error on update reading list (list of someone else)": {
+			expectedErrorOnUpdate: utils.ErrListNotOwner,
+			expectedStatus:        http.StatusForbidden,
+		},
+		"error on update reading list (not found)": {
+			expectedErrorOnUpdate: utils.ErrListIdNotFound,
+			expectedStatus:        http.StatusNotFound,
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			readingListServiceMock := new(readinglistservicemock.ReadingListServiceMock)
+			readingListServiceMock.On("UpdateList", "ana", 4, dtos.ReadingListRequest{Name: "Course syllabus", Visibility: "public"}).Return(dtos.ReadingListResponse{Id: 4}, tc.expectedErrorOnUpdate)
+
+			readingListControllerTest := readingListController{readingListService: readingListServiceMock}
+
+			request, _ := http.NewRequest("PUT", "/lists/4", strings.NewReader(`{"name":"Course syllabus","visibility":"public"}`))
+			request.Header.Add("Content-type", "application/json")
+			recorder := httptest.NewRecorder()
+			e := echo.New()
+			e.Use(withOwner("ana"))
+			e.PUT("/lists/:id", readingListControllerTest.UpdateList)
+			e.ServeHTTP(recorder, request)
+
+			require.Equal(t, tc.expectedStatus, recorder.Code)
+		})
+	}
+}
+
+func TestDeleteList(t *testing.T) {
+	tests := map[string]struct {
+		expectedErrorOnDelete error
+		expectedStatus        int
+	}{
+		"success on delete reading list": {
+			expectedStatus: http.StatusNoContent,
+		},
+		"error on delete reading list (list of someone else)": {
+			expectedErrorOnDelete: utils.ErrListNotOwner,
+			expectedStatus:        http.StatusForbidden,
+		},
+		"error on delete reading list (service)": {
+			expectedErrorOnDelete: errors.New("error occurred"),
+			expectedStatus:        http.StatusInternalServerError,
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			readingListServiceMock := new(readinglistservicemock.ReadingListServiceMock)
+			readingListServiceMock.On("DeleteList", "ana", 4).Return(tc.expectedErrorOnDelete)
+
+			readingListControllerTest := readingListController{readingListService: readingListServiceMock}
+
+			request, _ := http.NewRequest("DELETE", "/lists/4", nil)
+			recorder := httptest.NewRecorder()
+			e := echo.New()
+			e.Use(withOwner("ana"))
+			e.DELETE("/lists/:id", readingListControllerTest.DeleteList)
+			e.ServeHTTP(recorder, request)
+
+			require.Equal(t, tc.expectedStatus, recorder.Code)
+		})
+	}
+}
+
+func TestAddBook(t *testing.T) {
+	tests := map[string]struct {
+		expectedErrorOnAdd error
+		expectedStatus     int
+	}{
+		"success on add book to reading list": {
+			expectedStatus: http.StatusCreated,
+		},
+		"error on add book to reading list (book not found)": {
+			expectedErrorOnAdd: utils.ErrBookIdNotFound,
+			expectedStatus:     http.StatusNotFound,
+		},
+		"error on add book to reading list (already on the list)": {
+			expectedErrorOnAdd: utils.ErrListBookExists,
+			expectedStatus:     http.StatusConflict,
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			readingListServiceMock := new(readinglistservicemock.ReadingListServiceMock)
+			readingListServiceMock.On("AddBook", "ana", 4, dtos.ReadingListBookRequest{BookId: 1, Note: "For the exam", Position: 1}).Return(dtos.ReadingListResponse{Id: 4}, tc.expectedErrorOnAdd)
+
+			readingListControllerTest := readingListController{readingListService: readingListServiceMock}
+
+			request, _ := http.NewRequest("POST", "/lists/4/books", strings.NewReader(`{"book_id":1,"note":"For the exam","position":1}`))
+			request.Header.Add("Content-type", "application/json")
+			recorder := httptest.NewRecorder()
+			e := echo.New()
+			e.Use(withOwner("ana"))
+			e.POST("/lists/:id/books", readingListControllerTest.AddBook)
+			e.ServeHTTP(recorder, request)
+
+			require.Equal(t, tc.expectedStatus, recorder.Code)
+		})
+	}
+}
+
+func TestUpdateBookNote(t *testing.T) {
+	readingListServiceMock := new(readinglistservicemock.ReadingListServiceMock)
+	readingListServiceMock.On("UpdateBookNote", "ana", 4, 1, dtos.ReadingListNoteRequest{Note: "Skip the preface"}).Return(dtos.ReadingListResponse{Id: 4}, nil)
+	readingListServiceMock.On("UpdateBookNote", "ana", 4, 2, dtos.ReadingListNoteRequest{Note: "Skip the preface"}).Return(dtos.ReadingListResponse{}, utils.ErrListBookNotFound)
+
+	readingListControllerTest := readingListController{readingListService: readingListServiceMock}
+	e := echo.New()
+	e.Use(withOwner("ana"))
+	e.PUT("/lists/:id/books/:bookId", readingListControllerTest.UpdateBookNote)
+
+	request, _ := http.NewRequest("PUT", "/lists/4/books/1", strings.NewReader(`{"note":"Skip the preface"}`))
+	request.Header.Add("Content-type", "application/json")
+	recorder := httptest.NewRecorder()
+	e.ServeHTTP(recorder, request)
+
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	request, _ = http.NewRequest("PUT", "/lists/4/books/2", strings.NewReader(`{"note":"Skip the preface"}`))
+	request.Header.Add("Content-type", "application/json")
+	recorder = httptest.NewRecorder()
+	e.ServeHTTP(recorder, request)
+
+	require.Equal(t, http.StatusNotFound, recorder.Code)
+}
+
+func TestRemoveBook(t *testing.T) {
+	tests := map[string]struct {
+		path                  string
+		expectedErrorOnRemove error
+		expectedStatus        int
+	}{
+		"success on remove book from reading list": {
+			path:           "/lists/4/books/1",
+			expectedStatus: http.StatusNoContent,
+		},
+		"error on remove book from reading list (invalid book id)": {
+			path:           "/lists/4/books/a",
+			expectedStatus: http.StatusBadRequest,
+		},
+		"error on remove book from reading list (not on the list)": {
+			path:                  "/lists/4/books/1",
+			expectedErrorOnRemove: utils.ErrListBookNotFound,
+			expectedStatus:        http.StatusNotFound,
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			readingListServiceMock := new(readinglistservicemock.ReadingListServiceMock)
+			readingListServiceMock.On("RemoveBook", "ana", 4, 1).Return(tc.expectedErrorOnRemove)
+
+			readingListControllerTest := readingListController{readingListService: readingListServiceMock}
+
+			request, _ := http.NewRequest("DELETE", tc.path, nil)
+			recorder := httptest.NewRecorder()
+			e := echo.New()
+			e.Use(withOwner("ana"))
+			e.DELETE("/lists/:id/books/:bookId", readingListControllerTest.RemoveBook)
+			e.ServeHTTP(recorder, request)
+
+			require.Equal(t, tc.expectedStatus, recorder.Code)
+		})
+	}
+}
+
+func TestReorderBooks(t *testing.T) {
+	tests := map[string]struct {
+		expectedErrorOnReorder error
+		expectedStatus         int
+	}{
+		"success on reorder reading list": {
+			expectedStatus: http.StatusOK,
+		},
+		"error on reorder reading list (invalid order)": {
+			expectedErrorOnReorder: utils.ErrInvalidListOrder,
+			expectedStatus:         http.StatusBadRequest,
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			readingListServiceMock := new(readinglistservicemock.ReadingListServiceMock)
+			readingListServiceMock.On("ReorderBooks", "ana", 4, dtos.ReadingListOrderRequest{BookIds: []int{3, 1}}).Return(dtos.ReadingListResponse{Id: 4}, tc.expectedErrorOnReorder)
+
+			readingListControllerTest := readingListController{readingListService: readingListServiceMock}
+
+			request, _ := http.NewRequest("PUT", "/lists/4/order", strings.NewReader(`{"book_ids":[3,1]}`))
+			request.Header.Add("Content-type", "application/json")
+			recorder := httptest.NewRecorder()
+			e := echo.New()
+			e.Use(withOwner("ana"))
+			e.PUT("/lists/:id/order", readingListControllerTest.ReorderBooks)
+			e.ServeHTTP(recorder, request)
+
+			require.Equal(t, tc.expectedStatus, recorder.Code)
+		})
+	}
+}
+
+func TestExportList(t *testing.T) {
+	tests := map[string]struct {
+		query               string
+		format              string
+		expectedErrorOnGet  error
+		expectedStatus      int
+		expectedContentType string
+		expectedDisposition string
+	}{
+		"success on export reading list (csv)": {
+			query:               "?format=csv",
+			format:              dtos.ExportFormatCsv,
+			expectedStatus:      http.StatusOK,
+			expectedContentType: "text/csv; charset=UTF-8",
+			expectedDisposition: `attachment; filename="reading-list-4.csv"`,
+		},
+		"success on export reading list (json by default)": {
+			format:              dtos.ExportFormatJson,
+			expectedStatus:      http.StatusOK,
+			expectedContentType: echo.MIMEApplicationJSONCharsetUTF8,
+			expectedDisposition: `attachment; filename="reading-list-4.json"`,
+		},
+		"error on export reading list (invalid format)": {
+			query:              "?format=xml",
+			format:             "xml",
+			expectedErrorOnGet: utils.ErrInvalidExportFormat,
+			expectedStatus:     http.StatusBadRequest,
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			readingListServiceMock := new(readinglistservicemock.ReadingListServiceMock)
+			readingListServiceMock.On("ExportList", "", 4, tc.format).Return([]byte("data"), tc.expectedErrorOnGet)
+
+			readingListControllerTest := readingListController{readingListService: readingListServiceMock}
+
+			request, _ := http.NewRequest("GET", "/lists/4/export"+tc.query, nil)
+			recorder := httptest.NewRecorder()
+			e := echo.New()
+			e.GET("/lists/:id/export", readingListControllerTest.ExportList)
+			e.ServeHTTP(recorder, request)
+
+			require.Equal(t, tc.expectedStatus, recorder.Code)
+			if tc.expectedStatus == http.StatusOK {
+				require.Equal(t, tc.expectedContentType, recorder.Header().Get(echo.HeaderContentType))
+				require.Equal(t, tc.expectedDisposition, recorder.Header().Get(echo.HeaderContentDisposition))
+				require.Equal(t, "data", recorder.Body.String())
+			}
+		})
+	}
+}
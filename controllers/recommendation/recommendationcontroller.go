@@ -0,0 +1,70 @@
+package controllers
+
+import (
+	"fmt"
+	"github/brunojoenk/golang-test/models/dtos"
+	recommendationservice "github/brunojoenk/golang-test/services/recommendation"
+	"github/brunojoenk/golang-test/utils"
+	"net/http"
+	"strconv"
+
+	"github.com/pkg/errors"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+type IRecommendationController interface {
+	GetRelatedBooks(c echo.Context) error
+}
+
+type recommendationController struct {
+	recommendationService recommendationservice.IRecommendationService
+}
+
+// NewRecommendationController Controller Constructor
+func NewRecommendationController(db *gorm.DB) IRecommendationController {
+	return &recommendationController{recommendationService: recommendationservice.NewRecommendationService(db)}
+}
+
+// GetRelatedBooks godoc
+// @Summary Show the books related to a book with paginations.
+// @Description Show the books that share authors, genres or tags with a book, or were borrowed by the same patrons or put on the same public reading lists, the most related first. Each book has its score and the count of each thing it has in common with the book. Other editions of the same work are left out.
+// @Tags Books
+// @Accept */*
+// @Produce json
+// @Param id   path int true "Book ID"
+// @Param   page     query     int     false  "page list"     example(1) minimum(1)
+// @Param   limit     query     int     false  "page size"     example(1) minimum(1) maximum(100)
+// @Param Accept-Language header string false "preferred languages of the book titles, e.g. pt-BR, en;q=0.8"
+// @Success 200 {object} dtos.RelatedBookResponseMetadata
+// @Failure 400 {object} string
+// @Failure 404 {object} string
+// @Failure 500 {object} string
+// @Router /books/{id}/related [get]
+func (r *recommendationController) GetRelatedBooks(c echo.Context) error {
+
+	bookId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Logger().Warn("Error on parse parameters id on get related books %s", err.Error())
+		return c.JSON(http.StatusBadRequest, "Invalid query parameter id")
+	}
+
+	var pagination dtos.Pagination
+	if err := c.Bind(&pagination); err != nil {
+		c.Logger().Warn("Error on bind query to get related books: %s", err.Error())
+		return c.JSON(http.StatusBadRequest, fmt.Sprintf("Invalid query parameters: %s", err.Error()))
+	}
+
+	relatedBooks, err := r.recommendationService.GetRelatedBooks(bookId, pagination)
+	if err != nil {
+		if errors.Is(err, utils.ErrBookIdNotFound) {
+			return c.JSON(http.StatusNotFound, err.Error())
+		}
+		c.Logger().Error("Error on get related books %s", err.Error())
+		return c.JSON(http.StatusInternalServerError, "Error on get related books. Please contact system admin")
+	}
+
+	relatedBooks.Localize(c.Request().Header.Get("Accept-Language"))
+	return c.JSON(http.StatusOK, relatedBooks)
+}
@@ -0,0 +1,70 @@
+package controllers
+
+import (
+	"errors"
+	"github/brunojoenk/golang-test/models/dtos"
+	"github/brunojoenk/golang-test/utils"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	recommendationservicemock "github/brunojoenk/golang-test/services/recommendation/mock"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetRelatedBooks(t *testing.T) {
+	tests := map[string]struct {
+		path                  string
+		expectedErrorOnGet    error
+		expectedStatus        int
+		expectedBodyToContain string
+	}{
+		"success on get related books": {
+			path:                  "/books/7/related?page=2&limit=5",
+			expectedStatus:        http.StatusOK,
+			expectedBodyToContain: `"title":"O Hobbit"`,
+		},
+		"error on get related books (invalid id)": {
+			path:           "/books/a/related",
+			expectedStatus: http.StatusBadRequest,
+		},
+		"error on get related books (invalid page)": {
+			path:           "/books/7/related?page=a",
+			expectedStatus: http.StatusBadRequest,
+		},
+		"error on get related books (book not found)": {
+			path:               "/books/7/related?page=2&limit=5",
+			expectedErrorOnGet: utils.ErrBookIdNotFound,
+			expectedStatus:     http.StatusNotFound,
+		},
+		"error on get related books (service)": {
+			path:               "/books/7/related?page=2&limit=5",
+			expectedErrorOnGet: errors.New("error occurred"),
+			expectedStatus:     http.StatusInternalServerError,
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			recommendationServiceMock := new(recommendationservicemock.RecommendationServiceMock)
+			recommendationServiceMock.On("GetRelatedBooks", 7, dtos.Pagination{Page: 2, Limit: 5}).Return(dtos.RelatedBookResponseMetadata{
+				Books: []dtos.RelatedBookResponse{{Score: 4, Reasons: dtos.RelatedReasons{SharedAuthors: 1},
+					Book: dtos.BookResponse{Id: 1, Name: "The Hobbit", Language: "en", Titles: map[string]string{"pt-BR": "O Hobbit"}}}},
+				Pagination: dtos.Pagination{Page: 2, Limit: 5},
+			}, tc.expectedErrorOnGet)
+
+			recommendationControllerTest := recommendationController{recommendationService: recommendationServiceMock}
+
+			request, _ := http.NewRequest("GET", tc.path, nil)
+			request.Header.Add("Accept-Language", "pt-BR")
+			recorder := httptest.NewRecorder()
+			e := echo.New()
+			e.GET("/books/:id/related", recommendationControllerTest.GetRelatedBooks)
+			e.ServeHTTP(recorder, request)
+
+			require.Equal(t, tc.expectedStatus, recorder.Code)
+			require.Contains(t, recorder.Body.String(), tc.expectedBodyToContain)
+		})
+	}
+}
@@ -0,0 +1,242 @@
+package controllers
+
+import (
+	"fmt"
+	"github/brunojoenk/golang-test/middlewares"
+	"github/brunojoenk/golang-test/models/dtos"
+	reviewservice "github/brunojoenk/golang-test/services/review"
+	"github/brunojoenk/golang-test/utils"
+	"net/http"
+	"strconv"
+
+	"github.com/pkg/errors"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+type IReviewController interface {
+	CreateReview(c echo.Context) error
+	GetBookReviews(c echo.Context) error
+	GetReview(c echo.Context) error
+	UpdateReview(c echo.Context) error
+	DeleteReview(c echo.Context) error
+}
+
+type reviewController struct {
+	reviewService reviewservice.IReviewService
+}
+
+// NewReviewController Controller Constructor
+func NewReviewController(db *gorm.DB) IReviewController {
+	return &reviewController{reviewService: reviewservice.NewReviewService(db)}
+}
+
+// CreateReview godoc
+// @Summary Review a book.
+// @Description Rate a book from 1 to 5 with an optional text of up to 5000 characters. A patron reviews a book only once, the rating average and count of the book are updated with it. The review belongs to the owner of the api key of the request.
+// @Tags Reviews
+// @Accept json
+// @Produce json
+// @Param id   path int true "Book ID"
+// @Param request body dtos.ReviewRequest true "review"
+// @Success 201 {object} dtos.ReviewResponse
+// @Failure 400 {object} string
+// @Failure 401 {object} string
+// @Failure 404 {object} string
+// @Failure 409 {object} string
+// @Failure 500 {object} string
+// @Router /books/{id}/reviews [post]
+func (r *reviewController) CreateReview(c echo.Context) error {
+
+	bookId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Logger().Warn("Error on parse parameters id on create review %s", err.Error())
+		return c.JSON(http.StatusBadRequest, "Invalid query parameter id")
+	}
+
+	reviewRequest := new(dtos.ReviewRequest)
+	if err := c.Bind(reviewRequest); err != nil {
+		c.Logger().Warn("Error on bind body to create review: %s", err.Error())
+		return c.JSON(http.StatusBadRequest, fmt.Sprintf("Request body to create a review is invalid: %s", err.Error()))
+	}
+
+	review, err := r.reviewService.CreateReview(owner(c), bookId, *reviewRequest)
+	if err != nil {
+		return r.errorResponse(c, "create", err)
+	}
+
+	return c.JSON(http.StatusCreated, review)
+}
+
+// GetBookReviews godoc
+// @Summary Show the reviews of a book with paginations.
+// @Description Show the reviews of a book, the newest first.
+// @Tags Reviews
+// @Accept */*
+// @Produce json
+// @Param id   path int true "Book ID"
+// @Param   rating     query     int     false  "search reviews by rating"     minimum(1) maximum(5)
+// @Param   page     query     int     false  "page list"     example(1) minimum(1)
+// @Param   limit     query     int     false  "page size"     example(1) minimum(1) maximum(100)
+// @Success 200 {object} dtos.ReviewResponseMetadata
+// @Failure 400 {object} string
+// @Failure 404 {object} string
+// @Failure 500 {object} string
+// @Router /books/{id}/reviews [get]
+func (r *reviewController) GetBookReviews(c echo.Context) error {
+
+	bookId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Logger().Warn("Error on parse parameters id on get reviews %s", err.Error())
+		return c.JSON(http.StatusBadRequest, "Invalid query parameter id")
+	}
+
+	var filter dtos.GetReviewsFilter
+	if err := c.Bind(&filter); err != nil {
+		c.Logger().Warn("Error on bind query to filter reviews: %s", err.Error())
+		return c.JSON(http.StatusBadRequest, fmt.Sprintf("Invalid query parameters: %s", err.Error()))
+	}
+
+	reviews, err := r.reviewService.GetBookReviews(bookId, filter)
+	if err != nil {
+		return r.errorResponse(c, "get all", err)
+	}
+
+	return c.JSON(http.StatusOK, reviews)
+}
+
+// GetReview godoc
+// @Summary Get a review of a book.
+// @Description Get a review of a book.
+// @Tags Reviews
+// @Accept */*
+// @Produce json
+// @Param id   path int true "Book ID"
+// @Param reviewId   path int true "Review ID"
+// @Success 200 {object} dtos.ReviewResponse
+// @Failure 400 {object} string
+// @Failure 404 {object} string
+// @Failure 500 {object} string
+// @Router /books/{id}/reviews/{reviewId} [get]
+func (r *reviewController) GetReview(c echo.Context) error {
+
+	bookId, id, err := reviewIds(c)
+	if err != nil {
+		c.Logger().Warn("Error on parse parameters on get review %s", err.Error())
+		return c.JSON(http.StatusBadRequest, "Invalid query parameters id and reviewId")
+	}
+
+	review, err := r.reviewService.GetReview(bookId, id)
+	if err != nil {
+		return r.errorResponse(c, "get", err)
+	}
+
+	return c.JSON(http.StatusOK, review)
+}
+
+// UpdateReview godoc
+// @Summary Update a review of a book.
+// @Description Change the rating and text of a review. Only the owner of the api key that wrote the review changes it, and the review stays with the patron who wrote it.
+// @Tags Reviews
+// @Accept json
+// @Produce json
+// @Param id   path int true "Book ID"
+// @Param reviewId   path int true "Review ID"
+// @Param request body dtos.ReviewRequest true "review"
+// @Success 200 {object} dtos.ReviewResponse
+// @Failure 400 {object} string
+// @Failure 401 {object} string
+// @Failure 403 {object} string
+// @Failure 404 {object} string
+// @Failure 500 {object} string
+// @Router /books/{id}/reviews/{reviewId} [put]
+func (r *reviewController) UpdateReview(c echo.Context) error {
+
+	bookId, id, err := reviewIds(c)
+	if err != nil {
+		c.Logger().Warn("Error on parse parameters on update review %s", err.Error())
+		return c.JSON(http.StatusBadRequest, "Invalid query parameters id and reviewId")
+	}
+
+	reviewRequest := new(dtos.ReviewRequest)
+	if err := c.Bind(reviewRequest); err != nil {
+		c.Logger().Warn("Error on parse body on update review %s", err.Error())
+		return c.JSON(http.StatusBadRequest, fmt.Sprintf("Error on parse body to update review: %s", err.Error()))
+	}
+
+	review, err := r.reviewService.UpdateReview(owner(c), bookId, id, *reviewRequest)
+	if err != nil {
+		return r.errorResponse(c, "update", err)
+	}
+
+	return c.JSON(http.StatusOK, review)
+}
+
+// DeleteReview godoc
+// @Summary Delete a review of a book.
+// @Description Delete a review of a book. Only the owner of the api key that wrote the review deletes it, and the rating average and count of the book are updated without it.
+// @Tags Reviews
+// @Accept */*
+// @Produce json
+// @Param id   path int true "Book ID"
+// @Param reviewId   path int true "Review ID"
+// @Success 204 {object} string
+// @Failure 400 {object} string
+// @Failure 401 {object} string
+// @Failure 403 {object} string
+// @Failure 404 {object} string
+// @Failure 500 {object} string
+// @Router /books/{id}/reviews/{reviewId} [delete]
+func (r *reviewController) DeleteReview(c echo.Context) error {
+
+	bookId, id, err := reviewIds(c)
+	if err != nil {
+		c.Logger().Warn("Error on parse parameters on delete review %s", err.Error())
+		return c.JSON(http.StatusBadRequest, "Invalid query parameters id and reviewId")
+	}
+
+	if err := r.reviewService.DeleteReview(owner(c), bookId, id); err != nil {
+		return r.errorResponse(c, "delete", err)
+	}
+
+	return c.JSON(http.StatusNoContent, "Deleted")
+}
+
+// owner is the user calling, the owner of the api key of the request, if any
+func owner(c echo.Context) string {
+	principal, ok := middlewares.GetPrincipal(c)
+	if !ok {
+		return ""
+	}
+	return principal.Owner
+}
+
+func reviewIds(c echo.Context) (int, int, error) {
+	bookId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return 0, 0, err
+	}
+	id, err := strconv.Atoi(c.Param("reviewId"))
+	if err != nil {
+		return 0, 0, err
+	}
+	return bookId, id, nil
+}
+
+func (r *reviewController) errorResponse(c echo.Context, action string, err error) error {
+	switch {
+	case errors.Is(err, utils.ErrInvalidRating), errors.Is(err, utils.ErrReviewTextTooLong), errors.Is(err, utils.ErrReviewPatron):
+		return c.JSON(http.StatusBadRequest, err.Error())
+	case errors.Is(err, utils.ErrReviewOwnerRequired):
+		return c.JSON(http.StatusUnauthorized, err.Error())
+	case errors.Is(err, utils.ErrReviewNotOwner):
+		return c.JSON(http.StatusForbidden, err.Error())
+	case errors.Is(err, utils.ErrBookIdNotFound), errors.Is(err, utils.ErrPatronIdNotFound), errors.Is(err, utils.ErrReviewIdNotFound):
+		return c.JSON(http.StatusNotFound, err.Error())
+	case errors.Is(err, utils.ErrReviewExists):
+		return c.JSON(http.StatusConflict, err.Error())
+	}
+	c.Logger().Error("Error on %s review %s", action, err.Error())
+	return c.JSON(http.StatusInternalServerError, fmt.Sprintf("Error on %s review. Please contact system admin", action))
+}
@@ -0,0 +1,272 @@
+package controllers
+
+import (
+	"errors"
+	"github/brunojoenk/golang-test/middlewares"
+	"github/brunojoenk/golang-test/models/dtos"
+	"github/brunojoenk/golang-test/utils"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	reviewservicemock "github/brunojoenk/golang-test/services/review/mock"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+)
+
+// withOwner authenticates the requests as the owner of an api key
+func withOwner(owner string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if owner != "" {
+				c.Set(middlewares.API_KEY_PRINCIPAL_KEY, dtos.ApiKeyPrincipal{Id: 1, Owner: owner, Scopes: []string{dtos.ScopeWrite}})
+			}
+			return next(c)
+		}
+	}
+}
+
+func TestCreateReview(t *testing.T) {
+	tests := map[string]struct {
+		owner                 string
+		id                    string
+		body                  string
+		expectedErrorOnCreate error
+		expectedStatus        int
+	}{
+		"success on create review": {
+			owner:          "ana",
+			id:             "1",
+			body:           `{"patron_id":10,"rating":4,"text":"Great read"}`,
+			expectedStatus: http.StatusCreated,
+		},
+		"error on create review (invalid id)": {
+			owner:          "ana",
+			id:             "a",
+			body:           `{"patron_id":10,"rating":4,"text":"Great read"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		"error on create review (body)": {
+			owner:          "ana",
+			id:             "1",
+			body:           `{"rating":"four"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		"error on create review (invalid rating)": {
+			owner:                 "ana",
+			id:                    "1",
+			body:                  `{"patron_id":10,"rating":4,"text":"Great read"}`,
+			expectedErrorOnCreate: utils.ErrInvalidRating,
+			expectedStatus:        http.StatusBadRequest,
+		},
+		"error on create review (patron not found)": {
+			owner:                 "ana",
+			id:                    "1",
+			body:                  `{"patron_id":10,"rating":4,"text":"Great read"}`,
+			expectedErrorOnCreate: utils.ErrPatronIdNotFound,
+			expectedStatus:        http.StatusNotFound,
+		},
+		"error on create review (already reviewed)": {
+			owner:                 "ana",
+			id:                    "1",
+			body:                  `{"patron_id":10,"rating":4,"text":"Great read"}`,
+			expectedErrorOnCreate: utils.ErrReviewExists,
+			expectedStatus:        http.StatusConflict,
+		},
+		"error on create review (no api key)": {
+			id:                    "1",
+			body:                  `{"patron_id":10,"rating":4,"text":"Great read"}`,
+			expectedErrorOnCreate: utils.ErrReviewOwnerRequired,
+			expectedStatus:        http.StatusUnauthorized,
+		},
+		"error on create review (service)": {
+			owner:                 "ana",
+			id:                    "1",
+			body:                  `{"patron_id":10,"rating":4,"text":"Great read"}`,
+			expectedErrorOnCreate: errors.New("error occurred"),
+			expectedStatus:        http.StatusInternalServerError,
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			reviewServiceMock := new(reviewservicemock.ReviewServiceMock)
+			reviewServiceMock.On("CreateReview", tc.owner, 1, dtos.ReviewRequest{PatronId: 10, Rating: 4, Text: "Great read"}).Return(dtos.ReviewResponse{Id: 8, BookId: 1}, tc.expectedErrorOnCreate)
+
+			reviewControllerTest := reviewController{reviewService: reviewServiceMock}
+
+			request, _ := http.NewRequest("POST", "/books/"+tc.id+"/reviews", strings.NewReader(tc.body))
+			request.Header.Add("Content-type", "application/json")
+			recorder := httptest.NewRecorder()
+			e := echo.New()
+			e.Use(withOwner(tc.owner))
+			e.POST("/books/:id/reviews", reviewControllerTest.CreateReview)
+			e.ServeHTTP(recorder, request)
+
+			require.Equal(t, tc.expectedStatus, recorder.Code)
+		})
+	}
+}
+
+func TestGetBookReviews(t *testing.T) {
+	reviewsResponse := dtos.ReviewResponseMetadata{
+		Reviews:    []dtos.ReviewResponse{{Id: 8, BookId: 1, PatronId: 10, Reviewer: "Ana", Rating: 5}},
+		Pagination: dtos.Pagination{Page: 1, Limit: 10},
+	}
+	reviewServiceMock := new(reviewservicemock.ReviewServiceMock)
+	reviewServiceMock.On("GetBookReviews", 1, dtos.GetReviewsFilter{Rating: 5}).Return(reviewsResponse, nil)
+	reviewServiceMock.On("GetBookReviews", 1, dtos.GetReviewsFilter{Rating: 9}).Return(dtos.ReviewResponseMetadata{}, utils.ErrInvalidRating)
+	reviewServiceMock.On("GetBookReviews", 2, dtos.GetReviewsFilter{}).Return(dtos.ReviewResponseMetadata{}, utils.ErrBookIdNotFound)
+
+	reviewControllerTest := reviewController{reviewService: reviewServiceMock}
+	e := echo.New()
+	e.GET("/books/:id/reviews", reviewControllerTest.GetBookReviews)
+
+	request, _ := http.NewRequest("GET", "/books/1/reviews?rating=5", nil)
+	recorder := httptest.NewRecorder()
+	e.ServeHTTP(recorder, request)
+
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Contains(t, recorder.Body.String(), `"reviewer":"Ana"`)
+
+	request, _ = http.NewRequest("GET", "/books/1/reviews?rating=9", nil)
+	recorder = httptest.NewRecorder()
+	e.ServeHTTP(recorder, request)
+
+	require.Equal(t, http.StatusBadRequest, recorder.Code)
+
+	request, _ = http.NewRequest("GET", "/books/2/reviews", nil)
+	recorder = httptest.NewRecorder()
+	e.ServeHTTP(recorder, request)
+
+	require.Equal(t, http.StatusNotFound, recorder.Code)
+}
+
+func TestGetReview(t *testing.T) {
+	tests := map[string]struct {
+		path               string
+		expectedErrorOnGet error
+		expectedStatus     int
+	}{
+		"success on get review": {
+			path:           "/books/1/reviews/8",
+			expectedStatus: http.StatusOK,
+		},
+		"error on get review (invalid review id)": {
+			path:           "/books/1/reviews/a",
+			expectedStatus: http.StatusBadRequest,
+		},
+		"error on get review (not found)": {
+			path:               "/books/1/reviews/8",
+			expectedErrorOnGet: utils.ErrReviewIdNotFound,
+			expectedStatus:     http.StatusNotFound,
+		},
+		"error on get review (service)": {
+			path:               "/books/1/reviews/8",
+			expectedErrorOnGet: errors.New("error occurred"),
+			expectedStatus:     http.StatusInternalServerError,
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			reviewServiceMock := new(reviewservicemock.ReviewServiceMock)
+			reviewServiceMock.On("GetReview", 1, 8).Return(dtos.ReviewResponse{Id: 8, BookId: 1, Rating: 5}, tc.expectedErrorOnGet)
+
+			reviewControllerTest := reviewController{reviewService: reviewServiceMock}
+
+			request, _ := http.NewRequest("GET", tc.path, nil)
+			recorder := httptest.NewRecorder()
+			e := echo.New()
+			e.GET("/books/:id/reviews/:reviewId", reviewControllerTest.GetReview)
+			e.ServeHTTP(recorder, request)
+
+			require.Equal(t, tc.expectedStatus, recorder.Code)
+		})
+	}
+}
+
+func TestUpdateReview(t *testing.T) {
+	tests := map[string]struct {
+		expectedErrorOnUpdate error
+		expectedStatus        int
+	}{
+		"success on update review": {
+			expectedStatus: http.StatusOK,
+		},
+		"error on update review (text too long)": {
+			expectedErrorOnUpdate: utils.ErrReviewTextTooLong,
+			expectedStatus:        http.StatusBadRequest,
+		},
+		"error on update review (review of someone else)": {
+			expectedErrorOnUpdate: utils.ErrReviewNotOwner,
+			expectedStatus:        http.StatusForbidden,
+		},
+		"error on update review (another patron)": {
+			expectedErrorOnUpdate: utils.ErrReviewPatron,
+			expectedStatus:        http.StatusBadRequest,
+		},
+		"error on update review (not found)": {
+			expectedErrorOnUpdate: utils.ErrReviewIdNotFound,
+			expectedStatus:        http.StatusNotFound,
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			reviewServiceMock := new(reviewservicemock.ReviewServiceMock)
+			reviewServiceMock.On("UpdateReview", "ana", 1, 8, dtos.ReviewRequest{Rating: 2, Text: "Changed my mind"}).Return(dtos.ReviewResponse{Id: 8}, tc.expectedErrorOnUpdate)
+
+			reviewControllerTest := reviewController{reviewService: reviewServiceMock}
+
+			request, _ := http.NewRequest("PUT", "/books/1/reviews/8", strings.NewReader(`{"rating":2,"text":"Changed my mind"}`))
+			request.Header.Add("Content-type", "application/json")
+			recorder := httptest.NewRecorder()
+			e := echo.New()
+			e.Use(withOwner("ana"))
+			e.PUT("/books/:id/reviews/:reviewId", reviewControllerTest.UpdateReview)
+			e.ServeHTTP(recorder, request)
+
+			require.Equal(t, tc.expectedStatus, recorder.Code)
+		})
+	}
+}
+
+func TestDeleteReview(t *testing.T) {
+	tests := map[string]struct {
+		expectedErrorOnDelete error
+		expectedStatus        int
+	}{
+		"success on delete review": {
+			expectedStatus: http.StatusNoContent,
+		},
+		"error on delete review (review of someone else)": {
+			expectedErrorOnDelete: utils.ErrReviewNotOwner,
+			expectedStatus:        http.StatusForbidden,
+		},
+		"error on delete review (not found)": {
+			expectedErrorOnDelete: utils.ErrReviewIdNotFound,
+			expectedStatus:        http.StatusNotFound,
+		},
+		"error on delete review (service)": {
+			expectedErrorOnDelete: errors.New("error occurred"),
+			expectedStatus:        http.StatusInternalServerError,
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			reviewServiceMock := new(reviewservicemock.ReviewServiceMock)
+			reviewServiceMock.On("DeleteReview", "ana", 1, 8).Return(tc.expectedErrorOnDelete)
+
+			reviewControllerTest := reviewController{reviewService: reviewServiceMock}
+
+			request, _ := http.NewRequest("DELETE", "/books/1/reviews/8", nil)
+			recorder := httptest.NewRecorder()
+			e := echo.New()
+			e.Use(withOwner("ana"))
+			e.DELETE("/books/:id/reviews/:reviewId", reviewControllerTest.DeleteReview)
+			e.ServeHTTP(recorder, request)
+
+			require.Equal(t, tc.expectedStatus, recorder.Code)
+		})
+	}
+}
@@ -0,0 +1,198 @@
+package controllers
+
+import (
+	"fmt"
+	"github/brunojoenk/golang-test/models/dtos"
+	seriesservice "github/brunojoenk/golang-test/services/series"
+	"github/brunojoenk/golang-test/utils"
+	"net/http"
+	"strconv"
+
+	"github.com/pkg/errors"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+type ISeriesController interface {
+	CreateSeries(c echo.Context) error
+	GetAllSeries(c echo.Context) error
+	GetSeries(c echo.Context) error
+	UpdateSeries(c echo.Context) error
+	DeleteSeries(c echo.Context) error
+}
+
+type seriesController struct {
+	seriesService seriesservice.ISeriesService
+}
+
+// NewSeriesController Controller Constructor
+func NewSeriesController(db *gorm.DB) ISeriesController {
+	return &seriesController{seriesService: seriesservice.NewSeriesService(db)}
+}
+
+// CreateSeries godoc
+// @Summary Create a series.
+// @Description Create a series. Names are unique ignoring case, accents and punctuation.
+// @Tags Series
+// @Accept json
+// @Produce json
+// @Param request body dtos.SeriesRequest true "series"
+// @Success 201 {object} dtos.SeriesResponse
+// @Failure 400 {object} string
+// @Failure 409 {object} string
+// @Failure 500 {object} string
+// @Router /series [post]
+func (s *seriesController) CreateSeries(c echo.Context) error {
+
+	seriesRequest := new(dtos.SeriesRequest)
+	if err := c.Bind(seriesRequest); err != nil {
+		c.Logger().Warn("Error on bind body to create series: %s", err.Error())
+		return c.JSON(http.StatusBadRequest, fmt.Sprintf("Request body to create a series is invalid: %s", err.Error()))
+	}
+
+	series, err := s.seriesService.CreateSeries(*seriesRequest)
+	if err != nil {
+		return s.errorResponse(c, "create", err)
+	}
+
+	return c.JSON(http.StatusCreated, series)
+}
+
+// GetAllSeries godoc
+// @Summary Show all the series with paginations.
+// @Description Show all the series with paginations.
+// @Tags Series
+// @Accept */*
+// @Produce json
+// @Param   name     query     string     false  "search series by name"     example(string)
+// @Param   page     query     int     false  "page list"     example(1) minimum(1)
+// @Param   limit     query     int     false  "page size"     example(1) minimum(1) maximum(100)
+// @Success 200 {object} dtos.SeriesResponseMetadata
+// @Failure 400 {object} string
+// @Failure 500 {object} string
+// @Router /series [get]
+func (s *seriesController) GetAllSeries(c echo.Context) error {
+	var filter dtos.GetSeriesFilter
+	err := c.Bind(&filter)
+	if err != nil {
+		c.Logger().Warn("Error on bind query to filter all series: %s", err.Error())
+		return c.JSON(http.StatusBadRequest, fmt.Sprintf("Invalid query parameters: %s", err.Error()))
+	}
+
+	seriesResponse, err := s.seriesService.GetAllSeries(filter)
+	if err != nil {
+		return s.errorResponse(c, "get all", err)
+	}
+
+	return c.JSON(http.StatusOK, seriesResponse)
+}
+
+// GetSeries godoc
+// @Summary Get a series with its books.
+// @Description Get a series with its books in reading order. Books without a position come last.
+// @Tags Series
+// @Accept */*
+// @Produce json
+// @Param Accept-Language header string false "preferred languages of the book titles, e.g. pt-BR, en;q=0.8"
+// @Param id   path int true "Series ID"
+// @Success 200 {object} dtos.SeriesResponse
+// @Failure 400 {object} string
+// @Failure 404 {object} string
+// @Failure 500 {object} string
+// @Router /series/{id} [get]
+func (s *seriesController) GetSeries(c echo.Context) error {
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Logger().Warn("Error on parse parameters id on get series %s", err.Error())
+		return c.JSON(http.StatusBadRequest, "Invalid query parameter id")
+	}
+
+	series, err := s.seriesService.GetSeries(id)
+	if err != nil {
+		return s.errorResponse(c, "get", err)
+	}
+
+	for i := range series.Books {
+		series.Books[i].Localize(c.Request().Header.Get("Accept-Language"))
+	}
+	return c.JSON(http.StatusOK, series)
+}
+
+// UpdateSeries godoc
+// @Summary Update a series.
+// @Description Update a series.
+// @Tags Series
+// @Accept json
+// @Produce json
+// @Param id   path int true "Series ID"
+// @Param request body dtos.SeriesRequest true "series"
+// @Success 200 {object} dtos.SeriesResponse
+// @Failure 400 {object} string
+// @Failure 404 {object} string
+// @Failure 409 {object} string
+// @Failure 500 {object} string
+// @Router /series/{id} [put]
+func (s *seriesController) UpdateSeries(c echo.Context) error {
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Logger().Warn("Error on parse parameters id on update series %s", err.Error())
+		return c.JSON(http.StatusBadRequest, "Invalid query parameter id")
+	}
+
+	seriesRequest := new(dtos.SeriesRequest)
+	if err := c.Bind(seriesRequest); err != nil {
+		c.Logger().Warn("Error on parse body on update series %s", err.Error())
+		return c.JSON(http.StatusBadRequest, fmt.Sprintf("Error on parse body to update series: %s", err.Error()))
+	}
+
+	series, err := s.seriesService.UpdateSeries(id, *seriesRequest)
+	if err != nil {
+		return s.errorResponse(c, "update", err)
+	}
+
+	return c.JSON(http.StatusOK, series)
+}
+
+// DeleteSeries godoc
+// @Summary Delete a series.
+// @Description Delete a series without books.
+// @Tags Series
+// @Accept */*
+// @Produce json
+// @Param id   path int true "Series ID"
+// @Success 204 {object} string
+// @Failure 400 {object} string
+// @Failure 404 {object} string
+// @Failure 409 {object} string
+// @Failure 500 {object} string
+// @Router /series/{id} [delete]
+func (s *seriesController) DeleteSeries(c echo.Context) error {
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Logger().Warn("Error on parse parameters id on delete series %s", err.Error())
+		return c.JSON(http.StatusBadRequest, "Invalid query parameter id")
+	}
+
+	if err := s.seriesService.DeleteSeries(id); err != nil {
+		return s.errorResponse(c, "delete", err)
+	}
+
+	return c.JSON(http.StatusNoContent, "Deleted")
+}
+
+func (s *seriesController) errorResponse(c echo.Context, action string, err error) error {
+	switch {
+	case errors.Is(err, utils.ErrSeriesNameEmpty):
+		return c.JSON(http.StatusBadRequest, err.Error())
+	case errors.Is(err, utils.ErrSeriesIdNotFound):
+		return c.JSON(http.StatusNotFound, err.Error())
+	case errors.Is(err, utils.ErrSeriesNameExists), errors.Is(err, utils.ErrSeriesHasBooks):
+		return c.JSON(http.StatusConflict, err.Error())
+	}
+	c.Logger().Error("Error on %s series %s", action, err.Error())
+	return c.JSON(http.StatusInternalServerError, fmt.Sprintf("Error on %s series. Please contact system admin", action))
+}
@@ -0,0 +1,218 @@
+package controllers
+
+import (
+	"errors"
+	"github/brunojoenk/golang-test/models/dtos"
+	"github/brunojoenk/golang-test/utils"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	seriesservicemock "github/brunojoenk/golang-test/services/series/mock"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateSeries(t *testing.T) {
+	tests := map[string]struct {
+		body                  string
+		expectedErrorOnCreate error
+		expectedStatus        int
+	}{
+		"success on create series": {
+			body:           `{"name":"Discworld"}`,
+			expectedStatus: http.StatusCreated,
+		},
+		"error on create series (body)": {
+			body:           `{"name":5}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		"error on create series (empty name)": {
+			body:                  `{"name":"Discworld"}`,
+			expectedErrorOnCreate: utils.ErrSeriesNameEmpty,
+			expectedStatus:        http.StatusBadRequest,
+		},
+		"error on create series (name exists)": {
+			body:                  `{"name":"Discworld"}`,
+			expectedErrorOnCreate: utils.ErrSeriesNameExists,
+			expectedStatus:        http.StatusConflict,
+		},
+		"error on create series (service)": {
+			body:                  `{"name":"Discworld"}`,
+			expectedErrorOnCreate: errors.New("error occurred"),
+			expectedStatus:        http.StatusInternalServerError,
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			seriesServiceMock := new(seriesservicemock.SeriesServiceMock)
+			seriesServiceMock.On("CreateSeries", dtos.SeriesRequest{Name: "Discworld"}).Return(dtos.SeriesResponse{Id: 1}, tc.expectedErrorOnCreate)
+
+			seriesControllerTest := seriesController{seriesService: seriesServiceMock}
+
+			request, _ := http.NewRequest("POST", "/series", strings.NewReader(tc.body))
+			request.Header.Add("Content-type", "application/json")
+			recorder := httptest.NewRecorder()
+			e := echo.New()
+			e.POST("/series", seriesControllerTest.CreateSeries)
+			e.ServeHTTP(recorder, request)
+
+			require.Equal(t, tc.expectedStatus, recorder.Code)
+		})
+	}
+}
+
+func TestGetAllSeries(t *testing.T) {
+	seriesResponse := dtos.SeriesResponseMetadata{
+		Series:     []dtos.SeriesResponse{{Id: 1, Name: "Discworld"}},
+		Pagination: dtos.Pagination{Page: 1, Limit: 10},
+	}
+	seriesServiceMock := new(seriesservicemock.SeriesServiceMock)
+	seriesServiceMock.On("GetAllSeries", dtos.GetSeriesFilter{Name: "disc"}).Return(seriesResponse, nil)
+	seriesServiceMock.On("GetAllSeries", dtos.GetSeriesFilter{Name: "error"}).Return(dtos.SeriesResponseMetadata{}, errors.New("error occurred"))
+
+	seriesControllerTest := seriesController{seriesService: seriesServiceMock}
+	e := echo.New()
+	e.GET("/series", seriesControllerTest.GetAllSeries)
+
+	request, _ := http.NewRequest("GET", "/series?name=disc", nil)
+	recorder := httptest.NewRecorder()
+	e.ServeHTTP(recorder, request)
+
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Contains(t, recorder.Body.String(), `"name":"Discworld"`)
+
+	request, _ = http.NewRequest("GET", "/series?name=error", nil)
+	recorder = httptest.NewRecorder()
+	e.ServeHTTP(recorder, request)
+
+	require.Equal(t, http.StatusInternalServerError, recorder.Code)
+}
+
+func TestGetSeries(t *testing.T) {
+	tests := map[string]struct {
+		id                 string
+		expectedErrorOnGet error
+		expectedStatus     int
+	}{
+		"success on get series": {
+			id:             "1",
+			expectedStatus: http.StatusOK,
+		},
+		"error on get series (invalid id)": {
+			id:             "a",
+			expectedStatus: http.StatusBadRequest,
+		},
+		"error on get series (not found)": {
+			id:                 "1",
+			expectedErrorOnGet: utils.ErrSeriesIdNotFound,
+			expectedStatus:     http.StatusNotFound,
+		},
+		"error on get series (service)": {
+			id:                 "1",
+			expectedErrorOnGet: errors.New("error occurred"),
+			expectedStatus:     http.StatusInternalServerError,
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			series := dtos.SeriesResponse{Id: 1, Name: "Discworld", Books: []dtos.BookResponse{{Id: 3, Name: "The Colour of Magic", SeriesPosition: 1}}}
+			seriesServiceMock := new(seriesservicemock.SeriesServiceMock)
+			seriesServiceMock.On("GetSeries", 1).Return(series, tc.expectedErrorOnGet)
+
+			seriesControllerTest := seriesController{seriesService: seriesServiceMock}
+
+			request, _ := http.NewRequest("GET", "/series/"+tc.id, nil)
+			recorder := httptest.NewRecorder()
+			e := echo.New()
+			e.GET("/series/:id", seriesControllerTest.GetSeries)
+			e.ServeHTTP(recorder, request)
+
+			require.Equal(t, tc.expectedStatus, recorder.Code)
+			if tc.expectedStatus == http.StatusOK {
+				require.Contains(t, recorder.Body.String(), `"series_position":1`)
+			}
+		})
+	}
+}
+
+func TestUpdateSeries(t *testing.T) {
+	tests := map[string]struct {
+		id                    string
+		expectedErrorOnUpdate error
+		expectedStatus        int
+	}{
+		"success on update series": {
+			id:             "1",
+			expectedStatus: http.StatusOK,
+		},
+		"error on update series (invalid id)": {
+			id:             "a",
+			expectedStatus: http.StatusBadRequest,
+		},
+		"error on update series (not found)": {
+			id:                    "1",
+			expectedErrorOnUpdate: utils.ErrSeriesIdNotFound,
+			expectedStatus:        http.StatusNotFound,
+		},
+		"error on update series (name exists)": {
+			id:                    "1",
+			expectedErrorOnUpdate: utils.ErrSeriesNameExists,
+			expectedStatus:        http.StatusConflict,
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			seriesServiceMock := new(seriesservicemock.SeriesServiceMock)
+			seriesServiceMock.On("UpdateSeries", 1, dtos.SeriesRequest{Name: "Discworld"}).Return(dtos.SeriesResponse{Id: 1}, tc.expectedErrorOnUpdate)
+
+			seriesControllerTest := seriesController{seriesService: seriesServiceMock}
+
+			request, _ := http.NewRequest("PUT", "/series/"+tc.id, strings.NewReader(`{"name":"Discworld"}`))
+			request.Header.Add("Content-type", "application/json")
+			recorder := httptest.NewRecorder()
+			e := echo.New()
+			e.PUT("/series/:id", seriesControllerTest.UpdateSeries)
+			e.ServeHTTP(recorder, request)
+
+			require.Equal(t, tc.expectedStatus, recorder.Code)
+		})
+	}
+}
+
+func TestDeleteSeries(t *testing.T) {
+	tests := map[string]struct {
+		expectedErrorOnDelete error
+		expectedStatus        int
+	}{
+		"success on delete series": {
+			expectedStatus: http.StatusNoContent,
+		},
+		"error on delete series (not found)": {
+			expectedErrorOnDelete: utils.ErrSeriesIdNotFound,
+			expectedStatus:        http.StatusNotFound,
+		},
+		"error on delete series (has books)": {
+			expectedErrorOnDelete: utils.ErrSeriesHasBooks,
+			expectedStatus:        http.StatusConflict,
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			seriesServiceMock := new(seriesservicemock.SeriesServiceMock)
+			seriesServiceMock.On("DeleteSeries", 1).Return(tc.expectedErrorOnDelete)
+
+			seriesControllerTest := seriesController{seriesService: seriesServiceMock}
+
+			request, _ := http.NewRequest("DELETE", "/series/1", nil)
+			recorder := httptest.NewRecorder()
+			e := echo.New()
+			e.DELETE("/series/:id", seriesControllerTest.DeleteSeries)
+			e.ServeHTTP(recorder, request)
+
+			require.Equal(t, tc.expectedStatus, recorder.Code)
+		})
+	}
+}
@@ -0,0 +1,250 @@
+package controllers
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"github/brunojoenk/golang-test/config"
+	"github/brunojoenk/golang-test/models/dtos"
+	statsservice "github/brunojoenk/golang-test/services/stats"
+	"github/brunojoenk/golang-test/utils"
+	"net/http"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+type IStatsController interface {
+	GetPublicationYearStats(c echo.Context) error
+	GetProlificAuthors(c echo.Context) error
+	GetBooksWithoutAuthors(c echo.Context) error
+	GetAuthorsWithoutBooks(c echo.Context) error
+	GetImportStats(c echo.Context) error
+}
+
+type statsController struct {
+	statsService statsservice.IStatsService
+}
+
+// NewStatsController Controller Constructor
+func NewStatsController(db *gorm.DB, cfg *config.Config) IStatsController {
+	return &statsController{statsService: statsservice.NewStatsService(db, cfg)}
+}
+
+// csvExporter is a statistic that can be downloaded as csv, the first record being the header
+type csvExporter interface {
+	CsvRecords() [][]string
+}
+
+// GetPublicationYearStats godoc
+// @Summary Show how many books were published each year or decade.
+// @Description Count the books per publication year, or per decade when the bucket is decade, the oldest first. Books without a publication year are left out. With format csv the columns are year (or decade) and books.
+// @Tags Statistics
+// @Accept */*
+// @Produce json
+// @Produce text/csv
+// @Param   bucket     query     string     false  "year by default"     Enums(year, decade)
+// @Param   format     query     string     false  "json by default"     Enums(csv, json)
+// @Success 200 {object} dtos.PublicationYearStatsResponse
+// @Failure 400 {object} string
+// @Failure 500 {object} string
+// @Router /stats/books/publication-years [get]
+func (s *statsController) GetPublicationYearStats(c echo.Context) error {
+
+	format, err := exportFormat(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, err.Error())
+	}
+
+	var filter dtos.GetPublicationYearStatsFilter
+	if err := c.Bind(&filter); err != nil {
+		c.Logger().Warn("Error on bind query to get publication year stats: %s", err.Error())
+		return c.JSON(http.StatusBadRequest, fmt.Sprintf("Invalid query parameters: %s", err.Error()))
+	}
+
+	stats, err := s.statsService.GetPublicationYearStats(filter)
+	if err != nil {
+		return s.errorResponse(c, "publication years", err)
+	}
+
+	return s.respond(c, "publication-years", format, stats)
+}
+
+// GetProlificAuthors godoc
+// @Summary Show the authors with the most books with paginations.
+// @Description List the authors by the number of books they are credited as author of, the most prolific first. With format csv the columns are author_id, name and books.
+// @Tags Statistics
+// @Accept */*
+// @Produce json
+// @Produce text/csv
+// @Param   page     query     int     false  "page list"     example(1) minimum(1)
+// @Param   limit     query     int     false  "page size"     example(1) minimum(1) maximum(100)
+// @Param   format     query     string     false  "json by default"     Enums(csv, json)
+// @Success 200 {object} dtos.ProlificAuthorsResponse
+// @Failure 400 {object} string
+// @Failure 500 {object} string
+// @Router /stats/authors/prolific [get]
+func (s *statsController) GetProlificAuthors(c echo.Context) error {
+
+	format, err := exportFormat(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, err.Error())
+	}
+
+	var pagination dtos.Pagination
+	if err := c.Bind(&pagination); err != nil {
+		c.Logger().Warn("Error on bind query to get prolific authors: %s", err.Error())
+		return c.JSON(http.StatusBadRequest, fmt.Sprintf("Invalid query parameters: %s", err.Error()))
+	}
+
+	stats, err := s.statsService.GetProlificAuthors(pagination)
+	if err != nil {
+		return s.errorResponse(c, "prolific authors", err)
+	}
+
+	return s.respond(c, "prolific-authors", format, stats)
+}
+
+// GetBooksWithoutAuthors godoc
+// @Summary Show the books without authors with paginations.
+// @Description List the books nobody is credited as author of, even if they have a translator, editor or illustrator. With format csv the columns are id, name and publication_year.
+// @Tags Statistics
+// @Accept */*
+// @Produce json
+// @Produce text/csv
+// @Param   page     query     int     false  "page list"     example(1) minimum(1)
+// @Param   limit     query     int     false  "page size"     example(1) minimum(1) maximum(100)
+// @Param   format     query     string     false  "json by default"     Enums(csv, json)
+// @Success 200 {object} dtos.BooksWithoutAuthorsResponse
+// @Failure 400 {object} string
+// @Failure 500 {object} string
+// @Router /stats/books/without-authors [get]
+func (s *statsController) GetBooksWithoutAuthors(c echo.Context) error {
+
+	format, err := exportFormat(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, err.Error())
+	}
+
+	var pagination dtos.Pagination
+	if err := c.Bind(&pagination); err != nil {
+		c.Logger().Warn("Error on bind query to get books without authors: %s", err.Error())
+		return c.JSON(http.StatusBadRequest, fmt.Sprintf("Invalid query parameters: %s", err.Error()))
+	}
+
+	stats, err := s.statsService.GetBooksWithoutAuthors(pagination)
+	if err != nil {
+		return s.errorResponse(c, "books without authors", err)
+	}
+
+	return s.respond(c, "books-without-authors", format, stats)
+}
+
+// GetAuthorsWithoutBooks godoc
+// @Summary Show the authors without books with paginations.
+// @Description List the authors not credited on any book, in any role, by name. With format csv the columns are id and name.
+// @Tags Statistics
+// @Accept */*
+// @Produce json
+// @Produce text/csv
+// @Param   page     query     int     false  "page list"     example(1) minimum(1)
+// @Param   limit     query     int     false  "page size"     example(1) minimum(1) maximum(100)
+// @Param   format     query     string     false  "json by default"     Enums(csv, json)
+// @Success 200 {object} dtos.AuthorsWithoutBooksResponse
+// @Failure 400 {object} string
+// @Failure 500 {object} string
+// @Router /stats/authors/without-books [get]
+func (s *statsController) GetAuthorsWithoutBooks(c echo.Context) error {
+
+	format, err := exportFormat(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, err.Error())
+	}
+
+	var pagination dtos.Pagination
+	if err := c.Bind(&pagination); err != nil {
+		c.Logger().Warn("Error on bind query to get authors without books: %s", err.Error())
+		return c.JSON(http.StatusBadRequest, fmt.Sprintf("Invalid query parameters: %s", err.Error()))
+	}
+
+	stats, err := s.statsService.GetAuthorsWithoutBooks(pagination)
+	if err != nil {
+		return s.errorResponse(c, "authors without books", err)
+	}
+
+	return s.respond(c, "authors-without-books", format, stats)
+}
+
+// GetImportStats godoc
+// @Summary Show the import volumes over time.
+// @Description Sum up the imports that ran each day, week, month or year and the rows they added, the oldest period first. Periods without imports are left out. With format csv the columns are the period start date, runs and added.
+// @Tags Statistics
+// @Accept */*
+// @Produce json
+// @Produce text/csv
+// @Param   kind     query     string     false  "kind of import, every kind by default"     Enums(authors)
+// @Param   interval     query     string     false  "month by default"     Enums(day, week, month, year)
+// @Param   format     query     string     false  "json by default"     Enums(csv, json)
+// @Success 200 {object} dtos.ImportStatsResponse
+// @Failure 400 {object} string
+// @Failure 500 {object} string
+// @Router /stats/imports [get]
+func (s *statsController) GetImportStats(c echo.Context) error {
+
+	format, err := exportFormat(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, err.Error())
+	}
+
+	var filter dtos.GetImportStatsFilter
+	if err := c.Bind(&filter); err != nil {
+		c.Logger().Warn("Error on bind query to get import stats: %s", err.Error())
+		return c.JSON(http.StatusBadRequest, fmt.Sprintf("Invalid query parameters: %s", err.Error()))
+	}
+
+	stats, err := s.statsService.GetImportStats(filter)
+	if err != nil {
+		return s.errorResponse(c, "imports", err)
+	}
+
+	return s.respond(c, "imports", format, stats)
+}
+
+// exportFormat is the format query parameter, json when missing
+func exportFormat(c echo.Context) (string, error) {
+	format := strings.ToLower(strings.TrimSpace(c.QueryParam("format")))
+	if format == "" {
+		return dtos.ExportFormatJson, nil
+	}
+	if format != dtos.ExportFormatCsv && format != dtos.ExportFormatJson {
+		return "", utils.ErrInvalidExportFormat
+	}
+	return format, nil
+}
+
+// respond writes the statistic as json, or as a csv file download named after it
+func (s *statsController) respond(c echo.Context, name, format string, stats csvExporter) error {
+	if format != dtos.ExportFormatCsv {
+		return c.JSON(http.StatusOK, stats)
+	}
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.WriteAll(stats.CsvRecords()); err != nil {
+		return s.errorResponse(c, name, err)
+	}
+
+	c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="stats-%s.csv"`, name))
+	return c.Blob(http.StatusOK, "text/csv; charset=UTF-8", buf.Bytes())
+}
+
+func (s *statsController) errorResponse(c echo.Context, stat string, err error) error {
+	if errors.Is(err, utils.ErrInvalidStatsBucket) || errors.Is(err, utils.ErrInvalidStatsInterval) {
+		return c.JSON(http.StatusBadRequest, err.Error())
+	}
+	c.Logger().Error("Error on get %s stats %s", stat, err.Error())
+	return c.JSON(http.StatusInternalServerError, fmt.Sprintf("Error on get %s stats. Please contact system admin", stat))
+}
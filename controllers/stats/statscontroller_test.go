@@ -0,0 +1,272 @@
+package controllers
+
+import (
+	"errors"
+	"github/brunojoenk/golang-test/models/dtos"
+	"github/brunojoenk/golang-test/utils"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	statsservicemock "github/brunojoenk/golang-test/services/stats/mock"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetPublicationYearStats(t *testing.T) {
+	tests := map[string]struct {
+		path                string
+		expectedErrorOnGet  error
+		expectedStatus      int
+		expectedBody        string
+		expectedDisposition string
+	}{
+		"success on get publication year stats": {
+			path:           "/stats/books/publication-years?bucket=decade",
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"bucket":"decade","years":[{"year":1990,"books":3},{"year":2000,"books":5}]}` + "\n",
+		},
+		"success on get publication year stats (csv)": {
+			path:                "/stats/books/publication-years?bucket=decade&format=csv",
+			expectedStatus:      http.StatusOK,
+			expectedBody:        "decade,books\n1990,3\n2000,5\n",
+			expectedDisposition: `attachment; filename="stats-publication-years.csv"`,
+		},
+		"error on get publication year stats (invalid format)": {
+			path:           "/stats/books/publication-years?format=xml",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `"` + utils.ErrInvalidExportFormat.Error() + `"` + "\n",
+		},
+		"error on get publication year stats (invalid bucket)": {
+			path:               "/stats/books/publication-years?bucket=decade",
+			expectedErrorOnGet: utils.ErrInvalidStatsBucket,
+			expectedStatus:     http.StatusBadRequest,
+			expectedBody:       `"` + utils.ErrInvalidStatsBucket.Error() + `"` + "\n",
+		},
+		"error on get publication year stats (service)": {
+			path:               "/stats/books/publication-years?bucket=decade",
+			expectedErrorOnGet: errors.New("error occurred"),
+			expectedStatus:     http.StatusInternalServerError,
+			expectedBody:       `"Error on get publication years stats. Please contact system admin"` + "\n",
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			statsServiceMock := new(statsservicemock.StatsServiceMock)
+			statsServiceMock.On("GetPublicationYearStats", dtos.GetPublicationYearStatsFilter{Bucket: "decade"}).Return(dtos.PublicationYearStatsResponse{
+				Bucket: "decade",
+				Years:  []dtos.YearBookCount{{Year: 1990, Books: 3}, {Year: 2000, Books: 5}},
+			}, tc.expectedErrorOnGet)
+
+			statsControllerTest := statsController{statsService: statsServiceMock}
+
+			request, _ := http.NewRequest("GET", tc.path, nil)
+			recorder := httptest.NewRecorder()
+			e := echo.New()
+			e.GET("/stats/books/publication-years", statsControllerTest.GetPublicationYearStats)
+			e.ServeHTTP(recorder, request)
+
+			require.Equal(t, tc.expectedStatus, recorder.Code)
+			require.Equal(t, tc.expectedBody, recorder.Body.String())
+			require.Equal(t, tc.expectedDisposition, recorder.Header().Get(echo.HeaderContentDisposition))
+		})
+	}
+}
+
+func TestGetProlificAuthors(t *testing.T) {
+	tests := map[string]struct {
+		path                  string
+		expectedErrorOnGet    error
+		expectedStatus        int
+		expectedBodyToContain string
+		expectedContentType   string
+	}{
+		"success on get prolific authors": {
+			path:                  "/stats/authors/prolific?page=2&limit=5",
+			expectedStatus:        http.StatusOK,
+			expectedBodyToContain: `{"author_id":3,"name":"Stephen King","books":12}`,
+			expectedContentType:   echo.MIMEApplicationJSONCharsetUTF8,
+		},
+		"success on get prolific authors (csv)": {
+			path:                  "/stats/authors/prolific?page=2&limit=5&format=CSV",
+			expectedStatus:        http.StatusOK,
+			expectedBodyToContain: "author_id,name,books\n3,Stephen King,12\n",
+			expectedContentType:   "text/csv; charset=UTF-8",
+		},
+		"error on get prolific authors (invalid page)": {
+			path:           "/stats/authors/prolific?page=a",
+			expectedStatus: http.StatusBadRequest,
+		},
+		"error on get prolific authors (service)": {
+			path:               "/stats/authors/prolific?page=2&limit=5",
+			expectedErrorOnGet: errors.New("error occurred"),
+			expectedStatus:     http.StatusInternalServerError,
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			statsServiceMock := new(statsservicemock.StatsServiceMock)
+			statsServiceMock.On("GetProlificAuthors", dtos.Pagination{Page: 2, Limit: 5}).Return(dtos.ProlificAuthorsResponse{
+				Authors:    []dtos.AuthorBookCount{{AuthorId: 3, Name: "Stephen King", Books: 12}},
+				Pagination: dtos.Pagination{Page: 2, Limit: 5},
+			}, tc.expectedErrorOnGet)
+
+			statsControllerTest := statsController{statsService: statsServiceMock}
+
+			request, _ := http.NewRequest("GET", tc.path, nil)
+			recorder := httptest.NewRecorder()
+			e := echo.New()
+			e.GET("/stats/authors/prolific", statsControllerTest.GetProlificAuthors)
+			e.ServeHTTP(recorder, request)
+
+			require.Equal(t, tc.expectedStatus, recorder.Code)
+			require.Contains(t, recorder.Body.String(), tc.expectedBodyToContain)
+			if tc.expectedContentType != "" {
+				require.Equal(t, tc.expectedContentType, recorder.Header().Get(echo.HeaderContentType))
+			}
+		})
+	}
+}
+
+func TestGetBooksWithoutAuthors(t *testing.T) {
+	tests := map[string]struct {
+		path                  string
+		expectedErrorOnGet    error
+		expectedStatus        int
+		expectedBodyToContain string
+	}{
+		"success on get books without authors": {
+			path:                  "/stats/books/without-authors",
+			expectedStatus:        http.StatusOK,
+			expectedBodyToContain: `{"id":4,"name":"Beowulf","publication_year":1000}`,
+		},
+		"success on get books without authors (csv)": {
+			path:                  "/stats/books/without-authors?format=csv",
+			expectedStatus:        http.StatusOK,
+			expectedBodyToContain: "id,name,publication_year\n4,Beowulf,1000\n",
+		},
+		"error on get books without authors (service)": {
+			path:               "/stats/books/without-authors",
+			expectedErrorOnGet: errors.New("error occurred"),
+			expectedStatus:     http.StatusInternalServerError,
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			statsServiceMock := new(statsservicemock.StatsServiceMock)
+			statsServiceMock.On("GetBooksWithoutAuthors", dtos.Pagination{}).Return(dtos.BooksWithoutAuthorsResponse{
+				Books:      []dtos.StatsBook{{Id: 4, Name: "Beowulf", PublicationYear: 1000}},
+				Pagination: dtos.Pagination{Page: 1, Limit: 10},
+			}, tc.expectedErrorOnGet)
+
+			statsControllerTest := statsController{statsService: statsServiceMock}
+
+			request, _ := http.NewRequest("GET", tc.path, nil)
+			recorder := httptest.NewRecorder()
+			e := echo.New()
+			e.GET("/stats/books/without-authors", statsControllerTest.GetBooksWithoutAuthors)
+			e.ServeHTTP(recorder, request)
+
+			require.Equal(t, tc.expectedStatus, recorder.Code)
+			require.Contains(t, recorder.Body.String(), tc.expectedBodyToContain)
+		})
+	}
+}
+
+func TestGetAuthorsWithoutBooks(t *testing.T) {
+	tests := map[string]struct {
+		path                  string
+		expectedErrorOnGet    error
+		expectedStatus        int
+		expectedBodyToContain string
+	}{
+		"success on get authors without books": {
+			path:                  "/stats/authors/without-books",
+			expectedStatus:        http.StatusOK,
+			expectedBodyToContain: `{"id":8,"name":"Bachman, Richard"}`,
+		},
+		"success on get authors without books (csv)": {
+			path:                  "/stats/authors/without-books?format=csv",
+			expectedStatus:        http.StatusOK,
+			expectedBodyToContain: "id,name\n8,\"Bachman, Richard\"\n",
+		},
+		"error on get authors without books (service)": {
+			path:               "/stats/authors/without-books",
+			expectedErrorOnGet: errors.New("error occurred"),
+			expectedStatus:     http.StatusInternalServerError,
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			statsServiceMock := new(statsservicemock.StatsServiceMock)
+			statsServiceMock.On("GetAuthorsWithoutBooks", dtos.Pagination{}).Return(dtos.AuthorsWithoutBooksResponse{
+				Authors:    []dtos.StatsAuthor{{Id: 8, Name: "Bachman, Richard"}},
+				Pagination: dtos.Pagination{Page: 1, Limit: 10},
+			}, tc.expectedErrorOnGet)
+
+			statsControllerTest := statsController{statsService: statsServiceMock}
+
+			request, _ := http.NewRequest("GET", tc.path, nil)
+			recorder := httptest.NewRecorder()
+			e := echo.New()
+			e.GET("/stats/authors/without-books", statsControllerTest.GetAuthorsWithoutBooks)
+			e.ServeHTTP(recorder, request)
+
+			require.Equal(t, tc.expectedStatus, recorder.Code)
+			require.Contains(t, recorder.Body.String(), tc.expectedBodyToContain)
+		})
+	}
+}
+
+func TestGetImportStats(t *testing.T) {
+	tests := map[string]struct {
+		path                  string
+		expectedErrorOnGet    error
+		expectedStatus        int
+		expectedBodyToContain string
+	}{
+		"success on get import stats": {
+			path:                  "/stats/imports?kind=authors&interval=week",
+			expectedStatus:        http.StatusOK,
+			expectedBodyToContain: `{"period":"2022-10-03T00:00:00Z","runs":2,"added":120}`,
+		},
+		"success on get import stats (csv)": {
+			path:                  "/stats/imports?kind=authors&interval=week&format=csv",
+			expectedStatus:        http.StatusOK,
+			expectedBodyToContain: "week,runs,added\n2022-10-03,2,120\n",
+		},
+		"error on get import stats (invalid interval)": {
+			path:               "/stats/imports?kind=authors&interval=week",
+			expectedErrorOnGet: utils.ErrInvalidStatsInterval,
+			expectedStatus:     http.StatusBadRequest,
+		},
+		"error on get import stats (service)": {
+			path:               "/stats/imports?kind=authors&interval=week",
+			expectedErrorOnGet: errors.New("error occurred"),
+			expectedStatus:     http.StatusInternalServerError,
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			statsServiceMock := new(statsservicemock.StatsServiceMock)
+			statsServiceMock.On("GetImportStats", dtos.GetImportStatsFilter{Kind: "authors", Interval: "week"}).Return(dtos.ImportStatsResponse{
+				Kind:     "authors",
+				Interval: "week",
+				Volumes:  []dtos.ImportVolume{{Period: time.Date(2022, 10, 3, 0, 0, 0, 0, time.UTC), Runs: 2, Added: 120}},
+			}, tc.expectedErrorOnGet)
+
+			statsControllerTest := statsController{statsService: statsServiceMock}
+
+			request, _ := http.NewRequest("GET", tc.path, nil)
+			recorder := httptest.NewRecorder()
+			e := echo.New()
+			e.GET("/stats/imports", statsControllerTest.GetImportStats)
+			e.ServeHTTP(recorder, request)
+
+			require.Equal(t, tc.expectedStatus, recorder.Code)
+			require.Contains(t, recorder.Body.String(), tc.expectedBodyToContain)
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package controllers
+
+import (
+	"fmt"
+	"github/brunojoenk/golang-test/models/dtos"
+	tagservice "github/brunojoenk/golang-test/services/tag"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+type ITagController interface {
+	GetTags(c echo.Context) error
+}
+
+type tagController struct {
+	tagService tagservice.ITagService
+}
+
+// NewTagController Controller Constructor
+func NewTagController(db *gorm.DB) ITagController {
+	return &tagController{tagService: tagservice.NewTagService(db)}
+}
+
+// GetTags godoc
+// @Summary Show the tags with their usage counts.
+// @Description Show the tags used by books with the number of books that have each one, most used first. Useful to build tag clouds.
+// @Tags Tags
+// @Accept */*
+// @Produce json
+// @Param   page     query     int     false  "page list"     example(1) minimum(1)
+// @Param   limit     query     int     false  "page size"     example(1) minimum(1) maximum(100)
+// @Success 200 {object} dtos.TagResponseMetadata
+// @Failure 400 {object} string
+// @Failure 500 {object} string
+// @Router /tags [get]
+func (t *tagController) GetTags(c echo.Context) error {
+	var pagination dtos.Pagination
+	if err := c.Bind(&pagination); err != nil {
+		c.Logger().Warn("Error on bind query to get tags: %s", err.Error())
+		return c.JSON(http.StatusBadRequest, fmt.Sprintf("Invalid query parameters: %s", err.Error()))
+	}
+
+	tags, err := t.tagService.GetTags(pagination)
+	if err != nil {
+		c.Logger().Error("Error on get tags: %s", err.Error())
+		return c.JSON(http.StatusInternalServerError, "Error on get tags. Please, contact system admin")
+	}
+
+	return c.JSON(http.StatusOK, tags)
+}
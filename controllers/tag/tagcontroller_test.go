@@ -0,0 +1,56 @@
+package controllers
+
+import (
+	"errors"
+	"github/brunojoenk/golang-test/models/dtos"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	tagservicemock "github/brunojoenk/golang-test/services/tag/mock"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetTags(t *testing.T) {
+	tests := map[string]struct {
+		query              string
+		expectedErrorOnGet error
+		expectedStatus     int
+	}{
+		"success on get tags": {
+			query:          "?page=1&limit=20",
+			expectedStatus: http.StatusOK,
+		},
+		"error on get tags (query)": {
+			query:          "?limit=a",
+			expectedStatus: http.StatusBadRequest,
+		},
+		"error on get tags (service)": {
+			query:              "?page=1&limit=20",
+			expectedErrorOnGet: errors.New("error occurred"),
+			expectedStatus:     http.StatusInternalServerError,
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			tagsResponse := dtos.TagResponseMetadata{Tags: []dtos.TagResponse{{Name: "dystopia", Count: 3}}, Pagination: dtos.Pagination{Page: 1, Limit: 20}}
+			tagServiceMock := new(tagservicemock.TagServiceMock)
+			tagServiceMock.On("GetTags", dtos.Pagination{Page: 1, Limit: 20}).Return(tagsResponse, tc.expectedErrorOnGet)
+
+			tagControllerTest := tagController{tagService: tagServiceMock}
+
+			request, _ := http.NewRequest("GET", "/tags"+tc.query, nil)
+			recorder := httptest.NewRecorder()
+			e := echo.New()
+			e.GET("/tags", tagControllerTest.GetTags)
+			e.ServeHTTP(recorder, request)
+
+			require.Equal(t, tc.expectedStatus, recorder.Code)
+			if tc.expectedStatus == http.StatusOK {
+				require.Contains(t, recorder.Body.String(), `{"name":"dystopia","count":3}`)
+			}
+		})
+	}
+}
@@ -0,0 +1,133 @@
+package controllers
+
+import (
+	"fmt"
+	"github/brunojoenk/golang-test/models/dtos"
+	workservice "github/brunojoenk/golang-test/services/work"
+	"github/brunojoenk/golang-test/utils"
+	"net/http"
+	"strconv"
+
+	"github.com/pkg/errors"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+type IWorkController interface {
+	GetAllWorks(c echo.Context) error
+	GetWork(c echo.Context) error
+	GetWorkEditions(c echo.Context) error
+}
+
+type workController struct {
+	workService workservice.IWorkService
+}
+
+// NewWorkController Controller Constructor
+func NewWorkController(db *gorm.DB) IWorkController {
+	return &workController{workService: workservice.NewWorkService(db)}
+}
+
+// GetAllWorks godoc
+// @Summary Show all works with paginations.
+// @Description Show all works with paginations. A work groups the editions of the same book.
+// @Tags Works
+// @Accept */*
+// @Produce json
+// @Param   name     query     string     false  "search work by name"     example(string)
+// @Param   page     query     int     false  "page list"     example(1) minimum(1)
+// @Param   limit     query     int     false  "page size"     example(1) minimum(1) maximum(100)
+// @Success 200 {object} dtos.WorkResponseMetadata
+// @Failure 400 {object} string
+// @Failure 500 {object} string
+// @Router /works [get]
+func (w *workController) GetAllWorks(c echo.Context) error {
+	var filter dtos.GetWorksFilter
+	err := c.Bind(&filter)
+	if err != nil {
+		c.Logger().Warn("Error on bind query to filter all works: %s", err.Error())
+		return c.JSON(http.StatusBadRequest, fmt.Sprintf("Invalid query parameters: %s", err.Error()))
+	}
+
+	worksResponse, err := w.workService.GetAllWorks(filter)
+	if err != nil {
+		return w.errorResponse(c, "get all", err)
+	}
+
+	return c.JSON(http.StatusOK, worksResponse)
+}
+
+// GetWork godoc
+// @Summary Get a work.
+// @Description Get a work with its authors.
+// @Tags Works
+// @Accept */*
+// @Produce json
+// @Param id   path int true "Work ID"
+// @Success 200 {object} dtos.WorkResponse
+// @Failure 400 {object} string
+// @Failure 404 {object} string
+// @Failure 500 {object} string
+// @Router /works/{id} [get]
+func (w *workController) GetWork(c echo.Context) error {
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Logger().Warn("Error on parse parameters id on get work %s", err.Error())
+		return c.JSON(http.StatusBadRequest, "Invalid query parameter id")
+	}
+
+	work, err := w.workService.GetWork(id)
+	if err != nil {
+		return w.errorResponse(c, "get", err)
+	}
+
+	return c.JSON(http.StatusOK, work)
+}
+
+// GetWorkEditions godoc
+// @Summary Show the editions of a work.
+// @Description Show the books of a work with paginations, from the oldest publication year to the newest.
+// @Tags Works
+// @Accept */*
+// @Produce json
+// @Param Accept-Language header string false "preferred languages of the book titles, e.g. pt-BR, en;q=0.8"
+// @Param id   path int true "Work ID"
+// @Param   page     query     int     false  "page list"     example(1) minimum(1)
+// @Param   limit     query     int     false  "page size"     example(1) minimum(1) maximum(100)
+// @Success 200 {object} dtos.BookResponseMetadata
+// @Failure 400 {object} string
+// @Failure 404 {object} string
+// @Failure 500 {object} string
+// @Router /works/{id}/editions [get]
+func (w *workController) GetWorkEditions(c echo.Context) error {
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Logger().Warn("Error on parse parameters id on get work editions %s", err.Error())
+		return c.JSON(http.StatusBadRequest, "Invalid query parameter id")
+	}
+
+	var pagination dtos.Pagination
+	if err := c.Bind(&pagination); err != nil {
+		c.Logger().Warn("Error on bind query to get work editions: %s", err.Error())
+		return c.JSON(http.StatusBadRequest, fmt.Sprintf("Invalid query parameters: %s", err.Error()))
+	}
+
+	editions, err := w.workService.GetWorkEditions(id, pagination)
+	if err != nil {
+		return w.errorResponse(c, "get editions of", err)
+	}
+
+	editions.Localize(c.Request().Header.Get("Accept-Language"))
+	return c.JSON(http.StatusOK, editions)
+}
+
+func (w *workController) errorResponse(c echo.Context, action string, err error) error {
+	if errors.Is(err, utils.ErrWorkIdNotFound) {
+		return c.JSON(http.StatusNotFound, err.Error())
+	}
+	c.Logger().Error("Error on %s work %s", action, err.Error())
+	return c.JSON(http.StatusInternalServerError, fmt.Sprintf("Error on %s work. Please contact system admin", action))
+}
@@ -0,0 +1,139 @@
+package controllers
+
+import (
+	"errors"
+	"github/brunojoenk/golang-test/models/dtos"
+	"github/brunojoenk/golang-test/utils"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	workservicemock "github/brunojoenk/golang-test/services/work/mock"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetAllWorks(t *testing.T) {
+	worksResponse := dtos.WorkResponseMetadata{
+		Works:      []dtos.WorkResponse{{Id: 1, Name: "The Hobbit", Authors: "J. R. R. Tolkien"}},
+		Pagination: dtos.Pagination{Page: 1, Limit: 10},
+	}
+	workServiceMock := new(workservicemock.WorkServiceMock)
+	workServiceMock.On("GetAllWorks", dtos.GetWorksFilter{Name: "hobbit"}).Return(worksResponse, nil)
+	workServiceMock.On("GetAllWorks", dtos.GetWorksFilter{Name: "error"}).Return(dtos.WorkResponseMetadata{}, errors.New("error occurred"))
+
+	workControllerTest := workController{workService: workServiceMock}
+	e := echo.New()
+	e.GET("/works", workControllerTest.GetAllWorks)
+
+	request, _ := http.NewRequest("GET", "/works?name=hobbit", nil)
+	recorder := httptest.NewRecorder()
+	e.ServeHTTP(recorder, request)
+
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Contains(t, recorder.Body.String(), `"name":"The Hobbit"`)
+
+	request, _ = http.NewRequest("GET", "/works?name=error", nil)
+	recorder = httptest.NewRecorder()
+	e.ServeHTTP(recorder, request)
+
+	require.Equal(t, http.StatusInternalServerError, recorder.Code)
+}
+
+func TestGetWork(t *testing.T) {
+	tests := map[string]struct {
+		id                 string
+		expectedErrorOnGet error
+		expectedStatus     int
+	}{
+		"success on get work": {
+			id:             "1",
+			expectedStatus: http.StatusOK,
+		},
+		"error on get work (invalid id)": {
+			id:             "a",
+			expectedStatus: http.StatusBadRequest,
+		},
+		"error on get work (not found)": {
+			id:                 "1",
+			expectedErrorOnGet: utils.ErrWorkIdNotFound,
+			expectedStatus:     http.StatusNotFound,
+		},
+		"error on get work (service)": {
+			id:                 "1",
+			expectedErrorOnGet: errors.New("error occurred"),
+			expectedStatus:     http.StatusInternalServerError,
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			workServiceMock := new(workservicemock.WorkServiceMock)
+			workServiceMock.On("GetWork", 1).Return(dtos.WorkResponse{Id: 1, Name: "The Hobbit"}, tc.expectedErrorOnGet)
+
+			workControllerTest := workController{workService: workServiceMock}
+
+			request, _ := http.NewRequest("GET", "/works/"+tc.id, nil)
+			recorder := httptest.NewRecorder()
+			e := echo.New()
+			e.GET("/works/:id", workControllerTest.GetWork)
+			e.ServeHTTP(recorder, request)
+
+			require.Equal(t, tc.expectedStatus, recorder.Code)
+		})
+	}
+}
+
+func TestGetWorkEditions(t *testing.T) {
+	tests := map[string]struct {
+		url                        string
+		expectedErrorOnGetEditions error
+		expectedStatus             int
+	}{
+		"success on get work editions": {
+			url:            "/works/1/editions?page=2&limit=5",
+			expectedStatus: http.StatusOK,
+		},
+		"error on get work editions (invalid id)": {
+			url:            "/works/a/editions",
+			expectedStatus: http.StatusBadRequest,
+		},
+		"error on get work editions (invalid page)": {
+			url:            "/works/1/editions?page=a",
+			expectedStatus: http.StatusBadRequest,
+		},
+		"error on get work editions (not found)": {
+			url:                        "/works/1/editions?page=2&limit=5",
+			expectedErrorOnGetEditions: utils.ErrWorkIdNotFound,
+			expectedStatus:             http.StatusNotFound,
+		},
+		"error on get work editions (service)": {
+			url:                        "/works/1/editions?page=2&limit=5",
+			expectedErrorOnGetEditions: errors.New("error occurred"),
+			expectedStatus:             http.StatusInternalServerError,
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			editions := dtos.BookResponseMetadata{
+				Books:      []dtos.BookResponse{{Id: 3, Name: "The Hobbit", PublicationYear: 1937, WorkId: 1}},
+				Pagination: dtos.Pagination{Page: 2, Limit: 5},
+			}
+			workServiceMock := new(workservicemock.WorkServiceMock)
+			workServiceMock.On("GetWorkEditions", 1, dtos.Pagination{Page: 2, Limit: 5}).Return(editions, tc.expectedErrorOnGetEditions)
+
+			workControllerTest := workController{workService: workServiceMock}
+
+			request, _ := http.NewRequest("GET", tc.url, nil)
+			recorder := httptest.NewRecorder()
+			e := echo.New()
+			e.GET("/works/:id/editions", workControllerTest.GetWorkEditions)
+			e.ServeHTTP(recorder, request)
+
+			require.Equal(t, tc.expectedStatus, recorder.Code)
+			if tc.expectedStatus == http.StatusOK {
+				require.Contains(t, recorder.Body.String(), `"work_id":1`)
+			}
+		})
+	}
+}
@@ -1,8 +1,32 @@
 package handlers
 
 import (
+	"github/brunojoenk/golang-test/config"
+	apikeycontroller "github/brunojoenk/golang-test/controllers/apikey"
 	authorcontroller "github/brunojoenk/golang-test/controllers/author"
 	bookcontroller "github/brunojoenk/golang-test/controllers/book"
+	bookfilecontroller "github/brunojoenk/golang-test/controllers/bookfile"
+	copycontroller "github/brunojoenk/golang-test/controllers/copy"
+	covercontroller "github/brunojoenk/golang-test/controllers/cover"
+	genrecontroller "github/brunojoenk/golang-test/controllers/genre"
+	holdcontroller "github/brunojoenk/golang-test/controllers/hold"
+	ledgercontroller "github/brunojoenk/golang-test/controllers/ledger"
+	loancontroller "github/brunojoenk/golang-test/controllers/loan"
+	outboxcontroller "github/brunojoenk/golang-test/controllers/outbox"
+	patroncontroller "github/brunojoenk/golang-test/controllers/patron"
+	publishercontroller "github/brunojoenk/golang-test/controllers/publisher"
+	readinglistcontroller "github/brunojoenk/golang-test/controllers/readinglist"
+	recommendationcontroller "github/brunojoenk/golang-test/controllers/recommendation"
+	reviewcontroller "github/brunojoenk/golang-test/controllers/review"
+	seriescontroller "github/brunojoenk/golang-test/controllers/series"
+	statscontroller "github/brunojoenk/golang-test/controllers/stats"
+	tagcontroller "github/brunojoenk/golang-test/controllers/tag"
+	workcontroller "github/brunojoenk/golang-test/controllers/work"
+	"github/brunojoenk/golang-test/middlewares"
+	"github/brunojoenk/golang-test/models/dtos"
+	apikeyservice "github/brunojoenk/golang-test/services/apikey"
+	idempotencyservice "github/brunojoenk/golang-test/services/idempotency"
+	"github/brunojoenk/golang-test/storage"
 
 	_ "github/brunojoenk/golang-test/docs"
 
@@ -14,23 +38,183 @@ import (
 )
 
 type Handler struct {
-	authorController authorcontroller.IAuthorController
-	bookController   bookcontroller.IBookController
+	authorController         authorcontroller.IAuthorController
+	bookController           bookcontroller.IBookController
+	copyController           copycontroller.ICopyController
+	coverController          covercontroller.ICoverController
+	bookFileController       bookfilecontroller.IBookFileController
+	publisherController      publishercontroller.IPublisherController
+	genreController          genrecontroller.IGenreController
+	seriesController         seriescontroller.ISeriesController
+	tagController            tagcontroller.ITagController
+	workController           workcontroller.IWorkController
+	patronController         patroncontroller.IPatronController
+	loanController           loancontroller.ILoanController
+	holdController           holdcontroller.IHoldController
+	reviewController         reviewcontroller.IReviewController
+	readingListController    readinglistcontroller.IReadingListController
+	recommendationController recommendationcontroller.IRecommendationController
+	statsController          statscontroller.IStatsController
+	ledgerController         ledgercontroller.ILedgerController
+	outboxController         outboxcontroller.IOutboxController
+	apiKeyController         apikeycontroller.IApiKeyController
+	apiKeyAuth               echo.MiddlewareFunc
+	ipRateLimit              echo.MiddlewareFunc
+	rateLimit                echo.MiddlewareFunc
+	importRateLimit          echo.MiddlewareFunc
+	idempotency              echo.MiddlewareFunc
 }
 
-func New(db *gorm.DB) *Handler {
-	return &Handler{authorController: authorcontroller.NewAuthorController(db), bookController: bookcontroller.NewBookController(db)}
+func New(db *gorm.DB, cfg *config.Config, store storage.BlobStore) *Handler {
+	rateLimitStore := middlewares.NewMemoryRateLimitStore()
+	return &Handler{
+		authorController:         authorcontroller.NewAuthorController(db),
+		bookController:           bookcontroller.NewBookController(db, cfg, store),
+		copyController:           copycontroller.NewCopyController(db, cfg),
+		coverController:          covercontroller.NewCoverController(db, cfg, store),
+		bookFileController:       bookfilecontroller.NewBookFileController(db, cfg, store),
+		publisherController:      publishercontroller.NewPublisherController(db),
+		genreController:          genrecontroller.NewGenreController(db),
+		seriesController:         seriescontroller.NewSeriesController(db),
+		tagController:            tagcontroller.NewTagController(db),
+		workController:           workcontroller.NewWorkController(db),
+		patronController:         patroncontroller.NewPatronController(db),
+		loanController:           loancontroller.NewLoanController(db, cfg),
+		holdController:           holdcontroller.NewHoldController(db, cfg),
+		reviewController:         reviewcontroller.NewReviewController(db),
+		readingListController:    readinglistcontroller.NewReadingListController(db),
+		recommendationController: recommendationcontroller.NewRecommendationController(db),
+		statsController:          statscontroller.NewStatsController(db, cfg),
+		ledgerController:         ledgercontroller.NewLedgerController(db, cfg),
+		outboxController:         outboxcontroller.NewOutboxController(db, cfg),
+		apiKeyController:         apikeycontroller.NewApiKeyController(db, cfg),
+		apiKeyAuth:               middlewares.ApiKeyAuth(apikeyservice.NewApiKeyService(db, cfg), cfg.ApiKeyRequired),
+		ipRateLimit: middlewares.IpRateLimiter(rateLimitStore, "ip",
+			middlewares.RateLimit{PerMinute: cfg.IpRateLimitPerMinute, Burst: cfg.IpRateLimitBurst}),
+		rateLimit: middlewares.RateLimiter(rateLimitStore, "default",
+			middlewares.RateLimit{PerMinute: cfg.RateLimitPerMinute, Burst: cfg.RateLimitBurst}),
+		importRateLimit: middlewares.RateLimiter(rateLimitStore, "import",
+			middlewares.RateLimit{PerMinute: cfg.ImportRateLimitPerMinute, Burst: cfg.ImportRateLimitBurst}),
+		idempotency: middlewares.Idempotency(idempotencyservice.NewIdempotencyService(db, cfg)),
+	}
 }
 
 func (h *Handler) HandleControllers(e *echo.Echo) {
-	e.POST("/authors/import", h.authorController.ReadCsvHandler)
+	// Requests are throttled per client ip before their api key is checked, then per api key
+	e.Use(h.ipRateLimit)
+	e.Use(h.apiKeyAuth)
+	e.Use(h.rateLimit)
+
+	e.POST("/authors/import", h.authorController.ReadCsvHandler, h.importRateLimit)
 	e.GET("/authors", h.authorController.GetAllAuthors)
+	e.GET("/authors/duplicates", h.authorController.GetDuplicateAuthors)
+	e.POST("/authors/:id/merge", h.authorController.MergeAuthors)
+	e.GET("/authors/:id/aliases", h.authorController.GetAuthorAliases)
+	e.POST("/authors/:id/aliases", h.authorController.CreateAuthorAlias)
+	e.PUT("/authors/:id/aliases/:aliasId", h.authorController.UpdateAuthorAlias)
+	e.DELETE("/authors/:id/aliases/:aliasId", h.authorController.DeleteAuthorAlias)
 
-	e.POST("/book", h.bookController.CreateBook)
+	e.POST("/book", h.bookController.CreateBook, h.idempotency)
 	e.GET("/books", h.bookController.GetAllBooks)
 	e.GET("/book/:id", h.bookController.GetBook)
 	e.PUT("/book/:id", h.bookController.UpdateBook)
 	e.DELETE("/book/:id", h.bookController.DeleteBook)
+	e.GET("/books/duplicates", h.bookController.GetDuplicateBooks)
+	e.GET("/books/isbn/:isbn", h.bookController.GetBookByIsbn)
+	e.POST("/books/:id/merge", h.bookController.MergeBooks)
+	e.POST("/books/:id/tags", h.bookController.AddBookTags)
+	e.DELETE("/books/:id/tags/:tag", h.bookController.RemoveBookTag)
+	e.GET("/books/:id/related", h.recommendationController.GetRelatedBooks)
+
+	e.PUT("/books/:id/cover", h.coverController.SaveCover)
+	e.GET("/books/:id/cover", h.coverController.GetCover)
+	e.DELETE("/books/:id/cover", h.coverController.DeleteCover)
+
+	e.POST("/books/:id/files", h.bookFileController.CreateBookFile)
+	e.GET("/books/:id/files", h.bookFileController.GetBookFiles)
+	e.GET("/books/:id/files/:fileId", h.bookFileController.GetBookFile)
+	e.DELETE("/books/:id/files/:fileId", h.bookFileController.DeleteBookFile)
+	e.GET("/books/:id/files/:fileId/link", h.bookFileController.GetDownloadLink)
+	e.GET("/downloads/:fileId", h.bookFileController.DownloadBookFile)
+
+	e.POST("/books/:id/copies", h.copyController.CreateCopy)
+	e.GET("/books/:id/copies", h.copyController.GetBookCopies)
+	e.GET("/books/:id/copies/:copyId", h.copyController.GetCopy)
+	e.PUT("/books/:id/copies/:copyId", h.copyController.UpdateCopy)
+	e.DELETE("/books/:id/copies/:copyId", h.copyController.DeleteCopy)
+
+	e.POST("/publisher", h.publisherController.CreatePublisher)
+	e.GET("/publishers", h.publisherController.GetAllPublishers)
+	e.GET("/publisher/:id", h.publisherController.GetPublisher)
+	e.PUT("/publisher/:id", h.publisherController.UpdatePublisher)
+	e.DELETE("/publisher/:id", h.publisherController.DeletePublisher)
+	e.GET("/publishers/:id/books", h.publisherController.GetPublisherBooks)
+
+	e.POST("/genre", h.genreController.CreateGenre)
+	e.GET("/genres", h.genreController.GetGenreTree)
+	e.PUT("/genre/:id", h.genreController.UpdateGenre)
+	e.DELETE("/genre/:id", h.genreController.DeleteGenre)
+
+	e.POST("/series", h.seriesController.CreateSeries)
+	e.GET("/series", h.seriesController.GetAllSeries)
+	e.GET("/series/:id", h.seriesController.GetSeries)
+	e.PUT("/series/:id", h.seriesController.UpdateSeries)
+	e.DELETE("/series/:id", h.seriesController.DeleteSeries)
+
+	e.GET("/tags", h.tagController.GetTags)
+
+	e.GET("/works", h.workController.GetAllWorks)
+	e.GET("/works/:id", h.workController.GetWork)
+	e.GET("/works/:id/editions", h.workController.GetWorkEditions)
+
+	e.POST("/patrons", h.patronController.CreatePatron)
+	e.GET("/patrons", h.patronController.GetAllPatrons)
+	e.GET("/patrons/:id", h.patronController.GetPatron)
+	e.PUT("/patrons/:id", h.patronController.UpdatePatron)
+	e.DELETE("/patrons/:id", h.patronController.DeletePatron)
+	e.GET("/patrons/:id/loans", h.loanController.GetPatronLoans)
+	e.GET("/patrons/:id/ledger", h.ledgerController.GetPatronLedger)
+	e.POST("/patrons/:id/payments", h.ledgerController.RecordPayment)
+	e.POST("/patrons/:id/waivers", h.ledgerController.RecordWaiver)
+
+	e.POST("/loans", h.loanController.CheckOut)
+	e.GET("/loans/:id", h.loanController.GetLoan)
+	e.POST("/loans/:id/checkin", h.loanController.CheckIn)
+	e.POST("/loans/:id/renew", h.loanController.Renew)
+
+	e.POST("/books/:id/holds", h.holdController.PlaceHold)
+	e.GET("/books/:id/holds", h.holdController.GetBookHolds)
+	e.GET("/holds/:id", h.holdController.GetHold)
+	e.POST("/holds/:id/cancel", h.holdController.CancelHold)
+
+	e.POST("/books/:id/reviews", h.reviewController.CreateReview)
+	e.GET("/books/:id/reviews", h.reviewController.GetBookReviews)
+	e.GET("/books/:id/reviews/:reviewId", h.reviewController.GetReview)
+	e.PUT("/books/:id/reviews/:reviewId", h.reviewController.UpdateReview)
+	e.DELETE("/books/:id/reviews/:reviewId", h.reviewController.DeleteReview)
+
+	e.POST("/lists", h.readingListController.CreateList)
+	e.GET("/lists", h.readingListController.GetLists)
+	e.GET("/lists/:id", h.readingListController.GetList)
+	e.PUT("/lists/:id", h.readingListController.UpdateList)
+	e.DELETE("/lists/:id", h.readingListController.DeleteList)
+	e.POST("/lists/:id/books", h.readingListController.AddBook)
+	e.PUT("/lists/:id/books/:bookId", h.readingListController.UpdateBookNote)
+	e.DELETE("/lists/:id/books/:bookId", h.readingListController.RemoveBook)
+	e.PUT("/lists/:id/order", h.readingListController.ReorderBooks)
+	e.GET("/lists/:id/export", h.readingListController.ExportList)
+
+	e.GET("/stats/books/publication-years", h.statsController.GetPublicationYearStats)
+	e.GET("/stats/books/without-authors", h.statsController.GetBooksWithoutAuthors)
+	e.GET("/stats/authors/prolific", h.statsController.GetProlificAuthors)
+	e.GET("/stats/authors/without-books", h.statsController.GetAuthorsWithoutBooks)
+	e.GET("/stats/imports", h.statsController.GetImportStats)
+
+	admin := middlewares.RequireScope(dtos.ScopeAdmin)
+	e.POST("/apikey", h.apiKeyController.CreateApiKey, admin)
+	e.GET("/apikeys", h.apiKeyController.GetAllApiKeys, admin)
+	e.DELETE("/apikey/:id", h.apiKeyController.RevokeApiKey, admin)
+	e.GET("/outbox", h.outboxController.GetOutbox, admin)
 
 	e.GET("/swagger/*", echoSwagger.WrapHandler)
 }
@@ -1,11 +1,21 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"github/brunojoenk/golang-test/config"
 	"github/brunojoenk/golang-test/database"
 	"github/brunojoenk/golang-test/handlers"
 	"github/brunojoenk/golang-test/models/entities"
+	"github/brunojoenk/golang-test/scheduler"
+	authorservice "github/brunojoenk/golang-test/services/author"
+	bookservice "github/brunojoenk/golang-test/services/book"
+	holdservice "github/brunojoenk/golang-test/services/hold"
+	idempotencyservice "github/brunojoenk/golang-test/services/idempotency"
+	ledgerservice "github/brunojoenk/golang-test/services/ledger"
+	notificationservice "github/brunojoenk/golang-test/services/notification"
+	"github/brunojoenk/golang-test/storage"
+	"time"
 
 	_ "github/brunojoenk/golang-test/docs"
 
@@ -36,6 +46,9 @@ func main() {
 	e.Use(middleware.Recover())
 
 	cfg := config.New()
+	if err := cfg.Validate(); err != nil {
+		e.Logger.Fatal("Error on validate config: ", err.Error())
+	}
 
 	database, err := database.NewPsqlDB(cfg)
 	if err != nil {
@@ -43,12 +56,53 @@ func main() {
 	}
 
 	// Create/update tables on database
-	err = database.AutoMigrate(&entities.Author{}, &entities.Book{})
+	err = database.AutoMigrate(&entities.Author{}, &entities.AuthorAlias{}, &entities.Publisher{}, &entities.Genre{}, &entities.Series{}, &entities.Tag{}, &entities.Work{}, &entities.Book{}, &entities.BookTitle{}, &entities.Copy{}, &entities.Patron{}, &entities.Loan{}, &entities.Hold{}, &entities.LedgerEntry{}, &entities.OutboxMessage{}, &entities.Review{}, &entities.BookCover{}, &entities.BookFile{}, &entities.ReadingList{}, &entities.ReadingListEntry{}, &entities.AuthorBook{}, &entities.ImportRun{}, &entities.ApiKey{}, &entities.IdempotencyKey{})
 	if err != nil {
 		e.Logger.Fatal("Error on execute migrate: ", err.Error())
 	}
 
-	h := handlers.New(database)
+	// Cover images, book files and other uploads live outside the database
+	store, err := storage.NewBlobStore(cfg)
+	if err != nil {
+		e.Logger.Fatal("Error on create blob store: ", err.Error())
+	}
+
+	// Change the keys AutoMigrate leaves as they are, and fill derived columns of rows created before they existed
+	err = bookservice.NewBookService(database, cfg, store).AddRoleToContributorsKey()
+	if err != nil {
+		e.Logger.Fatal("Error on add role to contributors key: ", err.Error())
+	}
+	err = bookservice.NewBookService(database, cfg, store).BackfillNormalizedNames()
+	if err != nil {
+		e.Logger.Fatal("Error on backfill book normalized names: ", err.Error())
+	}
+	err = authorservice.NewAuthorService(database).BackfillNormalizedNames()
+	if err != nil {
+		e.Logger.Fatal("Error on backfill author normalized names: ", err.Error())
+	}
+	err = bookservice.NewBookService(database, cfg, store).BackfillWorks()
+	if err != nil {
+		e.Logger.Fatal("Error on backfill book works: ", err.Error())
+	}
+
+	// Assess fines, let go of expired hold pickups, queue reminders, deliver them and delete expired idempotency keys in the background
+	sender, err := notificationservice.NewSender(cfg)
+	if err != nil {
+		e.Logger.Fatal("Error on create notification sender: ", err.Error())
+	}
+	ledgerService := ledgerservice.NewLedgerService(database, cfg)
+	holdService := holdservice.NewHoldService(database, cfg)
+	notificationService := notificationservice.NewNotificationService(database, cfg, sender)
+	idempotencyService := idempotencyservice.NewIdempotencyService(database, cfg)
+	go scheduler.New(time.Duration(cfg.SchedulerIntervalMinutes)*time.Minute,
+		scheduler.Job{Name: "assess fines", Run: ledgerService.AssessFines},
+		scheduler.Job{Name: "expire holds", Run: holdService.ExpireHolds},
+		scheduler.Job{Name: "queue reminders", Run: notificationService.QueueReminders},
+		scheduler.Job{Name: "deliver outbox", Run: notificationService.DeliverOutbox},
+		scheduler.Job{Name: "delete expired idempotency keys", Run: idempotencyService.DeleteExpiredKeys},
+	).Run(context.Background())
+
+	h := handlers.New(database, cfg, store)
 	h.HandleControllers(e)
 
 	// Start server
@@ -0,0 +1,85 @@
+package middlewares
+
+import (
+	"github/brunojoenk/golang-test/models/dtos"
+	apikeyservice "github/brunojoenk/golang-test/services/apikey"
+	"github/brunojoenk/golang-test/utils"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+)
+
+const (
+	API_KEY_HEADER        = "X-API-Key"
+	API_KEY_PRINCIPAL_KEY = "api_key_principal"
+)
+
+// ApiKeyAuth validates the X-API-Key header and stores the principal on the context.
+// Requests without the header are only rejected when required is true. Download links carry their own
+// signature and need no key.
+func ApiKeyAuth(apiKeyService apikeyservice.IApiKeyService, required bool) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if strings.HasPrefix(c.Path(), "/swagger") || strings.HasPrefix(c.Path(), "/downloads/") {
+				return next(c)
+			}
+
+			key := strings.TrimSpace(c.Request().Header.Get(API_KEY_HEADER))
+			if key == "" {
+				if required {
+					return c.JSON(http.StatusUnauthorized, "Missing api key")
+				}
+				return next(c)
+			}
+
+			principal, err := apiKeyService.Authenticate(key)
+			if err != nil {
+				if errors.Is(err, utils.ErrApiKeyInvalid) {
+					return c.JSON(http.StatusUnauthorized, err.Error())
+				}
+				c.Logger().Error("Error on authenticate api key: %s", err.Error())
+				return c.JSON(http.StatusInternalServerError, "Error on authenticate api key. Please, contact system admin")
+			}
+
+			if !principal.HasScope(scopeForMethod(c.Request().Method)) {
+				return c.JSON(http.StatusForbidden, "Api key has no scope for this operation")
+			}
+
+			c.Set(API_KEY_PRINCIPAL_KEY, principal)
+			return next(c)
+		}
+	}
+}
+
+// RequireScope rejects requests whose principal does not have the given scope
+func RequireScope(scope string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			principal, ok := GetPrincipal(c)
+			if !ok {
+				return c.JSON(http.StatusUnauthorized, "Missing api key")
+			}
+			if !principal.HasScope(scope) {
+				return c.JSON(http.StatusForbidden, "Api key has no scope for this operation")
+			}
+			return next(c)
+		}
+	}
+}
+
+// GetPrincipal returns the principal authenticated by ApiKeyAuth, if any
+func GetPrincipal(c echo.Context) (dtos.ApiKeyPrincipal, bool) {
+	principal, ok := c.Get(API_KEY_PRINCIPAL_KEY).(dtos.ApiKeyPrincipal)
+	return principal, ok
+}
+
+func scopeForMethod(method string) string {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return dtos.ScopeRead
+	default:
+		return dtos.ScopeWrite
+	}
+}
@@ -0,0 +1,144 @@
+package middlewares
+
+import (
+	"errors"
+	"github/brunojoenk/golang-test/models/dtos"
+	"github/brunojoenk/golang-test/utils"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	apikeyservicemock "github/brunojoenk/golang-test/services/apikey/mock"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApiKeyAuth(t *testing.T) {
+	tests := map[string]struct {
+		method                string
+		path                  string
+		key                   string
+		required              bool
+		principal             dtos.ApiKeyPrincipal
+		expectedErrorOnAuth   error
+		expectedStatus        int
+		expectedPrincipalName string
+	}{
+		"success without key": {
+			method:         http.MethodGet,
+			expectedStatus: http.StatusOK,
+		},
+		"success with read key": {
+			method:                http.MethodGet,
+			key:                   "gt_read",
+			principal:             dtos.ApiKeyPrincipal{Owner: "jobs", Scopes: []string{dtos.ScopeRead}},
+			expectedStatus:        http.StatusOK,
+			expectedPrincipalName: "jobs",
+		},
+		"success with write key on get": {
+			method:                http.MethodGet,
+			key:                   "gt_write",
+			principal:             dtos.ApiKeyPrincipal{Owner: "jobs", Scopes: []string{dtos.ScopeWrite}},
+			expectedStatus:        http.StatusOK,
+			expectedPrincipalName: "jobs",
+		},
+		"error when key is required": {
+			method:         http.MethodGet,
+			required:       true,
+			expectedStatus: http.StatusUnauthorized,
+		},
+		"success on download link when key is required": {
+			method:         http.MethodGet,
+			path:           "/downloads/1",
+			required:       true,
+			expectedStatus: http.StatusOK,
+		},
+		"error with invalid key": {
+			method:              http.MethodGet,
+			key:                 "gt_invalid",
+			expectedErrorOnAuth: utils.ErrApiKeyInvalid,
+			expectedStatus:      http.StatusUnauthorized,
+		},
+		"error with read key on post": {
+			method:         http.MethodPost,
+			key:            "gt_read",
+			principal:      dtos.ApiKeyPrincipal{Owner: "jobs", Scopes: []string{dtos.ScopeRead}},
+			expectedStatus: http.StatusForbidden,
+		},
+		"error on authenticate": {
+			method:              http.MethodGet,
+			key:                 "gt_read",
+			expectedErrorOnAuth: errors.New("error occurred"),
+			expectedStatus:      http.StatusInternalServerError,
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			apiKeyServiceMock := new(apikeyservicemock.ApiKeyServiceMock)
+			apiKeyServiceMock.On("Authenticate", tc.key).Return(tc.principal, tc.expectedErrorOnAuth)
+
+			var principalName string
+			e := echo.New()
+			e.Use(ApiKeyAuth(apiKeyServiceMock, tc.required))
+			handler := func(c echo.Context) error {
+				principal, _ := GetPrincipal(c)
+				principalName = principal.Owner
+				return c.NoContent(http.StatusOK)
+			}
+			e.Add(tc.method, "/books", handler)
+			e.Add(tc.method, "/downloads/:fileId", handler)
+
+			path := tc.path
+			if path == "" {
+				path = "/books"
+			}
+			request := httptest.NewRequest(tc.method, path, nil)
+			if tc.key != "" {
+				request.Header.Set(API_KEY_HEADER, tc.key)
+			}
+			recorder := httptest.NewRecorder()
+			e.ServeHTTP(recorder, request)
+
+			require.Equal(t, tc.expectedStatus, recorder.Code)
+			require.Equal(t, tc.expectedPrincipalName, principalName)
+		})
+	}
+}
+
+func TestRequireScope(t *testing.T) {
+	tests := map[string]struct {
+		principal      *dtos.ApiKeyPrincipal
+		expectedStatus int
+	}{
+		"success with admin scope": {
+			principal:      &dtos.ApiKeyPrincipal{Scopes: []string{dtos.ScopeAdmin}},
+			expectedStatus: http.StatusOK,
+		},
+		"error without principal": {
+			expectedStatus: http.StatusUnauthorized,
+		},
+		"error without admin scope": {
+			principal:      &dtos.ApiKeyPrincipal{Scopes: []string{dtos.ScopeWrite}},
+			expectedStatus: http.StatusForbidden,
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodGet, "/apikeys", nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			if tc.principal != nil {
+				c.Set(API_KEY_PRINCIPAL_KEY, *tc.principal)
+			}
+
+			handler := RequireScope(dtos.ScopeAdmin)(func(c echo.Context) error {
+				return c.NoContent(http.StatusOK)
+			})
+			handler(c)
+
+			require.Equal(t, tc.expectedStatus, rec.Code)
+		})
+	}
+}
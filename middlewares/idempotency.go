@@ -0,0 +1,109 @@
+package middlewares
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"github/brunojoenk/golang-test/models/dtos"
+	idempotencyservice "github/brunojoenk/golang-test/services/idempotency"
+	"github/brunojoenk/golang-test/utils"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+)
+
+const (
+	IDEMPOTENCY_KEY_HEADER      = "Idempotency-Key"
+	IDEMPOTENCY_REPLAYED_HEADER = "Idempotent-Replayed"
+	IDEMPOTENCY_KEY_MAX_SIZE    = 255
+)
+
+type bodyRecorder struct {
+	http.ResponseWriter
+	body *bytes.Buffer
+}
+
+func (b *bodyRecorder) Write(p []byte) (int, error) {
+	b.body.Write(p)
+	return b.ResponseWriter.Write(p)
+}
+
+// Idempotency stores the first response of a request sent with the Idempotency-Key header and
+// replays it for retries with the same body. Keys are scoped by client, method and route
+func Idempotency(idempotencyService idempotencyservice.IIdempotencyService) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			key := strings.TrimSpace(c.Request().Header.Get(IDEMPOTENCY_KEY_HEADER))
+			if key == "" {
+				return next(c)
+			}
+			if len(key) > IDEMPOTENCY_KEY_MAX_SIZE {
+				return c.JSON(http.StatusBadRequest, fmt.Sprintf("Idempotency key must have at most %d characters", IDEMPOTENCY_KEY_MAX_SIZE))
+			}
+
+			body, err := io.ReadAll(c.Request().Body)
+			if err != nil {
+				c.Logger().Warn("Error on read body to check idempotency key: %s", err.Error())
+				return c.JSON(http.StatusBadRequest, "Invalid request body")
+			}
+			c.Request().Body = io.NopCloser(bytes.NewReader(body))
+
+			scope := fmt.Sprintf("%s:%s %s", clientKey(c), c.Request().Method, c.Path())
+			hash := sha256.Sum256(append([]byte(c.Request().URL.String()+"\n"), body...))
+
+			stored, replay, err := idempotencyService.Begin(scope, key, hex.EncodeToString(hash[:]))
+			if err != nil {
+				switch {
+				case errors.Is(err, utils.ErrIdempotencyKeyReused):
+					return c.JSON(http.StatusUnprocessableEntity, err.Error())
+				case errors.Is(err, utils.ErrIdempotencyKeyInProgress):
+					return c.JSON(http.StatusConflict, err.Error())
+				}
+				c.Logger().Error("Error on begin idempotent request: %s", err.Error())
+				return c.JSON(http.StatusInternalServerError, "Error on check idempotency key. Please, contact system admin")
+			}
+
+			if replay {
+				c.Response().Header().Set(IDEMPOTENCY_REPLAYED_HEADER, "true")
+				return c.Blob(stored.StatusCode, stored.ContentType, stored.Body)
+			}
+
+			recorder := &bodyRecorder{ResponseWriter: c.Response().Writer, body: new(bytes.Buffer)}
+			c.Response().Writer = recorder
+
+			// A handler that panics releases the key, then the panic goes on to be recovered
+			defer func() {
+				if r := recover(); r != nil {
+					if releaseErr := idempotencyService.Release(scope, key); releaseErr != nil {
+						c.Logger().Error("Error on release idempotency key: %s", releaseErr.Error())
+					}
+					panic(r)
+				}
+			}()
+
+			err = next(c)
+
+			status := c.Response().Status
+			if err != nil || status >= http.StatusInternalServerError {
+				if releaseErr := idempotencyService.Release(scope, key); releaseErr != nil {
+					c.Logger().Error("Error on release idempotency key: %s", releaseErr.Error())
+				}
+				return err
+			}
+
+			if completeErr := idempotencyService.Complete(scope, key, dtos.IdempotentResponse{
+				StatusCode:  status,
+				ContentType: c.Response().Header().Get(echo.HeaderContentType),
+				Body:        recorder.body.Bytes(),
+			}); completeErr != nil {
+				c.Logger().Error("Error on store idempotent response: %s", completeErr.Error())
+			}
+
+			return nil
+		}
+	}
+}
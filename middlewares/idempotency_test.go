@@ -0,0 +1,150 @@
+package middlewares
+
+import (
+	"errors"
+	"github/brunojoenk/golang-test/models/dtos"
+	"github/brunojoenk/golang-test/utils"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	idempotencyservicemock "github/brunojoenk/golang-test/services/idempotency/mock"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIdempotency(t *testing.T) {
+	scope := "ip-192.0.2.1:POST /book"
+	tests := map[string]struct {
+		key                  string
+		handlerStatus        int
+		stored               dtos.IdempotentResponse
+		replay               bool
+		expectedErrorOnBegin error
+		expectedStatus       int
+		expectedBody         string
+		expectedHandlerCalls int
+		expectedComplete     bool
+		expectedRelease      bool
+	}{
+		"request without key": {
+			handlerStatus:        http.StatusCreated,
+			expectedStatus:       http.StatusCreated,
+			expectedBody:         `{"id":1}`,
+			expectedHandlerCalls: 1,
+		},
+		"first request with key": {
+			key:                  "key",
+			handlerStatus:        http.StatusCreated,
+			expectedStatus:       http.StatusCreated,
+			expectedBody:         `{"id":1}`,
+			expectedHandlerCalls: 1,
+			expectedComplete:     true,
+		},
+		"first request with key fails": {
+			key:                  "key",
+			handlerStatus:        http.StatusInternalServerError,
+			expectedStatus:       http.StatusInternalServerError,
+			expectedBody:         `{"id":1}`,
+			expectedHandlerCalls: 1,
+			expectedRelease:      true,
+		},
+		"replayed request": {
+			key:            "key",
+			stored:         dtos.IdempotentResponse{StatusCode: http.StatusCreated, ContentType: "application/json", Body: []byte(`{"id":7}`)},
+			replay:         true,
+			expectedStatus: http.StatusCreated,
+			expectedBody:   `{"id":7}`,
+		},
+		"key reused with another body": {
+			key:                  "key",
+			expectedErrorOnBegin: utils.ErrIdempotencyKeyReused,
+			expectedStatus:       http.StatusUnprocessableEntity,
+		},
+		"key in progress": {
+			key:                  "key",
+			expectedErrorOnBegin: utils.ErrIdempotencyKeyInProgress,
+			expectedStatus:       http.StatusConflict,
+		},
+		"error on begin": {
+			key:                  "key",
+			expectedErrorOnBegin: errors.New("error occurred"),
+			expectedStatus:       http.StatusInternalServerError,
+		},
+		"key too long": {
+			key:            strings.Repeat("k", IDEMPOTENCY_KEY_MAX_SIZE+1),
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			idempotencyServiceMock := new(idempotencyservicemock.IdempotencyServiceMock)
+			idempotencyServiceMock.On("Begin", scope, tc.key, mock.Anything).Return(tc.stored, tc.replay, tc.expectedErrorOnBegin)
+			idempotencyServiceMock.On("Complete", scope, tc.key, mock.Anything).Return(nil)
+			idempotencyServiceMock.On("Release", scope, tc.key).Return(nil)
+
+			handlerCalls := 0
+			e := echo.New()
+			e.POST("/book", func(c echo.Context) error {
+				handlerCalls++
+				body, _ := io.ReadAll(c.Request().Body)
+				require.Equal(t, `{"name":"book"}`, string(body))
+				return c.JSONBlob(tc.handlerStatus, []byte(`{"id":1}`))
+			}, Idempotency(idempotencyServiceMock))
+
+			request := httptest.NewRequest(http.MethodPost, "/book", strings.NewReader(`{"name":"book"}`))
+			request.RemoteAddr = "192.0.2.1:1234"
+			if tc.key != "" {
+				request.Header.Set(IDEMPOTENCY_KEY_HEADER, tc.key)
+			}
+			recorder := httptest.NewRecorder()
+			e.ServeHTTP(recorder, request)
+
+			require.Equal(t, tc.expectedStatus, recorder.Code)
+			if tc.expectedBody != "" {
+				require.Equal(t, tc.expectedBody, recorder.Body.String())
+			}
+			require.Equal(t, tc.expectedHandlerCalls, handlerCalls)
+			if tc.replay {
+				require.Equal(t, "true", recorder.Header().Get(IDEMPOTENCY_REPLAYED_HEADER))
+			}
+			if tc.expectedComplete {
+				idempotencyServiceMock.AssertCalled(t, "Complete", scope, tc.key, dtos.IdempotentResponse{
+					StatusCode: tc.handlerStatus, ContentType: echo.MIMEApplicationJSONCharsetUTF8, Body: []byte(`{"id":1}`)})
+			} else {
+				idempotencyServiceMock.AssertNotCalled(t, "Complete", scope, tc.key, mock.Anything)
+			}
+			if tc.expectedRelease {
+				idempotencyServiceMock.AssertCalled(t, "Release", scope, tc.key)
+			}
+		})
+	}
+}
+
+func TestIdempotencyReleasesKeyOnPanic(t *testing.T) {
+	scope := "ip-192.0.2.1:POST /book"
+	idempotencyServiceMock := new(idempotencyservicemock.IdempotencyServiceMock)
+	idempotencyServiceMock.On("Begin", scope, "key", mock.Anything).Return(dtos.IdempotentResponse{}, false, nil)
+	idempotencyServiceMock.On("Release", scope, "key").Return(nil)
+
+	e := echo.New()
+	e.Use(middleware.Recover())
+	e.POST("/book", func(c echo.Context) error {
+		panic("handler failed")
+	}, Idempotency(idempotencyServiceMock))
+
+	request := httptest.NewRequest(http.MethodPost, "/book", strings.NewReader(`{"name":"book"}`))
+	request.RemoteAddr = "192.0.2.1:1234"
+	request.Header.Set(IDEMPOTENCY_KEY_HEADER, "key")
+	recorder := httptest.NewRecorder()
+	e.ServeHTTP(recorder, request)
+
+	require.Equal(t, http.StatusInternalServerError, recorder.Code)
+	idempotencyServiceMock.AssertCalled(t, "Release", scope, "key")
+	idempotencyServiceMock.AssertNotCalled(t, "Complete", scope, "key", mock.Anything)
+}
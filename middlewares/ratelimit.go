@@ -0,0 +1,59 @@
+package middlewares
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// RateLimiter throttles requests per api key, or per client ip for anonymous requests.
+// It must run after ApiKeyAuth so the principal is already on the context
+func RateLimiter(store IRateLimitStore, name string, limit RateLimit) echo.MiddlewareFunc {
+	return rateLimiter(store, name, limit, clientKey)
+}
+
+// IpRateLimiter throttles requests per client ip, whatever their api key. It runs before ApiKeyAuth,
+// so requests with bad keys are throttled before they are checked
+func IpRateLimiter(store IRateLimitStore, name string, limit RateLimit) echo.MiddlewareFunc {
+	return rateLimiter(store, name, limit, func(c echo.Context) string {
+		return "ip-" + c.RealIP()
+	})
+}
+
+func rateLimiter(store IRateLimitStore, name string, limit RateLimit, key func(c echo.Context) string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if limit.PerMinute < 1 || limit.Burst < 1 {
+				return next(c)
+			}
+
+			result, err := store.Take(fmt.Sprintf("%s:%s", name, key(c)), limit, time.Now())
+			if err != nil {
+				c.Logger().Error("Error on take rate limit token, request allowed: %s", err.Error())
+				return next(c)
+			}
+
+			header := c.Response().Header()
+			header.Set("RateLimit-Limit", strconv.Itoa(limit.Burst))
+			header.Set("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+			header.Set("RateLimit-Reset", strconv.Itoa(int(result.Reset.Seconds())))
+
+			if !result.Allowed {
+				header.Set("Retry-After", strconv.Itoa(int(result.RetryAfter.Seconds())))
+				return c.JSON(http.StatusTooManyRequests, "Too many requests. Please, try again later")
+			}
+
+			return next(c)
+		}
+	}
+}
+
+func clientKey(c echo.Context) string {
+	if principal, ok := GetPrincipal(c); ok {
+		return fmt.Sprintf("key-%d-%s", principal.Id, principal.Owner)
+	}
+	return "ip-" + c.RealIP()
+}
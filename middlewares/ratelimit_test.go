@@ -0,0 +1,145 @@
+package middlewares
+
+import (
+	"errors"
+	"github/brunojoenk/golang-test/models/dtos"
+	"github/brunojoenk/golang-test/utils"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	apikeyservicemock "github/brunojoenk/golang-test/services/apikey/mock"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type failingRateLimitStore struct{}
+
+func (f failingRateLimitStore) Take(key string, limit RateLimit, now time.Time) (RateLimitResult, error) {
+	return RateLimitResult{}, errors.New("store unavailable")
+}
+
+func TestMemoryRateLimitStore(t *testing.T) {
+	store := NewMemoryRateLimitStore()
+	limit := RateLimit{PerMinute: 60, Burst: 2}
+	now := time.Now()
+
+	first, _ := store.Take("client", limit, now)
+	second, _ := store.Take("client", limit, now)
+	third, _ := store.Take("client", limit, now)
+
+	require.True(t, first.Allowed)
+	require.Equal(t, 1, first.Remaining)
+	require.True(t, second.Allowed)
+	require.Equal(t, 0, second.Remaining)
+	require.False(t, third.Allowed)
+	require.Equal(t, time.Second, third.RetryAfter)
+	require.Equal(t, 2*time.Second, third.Reset)
+
+	other, _ := store.Take("other-client", limit, now)
+	require.True(t, other.Allowed)
+
+	refilled, _ := store.Take("client", limit, now.Add(time.Second))
+	require.True(t, refilled.Allowed)
+}
+
+func TestMemoryRateLimitStoreSweep(t *testing.T) {
+	store := NewMemoryRateLimitStore().(*memoryRateLimitStore)
+	limit := RateLimit{PerMinute: 60, Burst: 2}
+	now := time.Now()
+
+	store.Take("idle", limit, now)
+	store.Take("active", limit, now.Add(2*time.Minute))
+
+	require.NotContains(t, store.buckets, "idle")
+	require.Contains(t, store.buckets, "active")
+}
+
+func TestRateLimiter(t *testing.T) {
+	e := echo.New()
+	e.Use(RateLimiter(NewMemoryRateLimitStore(), "default", RateLimit{PerMinute: 1, Burst: 1}))
+	e.GET("/books", func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	})
+
+	request := httptest.NewRequest(http.MethodGet, "/books", nil)
+	recorder := httptest.NewRecorder()
+	e.ServeHTTP(recorder, request)
+
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Equal(t, "1", recorder.Header().Get("RateLimit-Limit"))
+	require.Equal(t, "0", recorder.Header().Get("RateLimit-Remaining"))
+	require.Empty(t, recorder.Header().Get("Retry-After"))
+
+	recorder = httptest.NewRecorder()
+	e.ServeHTTP(recorder, request)
+
+	require.Equal(t, http.StatusTooManyRequests, recorder.Code)
+	require.Equal(t, "60", recorder.Header().Get("Retry-After"))
+	require.Equal(t, "60", recorder.Header().Get("RateLimit-Reset"))
+}
+
+func TestRateLimiterKeyedByApiKey(t *testing.T) {
+	e := echo.New()
+	e.Use(func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if owner := c.Request().Header.Get(API_KEY_HEADER); owner != "" {
+				c.Set(API_KEY_PRINCIPAL_KEY, dtos.ApiKeyPrincipal{Id: 1, Owner: owner})
+			}
+			return next(c)
+		}
+	})
+	e.Use(RateLimiter(NewMemoryRateLimitStore(), "default", RateLimit{PerMinute: 1, Burst: 1}))
+	e.GET("/books", func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	})
+
+	for _, key := range []string{"", "jobs", "partner"} {
+		request := httptest.NewRequest(http.MethodGet, "/books", nil)
+		request.Header.Set(API_KEY_HEADER, key)
+		recorder := httptest.NewRecorder()
+		e.ServeHTTP(recorder, request)
+
+		require.Equal(t, http.StatusOK, recorder.Code)
+	}
+}
+
+func TestIpRateLimiterIgnoresApiKey(t *testing.T) {
+	apiKeyServiceMock := new(apikeyservicemock.ApiKeyServiceMock)
+	apiKeyServiceMock.On("Authenticate", mock.Anything).Return(dtos.ApiKeyPrincipal{}, utils.ErrApiKeyInvalid)
+
+	e := echo.New()
+	e.Use(IpRateLimiter(NewMemoryRateLimitStore(), "ip", RateLimit{PerMinute: 1, Burst: 1}))
+	e.Use(ApiKeyAuth(apiKeyServiceMock, false))
+	e.GET("/books", func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	})
+
+	codes := []int{}
+	for _, key := range []string{"bad-key", "other-bad-key"} {
+		request := httptest.NewRequest(http.MethodGet, "/books", nil)
+		request.Header.Set(API_KEY_HEADER, key)
+		recorder := httptest.NewRecorder()
+		e.ServeHTTP(recorder, request)
+		codes = append(codes, recorder.Code)
+	}
+
+	require.Equal(t, []int{http.StatusUnauthorized, http.StatusTooManyRequests}, codes)
+	apiKeyServiceMock.AssertNumberOfCalls(t, "Authenticate", 1)
+}
+
+func TestRateLimiterAllowsWhenStoreFails(t *testing.T) {
+	e := echo.New()
+	e.Use(RateLimiter(failingRateLimitStore{}, "default", RateLimit{PerMinute: 1, Burst: 1}))
+	e.GET("/books", func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	})
+
+	recorder := httptest.NewRecorder()
+	e.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/books", nil))
+
+	require.Equal(t, http.StatusOK, recorder.Code)
+}
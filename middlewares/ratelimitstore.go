@@ -0,0 +1,90 @@
+package middlewares
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// RateLimit configures a token bucket: Burst tokens at most, refilled at PerMinute tokens per minute
+type RateLimit struct {
+	PerMinute int
+	Burst     int
+}
+
+type RateLimitResult struct {
+	Allowed    bool
+	Remaining  int
+	Reset      time.Duration
+	RetryAfter time.Duration
+}
+
+// IRateLimitStore keeps the token buckets. The in-memory store is enough for a single instance,
+// a shared store can be plugged when running many instances
+type IRateLimitStore interface {
+	Take(key string, limit RateLimit, now time.Time) (RateLimitResult, error)
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+	fullAt time.Time
+}
+
+type memoryRateLimitStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+// NewMemoryRateLimitStore Store Constructor
+func NewMemoryRateLimitStore() IRateLimitStore {
+	return &memoryRateLimitStore{buckets: make(map[string]*bucket)}
+}
+
+func (m *memoryRateLimitStore) Take(key string, limit RateLimit, now time.Time) (RateLimitResult, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.sweep(now)
+
+	b, ok := m.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(limit.Burst), last: now}
+		m.buckets[key] = b
+	}
+
+	ratePerSecond := float64(limit.PerMinute) / 60
+	b.tokens = math.Min(float64(limit.Burst), b.tokens+now.Sub(b.last).Seconds()*ratePerSecond)
+	b.last = now
+
+	result := RateLimitResult{}
+	if b.tokens >= 1 {
+		b.tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = secondsToDuration((1 - b.tokens) / ratePerSecond)
+	}
+	result.Remaining = int(b.tokens)
+	result.Reset = secondsToDuration((float64(limit.Burst) - b.tokens) / ratePerSecond)
+	b.fullAt = now.Add(result.Reset)
+
+	return result, nil
+}
+
+// sweep drops buckets that are already full again, at most once a minute
+func (m *memoryRateLimitStore) sweep(now time.Time) {
+	if now.Sub(m.lastSweep) < time.Minute {
+		return
+	}
+	m.lastSweep = now
+	for key, b := range m.buckets {
+		if now.After(b.fullAt) {
+			delete(m.buckets, key)
+		}
+	}
+}
+
+func secondsToDuration(seconds float64) time.Duration {
+	return time.Duration(math.Ceil(seconds)) * time.Second
+}
@@ -1,27 +1,94 @@
 package dtos
 
+import (
+	"encoding/json"
+	"github/brunojoenk/golang-test/utils"
+	"io"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// MAX_PAGE_LIMIT caps the page size a client can ask for
+var MAX_PAGE_LIMIT = 100
+
 type AuthorResponseMetadata struct {
 	Authors    []AuthorResponse `json:"authors"`
 	Pagination Pagination       `json:"pagination"`
 }
 
 type AuthorResponse struct {
-	Id   int    `json:"id"`
+	Id          int    `json:"id"`
+	Name        string `json:"name"`
+	BirthDate   string `json:"birth_date,omitempty"`
+	DeathDate   string `json:"death_date,omitempty"`
+	Nationality string `json:"nationality,omitempty"`
+	Biography   string `json:"biography,omitempty"`
+	Website     string `json:"website,omitempty"`
+	Orcid       string `json:"orcid,omitempty"`
+	Viaf        string `json:"viaf,omitempty"`
+	WikidataId  string `json:"wikidata_id,omitempty"`
+}
+
+type AuthorMergeRequest struct {
+	SourceId int `json:"source_id"`
+}
+
+type AuthorDuplicatesResponseMetadata struct {
+	Candidates []AuthorDuplicateCandidate `json:"candidates"`
+	Pagination Pagination                 `json:"pagination"`
+}
+
+// AuthorDuplicateCandidate is a pair of authors whose names are probably the same person
+type AuthorDuplicateCandidate struct {
+	Author AuthorResponse `json:"author"`
+	Other  AuthorResponse `json:"other"`
+	Score  float64        `json:"score"`
+}
+
+type AuthorAliasRequest struct {
 	Name string `json:"name"`
 }
 
+type AuthorAliasResponse struct {
+	Id       int    `json:"id"`
+	AuthorId int    `json:"author_id"`
+	Name     string `json:"name"`
+}
+
+type GetAuthorDuplicatesFilter struct {
+	MinScore float64 `query:"min_score"`
+	Pagination
+}
+
 type BookRequestCreate struct {
-	Name            string `json:"name"`
-	Edition         string `json:"edition"`
-	PublicationYear int    `json:"publication_year"`
-	Authors         []int  `json:"authors"`
+	Name            string              `json:"name"`
+	Language        string              `json:"language"`
+	Titles          map[string]string   `json:"titles"`
+	Edition         string              `json:"edition"`
+	PublicationYear int                 `json:"publication_year"`
+	Isbn            string              `json:"isbn"`
+	WorkId          int                 `json:"work_id"`
+	PublisherId     int                 `json:"publisher_id"`
+	SeriesId        int                 `json:"series_id"`
+	SeriesPosition  float64             `json:"series_position"`
+	Authors         []BookAuthorRequest `json:"authors"`
+	Genres          []int               `json:"genres"`
 }
 
 type BookRequestUpdate struct {
-	Name            string `json:"name"`
-	Edition         string `json:"edition"`
-	PublicationYear int    `json:"publication_year"`
-	Authors         []int  `json:"authors"`
+	Name            string              `json:"name"`
+	Language        string              `json:"language"`
+	Titles          map[string]string   `json:"titles"`
+	Edition         string              `json:"edition"`
+	PublicationYear int                 `json:"publication_year"`
+	Isbn            string              `json:"isbn"`
+	WorkId          int                 `json:"work_id"`
+	PublisherId     int                 `json:"publisher_id"`
+	SeriesId        int                 `json:"series_id"`
+	SeriesPosition  float64             `json:"series_position"`
+	Authors         []BookAuthorRequest `json:"authors"`
+	Genres          []int               `json:"genres"`
 }
 
 type BookResponseMetadata struct {
@@ -30,11 +97,515 @@ type BookResponseMetadata struct {
 }
 
 type BookResponse struct {
+	Id              int                      `json:"id"`
+	Name            string                   `json:"name"`
+	Title           string                   `json:"title,omitempty"`
+	Language        string                   `json:"language,omitempty"`
+	Titles          map[string]string        `json:"titles,omitempty"`
+	Edition         string                   `json:"edition"`
+	PublicationYear int                      `json:"publication_year"`
+	Isbn13          string                   `json:"isbn13,omitempty"`
+	Isbn10          string                   `json:"isbn10,omitempty"`
+	WorkId          int                      `json:"work_id,omitempty"`
+	PublisherId     int                      `json:"publisher_id,omitempty"`
+	Publisher       string                   `json:"publisher,omitempty"`
+	SeriesId        int                      `json:"series_id,omitempty"`
+	Series          string                   `json:"series,omitempty"`
+	SeriesPosition  float64                  `json:"series_position,omitempty"`
+	Authors         string                   `json:"authors"`
+	Contributors    BookContributorsResponse `json:"contributors"`
+	Genres          string                   `json:"genres,omitempty"`
+	Tags            []string                 `json:"tags,omitempty"`
+	Availability    BookAvailabilityResponse `json:"availability"`
+	Rating          BookRatingResponse       `json:"rating"`
+}
+
+// BookRatingResponse is the average rating of the reviews of a book, 0 while it has none
+type BookRatingResponse struct {
+	Average float64 `json:"average"`
+	Count   int     `json:"count"`
+}
+
+// BookAvailabilityResponse counts the copies of a book in circulation, leaving out the lost and withdrawn ones.
+// Copies on hold are put aside for the patron first in line and are not available to others
+type BookAvailabilityResponse struct {
+	Copies    int `json:"copies"`
+	Available int `json:"available"`
+	OnHold    int `json:"on_hold"`
+}
+
+// BookAuthorRequest is a contributor of a book and their role on it. Plain author ids, the format
+// used before roles, are accepted too and mean the role author
+type BookAuthorRequest struct {
+	Id   int    `json:"id"`
+	Role string `json:"role" enums:"author,editor,translator,illustrator"`
+}
+
+// BookContributorsResponse lists the contributors of a book grouped by role, in credit order
+type BookContributorsResponse struct {
+	Authors      []string `json:"authors,omitempty"`
+	Editors      []string `json:"editors,omitempty"`
+	Translators  []string `json:"translators,omitempty"`
+	Illustrators []string `json:"illustrators,omitempty"`
+}
+
+type BookTagsRequest struct {
+	Tags []string `json:"tags"`
+}
+
+type BookMergeRequest struct {
+	SourceId int `json:"source_id"`
+}
+
+type BookDuplicatedResponse struct {
+	Msg            string `json:"msg"`
+	ExistingBookId int    `json:"existing_book_id"`
+}
+
+type BookDuplicatesResponseMetadata struct {
+	Clusters   []BookDuplicateCluster `json:"clusters"`
+	Pagination Pagination             `json:"pagination"`
+}
+
+// BookDuplicateCluster groups books whose normalized names are equal
+type BookDuplicateCluster struct {
+	NormalizedName string         `json:"normalized_name"`
+	Books          []BookResponse `json:"books"`
+}
+
+type PublisherRequest struct {
+	Name    string `json:"name"`
+	Country string `json:"country"`
+	Website string `json:"website"`
+}
+
+type PublisherResponseMetadata struct {
+	Publishers []PublisherResponse `json:"publishers"`
+	Pagination Pagination          `json:"pagination"`
+}
+
+type PublisherResponse struct {
+	Id      int    `json:"id"`
+	Name    string `json:"name"`
+	Country string `json:"country,omitempty"`
+	Website string `json:"website,omitempty"`
+}
+
+type CopyRequest struct {
+	Barcode    string `json:"barcode"`
+	Branch     string `json:"branch"`
+	Location   string `json:"location"`
+	Condition  string `json:"condition"`
+	AcquiredAt string `json:"acquired_at"`
+	Status     string `json:"status"`
+}
+
+type CopyResponseMetadata struct {
+	Copies     []CopyResponse `json:"copies"`
+	Pagination Pagination     `json:"pagination"`
+}
+
+type CopyResponse struct {
+	Id         int    `json:"id"`
+	BookId     int    `json:"book_id"`
+	Barcode    string `json:"barcode"`
+	Branch     string `json:"branch,omitempty"`
+	Location   string `json:"location,omitempty"`
+	Condition  string `json:"condition"`
+	AcquiredAt string `json:"acquired_at,omitempty"`
+	Status     string `json:"status"`
+}
+
+// ReviewRequest creates a review by the patron, or updates it; the patron of a review can not change
+type ReviewRequest struct {
+	PatronId int    `json:"patron_id"`
+	Rating   int    `json:"rating"`
+	Text     string `json:"text"`
+}
+
+type ReviewResponseMetadata struct {
+	Reviews    []ReviewResponse `json:"reviews"`
+	Pagination Pagination       `json:"pagination"`
+}
+
+type ReviewResponse struct {
+	Id        int       `json:"id"`
+	BookId    int       `json:"book_id"`
+	PatronId  int       `json:"patron_id"`
+	Reviewer  string    `json:"reviewer,omitempty"`
+	Rating    int       `json:"rating"`
+	Text      string    `json:"text,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// ReadingListRequest creates or updates a reading list. An empty visibility keeps the list private
+type ReadingListRequest struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Visibility  string `json:"visibility"`
+}
+
+// ReadingListBookRequest adds a book to a reading list at position, or at the end without it
+type ReadingListBookRequest struct {
+	BookId   int    `json:"book_id"`
+	Note     string `json:"note"`
+	Position int    `json:"position"`
+}
+
+type ReadingListNoteRequest struct {
+	Note string `json:"note"`
+}
+
+// ReadingListOrderRequest puts the books of a reading list in a new order; it must have every book of the list once
+type ReadingListOrderRequest struct {
+	BookIds []int `json:"book_ids"`
+}
+
+type ReadingListResponseMetadata struct {
+	Lists      []ReadingListResponse `json:"lists"`
+	Pagination Pagination            `json:"pagination"`
+}
+
+type ReadingListResponse struct {
+	Id          int                       `json:"id"`
+	Owner       string                    `json:"owner"`
+	Name        string                    `json:"name"`
+	Description string                    `json:"description,omitempty"`
+	Visibility  string                    `json:"visibility"`
+	BookCount   int                       `json:"book_count"`
+	Books       []ReadingListBookResponse `json:"books,omitempty"`
+	CreatedAt   time.Time                 `json:"created_at"`
+	UpdatedAt   time.Time                 `json:"updated_at"`
+}
+
+type ReadingListBookResponse struct {
+	Position int          `json:"position"`
+	Note     string       `json:"note,omitempty"`
+	AddedAt  time.Time    `json:"added_at"`
+	Book     BookResponse `json:"book"`
+}
+
+type RelatedBookResponseMetadata struct {
+	Books      []RelatedBookResponse `json:"books"`
+	Pagination Pagination            `json:"pagination"`
+}
+
+// RelatedBookResponse is a book related to another, with its score and what the two have in common
+type RelatedBookResponse struct {
+	Score   int            `json:"score"`
+	Reasons RelatedReasons `json:"reasons"`
+	Book    BookResponse   `json:"book"`
+}
+
+// RelatedReasons counts what two books have in common: authors, genres and tags, the patrons who borrowed
+// both and the public reading lists with both
+type RelatedReasons struct {
+	SharedAuthors int `json:"shared_authors"`
+	SharedGenres  int `json:"shared_genres"`
+	SharedTags    int `json:"shared_tags"`
+	CoBorrowed    int `json:"co_borrowed"`
+	CoListed      int `json:"co_listed"`
+}
+
+// RelatedBookScore is a book scored by how related it is to another
+type RelatedBookScore struct {
+	BookId        int
+	Score         int
+	SharedAuthors int
+	SharedGenres  int
+	SharedTags    int
+	CoBorrowed    int
+	CoListed      int
+}
+
+// RelatedWeights are the points a related book scores for each author, genre and tag in common, each patron
+// who borrowed both books and each public reading list with both
+type RelatedWeights struct {
+	Author     int
+	Genre      int
+	Tag        int
+	CoBorrowed int
+	CoListed   int
+}
+
+// PublicationYearStatsResponse counts the books published each year, or each decade when the bucket is decade
+type PublicationYearStatsResponse struct {
+	Bucket string          `json:"bucket"`
+	Years  []YearBookCount `json:"years"`
+}
+
+// YearBookCount is the number of books published in a year, or in the decade starting on it
+type YearBookCount struct {
+	Year  int `json:"year"`
+	Books int `json:"books"`
+}
+
+type ProlificAuthorsResponse struct {
+	Authors    []AuthorBookCount `json:"authors"`
+	Pagination Pagination        `json:"pagination"`
+}
+
+// AuthorBookCount is the number of books an author wrote, translations and other roles left out
+type AuthorBookCount struct {
+	AuthorId int    `json:"author_id"`
+	Name     string `json:"name"`
+	Books    int    `json:"books"`
+}
+
+type BooksWithoutAuthorsResponse struct {
+	Books      []StatsBook `json:"books"`
+	Pagination Pagination  `json:"pagination"`
+}
+
+type StatsBook struct {
 	Id              int    `json:"id"`
 	Name            string `json:"name"`
-	Edition         string `json:"edition"`
 	PublicationYear int    `json:"publication_year"`
-	Authors         string `json:"authors"`
+}
+
+type AuthorsWithoutBooksResponse struct {
+	Authors    []StatsAuthor `json:"authors"`
+	Pagination Pagination    `json:"pagination"`
+}
+
+type StatsAuthor struct {
+	Id   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+// ImportStatsResponse sums up the import runs of each day, week, month or year
+type ImportStatsResponse struct {
+	Kind     string         `json:"kind"`
+	Interval string         `json:"interval"`
+	Volumes  []ImportVolume `json:"volumes"`
+}
+
+// ImportVolume is how many imports ran in the period starting at Period and how many rows they added
+type ImportVolume struct {
+	Period time.Time `json:"period"`
+	Runs   int       `json:"runs"`
+	Added  int       `json:"added"`
+}
+
+// CoverResponse describes the cover of a book, with the URL of the original image and of each thumbnail
+type CoverResponse struct {
+	BookId      int               `json:"book_id"`
+	ContentType string            `json:"content_type"`
+	Width       int               `json:"width"`
+	Height      int               `json:"height"`
+	Size        int64             `json:"size"`
+	Urls        map[string]string `json:"urls"`
+	UpdatedAt   time.Time         `json:"updated_at"`
+}
+
+// CoverImage is a cover image to be sent to the client, who closes the body
+type CoverImage struct {
+	Body        io.ReadCloser
+	ContentType string
+	Size        int64
+	ETag        string
+}
+
+// BookFileResponse describes a digital file of a book with the metadata read from it
+type BookFileResponse struct {
+	Id          int       `json:"id"`
+	BookId      int       `json:"book_id"`
+	Name        string    `json:"name"`
+	Format      string    `json:"format"`
+	ContentType string    `json:"content_type"`
+	Size        int64     `json:"size"`
+	Pages       int       `json:"pages"`
+	Title       string    `json:"title"`
+	Authors     []string  `json:"authors"`
+	Language    string    `json:"language"`
+	Isbn13      string    `json:"isbn13"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// BookFileUploadResponse is an uploaded file and how its metadata compares with the book: the fields
+// that differ, and the empty fields of the book filled from the file
+type BookFileUploadResponse struct {
+	BookFileResponse
+	Mismatches []BookFileMismatch `json:"mismatches"`
+	Prefilled  []string           `json:"prefilled"`
+}
+
+// BookFileMismatch is a field whose value on the book differs from the one read from its file
+type BookFileMismatch struct {
+	Field string `json:"field"`
+	Book  string `json:"book"`
+	File  string `json:"file"`
+}
+
+// DownloadLinkResponse is a signed link to download a book file, valid until it expires
+type DownloadLinkResponse struct {
+	Url       string    `json:"url"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// BookFileDownload is a book file to be sent to the client. The body seeks, to answer range requests,
+// and is closed by the caller
+type BookFileDownload struct {
+	Body        io.ReadSeekCloser
+	Name        string
+	ContentType string
+	ModTime     time.Time
+}
+
+type PatronRequest struct {
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+type PatronResponseMetadata struct {
+	Patrons    []PatronResponse `json:"patrons"`
+	Pagination Pagination       `json:"pagination"`
+}
+
+type PatronResponse struct {
+	Id    int    `json:"id"`
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+// CheckOutRequest lends the copy with the barcode to the patron. Without due_at the loan period applies
+type CheckOutRequest struct {
+	Barcode  string `json:"barcode"`
+	PatronId int    `json:"patron_id"`
+	DueAt    string `json:"due_at"`
+}
+
+type LoanResponseMetadata struct {
+	Loans      []LoanResponse `json:"loans"`
+	Pagination Pagination     `json:"pagination"`
+}
+
+type LoanResponse struct {
+	Id         int        `json:"id"`
+	CopyId     int        `json:"copy_id"`
+	BookId     int        `json:"book_id"`
+	Barcode    string     `json:"barcode"`
+	PatronId   int        `json:"patron_id"`
+	LoanedAt   time.Time  `json:"loaned_at"`
+	DueAt      string     `json:"due_at"`
+	ReturnedAt *time.Time `json:"returned_at,omitempty"`
+	Renewals   int        `json:"renewals"`
+	Overdue    bool       `json:"overdue"`
+}
+
+// LedgerCreditRequest is a payment or waiver lowering the balance of a patron, in cents
+type LedgerCreditRequest struct {
+	AmountCents int64  `json:"amount_cents"`
+	Note        string `json:"note"`
+}
+
+// LedgerResponse is the balance a patron owes, in cents, and the entries that make it, newest first
+type LedgerResponse struct {
+	PatronId     int                   `json:"patron_id"`
+	BalanceCents int64                 `json:"balance_cents"`
+	Entries      []LedgerEntryResponse `json:"entries"`
+	Pagination   Pagination            `json:"pagination"`
+}
+
+type LedgerEntryResponse struct {
+	Id          int       `json:"id"`
+	LoanId      *int      `json:"loan_id,omitempty"`
+	Kind        string    `json:"kind"`
+	AmountCents int64     `json:"amount_cents"`
+	Note        string    `json:"note,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type OutboxResponseMetadata struct {
+	Messages   []OutboxMessageResponse `json:"messages"`
+	Pagination Pagination              `json:"pagination"`
+}
+
+type OutboxMessageResponse struct {
+	Id            int        `json:"id"`
+	Kind          string     `json:"kind"`
+	Recipient     string     `json:"recipient"`
+	Subject       string     `json:"subject"`
+	Status        string     `json:"status"`
+	Attempts      int        `json:"attempts"`
+	LastError     string     `json:"last_error,omitempty"`
+	NextAttemptAt time.Time  `json:"next_attempt_at"`
+	CreatedAt     time.Time  `json:"created_at"`
+	SentAt        *time.Time `json:"sent_at,omitempty"`
+}
+
+type HoldRequest struct {
+	PatronId int `json:"patron_id"`
+}
+
+type HoldsResponse struct {
+	Holds []HoldResponse `json:"holds"`
+}
+
+// HoldResponse is a hold with its place in the queue of the book. Position counts only the waiting
+// holds, a ready hold has the copy put aside for the patron until expires_at
+type HoldResponse struct {
+	Id        int        `json:"id"`
+	BookId    int        `json:"book_id"`
+	PatronId  int        `json:"patron_id"`
+	Status    string     `json:"status"`
+	Position  int        `json:"position,omitempty"`
+	CopyId    *int       `json:"copy_id,omitempty"`
+	PlacedAt  time.Time  `json:"placed_at"`
+	ReadyAt   *time.Time `json:"ready_at,omitempty"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+type SeriesRequest struct {
+	Name string `json:"name"`
+}
+
+type SeriesResponseMetadata struct {
+	Series     []SeriesResponse `json:"series"`
+	Pagination Pagination       `json:"pagination"`
+}
+
+// SeriesResponse is a series with, when requested by id, its books in reading order
+type SeriesResponse struct {
+	Id    int            `json:"id"`
+	Name  string         `json:"name"`
+	Books []BookResponse `json:"books,omitempty"`
+}
+
+type WorkResponseMetadata struct {
+	Works      []WorkResponse `json:"works"`
+	Pagination Pagination     `json:"pagination"`
+}
+
+type WorkResponse struct {
+	Id      int    `json:"id"`
+	Name    string `json:"name"`
+	Authors string `json:"authors"`
+}
+
+type GenreRequest struct {
+	Name     string `json:"name"`
+	ParentId int    `json:"parent_id"`
+}
+
+// GenreResponse is a genre with its subgenres, so GET /genres returns the whole taxonomy as a tree
+type GenreResponse struct {
+	Id       int             `json:"id"`
+	Name     string          `json:"name"`
+	ParentId int             `json:"parent_id,omitempty"`
+	Children []GenreResponse `json:"children,omitempty"`
+}
+
+type TagResponseMetadata struct {
+	Tags       []TagResponse `json:"tags"`
+	Pagination Pagination    `json:"pagination"`
+}
+
+// TagResponse is a tag with the number of books that have it
+type TagResponse struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
 }
 
 type Pagination struct {
@@ -43,15 +614,87 @@ type Pagination struct {
 }
 
 type GetAuthorsFilter struct {
-	Name string `query:"name"`
+	Name        string `query:"name"`
+	Nationality string `query:"nationality"`
+	BornBefore  string `query:"born_before"`
+	BornAfter   string `query:"born_after"`
 	Pagination
 }
 
+type GetPublicationYearStatsFilter struct {
+	Bucket string `query:"bucket"`
+}
+
+type GetImportStatsFilter struct {
+	Kind     string `query:"kind"`
+	Interval string `query:"interval"`
+}
+
 type GetBooksFilter struct {
-	Name            string `query:"name"`
-	Edition         string `query:"edition"`
-	PublicationYear int    `query:"publication_year"`
-	Author          string `query:"author"`
+	Name            string  `query:"name"`
+	Edition         string  `query:"edition"`
+	PublicationYear int     `query:"publication_year"`
+	Author          string  `query:"author"`
+	Isbn            string  `query:"isbn"`
+	Publisher       string  `query:"publisher"`
+	PublisherId     int     `query:"publisher_id"`
+	Series          string  `query:"series"`
+	SeriesId        int     `query:"series_id"`
+	Genre           string  `query:"genre"`
+	Language        string  `query:"language"`
+	Available       bool    `query:"available"`
+	Tags            string  `query:"tags"`
+	TagsMatch       string  `query:"tags_match"`
+	MinRating       float64 `query:"min_rating"`
+	Sort            string  `query:"sort"`
+	Pagination
+}
+
+type GetWorksFilter struct {
+	Name string `query:"name"`
+	Pagination
+}
+
+type GetSeriesFilter struct {
+	Name string `query:"name"`
+	Pagination
+}
+
+type GetCopiesFilter struct {
+	Status string `query:"status"`
+	Branch string `query:"branch"`
+	Pagination
+}
+
+// GetReadingListsFilter lists the public reading lists and the ones of the caller, or only those of owner
+type GetReadingListsFilter struct {
+	Owner string `query:"owner"`
+	Pagination
+}
+
+type GetReviewsFilter struct {
+	Rating int `query:"rating"`
+	Pagination
+}
+
+type GetPatronsFilter struct {
+	Name string `query:"name"`
+	Pagination
+}
+
+type GetOutboxFilter struct {
+	Status string `query:"status"`
+	Pagination
+}
+
+type GetLoansFilter struct {
+	Status string `query:"status"`
+	Pagination
+}
+
+type GetPublishersFilter struct {
+	Name    string `query:"name"`
+	Country string `query:"country"`
 	Pagination
 }
 
@@ -60,11 +703,279 @@ type AuthorImportResponse struct {
 	Total int    `json:"total"`
 }
 
+type ApiKeyRequestCreate struct {
+	Name      string     `json:"name"`
+	Owner     string     `json:"owner"`
+	Scopes    []string   `json:"scopes"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+type ApiKeyResponseMetadata struct {
+	ApiKeys    []ApiKeyResponse `json:"api_keys"`
+	Pagination Pagination       `json:"pagination"`
+}
+
+type ApiKeyResponse struct {
+	Id         int        `json:"id"`
+	Name       string     `json:"name"`
+	Owner      string     `json:"owner"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	RevokedAt  *time.Time `json:"revoked_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// ApiKeyCreatedResponse is the only response that carries the plain key
+type ApiKeyCreatedResponse struct {
+	ApiKeyResponse
+	Key string `json:"key"`
+}
+
+type GetApiKeysFilter struct {
+	Owner          string `query:"owner"`
+	IncludeRevoked bool   `query:"include_revoked"`
+	Pagination
+}
+
+const (
+	TagsMatchAny = "any"
+	TagsMatchAll = "all"
+)
+
+const (
+	RoleAuthor      = "author"
+	RoleEditor      = "editor"
+	RoleTranslator  = "translator"
+	RoleIllustrator = "illustrator"
+)
+
+const (
+	CopyStatusAvailable = "available"
+	CopyStatusOnLoan    = "on_loan"
+	CopyStatusOnHold    = "on_hold"
+	CopyStatusLost      = "lost"
+	CopyStatusWithdrawn = "withdrawn"
+)
+
+const (
+	LoanStatusActive   = "active"
+	LoanStatusOverdue  = "overdue"
+	LoanStatusReturned = "returned"
+)
+
+const (
+	HoldStatusWaiting   = "waiting"
+	HoldStatusReady     = "ready"
+	HoldStatusFulfilled = "fulfilled"
+	HoldStatusCancelled = "cancelled"
+	HoldStatusExpired   = "expired"
+)
+
+const (
+	BookSortName   = "name"
+	BookSortRating = "rating"
+)
+
+const (
+	ListVisibilityPrivate = "private"
+	ListVisibilityPublic  = "public"
+	// ListNameMaxLength is the most characters a reading list name can have
+	ListNameMaxLength = 100
+	// ListNoteMaxLength is the most characters the note of a book on a reading list can have
+	ListNoteMaxLength = 1000
+)
+
+const (
+	ExportFormatCsv  = "csv"
+	ExportFormatJson = "json"
+)
+
+const (
+	// ImportKindAuthors is the kind of the import runs of the authors csv file
+	ImportKindAuthors = "authors"
+)
+
+const (
+	CoverSizeOriginal = "original"
+	CoverSizeSmall    = "small"
+	CoverSizeMedium   = "medium"
+)
+
+const (
+	BookFileFormatPdf  = "pdf"
+	BookFileFormatEpub = "epub"
+)
+
+const (
+	StatsBucketYear   = "year"
+	StatsBucketDecade = "decade"
+)
+
+const (
+	StatsIntervalDay   = "day"
+	StatsIntervalWeek  = "week"
+	StatsIntervalMonth = "month"
+	StatsIntervalYear  = "year"
+)
+
+const (
+	MinRating = 1
+	MaxRating = 5
+	// ReviewTextMaxLength is the most characters a review text can have
+	ReviewTextMaxLength = 5000
+)
+
+const (
+	LedgerKindFine    = "fine"
+	LedgerKindPayment = "payment"
+	LedgerKindWaiver  = "waiver"
+)
+
+const (
+	OutboxStatusPending = "pending"
+	OutboxStatusSent    = "sent"
+	OutboxStatusFailed  = "failed"
+)
+
+const (
+	NotificationDueSoon   = "due_soon"
+	NotificationOverdue   = "overdue"
+	NotificationHoldReady = "hold_ready"
+)
+
+// OpenHoldStatuses are the statuses of the holds still in the queue of a book
+var OpenHoldStatuses = []string{HoldStatusWaiting, HoldStatusReady}
+
+const (
+	CopyConditionNew  = "new"
+	CopyConditionGood = "good"
+	CopyConditionFair = "fair"
+	CopyConditionPoor = "poor"
+)
+
+const (
+	ScopeRead  = "read"
+	ScopeWrite = "write"
+	ScopeAdmin = "admin"
+)
+
+// ApiKeyPrincipal identifies the client authenticated by an api key
+type ApiKeyPrincipal struct {
+	Id     int
+	Owner  string
+	Scopes []string
+}
+
+type IdempotentResponse struct {
+	StatusCode  int
+	ContentType string
+	Body        []byte
+}
+
 func (p *Pagination) ValidValuesAndSetDefault() {
 	if p.Limit < 1 {
 		p.Limit = 10
 	}
+	if p.Limit > MAX_PAGE_LIMIT {
+		p.Limit = MAX_PAGE_LIMIT
+	}
 	if p.Page < 1 {
 		p.Page = 1
 	}
 }
+
+// Localize sets Title to the name or translated title that best fits an Accept-Language header. Name is
+// in the language of the book, so it is the title when no translation fits better
+func (b *BookResponse) Localize(acceptLanguage string) {
+	b.Title = b.Name
+
+	locales := []string{b.Language}
+	for locale := range b.Titles {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales[1:])
+
+	if best := utils.BestLocale(acceptLanguage, locales); best > 0 {
+		b.Title = b.Titles[locales[best]]
+	}
+}
+
+func (m *BookResponseMetadata) Localize(acceptLanguage string) {
+	for i := range m.Books {
+		m.Books[i].Localize(acceptLanguage)
+	}
+}
+
+func (m *RelatedBookResponseMetadata) Localize(acceptLanguage string) {
+	for i := range m.Books {
+		m.Books[i].Book.Localize(acceptLanguage)
+	}
+}
+
+func (l *ReadingListResponse) Localize(acceptLanguage string) {
+	for i := range l.Books {
+		l.Books[i].Book.Localize(acceptLanguage)
+	}
+}
+
+func (s PublicationYearStatsResponse) CsvRecords() [][]string {
+	records := [][]string{{s.Bucket, "books"}}
+	for _, y := range s.Years {
+		records = append(records, []string{strconv.Itoa(y.Year), strconv.Itoa(y.Books)})
+	}
+	return records
+}
+
+func (s ProlificAuthorsResponse) CsvRecords() [][]string {
+	records := [][]string{{"author_id", "name", "books"}}
+	for _, a := range s.Authors {
+		records = append(records, []string{strconv.Itoa(a.AuthorId), a.Name, strconv.Itoa(a.Books)})
+	}
+	return records
+}
+
+func (s BooksWithoutAuthorsResponse) CsvRecords() [][]string {
+	records := [][]string{{"id", "name", "publication_year"}}
+	for _, b := range s.Books {
+		records = append(records, []string{strconv.Itoa(b.Id), b.Name, strconv.Itoa(b.PublicationYear)})
+	}
+	return records
+}
+
+func (s AuthorsWithoutBooksResponse) CsvRecords() [][]string {
+	records := [][]string{{"id", "name"}}
+	for _, a := range s.Authors {
+		records = append(records, []string{strconv.Itoa(a.Id), a.Name})
+	}
+	return records
+}
+
+func (s ImportStatsResponse) CsvRecords() [][]string {
+	records := [][]string{{s.Interval, "runs", "added"}}
+	for _, v := range s.Volumes {
+		records = append(records, []string{v.Period.UTC().Format("2006-01-02"), strconv.Itoa(v.Runs), strconv.Itoa(v.Added)})
+	}
+	return records
+}
+
+func (a *BookAuthorRequest) UnmarshalJSON(data []byte) error {
+	var id int
+	if err := json.Unmarshal(data, &id); err == nil {
+		*a = BookAuthorRequest{Id: id}
+		return nil
+	}
+	type bookAuthorRequest BookAuthorRequest
+	return json.Unmarshal(data, (*bookAuthorRequest)(a))
+}
+
+// HasScope reports whether the principal was granted scope. Admin implies every scope and write implies read.
+func (p ApiKeyPrincipal) HasScope(scope string) bool {
+	for _, s := range p.Scopes {
+		if s == scope || s == ScopeAdmin || (s == ScopeWrite && scope == ScopeRead) {
+			return true
+		}
+	}
+	return false
+}
@@ -1,14 +1,296 @@
 package entities
 
+import "time"
+
 type Author struct {
+	Id             int        `gorm:"primary_key, AUTO_INCREMENT"`
+	Name           string     `gorm:"index:idx_name,unique" json:"name"`
+	NormalizedName string     `gorm:"index:idx_author_normalized_name" json:"-"`
+	BirthDate      *time.Time `gorm:"type:date;index:idx_author_birth_date" json:"birth_date"`
+	DeathDate      *time.Time `gorm:"type:date" json:"death_date"`
+	Nationality    string     `gorm:"size:2;index:idx_author_nationality" json:"nationality"`
+	Biography      string     `json:"biography"`
+	Website        string     `json:"website"`
+	Orcid          string     `json:"orcid"`
+	Viaf           string     `json:"viaf"`
+	WikidataId     string     `json:"wikidata_id"`
+}
+
+// AuthorAlias is another name of an author, e.g. the name of an author merged into it
+type AuthorAlias struct {
+	Id             int    `gorm:"primary_key, AUTO_INCREMENT"`
+	AuthorId       int    `gorm:"index:idx_author_alias,unique" json:"author_id"`
+	Name           string `gorm:"name" json:"name"`
+	NormalizedName string `gorm:"index:idx_author_alias,unique;index:idx_alias_normalized_name" json:"-"`
+}
+
+type Publisher struct {
+	Id             int    `gorm:"primary_key, AUTO_INCREMENT"`
+	Name           string `gorm:"name" json:"name"`
+	NormalizedName string `gorm:"index:idx_publisher_normalized_name,unique" json:"-"`
+	Country        string `gorm:"size:2" json:"country"`
+	Website        string `json:"website"`
+}
+
+// Genre is a node of the genres taxonomy, e.g. Fiction > Fantasy > High Fantasy. Root genres have no parent
+type Genre struct {
+	Id             int    `gorm:"primary_key, AUTO_INCREMENT"`
+	Name           string `gorm:"name" json:"name"`
+	NormalizedName string `gorm:"index:idx_genre_name,unique;index:idx_genre_normalized_name" json:"-"`
+	ParentId       *int   `gorm:"index:idx_genre_name,unique;index:idx_genre_parent" json:"parent_id"`
+}
+
+// Series groups books meant to be read in order, e.g. The Lord of the Rings
+type Series struct {
+	Id             int    `gorm:"primary_key, AUTO_INCREMENT"`
+	Name           string `gorm:"name" json:"name"`
+	NormalizedName string `gorm:"index:idx_series_normalized_name,unique" json:"-"`
+}
+
+// Tag is a free-form label librarians put on books, stored normalized (see utils.NormalizeTag)
+type Tag struct {
 	Id   int    `gorm:"primary_key, AUTO_INCREMENT"`
-	Name string `gorm:"index:idx_name,unique" json:"name"`
+	Name string `gorm:"size:50;uniqueIndex:idx_tag_name" json:"name"`
+}
+
+// Work is the abstract creation a book is an edition of, e.g. The Hobbit, whatever its edition or publisher.
+// Authors belong to the work, while translators, illustrators and editors are credited on each edition
+type Work struct {
+	Id             int      `gorm:"primary_key, AUTO_INCREMENT"`
+	Name           string   `gorm:"name" json:"name"`
+	NormalizedName string   `gorm:"index:idx_work_normalized_name" json:"-"`
+	Authors        []Author `gorm:"many2many:author_work;"`
 }
 
 type Book struct {
-	Id              int      `gorm:"primary_key, AUTO_INCREMENT"`
-	Name            string   `gorm:"name" json:"name"`
-	Edition         string   `gorm:"edition" json:"edition"`
-	PublicationYear int      `gorm:"publication_year" json:"publication_year"`
-	Authors         []Author `gorm:"many2many:author_book;"`
+	Id              int        `gorm:"primary_key, AUTO_INCREMENT"`
+	Name            string     `gorm:"name" json:"name"`
+	NormalizedName  string     `gorm:"index:idx_book_normalized_name" json:"-"`
+	NaturalKey      *string    `gorm:"size:64;uniqueIndex:idx_book_natural_key,where:isbn13 IS NULL" json:"-"`
+	Language        string     `gorm:"size:3;index:idx_book_language" json:"language"`
+	Edition         string     `gorm:"edition" json:"edition"`
+	PublicationYear int        `gorm:"publication_year" json:"publication_year"`
+	Isbn13          *string    `gorm:"size:13;uniqueIndex:idx_book_isbn13" json:"isbn13"`
+	WorkId          *int       `gorm:"index:idx_book_work" json:"work_id"`
+	Work            *Work      `json:"work"`
+	PublisherId     *int       `gorm:"index:idx_book_publisher" json:"publisher_id"`
+	Publisher       *Publisher `json:"publisher"`
+	SeriesId        *int       `gorm:"index:idx_book_series" json:"series_id"`
+	Series          *Series    `json:"series"`
+	SeriesPosition  *float64   `json:"series_position"`
+	RatingAverage   float64    `gorm:"default:0;index:idx_book_rating,priority:1" json:"rating_average"`
+	RatingCount     int        `gorm:"default:0;index:idx_book_rating,priority:2" json:"rating_count"`
+	Titles          []BookTitle
+	Copies          []Copy
+	Contributors    []AuthorBook
+	Genres          []Genre `gorm:"many2many:book_genre;"`
+	Tags            []Tag   `gorm:"many2many:book_tag;"`
+}
+
+// BookTitle is the title of a book translated to a locale, e.g. "O Hobbit" for pt-BR
+type BookTitle struct {
+	BookId int    `gorm:"primaryKey" json:"book_id"`
+	Locale string `gorm:"primaryKey;size:35" json:"locale"`
+	Title  string `json:"title"`
+}
+
+// Copy is a physical copy of a book the library holds, told apart by the barcode on its label
+type Copy struct {
+	Id         int        `gorm:"primary_key, AUTO_INCREMENT"`
+	BookId     int        `gorm:"index:idx_copy_book" json:"book_id"`
+	Barcode    string     `gorm:"size:50;uniqueIndex:idx_copy_barcode" json:"barcode"`
+	Branch     string     `gorm:"size:100" json:"branch"`
+	Location   string     `gorm:"size:100" json:"location"`
+	Condition  string     `gorm:"size:20" json:"condition"`
+	AcquiredAt *time.Time `gorm:"type:date" json:"acquired_at"`
+	Status     string     `gorm:"size:20;index:idx_copy_status" json:"status"`
+}
+
+// Patron is a library member who can borrow copies
+type Patron struct {
+	Id    int    `gorm:"primary_key, AUTO_INCREMENT"`
+	Name  string `gorm:"size:200" json:"name"`
+	Email string `gorm:"size:254;uniqueIndex:idx_patron_email" json:"email"`
+}
+
+// Review is what a patron thought of a book, rated from 1 to 5. A patron reviews a book once, and the
+// average rating and count of the reviews of a book are kept on the book
+type Review struct {
+	Id        int       `gorm:"primary_key, AUTO_INCREMENT"`
+	BookId    int       `gorm:"uniqueIndex:idx_review_book_patron,priority:1" json:"book_id"`
+	Book      Book      `gorm:"constraint:OnDelete:CASCADE" json:"-"`
+	PatronId  int       `gorm:"uniqueIndex:idx_review_book_patron,priority:2;index:idx_review_patron" json:"patron_id"`
+	Patron    Patron    `gorm:"constraint:OnDelete:CASCADE" json:"-"`
+	Owner     string    `gorm:"size:200" json:"-"`
+	Rating    int       `json:"rating"`
+	Text      string    `json:"text"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// BookCover is the cover image of a book. The original and its thumbnails are kept on the blob store
+// under Key, which changes on every upload
+type BookCover struct {
+	BookId      int       `gorm:"primaryKey;autoIncrement:false" json:"book_id"`
+	Book        Book      `gorm:"constraint:OnDelete:CASCADE" json:"-"`
+	Key         string    `gorm:"size:200" json:"key"`
+	ContentType string    `gorm:"size:20" json:"content_type"`
+	Width       int       `json:"width"`
+	Height      int       `json:"height"`
+	Size        int64     `json:"size"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// BookFile is a digital edition of a book, a PDF or EPUB file kept on the blob store under Key. The
+// pages, title, authors, language and ISBN are read from the file on upload, when it has them
+type BookFile struct {
+	Id        int       `gorm:"primary_key, AUTO_INCREMENT" json:"id"`
+	BookId    int       `gorm:"index:idx_book_file_book" json:"book_id"`
+	Book      Book      `gorm:"constraint:OnDelete:CASCADE" json:"-"`
+	Key       string    `gorm:"size:200" json:"-"`
+	Name      string    `gorm:"size:200" json:"name"`
+	Format    string    `gorm:"size:10" json:"format"`
+	Size      int64     `json:"size"`
+	Pages     int       `json:"pages"`
+	Title     string    `json:"title"`
+	Authors   string    `json:"authors"` // one per line
+	Language  string    `gorm:"size:3" json:"language"`
+	Isbn13    string    `gorm:"size:13" json:"isbn13"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ReadingList is a named list of books kept by a user, the owner of the api key that created it. Only
+// the owner sees a private list and changes a list
+type ReadingList struct {
+	Id          int                `gorm:"primary_key, AUTO_INCREMENT"`
+	Owner       string             `gorm:"size:200;index:idx_reading_list_owner" json:"owner"`
+	Name        string             `gorm:"size:100" json:"name"`
+	Description string             `json:"description"`
+	Visibility  string             `gorm:"size:20;index:idx_reading_list_visibility" json:"visibility"`
+	BookCount   int                `gorm:"->;-:migration" json:"book_count"`
+	Entries     []ReadingListEntry `gorm:"foreignKey:ListId;constraint:OnDelete:CASCADE" json:"entries"`
+	CreatedAt   time.Time          `json:"created_at"`
+	UpdatedAt   time.Time          `json:"updated_at"`
+}
+
+// ReadingListEntry is a book on a reading list, in the order of its position, with a note of the owner
+type ReadingListEntry struct {
+	Id       int       `gorm:"primary_key, AUTO_INCREMENT"`
+	ListId   int       `gorm:"uniqueIndex:idx_reading_list_entry_book,priority:1" json:"list_id"`
+	BookId   int       `gorm:"uniqueIndex:idx_reading_list_entry_book,priority:2;index:idx_reading_list_entry_book_id" json:"book_id"`
+	Book     Book      `gorm:"constraint:OnDelete:CASCADE" json:"-"`
+	Position int       `json:"position"`
+	Note     string    `json:"note"`
+	AddedAt  time.Time `json:"added_at"`
+}
+
+// Loan is a copy checked out to a patron. A copy has at most one loan not returned yet
+type Loan struct {
+	Id         int        `gorm:"primary_key, AUTO_INCREMENT"`
+	CopyId     int        `gorm:"index:idx_loan_copy;uniqueIndex:idx_loan_open_copy,where:returned_at IS NULL" json:"copy_id"`
+	Copy       Copy       `gorm:"constraint:OnDelete:CASCADE" json:"copy"`
+	PatronId   int        `gorm:"index:idx_loan_patron" json:"patron_id"`
+	Patron     Patron     `gorm:"constraint:OnDelete:CASCADE" json:"-"`
+	LoanedAt   time.Time  `json:"loaned_at"`
+	DueAt      time.Time  `gorm:"type:date;index:idx_loan_due" json:"due_at"`
+	ReturnedAt *time.Time `json:"returned_at"`
+	Renewals   int        `json:"renewals"`
+}
+
+// Hold is a patron waiting in line for a copy of a book. When a copy frees up the first waiting hold
+// gets it, the copy goes on hold and the patron has until ExpiresAt to pick it up
+type Hold struct {
+	Id        int        `gorm:"primary_key, AUTO_INCREMENT"`
+	BookId    int        `gorm:"index:idx_hold_book;uniqueIndex:idx_hold_open_patron,where:status IN ('waiting'\\,'ready')" json:"book_id"`
+	Book      Book       `gorm:"constraint:OnDelete:CASCADE" json:"-"`
+	PatronId  int        `gorm:"uniqueIndex:idx_hold_open_patron,where:status IN ('waiting'\\,'ready')" json:"patron_id"`
+	Patron    Patron     `gorm:"constraint:OnDelete:CASCADE" json:"-"`
+	CopyId    *int       `gorm:"uniqueIndex:idx_hold_ready_copy,where:status = 'ready'" json:"copy_id"`
+	Copy      *Copy      `gorm:"constraint:OnDelete:SET NULL" json:"-"`
+	Status    string     `gorm:"size:20;index:idx_hold_status" json:"status"`
+	PlacedAt  time.Time  `json:"placed_at"`
+	ReadyAt   *time.Time `json:"ready_at"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+// LedgerEntry is a movement on the balance a patron owes the library: fines are positive, payments and
+// waivers negative. Entries are never changed, a fine that grows is charged again for the difference
+type LedgerEntry struct {
+	Id          int       `gorm:"primary_key, AUTO_INCREMENT"`
+	PatronId    int       `gorm:"index:idx_ledger_patron" json:"patron_id"`
+	Patron      Patron    `gorm:"constraint:OnDelete:CASCADE" json:"-"`
+	LoanId      *int      `gorm:"index:idx_ledger_loan" json:"loan_id"`
+	Loan        *Loan     `gorm:"constraint:OnDelete:SET NULL" json:"-"`
+	Kind        string    `gorm:"size:20" json:"kind"`
+	AmountCents int64     `json:"amount_cents"`
+	Note        string    `gorm:"size:500" json:"note"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// OutboxMessage is a notification waiting to be delivered, or already delivered, by the configured
+// sender. DedupKey keeps the same reminder from being queued twice
+type OutboxMessage struct {
+	Id            int        `gorm:"primary_key, AUTO_INCREMENT"`
+	Kind          string     `gorm:"size:30" json:"kind"`
+	Recipient     string     `gorm:"size:254" json:"recipient"`
+	Subject       string     `gorm:"size:200" json:"subject"`
+	Body          string     `json:"body"`
+	DedupKey      string     `gorm:"size:200;uniqueIndex:idx_outbox_dedup_key" json:"dedup_key"`
+	Status        string     `gorm:"size:20;index:idx_outbox_status_next,priority:1" json:"status"`
+	Attempts      int        `json:"attempts"`
+	LastError     string     `gorm:"size:500" json:"last_error"`
+	NextAttemptAt time.Time  `gorm:"index:idx_outbox_status_next,priority:2" json:"next_attempt_at"`
+	CreatedAt     time.Time  `json:"created_at"`
+	SentAt        *time.Time `json:"sent_at"`
+}
+
+// AuthorBook links an author to a book with a role they had on it, e.g. translator. An author with
+// more than one role, e.g. author and illustrator, has a link for each. Position keeps the order the
+// contributors are credited on the cover
+type AuthorBook struct {
+	BookId   int    `gorm:"primaryKey" json:"book_id"`
+	AuthorId int    `gorm:"primaryKey" json:"author_id"`
+	Author   Author `json:"author"`
+	Role     string `gorm:"primaryKey;size:20;default:author" json:"role"`
+	Position int    `json:"position"`
+}
+
+func (AuthorBook) TableName() string {
+	return "author_book"
+}
+
+type ApiKey struct {
+	Id         int    `gorm:"primary_key, AUTO_INCREMENT"`
+	Name       string `gorm:"name" json:"name"`
+	Owner      string `gorm:"owner" json:"owner"`
+	Prefix     string `gorm:"prefix" json:"prefix"`
+	KeyHash    string `gorm:"index:idx_key_hash,unique" json:"-"`
+	Scopes     string `gorm:"scopes" json:"scopes"`
+	ExpiresAt  *time.Time
+	LastUsedAt *time.Time
+	RevokedAt  *time.Time
+	CreatedAt  time.Time
+}
+
+// IdempotencyKey stores the first response of a request sent with the Idempotency-Key header
+type IdempotencyKey struct {
+	Scope       string `gorm:"primaryKey"`
+	Key         string `gorm:"primaryKey"`
+	RequestHash string `gorm:"request_hash"`
+	StatusCode  int    `gorm:"status_code"`
+	ContentType string `gorm:"content_type"`
+	Body        []byte `gorm:"body"`
+	CreatedAt   time.Time
+	ExpiresAt   time.Time `gorm:"index:idx_idempotency_expires_at"`
+}
+
+// ImportRun records a finished import, e.g. of the authors csv file, so import volumes can be
+// reported over time. Added is how many new rows the run created
+type ImportRun struct {
+	Id         int       `gorm:"primary_key, AUTO_INCREMENT"`
+	Kind       string    `gorm:"size:30;index:idx_import_run_kind_finished,priority:1" json:"kind"`
+	Source     string    `gorm:"size:500" json:"source"`
+	Added      int       `json:"added"`
+	StartedAt  time.Time `json:"started_at"`
+	FinishedAt time.Time `gorm:"index:idx_import_run_kind_finished,priority:2" json:"finished_at"`
 }
@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"github/brunojoenk/golang-test/models/dtos"
+	"github/brunojoenk/golang-test/models/entities"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type IApiKeyRepository interface {
+	CreateApiKey(apiKey entities.ApiKey) (entities.ApiKey, error)
+	GetApiKey(id int) (entities.ApiKey, error)
+	GetApiKeyByHash(keyHash string) (entities.ApiKey, error)
+	GetAllApiKeys(filter dtos.GetApiKeysFilter) ([]entities.ApiKey, error)
+	RevokeApiKey(id int, revokedAt time.Time) error
+	TouchApiKey(id int, usedAt, staleBefore time.Time) error
+}
+
+// ApiKeyRepository Api Keys Repository
+type ApiKeyRepository struct {
+	db *gorm.DB
+}
+
+// NewApiKeyRepository Repository Constructor
+func NewApiKeyRepository(db *gorm.DB) IApiKeyRepository {
+	return &ApiKeyRepository{db: db}
+}
+
+func (a *ApiKeyRepository) CreateApiKey(apiKey entities.ApiKey) (entities.ApiKey, error) {
+
+	if result := a.db.Create(&apiKey); result.Error != nil {
+		log.Error("Error on create api key: ", result.Error.Error())
+		return entities.ApiKey{}, result.Error
+	}
+
+	return apiKey, nil
+}
+
+func (a *ApiKeyRepository) GetApiKey(id int) (entities.ApiKey, error) {
+	var apiKey entities.ApiKey
+
+	if result := a.db.First(&apiKey, id); result.Error != nil {
+		log.Error("Error on get api key: ", result.Error.Error())
+		return apiKey, result.Error
+	}
+
+	return apiKey, nil
+}
+
+func (a *ApiKeyRepository) GetApiKeyByHash(keyHash string) (entities.ApiKey, error) {
+	var apiKey entities.ApiKey
+
+	if result := a.db.Where("key_hash = ?", keyHash).First(&apiKey); result.Error != nil {
+		return apiKey, result.Error
+	}
+
+	return apiKey, nil
+}
+
+func (a *ApiKeyRepository) GetAllApiKeys(filter dtos.GetApiKeysFilter) ([]entities.ApiKey, error) {
+
+	var apiKeys []entities.ApiKey
+	toExec := a.db
+
+	if strings.TrimSpace(filter.Owner) != "" {
+		toExec = toExec.Where("LOWER(owner) = ?", strings.ToLower(strings.TrimSpace(filter.Owner)))
+	}
+
+	if !filter.IncludeRevoked {
+		toExec = toExec.Where("revoked_at IS NULL")
+	}
+
+	toExec = toExec.Offset((filter.Page - 1) * filter.Limit).Limit(filter.Limit).Order("id asc")
+
+	if result := toExec.Find(&apiKeys); result.Error != nil {
+		log.Error("Error on get all api keys: ", result.Error.Error())
+		return nil, result.Error
+	}
+
+	return apiKeys, nil
+}
+
+func (a *ApiKeyRepository) RevokeApiKey(id int, revokedAt time.Time) error {
+
+	if result := a.db.Model(&entities.ApiKey{Id: id}).Update("revoked_at", revokedAt); result.Error != nil {
+		log.Error("Error on revoke api key: ", result.Error.Error())
+		return result.Error
+	}
+
+	return nil
+}
+
+// TouchApiKey sets when the key was last used, unless it was already set after staleBefore, so keys in
+// constant use are not written on every request
+func (a *ApiKeyRepository) TouchApiKey(id int, usedAt, staleBefore time.Time) error {
+
+	if result := a.db.Model(&entities.ApiKey{Id: id}).
+		Where("last_used_at IS NULL OR last_used_at < ?", staleBefore).
+		Update("last_used_at", usedAt); result.Error != nil {
+		log.Error("Error on update api key last used: ", result.Error.Error())
+		return result.Error
+	}
+
+	return nil
+}
@@ -0,0 +1,184 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"github/brunojoenk/golang-test/models/dtos"
+	"github/brunojoenk/golang-test/models/entities"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-test/deep"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+type Suite struct {
+	suite.Suite
+	DB   *gorm.DB
+	mock sqlmock.Sqlmock
+
+	repository *ApiKeyRepository
+}
+
+func (s *Suite) AfterTest(_, _ string) {
+	require.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func TestInit(t *testing.T) {
+	suite.Run(t, new(Suite))
+}
+
+func (s *Suite) SetupSuite() {
+	var (
+		db  *sql.DB
+		err error
+	)
+
+	db, s.mock, err = sqlmock.New()
+	require.NoError(s.T(), err)
+
+	dialector := postgres.New(postgres.Config{
+		DSN:                  "sqlmock_db_0",
+		DriverName:           "postgres",
+		Conn:                 db,
+		PreferSimpleProtocol: true,
+	})
+	s.DB, err = gorm.Open(dialector, &gorm.Config{})
+	require.NoError(s.T(), err)
+
+	s.repository = &ApiKeyRepository{db: s.DB}
+}
+
+func (s *Suite) Test_repository_Create_ApiKey() {
+	var (
+		id      = 1
+		name    = "batch"
+		owner   = "jobs"
+		prefix  = "gt_abcdefgh"
+		keyHash = "hash"
+		scopes  = "read,write"
+	)
+
+	s.mock.ExpectBegin()
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`INSERT INTO "api_keys" ("name","owner","prefix","key_hash","scopes","expires_at","last_used_at","revoked_at","created_at") VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9) RETURNING "id"`)).
+		WithArgs(name, owner, prefix, keyHash, scopes, nil, nil, nil, sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).
+			AddRow(id))
+
+	s.mock.ExpectCommit()
+
+	apiKey, err := s.repository.CreateApiKey(entities.ApiKey{Name: name, Owner: owner, Prefix: prefix, KeyHash: keyHash, Scopes: scopes})
+
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), id, apiKey.Id)
+}
+
+func (s *Suite) Test_repository_Create_ApiKey_Error() {
+	s.mock.ExpectBegin()
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`INSERT INTO "api_keys"`)).
+		WillReturnError(context.Canceled)
+
+	s.mock.ExpectRollback()
+
+	_, err := s.repository.CreateApiKey(entities.ApiKey{Name: "batch"})
+
+	require.Error(s.T(), err)
+}
+
+func (s *Suite) Test_repository_Get_ApiKey_By_Hash() {
+	var (
+		id      = 1
+		owner   = "jobs"
+		keyHash = "hash"
+	)
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "api_keys" WHERE key_hash = $1 ORDER BY "api_keys"."id" LIMIT 1`)).
+		WithArgs(keyHash).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "owner", "key_hash"}).
+			AddRow(id, owner, keyHash))
+
+	res, err := s.repository.GetApiKeyByHash(keyHash)
+
+	require.NoError(s.T(), err)
+	require.Nil(s.T(), deep.Equal(entities.ApiKey{Id: id, Owner: owner, KeyHash: keyHash}, res))
+}
+
+func (s *Suite) Test_repository_Get_ApiKey_Error() {
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "api_keys" WHERE "api_keys"."id" = $1 ORDER BY "api_keys"."id" LIMIT 1`)).
+		WithArgs(1).
+		WillReturnError(gorm.ErrRecordNotFound)
+
+	_, err := s.repository.GetApiKey(1)
+
+	require.ErrorIs(s.T(), err, gorm.ErrRecordNotFound)
+}
+
+func (s *Suite) Test_repository_Get_All_ApiKeys() {
+	var (
+		id    = 1
+		owner = "jobs"
+	)
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "api_keys" WHERE LOWER(owner) = $1 AND revoked_at IS NULL ORDER BY id asc LIMIT 10`)).
+		WithArgs(owner).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "owner"}).
+			AddRow(id, owner))
+
+	res, err := s.repository.GetAllApiKeys(dtos.GetApiKeysFilter{Owner: "Jobs", Pagination: dtos.Pagination{Page: 1, Limit: 10}})
+
+	require.NoError(s.T(), err)
+	require.Nil(s.T(), deep.Equal([]entities.ApiKey{{Id: id, Owner: owner}}, res))
+}
+
+func (s *Suite) Test_repository_Revoke_ApiKey() {
+	var (
+		id        = 1
+		revokedAt = time.Now()
+	)
+
+	s.mock.ExpectBegin()
+
+	s.mock.ExpectExec(regexp.QuoteMeta(
+		`UPDATE "api_keys" SET "revoked_at"=$1 WHERE "id" = $2`)).
+		WithArgs(revokedAt, id).
+		WillReturnResult(sqlmock.NewResult(int64(id), 1))
+
+	s.mock.ExpectCommit()
+
+	err := s.repository.RevokeApiKey(id, revokedAt)
+
+	require.NoError(s.T(), err)
+}
+
+func (s *Suite) Test_repository_Touch_ApiKey_Error() {
+	var (
+		id          = 1
+		usedAt      = time.Now()
+		staleBefore = usedAt.Add(-time.Minute)
+	)
+
+	s.mock.ExpectBegin()
+
+	s.mock.ExpectExec(regexp.QuoteMeta(
+		`UPDATE "api_keys" SET "last_used_at"=$1 WHERE (last_used_at IS NULL OR last_used_at < $2) AND "id" = $3`)).
+		WithArgs(usedAt, staleBefore, id).
+		WillReturnError(context.Canceled)
+
+	s.mock.ExpectRollback()
+
+	err := s.repository.TouchApiKey(id, usedAt, staleBefore)
+
+	require.Error(s.T(), err)
+}
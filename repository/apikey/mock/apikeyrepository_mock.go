@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"github/brunojoenk/golang-test/models/dtos"
+	"github/brunojoenk/golang-test/models/entities"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type ApiKeyRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *ApiKeyRepositoryMock) CreateApiKey(apiKey entities.ApiKey) (entities.ApiKey, error) {
+	args := m.Called(apiKey)
+	return args.Get(0).(entities.ApiKey), args.Error(1)
+}
+
+func (m *ApiKeyRepositoryMock) GetApiKey(id int) (entities.ApiKey, error) {
+	args := m.Called(id)
+	return args.Get(0).(entities.ApiKey), args.Error(1)
+}
+
+func (m *ApiKeyRepositoryMock) GetApiKeyByHash(keyHash string) (entities.ApiKey, error) {
+	args := m.Called(keyHash)
+	return args.Get(0).(entities.ApiKey), args.Error(1)
+}
+
+func (m *ApiKeyRepositoryMock) GetAllApiKeys(filter dtos.GetApiKeysFilter) ([]entities.ApiKey, error) {
+	args := m.Called(filter)
+	return args.Get(0).([]entities.ApiKey), args.Error(1)
+}
+
+func (m *ApiKeyRepositoryMock) RevokeApiKey(id int, revokedAt time.Time) error {
+	args := m.Called(id, revokedAt)
+	return args.Error(0)
+}
+
+func (m *ApiKeyRepositoryMock) TouchApiKey(id int, usedAt, staleBefore time.Time) error {
+	args := m.Called(id, usedAt, staleBefore)
+	return args.Error(0)
+}
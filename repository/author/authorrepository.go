@@ -14,6 +14,18 @@ type IAuthorRepository interface {
 	CreateAuthorInBatch(author []entities.Author, batchSize int) error
 	GetAuthor(id int) (entities.Author, error)
 	GetAllAuthors(filter dtos.GetAuthorsFilter) ([]entities.Author, error)
+	GetAuthorsByNormalizedNames(normalizedNames []string) ([]entities.Author, error)
+	GetAuthorsWithSharedLastName() ([]entities.Author, error)
+	MergeAuthors(target, source entities.Author) error
+	GetAuthorsWithoutNormalizedName(afterId, limit int) ([]entities.Author, error)
+	UpdateNormalizedName(id int, normalizedName string) error
+	GetAliases(authorId int) ([]entities.AuthorAlias, error)
+	GetAlias(authorId, aliasId int) (entities.AuthorAlias, error)
+	GetAliasesByNormalizedNames(normalizedNames []string) ([]entities.AuthorAlias, error)
+	CreateAlias(alias entities.AuthorAlias) (entities.AuthorAlias, error)
+	UpdateAlias(alias entities.AuthorAlias) (entities.AuthorAlias, error)
+	DeleteAlias(aliasId int) error
+	CreateImportRun(run entities.ImportRun) error
 }
 
 // AuthorsRepository Author Repository
@@ -43,7 +55,7 @@ func (a *AuthorRepository) GetAuthor(id int) (entities.Author, error) {
 
 	var author entities.Author
 
-	if result := a.db.Find(&author, id); result.Error != nil {
+	if result := a.db.First(&author, id); result.Error != nil {
 		log.Error("Error on get author: ", result.Error.Error())
 		return author, result.Error
 	}
@@ -57,7 +69,20 @@ func (a *AuthorRepository) GetAllAuthors(filter dtos.GetAuthorsFilter) ([]entiti
 	toExec := a.db
 
 	if strings.TrimSpace(filter.Name) != "" {
-		toExec = toExec.Where("LOWER(name) LIKE ?", "%"+strings.ToLower(filter.Name)+"%")
+		name := "%" + strings.ToLower(filter.Name) + "%"
+		toExec = toExec.Where("LOWER(name) LIKE ? OR id IN (SELECT author_id FROM author_aliases WHERE LOWER(name) LIKE ?)", name, name)
+	}
+
+	if strings.TrimSpace(filter.Nationality) != "" {
+		toExec = toExec.Where("nationality = ?", filter.Nationality)
+	}
+
+	if strings.TrimSpace(filter.BornBefore) != "" {
+		toExec = toExec.Where("birth_date < ?", filter.BornBefore)
+	}
+
+	if strings.TrimSpace(filter.BornAfter) != "" {
+		toExec = toExec.Where("birth_date > ?", filter.BornAfter)
 	}
 
 	toExec = toExec.Offset((filter.Page - 1) * filter.Limit).Limit(filter.Limit).Order("name asc")
@@ -69,3 +94,193 @@ func (a *AuthorRepository) GetAllAuthors(filter dtos.GetAuthorsFilter) ([]entiti
 
 	return authors, nil
 }
+
+func (a *AuthorRepository) GetAuthorsByNormalizedNames(normalizedNames []string) ([]entities.Author, error) {
+
+	var authors []entities.Author
+
+	if result := a.db.Where("normalized_name IN ?", normalizedNames).Find(&authors); result.Error != nil {
+		log.Error("Error on get authors by normalized names: ", result.Error.Error())
+		return nil, result.Error
+	}
+
+	return authors, nil
+}
+
+// GetAuthorsWithSharedLastName returns the authors whose last name (the last word of the normalized
+// name) is shared with another author, ordered by last name. Only those can be duplicates of each other
+func (a *AuthorRepository) GetAuthorsWithSharedLastName() ([]entities.Author, error) {
+
+	var authors []entities.Author
+	lastName := "regexp_replace(normalized_name, '^.* ', '')"
+
+	sharedLastNames := a.db.Model(&entities.Author{}).
+		Select(lastName).
+		Where("normalized_name <> ''").
+		Group(lastName).
+		Having("COUNT(*) > 1")
+
+	if result := a.db.Where(lastName+" IN (?)", sharedLastNames).
+		Order(lastName + " asc").Order("id asc").
+		Find(&authors); result.Error != nil {
+		log.Error("Error on get authors with shared last name: ", result.Error.Error())
+		return nil, result.Error
+	}
+
+	return authors, nil
+}
+
+// MergeAuthors moves the book and work links and aliases of source to the target author, keeps the source
+// name as an alias of the target and deletes source. Aliases the target already has, or that are its own
+// name, are dropped instead of moved
+func (a *AuthorRepository) MergeAuthors(target, source entities.Author) error {
+	return a.db.Transaction(func(tx *gorm.DB) error {
+		if result := tx.Exec("INSERT INTO author_book (book_id, author_id, role, position) "+
+			"SELECT book_id, ?, role, position FROM author_book WHERE author_id = ? ON CONFLICT DO NOTHING", target.Id, source.Id); result.Error != nil {
+			log.Error("Error on move books to merged author: ", result.Error.Error())
+			return result.Error
+		}
+
+		if result := tx.Exec("DELETE FROM author_book WHERE author_book.author_id = ?", source.Id); result.Error != nil {
+			log.Error("Error on delete relations from author_book: ", result.Error.Error())
+			return result.Error
+		}
+
+		if result := tx.Exec("INSERT INTO author_work (work_id, author_id) "+
+			"SELECT work_id, ? FROM author_work WHERE author_id = ? ON CONFLICT DO NOTHING", target.Id, source.Id); result.Error != nil {
+			log.Error("Error on move works to merged author: ", result.Error.Error())
+			return result.Error
+		}
+
+		if result := tx.Exec("DELETE FROM author_work WHERE author_work.author_id = ?", source.Id); result.Error != nil {
+			log.Error("Error on delete relations from author_work: ", result.Error.Error())
+			return result.Error
+		}
+
+		if result := tx.Where("author_id = ? AND (normalized_name = ? OR normalized_name IN "+
+			"(SELECT normalized_name FROM author_aliases WHERE author_id = ?))", source.Id, target.NormalizedName, target.Id).
+			Delete(&entities.AuthorAlias{}); result.Error != nil {
+			log.Error("Error on delete aliases the merged author already has: ", result.Error.Error())
+			return result.Error
+		}
+
+		if result := tx.Model(&entities.AuthorAlias{}).Where("author_id = ?", source.Id).
+			Update("author_id", target.Id); result.Error != nil {
+			log.Error("Error on move aliases to merged author: ", result.Error.Error())
+			return result.Error
+		}
+
+		if source.NormalizedName != target.NormalizedName {
+			alias := entities.AuthorAlias{AuthorId: target.Id, Name: source.Name, NormalizedName: source.NormalizedName}
+			if result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&alias); result.Error != nil {
+				log.Error("Error on create alias of merged author: ", result.Error.Error())
+				return result.Error
+			}
+		}
+
+		if result := tx.Delete(&entities.Author{}, source.Id); result.Error != nil {
+			log.Error("Error on delete merged author: ", result.Error.Error())
+			return result.Error
+		}
+
+		return nil
+	})
+}
+
+func (a *AuthorRepository) GetAuthorsWithoutNormalizedName(afterId, limit int) ([]entities.Author, error) {
+	var authors []entities.Author
+
+	if result := a.db.Where("(normalized_name = '' OR normalized_name IS NULL) AND id > ?", afterId).
+		Order("id asc").Limit(limit).Find(&authors); result.Error != nil {
+		log.Error("Error on get authors without normalized name: ", result.Error.Error())
+		return nil, result.Error
+	}
+
+	return authors, nil
+}
+
+func (a *AuthorRepository) UpdateNormalizedName(id int, normalizedName string) error {
+	if result := a.db.Model(&entities.Author{}).Where("id = ?", id).
+		Update("normalized_name", normalizedName); result.Error != nil {
+		log.Error("Error on update author normalized name: ", result.Error.Error())
+		return result.Error
+	}
+
+	return nil
+}
+
+func (a *AuthorRepository) GetAliases(authorId int) ([]entities.AuthorAlias, error) {
+
+	var aliases []entities.AuthorAlias
+
+	if result := a.db.Where("author_id = ?", authorId).Order("name asc").Find(&aliases); result.Error != nil {
+		log.Error("Error on get author aliases: ", result.Error.Error())
+		return nil, result.Error
+	}
+
+	return aliases, nil
+}
+
+func (a *AuthorRepository) GetAlias(authorId, aliasId int) (entities.AuthorAlias, error) {
+
+	var alias entities.AuthorAlias
+
+	if result := a.db.Where("author_id = ?", authorId).First(&alias, aliasId); result.Error != nil {
+		log.Error("Error on get author alias: ", result.Error.Error())
+		return alias, result.Error
+	}
+
+	return alias, nil
+}
+
+func (a *AuthorRepository) GetAliasesByNormalizedNames(normalizedNames []string) ([]entities.AuthorAlias, error) {
+
+	var aliases []entities.AuthorAlias
+
+	if result := a.db.Where("normalized_name IN ?", normalizedNames).Order("id asc").Find(&aliases); result.Error != nil {
+		log.Error("Error on get author aliases by normalized names: ", result.Error.Error())
+		return nil, result.Error
+	}
+
+	return aliases, nil
+}
+
+func (a *AuthorRepository) CreateAlias(alias entities.AuthorAlias) (entities.AuthorAlias, error) {
+
+	if result := a.db.Create(&alias); result.Error != nil {
+		log.Error("Error on create author alias: ", result.Error.Error())
+		return entities.AuthorAlias{}, result.Error
+	}
+
+	return alias, nil
+}
+
+func (a *AuthorRepository) UpdateAlias(alias entities.AuthorAlias) (entities.AuthorAlias, error) {
+
+	if result := a.db.Save(&alias); result.Error != nil {
+		log.Error("Error on update author alias: ", result.Error.Error())
+		return entities.AuthorAlias{}, result.Error
+	}
+
+	return alias, nil
+}
+
+func (a *AuthorRepository) DeleteAlias(aliasId int) error {
+
+	if result := a.db.Delete(&entities.AuthorAlias{}, aliasId); result.Error != nil {
+		log.Error("Error on delete author alias: ", result.Error.Error())
+		return result.Error
+	}
+
+	return nil
+}
+
+func (a *AuthorRepository) CreateImportRun(run entities.ImportRun) error {
+
+	if result := a.db.Create(&run); result.Error != nil {
+		log.Error("Error on create import run: ", result.Error.Error())
+		return result.Error
+	}
+
+	return nil
+}
@@ -0,0 +1,60 @@
+//go:build integration
+
+package repository
+
+import (
+	"github/brunojoenk/golang-test/models/entities"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// Two authors with the same alias, whose names also normalize the same, are merged against a real
+// database. idx_author_alias allows an alias once per author, so the alias of source is dropped rather
+// than moved, and the target gets no alias of its own name
+func TestMergeAuthorsSharingAlias(t *testing.T) {
+	dataSourceName := os.Getenv("TEST_DATABASE_URL")
+	if dataSourceName == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+	db, err := gorm.Open(postgres.Open(dataSourceName), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&entities.Author{}, &entities.AuthorAlias{}))
+
+	suffix := time.Now().Format("20060102150405.000000000")
+	normalizedName := "j r r tolkien " + suffix
+	authors := []entities.Author{
+		{Name: "J. R. R. Tolkien " + suffix, NormalizedName: normalizedName},
+		{Name: "Tolkien, J.R.R. " + suffix, NormalizedName: normalizedName},
+	}
+	require.NoError(t, db.Create(&authors).Error)
+	target, source := authors[0], authors[1]
+	aliases := []entities.AuthorAlias{
+		{AuthorId: target.Id, Name: "JRR Tolkien", NormalizedName: "jrr tolkien " + suffix},
+		{AuthorId: source.Id, Name: "JRR Tolkien", NormalizedName: "jrr tolkien " + suffix},
+		{AuthorId: source.Id, Name: "John Ronald Reuel Tolkien", NormalizedName: "john ronald reuel tolkien " + suffix},
+	}
+	require.NoError(t, db.Create(&aliases).Error)
+	t.Cleanup(func() {
+		db.Where("author_id IN ?", []int{target.Id, source.Id}).Delete(&entities.AuthorAlias{})
+		db.Delete(&authors)
+	})
+
+	require.NoError(t, NewAuthorRepository(db).MergeAuthors(target, source))
+
+	var merged []string
+	require.NoError(t, db.Model(&entities.AuthorAlias{}).Where("author_id = ?", target.Id).
+		Order("normalized_name asc").Pluck("normalized_name", &merged).Error)
+	require.Equal(t, []string{"john ronald reuel tolkien " + suffix, "jrr tolkien " + suffix}, merged)
+
+	var sourceCount int64
+	require.NoError(t, db.Model(&entities.Author{}).Where("id = ?", source.Id).Count(&sourceCount).Error)
+	require.Zero(t, sourceCount)
+}
@@ -7,6 +7,7 @@ import (
 	"github/brunojoenk/golang-test/models/entities"
 	"regexp"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/go-test/deep"
@@ -71,6 +72,18 @@ func (s *Suite) Test_repository_Get_Author() {
 	require.Nil(s.T(), deep.Equal(entities.Author{Id: id, Name: name}, res))
 }
 
+func (s *Suite) Test_repository_Get_Author_Not_Found() {
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "authors" WHERE "authors"."id" = $1 ORDER BY "authors"."id" LIMIT 1`)).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}))
+
+	_, err := s.repository.GetAuthor(1)
+
+	require.ErrorIs(s.T(), err, gorm.ErrRecordNotFound)
+}
+
 func (s *Suite) Test_repository_Get_Author_Error() {
 
 	s.mock.ExpectQuery(regexp.QuoteMeta(
@@ -118,7 +131,8 @@ func (s *Suite) Test_repository_Get_All_Authors_Filter_Name() {
 	)
 
 	s.mock.ExpectQuery(regexp.QuoteMeta(
-		`SELECT * FROM "authors" WHERE LOWER(name) LIKE $1`)).
+		`SELECT * FROM "authors" WHERE LOWER(name) LIKE $1 OR id IN (SELECT author_id FROM author_aliases WHERE LOWER(name) LIKE $2)`)).
+		WithArgs("%"+name+"%", "%"+name+"%").
 		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).
 			AddRow(id, name))
 
@@ -130,20 +144,22 @@ func (s *Suite) Test_repository_Get_All_Authors_Filter_Name() {
 
 func (s *Suite) Test_repository_Create_Author() {
 	var (
-		id   = 1
-		name = "test-name"
+		id             = 1
+		name           = "test-name"
+		normalizedName = "test name"
 	)
 
 	s.mock.ExpectBegin()
 
 	s.mock.ExpectQuery(regexp.QuoteMeta(
-		`INSERT INTO "authors" ("name") VALUES ($1)`)).
-		WithArgs(name).
+		`INSERT INTO "authors" ("name","normalized_name","birth_date","death_date","nationality","biography","website","orcid","viaf","wikidata_id") `+
+			`VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10) ON CONFLICT ("name") DO NOTHING`)).
+		WithArgs(name, normalizedName, nil, nil, "", "", "", "", "", "").
 		WillReturnRows(sqlmock.NewRows([]string{"id"}).
 			AddRow(id))
 	s.mock.ExpectCommit()
 
-	err := s.repository.CreateAuthorInBatch([]entities.Author{{Name: name}}, 1)
+	err := s.repository.CreateAuthorInBatch([]entities.Author{{Name: name, NormalizedName: normalizedName}}, 1)
 
 	require.NoError(s.T(), err)
 }
@@ -156,8 +172,7 @@ func (s *Suite) Test_repository_Create_Author_Error() {
 	s.mock.ExpectBegin()
 
 	s.mock.ExpectQuery(regexp.QuoteMeta(
-		`INSERT INTO "authors" ("name") VALUES ($1)`)).
-		WithArgs(name).
+		`INSERT INTO "authors" ("name","normalized_name"`)).
 		WillReturnError(context.Canceled)
 
 	s.mock.ExpectRollback()
@@ -166,3 +181,475 @@ func (s *Suite) Test_repository_Create_Author_Error() {
 
 	require.Error(s.T(), err)
 }
+
+func (s *Suite) Test_repository_Get_All_Authors_Filter_Profile() {
+	var (
+		id   = 1
+		name = "test-name"
+	)
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "authors" WHERE nationality = $1 AND birth_date < $2 AND birth_date > $3 ORDER BY name asc LIMIT 10 OFFSET 10`)).
+		WithArgs("BR", "1950-01-01", "1900-12-31").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "nationality"}).
+			AddRow(id, name, "BR"))
+
+	res, err := s.repository.GetAllAuthors(dtos.GetAuthorsFilter{
+		Nationality: "BR",
+		BornBefore:  "1950-01-01",
+		BornAfter:   "1900-12-31",
+		Pagination:  dtos.Pagination{Page: 2, Limit: 10},
+	})
+
+	require.NoError(s.T(), err)
+	require.Nil(s.T(), deep.Equal([]entities.Author{{Id: id, Name: name, Nationality: "BR"}}, res))
+}
+
+func (s *Suite) Test_repository_Get_Authors_By_Normalized_Names() {
+	var (
+		id             = 1
+		name           = "J. R. R. Tolkien"
+		normalizedName = "j r r tolkien"
+	)
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "authors" WHERE normalized_name IN ($1,$2)`)).
+		WithArgs(normalizedName, "c s lewis").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "normalized_name"}).
+			AddRow(id, name, normalizedName))
+
+	res, err := s.repository.GetAuthorsByNormalizedNames([]string{normalizedName, "c s lewis"})
+
+	require.NoError(s.T(), err)
+	require.Nil(s.T(), deep.Equal([]entities.Author{{Id: id, Name: name, NormalizedName: normalizedName}}, res))
+}
+
+func (s *Suite) Test_repository_Get_Authors_By_Normalized_Names_Error() {
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "authors" WHERE normalized_name IN ($1)`)).
+		WillReturnError(context.Canceled)
+
+	_, err := s.repository.GetAuthorsByNormalizedNames([]string{"j r r tolkien"})
+
+	require.Error(s.T(), err)
+}
+
+func (s *Suite) Test_repository_Get_Authors_With_Shared_Last_Name() {
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "authors" WHERE regexp_replace(normalized_name, '^.* ', '') IN ` +
+			`(SELECT regexp_replace(normalized_name, '^.* ', '') FROM "authors" WHERE normalized_name <> '' ` +
+			`GROUP BY regexp_replace(normalized_name, '^.* ', '') HAVING COUNT(*) > 1) ` +
+			`ORDER BY regexp_replace(normalized_name, '^.* ', '') asc,id asc`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "normalized_name"}).
+			AddRow(1, "J. R. R. Tolkien", "j r r tolkien").
+			AddRow(2, "John Ronald Reuel Tolkien", "john ronald reuel tolkien"))
+
+	res, err := s.repository.GetAuthorsWithSharedLastName()
+
+	require.NoError(s.T(), err)
+	require.Nil(s.T(), deep.Equal([]entities.Author{
+		{Id: 1, Name: "J. R. R. Tolkien", NormalizedName: "j r r tolkien"},
+		{Id: 2, Name: "John Ronald Reuel Tolkien", NormalizedName: "john ronald reuel tolkien"},
+	}, res))
+}
+
+func (s *Suite) Test_repository_Get_Authors_With_Shared_Last_Name_Error() {
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "authors"`)).
+		WillReturnError(context.Canceled)
+
+	_, err := s.repository.GetAuthorsWithSharedLastName()
+
+	require.Error(s.T(), err)
+}
+
+func (s *Suite) Test_repository_Merge_Authors() {
+	var (
+		target = entities.Author{Id: 1, Name: "John Ronald Reuel Tolkien", NormalizedName: "john ronald reuel tolkien"}
+		source = entities.Author{Id: 2, Name: "Tolkien, J.R.R.", NormalizedName: "j r r tolkien"}
+	)
+
+	s.mock.ExpectBegin()
+
+	s.mock.ExpectExec(regexp.QuoteMeta(
+		`INSERT INTO author_book (book_id, author_id, role, position) SELECT book_id, $1, role, position FROM author_book WHERE author_id = $2 ON CONFLICT DO NOTHING`)).
+		WithArgs(target.Id, source.Id).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	s.mock.ExpectExec(regexp.QuoteMeta(
+		`DELETE FROM author_book WHERE author_book.author_id = $1`)).
+		WithArgs(source.Id).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	s.mock.ExpectExec(regexp.QuoteMeta(
+		`INSERT INTO author_work (work_id, author_id) SELECT work_id, $1 FROM author_work WHERE author_id = $2 ON CONFLICT DO NOTHING`)).
+		WithArgs(target.Id, source.Id).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	s.mock.ExpectExec(regexp.QuoteMeta(
+		`DELETE FROM author_work WHERE author_work.author_id = $1`)).
+		WithArgs(source.Id).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	s.mock.ExpectExec(regexp.QuoteMeta(
+		`DELETE FROM "author_aliases" WHERE author_id = $1 AND (normalized_name = $2 OR normalized_name IN (SELECT normalized_name FROM author_aliases WHERE author_id = $3))`)).
+		WithArgs(source.Id, target.NormalizedName, target.Id).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	s.mock.ExpectExec(regexp.QuoteMeta(
+		`UPDATE "author_aliases" SET "author_id"=$1 WHERE author_id = $2`)).
+		WithArgs(target.Id, source.Id).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`INSERT INTO "author_aliases" ("author_id","name","normalized_name") VALUES ($1,$2,$3) ON CONFLICT DO NOTHING RETURNING "id"`)).
+		WithArgs(target.Id, source.Name, source.NormalizedName).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+
+	s.mock.ExpectExec(regexp.QuoteMeta(
+		`DELETE FROM "authors" WHERE "authors"."id" = $1`)).
+		WithArgs(source.Id).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	s.mock.ExpectCommit()
+
+	err := s.repository.MergeAuthors(target, source)
+
+	require.NoError(s.T(), err)
+}
+
+// Both authors have the alias "J.R.R. Tolkien" and their names normalize the same: the alias of source is
+// dropped rather than moved, and its name is not added as an alias of the target
+func (s *Suite) Test_repository_Merge_Authors_Sharing_Alias() {
+	var (
+		target = entities.Author{Id: 1, Name: "J. R. R. Tolkien", NormalizedName: "j r r tolkien"}
+		source = entities.Author{Id: 2, Name: "Tolkien, J.R.R.", NormalizedName: "j r r tolkien"}
+	)
+
+	s.mock.ExpectBegin()
+
+	s.mock.ExpectExec(regexp.QuoteMeta(
+		`INSERT INTO author_book (book_id, author_id, role, position) SELECT book_id, $1, role, position FROM author_book WHERE author_id = $2 ON CONFLICT DO NOTHING`)).
+		WithArgs(target.Id, source.Id).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	s.mock.ExpectExec(regexp.QuoteMeta(
+		`DELETE FROM author_book WHERE author_book.author_id = $1`)).
+		WithArgs(source.Id).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	s.mock.ExpectExec(regexp.QuoteMeta(
+		`INSERT INTO author_work (work_id, author_id) SELECT work_id, $1 FROM author_work WHERE author_id = $2 ON CONFLICT DO NOTHING`)).
+		WithArgs(target.Id, source.Id).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	s.mock.ExpectExec(regexp.QuoteMeta(
+		`DELETE FROM author_work WHERE author_work.author_id = $1`)).
+		WithArgs(source.Id).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	s.mock.ExpectExec(regexp.QuoteMeta(
+		`DELETE FROM "author_aliases" WHERE author_id = $1 AND (normalized_name = $2 OR normalized_name IN (SELECT normalized_name FROM author_aliases WHERE author_id = $3))`)).
+		WithArgs(source.Id, target.NormalizedName, target.Id).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	s.mock.ExpectExec(regexp.QuoteMeta(
+		`UPDATE "author_aliases" SET "author_id"=$1 WHERE author_id = $2`)).
+		WithArgs(target.Id, source.Id).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	s.mock.ExpectExec(regexp.QuoteMeta(
+		`DELETE FROM "authors" WHERE "authors"."id" = $1`)).
+		WithArgs(source.Id).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	s.mock.ExpectCommit()
+
+	err := s.repository.MergeAuthors(target, source)
+
+	require.NoError(s.T(), err)
+}
+
+func (s *Suite) Test_repository_Merge_Authors_Error_On_Move_Books() {
+	var (
+		target = entities.Author{Id: 1, Name: "J. R. R. Tolkien"}
+		source = entities.Author{Id: 2, Name: "Tolkien, J.R.R."}
+	)
+
+	s.mock.ExpectBegin()
+
+	s.mock.ExpectExec(regexp.QuoteMeta(
+		`INSERT INTO author_book (book_id, author_id, role, position)`)).
+		WithArgs(target.Id, source.Id).
+		WillReturnError(context.Canceled)
+
+	s.mock.ExpectRollback()
+
+	err := s.repository.MergeAuthors(target, source)
+
+	require.Error(s.T(), err)
+}
+
+func (s *Suite) Test_repository_Merge_Authors_Error_On_Move_Works() {
+	var (
+		target = entities.Author{Id: 1, Name: "J. R. R. Tolkien"}
+		source = entities.Author{Id: 2, Name: "Tolkien, J.R.R."}
+	)
+
+	s.mock.ExpectBegin()
+
+	s.mock.ExpectExec(regexp.QuoteMeta(
+		`INSERT INTO author_book (book_id, author_id, role, position)`)).
+		WithArgs(target.Id, source.Id).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	s.mock.ExpectExec(regexp.QuoteMeta(
+		`DELETE FROM author_book WHERE author_book.author_id = $1`)).
+		WithArgs(source.Id).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	s.mock.ExpectExec(regexp.QuoteMeta(
+		`INSERT INTO author_work (work_id, author_id)`)).
+		WithArgs(target.Id, source.Id).
+		WillReturnError(context.Canceled)
+
+	s.mock.ExpectRollback()
+
+	err := s.repository.MergeAuthors(target, source)
+
+	require.Error(s.T(), err)
+}
+
+func (s *Suite) Test_repository_Backfill_Normalized_Name() {
+	var (
+		id             = 1
+		name           = "Tolkien, J.R.R."
+		normalizedName = "j r r tolkien"
+	)
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "authors" WHERE (normalized_name = '' OR normalized_name IS NULL) AND id > $1 ORDER BY id asc LIMIT 500`)).
+		WithArgs(0).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).
+			AddRow(id, name))
+
+	s.mock.ExpectBegin()
+
+	s.mock.ExpectExec(regexp.QuoteMeta(
+		`UPDATE "authors" SET "normalized_name"=$1 WHERE id = $2`)).
+		WithArgs(normalizedName, id).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	s.mock.ExpectCommit()
+
+	authors, err := s.repository.GetAuthorsWithoutNormalizedName(0, 500)
+	require.NoError(s.T(), err)
+	require.Nil(s.T(), deep.Equal([]entities.Author{{Id: id, Name: name}}, authors))
+
+	err = s.repository.UpdateNormalizedName(id, normalizedName)
+	require.NoError(s.T(), err)
+}
+
+func (s *Suite) Test_repository_Get_Aliases() {
+	var (
+		authorId = 1
+		aliasId  = 3
+		name     = "Richard Bachman"
+	)
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "author_aliases" WHERE author_id = $1 ORDER BY name asc`)).
+		WithArgs(authorId).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "author_id", "name"}).
+			AddRow(aliasId, authorId, name))
+
+	res, err := s.repository.GetAliases(authorId)
+
+	require.NoError(s.T(), err)
+	require.Nil(s.T(), deep.Equal([]entities.AuthorAlias{{Id: aliasId, AuthorId: authorId, Name: name}}, res))
+}
+
+func (s *Suite) Test_repository_Get_Aliases_Error() {
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "author_aliases"`)).
+		WillReturnError(context.Canceled)
+
+	_, err := s.repository.GetAliases(1)
+
+	require.Error(s.T(), err)
+}
+
+func (s *Suite) Test_repository_Get_Alias() {
+	var (
+		authorId = 1
+		aliasId  = 3
+		name     = "Richard Bachman"
+	)
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "author_aliases" WHERE author_id = $1 AND "author_aliases"."id" = $2 ORDER BY "author_aliases"."id" LIMIT 1`)).
+		WithArgs(authorId, aliasId).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "author_id", "name"}).
+			AddRow(aliasId, authorId, name))
+
+	res, err := s.repository.GetAlias(authorId, aliasId)
+
+	require.NoError(s.T(), err)
+	require.Nil(s.T(), deep.Equal(entities.AuthorAlias{Id: aliasId, AuthorId: authorId, Name: name}, res))
+}
+
+func (s *Suite) Test_repository_Get_Alias_Not_Found() {
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "author_aliases" WHERE author_id = $1`)).
+		WithArgs(1, 3).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "author_id", "name"}))
+
+	_, err := s.repository.GetAlias(1, 3)
+
+	require.ErrorIs(s.T(), err, gorm.ErrRecordNotFound)
+}
+
+func (s *Suite) Test_repository_Get_Aliases_By_Normalized_Names() {
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "author_aliases" WHERE normalized_name IN ($1) ORDER BY id asc`)).
+		WithArgs("richard bachman").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "author_id", "name", "normalized_name"}).
+			AddRow(3, 1, "Richard Bachman", "richard bachman"))
+
+	res, err := s.repository.GetAliasesByNormalizedNames([]string{"richard bachman"})
+
+	require.NoError(s.T(), err)
+	require.Nil(s.T(), deep.Equal([]entities.AuthorAlias{{Id: 3, AuthorId: 1, Name: "Richard Bachman", NormalizedName: "richard bachman"}}, res))
+}
+
+func (s *Suite) Test_repository_Get_Aliases_By_Normalized_Names_Error() {
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "author_aliases"`)).
+		WillReturnError(context.Canceled)
+
+	_, err := s.repository.GetAliasesByNormalizedNames([]string{"richard bachman"})
+
+	require.Error(s.T(), err)
+}
+
+func (s *Suite) Test_repository_Create_Alias() {
+	alias := entities.AuthorAlias{AuthorId: 1, Name: "Richard Bachman", NormalizedName: "richard bachman"}
+
+	s.mock.ExpectBegin()
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`INSERT INTO "author_aliases" ("author_id","name","normalized_name") VALUES ($1,$2,$3) RETURNING "id"`)).
+		WithArgs(alias.AuthorId, alias.Name, alias.NormalizedName).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))
+
+	s.mock.ExpectCommit()
+
+	res, err := s.repository.CreateAlias(alias)
+
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), 3, res.Id)
+}
+
+func (s *Suite) Test_repository_Create_Alias_Error() {
+
+	s.mock.ExpectBegin()
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`INSERT INTO "author_aliases"`)).
+		WillReturnError(context.Canceled)
+
+	s.mock.ExpectRollback()
+
+	_, err := s.repository.CreateAlias(entities.AuthorAlias{AuthorId: 1, Name: "Richard Bachman"})
+
+	require.Error(s.T(), err)
+}
+
+func (s *Suite) Test_repository_Update_Alias() {
+	alias := entities.AuthorAlias{Id: 3, AuthorId: 1, Name: "Richard Bachman", NormalizedName: "richard bachman"}
+
+	s.mock.ExpectBegin()
+
+	s.mock.ExpectExec(regexp.QuoteMeta(
+		`UPDATE "author_aliases" SET "author_id"=$1,"name"=$2,"normalized_name"=$3 WHERE "id" = $4`)).
+		WithArgs(alias.AuthorId, alias.Name, alias.NormalizedName, alias.Id).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	s.mock.ExpectCommit()
+
+	res, err := s.repository.UpdateAlias(alias)
+
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), alias, res)
+}
+
+func (s *Suite) Test_repository_Delete_Alias() {
+
+	s.mock.ExpectBegin()
+
+	s.mock.ExpectExec(regexp.QuoteMeta(
+		`DELETE FROM "author_aliases" WHERE "author_aliases"."id" = $1`)).
+		WithArgs(3).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	s.mock.ExpectCommit()
+
+	err := s.repository.DeleteAlias(3)
+
+	require.NoError(s.T(), err)
+}
+
+func (s *Suite) Test_repository_Delete_Alias_Error() {
+
+	s.mock.ExpectBegin()
+
+	s.mock.ExpectExec(regexp.QuoteMeta(
+		`DELETE FROM "author_aliases"`)).
+		WillReturnError(context.Canceled)
+
+	s.mock.ExpectRollback()
+
+	err := s.repository.DeleteAlias(3)
+
+	require.Error(s.T(), err)
+}
+
+func (s *Suite) Test_repository_Create_Import_Run() {
+	startedAt := time.Date(2022, 10, 1, 10, 0, 0, 0, time.UTC)
+	run := entities.ImportRun{Kind: "authors", Source: "./data/authors.csv", Added: 6, StartedAt: startedAt, FinishedAt: startedAt.Add(time.Second)}
+
+	s.mock.ExpectBegin()
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`INSERT INTO "import_runs" ("kind","source","added","started_at","finished_at") VALUES ($1,$2,$3,$4,$5) RETURNING "id"`)).
+		WithArgs(run.Kind, run.Source, run.Added, run.StartedAt, run.FinishedAt).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+
+	s.mock.ExpectCommit()
+
+	err := s.repository.CreateImportRun(run)
+
+	require.NoError(s.T(), err)
+}
+
+func (s *Suite) Test_repository_Create_Import_Run_Error() {
+
+	s.mock.ExpectBegin()
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`INSERT INTO "import_runs"`)).
+		WillReturnError(context.Canceled)
+
+	s.mock.ExpectRollback()
+
+	err := s.repository.CreateImportRun(entities.ImportRun{Kind: "authors"})
+
+	require.Error(s.T(), err)
+}
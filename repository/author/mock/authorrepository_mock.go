@@ -25,3 +25,63 @@ func (m *AuthorRepositoryMock) GetAuthor(id int) (entities.Author, error) {
 	args := m.Called(id)
 	return args.Get(0).(entities.Author), args.Error(1)
 }
+
+func (m *AuthorRepositoryMock) GetAuthorsByNormalizedNames(normalizedNames []string) ([]entities.Author, error) {
+	args := m.Called(normalizedNames)
+	return args.Get(0).([]entities.Author), args.Error(1)
+}
+
+func (m *AuthorRepositoryMock) GetAuthorsWithSharedLastName() ([]entities.Author, error) {
+	args := m.Called()
+	return args.Get(0).([]entities.Author), args.Error(1)
+}
+
+func (m *AuthorRepositoryMock) MergeAuthors(target, source entities.Author) error {
+	args := m.Called(target, source)
+	return args.Error(0)
+}
+
+func (m *AuthorRepositoryMock) GetAuthorsWithoutNormalizedName(afterId, limit int) ([]entities.Author, error) {
+	args := m.Called(afterId, limit)
+	return args.Get(0).([]entities.Author), args.Error(1)
+}
+
+func (m *AuthorRepositoryMock) UpdateNormalizedName(id int, normalizedName string) error {
+	args := m.Called(id, normalizedName)
+	return args.Error(0)
+}
+
+func (m *AuthorRepositoryMock) GetAliases(authorId int) ([]entities.AuthorAlias, error) {
+	args := m.Called(authorId)
+	return args.Get(0).([]entities.AuthorAlias), args.Error(1)
+}
+
+func (m *AuthorRepositoryMock) GetAlias(authorId, aliasId int) (entities.AuthorAlias, error) {
+	args := m.Called(authorId, aliasId)
+	return args.Get(0).(entities.AuthorAlias), args.Error(1)
+}
+
+func (m *AuthorRepositoryMock) GetAliasesByNormalizedNames(normalizedNames []string) ([]entities.AuthorAlias, error) {
+	args := m.Called(normalizedNames)
+	return args.Get(0).([]entities.AuthorAlias), args.Error(1)
+}
+
+func (m *AuthorRepositoryMock) CreateAlias(alias entities.AuthorAlias) (entities.AuthorAlias, error) {
+	args := m.Called(alias)
+	return args.Get(0).(entities.AuthorAlias), args.Error(1)
+}
+
+func (m *AuthorRepositoryMock) UpdateAlias(alias entities.AuthorAlias) (entities.AuthorAlias, error) {
+	args := m.Called(alias)
+	return args.Get(0).(entities.AuthorAlias), args.Error(1)
+}
+
+func (m *AuthorRepositoryMock) DeleteAlias(aliasId int) error {
+	args := m.Called(aliasId)
+	return args.Error(0)
+}
+
+func (m *AuthorRepositoryMock) CreateImportRun(run entities.ImportRun) error {
+	args := m.Called(run)
+	return args.Error(0)
+}
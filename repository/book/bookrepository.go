@@ -3,19 +3,38 @@ package repository
 import (
 	"github/brunojoenk/golang-test/models/dtos"
 	"github/brunojoenk/golang-test/models/entities"
+	reviewrepo "github/brunojoenk/golang-test/repository/review"
 	"strings"
 
 	log "github.com/sirupsen/logrus"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type IBookRepository interface {
 	CreateBook(book entities.Book) (entities.Book, error)
-	UpdateBook(book entities.Book, authors []entities.Author) (entities.Book, error)
+	UpdateBook(book entities.Book, contributors []entities.AuthorBook, genres []entities.Genre) (entities.Book, error)
 	GetBook(id int) (entities.Book, error)
+	GetBookByIsbn(isbn13 string) (entities.Book, error)
 	GetAllBooks(filter dtos.GetBooksFilter) ([]entities.Book, error)
 	DeleteBook(id int) error
+	GetBooksByNaturalKey(normalizedName, edition string, publicationYear int) ([]entities.Book, error)
+	GetDuplicateBooks(pagination dtos.Pagination) ([]entities.Book, error)
+	GetSeriesBooks(seriesId int) ([]entities.Book, error)
+	GetWorkEditions(workId int, pagination dtos.Pagination) ([]entities.Book, error)
+	MergeBooks(target entities.Book, sourceId int) error
+	GetBooksWithoutNormalizedName(afterId, limit int) ([]entities.Book, error)
+	UpdateNormalizedName(id int, normalizedName string) error
+	AddRoleToContributorsKey() error
+	PrefillBook(id int, fields entities.Book) error
+	GetBooksWithoutWork(afterId, limit int) ([]entities.Book, error)
+	UpdateWork(id, workId int) error
+	AddBookTags(id int, tags []string) error
+	RemoveBookTag(id int, tag string) error
+	CountCopies(id int) (int64, error)
+	GetBookNames(ids []int) ([]entities.Book, error)
+	GetBooks(ids []int) ([]entities.Book, error)
 }
 
 // BookRepository Books Repository
@@ -28,14 +47,26 @@ func NewBookRepository(db *gorm.DB) IBookRepository {
 	return &BookRepository{db: db}
 }
 
+// CreateBook creates the book, and its work when it is a new one, in one transaction
 func (b *BookRepository) CreateBook(book entities.Book) (entities.Book, error) {
 
-	if result := b.db.Create(&book); result.Error != nil {
-		log.Error("Error on create book: ", result.Error.Error())
-		return entities.Book{}, result.Error
+	err := b.db.Transaction(func(tx *gorm.DB) error {
+		if err := createWork(tx, &book); err != nil {
+			return err
+		}
+
+		if result := tx.Omit("Publisher", "Series", "Work", "Contributors.Author").Create(&book); result.Error != nil {
+			log.Error("Error on create book: ", result.Error.Error())
+			return result.Error
+		}
+
+		return nil
+	})
+	if err != nil {
+		return entities.Book{}, err
 	}
 
-	book, err := b.GetBook(book.Id)
+	book, err = b.GetBook(book.Id)
 	if err != nil {
 		log.Error("Error on get book after create: ", err.Error())
 		return entities.Book{}, err
@@ -44,27 +75,89 @@ func (b *BookRepository) CreateBook(book entities.Book) (entities.Book, error) {
 	return book, nil
 }
 
-func (b *BookRepository) UpdateBook(book entities.Book, authors []entities.Author) (entities.Book, error) {
-
-	if err := b.db.Model(&book).Association("Authors").Clear(); err != nil {
-		log.Error("Error on clear authors from book: ", err.Error())
+// UpdateBook saves the book with its contributors and genres in one transaction. A new work of the book
+// is created, and the work it leaves is removed when no other edition is left on it
+func (b *BookRepository) UpdateBook(book entities.Book, contributors []entities.AuthorBook, genres []entities.Genre) (entities.Book, error) {
+
+	err := b.db.Transaction(func(tx *gorm.DB) error {
+		var previous entities.Book
+		if result := tx.Select("id", "work_id").First(&previous, book.Id); result.Error != nil {
+			log.Error("Error on get work of book: ", result.Error.Error())
+			return result.Error
+		}
+
+		if err := createWork(tx, &book); err != nil {
+			return err
+		}
+
+		if result := tx.Where("book_id = ?", book.Id).Delete(&entities.AuthorBook{}); result.Error != nil {
+			log.Error("Error on clear authors from book: ", result.Error.Error())
+			return result.Error
+		}
+
+		if result := tx.Where("book_id = ?", book.Id).Delete(&entities.BookTitle{}); result.Error != nil {
+			log.Error("Error on clear titles from book: ", result.Error.Error())
+			return result.Error
+		}
+
+		if err := tx.Model(&book).Association("Genres").Clear(); err != nil {
+			log.Error("Error on clear genres from book: ", err.Error())
+			return err
+		}
+
+		book.Contributors = contributors
+		book.Genres = genres
+
+		// The rating is kept by the reviews of the book
+		if result := tx.Omit("Publisher", "Series", "Work", "Tags", "Copies", "Contributors.Author", "RatingAverage", "RatingCount").Save(&book); result.Error != nil {
+			log.Error("Error on update book: ", result.Error.Error())
+			return result.Error
+		}
+
+		if previous.WorkId != nil && (book.WorkId == nil || *book.WorkId != *previous.WorkId) {
+			return deleteEmptyWork(tx, *previous.WorkId)
+		}
+		return nil
+	})
+	if err != nil {
 		return entities.Book{}, err
 	}
 
-	book.Authors = authors
+	return book, nil
+}
 
-	if result := b.db.Save(&book); result.Error != nil {
-		log.Error("Error on update book: ", result.Error.Error())
-		return entities.Book{}, result.Error
+// createWork creates the work of the book when it is a new one, linking it to its authors
+func createWork(tx *gorm.DB, book *entities.Book) error {
+	if book.Work == nil || book.Work.Id != 0 {
+		return nil
 	}
 
-	return book, nil
+	if result := tx.Omit("Authors.*").Create(book.Work); result.Error != nil {
+		log.Error("Error on create work of book: ", result.Error.Error())
+		return result.Error
+	}
+	book.WorkId = &book.Work.Id
+	return nil
+}
+
+// deleteEmptyWork removes the work when it has no editions
+func deleteEmptyWork(tx *gorm.DB, workId int) error {
+	if result := tx.Exec("DELETE FROM author_work WHERE work_id = ? AND NOT EXISTS (SELECT 1 FROM books WHERE work_id = ?)", workId, workId); result.Error != nil {
+		log.Error("Error on delete authors of empty work: ", result.Error.Error())
+		return result.Error
+	}
+
+	if result := tx.Exec("DELETE FROM works WHERE id = ? AND NOT EXISTS (SELECT 1 FROM books WHERE work_id = ?)", workId, workId); result.Error != nil {
+		log.Error("Error on delete empty work: ", result.Error.Error())
+		return result.Error
+	}
+	return nil
 }
 
 func (b *BookRepository) GetBook(id int) (entities.Book, error) {
 	var book entities.Book
 
-	if result := b.db.Preload("Authors").First(&book, id); result.Error != nil {
+	if result := b.db.Preload("Contributors", orderContributors).Preload("Contributors.Author").Preload("Copies").Preload("Genres").Preload("Publisher").Preload("Series").Preload("Tags").Preload("Titles").First(&book, id); result.Error != nil {
 		log.Error("Error on preload authors from book: ", result.Error.Error())
 		return book, result.Error
 	}
@@ -72,20 +165,40 @@ func (b *BookRepository) GetBook(id int) (entities.Book, error) {
 	return book, nil
 }
 
+func (b *BookRepository) GetBookByIsbn(isbn13 string) (entities.Book, error) {
+	var book entities.Book
+
+	if result := b.db.Preload("Contributors", orderContributors).Preload("Contributors.Author").Preload("Copies").Preload("Genres").Preload("Publisher").Preload("Series").Preload("Tags").Preload("Titles").Where("isbn13 = ?", isbn13).First(&book); result.Error != nil {
+		log.Error("Error on get book by isbn: ", result.Error.Error())
+		return book, result.Error
+	}
+
+	return book, nil
+}
+
 func (b *BookRepository) GetAllBooks(filter dtos.GetBooksFilter) ([]entities.Book, error) {
 
 	var books []entities.Book
 	toExec := b.db
 
 	if strings.TrimSpace(filter.Author) != "" {
-		toExec = toExec.Joins(
-			"JOIN author_book ON author_book.book_id = books.id " +
-				"JOIN authors ON authors.id = author_book.author_id")
-		toExec = toExec.Where("LOWER(authors.name) LIKE ?", "%"+strings.ToLower(filter.Author)+"%")
+		// A subquery, so a book is listed once whatever the roles of its matching contributors
+		author := "%" + strings.ToLower(filter.Author) + "%"
+		toExec = toExec.Where("books.id IN (SELECT author_book.book_id FROM author_book JOIN authors ON authors.id = author_book.author_id "+
+			"WHERE LOWER(authors.name) LIKE ? OR authors.id IN (SELECT author_id FROM author_aliases WHERE LOWER(name) LIKE ?))", author, author)
 	}
 
 	if strings.TrimSpace(filter.Name) != "" {
-		toExec = toExec.Where("LOWER(books.name) LIKE ?", "%"+strings.ToLower(filter.Name)+"%")
+		name := "%" + strings.ToLower(filter.Name) + "%"
+		toExec = toExec.Where("LOWER(books.name) LIKE ? OR books.id IN (SELECT book_id FROM book_titles WHERE LOWER(title) LIKE ?)", name, name)
+	}
+
+	if filter.Language != "" {
+		toExec = toExec.Where("books.language = ?", filter.Language)
+	}
+
+	if filter.Available {
+		toExec = toExec.Where("books.id IN (SELECT book_id FROM copies WHERE status = ?)", dtos.CopyStatusAvailable)
 	}
 
 	if strings.TrimSpace(filter.Edition) != "" {
@@ -96,9 +209,56 @@ func (b *BookRepository) GetAllBooks(filter dtos.GetBooksFilter) ([]entities.Boo
 		toExec = toExec.Where("books.publication_year = ?", filter.PublicationYear)
 	}
 
-	toExec = toExec.Offset((filter.Page - 1) * filter.Limit).Limit(filter.Limit).Order("name asc")
+	if strings.TrimSpace(filter.Isbn) != "" {
+		toExec = toExec.Where("books.isbn13 = ?", filter.Isbn)
+	}
+
+	if strings.TrimSpace(filter.Publisher) != "" {
+		toExec = toExec.Where("books.publisher_id IN (SELECT id FROM publishers WHERE LOWER(name) LIKE ?)", "%"+strings.ToLower(filter.Publisher)+"%")
+	}
+
+	if filter.PublisherId > 0 {
+		toExec = toExec.Where("books.publisher_id = ?", filter.PublisherId)
+	}
+
+	if strings.TrimSpace(filter.Series) != "" {
+		toExec = toExec.Where("books.series_id IN (SELECT id FROM series WHERE LOWER(name) LIKE ?)", "%"+strings.ToLower(filter.Series)+"%")
+	}
+
+	if filter.SeriesId > 0 {
+		toExec = toExec.Where("books.series_id = ?", filter.SeriesId)
+	}
+
+	if strings.TrimSpace(filter.Genre) != "" {
+		toExec = toExec.Where("books.id IN (SELECT book_id FROM book_genre WHERE genre_id IN ("+
+			"WITH RECURSIVE subgenres AS (SELECT id FROM genres WHERE normalized_name = ? "+
+			"UNION SELECT genres.id FROM genres JOIN subgenres ON genres.parent_id = subgenres.id) "+
+			"SELECT id FROM subgenres))", filter.Genre)
+	}
 
-	if result := toExec.Preload("Authors").Find(&books); result.Error != nil {
+	if strings.TrimSpace(filter.Tags) != "" {
+		tags := strings.Split(filter.Tags, ",")
+		if filter.TagsMatch == dtos.TagsMatchAll {
+			toExec = toExec.Where("books.id IN (SELECT book_tag.book_id FROM book_tag JOIN tags ON tags.id = book_tag.tag_id "+
+				"WHERE tags.name IN ? GROUP BY book_tag.book_id HAVING COUNT(*) = ?)", tags, len(tags))
+		} else {
+			toExec = toExec.Where("books.id IN (SELECT book_tag.book_id FROM book_tag JOIN tags ON tags.id = book_tag.tag_id "+
+				"WHERE tags.name IN ?)", tags)
+		}
+	}
+
+	if filter.MinRating > 0 {
+		toExec = toExec.Where("books.rating_count > 0 AND books.rating_average >= ?", filter.MinRating)
+	}
+
+	toExec = toExec.Offset((filter.Page - 1) * filter.Limit).Limit(filter.Limit)
+	if filter.Sort == dtos.BookSortRating {
+		toExec = toExec.Order("books.rating_average desc, books.rating_count desc, books.name asc")
+	} else {
+		toExec = toExec.Order("name asc")
+	}
+
+	if result := toExec.Preload("Contributors", orderContributors).Preload("Contributors.Author").Preload("Copies").Preload("Genres").Preload("Publisher").Preload("Series").Preload("Tags").Preload("Titles").Find(&books); result.Error != nil {
 		log.Error("Error on preload authors from book (filter): ", result.Error.Error())
 		return nil, result.Error
 	}
@@ -107,22 +267,421 @@ func (b *BookRepository) GetAllBooks(filter dtos.GetBooksFilter) ([]entities.Boo
 }
 
 func (b *BookRepository) DeleteBook(id int) error {
-	var book entities.Book
+	return b.db.Transaction(func(tx *gorm.DB) error {
+		var book entities.Book
+
+		if result := tx.First(&book, id); result.Error != nil {
+			log.Error("Error on get book to delete: ", result.Error.Error())
+			return result.Error
+		}
+
+		if result := tx.Exec("DELETE FROM author_book WHERE author_book.book_id = $1", id); result.Error != nil {
+			log.Error("Error on delete relations from author_book: ", result.Error.Error())
+			return result.Error
+		}
+
+		if result := tx.Exec("DELETE FROM book_genre WHERE book_genre.book_id = $1", id); result.Error != nil {
+			log.Error("Error on delete relations from book_genre: ", result.Error.Error())
+			return result.Error
+		}
+
+		if result := tx.Exec("DELETE FROM book_tag WHERE book_tag.book_id = $1", id); result.Error != nil {
+			log.Error("Error on delete relations from book_tag: ", result.Error.Error())
+			return result.Error
+		}
+
+		if result := tx.Exec("DELETE FROM book_titles WHERE book_titles.book_id = $1", id); result.Error != nil {
+			log.Error("Error on delete titles of book: ", result.Error.Error())
+			return result.Error
+		}
+
+		if result := tx.Delete(&book); result.Error != nil {
+			log.Error("Error delete book: ", result.Error.Error())
+			return result.Error
+		}
+
+		if book.WorkId != nil {
+			return deleteEmptyWork(tx, *book.WorkId)
+		}
+		return nil
+	})
+}
+
+func (b *BookRepository) GetBooksByNaturalKey(normalizedName, edition string, publicationYear int) ([]entities.Book, error) {
+	var books []entities.Book
+
+	if result := b.db.
+		Where("normalized_name = ? AND LOWER(TRIM(edition)) = ? AND publication_year = ?",
+			normalizedName, strings.ToLower(strings.TrimSpace(edition)), publicationYear).
+		Preload("Contributors").Find(&books); result.Error != nil {
+		log.Error("Error on get books by natural key: ", result.Error.Error())
+		return nil, result.Error
+	}
+
+	return books, nil
+}
+
+// GetDuplicateBooks returns the books of a page of normalized names shared by more than one book
+func (b *BookRepository) GetDuplicateBooks(pagination dtos.Pagination) ([]entities.Book, error) {
+	var names []string
+
+	if result := b.db.Model(&entities.Book{}).
+		Where("normalized_name <> ''").
+		Group("normalized_name").
+		Having("COUNT(*) > 1").
+		Order("normalized_name asc").
+		Offset((pagination.Page-1)*pagination.Limit).Limit(pagination.Limit).
+		Pluck("normalized_name", &names); result.Error != nil {
+		log.Error("Error on get duplicate book names: ", result.Error.Error())
+		return nil, result.Error
+	}
+
+	books := make([]entities.Book, 0)
+	if len(names) == 0 {
+		return books, nil
+	}
+
+	if result := b.db.Where("normalized_name IN ?", names).
+		Order("normalized_name asc, id asc").
+		Preload("Contributors", orderContributors).Preload("Contributors.Author").Preload("Copies").Preload("Genres").Preload("Publisher").Preload("Series").Preload("Tags").Preload("Titles").Find(&books); result.Error != nil {
+		log.Error("Error on get duplicate books: ", result.Error.Error())
+		return nil, result.Error
+	}
+
+	return books, nil
+}
+
+// GetSeriesBooks returns the books of a series in reading order. Books without a position come last
+func (b *BookRepository) GetSeriesBooks(seriesId int) ([]entities.Book, error) {
+	books := make([]entities.Book, 0)
+
+	if result := b.db.Where("series_id = ?", seriesId).
+		Order("series_position asc nulls last, name asc").
+		Preload("Contributors", orderContributors).Preload("Contributors.Author").Preload("Copies").Preload("Genres").Preload("Publisher").Preload("Tags").Preload("Titles").Find(&books); result.Error != nil {
+		log.Error("Error on get books of series: ", result.Error.Error())
+		return nil, result.Error
+	}
+
+	return books, nil
+}
+
+// GetWorkEditions returns the editions of a work, oldest first
+func (b *BookRepository) GetWorkEditions(workId int, pagination dtos.Pagination) ([]entities.Book, error) {
+	books := make([]entities.Book, 0)
+
+	if result := b.db.Where("work_id = ?", workId).
+		Order("publication_year asc, id asc").
+		Offset((pagination.Page-1)*pagination.Limit).Limit(pagination.Limit).
+		Preload("Contributors", orderContributors).Preload("Contributors.Author").Preload("Copies").Preload("Genres").Preload("Publisher").Preload("Series").Preload("Tags").Preload("Titles").Find(&books); result.Error != nil {
+		log.Error("Error on get editions of work: ", result.Error.Error())
+		return nil, result.Error
+	}
+
+	return books, nil
+}
+
+// MergeBooks moves the author, genre and tag links, the translated titles, the copies, holds, reviews and
+// reading list books of source to target, deletes source and saves target. The authors of source keep their
+// roles and are credited after those of target. Target is saved last, so it can take unique values (e.g. the
+// ISBN) of source
+func (b *BookRepository) MergeBooks(target entities.Book, sourceId int) error {
+	return b.db.Transaction(func(tx *gorm.DB) error {
+		if err := reviewrepo.LockBooks(tx, []int{target.Id, sourceId}); err != nil {
+			log.Error("Error on lock merged books: ", err.Error())
+			return err
+		}
+
+		var source entities.Book
+		if result := tx.Select("id", "work_id").First(&source, sourceId); result.Error != nil {
+			log.Error("Error on get merged book: ", result.Error.Error())
+			return result.Error
+		}
+
+		if result := tx.Exec("INSERT INTO author_book (book_id, author_id, role, position) "+
+			"SELECT ?, author_id, role, position + (SELECT COALESCE(MAX(position) + 1, 0) FROM author_book WHERE book_id = ?) "+
+			"FROM author_book WHERE book_id = ? ON CONFLICT DO NOTHING", target.Id, target.Id, sourceId); result.Error != nil {
+			log.Error("Error on move authors to merged book: ", result.Error.Error())
+			return result.Error
+		}
+
+		if result := tx.Exec("DELETE FROM author_book WHERE author_book.book_id = ?", sourceId); result.Error != nil {
+			log.Error("Error on delete relations from author_book: ", result.Error.Error())
+			return result.Error
+		}
+
+		if result := tx.Exec("INSERT INTO book_genre (book_id, genre_id) "+
+			"SELECT ?, genre_id FROM book_genre WHERE book_id = ? ON CONFLICT DO NOTHING", target.Id, sourceId); result.Error != nil {
+			log.Error("Error on move genres to merged book: ", result.Error.Error())
+			return result.Error
+		}
+
+		if result := tx.Exec("DELETE FROM book_genre WHERE book_genre.book_id = ?", sourceId); result.Error != nil {
+			log.Error("Error on delete relations from book_genre: ", result.Error.Error())
+			return result.Error
+		}
+
+		if result := tx.Exec("INSERT INTO book_tag (book_id, tag_id) "+
+			"SELECT ?, tag_id FROM book_tag WHERE book_id = ? ON CONFLICT DO NOTHING", target.Id, sourceId); result.Error != nil {
+			log.Error("Error on move tags to merged book: ", result.Error.Error())
+			return result.Error
+		}
+
+		if result := tx.Exec("DELETE FROM book_tag WHERE book_tag.book_id = ?", sourceId); result.Error != nil {
+			log.Error("Error on delete relations from book_tag: ", result.Error.Error())
+			return result.Error
+		}
+
+		if result := tx.Exec("INSERT INTO book_titles (book_id, locale, title) "+
+			"SELECT ?, locale, title FROM book_titles WHERE book_id = ? ON CONFLICT DO NOTHING", target.Id, sourceId); result.Error != nil {
+			log.Error("Error on move titles to merged book: ", result.Error.Error())
+			return result.Error
+		}
+
+		if result := tx.Exec("DELETE FROM book_titles WHERE book_titles.book_id = ?", sourceId); result.Error != nil {
+			log.Error("Error on delete titles of merged book: ", result.Error.Error())
+			return result.Error
+		}
+
+		if result := tx.Exec("UPDATE copies SET book_id = ? WHERE book_id = ?", target.Id, sourceId); result.Error != nil {
+			log.Error("Error on move copies to merged book: ", result.Error.Error())
+			return result.Error
+		}
+
+		// A patron waiting for both books keeps the older place in line of the book kept
+		if result := tx.Exec("UPDATE holds SET status = ?, copy_id = NULL WHERE book_id = ? AND status IN ? "+
+			"AND patron_id IN (SELECT patron_id FROM holds WHERE book_id = ? AND status IN ?)",
+			dtos.HoldStatusCancelled, sourceId, dtos.OpenHoldStatuses, target.Id, dtos.OpenHoldStatuses); result.Error != nil {
+			log.Error("Error on cancel repeated holds of merged book: ", result.Error.Error())
+			return result.Error
+		}
+
+		if result := tx.Exec("UPDATE holds SET book_id = ? WHERE book_id = ?", target.Id, sourceId); result.Error != nil {
+			log.Error("Error on move holds to merged book: ", result.Error.Error())
+			return result.Error
+		}
+
+		// A patron who reviewed both books keeps the review of the book kept
+		if result := tx.Exec("DELETE FROM reviews WHERE book_id = ? AND patron_id IN (SELECT patron_id FROM reviews WHERE book_id = ?)",
+			sourceId, target.Id); result.Error != nil {
+			log.Error("Error on delete repeated reviews of merged book: ", result.Error.Error())
+			return result.Error
+		}
+
+		if result := tx.Exec("UPDATE reviews SET book_id = ? WHERE book_id = ?", target.Id, sourceId); result.Error != nil {
+			log.Error("Error on move reviews to merged book: ", result.Error.Error())
+			return result.Error
+		}
+
+		// A reading list with both books keeps the book kept where it is
+		if result := tx.Exec("DELETE FROM reading_list_entries WHERE book_id = ? AND list_id IN (SELECT list_id FROM reading_list_entries WHERE book_id = ?)",
+			sourceId, target.Id); result.Error != nil {
+			log.Error("Error on delete repeated reading list books of merged book: ", result.Error.Error())
+			return result.Error
+		}
+
+		if result := tx.Exec("UPDATE reading_list_entries SET book_id = ? WHERE book_id = ?", target.Id, sourceId); result.Error != nil {
+			log.Error("Error on move reading list books to merged book: ", result.Error.Error())
+			return result.Error
+		}
+
+		// The book kept keeps its cover, it takes the cover of the merged book only when it has none
+		if result := tx.Exec("UPDATE book_covers SET book_id = ? WHERE book_id = ? AND NOT EXISTS (SELECT 1 FROM book_covers WHERE book_id = ?)",
+			target.Id, sourceId, target.Id); result.Error != nil {
+			log.Error("Error on move cover to merged book: ", result.Error.Error())
+			return result.Error
+		}
+
+		if result := tx.Exec("UPDATE book_files SET book_id = ? WHERE book_id = ?", target.Id, sourceId); result.Error != nil {
+			log.Error("Error on move files to merged book: ", result.Error.Error())
+			return result.Error
+		}
+
+		if result := tx.Delete(&entities.Book{}, sourceId); result.Error != nil {
+			log.Error("Error on delete merged book: ", result.Error.Error())
+			return result.Error
+		}
+
+		if result := tx.Omit(clause.Associations, "RatingAverage", "RatingCount").Save(&target); result.Error != nil {
+			log.Error("Error on save merged book: ", result.Error.Error())
+			return result.Error
+		}
+
+		if err := reviewrepo.UpdateRatings(tx, []int{target.Id}); err != nil {
+			log.Error("Error on update rating of merged book: ", err.Error())
+			return err
+		}
+
+		if source.WorkId != nil && (target.WorkId == nil || *target.WorkId != *source.WorkId) {
+			return deleteEmptyWork(tx, *source.WorkId)
+		}
+		return nil
+	})
+}
+
+// orderContributors preloads the contributors of a book in credit order
+func orderContributors(db *gorm.DB) *gorm.DB {
+	return db.Order("position asc, author_id asc")
+}
+
+func (b *BookRepository) GetBooksWithoutNormalizedName(afterId, limit int) ([]entities.Book, error) {
+	var books []entities.Book
+
+	if result := b.db.Where("(normalized_name = '' OR normalized_name IS NULL) AND id > ?", afterId).
+		Order("id asc").Limit(limit).Find(&books); result.Error != nil {
+		log.Error("Error on get books without normalized name: ", result.Error.Error())
+		return nil, result.Error
+	}
+
+	return books, nil
+}
+
+func (b *BookRepository) UpdateNormalizedName(id int, normalizedName string) error {
+
+	if result := b.db.Model(&entities.Book{Id: id}).Update("normalized_name", normalizedName); result.Error != nil {
+		log.Error("Error on update book normalized name: ", result.Error.Error())
+		return result.Error
+	}
 
-	if result := b.db.First(&book, id); result.Error != nil {
-		log.Error("Error on get book to delete: ", result.Error.Error())
+	return nil
+}
+
+// AddRoleToContributorsKey adds the role to the primary key of an author_book table created before an
+// author could have more than one role on a book, a change AutoMigrate does not make
+func (b *BookRepository) AddRoleToContributorsKey() error {
+	var roleInKey int64
+
+	if result := b.db.Raw("SELECT COUNT(*) FROM information_schema.key_column_usage " +
+		"WHERE table_name = 'author_book' AND constraint_name = 'author_book_pkey' AND column_name = 'role'").
+		Scan(&roleInKey); result.Error != nil {
+		log.Error("Error on get primary key of author_book: ", result.Error.Error())
 		return result.Error
 	}
+	if roleInKey > 0 {
+		return nil
+	}
+
+	return b.db.Transaction(func(tx *gorm.DB) error {
+		if result := tx.Exec("UPDATE author_book SET role = ? WHERE role IS NULL OR role = ''", dtos.RoleAuthor); result.Error != nil {
+			log.Error("Error on fill role of author_book: ", result.Error.Error())
+			return result.Error
+		}
+
+		if result := tx.Exec("ALTER TABLE author_book DROP CONSTRAINT IF EXISTS author_book_pkey, " +
+			"ADD PRIMARY KEY (book_id, author_id, role)"); result.Error != nil {
+			log.Error("Error on add role to primary key of author_book: ", result.Error.Error())
+			return result.Error
+		}
+
+		return nil
+	})
+}
+
+// PrefillBook sets the fields of the book that are not zero on fields, e.g. the language read from a file
+// of the book. Associations are left as they are
+func (b *BookRepository) PrefillBook(id int, fields entities.Book) error {
+
+	if result := b.db.Model(&entities.Book{Id: id}).Omit(clause.Associations).Updates(fields); result.Error != nil {
+		log.Error("Error on prefill book: ", result.Error.Error())
+		return result.Error
+	}
+
+	return nil
+}
+
+func (b *BookRepository) GetBooksWithoutWork(afterId, limit int) ([]entities.Book, error) {
+	var books []entities.Book
+
+	if result := b.db.Where("work_id IS NULL AND id > ?", afterId).
+		Order("id asc").Limit(limit).Preload("Contributors", orderContributors).Preload("Contributors.Author").Find(&books); result.Error != nil {
+		log.Error("Error on get books without work: ", result.Error.Error())
+		return nil, result.Error
+	}
+
+	return books, nil
+}
 
-	if result := b.db.Exec("DELETE FROM author_book WHERE author_book.book_id = $1", id); result.Error != nil {
-		log.Error("Error on delete relations from author_book: ", result.Error.Error())
+func (b *BookRepository) UpdateWork(id, workId int) error {
+
+	if result := b.db.Model(&entities.Book{Id: id}).Update("work_id", workId); result.Error != nil {
+		log.Error("Error on update book work: ", result.Error.Error())
 		return result.Error
 	}
 
-	if result := b.db.Delete(&book); result.Error != nil {
-		log.Error("Error delete book: ", result.Error.Error())
+	return nil
+}
+
+// AddBookTags creates the tags that do not exist yet and links them to the book. Tags the book already has are kept
+func (b *BookRepository) AddBookTags(id int, tags []string) error {
+	return b.db.Transaction(func(tx *gorm.DB) error {
+		newTags := make([]entities.Tag, len(tags))
+		for i, tag := range tags {
+			newTags[i] = entities.Tag{Name: tag}
+		}
+
+		if result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&newTags); result.Error != nil {
+			log.Error("Error on create tags: ", result.Error.Error())
+			return result.Error
+		}
+
+		if result := tx.Exec("INSERT INTO book_tag (book_id, tag_id) "+
+			"SELECT ?, id FROM tags WHERE name IN ? ON CONFLICT DO NOTHING", id, tags); result.Error != nil {
+			log.Error("Error on add tags to book: ", result.Error.Error())
+			return result.Error
+		}
+
+		return nil
+	})
+}
+
+// RemoveBookTag unlinks the tag from the book, returning gorm.ErrRecordNotFound when the book does not have it
+func (b *BookRepository) RemoveBookTag(id int, tag string) error {
+
+	result := b.db.Exec("DELETE FROM book_tag WHERE book_tag.book_id = ? AND book_tag.tag_id IN (SELECT id FROM tags WHERE name = ?)", id, tag)
+	if result.Error != nil {
+		log.Error("Error on remove tag from book: ", result.Error.Error())
 		return result.Error
 	}
 
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
 	return nil
 }
+
+// CountCopies returns how many copies of the book the library holds, whatever their status
+func (b *BookRepository) CountCopies(id int) (int64, error) {
+	var count int64
+
+	if result := b.db.Model(&entities.Copy{}).Where("book_id = ?", id).Count(&count); result.Error != nil {
+		log.Error("Error on count copies of book: ", result.Error.Error())
+		return 0, result.Error
+	}
+
+	return count, nil
+}
+
+// GetBooks returns the books with everything the book response shows, in no particular order
+func (b *BookRepository) GetBooks(ids []int) ([]entities.Book, error) {
+
+	books := make([]entities.Book, 0)
+	if result := b.db.Where("id IN ?", ids).
+		Preload("Contributors", orderContributors).Preload("Contributors.Author").Preload("Copies").Preload("Genres").Preload("Publisher").Preload("Series").Preload("Tags").Preload("Titles").Find(&books); result.Error != nil {
+		log.Error("Error on get books: ", result.Error.Error())
+		return nil, result.Error
+	}
+
+	return books, nil
+}
+
+// GetBookNames returns the books with only their IDs and names, for messages that just mention them
+func (b *BookRepository) GetBookNames(ids []int) ([]entities.Book, error) {
+
+	books := make([]entities.Book, 0)
+	if result := b.db.Select("id", "name").Where("id IN ?", ids).Find(&books); result.Error != nil {
+		log.Error("Error on get book names: ", result.Error.Error())
+		return nil, result.Error
+	}
+
+	return books, nil
+}
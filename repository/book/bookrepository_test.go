@@ -69,20 +69,20 @@ func (s *Suite) Test_repository_Create_Book() {
 	s.mock.ExpectBegin()
 
 	s.mock.ExpectQuery(regexp.QuoteMeta(
-		`INSERT INTO "books" ("name","edition","publication_year") VALUES ($1,$2,$3) RETURNING "id"`)).
-		WithArgs(name, edition, publicationYear).
+		`INSERT INTO "books" ("name","normalized_name","natural_key","language","edition","publication_year","isbn13","work_id","publisher_id","series_id","series_position","rating_average","rating_count") VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13) RETURNING "id"`)).
+		WithArgs(name, "", nil, "", edition, publicationYear, nil, nil, nil, nil, nil, 0.0, 0).
 		WillReturnRows(sqlmock.NewRows([]string{"id"}).
 			AddRow(bookId))
 
-	s.mock.ExpectQuery(regexp.QuoteMeta(
-		`INSERT INTO "authors" ("name","id") VALUES ($1,$2) ON CONFLICT DO NOTHING RETURNING "id"`)).
-		WithArgs(authorName, authorId).
-		WillReturnRows(sqlmock.NewRows([]string{"id"}).
-			AddRow(authorId))
+	s.mock.ExpectExec(regexp.QuoteMeta(
+		`INSERT INTO "book_titles" ("book_id","locale","title") VALUES ($1,$2,$3) `+
+			`ON CONFLICT ("book_id","locale") DO UPDATE SET "book_id"="excluded"."book_id"`)).
+		WithArgs(bookId, "pt-BR", "nome-teste").WillReturnResult(driver.ResultNoRows)
 
 	s.mock.ExpectExec(regexp.QuoteMeta(
-		`INSERT INTO "author_book" ("book_id","author_id") VALUES ($1,$2) ON CONFLICT DO NOTHING`)).
-		WithArgs(bookId, authorId).WillReturnResult(driver.ResultNoRows)
+		`INSERT INTO "author_book" ("book_id","author_id","role","position") VALUES ($1,$2,$3,$4) `+
+			`ON CONFLICT ("book_id","author_id","role") DO UPDATE SET "book_id"="excluded"."book_id"`)).
+		WithArgs(bookId, authorId, "translator", 0).WillReturnResult(driver.ResultNoRows)
 
 	s.mock.ExpectCommit()
 
@@ -93,10 +93,10 @@ func (s *Suite) Test_repository_Create_Book() {
 			AddRow(bookId, name, edition, publicationYear))
 
 	s.mock.ExpectQuery(regexp.QuoteMeta(
-		`SELECT * FROM "author_book" WHERE "author_book"."book_id" = $1`)).
+		`SELECT * FROM "author_book" WHERE "author_book"."book_id" = $1 ORDER BY position asc, author_id asc`)).
 		WithArgs(bookId).
-		WillReturnRows(sqlmock.NewRows([]string{"book_id", "author_id"}).
-			AddRow(bookId, authorId))
+		WillReturnRows(sqlmock.NewRows([]string{"book_id", "author_id", "role", "position"}).
+			AddRow(bookId, authorId, "translator", 0))
 
 	s.mock.ExpectQuery(regexp.QuoteMeta(
 		`SELECT * FROM "authors" WHERE "authors"."id" = $1`)).
@@ -104,18 +104,39 @@ func (s *Suite) Test_repository_Create_Book() {
 		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).
 			AddRow(authorId, authorName))
 
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "copies" WHERE "copies"."book_id" = $1`)).
+		WithArgs(bookId).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "book_id", "status"}))
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "book_genre" WHERE "book_genre"."book_id" = $1`)).
+		WithArgs(bookId).
+		WillReturnRows(sqlmock.NewRows([]string{"book_id", "genre_id"}))
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "book_tag" WHERE "book_tag"."book_id" = $1`)).
+		WithArgs(bookId).
+		WillReturnRows(sqlmock.NewRows([]string{"book_id", "tag_id"}))
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "book_titles" WHERE "book_titles"."book_id" = $1`)).
+		WithArgs(bookId).
+		WillReturnRows(sqlmock.NewRows([]string{"book_id", "locale", "title"}))
+
 	book, err := s.repository.CreateBook(entities.Book{
 		Name:            name,
 		Edition:         edition,
 		PublicationYear: publicationYear,
-		Authors:         []entities.Author{{Id: authorId, Name: authorName}}})
+		Titles:          []entities.BookTitle{{Locale: "pt-BR", Title: "nome-teste"}},
+		Contributors:    []entities.AuthorBook{{AuthorId: authorId, Author: entities.Author{Id: authorId, Name: authorName}, Role: "translator"}}})
 
 	require.NoError(s.T(), err)
 	require.Equal(s.T(), bookId, book.Id)
 	require.Equal(s.T(), name, book.Name)
 	require.Equal(s.T(), edition, book.Edition)
 	require.Equal(s.T(), publicationYear, book.PublicationYear)
-	require.Equal(s.T(), []entities.Author{{Id: authorId, Name: authorName}}, book.Authors)
+	require.Equal(s.T(), []entities.AuthorBook{{BookId: bookId, AuthorId: authorId, Author: entities.Author{Id: authorId, Name: authorName}, Role: "translator"}}, book.Contributors)
 }
 
 func (s *Suite) Test_repository_Update_Book() {
@@ -129,30 +150,30 @@ func (s *Suite) Test_repository_Update_Book() {
 		authorName = "brad"
 	)
 
-	//s.mock.MatchExpectationsInOrder(false)
 	s.mock.ExpectBegin()
 
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT "id","work_id" FROM "books" WHERE "books"."id" = $1 ORDER BY "books"."id" LIMIT 1`)).
+		WithArgs(bookId).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "work_id"}).AddRow(bookId, nil))
+
 	s.mock.ExpectExec(regexp.QuoteMeta(
-		`DELETE FROM "author_book" WHERE "author_book"."book_id" = $1`)).
+		`DELETE FROM "author_book" WHERE book_id = $1`)).
 		WithArgs(bookId).WillReturnResult(sqlmock.NewResult(int64(bookId), 1))
-
-	s.mock.ExpectCommit()
-
-	s.mock.ExpectBegin()
-
 	s.mock.ExpectExec(regexp.QuoteMeta(
-		`UPDATE "books" SET "name"=$1,"edition"=$2,"publication_year"=$3 WHERE "id" = $4`)).
-		WithArgs(name, edition, publicationYear, bookId).WillReturnResult(sqlmock.NewResult(int64(bookId), 1))
-
-	s.mock.ExpectQuery(regexp.QuoteMeta(
-		`INSERT INTO "authors" ("name","id") VALUES ($1,$2) ON CONFLICT DO NOTHING RETURNING "id"`)).
-		WithArgs(authorName, authorId).
-		WillReturnRows(sqlmock.NewRows([]string{"id"}).
-			AddRow(authorId))
+		`DELETE FROM "book_titles" WHERE book_id = $1`)).
+		WithArgs(bookId).WillReturnResult(sqlmock.NewResult(int64(bookId), 1))
+	s.mock.ExpectExec(regexp.QuoteMeta(
+		`DELETE FROM "book_genre" WHERE "book_genre"."book_id" = $1`)).
+		WithArgs(bookId).WillReturnResult(sqlmock.NewResult(int64(bookId), 1))
+	s.mock.ExpectExec(regexp.QuoteMeta(
+		`UPDATE "books" SET "name"=$1,"normalized_name"=$2,"natural_key"=$3,"language"=$4,"edition"=$5,"publication_year"=$6,"isbn13"=$7,"work_id"=$8,"publisher_id"=$9,"series_id"=$10,"series_position"=$11 WHERE "id" = $12`)).
+		WithArgs(name, "", nil, "", edition, publicationYear, nil, nil, nil, nil, nil, bookId).WillReturnResult(sqlmock.NewResult(int64(bookId), 1))
 
 	s.mock.ExpectExec(regexp.QuoteMeta(
-		`INSERT INTO "author_book" ("book_id","author_id") VALUES ($1,$2) ON CONFLICT DO NOTHING`)).
-		WithArgs(bookId, authorId).WillReturnResult(sqlmock.NewResult(int64(bookId), int64(authorId)))
+		`INSERT INTO "author_book" ("book_id","author_id","role","position") VALUES ($1,$2,$3,$4) `+
+			`ON CONFLICT ("book_id","author_id","role") DO UPDATE SET "book_id"="excluded"."book_id"`)).
+		WithArgs(bookId, authorId, "author", 0).WillReturnResult(sqlmock.NewResult(int64(bookId), int64(authorId)))
 
 	s.mock.ExpectCommit()
 
@@ -160,14 +181,14 @@ func (s *Suite) Test_repository_Update_Book() {
 		Id:              bookId,
 		Name:            name,
 		Edition:         edition,
-		PublicationYear: publicationYear}, []entities.Author{{Id: authorId, Name: authorName}})
+		PublicationYear: publicationYear}, []entities.AuthorBook{{AuthorId: authorId, Author: entities.Author{Id: authorId, Name: authorName}, Role: "author"}}, nil)
 
 	require.NoError(s.T(), err)
 	require.Equal(s.T(), bookId, book.Id)
 	require.Equal(s.T(), name, book.Name)
 	require.Equal(s.T(), edition, book.Edition)
 	require.Equal(s.T(), publicationYear, book.PublicationYear)
-	require.Equal(s.T(), []entities.Author{{Id: authorId, Name: authorName}}, book.Authors)
+	require.Equal(s.T(), []entities.AuthorBook{{BookId: bookId, AuthorId: authorId, Author: entities.Author{Id: authorId, Name: authorName}, Role: "author"}}, book.Contributors)
 }
 
 func (s *Suite) Test_repository_Update_Book_Error_On_Clear() {
@@ -181,11 +202,15 @@ func (s *Suite) Test_repository_Update_Book_Error_On_Clear() {
 		authorName = "brad"
 	)
 
-	//s.mock.MatchExpectationsInOrder(false)
 	s.mock.ExpectBegin()
 
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT "id","work_id" FROM "books" WHERE "books"."id" = $1 ORDER BY "books"."id" LIMIT 1`)).
+		WithArgs(bookId).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "work_id"}).AddRow(bookId, nil))
+
 	s.mock.ExpectExec(regexp.QuoteMeta(
-		`DELETE FROM "author_book" WHERE "author_book"."book_id" = $1`)).
+		`DELETE FROM "author_book" WHERE book_id = $1`)).
 		WithArgs(bookId).WillReturnError(context.Canceled)
 
 	s.mock.ExpectRollback()
@@ -194,7 +219,7 @@ func (s *Suite) Test_repository_Update_Book_Error_On_Clear() {
 		Id:              bookId,
 		Name:            name,
 		Edition:         edition,
-		PublicationYear: publicationYear}, []entities.Author{{Id: authorId, Name: authorName}})
+		PublicationYear: publicationYear}, []entities.AuthorBook{{AuthorId: authorId, Author: entities.Author{Id: authorId, Name: authorName}, Role: "author"}}, nil)
 
 	require.Error(s.T(), err)
 }
@@ -210,20 +235,25 @@ func (s *Suite) Test_repository_Update_Book_Error_On_Save() {
 		authorName = "brad"
 	)
 
-	//s.mock.MatchExpectationsInOrder(false)
 	s.mock.ExpectBegin()
 
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT "id","work_id" FROM "books" WHERE "books"."id" = $1 ORDER BY "books"."id" LIMIT 1`)).
+		WithArgs(bookId).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "work_id"}).AddRow(bookId, nil))
+
 	s.mock.ExpectExec(regexp.QuoteMeta(
-		`DELETE FROM "author_book" WHERE "author_book"."book_id" = $1`)).
+		`DELETE FROM "author_book" WHERE book_id = $1`)).
+		WithArgs(bookId).WillReturnResult(sqlmock.NewResult(int64(bookId), 1))
+	s.mock.ExpectExec(regexp.QuoteMeta(
+		`DELETE FROM "book_titles" WHERE book_id = $1`)).
+		WithArgs(bookId).WillReturnResult(sqlmock.NewResult(int64(bookId), 1))
+	s.mock.ExpectExec(regexp.QuoteMeta(
+		`DELETE FROM "book_genre" WHERE "book_genre"."book_id" = $1`)).
 		WithArgs(bookId).WillReturnResult(sqlmock.NewResult(int64(bookId), 1))
-
-	s.mock.ExpectCommit()
-
-	s.mock.ExpectBegin()
-
 	s.mock.ExpectExec(regexp.QuoteMeta(
-		`UPDATE "books" SET "name"=$1,"edition"=$2,"publication_year"=$3 WHERE "id" = $4`)).
-		WithArgs(name, edition, publicationYear, bookId).WillReturnError(context.Canceled)
+		`UPDATE "books" SET "name"=$1,"normalized_name"=$2,"natural_key"=$3,"language"=$4,"edition"=$5,"publication_year"=$6,"isbn13"=$7,"work_id"=$8,"publisher_id"=$9,"series_id"=$10,"series_position"=$11 WHERE "id" = $12`)).
+		WithArgs(name, "", nil, "", edition, publicationYear, nil, nil, nil, nil, nil, bookId).WillReturnError(context.Canceled)
 
 	s.mock.ExpectRollback()
 
@@ -231,11 +261,92 @@ func (s *Suite) Test_repository_Update_Book_Error_On_Save() {
 		Id:              bookId,
 		Name:            name,
 		Edition:         edition,
-		PublicationYear: publicationYear}, []entities.Author{{Id: authorId, Name: authorName}})
+		PublicationYear: publicationYear}, []entities.AuthorBook{{AuthorId: authorId, Author: entities.Author{Id: authorId, Name: authorName}, Role: "author"}}, nil)
 
 	require.Error(s.T(), err)
 }
 
+func (s *Suite) Test_repository_Create_Book_With_New_Work_Error() {
+	var (
+		workId   = 7
+		authorId = 2
+	)
+
+	s.mock.ExpectBegin()
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`INSERT INTO "works" ("name","normalized_name") VALUES ($1,$2) RETURNING "id"`)).
+		WithArgs("The Hobbit", "the hobbit").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(workId))
+
+	s.mock.ExpectExec(regexp.QuoteMeta(
+		`INSERT INTO "author_work" ("work_id","author_id") VALUES ($1,$2) ON CONFLICT DO NOTHING`)).
+		WithArgs(workId, authorId).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`INSERT INTO "books" ("name","normalized_name","natural_key","language","edition","publication_year","isbn13","work_id","publisher_id","series_id","series_position","rating_average","rating_count") VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13) RETURNING "id"`)).
+		WithArgs("The Hobbit", "the hobbit", nil, "", "", 0, nil, workId, nil, nil, nil, 0.0, 0).
+		WillReturnError(context.Canceled)
+
+	// The work goes with the book that failed
+	s.mock.ExpectRollback()
+
+	_, err := s.repository.CreateBook(entities.Book{
+		Name:           "The Hobbit",
+		NormalizedName: "the hobbit",
+		Work:           &entities.Work{Name: "The Hobbit", NormalizedName: "the hobbit", Authors: []entities.Author{{Id: authorId}}},
+	})
+
+	require.ErrorIs(s.T(), err, context.Canceled)
+}
+
+func (s *Suite) Test_repository_Update_Book_Leaving_Work() {
+	var (
+		bookId     = 1
+		previousId = 3
+		workId     = 4
+	)
+
+	s.mock.ExpectBegin()
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT "id","work_id" FROM "books" WHERE "books"."id" = $1 ORDER BY "books"."id" LIMIT 1`)).
+		WithArgs(bookId).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "work_id"}).AddRow(bookId, previousId))
+
+	s.mock.ExpectExec(regexp.QuoteMeta(
+		`DELETE FROM "author_book" WHERE book_id = $1`)).
+		WithArgs(bookId).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	s.mock.ExpectExec(regexp.QuoteMeta(
+		`DELETE FROM "book_titles" WHERE book_id = $1`)).
+		WithArgs(bookId).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	s.mock.ExpectExec(regexp.QuoteMeta(
+		`DELETE FROM "book_genre" WHERE "book_genre"."book_id" = $1`)).
+		WithArgs(bookId).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	s.mock.ExpectExec(regexp.QuoteMeta(
+		`UPDATE "books" SET "name"=$1,"normalized_name"=$2,"natural_key"=$3,"language"=$4,"edition"=$5,"publication_year"=$6,"isbn13"=$7,"work_id"=$8,"publisher_id"=$9,"series_id"=$10,"series_position"=$11 WHERE "id" = $12`)).
+		WithArgs("The Silmarillion", "the silmarillion", nil, "", "", 0, nil, workId, nil, nil, nil, bookId).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	s.mock.ExpectExec(regexp.QuoteMeta(
+		`DELETE FROM author_work WHERE work_id = $1 AND NOT EXISTS (SELECT 1 FROM books WHERE work_id = $2)`)).
+		WithArgs(previousId, previousId).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	s.mock.ExpectExec(regexp.QuoteMeta(
+		`DELETE FROM works WHERE id = $1 AND NOT EXISTS (SELECT 1 FROM books WHERE work_id = $2)`)).
+		WithArgs(previousId, previousId).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	s.mock.ExpectCommit()
+
+	_, err := s.repository.UpdateBook(entities.Book{Id: bookId, Name: "The Silmarillion", NormalizedName: "the silmarillion", WorkId: &workId}, nil, nil)
+
+	require.NoError(s.T(), err)
+}
+
 func (s *Suite) Test_repository_Create_Book_Error() {
 	var (
 		name            = "test-name"
@@ -246,8 +357,8 @@ func (s *Suite) Test_repository_Create_Book_Error() {
 	s.mock.ExpectBegin()
 
 	s.mock.ExpectQuery(regexp.QuoteMeta(
-		`INSERT INTO "books" ("name","edition","publication_year") VALUES ($1,$2,$3) RETURNING "id"`)).
-		WithArgs(name, edition, publicationYear).
+		`INSERT INTO "books" ("name","normalized_name","natural_key","language","edition","publication_year","isbn13","work_id","publisher_id","series_id","series_position","rating_average","rating_count") VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13) RETURNING "id"`)).
+		WithArgs(name, "", nil, "", edition, publicationYear, nil, nil, nil, nil, nil, 0.0, 0).
 		WillReturnError(context.Canceled)
 
 	s.mock.ExpectRollback()
@@ -266,6 +377,8 @@ func (s *Suite) Test_repository_Get_Book() {
 		name       = "test-name"
 		authorId   = 2
 		authorName = "author-name"
+		genreId    = 3
+		genreName  = "Fantasy"
 	)
 
 	s.mock.ExpectQuery(regexp.QuoteMeta(
@@ -286,14 +399,56 @@ func (s *Suite) Test_repository_Get_Book() {
 		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).
 			AddRow(authorId, authorName))
 
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "copies" WHERE "copies"."book_id" = $1`)).
+		WithArgs(id).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "book_id", "status"}))
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "book_genre" WHERE "book_genre"."book_id" = $1`)).
+		WithArgs(id).
+		WillReturnRows(sqlmock.NewRows([]string{"book_id", "genre_id"}).
+			AddRow(id, genreId))
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "genres" WHERE "genres"."id" = $1`)).
+		WithArgs(genreId).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).
+			AddRow(genreId, genreName))
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "book_tag" WHERE "book_tag"."book_id" = $1`)).
+		WithArgs(id).
+		WillReturnRows(sqlmock.NewRows([]string{"book_id", "tag_id"}).
+			AddRow(id, 8))
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "tags" WHERE "tags"."id" = $1`)).
+		WithArgs(8).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).
+			AddRow(8, "middle-earth"))
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "book_titles" WHERE "book_titles"."book_id" = $1`)).
+		WithArgs(id).
+		WillReturnRows(sqlmock.NewRows([]string{"book_id", "locale", "title"}).
+			AddRow(id, "pt-BR", "nome-teste"))
+
 	res, err := s.repository.GetBook(id)
 
 	require.NoError(s.T(), err)
 	require.Nil(s.T(), deep.Equal(entities.Book{
 		Id:   id,
 		Name: name,
-		Authors: []entities.Author{
-			{Id: authorId, Name: authorName}}}, res))
+		Contributors: []entities.AuthorBook{
+			{BookId: id, AuthorId: authorId, Author: entities.Author{Id: authorId, Name: authorName}}},
+		Genres: []entities.Genre{
+			{Id: genreId, Name: genreName}},
+		Tags: []entities.Tag{
+			{Id: 8, Name: "middle-earth"}},
+		Titles: []entities.BookTitle{
+			{BookId: id, Locale: "pt-BR", Title: "nome-teste"}},
+		Copies: []entities.Copy{}}, res))
 }
 
 func (s *Suite) Test_repository_Get_Book_Error() {
@@ -322,15 +477,13 @@ func (s *Suite) Test_repository_Get_All_Books() {
 	)
 
 	s.mock.ExpectQuery(regexp.QuoteMeta(
-		`SELECT "books"."id","books"."name","books"."edition","books"."publication_year" 
-		FROM "books" 
-		JOIN author_book ON author_book.book_id = books.id 
-		JOIN authors ON authors.id = author_book.author_id 
-		WHERE LOWER(authors.name) LIKE $1 
-		AND LOWER(books.name) LIKE $2
-		AND LOWER(books.edition) LIKE $3 
-		AND books.publication_year = $4`)).
-		WithArgs("%"+strings.ToLower(authorName)+"%", "%"+strings.ToLower(name)+"%", "%"+strings.ToLower(edition)+"%", publicationYear).
+		`SELECT * FROM "books" 
+		WHERE (books.id IN (SELECT author_book.book_id FROM author_book JOIN authors ON authors.id = author_book.author_id WHERE LOWER(authors.name) LIKE $1 OR authors.id IN (SELECT author_id FROM author_aliases WHERE LOWER(name) LIKE $2)))
+		AND (LOWER(books.name) LIKE $3 OR books.id IN (SELECT book_id FROM book_titles WHERE LOWER(title) LIKE $4))
+		AND books.language = $5
+		AND LOWER(books.edition) LIKE $6 
+		AND books.publication_year = $7`)).
+		WithArgs("%"+strings.ToLower(authorName)+"%", "%"+strings.ToLower(authorName)+"%", "%"+strings.ToLower(name)+"%", "%"+strings.ToLower(name)+"%", "en", "%"+strings.ToLower(edition)+"%", publicationYear).
 		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).
 			AddRow(id, name))
 
@@ -346,133 +499,1119 @@ func (s *Suite) Test_repository_Get_All_Books() {
 		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).
 			AddRow(authorId, authorName))
 
-	res, err := s.repository.GetAllBooks(dtos.GetBooksFilter{Name: name, Edition: edition, PublicationYear: publicationYear, Author: authorName})
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "copies" WHERE "copies"."book_id" = $1`)).
+		WithArgs(id).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "book_id", "status"}))
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "book_genre" WHERE "book_genre"."book_id" = $1`)).
+		WithArgs(id).
+		WillReturnRows(sqlmock.NewRows([]string{"book_id", "genre_id"}))
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "book_tag" WHERE "book_tag"."book_id" = $1`)).
+		WithArgs(id).
+		WillReturnRows(sqlmock.NewRows([]string{"book_id", "tag_id"}))
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "book_titles" WHERE "book_titles"."book_id" = $1`)).
+		WithArgs(id).
+		WillReturnRows(sqlmock.NewRows([]string{"book_id", "locale", "title"}))
+
+	res, err := s.repository.GetAllBooks(dtos.GetBooksFilter{Name: name, Language: "en", Edition: edition, PublicationYear: publicationYear, Author: authorName})
 
 	require.NoError(s.T(), err)
 	require.Nil(s.T(), deep.Equal([]entities.Book{{
 		Id:   id,
 		Name: name,
-		Authors: []entities.Author{
-			{Id: authorId, Name: authorName}}}}, res))
+		Contributors: []entities.AuthorBook{
+			{BookId: id, AuthorId: authorId, Author: entities.Author{Id: authorId, Name: authorName}}},
+		Genres: []entities.Genre{},
+		Tags:   []entities.Tag{},
+		Titles: []entities.BookTitle{},
+		Copies: []entities.Copy{}}}, res))
 }
 
-func (s *Suite) Test_repository_Get_All_Books_Error() {
+func (s *Suite) Test_repository_Get_Book_By_Isbn() {
 	var (
-		name            = "test-name"
-		edition         = "firtEsdiction"
-		publicationYear = 2022
-		authorName      = "author-name"
+		id   = 1
+		name = "The Hobbit"
+		isbn = "9780261103207"
 	)
 
 	s.mock.ExpectQuery(regexp.QuoteMeta(
-		`SELECT "books"."id","books"."name","books"."edition","books"."publication_year" 
-		FROM "books" 
-		JOIN author_book ON author_book.book_id = books.id 
-		JOIN authors ON authors.id = author_book.author_id 
-		WHERE LOWER(authors.name) LIKE $1 
-		AND LOWER(books.name) LIKE $2
-		AND LOWER(books.edition) LIKE $3 
-		AND books.publication_year = $4`)).
-		WithArgs("%"+strings.ToLower(authorName)+"%", "%"+strings.ToLower(name)+"%", "%"+strings.ToLower(edition)+"%", publicationYear).
-		WillReturnError(context.Canceled)
+		`SELECT * FROM "books" WHERE isbn13 = $1 ORDER BY "books"."id" LIMIT 1`)).
+		WithArgs(isbn).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "isbn13"}).
+			AddRow(id, name, isbn))
 
-	_, err := s.repository.GetAllBooks(dtos.GetBooksFilter{Name: name, Edition: edition, PublicationYear: publicationYear, Author: authorName})
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "author_book" WHERE "author_book"."book_id" = $1`)).
+		WithArgs(id).
+		WillReturnRows(sqlmock.NewRows([]string{"book_id", "author_id"}))
 
-	require.Error(s.T(), err)
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "copies" WHERE "copies"."book_id" = $1`)).
+		WithArgs(id).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "book_id", "status"}))
 
-}
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "book_genre" WHERE "book_genre"."book_id" = $1`)).
+		WithArgs(id).
+		WillReturnRows(sqlmock.NewRows([]string{"book_id", "genre_id"}))
 
-func (s *Suite) Test_repository_Delete_Book() {
-	var (
-		bookId   = 1
-		bookName = "book"
-	)
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "book_tag" WHERE "book_tag"."book_id" = $1`)).
+		WithArgs(id).
+		WillReturnRows(sqlmock.NewRows([]string{"book_id", "tag_id"}))
 
 	s.mock.ExpectQuery(regexp.QuoteMeta(
-		`SELECT * FROM "books" WHERE "books"."id" = $1 ORDER BY "books"."id" LIMIT 1`)).
-		WithArgs(bookId).
-		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).
-			AddRow(bookId, bookName))
+		`SELECT * FROM "book_titles" WHERE "book_titles"."book_id" = $1`)).
+		WithArgs(id).
+		WillReturnRows(sqlmock.NewRows([]string{"book_id", "locale", "title"}))
 
-	s.mock.ExpectExec(regexp.QuoteMeta(
-		`DELETE FROM author_book WHERE author_book.book_id = $1`)).
-		WithArgs(bookId).WillReturnResult(sqlmock.NewResult(int64(bookId), 1))
+	book, err := s.repository.GetBookByIsbn(isbn)
 
-	s.mock.ExpectBegin()
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), id, book.Id)
+	require.Equal(s.T(), isbn, *book.Isbn13)
+}
 
-	s.mock.ExpectExec(regexp.QuoteMeta(
-		`DELETE FROM "books" WHERE "books"."id" = $1`)).
-		WithArgs(bookId).WillReturnResult(sqlmock.NewResult(int64(bookId), 1))
+func (s *Suite) Test_repository_Get_Book_By_Isbn_Not_Found() {
 
-	s.mock.ExpectCommit()
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "books" WHERE isbn13 = $1`)).
+		WithArgs("9780261103207").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
 
-	err := s.repository.DeleteBook(bookId)
+	_, err := s.repository.GetBookByIsbn("9780261103207")
+
+	require.ErrorIs(s.T(), err, gorm.ErrRecordNotFound)
+}
+
+func (s *Suite) Test_repository_Get_All_Books_Filter_Rating() {
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "books" WHERE books.rating_count > 0 AND books.rating_average >= $1 ` +
+			`ORDER BY books.rating_average desc, books.rating_count desc, books.name asc LIMIT 10`)).
+		WithArgs(4.5).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "rating_average", "rating_count"}))
+
+	books, err := s.repository.GetAllBooks(dtos.GetBooksFilter{MinRating: 4.5, Sort: dtos.BookSortRating, Pagination: dtos.Pagination{Page: 1, Limit: 10}})
 
 	require.NoError(s.T(), err)
+	require.Empty(s.T(), books)
 }
 
-func (s *Suite) Test_repository_Delete_Book_Error_On_Select() {
+func (s *Suite) Test_repository_Get_All_Books_Filter_Isbn() {
 	var (
-		bookId = 1
+		id   = 1
+		name = "The Hobbit"
+		isbn = "9780261103207"
 	)
 
 	s.mock.ExpectQuery(regexp.QuoteMeta(
-		`SELECT * FROM "books" WHERE "books"."id" = $1 ORDER BY "books"."id" LIMIT 1`)).
-		WithArgs(bookId).
-		WillReturnError(context.Canceled)
+		`SELECT * FROM "books" WHERE books.isbn13 = $1 ORDER BY name asc LIMIT 10`)).
+		WithArgs(isbn).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "isbn13"}).
+			AddRow(id, name, isbn))
 
-	err := s.repository.DeleteBook(bookId)
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "author_book" WHERE "author_book"."book_id" = $1`)).
+		WithArgs(id).
+		WillReturnRows(sqlmock.NewRows([]string{"book_id", "author_id"}))
 
-	require.Error(s.T(), err)
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "copies" WHERE "copies"."book_id" = $1`)).
+		WithArgs(id).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "book_id", "status"}))
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "book_genre" WHERE "book_genre"."book_id" = $1`)).
+		WithArgs(id).
+		WillReturnRows(sqlmock.NewRows([]string{"book_id", "genre_id"}))
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "book_tag" WHERE "book_tag"."book_id" = $1`)).
+		WithArgs(id).
+		WillReturnRows(sqlmock.NewRows([]string{"book_id", "tag_id"}))
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "book_titles" WHERE "book_titles"."book_id" = $1`)).
+		WithArgs(id).
+		WillReturnRows(sqlmock.NewRows([]string{"book_id", "locale", "title"}))
+
+	books, err := s.repository.GetAllBooks(dtos.GetBooksFilter{Isbn: isbn, Pagination: dtos.Pagination{Page: 1, Limit: 10}})
+
+	require.NoError(s.T(), err)
+	require.Len(s.T(), books, 1)
+	require.Equal(s.T(), isbn, *books[0].Isbn13)
 }
 
-func (s *Suite) Test_repository_Delete_Book_Error_On_Delete_Foreign_key() {
+func (s *Suite) Test_repository_Get_All_Books_Filter_Available() {
 	var (
-		bookId   = 1
-		bookName = "book"
+		id   = 1
+		name = "The Hobbit"
 	)
 
 	s.mock.ExpectQuery(regexp.QuoteMeta(
-		`SELECT * FROM "books" WHERE "books"."id" = $1 ORDER BY "books"."id" LIMIT 1`)).
-		WithArgs(bookId).
+		`SELECT * FROM "books" WHERE books.id IN (SELECT book_id FROM copies WHERE status = $1) ORDER BY name asc LIMIT 10`)).
+		WithArgs(dtos.CopyStatusAvailable).
 		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).
-			AddRow(bookId, bookName))
+			AddRow(id, name))
 
-	s.mock.ExpectExec(regexp.QuoteMeta(
-		`DELETE FROM author_book WHERE author_book.book_id = $1`)).
-		WithArgs(bookId).
-		WillReturnError(context.Canceled)
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "author_book" WHERE "author_book"."book_id" = $1`)).
+		WithArgs(id).
+		WillReturnRows(sqlmock.NewRows([]string{"book_id", "author_id"}))
 
-	err := s.repository.DeleteBook(bookId)
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "copies" WHERE "copies"."book_id" = $1`)).
+		WithArgs(id).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "book_id", "barcode", "status"}).
+			AddRow(5, id, "0001", dtos.CopyStatusAvailable).
+			AddRow(6, id, "0002", dtos.CopyStatusLost))
 
-	require.Error(s.T(), err)
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "book_genre" WHERE "book_genre"."book_id" = $1`)).
+		WithArgs(id).
+		WillReturnRows(sqlmock.NewRows([]string{"book_id", "genre_id"}))
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "book_tag" WHERE "book_tag"."book_id" = $1`)).
+		WithArgs(id).
+		WillReturnRows(sqlmock.NewRows([]string{"book_id", "tag_id"}))
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "book_titles" WHERE "book_titles"."book_id" = $1`)).
+		WithArgs(id).
+		WillReturnRows(sqlmock.NewRows([]string{"book_id", "locale", "title"}))
+
+	books, err := s.repository.GetAllBooks(dtos.GetBooksFilter{Available: true, Pagination: dtos.Pagination{Page: 1, Limit: 10}})
+
+	require.NoError(s.T(), err)
+	require.Len(s.T(), books, 1)
+	require.Nil(s.T(), deep.Equal([]entities.Copy{
+		{Id: 5, BookId: id, Barcode: "0001", Status: dtos.CopyStatusAvailable},
+		{Id: 6, BookId: id, Barcode: "0002", Status: dtos.CopyStatusLost}}, books[0].Copies))
 }
 
-func (s *Suite) Test_repository_Delete_Book_Error_On_Delete_Book() {
+func (s *Suite) Test_repository_Get_All_Books_Filter_Publisher() {
 	var (
-		bookId   = 1
-		bookName = "book"
+		id          = 1
+		name        = "The Hobbit"
+		publisherId = 7
 	)
 
 	s.mock.ExpectQuery(regexp.QuoteMeta(
-		`SELECT * FROM "books" WHERE "books"."id" = $1 ORDER BY "books"."id" LIMIT 1`)).
-		WithArgs(bookId).
+		`SELECT * FROM "books" WHERE books.publisher_id IN (SELECT id FROM publishers WHERE LOWER(name) LIKE $1) AND books.publisher_id = $2 ORDER BY name asc LIMIT 10`)).
+		WithArgs("%allen%", publisherId).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "publisher_id"}).
+			AddRow(id, name, publisherId))
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "author_book" WHERE "author_book"."book_id" = $1`)).
+		WithArgs(id).
+		WillReturnRows(sqlmock.NewRows([]string{"book_id", "author_id"}))
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "copies" WHERE "copies"."book_id" = $1`)).
+		WithArgs(id).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "book_id", "status"}))
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "book_genre" WHERE "book_genre"."book_id" = $1`)).
+		WithArgs(id).
+		WillReturnRows(sqlmock.NewRows([]string{"book_id", "genre_id"}))
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "publishers" WHERE "publishers"."id" = $1`)).
+		WithArgs(publisherId).
 		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).
-			AddRow(bookId, bookName))
+			AddRow(publisherId, "Allen & Unwin"))
 
-	s.mock.ExpectExec(regexp.QuoteMeta(
-		`DELETE FROM author_book WHERE author_book.book_id = $1`)).
-		WithArgs(bookId).WillReturnResult(sqlmock.NewResult(int64(bookId), 1))
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "book_tag" WHERE "book_tag"."book_id" = $1`)).
+		WithArgs(id).
+		WillReturnRows(sqlmock.NewRows([]string{"book_id", "tag_id"}))
 
-	s.mock.ExpectBegin()
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "book_titles" WHERE "book_titles"."book_id" = $1`)).
+		WithArgs(id).
+		WillReturnRows(sqlmock.NewRows([]string{"book_id", "locale", "title"}))
 
-	s.mock.ExpectExec(regexp.QuoteMeta(
-		`DELETE FROM "books" WHERE "books"."id" = $1`)).
-		WithArgs(bookId).WillReturnError(context.Canceled)
+	books, err := s.repository.GetAllBooks(dtos.GetBooksFilter{Publisher: "Allen", PublisherId: publisherId, Pagination: dtos.Pagination{Page: 1, Limit: 10}})
 
-	s.mock.ExpectRollback()
+	require.NoError(s.T(), err)
+	require.Len(s.T(), books, 1)
+	require.Equal(s.T(), "Allen & Unwin", books[0].Publisher.Name)
+}
 
-	err := s.repository.DeleteBook(bookId)
+func (s *Suite) Test_repository_Get_All_Books_Filter_Genre() {
+	var (
+		id   = 1
+		name = "The Hobbit"
+	)
 
-	require.Error(s.T(), err)
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "books" WHERE books.id IN (SELECT book_id FROM book_genre WHERE genre_id IN (` +
+			`WITH RECURSIVE subgenres AS (SELECT id FROM genres WHERE normalized_name = $1 ` +
+			`UNION SELECT genres.id FROM genres JOIN subgenres ON genres.parent_id = subgenres.id) ` +
+			`SELECT id FROM subgenres)) ORDER BY name asc LIMIT 10`)).
+		WithArgs("fantasy").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).
+			AddRow(id, name))
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "author_book" WHERE "author_book"."book_id" = $1`)).
+		WithArgs(id).
+		WillReturnRows(sqlmock.NewRows([]string{"book_id", "author_id"}))
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "copies" WHERE "copies"."book_id" = $1`)).
+		WithArgs(id).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "book_id", "status"}))
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "book_genre" WHERE "book_genre"."book_id" = $1`)).
+		WithArgs(id).
+		WillReturnRows(sqlmock.NewRows([]string{"book_id", "genre_id"}).
+			AddRow(id, 5))
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "genres" WHERE "genres"."id" = $1`)).
+		WithArgs(5).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "parent_id"}).
+			AddRow(5, "High Fantasy", 4))
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "book_tag" WHERE "book_tag"."book_id" = $1`)).
+		WithArgs(id).
+		WillReturnRows(sqlmock.NewRows([]string{"book_id", "tag_id"}))
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "book_titles" WHERE "book_titles"."book_id" = $1`)).
+		WithArgs(id).
+		WillReturnRows(sqlmock.NewRows([]string{"book_id", "locale", "title"}))
+
+	books, err := s.repository.GetAllBooks(dtos.GetBooksFilter{Genre: "fantasy", Pagination: dtos.Pagination{Page: 1, Limit: 10}})
+
+	require.NoError(s.T(), err)
+	require.Len(s.T(), books, 1)
+	require.Equal(s.T(), "High Fantasy", books[0].Genres[0].Name)
+}
+
+func (s *Suite) Test_repository_Get_All_Books_Filter_Tags() {
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "books" WHERE books.id IN (SELECT book_tag.book_id FROM book_tag JOIN tags ON tags.id = book_tag.tag_id `+
+			`WHERE tags.name IN ($1,$2)) ORDER BY name asc LIMIT 10`)).
+		WithArgs("dystopia", "classic").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}))
+
+	_, err := s.repository.GetAllBooks(dtos.GetBooksFilter{Tags: "dystopia,classic", TagsMatch: dtos.TagsMatchAny, Pagination: dtos.Pagination{Page: 1, Limit: 10}})
+	require.NoError(s.T(), err)
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "books" WHERE books.id IN (SELECT book_tag.book_id FROM book_tag JOIN tags ON tags.id = book_tag.tag_id `+
+			`WHERE tags.name IN ($1,$2) GROUP BY book_tag.book_id HAVING COUNT(*) = $3) ORDER BY name asc LIMIT 10`)).
+		WithArgs("dystopia", "classic", 2).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}))
+
+	_, err = s.repository.GetAllBooks(dtos.GetBooksFilter{Tags: "dystopia,classic", TagsMatch: dtos.TagsMatchAll, Pagination: dtos.Pagination{Page: 1, Limit: 10}})
+	require.NoError(s.T(), err)
+}
+
+func (s *Suite) Test_repository_Get_All_Books_Filter_Series() {
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "books" WHERE books.series_id IN (SELECT id FROM series WHERE LOWER(name) LIKE $1) AND books.series_id = $2 ORDER BY name asc LIMIT 10`)).
+		WithArgs("%rings%", 4).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}))
+
+	books, err := s.repository.GetAllBooks(dtos.GetBooksFilter{Series: "Rings", SeriesId: 4, Pagination: dtos.Pagination{Page: 1, Limit: 10}})
+
+	require.NoError(s.T(), err)
+	require.Empty(s.T(), books)
+}
+
+func (s *Suite) Test_repository_Get_All_Books_Error() {
+	var (
+		name            = "test-name"
+		edition         = "firtEsdiction"
+		publicationYear = 2022
+		authorName      = "author-name"
+	)
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "books" 
+		WHERE (books.id IN (SELECT author_book.book_id FROM author_book JOIN authors ON authors.id = author_book.author_id WHERE LOWER(authors.name) LIKE $1 OR authors.id IN (SELECT author_id FROM author_aliases WHERE LOWER(name) LIKE $2)))
+		AND (LOWER(books.name) LIKE $3 OR books.id IN (SELECT book_id FROM book_titles WHERE LOWER(title) LIKE $4))
+		AND books.language = $5
+		AND LOWER(books.edition) LIKE $6 
+		AND books.publication_year = $7`)).
+		WithArgs("%"+strings.ToLower(authorName)+"%", "%"+strings.ToLower(authorName)+"%", "%"+strings.ToLower(name)+"%", "%"+strings.ToLower(name)+"%", "en", "%"+strings.ToLower(edition)+"%", publicationYear).
+		WillReturnError(context.Canceled)
+
+	_, err := s.repository.GetAllBooks(dtos.GetBooksFilter{Name: name, Language: "en", Edition: edition, PublicationYear: publicationYear, Author: authorName})
+
+	require.Error(s.T(), err)
+
+}
+
+func (s *Suite) Test_repository_Delete_Book() {
+	var (
+		bookId   = 1
+		bookName = "book"
+		workId   = 3
+	)
+
+	s.mock.ExpectBegin()
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "books" WHERE "books"."id" = $1 ORDER BY "books"."id" LIMIT 1`)).
+		WithArgs(bookId).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "work_id"}).
+			AddRow(bookId, bookName, workId))
+
+	s.mock.ExpectExec(regexp.QuoteMeta(
+		`DELETE FROM author_book WHERE author_book.book_id = $1`)).
+		WithArgs(bookId).WillReturnResult(sqlmock.NewResult(int64(bookId), 1))
+
+	s.mock.ExpectExec(regexp.QuoteMeta(
+		`DELETE FROM book_genre WHERE book_genre.book_id = $1`)).
+		WithArgs(bookId).WillReturnResult(sqlmock.NewResult(int64(bookId), 1))
+
+	s.mock.ExpectExec(regexp.QuoteMeta(
+		`DELETE FROM book_tag WHERE book_tag.book_id = $1`)).
+		WithArgs(bookId).WillReturnResult(sqlmock.NewResult(int64(bookId), 1))
+
+	s.mock.ExpectExec(regexp.QuoteMeta(
+		`DELETE FROM book_titles WHERE book_titles.book_id = $1`)).
+		WithArgs(bookId).WillReturnResult(sqlmock.NewResult(int64(bookId), 1))
+
+	s.mock.ExpectExec(regexp.QuoteMeta(
+		`DELETE FROM "books" WHERE "books"."id" = $1`)).
+		WithArgs(bookId).WillReturnResult(sqlmock.NewResult(int64(bookId), 1))
+
+	s.mock.ExpectExec(regexp.QuoteMeta(
+		`DELETE FROM author_work WHERE work_id = $1 AND NOT EXISTS (SELECT 1 FROM books WHERE work_id = $2)`)).
+		WithArgs(workId, workId).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	s.mock.ExpectExec(regexp.QuoteMeta(
+		`DELETE FROM works WHERE id = $1 AND NOT EXISTS (SELECT 1 FROM books WHERE work_id = $2)`)).
+		WithArgs(workId, workId).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	s.mock.ExpectCommit()
+
+	err := s.repository.DeleteBook(bookId)
+
+	require.NoError(s.T(), err)
+}
+
+func (s *Suite) Test_repository_Delete_Book_Error_On_Select() {
+	var (
+		bookId = 1
+	)
+
+	s.mock.ExpectBegin()
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "books" WHERE "books"."id" = $1 ORDER BY "books"."id" LIMIT 1`)).
+		WithArgs(bookId).
+		WillReturnError(context.Canceled)
+
+	s.mock.ExpectRollback()
+
+	err := s.repository.DeleteBook(bookId)
+
+	require.Error(s.T(), err)
+}
+
+func (s *Suite) Test_repository_Delete_Book_Error_On_Delete_Foreign_key() {
+	var (
+		bookId   = 1
+		bookName = "book"
+	)
+
+	s.mock.ExpectBegin()
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "books" WHERE "books"."id" = $1 ORDER BY "books"."id" LIMIT 1`)).
+		WithArgs(bookId).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).
+			AddRow(bookId, bookName))
+
+	s.mock.ExpectExec(regexp.QuoteMeta(
+		`DELETE FROM author_book WHERE author_book.book_id = $1`)).
+		WithArgs(bookId).
+		WillReturnError(context.Canceled)
+
+	s.mock.ExpectRollback()
+
+	err := s.repository.DeleteBook(bookId)
+
+	require.Error(s.T(), err)
+}
+
+func (s *Suite) Test_repository_Delete_Book_Error_On_Delete_Book() {
+	var (
+		bookId   = 1
+		bookName = "book"
+	)
+
+	s.mock.ExpectBegin()
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "books" WHERE "books"."id" = $1 ORDER BY "books"."id" LIMIT 1`)).
+		WithArgs(bookId).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).
+			AddRow(bookId, bookName))
+
+	s.mock.ExpectExec(regexp.QuoteMeta(
+		`DELETE FROM author_book WHERE author_book.book_id = $1`)).
+		WithArgs(bookId).WillReturnResult(sqlmock.NewResult(int64(bookId), 1))
+
+	s.mock.ExpectExec(regexp.QuoteMeta(
+		`DELETE FROM book_genre WHERE book_genre.book_id = $1`)).
+		WithArgs(bookId).WillReturnResult(sqlmock.NewResult(int64(bookId), 1))
+
+	s.mock.ExpectExec(regexp.QuoteMeta(
+		`DELETE FROM book_tag WHERE book_tag.book_id = $1`)).
+		WithArgs(bookId).WillReturnResult(sqlmock.NewResult(int64(bookId), 1))
+
+	s.mock.ExpectExec(regexp.QuoteMeta(
+		`DELETE FROM book_titles WHERE book_titles.book_id = $1`)).
+		WithArgs(bookId).WillReturnResult(sqlmock.NewResult(int64(bookId), 1))
+
+	s.mock.ExpectExec(regexp.QuoteMeta(
+		`DELETE FROM "books" WHERE "books"."id" = $1`)).
+		WithArgs(bookId).WillReturnError(context.Canceled)
+
+	s.mock.ExpectRollback()
+
+	err := s.repository.DeleteBook(bookId)
+
+	require.Error(s.T(), err)
+}
+
+func (s *Suite) Test_repository_Get_Books_By_Natural_Key() {
+	var (
+		id              = 1
+		normalizedName  = "harry potter"
+		edition         = " First "
+		publicationYear = 2022
+		authorId        = 2
+	)
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "books" WHERE normalized_name = $1 AND LOWER(TRIM(edition)) = $2 AND publication_year = $3`)).
+		WithArgs(normalizedName, "first", publicationYear).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "normalized_name"}).
+			AddRow(id, normalizedName))
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "author_book" WHERE "author_book"."book_id" = $1`)).
+		WithArgs(id).
+		WillReturnRows(sqlmock.NewRows([]string{"book_id", "author_id"}).
+			AddRow(id, authorId))
+
+	res, err := s.repository.GetBooksByNaturalKey(normalizedName, edition, publicationYear)
+
+	require.NoError(s.T(), err)
+	require.Nil(s.T(), deep.Equal([]entities.Book{{
+		Id:             id,
+		NormalizedName: normalizedName,
+		Contributors:   []entities.AuthorBook{{BookId: id, AuthorId: authorId}}}}, res))
+}
+
+func (s *Suite) Test_repository_Get_Duplicate_Books() {
+	var (
+		normalizedName = "harry potter"
+	)
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT "normalized_name" FROM "books" WHERE normalized_name <> '' GROUP BY "normalized_name" HAVING COUNT(*) > 1 ORDER BY normalized_name asc LIMIT 10`)).
+		WillReturnRows(sqlmock.NewRows([]string{"normalized_name"}).
+			AddRow(normalizedName))
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "books" WHERE normalized_name IN ($1) ORDER BY normalized_name asc, id asc`)).
+		WithArgs(normalizedName).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "normalized_name"}).
+			AddRow(1, "Harry Potter", normalizedName).
+			AddRow(2, "harry potter!", normalizedName))
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "author_book" WHERE "author_book"."book_id" IN ($1,$2)`)).
+		WithArgs(1, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"book_id", "author_id"}))
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "copies" WHERE "copies"."book_id" IN ($1,$2)`)).
+		WithArgs(1, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "book_id", "status"}))
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "book_genre" WHERE "book_genre"."book_id" IN ($1,$2)`)).
+		WithArgs(1, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"book_id", "genre_id"}))
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "book_tag" WHERE "book_tag"."book_id" IN ($1,$2)`)).
+		WithArgs(1, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"book_id", "tag_id"}))
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "book_titles" WHERE "book_titles"."book_id" IN ($1,$2)`)).
+		WithArgs(1, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"book_id", "locale", "title"}))
+
+	res, err := s.repository.GetDuplicateBooks(dtos.Pagination{Page: 1, Limit: 10})
+
+	require.NoError(s.T(), err)
+	require.Len(s.T(), res, 2)
+}
+
+func (s *Suite) Test_repository_Get_Duplicate_Books_Empty() {
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT "normalized_name" FROM "books"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"normalized_name"}))
+
+	res, err := s.repository.GetDuplicateBooks(dtos.Pagination{Page: 1, Limit: 10})
+
+	require.NoError(s.T(), err)
+	require.Empty(s.T(), res)
+}
+
+func (s *Suite) Test_repository_Merge_Books() {
+	var (
+		targetId     = 1
+		sourceId     = 2
+		name         = "book"
+		edition      = "first"
+		year         = 2022
+		isbn         = "9780261103207"
+		targetWorkId = 4
+		sourceWorkId = 5
+	)
+
+	s.mock.ExpectBegin()
+
+	s.mock.ExpectExec(regexp.QuoteMeta(
+		`SELECT id FROM books WHERE id IN ($1,$2) ORDER BY id FOR UPDATE`)).
+		WithArgs(targetId, sourceId).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT "id","work_id" FROM "books" WHERE "books"."id" = $1 ORDER BY "books"."id" LIMIT 1`)).
+		WithArgs(sourceId).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "work_id"}).AddRow(sourceId, sourceWorkId))
+
+	s.mock.ExpectExec(regexp.QuoteMeta(
+		`INSERT INTO author_book (book_id, author_id, role, position) `+
+			`SELECT $1, author_id, role, position + (SELECT COALESCE(MAX(position) + 1, 0) FROM author_book WHERE book_id = $2) `+
+			`FROM author_book WHERE book_id = $3 ON CONFLICT DO NOTHING`)).
+		WithArgs(targetId, targetId, sourceId).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	s.mock.ExpectExec(regexp.QuoteMeta(
+		`DELETE FROM author_book WHERE author_book.book_id = $1`)).
+		WithArgs(sourceId).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	s.mock.ExpectExec(regexp.QuoteMeta(
+		`INSERT INTO book_genre (book_id, genre_id) SELECT $1, genre_id FROM book_genre WHERE book_id = $2 ON CONFLICT DO NOTHING`)).
+		WithArgs(targetId, sourceId).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	s.mock.ExpectExec(regexp.QuoteMeta(
+		`DELETE FROM book_genre WHERE book_genre.book_id = $1`)).
+		WithArgs(sourceId).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	s.mock.ExpectExec(regexp.QuoteMeta(
+		`INSERT INTO book_tag (book_id, tag_id) SELECT $1, tag_id FROM book_tag WHERE book_id = $2 ON CONFLICT DO NOTHING`)).
+		WithArgs(targetId, sourceId).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	s.mock.ExpectExec(regexp.QuoteMeta(
+		`DELETE FROM book_tag WHERE book_tag.book_id = $1`)).
+		WithArgs(sourceId).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	s.mock.ExpectExec(regexp.QuoteMeta(
+		`INSERT INTO book_titles (book_id, locale, title) `+
+			`SELECT $1, locale, title FROM book_titles WHERE book_id = $2 ON CONFLICT DO NOTHING`)).
+		WithArgs(targetId, sourceId).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	s.mock.ExpectExec(regexp.QuoteMeta(
+		`DELETE FROM book_titles WHERE book_titles.book_id = $1`)).
+		WithArgs(sourceId).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	s.mock.ExpectExec(regexp.QuoteMeta(
+		`UPDATE copies SET book_id = $1 WHERE book_id = $2`)).
+		WithArgs(targetId, sourceId).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	s.mock.ExpectExec(regexp.QuoteMeta(
+		`UPDATE holds SET status = $1, copy_id = NULL WHERE book_id = $2 AND status IN ($3,$4) `+
+			`AND patron_id IN (SELECT patron_id FROM holds WHERE book_id = $5 AND status IN ($6,$7))`)).
+		WithArgs(dtos.HoldStatusCancelled, sourceId, dtos.HoldStatusWaiting, dtos.HoldStatusReady,
+			targetId, dtos.HoldStatusWaiting, dtos.HoldStatusReady).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	s.mock.ExpectExec(regexp.QuoteMeta(
+		`UPDATE holds SET book_id = $1 WHERE book_id = $2`)).
+		WithArgs(targetId, sourceId).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+
+	s.mock.ExpectExec(regexp.QuoteMeta(
+		`DELETE FROM reviews WHERE book_id = $1 AND patron_id IN (SELECT patron_id FROM reviews WHERE book_id = $2)`)).
+		WithArgs(sourceId, targetId).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	s.mock.ExpectExec(regexp.QuoteMeta(
+		`UPDATE reviews SET book_id = $1 WHERE book_id = $2`)).
+		WithArgs(targetId, sourceId).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	s.mock.ExpectExec(regexp.QuoteMeta(
+		`DELETE FROM reading_list_entries WHERE book_id = $1 AND list_id IN (SELECT list_id FROM reading_list_entries WHERE book_id = $2)`)).
+		WithArgs(sourceId, targetId).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	s.mock.ExpectExec(regexp.QuoteMeta(
+		`UPDATE reading_list_entries SET book_id = $1 WHERE book_id = $2`)).
+		WithArgs(targetId, sourceId).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	s.mock.ExpectExec(regexp.QuoteMeta(
+		`UPDATE book_covers SET book_id = $1 WHERE book_id = $2 AND NOT EXISTS (SELECT 1 FROM book_covers WHERE book_id = $3)`)).
+		WithArgs(targetId, sourceId, targetId).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	s.mock.ExpectExec(regexp.QuoteMeta(
+		`UPDATE book_files SET book_id = $1 WHERE book_id = $2`)).
+		WithArgs(targetId, sourceId).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	s.mock.ExpectExec(regexp.QuoteMeta(
+		`DELETE FROM "books" WHERE "books"."id" = $1`)).
+		WithArgs(sourceId).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	s.mock.ExpectExec(regexp.QuoteMeta(
+		`UPDATE "books" SET "name"=$1,"normalized_name"=$2,"natural_key"=$3,"language"=$4,"edition"=$5,"publication_year"=$6,"isbn13"=$7,"work_id"=$8,"publisher_id"=$9,"series_id"=$10,"series_position"=$11 WHERE "id" = $12`)).
+		WithArgs(name, name, nil, "", edition, year, isbn, targetWorkId, nil, nil, nil, targetId).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	s.mock.ExpectExec(regexp.QuoteMeta(
+		`UPDATE books SET rating_count = (SELECT COUNT(*) FROM reviews WHERE reviews.book_id = books.id), ` +
+			`rating_average = COALESCE((SELECT ROUND(AVG(rating), 2) FROM reviews WHERE reviews.book_id = books.id), 0) WHERE books.id IN ($1)`)).
+		WithArgs(targetId).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	s.mock.ExpectExec(regexp.QuoteMeta(
+		`DELETE FROM author_work WHERE work_id = $1 AND NOT EXISTS (SELECT 1 FROM books WHERE work_id = $2)`)).
+		WithArgs(sourceWorkId, sourceWorkId).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	s.mock.ExpectExec(regexp.QuoteMeta(
+		`DELETE FROM works WHERE id = $1 AND NOT EXISTS (SELECT 1 FROM books WHERE work_id = $2)`)).
+		WithArgs(sourceWorkId, sourceWorkId).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	s.mock.ExpectCommit()
+
+	err := s.repository.MergeBooks(entities.Book{Id: targetId, Name: name, NormalizedName: name, Edition: edition, PublicationYear: year, Isbn13: &isbn, WorkId: &targetWorkId}, sourceId)
+
+	require.NoError(s.T(), err)
+}
+
+func (s *Suite) Test_repository_Merge_Books_Error_On_Move_Authors() {
+	var (
+		targetId     = 1
+		sourceId     = 2
+		sourceWorkId = 5
+	)
+
+	s.mock.ExpectBegin()
+
+	s.mock.ExpectExec(regexp.QuoteMeta(
+		`SELECT id FROM books WHERE id IN ($1,$2) ORDER BY id FOR UPDATE`)).
+		WithArgs(targetId, sourceId).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT "id","work_id" FROM "books" WHERE "books"."id" = $1 ORDER BY "books"."id" LIMIT 1`)).
+		WithArgs(sourceId).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "work_id"}).AddRow(sourceId, sourceWorkId))
+
+	s.mock.ExpectExec(regexp.QuoteMeta(
+		`INSERT INTO author_book (book_id, author_id, role, position)`)).
+		WithArgs(targetId, targetId, sourceId).
+		WillReturnError(context.Canceled)
+
+	s.mock.ExpectRollback()
+
+	err := s.repository.MergeBooks(entities.Book{Id: targetId, Name: "book"}, sourceId)
+
+	require.Error(s.T(), err)
+}
+
+func (s *Suite) Test_repository_Add_Role_To_Contributors_Key() {
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT COUNT(*) FROM information_schema.key_column_usage WHERE table_name = 'author_book' AND constraint_name = 'author_book_pkey' AND column_name = 'role'`)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+
+	s.mock.ExpectBegin()
+
+	s.mock.ExpectExec(regexp.QuoteMeta(
+		`UPDATE author_book SET role = $1 WHERE role IS NULL OR role = ''`)).
+		WithArgs(dtos.RoleAuthor).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	s.mock.ExpectExec(regexp.QuoteMeta(
+		`ALTER TABLE author_book DROP CONSTRAINT IF EXISTS author_book_pkey, ADD PRIMARY KEY (book_id, author_id, role)`)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	s.mock.ExpectCommit()
+
+	err := s.repository.AddRoleToContributorsKey()
+
+	require.NoError(s.T(), err)
+}
+
+func (s *Suite) Test_repository_Add_Role_To_Contributors_Key_Already_Added() {
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT COUNT(*) FROM information_schema.key_column_usage`)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+
+	err := s.repository.AddRoleToContributorsKey()
+
+	require.NoError(s.T(), err)
+}
+
+func (s *Suite) Test_repository_Backfill_Normalized_Name() {
+	var (
+		id             = 1
+		name           = "Harry Potter"
+		normalizedName = "harry potter"
+	)
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "books" WHERE (normalized_name = '' OR normalized_name IS NULL) AND id > $1 ORDER BY id asc LIMIT 500`)).
+		WithArgs(0).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).
+			AddRow(id, name))
+
+	s.mock.ExpectBegin()
+
+	s.mock.ExpectExec(regexp.QuoteMeta(
+		`UPDATE "books" SET "normalized_name"=$1 WHERE "id" = $2`)).
+		WithArgs(normalizedName, id).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	s.mock.ExpectCommit()
+
+	books, err := s.repository.GetBooksWithoutNormalizedName(0, 500)
+	require.NoError(s.T(), err)
+	require.Nil(s.T(), deep.Equal([]entities.Book{{Id: id, Name: name}}, books))
+
+	err = s.repository.UpdateNormalizedName(id, normalizedName)
+	require.NoError(s.T(), err)
+}
+
+func (s *Suite) Test_repository_Prefill_Book() {
+	isbn13 := "9780141439761"
+
+	s.mock.ExpectBegin()
+
+	s.mock.ExpectExec(regexp.QuoteMeta(
+		`UPDATE "books" SET "language"=$1,"isbn13"=$2 WHERE "id" = $3`)).
+		WithArgs("en", isbn13, 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	s.mock.ExpectCommit()
+
+	err := s.repository.PrefillBook(1, entities.Book{Language: "en", Isbn13: &isbn13})
+
+	require.NoError(s.T(), err)
+}
+
+func (s *Suite) Test_repository_Prefill_Book_Error() {
+	s.mock.ExpectBegin()
+
+	s.mock.ExpectExec(regexp.QuoteMeta(
+		`UPDATE "books" SET "language"=$1 WHERE "id" = $2`)).
+		WithArgs("en", 1).
+		WillReturnError(context.Canceled)
+
+	s.mock.ExpectRollback()
+
+	err := s.repository.PrefillBook(1, entities.Book{Language: "en"})
+
+	require.Error(s.T(), err)
+}
+
+func (s *Suite) Test_repository_Add_Book_Tags() {
+	s.mock.ExpectBegin()
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`INSERT INTO "tags" ("name") VALUES ($1),($2) ON CONFLICT DO NOTHING RETURNING "id"`)).
+		WithArgs("dystopia", "classic").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).
+			AddRow(8))
+
+	s.mock.ExpectExec(regexp.QuoteMeta(
+		`INSERT INTO book_tag (book_id, tag_id) SELECT $1, id FROM tags WHERE name IN ($2,$3) ON CONFLICT DO NOTHING`)).
+		WithArgs(1, "dystopia", "classic").
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	s.mock.ExpectCommit()
+
+	err := s.repository.AddBookTags(1, []string{"dystopia", "classic"})
+
+	require.NoError(s.T(), err)
+}
+
+func (s *Suite) Test_repository_Add_Book_Tags_Error() {
+	s.mock.ExpectBegin()
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`INSERT INTO "tags"`)).
+		WillReturnError(context.Canceled)
+
+	s.mock.ExpectRollback()
+
+	err := s.repository.AddBookTags(1, []string{"dystopia"})
+
+	require.Error(s.T(), err)
+}
+
+func (s *Suite) Test_repository_Remove_Book_Tag() {
+	s.mock.ExpectExec(regexp.QuoteMeta(
+		`DELETE FROM book_tag WHERE book_tag.book_id = $1 AND book_tag.tag_id IN (SELECT id FROM tags WHERE name = $2)`)).
+		WithArgs(1, "dystopia").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	s.mock.ExpectExec(regexp.QuoteMeta(
+		`DELETE FROM book_tag WHERE book_tag.book_id = $1 AND book_tag.tag_id IN (SELECT id FROM tags WHERE name = $2)`)).
+		WithArgs(1, "classic").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err := s.repository.RemoveBookTag(1, "dystopia")
+	require.NoError(s.T(), err)
+
+	err = s.repository.RemoveBookTag(1, "classic")
+	require.ErrorIs(s.T(), err, gorm.ErrRecordNotFound)
+}
+
+func (s *Suite) Test_repository_Get_Series_Books() {
+	var (
+		seriesId = 4
+		first    = 1.0
+		novella  = 1.5
+	)
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "books" WHERE series_id = $1 ORDER BY series_position asc nulls last, name asc`)).
+		WithArgs(seriesId).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "series_id", "series_position"}).
+			AddRow(1, "The Fellowship of the Ring", seriesId, first).
+			AddRow(3, "The Adventures of Tom Bombadil", seriesId, novella))
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "author_book" WHERE "author_book"."book_id" IN ($1,$2)`)).
+		WithArgs(1, 3).
+		WillReturnRows(sqlmock.NewRows([]string{"book_id", "author_id"}))
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "copies" WHERE "copies"."book_id" IN ($1,$2)`)).
+		WithArgs(1, 3).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "book_id", "status"}))
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "book_genre" WHERE "book_genre"."book_id" IN ($1,$2)`)).
+		WithArgs(1, 3).
+		WillReturnRows(sqlmock.NewRows([]string{"book_id", "genre_id"}))
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "book_tag" WHERE "book_tag"."book_id" IN ($1,$2)`)).
+		WithArgs(1, 3).
+		WillReturnRows(sqlmock.NewRows([]string{"book_id", "tag_id"}))
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "book_titles" WHERE "book_titles"."book_id" IN ($1,$2)`)).
+		WithArgs(1, 3).
+		WillReturnRows(sqlmock.NewRows([]string{"book_id", "locale", "title"}))
+
+	books, err := s.repository.GetSeriesBooks(seriesId)
+
+	require.NoError(s.T(), err)
+	require.Len(s.T(), books, 2)
+	require.Equal(s.T(), novella, *books[1].SeriesPosition)
+}
+
+func (s *Suite) Test_repository_Get_Series_Books_Error() {
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "books" WHERE series_id = $1`)).
+		WithArgs(4).
+		WillReturnError(context.Canceled)
+
+	_, err := s.repository.GetSeriesBooks(4)
+
+	require.Error(s.T(), err)
+}
+
+func (s *Suite) Test_repository_Get_Books() {
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "books" WHERE id IN ($1,$2)`)).
+		WithArgs(3, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).
+			AddRow(1, "The Hobbit").
+			AddRow(3, "Dune"))
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "author_book" WHERE "author_book"."book_id" IN ($1,$2) ORDER BY position asc, author_id asc`)).
+		WithArgs(1, 3).
+		WillReturnRows(sqlmock.NewRows([]string{"book_id", "author_id"}))
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "copies" WHERE "copies"."book_id" IN ($1,$2)`)).
+		WithArgs(1, 3).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "book_id", "status"}))
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "book_genre" WHERE "book_genre"."book_id" IN ($1,$2)`)).
+		WithArgs(1, 3).
+		WillReturnRows(sqlmock.NewRows([]string{"book_id", "genre_id"}))
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "book_tag" WHERE "book_tag"."book_id" IN ($1,$2)`)).
+		WithArgs(1, 3).
+		WillReturnRows(sqlmock.NewRows([]string{"book_id", "tag_id"}))
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "book_titles" WHERE "book_titles"."book_id" IN ($1,$2)`)).
+		WithArgs(1, 3).
+		WillReturnRows(sqlmock.NewRows([]string{"book_id", "locale", "title"}))
+
+	books, err := s.repository.GetBooks([]int{3, 1})
+
+	require.NoError(s.T(), err)
+	require.Len(s.T(), books, 2)
+}
+
+func (s *Suite) Test_repository_Get_Work_Editions() {
+	var (
+		workId = 2
+	)
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "books" WHERE work_id = $1 ORDER BY publication_year asc, id asc LIMIT 10`)).
+		WithArgs(workId).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "edition", "publication_year", "work_id"}).
+			AddRow(1, "The Hobbit", "first", 1937, workId).
+			AddRow(3, "The Hobbit", "fourth", 1978, workId))
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "author_book" WHERE "author_book"."book_id" IN ($1,$2)`)).
+		WithArgs(1, 3).
+		WillReturnRows(sqlmock.NewRows([]string{"book_id", "author_id"}))
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "copies" WHERE "copies"."book_id" IN ($1,$2)`)).
+		WithArgs(1, 3).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "book_id", "status"}))
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "book_genre" WHERE "book_genre"."book_id" IN ($1,$2)`)).
+		WithArgs(1, 3).
+		WillReturnRows(sqlmock.NewRows([]string{"book_id", "genre_id"}))
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "book_tag" WHERE "book_tag"."book_id" IN ($1,$2)`)).
+		WithArgs(1, 3).
+		WillReturnRows(sqlmock.NewRows([]string{"book_id", "tag_id"}))
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "book_titles" WHERE "book_titles"."book_id" IN ($1,$2)`)).
+		WithArgs(1, 3).
+		WillReturnRows(sqlmock.NewRows([]string{"book_id", "locale", "title"}))
+
+	books, err := s.repository.GetWorkEditions(workId, dtos.Pagination{Page: 1, Limit: 10})
+
+	require.NoError(s.T(), err)
+	require.Len(s.T(), books, 2)
+	require.Equal(s.T(), "fourth", books[1].Edition)
+}
+
+func (s *Suite) Test_repository_Get_Work_Editions_Error() {
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "books" WHERE work_id = $1`)).
+		WithArgs(2).
+		WillReturnError(context.Canceled)
+
+	_, err := s.repository.GetWorkEditions(2, dtos.Pagination{Page: 1, Limit: 10})
+
+	require.Error(s.T(), err)
+}
+
+func (s *Suite) Test_repository_Backfill_Work() {
+	var (
+		id       = 1
+		name     = "The Hobbit"
+		workId   = 2
+		authorId = 5
+	)
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "books" WHERE work_id IS NULL AND id > $1 ORDER BY id asc LIMIT 500`)).
+		WithArgs(0).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).
+			AddRow(id, name))
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "author_book" WHERE "author_book"."book_id" = $1 ORDER BY position asc, author_id asc`)).
+		WithArgs(id).
+		WillReturnRows(sqlmock.NewRows([]string{"book_id", "author_id", "role"}).
+			AddRow(id, authorId, "author"))
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "authors" WHERE "authors"."id" = $1`)).
+		WithArgs(authorId).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).
+			AddRow(authorId, "J. R. R. Tolkien"))
+
+	s.mock.ExpectBegin()
+
+	s.mock.ExpectExec(regexp.QuoteMeta(
+		`UPDATE "books" SET "work_id"=$1 WHERE "id" = $2`)).
+		WithArgs(workId, id).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	s.mock.ExpectCommit()
+
+	books, err := s.repository.GetBooksWithoutWork(0, 500)
+	require.NoError(s.T(), err)
+	require.Nil(s.T(), deep.Equal([]entities.Book{{Id: id, Name: name, Contributors: []entities.AuthorBook{
+		{BookId: id, AuthorId: authorId, Author: entities.Author{Id: authorId, Name: "J. R. R. Tolkien"}, Role: "author"}}}}, books))
+
+	err = s.repository.UpdateWork(id, workId)
+	require.NoError(s.T(), err)
+}
+
+func (s *Suite) Test_repository_Count_Copies() {
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT count(*) FROM "copies" WHERE book_id = $1`)).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).
+			AddRow(2))
+
+	count, err := s.repository.CountCopies(1)
+
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), int64(2), count)
 }
@@ -16,8 +16,8 @@ func (m *BookRepositoryMock) CreateBook(book entities.Book) (entities.Book, erro
 	return args.Get(0).(entities.Book), args.Error(1)
 }
 
-func (m *BookRepositoryMock) UpdateBook(book entities.Book, authors []entities.Author) (entities.Book, error) {
-	args := m.Called(book, authors)
+func (m *BookRepositoryMock) UpdateBook(book entities.Book, contributors []entities.AuthorBook, genres []entities.Genre) (entities.Book, error) {
+	args := m.Called(book, contributors, genres)
 	return args.Get(0).(entities.Book), args.Error(1)
 }
 
@@ -26,6 +26,11 @@ func (m *BookRepositoryMock) GetBook(id int) (entities.Book, error) {
 	return args.Get(0).(entities.Book), args.Error(1)
 }
 
+func (m *BookRepositoryMock) GetBookByIsbn(isbn13 string) (entities.Book, error) {
+	args := m.Called(isbn13)
+	return args.Get(0).(entities.Book), args.Error(1)
+}
+
 func (m *BookRepositoryMock) GetAllBooks(filter dtos.GetBooksFilter) ([]entities.Book, error) {
 	args := m.Called(filter)
 	return args.Get(0).([]entities.Book), args.Error(1)
@@ -35,3 +40,83 @@ func (m *BookRepositoryMock) DeleteBook(id int) error {
 	args := m.Called(id)
 	return args.Error(0)
 }
+
+func (m *BookRepositoryMock) GetBooksByNaturalKey(normalizedName, edition string, publicationYear int) ([]entities.Book, error) {
+	args := m.Called(normalizedName, edition, publicationYear)
+	return args.Get(0).([]entities.Book), args.Error(1)
+}
+
+func (m *BookRepositoryMock) GetDuplicateBooks(pagination dtos.Pagination) ([]entities.Book, error) {
+	args := m.Called(pagination)
+	return args.Get(0).([]entities.Book), args.Error(1)
+}
+
+func (m *BookRepositoryMock) MergeBooks(target entities.Book, sourceId int) error {
+	args := m.Called(target, sourceId)
+	return args.Error(0)
+}
+
+func (m *BookRepositoryMock) GetBooksWithoutNormalizedName(afterId, limit int) ([]entities.Book, error) {
+	args := m.Called(afterId, limit)
+	return args.Get(0).([]entities.Book), args.Error(1)
+}
+
+func (m *BookRepositoryMock) UpdateNormalizedName(id int, normalizedName string) error {
+	args := m.Called(id, normalizedName)
+	return args.Error(0)
+}
+
+func (m *BookRepositoryMock) AddRoleToContributorsKey() error {
+	args := m.Called()
+	return args.Error(0)
+}
+
+func (m *BookRepositoryMock) PrefillBook(id int, fields entities.Book) error {
+	args := m.Called(id, fields)
+	return args.Error(0)
+}
+
+func (m *BookRepositoryMock) AddBookTags(id int, tags []string) error {
+	args := m.Called(id, tags)
+	return args.Error(0)
+}
+
+func (m *BookRepositoryMock) RemoveBookTag(id int, tag string) error {
+	args := m.Called(id, tag)
+	return args.Error(0)
+}
+
+func (m *BookRepositoryMock) GetSeriesBooks(seriesId int) ([]entities.Book, error) {
+	args := m.Called(seriesId)
+	return args.Get(0).([]entities.Book), args.Error(1)
+}
+
+func (m *BookRepositoryMock) GetWorkEditions(workId int, pagination dtos.Pagination) ([]entities.Book, error) {
+	args := m.Called(workId, pagination)
+	return args.Get(0).([]entities.Book), args.Error(1)
+}
+
+func (m *BookRepositoryMock) GetBooksWithoutWork(afterId, limit int) ([]entities.Book, error) {
+	args := m.Called(afterId, limit)
+	return args.Get(0).([]entities.Book), args.Error(1)
+}
+
+func (m *BookRepositoryMock) UpdateWork(id, workId int) error {
+	args := m.Called(id, workId)
+	return args.Error(0)
+}
+
+func (m *BookRepositoryMock) CountCopies(id int) (int64, error) {
+	args := m.Called(id)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *BookRepositoryMock) GetBookNames(ids []int) ([]entities.Book, error) {
+	args := m.Called(ids)
+	return args.Get(0).([]entities.Book), args.Error(1)
+}
+
+func (m *BookRepositoryMock) GetBooks(ids []int) ([]entities.Book, error) {
+	args := m.Called(ids)
+	return args.Get(0).([]entities.Book), args.Error(1)
+}
@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"github/brunojoenk/golang-test/models/entities"
+
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type IBookFileRepository interface {
+	CreateBookFile(file entities.BookFile) (entities.BookFile, error)
+	GetBookFile(id int) (entities.BookFile, error)
+	GetBookFiles(bookId int) ([]entities.BookFile, error)
+	DeleteBookFile(id int) error
+}
+
+// BookFileRepository Book Files Repository
+type BookFileRepository struct {
+	db *gorm.DB
+}
+
+// NewBookFileRepository Repository Constructor
+func NewBookFileRepository(db *gorm.DB) IBookFileRepository {
+	return &BookFileRepository{db: db}
+}
+
+func (r *BookFileRepository) CreateBookFile(file entities.BookFile) (entities.BookFile, error) {
+
+	if result := r.db.Omit(clause.Associations).Create(&file); result.Error != nil {
+		log.Error("Error on create book file: ", result.Error.Error())
+		return entities.BookFile{}, result.Error
+	}
+
+	return file, nil
+}
+
+func (r *BookFileRepository) GetBookFile(id int) (entities.BookFile, error) {
+	var file entities.BookFile
+
+	if result := r.db.First(&file, id); result.Error != nil {
+		log.Error("Error on get book file: ", result.Error.Error())
+		return file, result.Error
+	}
+
+	return file, nil
+}
+
+func (r *BookFileRepository) GetBookFiles(bookId int) ([]entities.BookFile, error) {
+	var files []entities.BookFile
+
+	if result := r.db.Where("book_id = ?", bookId).Order("id").Find(&files); result.Error != nil {
+		log.Error("Error on get book files: ", result.Error.Error())
+		return nil, result.Error
+	}
+
+	return files, nil
+}
+
+func (r *BookFileRepository) DeleteBookFile(id int) error {
+
+	if result := r.db.Delete(&entities.BookFile{}, id); result.Error != nil {
+		log.Error("Error on delete book file: ", result.Error.Error())
+		return result.Error
+	}
+
+	return nil
+}
@@ -0,0 +1,176 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"github/brunojoenk/golang-test/models/entities"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+type Suite struct {
+	suite.Suite
+	DB   *gorm.DB
+	mock sqlmock.Sqlmock
+
+	repository *BookFileRepository
+}
+
+func (s *Suite) AfterTest(_, _ string) {
+	require.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func TestInit(t *testing.T) {
+	suite.Run(t, new(Suite))
+}
+
+func (s *Suite) SetupSuite() {
+	var (
+		db  *sql.DB
+		err error
+	)
+
+	db, s.mock, err = sqlmock.New()
+	require.NoError(s.T(), err)
+
+	dialector := postgres.New(postgres.Config{
+		DSN:                  "sqlmock_db_0",
+		DriverName:           "postgres",
+		Conn:                 db,
+		PreferSimpleProtocol: true,
+	})
+	s.DB, err = gorm.Open(dialector, &gorm.Config{})
+	require.NoError(s.T(), err)
+
+	s.repository = &BookFileRepository{db: s.DB}
+}
+
+var createdAt = time.Date(2022, 10, 1, 10, 0, 0, 0, time.UTC)
+
+var bookFileColumns = []string{"id", "book_id", "key", "name", "format", "size", "pages", "title", "authors", "language", "isbn13", "created_at"}
+
+func (s *Suite) Test_repository_Create_Book_File() {
+	file := entities.BookFile{BookId: 7, Key: "files/7/1664618400000000000", Name: "alice.epub", Format: "epub", Size: 1024,
+		Title: "Alice's Adventures in Wonderland", Authors: "Lewis Carroll", Language: "en", CreatedAt: createdAt}
+
+	s.mock.ExpectBegin()
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`INSERT INTO "book_files" ("book_id","key","name","format","size","pages","title","authors","language","isbn13","created_at") `+
+			`VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11) RETURNING "id"`)).
+		WithArgs(file.BookId, file.Key, file.Name, file.Format, file.Size, file.Pages, file.Title, file.Authors, file.Language, file.Isbn13, file.CreatedAt).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))
+
+	s.mock.ExpectCommit()
+
+	created, err := s.repository.CreateBookFile(file)
+
+	require.NoError(s.T(), err)
+	file.Id = 3
+	require.Equal(s.T(), file, created)
+}
+
+func (s *Suite) Test_repository_Create_Book_File_Error() {
+
+	s.mock.ExpectBegin()
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`INSERT INTO "book_files"`)).
+		WillReturnError(context.Canceled)
+
+	s.mock.ExpectRollback()
+
+	_, err := s.repository.CreateBookFile(entities.BookFile{BookId: 7})
+
+	require.Error(s.T(), err)
+}
+
+func (s *Suite) Test_repository_Get_Book_File() {
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "book_files" WHERE "book_files"."id" = $1 ORDER BY "book_files"."id" LIMIT 1`)).
+		WithArgs(3).
+		WillReturnRows(sqlmock.NewRows(bookFileColumns).
+			AddRow(3, 7, "files/7/1664618400000000000", "book.pdf", "pdf", 2048, 12, "", "", "", "", createdAt))
+
+	file, err := s.repository.GetBookFile(3)
+
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), entities.BookFile{Id: 3, BookId: 7, Key: "files/7/1664618400000000000", Name: "book.pdf", Format: "pdf",
+		Size: 2048, Pages: 12, CreatedAt: createdAt}, file)
+}
+
+func (s *Suite) Test_repository_Get_Book_File_Not_Found() {
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "book_files" WHERE "book_files"."id" = $1`)).
+		WithArgs(3).
+		WillReturnError(gorm.ErrRecordNotFound)
+
+	_, err := s.repository.GetBookFile(3)
+
+	require.ErrorIs(s.T(), err, gorm.ErrRecordNotFound)
+}
+
+func (s *Suite) Test_repository_Get_Book_Files() {
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "book_files" WHERE book_id = $1 ORDER BY id`)).
+		WithArgs(7).
+		WillReturnRows(sqlmock.NewRows(bookFileColumns).
+			AddRow(3, 7, "files/7/1664618400000000000", "book.pdf", "pdf", 2048, 12, "", "", "", "", createdAt).
+			AddRow(4, 7, "files/7/1664618500000000000", "book.epub", "epub", 1024, 0, "Book", "Author", "en", "", createdAt))
+
+	files, err := s.repository.GetBookFiles(7)
+
+	require.NoError(s.T(), err)
+	require.Len(s.T(), files, 2)
+	require.Equal(s.T(), "book.pdf", files[0].Name)
+	require.Equal(s.T(), "Author", files[1].Authors)
+}
+
+func (s *Suite) Test_repository_Get_Book_Files_Error() {
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "book_files" WHERE book_id = $1 ORDER BY id`)).
+		WithArgs(7).
+		WillReturnError(context.Canceled)
+
+	_, err := s.repository.GetBookFiles(7)
+
+	require.Error(s.T(), err)
+}
+
+func (s *Suite) Test_repository_Delete_Book_File() {
+
+	s.mock.ExpectBegin()
+
+	s.mock.ExpectExec(regexp.QuoteMeta(
+		`DELETE FROM "book_files" WHERE "book_files"."id" = $1`)).
+		WithArgs(3).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	s.mock.ExpectCommit()
+
+	err := s.repository.DeleteBookFile(3)
+
+	require.NoError(s.T(), err)
+}
+
+func (s *Suite) Test_repository_Delete_Book_File_Error() {
+
+	s.mock.ExpectBegin()
+
+	s.mock.ExpectExec(regexp.QuoteMeta(
+		`DELETE FROM "book_files"`)).
+		WillReturnError(context.Canceled)
+
+	s.mock.ExpectRollback()
+
+	err := s.repository.DeleteBookFile(3)
+
+	require.Error(s.T(), err)
+}
@@ -0,0 +1,31 @@
+package repository
+
+import (
+	"github/brunojoenk/golang-test/models/entities"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type BookFileRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *BookFileRepositoryMock) CreateBookFile(file entities.BookFile) (entities.BookFile, error) {
+	args := m.Called(file)
+	return args.Get(0).(entities.BookFile), args.Error(1)
+}
+
+func (m *BookFileRepositoryMock) GetBookFile(id int) (entities.BookFile, error) {
+	args := m.Called(id)
+	return args.Get(0).(entities.BookFile), args.Error(1)
+}
+
+func (m *BookFileRepositoryMock) GetBookFiles(bookId int) ([]entities.BookFile, error) {
+	args := m.Called(bookId)
+	return args.Get(0).([]entities.BookFile), args.Error(1)
+}
+
+func (m *BookFileRepositoryMock) DeleteBookFile(id int) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"github/brunojoenk/golang-test/models/dtos"
+	"github/brunojoenk/golang-test/models/entities"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type ICopyRepository interface {
+	CreateCopy(bookCopy entities.Copy) (entities.Copy, error)
+	GetCopy(id int) (entities.Copy, error)
+	GetCopyByBarcode(barcode string) (entities.Copy, error)
+	GetBookCopies(bookId int, filter dtos.GetCopiesFilter) ([]entities.Copy, error)
+	UpdateCopy(bookCopy entities.Copy) (entities.Copy, error)
+	DeleteCopy(id int) error
+}
+
+// CopyRepository Copies Repository
+type CopyRepository struct {
+	db *gorm.DB
+}
+
+// NewCopyRepository Repository Constructor
+func NewCopyRepository(db *gorm.DB) ICopyRepository {
+	return &CopyRepository{db: db}
+}
+
+func (c *CopyRepository) CreateCopy(bookCopy entities.Copy) (entities.Copy, error) {
+
+	if result := c.db.Create(&bookCopy); result.Error != nil {
+		log.Error("Error on create copy: ", result.Error.Error())
+		return entities.Copy{}, result.Error
+	}
+
+	return bookCopy, nil
+}
+
+func (c *CopyRepository) GetCopy(id int) (entities.Copy, error) {
+	var bookCopy entities.Copy
+
+	if result := c.db.First(&bookCopy, id); result.Error != nil {
+		log.Error("Error on get copy: ", result.Error.Error())
+		return bookCopy, result.Error
+	}
+
+	return bookCopy, nil
+}
+
+func (c *CopyRepository) GetCopyByBarcode(barcode string) (entities.Copy, error) {
+	var bookCopy entities.Copy
+
+	if result := c.db.Where("barcode = ?", barcode).First(&bookCopy); result.Error != nil {
+		return bookCopy, result.Error
+	}
+
+	return bookCopy, nil
+}
+
+func (c *CopyRepository) GetBookCopies(bookId int, filter dtos.GetCopiesFilter) ([]entities.Copy, error) {
+
+	copies := make([]entities.Copy, 0)
+	toExec := c.db.Where("book_id = ?", bookId)
+
+	if filter.Status != "" {
+		toExec = toExec.Where("status = ?", filter.Status)
+	}
+
+	if strings.TrimSpace(filter.Branch) != "" {
+		toExec = toExec.Where("LOWER(branch) = ?", strings.ToLower(strings.TrimSpace(filter.Branch)))
+	}
+
+	toExec = toExec.Offset((filter.Page - 1) * filter.Limit).Limit(filter.Limit).Order("id asc")
+
+	if result := toExec.Find(&copies); result.Error != nil {
+		log.Error("Error on get copies of book: ", result.Error.Error())
+		return nil, result.Error
+	}
+
+	return copies, nil
+}
+
+func (c *CopyRepository) UpdateCopy(bookCopy entities.Copy) (entities.Copy, error) {
+
+	if result := c.db.Save(&bookCopy); result.Error != nil {
+		log.Error("Error on update copy: ", result.Error.Error())
+		return entities.Copy{}, result.Error
+	}
+
+	return bookCopy, nil
+}
+
+func (c *CopyRepository) DeleteCopy(id int) error {
+
+	if result := c.db.Delete(&entities.Copy{}, id); result.Error != nil {
+		log.Error("Error on delete copy: ", result.Error.Error())
+		return result.Error
+	}
+
+	return nil
+}
@@ -0,0 +1,152 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"github/brunojoenk/golang-test/models/dtos"
+	"github/brunojoenk/golang-test/models/entities"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-test/deep"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+type Suite struct {
+	suite.Suite
+	DB   *gorm.DB
+	mock sqlmock.Sqlmock
+
+	repository *CopyRepository
+}
+
+func (s *Suite) AfterTest(_, _ string) {
+	require.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func TestInit(t *testing.T) {
+	suite.Run(t, new(Suite))
+}
+
+func (s *Suite) SetupSuite() {
+	var (
+		db  *sql.DB
+		err error
+	)
+
+	db, s.mock, err = sqlmock.New()
+	require.NoError(s.T(), err)
+
+	dialector := postgres.New(postgres.Config{
+		DSN:                  "sqlmock_db_0",
+		DriverName:           "postgres",
+		Conn:                 db,
+		PreferSimpleProtocol: true,
+	})
+	s.DB, err = gorm.Open(dialector, &gorm.Config{})
+	require.NoError(s.T(), err)
+
+	s.repository = &CopyRepository{db: s.DB}
+}
+
+func (s *Suite) Test_repository_Create_Copy() {
+	s.mock.ExpectBegin()
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`INSERT INTO "copies" ("book_id","barcode","branch","location","condition","acquired_at","status") VALUES ($1,$2,$3,$4,$5,$6,$7) RETURNING "id"`)).
+		WithArgs(1, "0001", "Central", "A-12", "good", nil, "available").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).
+			AddRow(5))
+
+	s.mock.ExpectCommit()
+
+	bookCopy, err := s.repository.CreateCopy(entities.Copy{BookId: 1, Barcode: "0001", Branch: "Central", Location: "A-12", Condition: "good", Status: "available"})
+
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), 5, bookCopy.Id)
+}
+
+func (s *Suite) Test_repository_Create_Copy_Error() {
+	s.mock.ExpectBegin()
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`INSERT INTO "copies"`)).
+		WillReturnError(context.Canceled)
+
+	s.mock.ExpectRollback()
+
+	_, err := s.repository.CreateCopy(entities.Copy{BookId: 1, Barcode: "0001"})
+
+	require.Error(s.T(), err)
+}
+
+func (s *Suite) Test_repository_Get_Copy() {
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "copies" WHERE "copies"."id" = $1 ORDER BY "copies"."id" LIMIT 1`)).
+		WithArgs(5).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "book_id", "barcode", "status"}).
+			AddRow(5, 1, "0001", "available"))
+
+	res, err := s.repository.GetCopy(5)
+
+	require.NoError(s.T(), err)
+	require.Nil(s.T(), deep.Equal(entities.Copy{Id: 5, BookId: 1, Barcode: "0001", Status: "available"}, res))
+}
+
+func (s *Suite) Test_repository_Get_Copy_By_Barcode_Not_Found() {
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "copies" WHERE barcode = $1 ORDER BY "copies"."id" LIMIT 1`)).
+		WithArgs("0001").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	_, err := s.repository.GetCopyByBarcode("0001")
+
+	require.ErrorIs(s.T(), err, gorm.ErrRecordNotFound)
+}
+
+func (s *Suite) Test_repository_Get_Book_Copies() {
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "copies" WHERE book_id = $1 AND status = $2 AND LOWER(branch) = $3 ORDER BY id asc LIMIT 10`)).
+		WithArgs(1, "available", "central").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "book_id", "barcode", "branch", "status"}).
+			AddRow(5, 1, "0001", "Central", "available"))
+
+	res, err := s.repository.GetBookCopies(1, dtos.GetCopiesFilter{Status: "available", Branch: " Central ", Pagination: dtos.Pagination{Page: 1, Limit: 10}})
+
+	require.NoError(s.T(), err)
+	require.Nil(s.T(), deep.Equal([]entities.Copy{{Id: 5, BookId: 1, Barcode: "0001", Branch: "Central", Status: "available"}}, res))
+}
+
+func (s *Suite) Test_repository_Update_Copy() {
+	s.mock.ExpectBegin()
+
+	s.mock.ExpectExec(regexp.QuoteMeta(
+		`UPDATE "copies" SET "book_id"=$1,"barcode"=$2,"branch"=$3,"location"=$4,"condition"=$5,"acquired_at"=$6,"status"=$7 WHERE "id" = $8`)).
+		WithArgs(1, "0001", "Central", "", "fair", nil, "lost", 5).
+		WillReturnResult(sqlmock.NewResult(5, 1))
+
+	s.mock.ExpectCommit()
+
+	_, err := s.repository.UpdateCopy(entities.Copy{Id: 5, BookId: 1, Barcode: "0001", Branch: "Central", Condition: "fair", Status: "lost"})
+
+	require.NoError(s.T(), err)
+}
+
+func (s *Suite) Test_repository_Delete_Copy() {
+	s.mock.ExpectBegin()
+
+	s.mock.ExpectExec(regexp.QuoteMeta(
+		`DELETE FROM "copies" WHERE "copies"."id" = $1`)).
+		WithArgs(5).
+		WillReturnResult(sqlmock.NewResult(5, 1))
+
+	s.mock.ExpectCommit()
+
+	err := s.repository.DeleteCopy(5)
+
+	require.NoError(s.T(), err)
+}
@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"github/brunojoenk/golang-test/models/dtos"
+	"github/brunojoenk/golang-test/models/entities"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type CopyRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *CopyRepositoryMock) CreateCopy(bookCopy entities.Copy) (entities.Copy, error) {
+	args := m.Called(bookCopy)
+	return args.Get(0).(entities.Copy), args.Error(1)
+}
+
+func (m *CopyRepositoryMock) GetCopy(id int) (entities.Copy, error) {
+	args := m.Called(id)
+	return args.Get(0).(entities.Copy), args.Error(1)
+}
+
+func (m *CopyRepositoryMock) GetCopyByBarcode(barcode string) (entities.Copy, error) {
+	args := m.Called(barcode)
+	return args.Get(0).(entities.Copy), args.Error(1)
+}
+
+func (m *CopyRepositoryMock) GetBookCopies(bookId int, filter dtos.GetCopiesFilter) ([]entities.Copy, error) {
+	args := m.Called(bookId, filter)
+	return args.Get(0).([]entities.Copy), args.Error(1)
+}
+
+func (m *CopyRepositoryMock) UpdateCopy(bookCopy entities.Copy) (entities.Copy, error) {
+	args := m.Called(bookCopy)
+	return args.Get(0).(entities.Copy), args.Error(1)
+}
+
+func (m *CopyRepositoryMock) DeleteCopy(id int) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
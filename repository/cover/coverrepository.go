@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"github/brunojoenk/golang-test/models/entities"
+
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ICoverRepository interface {
+	GetCover(bookId int) (entities.BookCover, error)
+	SaveCover(cover entities.BookCover) error
+	DeleteCover(bookId int) error
+}
+
+// CoverRepository Book Covers Repository
+type CoverRepository struct {
+	db *gorm.DB
+}
+
+// NewCoverRepository Repository Constructor
+func NewCoverRepository(db *gorm.DB) ICoverRepository {
+	return &CoverRepository{db: db}
+}
+
+func (r *CoverRepository) GetCover(bookId int) (entities.BookCover, error) {
+	var cover entities.BookCover
+
+	if result := r.db.Where("book_id = ?", bookId).First(&cover); result.Error != nil {
+		log.Error("Error on get book cover: ", result.Error.Error())
+		return cover, result.Error
+	}
+
+	return cover, nil
+}
+
+// SaveCover creates the cover of the book or replaces the one it had
+func (r *CoverRepository) SaveCover(cover entities.BookCover) error {
+
+	if result := r.db.Omit(clause.Associations).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "book_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"key", "content_type", "width", "height", "size", "updated_at"}),
+	}).Create(&cover); result.Error != nil {
+		log.Error("Error on save book cover: ", result.Error.Error())
+		return result.Error
+	}
+
+	return nil
+}
+
+func (r *CoverRepository) DeleteCover(bookId int) error {
+
+	if result := r.db.Where("book_id = ?", bookId).Delete(&entities.BookCover{}); result.Error != nil {
+		log.Error("Error on delete book cover: ", result.Error.Error())
+		return result.Error
+	}
+
+	return nil
+}
@@ -0,0 +1,146 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"github/brunojoenk/golang-test/models/entities"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+type Suite struct {
+	suite.Suite
+	DB   *gorm.DB
+	mock sqlmock.Sqlmock
+
+	repository *CoverRepository
+}
+
+func (s *Suite) AfterTest(_, _ string) {
+	require.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func TestInit(t *testing.T) {
+	suite.Run(t, new(Suite))
+}
+
+func (s *Suite) SetupSuite() {
+	var (
+		db  *sql.DB
+		err error
+	)
+
+	db, s.mock, err = sqlmock.New()
+	require.NoError(s.T(), err)
+
+	dialector := postgres.New(postgres.Config{
+		DSN:                  "sqlmock_db_0",
+		DriverName:           "postgres",
+		Conn:                 db,
+		PreferSimpleProtocol: true,
+	})
+	s.DB, err = gorm.Open(dialector, &gorm.Config{})
+	require.NoError(s.T(), err)
+
+	s.repository = &CoverRepository{db: s.DB}
+}
+
+var updatedAt = time.Date(2022, 10, 1, 10, 0, 0, 0, time.UTC)
+
+func (s *Suite) Test_repository_Get_Cover() {
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "book_covers" WHERE book_id = $1 ORDER BY "book_covers"."book_id" LIMIT 1`)).
+		WithArgs(7).
+		WillReturnRows(sqlmock.NewRows([]string{"book_id", "key", "content_type", "width", "height", "size", "updated_at"}).
+			AddRow(7, "covers/7/1664618400000000000", "image/png", 600, 900, 20480, updatedAt))
+
+	cover, err := s.repository.GetCover(7)
+
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), entities.BookCover{BookId: 7, Key: "covers/7/1664618400000000000", ContentType: "image/png",
+		Width: 600, Height: 900, Size: 20480, UpdatedAt: updatedAt}, cover)
+}
+
+func (s *Suite) Test_repository_Get_Cover_Not_Found() {
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "book_covers" WHERE book_id = $1`)).
+		WithArgs(7).
+		WillReturnError(gorm.ErrRecordNotFound)
+
+	_, err := s.repository.GetCover(7)
+
+	require.ErrorIs(s.T(), err, gorm.ErrRecordNotFound)
+}
+
+func (s *Suite) Test_repository_Save_Cover() {
+	cover := entities.BookCover{BookId: 7, Key: "covers/7/1664618400000000000", ContentType: "image/png",
+		Width: 600, Height: 900, Size: 20480, UpdatedAt: updatedAt}
+
+	s.mock.ExpectBegin()
+
+	s.mock.ExpectExec(regexp.QuoteMeta(
+		`INSERT INTO "book_covers" ("book_id","key","content_type","width","height","size","updated_at") VALUES ($1,$2,$3,$4,$5,$6,$7) `+
+			`ON CONFLICT ("book_id") DO UPDATE SET "key"="excluded"."key","content_type"="excluded"."content_type",`+
+			`"width"="excluded"."width","height"="excluded"."height","size"="excluded"."size","updated_at"="excluded"."updated_at"`)).
+		WithArgs(cover.BookId, cover.Key, cover.ContentType, cover.Width, cover.Height, cover.Size, cover.UpdatedAt).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	s.mock.ExpectCommit()
+
+	err := s.repository.SaveCover(cover)
+
+	require.NoError(s.T(), err)
+}
+
+func (s *Suite) Test_repository_Save_Cover_Error() {
+
+	s.mock.ExpectBegin()
+
+	s.mock.ExpectExec(regexp.QuoteMeta(
+		`INSERT INTO "book_covers"`)).
+		WillReturnError(context.Canceled)
+
+	s.mock.ExpectRollback()
+
+	err := s.repository.SaveCover(entities.BookCover{BookId: 7})
+
+	require.Error(s.T(), err)
+}
+
+func (s *Suite) Test_repository_Delete_Cover() {
+
+	s.mock.ExpectBegin()
+
+	s.mock.ExpectExec(regexp.QuoteMeta(
+		`DELETE FROM "book_covers" WHERE book_id = $1`)).
+		WithArgs(7).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	s.mock.ExpectCommit()
+
+	err := s.repository.DeleteCover(7)
+
+	require.NoError(s.T(), err)
+}
+
+func (s *Suite) Test_repository_Delete_Cover_Error() {
+
+	s.mock.ExpectBegin()
+
+	s.mock.ExpectExec(regexp.QuoteMeta(
+		`DELETE FROM "book_covers"`)).
+		WillReturnError(context.Canceled)
+
+	s.mock.ExpectRollback()
+
+	err := s.repository.DeleteCover(7)
+
+	require.Error(s.T(), err)
+}
@@ -0,0 +1,26 @@
+package repository
+
+import (
+	"github/brunojoenk/golang-test/models/entities"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type CoverRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *CoverRepositoryMock) GetCover(bookId int) (entities.BookCover, error) {
+	args := m.Called(bookId)
+	return args.Get(0).(entities.BookCover), args.Error(1)
+}
+
+func (m *CoverRepositoryMock) SaveCover(cover entities.BookCover) error {
+	args := m.Called(cover)
+	return args.Error(0)
+}
+
+func (m *CoverRepositoryMock) DeleteCover(bookId int) error {
+	args := m.Called(bookId)
+	return args.Error(0)
+}
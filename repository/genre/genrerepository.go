@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"github/brunojoenk/golang-test/models/entities"
+
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type IGenreRepository interface {
+	CreateGenre(genre entities.Genre) (entities.Genre, error)
+	GetGenre(id int) (entities.Genre, error)
+	GetAllGenres() ([]entities.Genre, error)
+	GetGenreByName(parentId *int, normalizedName string) (entities.Genre, error)
+	UpdateGenre(genre entities.Genre) (entities.Genre, error)
+	DeleteGenre(id int) error
+	CountChildren(id int) (int64, error)
+}
+
+// GenreRepository Genres Repository
+type GenreRepository struct {
+	db *gorm.DB
+}
+
+// NewGenreRepository Repository Constructor
+func NewGenreRepository(db *gorm.DB) IGenreRepository {
+	return &GenreRepository{db: db}
+}
+
+func (g *GenreRepository) CreateGenre(genre entities.Genre) (entities.Genre, error) {
+
+	if result := g.db.Create(&genre); result.Error != nil {
+		log.Error("Error on create genre: ", result.Error.Error())
+		return entities.Genre{}, result.Error
+	}
+
+	return genre, nil
+}
+
+func (g *GenreRepository) GetGenre(id int) (entities.Genre, error) {
+	var genre entities.Genre
+
+	if result := g.db.First(&genre, id); result.Error != nil {
+		log.Error("Error on get genre: ", result.Error.Error())
+		return genre, result.Error
+	}
+
+	return genre, nil
+}
+
+func (g *GenreRepository) GetAllGenres() ([]entities.Genre, error) {
+	var genres []entities.Genre
+
+	if result := g.db.Order("name asc").Find(&genres); result.Error != nil {
+		log.Error("Error on get all genres: ", result.Error.Error())
+		return nil, result.Error
+	}
+
+	return genres, nil
+}
+
+// GetGenreByName returns the child of parentId (a root genre when nil) with the normalized name
+func (g *GenreRepository) GetGenreByName(parentId *int, normalizedName string) (entities.Genre, error) {
+	var genre entities.Genre
+
+	toExec := g.db.Where("normalized_name = ?", normalizedName)
+	if parentId == nil {
+		toExec = toExec.Where("parent_id IS NULL")
+	} else {
+		toExec = toExec.Where("parent_id = ?", *parentId)
+	}
+
+	if result := toExec.First(&genre); result.Error != nil {
+		return genre, result.Error
+	}
+
+	return genre, nil
+}
+
+func (g *GenreRepository) UpdateGenre(genre entities.Genre) (entities.Genre, error) {
+
+	if result := g.db.Save(&genre); result.Error != nil {
+		log.Error("Error on update genre: ", result.Error.Error())
+		return entities.Genre{}, result.Error
+	}
+
+	return genre, nil
+}
+
+// DeleteGenre deletes the genre and its links to books
+func (g *GenreRepository) DeleteGenre(id int) error {
+	return g.db.Transaction(func(tx *gorm.DB) error {
+		if result := tx.Exec("DELETE FROM book_genre WHERE book_genre.genre_id = ?", id); result.Error != nil {
+			log.Error("Error on delete relations from book_genre: ", result.Error.Error())
+			return result.Error
+		}
+
+		if result := tx.Delete(&entities.Genre{}, id); result.Error != nil {
+			log.Error("Error on delete genre: ", result.Error.Error())
+			return result.Error
+		}
+
+		return nil
+	})
+}
+
+func (g *GenreRepository) CountChildren(id int) (int64, error) {
+	var count int64
+
+	if result := g.db.Model(&entities.Genre{}).Where("parent_id = ?", id).Count(&count); result.Error != nil {
+		log.Error("Error on count subgenres: ", result.Error.Error())
+		return 0, result.Error
+	}
+
+	return count, nil
+}
@@ -0,0 +1,205 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"github/brunojoenk/golang-test/models/entities"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-test/deep"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+type Suite struct {
+	suite.Suite
+	DB   *gorm.DB
+	mock sqlmock.Sqlmock
+
+	repository *GenreRepository
+}
+
+func (s *Suite) AfterTest(_, _ string) {
+	require.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func TestInit(t *testing.T) {
+	suite.Run(t, new(Suite))
+}
+
+func (s *Suite) SetupSuite() {
+	var (
+		db  *sql.DB
+		err error
+	)
+
+	db, s.mock, err = sqlmock.New()
+	require.NoError(s.T(), err)
+
+	dialector := postgres.New(postgres.Config{
+		DSN:                  "sqlmock_db_0",
+		DriverName:           "postgres",
+		Conn:                 db,
+		PreferSimpleProtocol: true,
+	})
+	s.DB, err = gorm.Open(dialector, &gorm.Config{})
+	require.NoError(s.T(), err)
+
+	s.repository = &GenreRepository{db: s.DB}
+}
+
+func (s *Suite) Test_repository_Create_Genre() {
+	var (
+		id       = 2
+		name     = "Fantasy"
+		parentId = 1
+	)
+
+	s.mock.ExpectBegin()
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`INSERT INTO "genres" ("name","normalized_name","parent_id") VALUES ($1,$2,$3) RETURNING "id"`)).
+		WithArgs(name, "fantasy", parentId).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).
+			AddRow(id))
+
+	s.mock.ExpectCommit()
+
+	genre, err := s.repository.CreateGenre(entities.Genre{Name: name, NormalizedName: "fantasy", ParentId: &parentId})
+
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), id, genre.Id)
+}
+
+func (s *Suite) Test_repository_Create_Genre_Error() {
+	s.mock.ExpectBegin()
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`INSERT INTO "genres"`)).
+		WillReturnError(context.Canceled)
+
+	s.mock.ExpectRollback()
+
+	_, err := s.repository.CreateGenre(entities.Genre{Name: "Fantasy"})
+
+	require.Error(s.T(), err)
+}
+
+func (s *Suite) Test_repository_Get_Genre_Error() {
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "genres" WHERE "genres"."id" = $1 ORDER BY "genres"."id" LIMIT 1`)).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	_, err := s.repository.GetGenre(1)
+
+	require.ErrorIs(s.T(), err, gorm.ErrRecordNotFound)
+}
+
+func (s *Suite) Test_repository_Get_All_Genres() {
+	parentId := 1
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "genres" ORDER BY name asc`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "parent_id"}).
+			AddRow(2, "Fantasy", parentId).
+			AddRow(1, "Fiction", nil))
+
+	res, err := s.repository.GetAllGenres()
+
+	require.NoError(s.T(), err)
+	require.Nil(s.T(), deep.Equal([]entities.Genre{{Id: 2, Name: "Fantasy", ParentId: &parentId}, {Id: 1, Name: "Fiction"}}, res))
+}
+
+func (s *Suite) Test_repository_Get_Genre_By_Name() {
+	parentId := 1
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "genres" WHERE normalized_name = $1 AND parent_id IS NULL ORDER BY "genres"."id" LIMIT 1`)).
+		WithArgs("fiction").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).
+			AddRow(1, "Fiction"))
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "genres" WHERE normalized_name = $1 AND parent_id = $2 ORDER BY "genres"."id" LIMIT 1`)).
+		WithArgs("fantasy", parentId).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "parent_id"}).
+			AddRow(2, "Fantasy", parentId))
+
+	root, err := s.repository.GetGenreByName(nil, "fiction")
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), 1, root.Id)
+
+	child, err := s.repository.GetGenreByName(&parentId, "fantasy")
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), 2, child.Id)
+}
+
+func (s *Suite) Test_repository_Update_Genre() {
+	parentId := 1
+
+	s.mock.ExpectBegin()
+
+	s.mock.ExpectExec(regexp.QuoteMeta(
+		`UPDATE "genres" SET "name"=$1,"normalized_name"=$2,"parent_id"=$3 WHERE "id" = $4`)).
+		WithArgs("Fantasy", "fantasy", parentId, 2).
+		WillReturnResult(sqlmock.NewResult(2, 1))
+
+	s.mock.ExpectCommit()
+
+	_, err := s.repository.UpdateGenre(entities.Genre{Id: 2, Name: "Fantasy", NormalizedName: "fantasy", ParentId: &parentId})
+
+	require.NoError(s.T(), err)
+}
+
+func (s *Suite) Test_repository_Delete_Genre() {
+	s.mock.ExpectBegin()
+
+	s.mock.ExpectExec(regexp.QuoteMeta(
+		`DELETE FROM book_genre WHERE book_genre.genre_id = $1`)).
+		WithArgs(2).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+
+	s.mock.ExpectExec(regexp.QuoteMeta(
+		`DELETE FROM "genres" WHERE "genres"."id" = $1`)).
+		WithArgs(2).
+		WillReturnResult(sqlmock.NewResult(2, 1))
+
+	s.mock.ExpectCommit()
+
+	err := s.repository.DeleteGenre(2)
+
+	require.NoError(s.T(), err)
+}
+
+func (s *Suite) Test_repository_Delete_Genre_Error_On_Delete_Links() {
+	s.mock.ExpectBegin()
+
+	s.mock.ExpectExec(regexp.QuoteMeta(
+		`DELETE FROM book_genre WHERE book_genre.genre_id = $1`)).
+		WithArgs(2).
+		WillReturnError(context.Canceled)
+
+	s.mock.ExpectRollback()
+
+	err := s.repository.DeleteGenre(2)
+
+	require.Error(s.T(), err)
+}
+
+func (s *Suite) Test_repository_Count_Children() {
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT count(*) FROM "genres" WHERE parent_id = $1`)).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).
+			AddRow(2))
+
+	count, err := s.repository.CountChildren(1)
+
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), int64(2), count)
+}
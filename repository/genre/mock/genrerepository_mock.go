@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"github/brunojoenk/golang-test/models/entities"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type GenreRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *GenreRepositoryMock) CreateGenre(genre entities.Genre) (entities.Genre, error) {
+	args := m.Called(genre)
+	return args.Get(0).(entities.Genre), args.Error(1)
+}
+
+func (m *GenreRepositoryMock) GetGenre(id int) (entities.Genre, error) {
+	args := m.Called(id)
+	return args.Get(0).(entities.Genre), args.Error(1)
+}
+
+func (m *GenreRepositoryMock) GetAllGenres() ([]entities.Genre, error) {
+	args := m.Called()
+	return args.Get(0).([]entities.Genre), args.Error(1)
+}
+
+func (m *GenreRepositoryMock) GetGenreByName(parentId *int, normalizedName string) (entities.Genre, error) {
+	args := m.Called(parentId, normalizedName)
+	return args.Get(0).(entities.Genre), args.Error(1)
+}
+
+func (m *GenreRepositoryMock) UpdateGenre(genre entities.Genre) (entities.Genre, error) {
+	args := m.Called(genre)
+	return args.Get(0).(entities.Genre), args.Error(1)
+}
+
+func (m *GenreRepositoryMock) DeleteGenre(id int) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *GenreRepositoryMock) CountChildren(id int) (int64, error) {
+	args := m.Called(id)
+	return args.Get(0).(int64), args.Error(1)
+}
@@ -0,0 +1,195 @@
+package repository
+
+import (
+	"github/brunojoenk/golang-test/models/dtos"
+	"github/brunojoenk/golang-test/models/entities"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// QueuePlan gets the open holds of a book, oldest first, and its copies and returns the holds and
+// copies it changed. Holds giving a copy up come before the holds taking one
+type QueuePlan func(holds []entities.Hold, copies []entities.Copy) ([]entities.Hold, []entities.Copy)
+
+type IHoldRepository interface {
+	CreateHold(hold entities.Hold) (entities.Hold, error)
+	GetHold(id int) (entities.Hold, error)
+	GetOpenHold(bookId, patronId int) (entities.Hold, error)
+	GetReadyHoldByCopy(copyId int, now time.Time) (entities.Hold, error)
+	GetBookHolds(bookId int) ([]entities.Hold, error)
+	GetBooksWithExpiredHolds(now time.Time) ([]int, error)
+	CancelHold(id int) error
+	ProcessQueue(bookId int, plan QueuePlan) ([]entities.Hold, error)
+}
+
+// HoldRepository Holds Repository
+type HoldRepository struct {
+	db *gorm.DB
+}
+
+// NewHoldRepository Repository Constructor
+func NewHoldRepository(db *gorm.DB) IHoldRepository {
+	return &HoldRepository{db: db}
+}
+
+func (h *HoldRepository) CreateHold(hold entities.Hold) (entities.Hold, error) {
+
+	if result := h.db.Omit("Book", "Patron", "Copy").Create(&hold); result.Error != nil {
+		log.Error("Error on create hold: ", result.Error.Error())
+		return entities.Hold{}, result.Error
+	}
+
+	return hold, nil
+}
+
+func (h *HoldRepository) GetHold(id int) (entities.Hold, error) {
+	var hold entities.Hold
+
+	if result := h.db.First(&hold, id); result.Error != nil {
+		log.Error("Error on get hold: ", result.Error.Error())
+		return hold, result.Error
+	}
+
+	return hold, nil
+}
+
+// GetOpenHold returns the hold of the patron still in the queue of the book
+func (h *HoldRepository) GetOpenHold(bookId, patronId int) (entities.Hold, error) {
+	var hold entities.Hold
+
+	if result := h.db.Where("book_id = ? AND patron_id = ? AND status IN ?", bookId, patronId, dtos.OpenHoldStatuses).
+		First(&hold); result.Error != nil {
+		return hold, result.Error
+	}
+
+	return hold, nil
+}
+
+// GetReadyHoldByCopy returns the hold the copy is put aside for, unless its pickup expired by now
+func (h *HoldRepository) GetReadyHoldByCopy(copyId int, now time.Time) (entities.Hold, error) {
+	var hold entities.Hold
+
+	if result := h.db.Where("copy_id = ? AND status = ? AND (expires_at IS NULL OR expires_at >= ?)", copyId, dtos.HoldStatusReady, now).
+		First(&hold); result.Error != nil {
+		return hold, result.Error
+	}
+
+	return hold, nil
+}
+
+// GetBookHolds returns the queue of the book, the open holds in the order they were placed
+func (h *HoldRepository) GetBookHolds(bookId int) ([]entities.Hold, error) {
+
+	holds := make([]entities.Hold, 0)
+	if result := h.db.Where("book_id = ? AND status IN ?", bookId, dtos.OpenHoldStatuses).
+		Order("id asc").Find(&holds); result.Error != nil {
+		log.Error("Error on get holds of book: ", result.Error.Error())
+		return nil, result.Error
+	}
+
+	return holds, nil
+}
+
+// GetBooksWithExpiredHolds returns the books with a ready hold whose pickup expired by now
+func (h *HoldRepository) GetBooksWithExpiredHolds(now time.Time) ([]int, error) {
+	var bookIds []int
+
+	if result := h.db.Model(&entities.Hold{}).Distinct("book_id").Where("status = ? AND expires_at < ?", dtos.HoldStatusReady, now).
+		Order("book_id asc").Pluck("book_id", &bookIds); result.Error != nil {
+		log.Error("Error on get books with expired holds: ", result.Error.Error())
+		return nil, result.Error
+	}
+
+	return bookIds, nil
+}
+
+// CancelHold takes the hold out of the queue, returning gorm.ErrRecordNotFound when it already left.
+// A copy on hold for it stays on hold until the queue of the book is processed again
+func (h *HoldRepository) CancelHold(id int) error {
+
+	result := h.db.Model(&entities.Hold{}).
+		Where("id = ? AND status IN ?", id, dtos.OpenHoldStatuses).
+		Updates(map[string]interface{}{"status": dtos.HoldStatusCancelled, "copy_id": nil})
+	if result.Error != nil {
+		log.Error("Error on cancel hold: ", result.Error.Error())
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// ProcessQueue applies the plan to the queue of the book in one transaction and returns the holds it
+// changed. The book row is locked so the queue of a book is processed one at a time. A copy only
+// changes while it still has the status it was read with, otherwise everything is rolled back with
+// gorm.ErrRecordNotFound, e.g. when the copy was checked out meanwhile
+func (h *HoldRepository) ProcessQueue(bookId int, plan QueuePlan) ([]entities.Hold, error) {
+
+	var changedHolds []entities.Hold
+	err := h.db.Transaction(func(tx *gorm.DB) error {
+		if result := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&entities.Book{}, bookId); result.Error != nil {
+			log.Error("Error on lock book to process holds: ", result.Error.Error())
+			return result.Error
+		}
+
+		holds := make([]entities.Hold, 0)
+		if result := tx.Where("book_id = ? AND status IN ?", bookId, dtos.OpenHoldStatuses).
+			Order("id asc").Find(&holds); result.Error != nil {
+			log.Error("Error on get holds to process: ", result.Error.Error())
+			return result.Error
+		}
+
+		copies := make([]entities.Copy, 0)
+		if result := tx.Where("book_id = ?", bookId).Order("id asc").Find(&copies); result.Error != nil {
+			log.Error("Error on get copies to process holds: ", result.Error.Error())
+			return result.Error
+		}
+
+		statuses := make(map[int]string, len(copies))
+		for _, bookCopy := range copies {
+			statuses[bookCopy.Id] = bookCopy.Status
+		}
+
+		var changedCopies []entities.Copy
+		changedHolds, changedCopies = plan(holds, copies)
+
+		for _, bookCopy := range changedCopies {
+			result := tx.Model(&entities.Copy{}).
+				Where("id = ? AND status = ?", bookCopy.Id, statuses[bookCopy.Id]).
+				Update("status", bookCopy.Status)
+			if result.Error != nil {
+				log.Error("Error on update copy status of hold: ", result.Error.Error())
+				return result.Error
+			}
+			if result.RowsAffected == 0 {
+				return gorm.ErrRecordNotFound
+			}
+		}
+
+		for _, hold := range changedHolds {
+			if result := tx.Model(&entities.Hold{}).Where("id = ?", hold.Id).
+				Updates(map[string]interface{}{
+					"status":     hold.Status,
+					"copy_id":    hold.CopyId,
+					"ready_at":   hold.ReadyAt,
+					"expires_at": hold.ExpiresAt,
+				}); result.Error != nil {
+				log.Error("Error on update hold: ", result.Error.Error())
+				return result.Error
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return changedHolds, nil
+}
@@ -0,0 +1,245 @@
+package repository
+
+import (
+	"database/sql"
+	"github/brunojoenk/golang-test/models/dtos"
+	"github/brunojoenk/golang-test/models/entities"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-test/deep"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+type Suite struct {
+	suite.Suite
+	DB   *gorm.DB
+	mock sqlmock.Sqlmock
+
+	repository *HoldRepository
+}
+
+func (s *Suite) AfterTest(_, _ string) {
+	require.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func TestInit(t *testing.T) {
+	suite.Run(t, new(Suite))
+}
+
+func (s *Suite) SetupSuite() {
+	var (
+		db  *sql.DB
+		err error
+	)
+
+	db, s.mock, err = sqlmock.New()
+	require.NoError(s.T(), err)
+
+	dialector := postgres.New(postgres.Config{
+		DSN:                  "sqlmock_db_0",
+		DriverName:           "postgres",
+		Conn:                 db,
+		PreferSimpleProtocol: true,
+	})
+	s.DB, err = gorm.Open(dialector, &gorm.Config{})
+	require.NoError(s.T(), err)
+
+	s.repository = &HoldRepository{db: s.DB}
+}
+
+var (
+	placedAt  = time.Date(2022, 10, 3, 14, 30, 0, 0, time.UTC)
+	readyAt   = time.Date(2022, 10, 5, 9, 0, 0, 0, time.UTC)
+	expiresAt = time.Date(2022, 10, 8, 9, 0, 0, 0, time.UTC)
+)
+
+func (s *Suite) Test_repository_Create_Hold() {
+	s.mock.ExpectBegin()
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`INSERT INTO "holds" ("book_id","patron_id","copy_id","status","placed_at","ready_at","expires_at") VALUES ($1,$2,$3,$4,$5,$6,$7) RETURNING "id"`)).
+		WithArgs(1, 3, nil, dtos.HoldStatusWaiting, placedAt, nil, nil).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).
+			AddRow(4))
+
+	s.mock.ExpectCommit()
+
+	hold, err := s.repository.CreateHold(entities.Hold{BookId: 1, PatronId: 3, Status: dtos.HoldStatusWaiting, PlacedAt: placedAt})
+
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), 4, hold.Id)
+}
+
+func (s *Suite) Test_repository_Get_Open_Hold_Not_Found() {
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "holds" WHERE book_id = $1 AND patron_id = $2 AND status IN ($3,$4) ORDER BY "holds"."id" LIMIT 1`)).
+		WithArgs(1, 3, dtos.HoldStatusWaiting, dtos.HoldStatusReady).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	_, err := s.repository.GetOpenHold(1, 3)
+
+	require.ErrorIs(s.T(), err, gorm.ErrRecordNotFound)
+}
+
+func (s *Suite) Test_repository_Get_Book_Holds() {
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "holds" WHERE book_id = $1 AND status IN ($2,$3) ORDER BY id asc`)).
+		WithArgs(1, dtos.HoldStatusWaiting, dtos.HoldStatusReady).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "book_id", "patron_id", "copy_id", "status", "placed_at"}).
+			AddRow(4, 1, 3, 5, dtos.HoldStatusReady, placedAt).
+			AddRow(6, 1, 8, nil, dtos.HoldStatusWaiting, placedAt))
+
+	res, err := s.repository.GetBookHolds(1)
+
+	copyId := 5
+	require.NoError(s.T(), err)
+	require.Nil(s.T(), deep.Equal([]entities.Hold{
+		{Id: 4, BookId: 1, PatronId: 3, CopyId: &copyId, Status: dtos.HoldStatusReady, PlacedAt: placedAt},
+		{Id: 6, BookId: 1, PatronId: 8, Status: dtos.HoldStatusWaiting, PlacedAt: placedAt},
+	}, res))
+}
+
+func (s *Suite) Test_repository_Get_Ready_Hold_By_Copy_Expired() {
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "holds" WHERE copy_id = $1 AND status = $2 AND (expires_at IS NULL OR expires_at >= $3) ORDER BY "holds"."id" LIMIT 1`)).
+		WithArgs(5, dtos.HoldStatusReady, expiresAt.Add(time.Hour)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	_, err := s.repository.GetReadyHoldByCopy(5, expiresAt.Add(time.Hour))
+
+	require.ErrorIs(s.T(), err, gorm.ErrRecordNotFound)
+}
+
+func (s *Suite) Test_repository_Get_Books_With_Expired_Holds() {
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT DISTINCT "book_id" FROM "holds" WHERE status = $1 AND expires_at < $2 ORDER BY book_id asc`)).
+		WithArgs(dtos.HoldStatusReady, expiresAt).
+		WillReturnRows(sqlmock.NewRows([]string{"book_id"}).
+			AddRow(1).
+			AddRow(7))
+
+	res, err := s.repository.GetBooksWithExpiredHolds(expiresAt)
+
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), []int{1, 7}, res)
+}
+
+func (s *Suite) Test_repository_Cancel_Hold() {
+	s.mock.ExpectBegin()
+
+	s.mock.ExpectExec(regexp.QuoteMeta(
+		`UPDATE "holds" SET "copy_id"=$1,"status"=$2 WHERE id = $3 AND status IN ($4,$5)`)).
+		WithArgs(nil, dtos.HoldStatusCancelled, 4, dtos.HoldStatusWaiting, dtos.HoldStatusReady).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	s.mock.ExpectCommit()
+
+	err := s.repository.CancelHold(4)
+
+	require.NoError(s.T(), err)
+}
+
+func (s *Suite) Test_repository_Cancel_Hold_Closed() {
+	s.mock.ExpectBegin()
+
+	s.mock.ExpectExec(regexp.QuoteMeta(
+		`UPDATE "holds" SET "copy_id"=$1,"status"=$2 WHERE id = $3 AND status IN ($4,$5)`)).
+		WithArgs(nil, dtos.HoldStatusCancelled, 4, dtos.HoldStatusWaiting, dtos.HoldStatusReady).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	s.mock.ExpectCommit()
+
+	err := s.repository.CancelHold(4)
+
+	require.ErrorIs(s.T(), err, gorm.ErrRecordNotFound)
+}
+
+func (s *Suite) expectQueueRead() {
+	s.mock.ExpectBegin()
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT "id" FROM "books" WHERE "books"."id" = $1 ORDER BY "books"."id" LIMIT 1 FOR UPDATE`)).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).
+			AddRow(1))
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "holds" WHERE book_id = $1 AND status IN ($2,$3) ORDER BY id asc`)).
+		WithArgs(1, dtos.HoldStatusWaiting, dtos.HoldStatusReady).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "book_id", "patron_id", "status", "placed_at"}).
+			AddRow(4, 1, 3, dtos.HoldStatusWaiting, placedAt))
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "copies" WHERE book_id = $1 ORDER BY id asc`)).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "book_id", "barcode", "status"}).
+			AddRow(5, 1, "0001", dtos.CopyStatusAvailable))
+}
+
+// readyFirst puts the copy of the book aside for the first hold
+func readyFirst(holds []entities.Hold, copies []entities.Copy) ([]entities.Hold, []entities.Copy) {
+	hold, bookCopy := holds[0], copies[0]
+	hold.Status, hold.CopyId, hold.ReadyAt, hold.ExpiresAt = dtos.HoldStatusReady, &bookCopy.Id, &readyAt, &expiresAt
+	bookCopy.Status = dtos.CopyStatusOnHold
+	return []entities.Hold{hold}, []entities.Copy{bookCopy}
+}
+
+func (s *Suite) Test_repository_Process_Queue() {
+	s.expectQueueRead()
+
+	s.mock.ExpectExec(regexp.QuoteMeta(
+		`UPDATE "copies" SET "status"=$1 WHERE id = $2 AND status = $3`)).
+		WithArgs(dtos.CopyStatusOnHold, 5, dtos.CopyStatusAvailable).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	s.mock.ExpectExec(regexp.QuoteMeta(
+		`UPDATE "holds" SET "copy_id"=$1,"expires_at"=$2,"ready_at"=$3,"status"=$4 WHERE id = $5`)).
+		WithArgs(5, expiresAt, readyAt, dtos.HoldStatusReady, 4).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	s.mock.ExpectCommit()
+
+	res, err := s.repository.ProcessQueue(1, readyFirst)
+
+	copyId := 5
+	require.NoError(s.T(), err)
+	require.Nil(s.T(), deep.Equal([]entities.Hold{{Id: 4, BookId: 1, PatronId: 3, CopyId: &copyId,
+		Status: dtos.HoldStatusReady, PlacedAt: placedAt, ReadyAt: &readyAt, ExpiresAt: &expiresAt}}, res))
+}
+
+// The copy was checked out after the queue was read, so nothing of the plan is saved
+func (s *Suite) Test_repository_Process_Queue_Copy_Changed() {
+	s.expectQueueRead()
+
+	s.mock.ExpectExec(regexp.QuoteMeta(
+		`UPDATE "copies" SET "status"=$1 WHERE id = $2 AND status = $3`)).
+		WithArgs(dtos.CopyStatusOnHold, 5, dtos.CopyStatusAvailable).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	s.mock.ExpectRollback()
+
+	_, err := s.repository.ProcessQueue(1, readyFirst)
+
+	require.ErrorIs(s.T(), err, gorm.ErrRecordNotFound)
+}
+
+func (s *Suite) Test_repository_Process_Queue_Book_Not_Found() {
+	s.mock.ExpectBegin()
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT "id" FROM "books" WHERE "books"."id" = $1 ORDER BY "books"."id" LIMIT 1 FOR UPDATE`)).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	s.mock.ExpectRollback()
+
+	_, err := s.repository.ProcessQueue(1, readyFirst)
+
+	require.ErrorIs(s.T(), err, gorm.ErrRecordNotFound)
+}
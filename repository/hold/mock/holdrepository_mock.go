@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"github/brunojoenk/golang-test/models/entities"
+	holdrepo "github/brunojoenk/golang-test/repository/hold"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type HoldRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *HoldRepositoryMock) CreateHold(hold entities.Hold) (entities.Hold, error) {
+	args := m.Called(hold)
+	return args.Get(0).(entities.Hold), args.Error(1)
+}
+
+func (m *HoldRepositoryMock) GetHold(id int) (entities.Hold, error) {
+	args := m.Called(id)
+	return args.Get(0).(entities.Hold), args.Error(1)
+}
+
+func (m *HoldRepositoryMock) GetOpenHold(bookId, patronId int) (entities.Hold, error) {
+	args := m.Called(bookId, patronId)
+	return args.Get(0).(entities.Hold), args.Error(1)
+}
+
+func (m *HoldRepositoryMock) GetReadyHoldByCopy(copyId int, now time.Time) (entities.Hold, error) {
+	args := m.Called(copyId, now)
+	return args.Get(0).(entities.Hold), args.Error(1)
+}
+
+func (m *HoldRepositoryMock) GetBookHolds(bookId int) ([]entities.Hold, error) {
+	args := m.Called(bookId)
+	return args.Get(0).([]entities.Hold), args.Error(1)
+}
+
+func (m *HoldRepositoryMock) GetBooksWithExpiredHolds(now time.Time) ([]int, error) {
+	args := m.Called(now)
+	return args.Get(0).([]int), args.Error(1)
+}
+
+func (m *HoldRepositoryMock) CancelHold(id int) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+// ProcessQueue runs the plan against the holds and copies given to the mock, so tests see the plan
+// of the service applied
+func (m *HoldRepositoryMock) ProcessQueue(bookId int, plan holdrepo.QueuePlan) ([]entities.Hold, error) {
+	args := m.Called(bookId)
+	if err := args.Error(2); err != nil {
+		return nil, err
+	}
+	holds, _ := plan(args.Get(0).([]entities.Hold), args.Get(1).([]entities.Copy))
+	return holds, nil
+}
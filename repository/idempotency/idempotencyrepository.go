@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"github/brunojoenk/golang-test/models/entities"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type IIdempotencyRepository interface {
+	CreateIdempotencyKey(idempotencyKey entities.IdempotencyKey) (bool, error)
+	TakeOverIdempotencyKey(idempotencyKey entities.IdempotencyKey, now time.Time) (bool, error)
+	GetIdempotencyKey(scope, key string) (entities.IdempotencyKey, error)
+	SaveIdempotencyResponse(idempotencyKey entities.IdempotencyKey) error
+	DeleteIdempotencyKey(scope, key string) error
+	DeleteExpiredIdempotencyKeys(now time.Time) error
+}
+
+// IdempotencyRepository Idempotency Keys Repository
+type IdempotencyRepository struct {
+	db *gorm.DB
+}
+
+// NewIdempotencyRepository Repository Constructor
+func NewIdempotencyRepository(db *gorm.DB) IIdempotencyRepository {
+	return &IdempotencyRepository{db: db}
+}
+
+// CreateIdempotencyKey returns false when the key already exists for the scope
+func (i *IdempotencyRepository) CreateIdempotencyKey(idempotencyKey entities.IdempotencyKey) (bool, error) {
+
+	result := i.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&idempotencyKey)
+	if result.Error != nil {
+		log.Error("Error on create idempotency key: ", result.Error.Error())
+		return false, result.Error
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+// TakeOverIdempotencyKey reserves again for a new request a key that expired by now, a finished one not
+// deleted yet or one whose request stopped before releasing it. It returns false when the key has not expired
+func (i *IdempotencyRepository) TakeOverIdempotencyKey(idempotencyKey entities.IdempotencyKey, now time.Time) (bool, error) {
+
+	result := i.db.Model(&entities.IdempotencyKey{}).
+		Where("scope = ? AND key = ? AND expires_at < ?", idempotencyKey.Scope, idempotencyKey.Key, now).
+		Updates(map[string]interface{}{
+			"request_hash": idempotencyKey.RequestHash,
+			"status_code":  0,
+			"content_type": "",
+			"body":         nil,
+			"created_at":   now,
+			"expires_at":   idempotencyKey.ExpiresAt,
+		})
+	if result.Error != nil {
+		log.Error("Error on take over idempotency key: ", result.Error.Error())
+		return false, result.Error
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+func (i *IdempotencyRepository) GetIdempotencyKey(scope, key string) (entities.IdempotencyKey, error) {
+	var idempotencyKey entities.IdempotencyKey
+
+	if result := i.db.Where("scope = ? AND key = ?", scope, key).First(&idempotencyKey); result.Error != nil {
+		log.Error("Error on get idempotency key: ", result.Error.Error())
+		return idempotencyKey, result.Error
+	}
+
+	return idempotencyKey, nil
+}
+
+// SaveIdempotencyResponse stores the response of the request and keeps the key until its new expiry
+func (i *IdempotencyRepository) SaveIdempotencyResponse(idempotencyKey entities.IdempotencyKey) error {
+
+	if result := i.db.Model(&entities.IdempotencyKey{}).
+		Where("scope = ? AND key = ?", idempotencyKey.Scope, idempotencyKey.Key).
+		Updates(map[string]interface{}{
+			"status_code":  idempotencyKey.StatusCode,
+			"content_type": idempotencyKey.ContentType,
+			"body":         idempotencyKey.Body,
+			"expires_at":   idempotencyKey.ExpiresAt,
+		}); result.Error != nil {
+		log.Error("Error on save idempotency response: ", result.Error.Error())
+		return result.Error
+	}
+
+	return nil
+}
+
+func (i *IdempotencyRepository) DeleteIdempotencyKey(scope, key string) error {
+
+	if result := i.db.Where("scope = ? AND key = ?", scope, key).Delete(&entities.IdempotencyKey{}); result.Error != nil {
+		log.Error("Error on delete idempotency key: ", result.Error.Error())
+		return result.Error
+	}
+
+	return nil
+}
+
+func (i *IdempotencyRepository) DeleteExpiredIdempotencyKeys(now time.Time) error {
+
+	if result := i.db.Where("expires_at < ?", now).Delete(&entities.IdempotencyKey{}); result.Error != nil {
+		log.Error("Error on delete expired idempotency keys: ", result.Error.Error())
+		return result.Error
+	}
+
+	return nil
+}
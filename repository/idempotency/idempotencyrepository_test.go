@@ -0,0 +1,206 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"github/brunojoenk/golang-test/models/entities"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+type Suite struct {
+	suite.Suite
+	DB   *gorm.DB
+	mock sqlmock.Sqlmock
+
+	repository *IdempotencyRepository
+}
+
+func (s *Suite) AfterTest(_, _ string) {
+	require.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func TestInit(t *testing.T) {
+	suite.Run(t, new(Suite))
+}
+
+func (s *Suite) SetupSuite() {
+	var (
+		db  *sql.DB
+		err error
+	)
+
+	db, s.mock, err = sqlmock.New()
+	require.NoError(s.T(), err)
+
+	dialector := postgres.New(postgres.Config{
+		DSN:                  "sqlmock_db_0",
+		DriverName:           "postgres",
+		Conn:                 db,
+		PreferSimpleProtocol: true,
+	})
+	s.DB, err = gorm.Open(dialector, &gorm.Config{})
+	require.NoError(s.T(), err)
+
+	s.repository = &IdempotencyRepository{db: s.DB}
+}
+
+func (s *Suite) Test_repository_Create_Idempotency_Key() {
+	var (
+		scope     = "ip-1:POST /book"
+		key       = "key"
+		hash      = "hash"
+		expiresAt = time.Now()
+	)
+	tests := map[string]struct {
+		rowsAffected int64
+		created      bool
+	}{
+		"new key":      {rowsAffected: 1, created: true},
+		"existing key": {rowsAffected: 0, created: false},
+	}
+	for testName, tc := range tests {
+		s.Run(testName, func() {
+			s.mock.ExpectBegin()
+
+			s.mock.ExpectExec(regexp.QuoteMeta(
+				`INSERT INTO "idempotency_keys" ("scope","key","request_hash","status_code","content_type","body","created_at","expires_at") VALUES ($1,$2,$3,$4,$5,$6,$7,$8) ON CONFLICT DO NOTHING`)).
+				WithArgs(scope, key, hash, 0, "", sqlmock.AnyArg(), sqlmock.AnyArg(), expiresAt).
+				WillReturnResult(sqlmock.NewResult(0, tc.rowsAffected))
+
+			s.mock.ExpectCommit()
+
+			created, err := s.repository.CreateIdempotencyKey(entities.IdempotencyKey{Scope: scope, Key: key, RequestHash: hash, ExpiresAt: expiresAt})
+
+			require.NoError(s.T(), err)
+			require.Equal(s.T(), tc.created, created)
+		})
+	}
+}
+
+func (s *Suite) Test_repository_Create_Idempotency_Key_Error() {
+	s.mock.ExpectBegin()
+
+	s.mock.ExpectExec(regexp.QuoteMeta(
+		`INSERT INTO "idempotency_keys"`)).
+		WillReturnError(context.Canceled)
+
+	s.mock.ExpectRollback()
+
+	_, err := s.repository.CreateIdempotencyKey(entities.IdempotencyKey{Scope: "scope", Key: "key"})
+
+	require.Error(s.T(), err)
+}
+
+func (s *Suite) Test_repository_Get_Idempotency_Key() {
+	var (
+		scope = "ip-1:POST /book"
+		key   = "key"
+	)
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "idempotency_keys" WHERE scope = $1 AND key = $2 ORDER BY "idempotency_keys"."scope" LIMIT 1`)).
+		WithArgs(scope, key).
+		WillReturnRows(sqlmock.NewRows([]string{"scope", "key", "status_code"}).
+			AddRow(scope, key, 201))
+
+	res, err := s.repository.GetIdempotencyKey(scope, key)
+
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), 201, res.StatusCode)
+}
+
+func (s *Suite) Test_repository_Save_Idempotency_Response() {
+	var (
+		scope     = "ip-1:POST /book"
+		key       = "key"
+		body      = []byte(`{"id":1}`)
+		expiresAt = time.Now()
+	)
+
+	s.mock.ExpectBegin()
+
+	s.mock.ExpectExec(regexp.QuoteMeta(
+		`UPDATE "idempotency_keys" SET "body"=$1,"content_type"=$2,"expires_at"=$3,"status_code"=$4 WHERE scope = $5 AND key = $6`)).
+		WithArgs(body, "application/json", expiresAt, 201, scope, key).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	s.mock.ExpectCommit()
+
+	err := s.repository.SaveIdempotencyResponse(entities.IdempotencyKey{Scope: scope, Key: key, StatusCode: 201, ContentType: "application/json", Body: body, ExpiresAt: expiresAt})
+
+	require.NoError(s.T(), err)
+}
+
+func (s *Suite) Test_repository_Take_Over_Idempotency_Key() {
+	var (
+		scope     = "ip-1:POST /book"
+		key       = "key"
+		hash      = "hash"
+		now       = time.Now()
+		expiresAt = now.Add(5 * time.Minute)
+	)
+	tests := map[string]struct {
+		rowsAffected int64
+		takenOver    bool
+	}{
+		"expired key":     {rowsAffected: 1, takenOver: true},
+		"key not expired": {rowsAffected: 0, takenOver: false},
+	}
+	for testName, tc := range tests {
+		s.Run(testName, func() {
+			s.mock.ExpectBegin()
+
+			s.mock.ExpectExec(regexp.QuoteMeta(
+				`UPDATE "idempotency_keys" SET "body"=$1,"content_type"=$2,"created_at"=$3,"expires_at"=$4,"request_hash"=$5,"status_code"=$6 WHERE scope = $7 AND key = $8 AND expires_at < $9`)).
+				WithArgs(nil, "", now, expiresAt, hash, 0, scope, key, now).
+				WillReturnResult(sqlmock.NewResult(0, tc.rowsAffected))
+
+			s.mock.ExpectCommit()
+
+			takenOver, err := s.repository.TakeOverIdempotencyKey(entities.IdempotencyKey{Scope: scope, Key: key, RequestHash: hash, ExpiresAt: expiresAt}, now)
+
+			require.NoError(s.T(), err)
+			require.Equal(s.T(), tc.takenOver, takenOver)
+		})
+	}
+}
+
+func (s *Suite) Test_repository_Delete_Expired_Idempotency_Keys() {
+	now := time.Now()
+
+	s.mock.ExpectBegin()
+
+	s.mock.ExpectExec(regexp.QuoteMeta(
+		`DELETE FROM "idempotency_keys" WHERE expires_at < $1`)).
+		WithArgs(now).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+
+	s.mock.ExpectCommit()
+
+	err := s.repository.DeleteExpiredIdempotencyKeys(now)
+
+	require.NoError(s.T(), err)
+}
+
+func (s *Suite) Test_repository_Delete_Idempotency_Key_Error() {
+	s.mock.ExpectBegin()
+
+	s.mock.ExpectExec(regexp.QuoteMeta(
+		`DELETE FROM "idempotency_keys" WHERE scope = $1 AND key = $2`)).
+		WithArgs("scope", "key").
+		WillReturnError(context.Canceled)
+
+	s.mock.ExpectRollback()
+
+	err := s.repository.DeleteIdempotencyKey("scope", "key")
+
+	require.Error(s.T(), err)
+}
@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"github/brunojoenk/golang-test/models/entities"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type IdempotencyRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *IdempotencyRepositoryMock) CreateIdempotencyKey(idempotencyKey entities.IdempotencyKey) (bool, error) {
+	args := m.Called(idempotencyKey)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *IdempotencyRepositoryMock) TakeOverIdempotencyKey(idempotencyKey entities.IdempotencyKey, now time.Time) (bool, error) {
+	args := m.Called(idempotencyKey, now)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *IdempotencyRepositoryMock) GetIdempotencyKey(scope, key string) (entities.IdempotencyKey, error) {
+	args := m.Called(scope, key)
+	return args.Get(0).(entities.IdempotencyKey), args.Error(1)
+}
+
+func (m *IdempotencyRepositoryMock) SaveIdempotencyResponse(idempotencyKey entities.IdempotencyKey) error {
+	args := m.Called(idempotencyKey)
+	return args.Error(0)
+}
+
+func (m *IdempotencyRepositoryMock) DeleteIdempotencyKey(scope, key string) error {
+	args := m.Called(scope, key)
+	return args.Error(0)
+}
+
+func (m *IdempotencyRepositoryMock) DeleteExpiredIdempotencyKeys(now time.Time) error {
+	args := m.Called(now)
+	return args.Error(0)
+}
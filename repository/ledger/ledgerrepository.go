@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"github/brunojoenk/golang-test/models/dtos"
+	"github/brunojoenk/golang-test/models/entities"
+
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ILedgerRepository interface {
+	ChargeLoanFine(loan entities.Loan, totalCents int64, note string) (int64, error)
+	CreateCredit(entry entities.LedgerEntry) (entities.LedgerEntry, error)
+	GetBalance(patronId int) (int64, error)
+	GetPatronEntries(patronId int, pagination dtos.Pagination) ([]entities.LedgerEntry, error)
+}
+
+// LedgerRepository Ledger entries Repository
+type LedgerRepository struct {
+	db *gorm.DB
+}
+
+// NewLedgerRepository Repository Constructor
+func NewLedgerRepository(db *gorm.DB) ILedgerRepository {
+	return &LedgerRepository{db: db}
+}
+
+// ChargeLoanFine brings the fines charged for the loan up to the total, charging the difference, and
+// returns the amount charged. The loan row is locked so the same growth is never charged twice
+func (l *LedgerRepository) ChargeLoanFine(loan entities.Loan, totalCents int64, note string) (int64, error) {
+
+	var charged int64
+	err := l.db.Transaction(func(tx *gorm.DB) error {
+		if result := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&entities.Loan{}, loan.Id); result.Error != nil {
+			log.Error("Error on lock loan to charge fine: ", result.Error.Error())
+			return result.Error
+		}
+
+		var chargedBefore int64
+		if result := tx.Model(&entities.LedgerEntry{}).Select("COALESCE(SUM(amount_cents), 0)").
+			Where("loan_id = ? AND kind = ?", loan.Id, dtos.LedgerKindFine).Scan(&chargedBefore); result.Error != nil {
+			log.Error("Error on sum fines of loan: ", result.Error.Error())
+			return result.Error
+		}
+
+		if totalCents <= chargedBefore {
+			return nil
+		}
+
+		loanId := loan.Id
+		entry := entities.LedgerEntry{
+			PatronId:    loan.PatronId,
+			LoanId:      &loanId,
+			Kind:        dtos.LedgerKindFine,
+			AmountCents: totalCents - chargedBefore,
+			Note:        note,
+		}
+		if result := tx.Omit("Patron", "Loan").Create(&entry); result.Error != nil {
+			log.Error("Error on create fine: ", result.Error.Error())
+			return result.Error
+		}
+
+		charged = entry.AmountCents
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return charged, nil
+}
+
+// CreateCredit saves a payment or waiver, a negative amount, while it is not greater than the balance
+// of the patron, returning gorm.ErrRecordNotFound otherwise. The patron row is locked so two credits
+// at the same time can not both take the same balance
+func (l *LedgerRepository) CreateCredit(entry entities.LedgerEntry) (entities.LedgerEntry, error) {
+
+	err := l.db.Transaction(func(tx *gorm.DB) error {
+		if result := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&entities.Patron{}, entry.PatronId); result.Error != nil {
+			log.Error("Error on lock patron to credit: ", result.Error.Error())
+			return result.Error
+		}
+
+		var balance int64
+		if result := tx.Model(&entities.LedgerEntry{}).Select("COALESCE(SUM(amount_cents), 0)").
+			Where("patron_id = ?", entry.PatronId).Scan(&balance); result.Error != nil {
+			log.Error("Error on sum balance of patron: ", result.Error.Error())
+			return result.Error
+		}
+
+		if balance+entry.AmountCents < 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		if result := tx.Omit("Patron", "Loan").Create(&entry); result.Error != nil {
+			log.Error("Error on create credit: ", result.Error.Error())
+			return result.Error
+		}
+
+		return nil
+	})
+	if err != nil {
+		return entities.LedgerEntry{}, err
+	}
+
+	return entry, nil
+}
+
+func (l *LedgerRepository) GetBalance(patronId int) (int64, error) {
+	var balance int64
+
+	if result := l.db.Model(&entities.LedgerEntry{}).Select("COALESCE(SUM(amount_cents), 0)").
+		Where("patron_id = ?", patronId).Scan(&balance); result.Error != nil {
+		log.Error("Error on get balance of patron: ", result.Error.Error())
+		return 0, result.Error
+	}
+
+	return balance, nil
+}
+
+// GetPatronEntries returns the ledger of the patron, the newest entries first
+func (l *LedgerRepository) GetPatronEntries(patronId int, pagination dtos.Pagination) ([]entities.LedgerEntry, error) {
+
+	entries := make([]entities.LedgerEntry, 0)
+	if result := l.db.Where("patron_id = ?", patronId).
+		Offset((pagination.Page - 1) * pagination.Limit).Limit(pagination.Limit).
+		Order("id desc").Find(&entries); result.Error != nil {
+		log.Error("Error on get ledger of patron: ", result.Error.Error())
+		return nil, result.Error
+	}
+
+	return entries, nil
+}
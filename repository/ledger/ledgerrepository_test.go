@@ -0,0 +1,164 @@
+package repository
+
+import (
+	"database/sql"
+	"github/brunojoenk/golang-test/models/dtos"
+	"github/brunojoenk/golang-test/models/entities"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+type Suite struct {
+	suite.Suite
+	DB   *gorm.DB
+	mock sqlmock.Sqlmock
+
+	repository *LedgerRepository
+}
+
+func (s *Suite) AfterTest(_, _ string) {
+	require.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func TestInit(t *testing.T) {
+	suite.Run(t, new(Suite))
+}
+
+func (s *Suite) SetupSuite() {
+	var (
+		db  *sql.DB
+		err error
+	)
+
+	db, s.mock, err = sqlmock.New()
+	require.NoError(s.T(), err)
+
+	dialector := postgres.New(postgres.Config{
+		DSN:                  "sqlmock_db_0",
+		DriverName:           "postgres",
+		Conn:                 db,
+		PreferSimpleProtocol: true,
+	})
+	s.DB, err = gorm.Open(dialector, &gorm.Config{})
+	require.NoError(s.T(), err)
+
+	s.repository = &LedgerRepository{db: s.DB}
+}
+
+func (s *Suite) Test_repository_Charge_Loan_Fine() {
+	s.mock.ExpectBegin()
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT "id" FROM "loans" WHERE "loans"."id" = $1 ORDER BY "loans"."id" LIMIT 1 FOR UPDATE`)).
+		WithArgs(9).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(9))
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT COALESCE(SUM(amount_cents), 0) FROM "ledger_entries" WHERE loan_id = $1 AND kind = $2`)).
+		WithArgs(9, dtos.LedgerKindFine).
+		WillReturnRows(sqlmock.NewRows([]string{"coalesce"}).AddRow(50))
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`INSERT INTO "ledger_entries" ("patron_id","loan_id","kind","amount_cents","note","created_at") VALUES ($1,$2,$3,$4,$5,$6) RETURNING "id"`)).
+		WithArgs(3, 9, dtos.LedgerKindFine, 25, "Overdue fine", sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(12))
+
+	s.mock.ExpectCommit()
+
+	charged, err := s.repository.ChargeLoanFine(entities.Loan{Id: 9, PatronId: 3}, 75, "Overdue fine")
+
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), int64(25), charged)
+}
+
+func (s *Suite) Test_repository_Charge_Loan_Fine_Charged_Before() {
+	s.mock.ExpectBegin()
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT "id" FROM "loans" WHERE "loans"."id" = $1 ORDER BY "loans"."id" LIMIT 1 FOR UPDATE`)).
+		WithArgs(9).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(9))
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT COALESCE(SUM(amount_cents), 0) FROM "ledger_entries" WHERE loan_id = $1 AND kind = $2`)).
+		WithArgs(9, dtos.LedgerKindFine).
+		WillReturnRows(sqlmock.NewRows([]string{"coalesce"}).AddRow(75))
+
+	s.mock.ExpectCommit()
+
+	charged, err := s.repository.ChargeLoanFine(entities.Loan{Id: 9, PatronId: 3}, 75, "Overdue fine")
+
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), int64(0), charged)
+}
+
+func (s *Suite) Test_repository_Create_Credit() {
+	s.mock.ExpectBegin()
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT "id" FROM "patrons" WHERE "patrons"."id" = $1 ORDER BY "patrons"."id" LIMIT 1 FOR UPDATE`)).
+		WithArgs(3).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT COALESCE(SUM(amount_cents), 0) FROM "ledger_entries" WHERE patron_id = $1`)).
+		WithArgs(3).
+		WillReturnRows(sqlmock.NewRows([]string{"coalesce"}).AddRow(75))
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`INSERT INTO "ledger_entries" ("patron_id","loan_id","kind","amount_cents","note","created_at") VALUES ($1,$2,$3,$4,$5,$6) RETURNING "id"`)).
+		WithArgs(3, nil, dtos.LedgerKindPayment, -50, "cash", sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(13))
+
+	s.mock.ExpectCommit()
+
+	entry, err := s.repository.CreateCredit(entities.LedgerEntry{PatronId: 3, Kind: dtos.LedgerKindPayment, AmountCents: -50, Note: "cash"})
+
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), 13, entry.Id)
+}
+
+func (s *Suite) Test_repository_Create_Credit_Exceeds_Balance() {
+	s.mock.ExpectBegin()
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT "id" FROM "patrons" WHERE "patrons"."id" = $1 ORDER BY "patrons"."id" LIMIT 1 FOR UPDATE`)).
+		WithArgs(3).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT COALESCE(SUM(amount_cents), 0) FROM "ledger_entries" WHERE patron_id = $1`)).
+		WithArgs(3).
+		WillReturnRows(sqlmock.NewRows([]string{"coalesce"}).AddRow(25))
+
+	s.mock.ExpectRollback()
+
+	_, err := s.repository.CreateCredit(entities.LedgerEntry{PatronId: 3, Kind: dtos.LedgerKindPayment, AmountCents: -50})
+
+	require.ErrorIs(s.T(), err, gorm.ErrRecordNotFound)
+}
+
+func (s *Suite) Test_repository_Get_Patron_Entries() {
+	createdAt := time.Date(2022, 10, 3, 14, 30, 0, 0, time.UTC)
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "ledger_entries" WHERE patron_id = $1 ORDER BY id desc LIMIT 10`)).
+		WithArgs(3).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "patron_id", "loan_id", "kind", "amount_cents", "note", "created_at"}).
+			AddRow(13, 3, nil, dtos.LedgerKindPayment, -50, "cash", createdAt).
+			AddRow(12, 3, 9, dtos.LedgerKindFine, 75, "Overdue fine", createdAt))
+
+	entries, err := s.repository.GetPatronEntries(3, dtos.Pagination{Page: 1, Limit: 10})
+
+	require.NoError(s.T(), err)
+	require.Len(s.T(), entries, 2)
+	require.Nil(s.T(), entries[0].LoanId)
+	require.Equal(s.T(), 9, *entries[1].LoanId)
+}
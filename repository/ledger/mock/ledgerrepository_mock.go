@@ -0,0 +1,32 @@
+package repository
+
+import (
+	"github/brunojoenk/golang-test/models/dtos"
+	"github/brunojoenk/golang-test/models/entities"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type LedgerRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *LedgerRepositoryMock) ChargeLoanFine(loan entities.Loan, totalCents int64, note string) (int64, error) {
+	args := m.Called(loan, totalCents, note)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *LedgerRepositoryMock) CreateCredit(entry entities.LedgerEntry) (entities.LedgerEntry, error) {
+	args := m.Called(entry)
+	return args.Get(0).(entities.LedgerEntry), args.Error(1)
+}
+
+func (m *LedgerRepositoryMock) GetBalance(patronId int) (int64, error) {
+	args := m.Called(patronId)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *LedgerRepositoryMock) GetPatronEntries(patronId int, pagination dtos.Pagination) ([]entities.LedgerEntry, error) {
+	args := m.Called(patronId, pagination)
+	return args.Get(0).([]entities.LedgerEntry), args.Error(1)
+}
@@ -0,0 +1,194 @@
+package repository
+
+import (
+	"github/brunojoenk/golang-test/models/dtos"
+	"github/brunojoenk/golang-test/models/entities"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type ILoanRepository interface {
+	CheckOut(loan entities.Loan, holdId int) (entities.Loan, error)
+	CheckIn(loan entities.Loan, fine entities.LedgerEntry) error
+	Renew(loan entities.Loan) error
+	GetLoan(id int) (entities.Loan, error)
+	GetPatronLoans(patronId int, filter dtos.GetLoansFilter, today time.Time) ([]entities.Loan, error)
+	GetOpenLoansDueBefore(date time.Time, afterId, limit int) ([]entities.Loan, error)
+}
+
+// LoanRepository Loans Repository
+type LoanRepository struct {
+	db *gorm.DB
+}
+
+// NewLoanRepository Repository Constructor
+func NewLoanRepository(db *gorm.DB) ILoanRepository {
+	return &LoanRepository{db: db}
+}
+
+// CheckOut marks the copy on loan and creates the loan in one transaction. The copy is only taken
+// while it is still available, so of two checkouts of the same copy at the same time one gets
+// gorm.ErrRecordNotFound. With a hold ID the copy is the one on hold for the patron instead, and
+// the hold is fulfilled while it is still ready
+func (l *LoanRepository) CheckOut(loan entities.Loan, holdId int) (entities.Loan, error) {
+	err := l.db.Transaction(func(tx *gorm.DB) error {
+		fromStatus := dtos.CopyStatusAvailable
+		if holdId != 0 {
+			fromStatus = dtos.CopyStatusOnHold
+		}
+
+		result := tx.Model(&entities.Copy{}).
+			Where("id = ? AND status = ?", loan.CopyId, fromStatus).
+			Update("status", dtos.CopyStatusOnLoan)
+		if result.Error != nil {
+			log.Error("Error on take copy to check out: ", result.Error.Error())
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		if holdId != 0 {
+			result := tx.Model(&entities.Hold{}).
+				Where("id = ? AND status = ?", holdId, dtos.HoldStatusReady).
+				Update("status", dtos.HoldStatusFulfilled)
+			if result.Error != nil {
+				log.Error("Error on fulfill hold on check out: ", result.Error.Error())
+				return result.Error
+			}
+			if result.RowsAffected == 0 {
+				return gorm.ErrRecordNotFound
+			}
+		}
+
+		if result := tx.Omit("Copy", "Patron").Create(&loan); result.Error != nil {
+			log.Error("Error on create loan: ", result.Error.Error())
+			return result.Error
+		}
+
+		return nil
+	})
+	if err != nil {
+		return entities.Loan{}, err
+	}
+
+	return loan, nil
+}
+
+// CheckIn closes the loan, charges what its fine grew to beyond the fines already charged and puts the
+// copy back on the shelf in one transaction, returning gorm.ErrRecordNotFound when the loan was already
+// returned. A fine with a zero amount charges nothing
+func (l *LoanRepository) CheckIn(loan entities.Loan, fine entities.LedgerEntry) error {
+	return l.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&entities.Loan{}).
+			Where("id = ? AND returned_at IS NULL", loan.Id).
+			Update("returned_at", loan.ReturnedAt)
+		if result.Error != nil {
+			log.Error("Error on close loan: ", result.Error.Error())
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		// Closing the loan locked its row, so a fine assessed at the same time waits for this one
+		if fine.AmountCents > 0 {
+			var chargedBefore int64
+			if result := tx.Model(&entities.LedgerEntry{}).Select("COALESCE(SUM(amount_cents), 0)").
+				Where("loan_id = ? AND kind = ?", loan.Id, dtos.LedgerKindFine).Scan(&chargedBefore); result.Error != nil {
+				log.Error("Error on sum fines of loan: ", result.Error.Error())
+				return result.Error
+			}
+
+			if fine.AmountCents > chargedBefore {
+				fine.AmountCents -= chargedBefore
+				if result := tx.Omit("Patron", "Loan").Create(&fine); result.Error != nil {
+					log.Error("Error on create fine on check in: ", result.Error.Error())
+					return result.Error
+				}
+			}
+		}
+
+		if result := tx.Model(&entities.Copy{}).
+			Where("id = ? AND status = ?", loan.CopyId, dtos.CopyStatusOnLoan).
+			Update("status", dtos.CopyStatusAvailable); result.Error != nil {
+			log.Error("Error on put copy back after check in: ", result.Error.Error())
+			return result.Error
+		}
+
+		return nil
+	})
+}
+
+// Renew saves the new due date and renewal count of an open loan, returning gorm.ErrRecordNotFound
+// when the loan was returned or renewed since it was read
+func (l *LoanRepository) Renew(loan entities.Loan) error {
+
+	result := l.db.Model(&entities.Loan{}).
+		Where("id = ? AND returned_at IS NULL AND renewals = ?", loan.Id, loan.Renewals-1).
+		Updates(map[string]interface{}{"due_at": loan.DueAt, "renewals": loan.Renewals})
+	if result.Error != nil {
+		log.Error("Error on renew loan: ", result.Error.Error())
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+func (l *LoanRepository) GetLoan(id int) (entities.Loan, error) {
+	var loan entities.Loan
+
+	if result := l.db.Preload("Copy").First(&loan, id); result.Error != nil {
+		log.Error("Error on get loan: ", result.Error.Error())
+		return loan, result.Error
+	}
+
+	return loan, nil
+}
+
+// GetPatronLoans returns the loans of the patron with the status of the filter, the ones due first
+// on top. Active loans include the overdue ones, which were due before today
+func (l *LoanRepository) GetPatronLoans(patronId int, filter dtos.GetLoansFilter, today time.Time) ([]entities.Loan, error) {
+
+	loans := make([]entities.Loan, 0)
+	toExec := l.db.Where("patron_id = ?", patronId)
+
+	switch filter.Status {
+	case dtos.LoanStatusOverdue:
+		toExec = toExec.Where("returned_at IS NULL AND due_at < ?", today)
+	case dtos.LoanStatusReturned:
+		toExec = toExec.Where("returned_at IS NOT NULL")
+	default:
+		toExec = toExec.Where("returned_at IS NULL")
+	}
+
+	toExec = toExec.Offset((filter.Page - 1) * filter.Limit).Limit(filter.Limit).Order("due_at asc, id asc")
+
+	if result := toExec.Preload("Copy").Find(&loans); result.Error != nil {
+		log.Error("Error on get loans of patron: ", result.Error.Error())
+		return nil, result.Error
+	}
+
+	return loans, nil
+}
+
+// GetOpenLoansDueBefore returns a batch of the loans not returned and due before the date, with their
+// copy and patron, after the loan ID given in the order of the IDs
+func (l *LoanRepository) GetOpenLoansDueBefore(date time.Time, afterId, limit int) ([]entities.Loan, error) {
+
+	loans := make([]entities.Loan, 0)
+	if result := l.db.Preload("Copy").Preload("Patron").
+		Where("returned_at IS NULL AND due_at < ? AND id > ?", date, afterId).
+		Order("id asc").Limit(limit).Find(&loans); result.Error != nil {
+		log.Error("Error on get open loans due: ", result.Error.Error())
+		return nil, result.Error
+	}
+
+	return loans, nil
+}
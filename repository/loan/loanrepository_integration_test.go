@@ -0,0 +1,82 @@
+//go:build integration
+
+package repository
+
+import (
+	"github/brunojoenk/golang-test/models/dtos"
+	"github/brunojoenk/golang-test/models/entities"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/logger"
+)
+
+// Two checkouts of the same copy run at the same time against a real database. The conditional
+// update takes the copy for one of them only, so one checkout creates the loan and the other gets
+// gorm.ErrRecordNotFound, and idx_loan_open_copy leaves the copy with a single open loan either way
+func TestCheckOutConcurrently(t *testing.T) {
+	dataSourceName := os.Getenv("TEST_DATABASE_URL")
+	if dataSourceName == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+	db, err := gorm.Open(postgres.Open(dataSourceName), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&entities.Author{}, &entities.Publisher{}, &entities.Genre{}, &entities.Series{}, &entities.Tag{}, &entities.Work{}, &entities.Book{}, &entities.AuthorBook{}, &entities.Copy{}, &entities.Patron{}, &entities.Loan{}))
+
+	suffix := time.Now().Format("20060102150405.000000000")
+	book := entities.Book{Name: "Concurrent checkout " + suffix}
+	require.NoError(t, db.Omit(clause.Associations).Create(&book).Error)
+	bookCopy := entities.Copy{BookId: book.Id, Barcode: "concurrent-" + suffix, Status: dtos.CopyStatusAvailable}
+	require.NoError(t, db.Create(&bookCopy).Error)
+	patrons := []entities.Patron{
+		{Name: "First", Email: "first-" + suffix + "@example.com"},
+		{Name: "Second", Email: "second-" + suffix + "@example.com"},
+	}
+	require.NoError(t, db.Create(&patrons).Error)
+	t.Cleanup(func() {
+		db.Where("copy_id = ?", bookCopy.Id).Delete(&entities.Loan{})
+		db.Delete(&bookCopy)
+		db.Delete(&patrons)
+		db.Delete(&book)
+	})
+
+	repository := NewLoanRepository(db)
+	var (
+		wg    sync.WaitGroup
+		start = make(chan struct{})
+		errs  = make([]error, len(patrons))
+	)
+	for i := range patrons {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			<-start
+			now := time.Now()
+			_, errs[i] = repository.CheckOut(entities.Loan{CopyId: bookCopy.Id, PatronId: patrons[i].Id, LoanedAt: now, DueAt: now.AddDate(0, 0, 21)}, 0)
+		}(i)
+	}
+	close(start)
+	wg.Wait()
+
+	succeeded := 0
+	for _, err := range errs {
+		if err == nil {
+			succeeded++
+		} else {
+			require.ErrorIs(t, err, gorm.ErrRecordNotFound)
+		}
+	}
+	require.Equal(t, 1, succeeded)
+
+	var openLoans int64
+	require.NoError(t, db.Model(&entities.Loan{}).Where("copy_id = ? AND returned_at IS NULL", bookCopy.Id).Count(&openLoans).Error)
+	require.Equal(t, int64(1), openLoans)
+}
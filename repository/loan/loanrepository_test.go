@@ -0,0 +1,347 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"github/brunojoenk/golang-test/models/dtos"
+	"github/brunojoenk/golang-test/models/entities"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+type Suite struct {
+	suite.Suite
+	DB   *gorm.DB
+	mock sqlmock.Sqlmock
+
+	repository *LoanRepository
+}
+
+func (s *Suite) AfterTest(_, _ string) {
+	require.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func TestInit(t *testing.T) {
+	suite.Run(t, new(Suite))
+}
+
+func (s *Suite) SetupSuite() {
+	var (
+		db  *sql.DB
+		err error
+	)
+
+	db, s.mock, err = sqlmock.New()
+	require.NoError(s.T(), err)
+
+	dialector := postgres.New(postgres.Config{
+		DSN:                  "sqlmock_db_0",
+		DriverName:           "postgres",
+		Conn:                 db,
+		PreferSimpleProtocol: true,
+	})
+	s.DB, err = gorm.Open(dialector, &gorm.Config{})
+	require.NoError(s.T(), err)
+
+	s.repository = &LoanRepository{db: s.DB}
+}
+
+func (s *Suite) SetupTest() {
+	s.mock.MatchExpectationsInOrder(true)
+}
+
+var (
+	loanedAt = time.Date(2022, 10, 3, 14, 30, 0, 0, time.UTC)
+	dueAt    = time.Date(2022, 10, 24, 0, 0, 0, 0, time.UTC)
+)
+
+func (s *Suite) Test_repository_Check_Out() {
+	s.mock.ExpectBegin()
+
+	s.mock.ExpectExec(regexp.QuoteMeta(
+		`UPDATE "copies" SET "status"=$1 WHERE id = $2 AND status = $3`)).
+		WithArgs(dtos.CopyStatusOnLoan, 5, dtos.CopyStatusAvailable).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`INSERT INTO "loans" ("copy_id","patron_id","loaned_at","due_at","returned_at","renewals") VALUES ($1,$2,$3,$4,$5,$6) RETURNING "id"`)).
+		WithArgs(5, 3, loanedAt, dueAt, nil, 0).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).
+			AddRow(9))
+
+	s.mock.ExpectCommit()
+
+	loan, err := s.repository.CheckOut(entities.Loan{CopyId: 5, PatronId: 3, LoanedAt: loanedAt, DueAt: dueAt}, 0)
+
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), 9, loan.Id)
+}
+
+func (s *Suite) Test_repository_Check_Out_On_Hold() {
+	s.mock.ExpectBegin()
+
+	s.mock.ExpectExec(regexp.QuoteMeta(
+		`UPDATE "copies" SET "status"=$1 WHERE id = $2 AND status = $3`)).
+		WithArgs(dtos.CopyStatusOnLoan, 5, dtos.CopyStatusOnHold).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	s.mock.ExpectExec(regexp.QuoteMeta(
+		`UPDATE "holds" SET "status"=$1 WHERE id = $2 AND status = $3`)).
+		WithArgs(dtos.HoldStatusFulfilled, 4, dtos.HoldStatusReady).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`INSERT INTO "loans" ("copy_id","patron_id","loaned_at","due_at","returned_at","renewals") VALUES ($1,$2,$3,$4,$5,$6) RETURNING "id"`)).
+		WithArgs(5, 3, loanedAt, dueAt, nil, 0).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).
+			AddRow(9))
+
+	s.mock.ExpectCommit()
+
+	loan, err := s.repository.CheckOut(entities.Loan{CopyId: 5, PatronId: 3, LoanedAt: loanedAt, DueAt: dueAt}, 4)
+
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), 9, loan.Id)
+}
+
+func (s *Suite) Test_repository_Check_Out_Hold_Expired() {
+	s.mock.ExpectBegin()
+
+	s.mock.ExpectExec(regexp.QuoteMeta(
+		`UPDATE "copies" SET "status"=$1 WHERE id = $2 AND status = $3`)).
+		WithArgs(dtos.CopyStatusOnLoan, 5, dtos.CopyStatusOnHold).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	s.mock.ExpectExec(regexp.QuoteMeta(
+		`UPDATE "holds" SET "status"=$1 WHERE id = $2 AND status = $3`)).
+		WithArgs(dtos.HoldStatusFulfilled, 4, dtos.HoldStatusReady).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	s.mock.ExpectRollback()
+
+	_, err := s.repository.CheckOut(entities.Loan{CopyId: 5, PatronId: 3, LoanedAt: loanedAt, DueAt: dueAt}, 4)
+
+	require.ErrorIs(s.T(), err, gorm.ErrRecordNotFound)
+}
+
+func (s *Suite) Test_repository_Check_Out_Copy_Not_Available() {
+	s.mock.ExpectBegin()
+
+	s.mock.ExpectExec(regexp.QuoteMeta(
+		`UPDATE "copies" SET "status"=$1 WHERE id = $2 AND status = $3`)).
+		WithArgs(dtos.CopyStatusOnLoan, 5, dtos.CopyStatusAvailable).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	s.mock.ExpectRollback()
+
+	_, err := s.repository.CheckOut(entities.Loan{CopyId: 5, PatronId: 3, LoanedAt: loanedAt, DueAt: dueAt}, 0)
+
+	require.ErrorIs(s.T(), err, gorm.ErrRecordNotFound)
+}
+
+func (s *Suite) Test_repository_Check_Out_Error() {
+	s.mock.ExpectBegin()
+
+	s.mock.ExpectExec(regexp.QuoteMeta(
+		`UPDATE "copies" SET "status"=$1 WHERE id = $2 AND status = $3`)).
+		WithArgs(dtos.CopyStatusOnLoan, 5, dtos.CopyStatusAvailable).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`INSERT INTO "loans"`)).
+		WillReturnError(context.Canceled)
+
+	s.mock.ExpectRollback()
+
+	_, err := s.repository.CheckOut(entities.Loan{CopyId: 5, PatronId: 3, LoanedAt: loanedAt, DueAt: dueAt}, 0)
+
+	require.ErrorIs(s.T(), err, context.Canceled)
+}
+
+func (s *Suite) Test_repository_Check_In() {
+	returnedAt := time.Date(2022, 10, 20, 9, 0, 0, 0, time.UTC)
+
+	s.mock.ExpectBegin()
+
+	s.mock.ExpectExec(regexp.QuoteMeta(
+		`UPDATE "loans" SET "returned_at"=$1 WHERE id = $2 AND returned_at IS NULL`)).
+		WithArgs(returnedAt, 9).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	s.mock.ExpectExec(regexp.QuoteMeta(
+		`UPDATE "copies" SET "status"=$1 WHERE id = $2 AND status = $3`)).
+		WithArgs(dtos.CopyStatusAvailable, 5, dtos.CopyStatusOnLoan).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	s.mock.ExpectCommit()
+
+	err := s.repository.CheckIn(entities.Loan{Id: 9, CopyId: 5, ReturnedAt: &returnedAt}, entities.LedgerEntry{})
+
+	require.NoError(s.T(), err)
+}
+
+func (s *Suite) Test_repository_Check_In_With_Fine() {
+	returnedAt := time.Date(2022, 10, 20, 9, 0, 0, 0, time.UTC)
+	loanId := 9
+
+	s.mock.ExpectBegin()
+
+	s.mock.ExpectExec(regexp.QuoteMeta(
+		`UPDATE "loans" SET "returned_at"=$1 WHERE id = $2 AND returned_at IS NULL`)).
+		WithArgs(returnedAt, 9).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT COALESCE(SUM(amount_cents), 0) FROM "ledger_entries" WHERE loan_id = $1 AND kind = $2`)).
+		WithArgs(9, dtos.LedgerKindFine).
+		WillReturnRows(sqlmock.NewRows([]string{"coalesce"}).AddRow(50))
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`INSERT INTO "ledger_entries" ("patron_id","loan_id","kind","amount_cents","note","created_at") VALUES ($1,$2,$3,$4,$5,$6) RETURNING "id"`)).
+		WithArgs(3, 9, dtos.LedgerKindFine, 25, "Overdue fine", sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(12))
+
+	s.mock.ExpectExec(regexp.QuoteMeta(
+		`UPDATE "copies" SET "status"=$1 WHERE id = $2 AND status = $3`)).
+		WithArgs(dtos.CopyStatusAvailable, 5, dtos.CopyStatusOnLoan).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	s.mock.ExpectCommit()
+
+	err := s.repository.CheckIn(entities.Loan{Id: 9, CopyId: 5, PatronId: 3, ReturnedAt: &returnedAt},
+		entities.LedgerEntry{PatronId: 3, LoanId: &loanId, Kind: dtos.LedgerKindFine, AmountCents: 75, Note: "Overdue fine"})
+
+	require.NoError(s.T(), err)
+}
+
+func (s *Suite) Test_repository_Check_In_Fine_Error() {
+	returnedAt := time.Date(2022, 10, 20, 9, 0, 0, 0, time.UTC)
+	loanId := 9
+
+	s.mock.ExpectBegin()
+
+	s.mock.ExpectExec(regexp.QuoteMeta(
+		`UPDATE "loans" SET "returned_at"=$1 WHERE id = $2 AND returned_at IS NULL`)).
+		WithArgs(returnedAt, 9).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT COALESCE(SUM(amount_cents), 0) FROM "ledger_entries" WHERE loan_id = $1 AND kind = $2`)).
+		WithArgs(9, dtos.LedgerKindFine).
+		WillReturnError(context.Canceled)
+
+	s.mock.ExpectRollback()
+
+	err := s.repository.CheckIn(entities.Loan{Id: 9, CopyId: 5, PatronId: 3, ReturnedAt: &returnedAt},
+		entities.LedgerEntry{PatronId: 3, LoanId: &loanId, Kind: dtos.LedgerKindFine, AmountCents: 75, Note: "Overdue fine"})
+
+	require.ErrorIs(s.T(), err, context.Canceled)
+}
+
+func (s *Suite) Test_repository_Check_In_Already_Returned() {
+	returnedAt := time.Date(2022, 10, 20, 9, 0, 0, 0, time.UTC)
+
+	s.mock.ExpectBegin()
+
+	s.mock.ExpectExec(regexp.QuoteMeta(
+		`UPDATE "loans" SET "returned_at"=$1 WHERE id = $2 AND returned_at IS NULL`)).
+		WithArgs(returnedAt, 9).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	s.mock.ExpectRollback()
+
+	err := s.repository.CheckIn(entities.Loan{Id: 9, CopyId: 5, ReturnedAt: &returnedAt}, entities.LedgerEntry{})
+
+	require.ErrorIs(s.T(), err, gorm.ErrRecordNotFound)
+}
+
+func (s *Suite) Test_repository_Renew() {
+	s.mock.ExpectBegin()
+
+	s.mock.ExpectExec(regexp.QuoteMeta(
+		`UPDATE "loans" SET "due_at"=$1,"renewals"=$2 WHERE id = $3 AND returned_at IS NULL AND renewals = $4`)).
+		WithArgs(dueAt, 1, 9, 0).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	s.mock.ExpectCommit()
+
+	err := s.repository.Renew(entities.Loan{Id: 9, DueAt: dueAt, Renewals: 1})
+
+	require.NoError(s.T(), err)
+}
+
+func (s *Suite) Test_repository_Renew_Changed() {
+	s.mock.ExpectBegin()
+
+	s.mock.ExpectExec(regexp.QuoteMeta(
+		`UPDATE "loans" SET "due_at"=$1,"renewals"=$2 WHERE id = $3 AND returned_at IS NULL AND renewals = $4`)).
+		WithArgs(dueAt, 1, 9, 0).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	s.mock.ExpectCommit()
+
+	err := s.repository.Renew(entities.Loan{Id: 9, DueAt: dueAt, Renewals: 1})
+
+	require.ErrorIs(s.T(), err, gorm.ErrRecordNotFound)
+}
+
+func (s *Suite) Test_repository_Get_Loan() {
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "loans" WHERE "loans"."id" = $1 ORDER BY "loans"."id" LIMIT 1`)).
+		WithArgs(9).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "copy_id", "patron_id", "loaned_at", "due_at"}).
+			AddRow(9, 5, 3, loanedAt, dueAt))
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "copies" WHERE "copies"."id" = $1`)).
+		WithArgs(5).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "book_id", "barcode", "status"}).
+			AddRow(5, 1, "0001", dtos.CopyStatusOnLoan))
+
+	loan, err := s.repository.GetLoan(9)
+
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), entities.Loan{Id: 9, CopyId: 5, PatronId: 3, LoanedAt: loanedAt, DueAt: dueAt,
+		Copy: entities.Copy{Id: 5, BookId: 1, Barcode: "0001", Status: dtos.CopyStatusOnLoan}}, loan)
+}
+
+func (s *Suite) Test_repository_Get_Patron_Loans_Overdue() {
+	today := time.Date(2022, 10, 25, 0, 0, 0, 0, time.UTC)
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "loans" WHERE patron_id = $1 AND (returned_at IS NULL AND due_at < $2) ORDER BY due_at asc, id asc LIMIT 10`)).
+		WithArgs(3, today).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "copy_id", "patron_id", "due_at"}).
+			AddRow(9, 5, 3, dueAt))
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "copies" WHERE "copies"."id" = $1`)).
+		WithArgs(5).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "book_id", "barcode"}).
+			AddRow(5, 1, "0001"))
+
+	loans, err := s.repository.GetPatronLoans(3, dtos.GetLoansFilter{Status: dtos.LoanStatusOverdue, Pagination: dtos.Pagination{Page: 1, Limit: 10}}, today)
+
+	require.NoError(s.T(), err)
+	require.Len(s.T(), loans, 1)
+	require.Equal(s.T(), "0001", loans[0].Copy.Barcode)
+}
+
+func (s *Suite) Test_repository_Get_Patron_Loans_Active() {
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "loans" WHERE patron_id = $1 AND returned_at IS NULL ORDER BY due_at asc, id asc LIMIT 10`)).
+		WithArgs(3).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	loans, err := s.repository.GetPatronLoans(3, dtos.GetLoansFilter{Pagination: dtos.Pagination{Page: 1, Limit: 10}}, time.Now())
+
+	require.NoError(s.T(), err)
+	require.Empty(s.T(), loans)
+}
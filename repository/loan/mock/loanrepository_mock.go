@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"github/brunojoenk/golang-test/models/dtos"
+	"github/brunojoenk/golang-test/models/entities"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type LoanRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *LoanRepositoryMock) CheckOut(loan entities.Loan, holdId int) (entities.Loan, error) {
+	args := m.Called(loan, holdId)
+	return args.Get(0).(entities.Loan), args.Error(1)
+}
+
+func (m *LoanRepositoryMock) CheckIn(loan entities.Loan, fine entities.LedgerEntry) error {
+	args := m.Called(loan, fine)
+	return args.Error(0)
+}
+
+func (m *LoanRepositoryMock) Renew(loan entities.Loan) error {
+	args := m.Called(loan)
+	return args.Error(0)
+}
+
+func (m *LoanRepositoryMock) GetLoan(id int) (entities.Loan, error) {
+	args := m.Called(id)
+	return args.Get(0).(entities.Loan), args.Error(1)
+}
+
+func (m *LoanRepositoryMock) GetPatronLoans(patronId int, filter dtos.GetLoansFilter, today time.Time) ([]entities.Loan, error) {
+	args := m.Called(patronId, filter, today)
+	return args.Get(0).([]entities.Loan), args.Error(1)
+}
+
+func (m *LoanRepositoryMock) GetOpenLoansDueBefore(date time.Time, afterId, limit int) ([]entities.Loan, error) {
+	args := m.Called(date, afterId, limit)
+	return args.Get(0).([]entities.Loan), args.Error(1)
+}
@@ -0,0 +1,38 @@
+package repository
+
+import (
+	"github/brunojoenk/golang-test/models/dtos"
+	"github/brunojoenk/golang-test/models/entities"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type OutboxRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *OutboxRepositoryMock) EnqueueMessage(message entities.OutboxMessage) (bool, error) {
+	args := m.Called(message)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *OutboxRepositoryMock) ClaimDueMessages(now time.Time, limit int, lease time.Duration) ([]entities.OutboxMessage, error) {
+	args := m.Called(now, limit, lease)
+	return args.Get(0).([]entities.OutboxMessage), args.Error(1)
+}
+
+func (m *OutboxRepositoryMock) MarkSent(id int, sentAt time.Time) error {
+	args := m.Called(id, sentAt)
+	return args.Error(0)
+}
+
+func (m *OutboxRepositoryMock) MarkFailed(message entities.OutboxMessage) error {
+	args := m.Called(message)
+	return args.Error(0)
+}
+
+func (m *OutboxRepositoryMock) GetMessages(filter dtos.GetOutboxFilter) ([]entities.OutboxMessage, error) {
+	args := m.Called(filter)
+	return args.Get(0).([]entities.OutboxMessage), args.Error(1)
+}
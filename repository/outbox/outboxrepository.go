@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"github/brunojoenk/golang-test/models/dtos"
+	"github/brunojoenk/golang-test/models/entities"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type IOutboxRepository interface {
+	EnqueueMessage(message entities.OutboxMessage) (bool, error)
+	ClaimDueMessages(now time.Time, limit int, lease time.Duration) ([]entities.OutboxMessage, error)
+	MarkSent(id int, sentAt time.Time) error
+	MarkFailed(message entities.OutboxMessage) error
+	GetMessages(filter dtos.GetOutboxFilter) ([]entities.OutboxMessage, error)
+}
+
+// OutboxRepository Outbox messages Repository
+type OutboxRepository struct {
+	db *gorm.DB
+}
+
+// NewOutboxRepository Repository Constructor
+func NewOutboxRepository(db *gorm.DB) IOutboxRepository {
+	return &OutboxRepository{db: db}
+}
+
+// EnqueueMessage returns false when a message with the same dedup key was queued before
+func (o *OutboxRepository) EnqueueMessage(message entities.OutboxMessage) (bool, error) {
+
+	result := o.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&message)
+	if result.Error != nil {
+		log.Error("Error on enqueue outbox message: ", result.Error.Error())
+		return false, result.Error
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+// ClaimDueMessages returns the pending messages due for delivery, oldest first, and moves their next
+// attempt one lease ahead, so a delivery that dies halfway is retried after the lease and other
+// instances skip the messages meanwhile
+func (o *OutboxRepository) ClaimDueMessages(now time.Time, limit int, lease time.Duration) ([]entities.OutboxMessage, error) {
+
+	messages := make([]entities.OutboxMessage, 0)
+	err := o.db.Transaction(func(tx *gorm.DB) error {
+		if result := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND next_attempt_at <= ?", dtos.OutboxStatusPending, now).
+			Order("id asc").Limit(limit).Find(&messages); result.Error != nil {
+			log.Error("Error on get due outbox messages: ", result.Error.Error())
+			return result.Error
+		}
+		if len(messages) == 0 {
+			return nil
+		}
+
+		ids := make([]int, len(messages))
+		for i, message := range messages {
+			ids[i] = message.Id
+		}
+		if result := tx.Model(&entities.OutboxMessage{}).Where("id IN ?", ids).
+			Update("next_attempt_at", now.Add(lease)); result.Error != nil {
+			log.Error("Error on lease outbox messages: ", result.Error.Error())
+			return result.Error
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return messages, nil
+}
+
+func (o *OutboxRepository) MarkSent(id int, sentAt time.Time) error {
+
+	if result := o.db.Model(&entities.OutboxMessage{}).Where("id = ?", id).
+		Updates(map[string]interface{}{"status": dtos.OutboxStatusSent, "sent_at": sentAt}); result.Error != nil {
+		log.Error("Error on mark outbox message sent: ", result.Error.Error())
+		return result.Error
+	}
+
+	return nil
+}
+
+// MarkFailed saves a failed delivery: the attempts, the error and when to try again, or the failed
+// status when there are no attempts left
+func (o *OutboxRepository) MarkFailed(message entities.OutboxMessage) error {
+
+	if result := o.db.Model(&entities.OutboxMessage{}).Where("id = ?", message.Id).
+		Updates(map[string]interface{}{
+			"status":          message.Status,
+			"attempts":        message.Attempts,
+			"last_error":      message.LastError,
+			"next_attempt_at": message.NextAttemptAt,
+		}); result.Error != nil {
+		log.Error("Error on mark outbox message failed: ", result.Error.Error())
+		return result.Error
+	}
+
+	return nil
+}
+
+// GetMessages returns the messages with the status of the filter, if any, the newest first
+func (o *OutboxRepository) GetMessages(filter dtos.GetOutboxFilter) ([]entities.OutboxMessage, error) {
+
+	messages := make([]entities.OutboxMessage, 0)
+	toExec := o.db
+	if filter.Status != "" {
+		toExec = toExec.Where("status = ?", filter.Status)
+	}
+
+	toExec = toExec.Offset((filter.Page - 1) * filter.Limit).Limit(filter.Limit).Order("id desc")
+
+	if result := toExec.Find(&messages); result.Error != nil {
+		log.Error("Error on get outbox messages: ", result.Error.Error())
+		return nil, result.Error
+	}
+
+	return messages, nil
+}
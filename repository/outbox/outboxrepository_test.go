@@ -0,0 +1,130 @@
+package repository
+
+import (
+	"database/sql"
+	"github/brunojoenk/golang-test/models/dtos"
+	"github/brunojoenk/golang-test/models/entities"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+type Suite struct {
+	suite.Suite
+	DB   *gorm.DB
+	mock sqlmock.Sqlmock
+
+	repository *OutboxRepository
+}
+
+func (s *Suite) AfterTest(_, _ string) {
+	require.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func TestInit(t *testing.T) {
+	suite.Run(t, new(Suite))
+}
+
+func (s *Suite) SetupSuite() {
+	var (
+		db  *sql.DB
+		err error
+	)
+
+	db, s.mock, err = sqlmock.New()
+	require.NoError(s.T(), err)
+
+	dialector := postgres.New(postgres.Config{
+		DSN:                  "sqlmock_db_0",
+		DriverName:           "postgres",
+		Conn:                 db,
+		PreferSimpleProtocol: true,
+	})
+	s.DB, err = gorm.Open(dialector, &gorm.Config{})
+	require.NoError(s.T(), err)
+
+	s.repository = &OutboxRepository{db: s.DB}
+}
+
+var now = time.Date(2022, 10, 3, 14, 30, 0, 0, time.UTC)
+
+func (s *Suite) Test_repository_Enqueue_Message_Duplicate() {
+	s.mock.ExpectBegin()
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`INSERT INTO "outbox_messages" ("kind","recipient","subject","body","dedup_key","status","attempts","last_error","next_attempt_at","created_at","sent_at") VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11) ON CONFLICT DO NOTHING RETURNING "id"`)).
+		WithArgs(dtos.NotificationOverdue, "ana@example.com", "Overdue", "Hello", "overdue:9:2022-10-01", dtos.OutboxStatusPending, 0, "", now, sqlmock.AnyArg(), nil).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	s.mock.ExpectCommit()
+
+	created, err := s.repository.EnqueueMessage(entities.OutboxMessage{
+		Kind:          dtos.NotificationOverdue,
+		Recipient:     "ana@example.com",
+		Subject:       "Overdue",
+		Body:          "Hello",
+		DedupKey:      "overdue:9:2022-10-01",
+		Status:        dtos.OutboxStatusPending,
+		NextAttemptAt: now,
+	})
+
+	require.NoError(s.T(), err)
+	require.False(s.T(), created)
+}
+
+func (s *Suite) Test_repository_Claim_Due_Messages() {
+	s.mock.ExpectBegin()
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "outbox_messages" WHERE status = $1 AND next_attempt_at <= $2 ORDER BY id asc LIMIT 50 FOR UPDATE SKIP LOCKED`)).
+		WithArgs(dtos.OutboxStatusPending, now).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "kind", "status"}).
+			AddRow(1, dtos.NotificationOverdue, dtos.OutboxStatusPending).
+			AddRow(2, dtos.NotificationDueSoon, dtos.OutboxStatusPending))
+
+	s.mock.ExpectExec(regexp.QuoteMeta(
+		`UPDATE "outbox_messages" SET "next_attempt_at"=$1 WHERE id IN ($2,$3)`)).
+		WithArgs(now.Add(5*time.Minute), 1, 2).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	s.mock.ExpectCommit()
+
+	messages, err := s.repository.ClaimDueMessages(now, 50, 5*time.Minute)
+
+	require.NoError(s.T(), err)
+	require.Len(s.T(), messages, 2)
+}
+
+func (s *Suite) Test_repository_Mark_Failed() {
+	s.mock.ExpectBegin()
+
+	s.mock.ExpectExec(regexp.QuoteMeta(
+		`UPDATE "outbox_messages" SET "attempts"=$1,"last_error"=$2,"next_attempt_at"=$3,"status"=$4 WHERE id = $5`)).
+		WithArgs(2, "timeout", now, dtos.OutboxStatusPending, 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	s.mock.ExpectCommit()
+
+	err := s.repository.MarkFailed(entities.OutboxMessage{Id: 1, Status: dtos.OutboxStatusPending, Attempts: 2, LastError: "timeout", NextAttemptAt: now})
+
+	require.NoError(s.T(), err)
+}
+
+func (s *Suite) Test_repository_Get_Messages() {
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "outbox_messages" WHERE status = $1 ORDER BY id desc LIMIT 10 OFFSET 10`)).
+		WithArgs(dtos.OutboxStatusFailed).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "status"}).
+			AddRow(1, dtos.OutboxStatusFailed))
+
+	messages, err := s.repository.GetMessages(dtos.GetOutboxFilter{Status: dtos.OutboxStatusFailed, Pagination: dtos.Pagination{Page: 2, Limit: 10}})
+
+	require.NoError(s.T(), err)
+	require.Len(s.T(), messages, 1)
+}
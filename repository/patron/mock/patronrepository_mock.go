@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"github/brunojoenk/golang-test/models/dtos"
+	"github/brunojoenk/golang-test/models/entities"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type PatronRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *PatronRepositoryMock) CreatePatron(patron entities.Patron) (entities.Patron, error) {
+	args := m.Called(patron)
+	return args.Get(0).(entities.Patron), args.Error(1)
+}
+
+func (m *PatronRepositoryMock) GetPatron(id int) (entities.Patron, error) {
+	args := m.Called(id)
+	return args.Get(0).(entities.Patron), args.Error(1)
+}
+
+func (m *PatronRepositoryMock) GetPatronByEmail(email string) (entities.Patron, error) {
+	args := m.Called(email)
+	return args.Get(0).(entities.Patron), args.Error(1)
+}
+
+func (m *PatronRepositoryMock) GetAllPatrons(filter dtos.GetPatronsFilter) ([]entities.Patron, error) {
+	args := m.Called(filter)
+	return args.Get(0).([]entities.Patron), args.Error(1)
+}
+
+func (m *PatronRepositoryMock) UpdatePatron(patron entities.Patron) (entities.Patron, error) {
+	args := m.Called(patron)
+	return args.Get(0).(entities.Patron), args.Error(1)
+}
+
+func (m *PatronRepositoryMock) DeletePatron(id int) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *PatronRepositoryMock) CountOpenLoans(id int) (int64, error) {
+	args := m.Called(id)
+	return args.Get(0).(int64), args.Error(1)
+}
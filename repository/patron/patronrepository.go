@@ -0,0 +1,138 @@
+package repository
+
+import (
+	"github/brunojoenk/golang-test/models/dtos"
+	"github/brunojoenk/golang-test/models/entities"
+	reviewrepo "github/brunojoenk/golang-test/repository/review"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type IPatronRepository interface {
+	CreatePatron(patron entities.Patron) (entities.Patron, error)
+	GetPatron(id int) (entities.Patron, error)
+	GetPatronByEmail(email string) (entities.Patron, error)
+	GetAllPatrons(filter dtos.GetPatronsFilter) ([]entities.Patron, error)
+	UpdatePatron(patron entities.Patron) (entities.Patron, error)
+	DeletePatron(id int) error
+	CountOpenLoans(id int) (int64, error)
+}
+
+// PatronRepository Patrons Repository
+type PatronRepository struct {
+	db *gorm.DB
+}
+
+// NewPatronRepository Repository Constructor
+func NewPatronRepository(db *gorm.DB) IPatronRepository {
+	return &PatronRepository{db: db}
+}
+
+func (p *PatronRepository) CreatePatron(patron entities.Patron) (entities.Patron, error) {
+
+	if result := p.db.Create(&patron); result.Error != nil {
+		log.Error("Error on create patron: ", result.Error.Error())
+		return entities.Patron{}, result.Error
+	}
+
+	return patron, nil
+}
+
+func (p *PatronRepository) GetPatron(id int) (entities.Patron, error) {
+	var patron entities.Patron
+
+	if result := p.db.First(&patron, id); result.Error != nil {
+		log.Error("Error on get patron: ", result.Error.Error())
+		return patron, result.Error
+	}
+
+	return patron, nil
+}
+
+func (p *PatronRepository) GetPatronByEmail(email string) (entities.Patron, error) {
+	var patron entities.Patron
+
+	if result := p.db.Where("email = ?", email).First(&patron); result.Error != nil {
+		return patron, result.Error
+	}
+
+	return patron, nil
+}
+
+func (p *PatronRepository) GetAllPatrons(filter dtos.GetPatronsFilter) ([]entities.Patron, error) {
+
+	var patrons []entities.Patron
+	toExec := p.db
+
+	if strings.TrimSpace(filter.Name) != "" {
+		toExec = toExec.Where("LOWER(name) LIKE ?", "%"+strings.ToLower(filter.Name)+"%")
+	}
+
+	toExec = toExec.Offset((filter.Page - 1) * filter.Limit).Limit(filter.Limit).Order("name asc")
+
+	if result := toExec.Find(&patrons); result.Error != nil {
+		log.Error("Error on get all patrons: ", result.Error.Error())
+		return nil, result.Error
+	}
+
+	return patrons, nil
+}
+
+func (p *PatronRepository) UpdatePatron(patron entities.Patron) (entities.Patron, error) {
+
+	if result := p.db.Save(&patron); result.Error != nil {
+		log.Error("Error on update patron: ", result.Error.Error())
+		return entities.Patron{}, result.Error
+	}
+
+	return patron, nil
+}
+
+// DeletePatron deletes the patron with their loans history and reviews, recounting the rating of the books they reviewed
+func (p *PatronRepository) DeletePatron(id int) error {
+
+	return p.db.Transaction(func(tx *gorm.DB) error {
+		var bookIds []int
+		if result := tx.Model(&entities.Review{}).Where("patron_id = ?", id).Pluck("book_id", &bookIds); result.Error != nil {
+			log.Error("Error on get books reviewed by patron: ", result.Error.Error())
+			return result.Error
+		}
+
+		if len(bookIds) > 0 {
+			if err := reviewrepo.LockBooks(tx, bookIds); err != nil {
+				log.Error("Error on lock books reviewed by patron: ", err.Error())
+				return err
+			}
+		}
+
+		if result := tx.Delete(&entities.Patron{}, id); result.Error != nil {
+			log.Error("Error on delete patron: ", result.Error.Error())
+			return result.Error
+		}
+
+		if len(bookIds) == 0 {
+			return nil
+		}
+
+		if err := reviewrepo.UpdateRatings(tx, bookIds); err != nil {
+			log.Error("Error on update rating of books reviewed by patron: ", err.Error())
+			return err
+		}
+
+		return nil
+	})
+}
+
+// CountOpenLoans returns how many copies the patron has checked out and not returned yet
+func (p *PatronRepository) CountOpenLoans(id int) (int64, error) {
+	var count int64
+
+	if result := p.db.Model(&entities.Loan{}).Where("patron_id = ? AND returned_at IS NULL", id).Count(&count); result.Error != nil {
+		log.Error("Error on count open loans of patron: ", result.Error.Error())
+		return 0, result.Error
+	}
+
+	return count, nil
+}
@@ -0,0 +1,203 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"github/brunojoenk/golang-test/models/dtos"
+	"github/brunojoenk/golang-test/models/entities"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-test/deep"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+type Suite struct {
+	suite.Suite
+	DB   *gorm.DB
+	mock sqlmock.Sqlmock
+
+	repository *PatronRepository
+}
+
+func (s *Suite) AfterTest(_, _ string) {
+	require.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func TestInit(t *testing.T) {
+	suite.Run(t, new(Suite))
+}
+
+func (s *Suite) SetupSuite() {
+	var (
+		db  *sql.DB
+		err error
+	)
+
+	db, s.mock, err = sqlmock.New()
+	require.NoError(s.T(), err)
+
+	dialector := postgres.New(postgres.Config{
+		DSN:                  "sqlmock_db_0",
+		DriverName:           "postgres",
+		Conn:                 db,
+		PreferSimpleProtocol: true,
+	})
+	s.DB, err = gorm.Open(dialector, &gorm.Config{})
+	require.NoError(s.T(), err)
+
+	s.repository = &PatronRepository{db: s.DB}
+}
+
+func (s *Suite) Test_repository_Create_Patron() {
+	s.mock.ExpectBegin()
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`INSERT INTO "patrons" ("name","email") VALUES ($1,$2) RETURNING "id"`)).
+		WithArgs("Ana Lima", "ana@example.com").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).
+			AddRow(3))
+
+	s.mock.ExpectCommit()
+
+	patron, err := s.repository.CreatePatron(entities.Patron{Name: "Ana Lima", Email: "ana@example.com"})
+
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), 3, patron.Id)
+}
+
+func (s *Suite) Test_repository_Create_Patron_Error() {
+	s.mock.ExpectBegin()
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`INSERT INTO "patrons"`)).
+		WillReturnError(context.Canceled)
+
+	s.mock.ExpectRollback()
+
+	_, err := s.repository.CreatePatron(entities.Patron{Name: "Ana Lima", Email: "ana@example.com"})
+
+	require.Error(s.T(), err)
+}
+
+func (s *Suite) Test_repository_Get_Patron() {
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "patrons" WHERE "patrons"."id" = $1 ORDER BY "patrons"."id" LIMIT 1`)).
+		WithArgs(3).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "email"}).
+			AddRow(3, "Ana Lima", "ana@example.com"))
+
+	res, err := s.repository.GetPatron(3)
+
+	require.NoError(s.T(), err)
+	require.Nil(s.T(), deep.Equal(entities.Patron{Id: 3, Name: "Ana Lima", Email: "ana@example.com"}, res))
+}
+
+func (s *Suite) Test_repository_Get_Patron_By_Email_Not_Found() {
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "patrons" WHERE email = $1 ORDER BY "patrons"."id" LIMIT 1`)).
+		WithArgs("ana@example.com").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	_, err := s.repository.GetPatronByEmail("ana@example.com")
+
+	require.ErrorIs(s.T(), err, gorm.ErrRecordNotFound)
+}
+
+func (s *Suite) Test_repository_Get_All_Patrons() {
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "patrons" WHERE LOWER(name) LIKE $1 ORDER BY name asc LIMIT 10`)).
+		WithArgs("%ana%").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "email"}).
+			AddRow(3, "Ana Lima", "ana@example.com"))
+
+	res, err := s.repository.GetAllPatrons(dtos.GetPatronsFilter{Name: "Ana", Pagination: dtos.Pagination{Page: 1, Limit: 10}})
+
+	require.NoError(s.T(), err)
+	require.Nil(s.T(), deep.Equal([]entities.Patron{{Id: 3, Name: "Ana Lima", Email: "ana@example.com"}}, res))
+}
+
+func (s *Suite) Test_repository_Update_Patron() {
+	s.mock.ExpectBegin()
+
+	s.mock.ExpectExec(regexp.QuoteMeta(
+		`UPDATE "patrons" SET "name"=$1,"email"=$2 WHERE "id" = $3`)).
+		WithArgs("Ana Lima", "ana.lima@example.com", 3).
+		WillReturnResult(sqlmock.NewResult(3, 1))
+
+	s.mock.ExpectCommit()
+
+	_, err := s.repository.UpdatePatron(entities.Patron{Id: 3, Name: "Ana Lima", Email: "ana.lima@example.com"})
+
+	require.NoError(s.T(), err)
+}
+
+func (s *Suite) Test_repository_Delete_Patron() {
+	s.mock.ExpectBegin()
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT "book_id" FROM "reviews" WHERE patron_id = $1`)).
+		WithArgs(3).
+		WillReturnRows(sqlmock.NewRows([]string{"book_id"}))
+
+	s.mock.ExpectExec(regexp.QuoteMeta(
+		`DELETE FROM "patrons" WHERE "patrons"."id" = $1`)).
+		WithArgs(3).
+		WillReturnResult(sqlmock.NewResult(3, 1))
+
+	s.mock.ExpectCommit()
+
+	err := s.repository.DeletePatron(3)
+
+	require.NoError(s.T(), err)
+}
+
+func (s *Suite) Test_repository_Delete_Patron_With_Reviews() {
+	s.mock.ExpectBegin()
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT "book_id" FROM "reviews" WHERE patron_id = $1`)).
+		WithArgs(3).
+		WillReturnRows(sqlmock.NewRows([]string{"book_id"}).
+			AddRow(1).
+			AddRow(7))
+
+	s.mock.ExpectExec(regexp.QuoteMeta(
+		`SELECT id FROM books WHERE id IN ($1,$2) ORDER BY id FOR UPDATE`)).
+		WithArgs(1, 7).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	s.mock.ExpectExec(regexp.QuoteMeta(
+		`DELETE FROM "patrons" WHERE "patrons"."id" = $1`)).
+		WithArgs(3).
+		WillReturnResult(sqlmock.NewResult(3, 1))
+
+	s.mock.ExpectExec(regexp.QuoteMeta(
+		`UPDATE books SET rating_count = (SELECT COUNT(*) FROM reviews WHERE reviews.book_id = books.id), `+
+			`rating_average = COALESCE((SELECT ROUND(AVG(rating), 2) FROM reviews WHERE reviews.book_id = books.id), 0) WHERE books.id IN ($1,$2)`)).
+		WithArgs(1, 7).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	s.mock.ExpectCommit()
+
+	err := s.repository.DeletePatron(3)
+
+	require.NoError(s.T(), err)
+}
+
+func (s *Suite) Test_repository_Count_Open_Loans() {
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT count(*) FROM "loans" WHERE patron_id = $1 AND returned_at IS NULL`)).
+		WithArgs(3).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).
+			AddRow(2))
+
+	count, err := s.repository.CountOpenLoans(3)
+
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), int64(2), count)
+}
@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"github/brunojoenk/golang-test/models/dtos"
+	"github/brunojoenk/golang-test/models/entities"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type PublisherRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *PublisherRepositoryMock) CreatePublisher(publisher entities.Publisher) (entities.Publisher, error) {
+	args := m.Called(publisher)
+	return args.Get(0).(entities.Publisher), args.Error(1)
+}
+
+func (m *PublisherRepositoryMock) GetPublisher(id int) (entities.Publisher, error) {
+	args := m.Called(id)
+	return args.Get(0).(entities.Publisher), args.Error(1)
+}
+
+func (m *PublisherRepositoryMock) GetPublisherByNormalizedName(normalizedName string) (entities.Publisher, error) {
+	args := m.Called(normalizedName)
+	return args.Get(0).(entities.Publisher), args.Error(1)
+}
+
+func (m *PublisherRepositoryMock) GetAllPublishers(filter dtos.GetPublishersFilter) ([]entities.Publisher, error) {
+	args := m.Called(filter)
+	return args.Get(0).([]entities.Publisher), args.Error(1)
+}
+
+func (m *PublisherRepositoryMock) UpdatePublisher(publisher entities.Publisher) (entities.Publisher, error) {
+	args := m.Called(publisher)
+	return args.Get(0).(entities.Publisher), args.Error(1)
+}
+
+func (m *PublisherRepositoryMock) DeletePublisher(id int) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *PublisherRepositoryMock) CountBooks(id int) (int64, error) {
+	args := m.Called(id)
+	return args.Get(0).(int64), args.Error(1)
+}
@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"github/brunojoenk/golang-test/models/dtos"
+	"github/brunojoenk/golang-test/models/entities"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type IPublisherRepository interface {
+	CreatePublisher(publisher entities.Publisher) (entities.Publisher, error)
+	GetPublisher(id int) (entities.Publisher, error)
+	GetPublisherByNormalizedName(normalizedName string) (entities.Publisher, error)
+	GetAllPublishers(filter dtos.GetPublishersFilter) ([]entities.Publisher, error)
+	UpdatePublisher(publisher entities.Publisher) (entities.Publisher, error)
+	DeletePublisher(id int) error
+	CountBooks(id int) (int64, error)
+}
+
+// PublisherRepository Publishers Repository
+type PublisherRepository struct {
+	db *gorm.DB
+}
+
+// NewPublisherRepository Repository Constructor
+func NewPublisherRepository(db *gorm.DB) IPublisherRepository {
+	return &PublisherRepository{db: db}
+}
+
+func (p *PublisherRepository) CreatePublisher(publisher entities.Publisher) (entities.Publisher, error) {
+
+	if result := p.db.Create(&publisher); result.Error != nil {
+		log.Error("Error on create publisher: ", result.Error.Error())
+		return entities.Publisher{}, result.Error
+	}
+
+	return publisher, nil
+}
+
+func (p *PublisherRepository) GetPublisher(id int) (entities.Publisher, error) {
+	var publisher entities.Publisher
+
+	if result := p.db.First(&publisher, id); result.Error != nil {
+		log.Error("Error on get publisher: ", result.Error.Error())
+		return publisher, result.Error
+	}
+
+	return publisher, nil
+}
+
+func (p *PublisherRepository) GetPublisherByNormalizedName(normalizedName string) (entities.Publisher, error) {
+	var publisher entities.Publisher
+
+	if result := p.db.Where("normalized_name = ?", normalizedName).First(&publisher); result.Error != nil {
+		return publisher, result.Error
+	}
+
+	return publisher, nil
+}
+
+func (p *PublisherRepository) GetAllPublishers(filter dtos.GetPublishersFilter) ([]entities.Publisher, error) {
+
+	var publishers []entities.Publisher
+	toExec := p.db
+
+	if strings.TrimSpace(filter.Name) != "" {
+		toExec = toExec.Where("LOWER(name) LIKE ?", "%"+strings.ToLower(filter.Name)+"%")
+	}
+
+	if strings.TrimSpace(filter.Country) != "" {
+		toExec = toExec.Where("country = ?", filter.Country)
+	}
+
+	toExec = toExec.Offset((filter.Page - 1) * filter.Limit).Limit(filter.Limit).Order("name asc")
+
+	if result := toExec.Find(&publishers); result.Error != nil {
+		log.Error("Error on get all publishers: ", result.Error.Error())
+		return nil, result.Error
+	}
+
+	return publishers, nil
+}
+
+func (p *PublisherRepository) UpdatePublisher(publisher entities.Publisher) (entities.Publisher, error) {
+
+	if result := p.db.Save(&publisher); result.Error != nil {
+		log.Error("Error on update publisher: ", result.Error.Error())
+		return entities.Publisher{}, result.Error
+	}
+
+	return publisher, nil
+}
+
+func (p *PublisherRepository) DeletePublisher(id int) error {
+
+	if result := p.db.Delete(&entities.Publisher{}, id); result.Error != nil {
+		log.Error("Error on delete publisher: ", result.Error.Error())
+		return result.Error
+	}
+
+	return nil
+}
+
+// CountBooks returns how many books are published by the publisher
+func (p *PublisherRepository) CountBooks(id int) (int64, error) {
+	var count int64
+
+	if result := p.db.Model(&entities.Book{}).Where("publisher_id = ?", id).Count(&count); result.Error != nil {
+		log.Error("Error on count books of publisher: ", result.Error.Error())
+		return 0, result.Error
+	}
+
+	return count, nil
+}
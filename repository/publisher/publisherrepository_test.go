@@ -0,0 +1,197 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"github/brunojoenk/golang-test/models/dtos"
+	"github/brunojoenk/golang-test/models/entities"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-test/deep"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+type Suite struct {
+	suite.Suite
+	DB   *gorm.DB
+	mock sqlmock.Sqlmock
+
+	repository *PublisherRepository
+}
+
+func (s *Suite) AfterTest(_, _ string) {
+	require.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func TestInit(t *testing.T) {
+	suite.Run(t, new(Suite))
+}
+
+func (s *Suite) SetupSuite() {
+	var (
+		db  *sql.DB
+		err error
+	)
+
+	db, s.mock, err = sqlmock.New()
+	require.NoError(s.T(), err)
+
+	dialector := postgres.New(postgres.Config{
+		DSN:                  "sqlmock_db_0",
+		DriverName:           "postgres",
+		Conn:                 db,
+		PreferSimpleProtocol: true,
+	})
+	s.DB, err = gorm.Open(dialector, &gorm.Config{})
+	require.NoError(s.T(), err)
+
+	s.repository = &PublisherRepository{db: s.DB}
+}
+
+func (s *Suite) Test_repository_Create_Publisher() {
+	var (
+		id      = 1
+		name    = "Allen & Unwin"
+		country = "GB"
+	)
+
+	s.mock.ExpectBegin()
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`INSERT INTO "publishers" ("name","normalized_name","country","website") VALUES ($1,$2,$3,$4) RETURNING "id"`)).
+		WithArgs(name, "allen unwin", country, "").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).
+			AddRow(id))
+
+	s.mock.ExpectCommit()
+
+	publisher, err := s.repository.CreatePublisher(entities.Publisher{Name: name, NormalizedName: "allen unwin", Country: country})
+
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), id, publisher.Id)
+}
+
+func (s *Suite) Test_repository_Create_Publisher_Error() {
+	s.mock.ExpectBegin()
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`INSERT INTO "publishers"`)).
+		WillReturnError(context.Canceled)
+
+	s.mock.ExpectRollback()
+
+	_, err := s.repository.CreatePublisher(entities.Publisher{Name: "Allen & Unwin"})
+
+	require.Error(s.T(), err)
+}
+
+func (s *Suite) Test_repository_Get_Publisher() {
+	var (
+		id   = 1
+		name = "Allen & Unwin"
+	)
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "publishers" WHERE "publishers"."id" = $1 ORDER BY "publishers"."id" LIMIT 1`)).
+		WithArgs(id).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).
+			AddRow(id, name))
+
+	res, err := s.repository.GetPublisher(id)
+
+	require.NoError(s.T(), err)
+	require.Nil(s.T(), deep.Equal(entities.Publisher{Id: id, Name: name}, res))
+}
+
+func (s *Suite) Test_repository_Get_Publisher_By_Normalized_Name_Not_Found() {
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "publishers" WHERE normalized_name = $1 ORDER BY "publishers"."id" LIMIT 1`)).
+		WithArgs("allen unwin").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	_, err := s.repository.GetPublisherByNormalizedName("allen unwin")
+
+	require.ErrorIs(s.T(), err, gorm.ErrRecordNotFound)
+}
+
+func (s *Suite) Test_repository_Get_All_Publishers() {
+	var (
+		id   = 1
+		name = "Allen & Unwin"
+	)
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "publishers" WHERE LOWER(name) LIKE $1 AND country = $2 ORDER BY name asc LIMIT 10`)).
+		WithArgs("%allen%", "GB").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "country"}).
+			AddRow(id, name, "GB"))
+
+	res, err := s.repository.GetAllPublishers(dtos.GetPublishersFilter{Name: "Allen", Country: "GB", Pagination: dtos.Pagination{Page: 1, Limit: 10}})
+
+	require.NoError(s.T(), err)
+	require.Nil(s.T(), deep.Equal([]entities.Publisher{{Id: id, Name: name, Country: "GB"}}, res))
+}
+
+func (s *Suite) Test_repository_Update_Publisher() {
+	var (
+		id   = 1
+		name = "HarperCollins"
+	)
+
+	s.mock.ExpectBegin()
+
+	s.mock.ExpectExec(regexp.QuoteMeta(
+		`UPDATE "publishers" SET "name"=$1,"normalized_name"=$2,"country"=$3,"website"=$4 WHERE "id" = $5`)).
+		WithArgs(name, "harpercollins", "", "", id).
+		WillReturnResult(sqlmock.NewResult(int64(id), 1))
+
+	s.mock.ExpectCommit()
+
+	_, err := s.repository.UpdatePublisher(entities.Publisher{Id: id, Name: name, NormalizedName: "harpercollins"})
+
+	require.NoError(s.T(), err)
+}
+
+func (s *Suite) Test_repository_Delete_Publisher() {
+	s.mock.ExpectBegin()
+
+	s.mock.ExpectExec(regexp.QuoteMeta(
+		`DELETE FROM "publishers" WHERE "publishers"."id" = $1`)).
+		WithArgs(1).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	s.mock.ExpectCommit()
+
+	err := s.repository.DeletePublisher(1)
+
+	require.NoError(s.T(), err)
+}
+
+func (s *Suite) Test_repository_Count_Books() {
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT count(*) FROM "books" WHERE publisher_id = $1`)).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).
+			AddRow(3))
+
+	count, err := s.repository.CountBooks(1)
+
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), int64(3), count)
+}
+
+func (s *Suite) Test_repository_Count_Books_Error() {
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT count(*) FROM "books" WHERE publisher_id = $1`)).
+		WithArgs(1).
+		WillReturnError(context.Canceled)
+
+	_, err := s.repository.CountBooks(1)
+
+	require.Error(s.T(), err)
+}
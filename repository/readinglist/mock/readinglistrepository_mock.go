@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"github/brunojoenk/golang-test/models/dtos"
+	"github/brunojoenk/golang-test/models/entities"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type ReadingListRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *ReadingListRepositoryMock) CreateList(list entities.ReadingList) (entities.ReadingList, error) {
+	args := m.Called(list)
+	return args.Get(0).(entities.ReadingList), args.Error(1)
+}
+
+func (m *ReadingListRepositoryMock) GetList(id int) (entities.ReadingList, error) {
+	args := m.Called(id)
+	return args.Get(0).(entities.ReadingList), args.Error(1)
+}
+
+func (m *ReadingListRepositoryMock) GetLists(viewer string, filter dtos.GetReadingListsFilter) ([]entities.ReadingList, error) {
+	args := m.Called(viewer, filter)
+	return args.Get(0).([]entities.ReadingList), args.Error(1)
+}
+
+func (m *ReadingListRepositoryMock) UpdateList(list entities.ReadingList) (entities.ReadingList, error) {
+	args := m.Called(list)
+	return args.Get(0).(entities.ReadingList), args.Error(1)
+}
+
+func (m *ReadingListRepositoryMock) DeleteList(id int) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *ReadingListRepositoryMock) GetListEntry(listId, bookId int) (entities.ReadingListEntry, error) {
+	args := m.Called(listId, bookId)
+	return args.Get(0).(entities.ReadingListEntry), args.Error(1)
+}
+
+func (m *ReadingListRepositoryMock) AddEntry(entry entities.ReadingListEntry) (entities.ReadingListEntry, error) {
+	args := m.Called(entry)
+	return args.Get(0).(entities.ReadingListEntry), args.Error(1)
+}
+
+func (m *ReadingListRepositoryMock) UpdateEntryNote(entry entities.ReadingListEntry) error {
+	args := m.Called(entry)
+	return args.Error(0)
+}
+
+func (m *ReadingListRepositoryMock) RemoveEntry(entry entities.ReadingListEntry) error {
+	args := m.Called(entry)
+	return args.Error(0)
+}
+
+func (m *ReadingListRepositoryMock) ReorderEntries(listId int, bookIds []int) error {
+	args := m.Called(listId, bookIds)
+	return args.Error(0)
+}
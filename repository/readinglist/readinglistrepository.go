@@ -0,0 +1,210 @@
+package repository
+
+import (
+	"github/brunojoenk/golang-test/models/dtos"
+	"github/brunojoenk/golang-test/models/entities"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type IReadingListRepository interface {
+	CreateList(list entities.ReadingList) (entities.ReadingList, error)
+	GetList(id int) (entities.ReadingList, error)
+	GetLists(viewer string, filter dtos.GetReadingListsFilter) ([]entities.ReadingList, error)
+	UpdateList(list entities.ReadingList) (entities.ReadingList, error)
+	DeleteList(id int) error
+	GetListEntry(listId, bookId int) (entities.ReadingListEntry, error)
+	AddEntry(entry entities.ReadingListEntry) (entities.ReadingListEntry, error)
+	UpdateEntryNote(entry entities.ReadingListEntry) error
+	RemoveEntry(entry entities.ReadingListEntry) error
+	ReorderEntries(listId int, bookIds []int) error
+}
+
+// ReadingListRepository Reading Lists Repository
+type ReadingListRepository struct {
+	db *gorm.DB
+}
+
+// NewReadingListRepository Repository Constructor
+func NewReadingListRepository(db *gorm.DB) IReadingListRepository {
+	return &ReadingListRepository{db: db}
+}
+
+func (r *ReadingListRepository) CreateList(list entities.ReadingList) (entities.ReadingList, error) {
+
+	if result := r.db.Omit("Entries").Create(&list); result.Error != nil {
+		log.Error("Error on create reading list: ", result.Error.Error())
+		return entities.ReadingList{}, result.Error
+	}
+
+	return list, nil
+}
+
+// GetList returns the list with its books in order
+func (r *ReadingListRepository) GetList(id int) (entities.ReadingList, error) {
+	var list entities.ReadingList
+
+	if result := r.db.Preload("Entries", func(db *gorm.DB) *gorm.DB {
+		return db.Order("position asc, id asc")
+	}).Preload("Entries.Book").Preload("Entries.Book.Contributors", func(db *gorm.DB) *gorm.DB {
+		return db.Order("position asc, author_id asc")
+	}).Preload("Entries.Book.Contributors.Author").Preload("Entries.Book.Copies").Preload("Entries.Book.Genres").
+		Preload("Entries.Book.Publisher").Preload("Entries.Book.Series").Preload("Entries.Book.Tags").Preload("Entries.Book.Titles").
+		First(&list, id); result.Error != nil {
+		log.Error("Error on get reading list: ", result.Error.Error())
+		return list, result.Error
+	}
+
+	return list, nil
+}
+
+// GetLists returns the public lists and those of the viewer, or only those of the owner of the filter, the last
+// changed first
+func (r *ReadingListRepository) GetLists(viewer string, filter dtos.GetReadingListsFilter) ([]entities.ReadingList, error) {
+
+	lists := make([]entities.ReadingList, 0)
+	toExec := r.db.Select("reading_lists.*, (SELECT COUNT(*) FROM reading_list_entries WHERE reading_list_entries.list_id = reading_lists.id) AS book_count")
+
+	if viewer != "" {
+		toExec = toExec.Where("visibility = ? OR owner = ?", dtos.ListVisibilityPublic, viewer)
+	} else {
+		toExec = toExec.Where("visibility = ?", dtos.ListVisibilityPublic)
+	}
+
+	if filter.Owner != "" {
+		toExec = toExec.Where("owner = ?", filter.Owner)
+	}
+
+	toExec = toExec.Offset((filter.Page - 1) * filter.Limit).Limit(filter.Limit).Order("updated_at desc, id desc")
+
+	if result := toExec.Find(&lists); result.Error != nil {
+		log.Error("Error on get reading lists: ", result.Error.Error())
+		return nil, result.Error
+	}
+
+	return lists, nil
+}
+
+func (r *ReadingListRepository) UpdateList(list entities.ReadingList) (entities.ReadingList, error) {
+
+	if result := r.db.Model(&list).Select("name", "description", "visibility", "updated_at").Updates(&list); result.Error != nil {
+		log.Error("Error on update reading list: ", result.Error.Error())
+		return entities.ReadingList{}, result.Error
+	}
+
+	return list, nil
+}
+
+// DeleteList deletes the list, its entries go with it
+func (r *ReadingListRepository) DeleteList(id int) error {
+
+	if result := r.db.Delete(&entities.ReadingList{}, id); result.Error != nil {
+		log.Error("Error on delete reading list: ", result.Error.Error())
+		return result.Error
+	}
+
+	return nil
+}
+
+func (r *ReadingListRepository) GetListEntry(listId, bookId int) (entities.ReadingListEntry, error) {
+	var entry entities.ReadingListEntry
+
+	if result := r.db.Where("list_id = ? AND book_id = ?", listId, bookId).First(&entry); result.Error != nil {
+		return entry, result.Error
+	}
+
+	return entry, nil
+}
+
+// AddEntry puts the book on the list at the position of the entry, moving the books from there one down, or
+// at the end when the position is not on the list
+func (r *ReadingListRepository) AddEntry(entry entities.ReadingListEntry) (entities.ReadingListEntry, error) {
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var last int
+		if result := tx.Raw("SELECT COALESCE(MAX(position), 0) FROM reading_list_entries WHERE list_id = ?", entry.ListId).
+			Scan(&last); result.Error != nil {
+			log.Error("Error on get last position of reading list: ", result.Error.Error())
+			return result.Error
+		}
+
+		if entry.Position < 1 || entry.Position > last {
+			entry.Position = last + 1
+		} else if result := tx.Exec("UPDATE reading_list_entries SET position = position + 1 WHERE list_id = ? AND position >= ?",
+			entry.ListId, entry.Position); result.Error != nil {
+			log.Error("Error on make room on reading list: ", result.Error.Error())
+			return result.Error
+		}
+
+		if result := tx.Omit("Book").Create(&entry); result.Error != nil {
+			log.Error("Error on add book to reading list: ", result.Error.Error())
+			return result.Error
+		}
+
+		return touchList(tx, entry.ListId)
+	})
+	if err != nil {
+		return entities.ReadingListEntry{}, err
+	}
+
+	return entry, nil
+}
+
+func (r *ReadingListRepository) UpdateEntryNote(entry entities.ReadingListEntry) error {
+
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if result := tx.Model(&entry).Select("note").Updates(&entry); result.Error != nil {
+			log.Error("Error on update note of reading list book: ", result.Error.Error())
+			return result.Error
+		}
+
+		return touchList(tx, entry.ListId)
+	})
+}
+
+// RemoveEntry takes the book off the list and moves the books after it one up
+func (r *ReadingListRepository) RemoveEntry(entry entities.ReadingListEntry) error {
+
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if result := tx.Delete(&entities.ReadingListEntry{}, entry.Id); result.Error != nil {
+			log.Error("Error on remove book from reading list: ", result.Error.Error())
+			return result.Error
+		}
+
+		if result := tx.Exec("UPDATE reading_list_entries SET position = position - 1 WHERE list_id = ? AND position > ?",
+			entry.ListId, entry.Position); result.Error != nil {
+			log.Error("Error on close gap on reading list: ", result.Error.Error())
+			return result.Error
+		}
+
+		return touchList(tx, entry.ListId)
+	})
+}
+
+// ReorderEntries numbers the books of the list in the order of bookIds
+func (r *ReadingListRepository) ReorderEntries(listId int, bookIds []int) error {
+
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for i, bookId := range bookIds {
+			if result := tx.Exec("UPDATE reading_list_entries SET position = ? WHERE list_id = ? AND book_id = ?",
+				i+1, listId, bookId); result.Error != nil {
+				log.Error("Error on reorder reading list: ", result.Error.Error())
+				return result.Error
+			}
+		}
+
+		return touchList(tx, listId)
+	})
+}
+
+// touchList marks the list as changed when its books change
+func touchList(tx *gorm.DB, listId int) error {
+	if result := tx.Exec("UPDATE reading_lists SET updated_at = ? WHERE id = ?", time.Now(), listId); result.Error != nil {
+		log.Error("Error on touch reading list: ", result.Error.Error())
+		return result.Error
+	}
+
+	return nil
+}
@@ -0,0 +1,286 @@
+package repository
+
+import (
+	"database/sql"
+	"github/brunojoenk/golang-test/models/dtos"
+	"github/brunojoenk/golang-test/models/entities"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+type Suite struct {
+	suite.Suite
+	DB   *gorm.DB
+	mock sqlmock.Sqlmock
+
+	repository *ReadingListRepository
+}
+
+func (s *Suite) AfterTest(_, _ string) {
+	require.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func TestInit(t *testing.T) {
+	suite.Run(t, new(Suite))
+}
+
+func (s *Suite) SetupSuite() {
+	var (
+		db  *sql.DB
+		err error
+	)
+
+	db, s.mock, err = sqlmock.New()
+	require.NoError(s.T(), err)
+
+	dialector := postgres.New(postgres.Config{
+		DSN:                  "sqlmock_db_0",
+		DriverName:           "postgres",
+		Conn:                 db,
+		PreferSimpleProtocol: true,
+	})
+	s.DB, err = gorm.Open(dialector, &gorm.Config{})
+	require.NoError(s.T(), err)
+
+	s.repository = &ReadingListRepository{db: s.DB}
+}
+
+const touchListQuery = `UPDATE reading_lists SET updated_at = $1 WHERE id = $2`
+
+var addedAt = time.Date(2022, 10, 8, 10, 0, 0, 0, time.UTC)
+
+func (s *Suite) Test_repository_Create_List() {
+	s.mock.ExpectBegin()
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`INSERT INTO "reading_lists" ("owner","name","description","visibility","created_at","updated_at") VALUES ($1,$2,$3,$4,$5,$6) RETURNING "id"`)).
+		WithArgs("ana", "To read", "", dtos.ListVisibilityPrivate, addedAt, addedAt).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).
+			AddRow(4))
+
+	s.mock.ExpectCommit()
+
+	list, err := s.repository.CreateList(entities.ReadingList{Owner: "ana", Name: "To read", Visibility: dtos.ListVisibilityPrivate, CreatedAt: addedAt, UpdatedAt: addedAt})
+
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), 4, list.Id)
+}
+
+func (s *Suite) Test_repository_Get_Lists_Of_Viewer() {
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT reading_lists.*, (SELECT COUNT(*) FROM reading_list_entries WHERE reading_list_entries.list_id = reading_lists.id) AS book_count `+
+			`FROM "reading_lists" WHERE (visibility = $1 OR owner = $2) AND owner = $3 ORDER BY updated_at desc, id desc LIMIT 10`)).
+		WithArgs(dtos.ListVisibilityPublic, "ana", "ana").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "owner", "name", "visibility", "book_count"}).
+			AddRow(4, "ana", "To read", dtos.ListVisibilityPrivate, 3))
+
+	lists, err := s.repository.GetLists("ana", dtos.GetReadingListsFilter{Owner: "ana", Pagination: dtos.Pagination{Page: 1, Limit: 10}})
+
+	require.NoError(s.T(), err)
+	require.Len(s.T(), lists, 1)
+	require.Equal(s.T(), 3, lists[0].BookCount)
+}
+
+func (s *Suite) Test_repository_Get_Lists_Anonymous() {
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT reading_lists.*, (SELECT COUNT(*) FROM reading_list_entries WHERE reading_list_entries.list_id = reading_lists.id) AS book_count ` +
+			`FROM "reading_lists" WHERE visibility = $1 ORDER BY updated_at desc, id desc LIMIT 10 OFFSET 10`)).
+		WithArgs(dtos.ListVisibilityPublic).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "owner", "name", "visibility", "book_count"}))
+
+	lists, err := s.repository.GetLists("", dtos.GetReadingListsFilter{Pagination: dtos.Pagination{Page: 2, Limit: 10}})
+
+	require.NoError(s.T(), err)
+	require.Empty(s.T(), lists)
+}
+
+func (s *Suite) Test_repository_Get_List_Not_Found() {
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "reading_lists" WHERE "reading_lists"."id" = $1 ORDER BY "reading_lists"."id" LIMIT 1`)).
+		WithArgs(4).
+		WillReturnError(gorm.ErrRecordNotFound)
+
+	_, err := s.repository.GetList(4)
+
+	require.ErrorIs(s.T(), err, gorm.ErrRecordNotFound)
+}
+
+func (s *Suite) Test_repository_Update_List() {
+	s.mock.ExpectBegin()
+
+	s.mock.ExpectExec(regexp.QuoteMeta(
+		`UPDATE "reading_lists" SET "name"=$1,"description"=$2,"visibility"=$3,"updated_at"=$4 WHERE "id" = $5`)).
+		WithArgs("Course syllabus", "Fall term", dtos.ListVisibilityPublic, sqlmock.AnyArg(), 4).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	s.mock.ExpectCommit()
+
+	list, err := s.repository.UpdateList(entities.ReadingList{Id: 4, Owner: "ana", Name: "Course syllabus", Description: "Fall term", Visibility: dtos.ListVisibilityPublic})
+
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), "Course syllabus", list.Name)
+}
+
+func (s *Suite) Test_repository_Delete_List() {
+	s.mock.ExpectBegin()
+
+	s.mock.ExpectExec(regexp.QuoteMeta(
+		`DELETE FROM "reading_lists" WHERE "reading_lists"."id" = $1`)).
+		WithArgs(4).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	s.mock.ExpectCommit()
+
+	err := s.repository.DeleteList(4)
+
+	require.NoError(s.T(), err)
+}
+
+func (s *Suite) Test_repository_Add_Entry_At_End() {
+	s.mock.ExpectBegin()
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT COALESCE(MAX(position), 0) FROM reading_list_entries WHERE list_id = $1`)).
+		WithArgs(4).
+		WillReturnRows(sqlmock.NewRows([]string{"coalesce"}).
+			AddRow(2))
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`INSERT INTO "reading_list_entries" ("list_id","book_id","position","note","added_at") VALUES ($1,$2,$3,$4,$5) RETURNING "id"`)).
+		WithArgs(4, 1, 3, "Chapter 2", addedAt).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).
+			AddRow(9))
+
+	s.mock.ExpectExec(regexp.QuoteMeta(touchListQuery)).
+		WithArgs(sqlmock.AnyArg(), 4).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	s.mock.ExpectCommit()
+
+	entry, err := s.repository.AddEntry(entities.ReadingListEntry{ListId: 4, BookId: 1, Position: 7, Note: "Chapter 2", AddedAt: addedAt})
+
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), 9, entry.Id)
+	require.Equal(s.T(), 3, entry.Position)
+}
+
+func (s *Suite) Test_repository_Add_Entry_At_Position() {
+	s.mock.ExpectBegin()
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT COALESCE(MAX(position), 0) FROM reading_list_entries WHERE list_id = $1`)).
+		WithArgs(4).
+		WillReturnRows(sqlmock.NewRows([]string{"coalesce"}).
+			AddRow(2))
+
+	s.mock.ExpectExec(regexp.QuoteMeta(
+		`UPDATE reading_list_entries SET position = position + 1 WHERE list_id = $1 AND position >= $2`)).
+		WithArgs(4, 1).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`INSERT INTO "reading_list_entries" ("list_id","book_id","position","note","added_at") VALUES ($1,$2,$3,$4,$5) RETURNING "id"`)).
+		WithArgs(4, 1, 1, "", addedAt).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).
+			AddRow(9))
+
+	s.mock.ExpectExec(regexp.QuoteMeta(touchListQuery)).
+		WithArgs(sqlmock.AnyArg(), 4).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	s.mock.ExpectCommit()
+
+	entry, err := s.repository.AddEntry(entities.ReadingListEntry{ListId: 4, BookId: 1, Position: 1, AddedAt: addedAt})
+
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), 1, entry.Position)
+}
+
+func (s *Suite) Test_repository_Add_Entry_Error() {
+	s.mock.ExpectBegin()
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT COALESCE(MAX(position), 0) FROM reading_list_entries WHERE list_id = $1`)).
+		WithArgs(4).
+		WillReturnError(gorm.ErrInvalidDB)
+
+	s.mock.ExpectRollback()
+
+	_, err := s.repository.AddEntry(entities.ReadingListEntry{ListId: 4, BookId: 1, AddedAt: addedAt})
+
+	require.ErrorIs(s.T(), err, gorm.ErrInvalidDB)
+}
+
+func (s *Suite) Test_repository_Update_Entry_Note() {
+	s.mock.ExpectBegin()
+
+	s.mock.ExpectExec(regexp.QuoteMeta(
+		`UPDATE "reading_list_entries" SET "note"=$1 WHERE "id" = $2`)).
+		WithArgs("Skip the preface", 9).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	s.mock.ExpectExec(regexp.QuoteMeta(touchListQuery)).
+		WithArgs(sqlmock.AnyArg(), 4).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	s.mock.ExpectCommit()
+
+	err := s.repository.UpdateEntryNote(entities.ReadingListEntry{Id: 9, ListId: 4, BookId: 1, Note: "Skip the preface"})
+
+	require.NoError(s.T(), err)
+}
+
+func (s *Suite) Test_repository_Remove_Entry() {
+	s.mock.ExpectBegin()
+
+	s.mock.ExpectExec(regexp.QuoteMeta(
+		`DELETE FROM "reading_list_entries" WHERE "reading_list_entries"."id" = $1`)).
+		WithArgs(9).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	s.mock.ExpectExec(regexp.QuoteMeta(
+		`UPDATE reading_list_entries SET position = position - 1 WHERE list_id = $1 AND position > $2`)).
+		WithArgs(4, 2).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	s.mock.ExpectExec(regexp.QuoteMeta(touchListQuery)).
+		WithArgs(sqlmock.AnyArg(), 4).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	s.mock.ExpectCommit()
+
+	err := s.repository.RemoveEntry(entities.ReadingListEntry{Id: 9, ListId: 4, BookId: 1, Position: 2})
+
+	require.NoError(s.T(), err)
+}
+
+func (s *Suite) Test_repository_Reorder_Entries() {
+	s.mock.ExpectBegin()
+
+	s.mock.ExpectExec(regexp.QuoteMeta(
+		`UPDATE reading_list_entries SET position = $1 WHERE list_id = $2 AND book_id = $3`)).
+		WithArgs(1, 4, 3).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	s.mock.ExpectExec(regexp.QuoteMeta(
+		`UPDATE reading_list_entries SET position = $1 WHERE list_id = $2 AND book_id = $3`)).
+		WithArgs(2, 4, 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	s.mock.ExpectExec(regexp.QuoteMeta(touchListQuery)).
+		WithArgs(sqlmock.AnyArg(), 4).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	s.mock.ExpectCommit()
+
+	err := s.repository.ReorderEntries(4, []int{3, 1})
+
+	require.NoError(s.T(), err)
+}
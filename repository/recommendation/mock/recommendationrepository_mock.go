@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"github/brunojoenk/golang-test/models/dtos"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type RecommendationRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *RecommendationRepositoryMock) GetRelatedBooks(bookId int, weights dtos.RelatedWeights, pagination dtos.Pagination) ([]dtos.RelatedBookScore, error) {
+	args := m.Called(bookId, weights, pagination)
+	return args.Get(0).([]dtos.RelatedBookScore), args.Error(1)
+}
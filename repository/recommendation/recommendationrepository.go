@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"github/brunojoenk/golang-test/models/dtos"
+
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type IRecommendationRepository interface {
+	GetRelatedBooks(bookId int, weights dtos.RelatedWeights, pagination dtos.Pagination) ([]dtos.RelatedBookScore, error)
+}
+
+// RecommendationRepository Recommendations Repository
+type RecommendationRepository struct {
+	db *gorm.DB
+}
+
+// NewRecommendationRepository Repository Constructor
+func NewRecommendationRepository(db *gorm.DB) IRecommendationRepository {
+	return &RecommendationRepository{db: db}
+}
+
+// relatedBooksSQL lists a row with the weight of each thing a book has in common with the book @id: each author,
+// genre and tag, each patron who borrowed both and each public reading list with both. Other editions of the
+// work of the book are left out, and the rows of a book are summed up into its score
+const relatedBooksSQL = "SELECT related.book_id, SUM(related.weight) AS score, " +
+	"COUNT(*) FILTER (WHERE related.reason = 'author') AS shared_authors, " +
+	"COUNT(*) FILTER (WHERE related.reason = 'genre') AS shared_genres, " +
+	"COUNT(*) FILTER (WHERE related.reason = 'tag') AS shared_tags, " +
+	"COUNT(*) FILTER (WHERE related.reason = 'co_borrowed') AS co_borrowed, " +
+	"COUNT(*) FILTER (WHERE related.reason = 'co_listed') AS co_listed " +
+	"FROM (" +
+	"SELECT other.book_id, 'author' AS reason, CAST(@author AS INTEGER) AS weight FROM author_book this " +
+	"JOIN author_book other ON other.author_id = this.author_id AND other.book_id <> this.book_id AND other.role = @role " +
+	"WHERE this.book_id = @id AND this.role = @role " +
+	"UNION ALL " +
+	"SELECT other.book_id, 'genre', CAST(@genre AS INTEGER) FROM book_genre this " +
+	"JOIN book_genre other ON other.genre_id = this.genre_id AND other.book_id <> this.book_id " +
+	"WHERE this.book_id = @id " +
+	"UNION ALL " +
+	"SELECT other.book_id, 'tag', CAST(@tag AS INTEGER) FROM book_tag this " +
+	"JOIN book_tag other ON other.tag_id = this.tag_id AND other.book_id <> this.book_id " +
+	"WHERE this.book_id = @id " +
+	"UNION ALL " +
+	"SELECT borrowed.book_id, 'co_borrowed', CAST(@coBorrowed AS INTEGER) FROM (" +
+	"SELECT DISTINCT other_copy.book_id, other_loan.patron_id FROM loans this_loan " +
+	"JOIN copies this_copy ON this_copy.id = this_loan.copy_id " +
+	"JOIN loans other_loan ON other_loan.patron_id = this_loan.patron_id " +
+	"JOIN copies other_copy ON other_copy.id = other_loan.copy_id AND other_copy.book_id <> this_copy.book_id " +
+	"WHERE this_copy.book_id = @id) borrowed " +
+	"UNION ALL " +
+	"SELECT other.book_id, 'co_listed', CAST(@coListed AS INTEGER) FROM reading_list_entries this " +
+	"JOIN reading_lists ON reading_lists.id = this.list_id AND reading_lists.visibility = @public " +
+	"JOIN reading_list_entries other ON other.list_id = this.list_id AND other.book_id <> this.book_id " +
+	"WHERE this.book_id = @id" +
+	") related " +
+	"JOIN books ON books.id = related.book_id " +
+	"WHERE books.work_id IS NULL OR books.work_id IS DISTINCT FROM (SELECT work_id FROM books WHERE id = @id) " +
+	"GROUP BY related.book_id, books.id " +
+	"ORDER BY score DESC, books.rating_average DESC, related.book_id ASC " +
+	"LIMIT @limit OFFSET @offset"
+
+// GetRelatedBooks scores the books by what they have in common with the book, the most related first. Ties go
+// to the best rated book, then to the oldest one, so the order is always the same
+func (r *RecommendationRepository) GetRelatedBooks(bookId int, weights dtos.RelatedWeights, pagination dtos.Pagination) ([]dtos.RelatedBookScore, error) {
+	scores := make([]dtos.RelatedBookScore, 0)
+
+	if result := r.db.Raw(relatedBooksSQL, map[string]interface{}{
+		"id":         bookId,
+		"role":       dtos.RoleAuthor,
+		"public":     dtos.ListVisibilityPublic,
+		"author":     weights.Author,
+		"genre":      weights.Genre,
+		"tag":        weights.Tag,
+		"coBorrowed": weights.CoBorrowed,
+		"coListed":   weights.CoListed,
+		"limit":      pagination.Limit,
+		"offset":     (pagination.Page - 1) * pagination.Limit,
+	}).Scan(&scores); result.Error != nil {
+		log.Error("Error on get related books: ", result.Error.Error())
+		return nil, result.Error
+	}
+
+	return scores, nil
+}
@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"database/sql"
+	"github/brunojoenk/golang-test/models/dtos"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+type Suite struct {
+	suite.Suite
+	DB   *gorm.DB
+	mock sqlmock.Sqlmock
+
+	repository *RecommendationRepository
+}
+
+func (s *Suite) AfterTest(_, _ string) {
+	require.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func TestInit(t *testing.T) {
+	suite.Run(t, new(Suite))
+}
+
+func (s *Suite) SetupSuite() {
+	var (
+		db  *sql.DB
+		err error
+	)
+
+	db, s.mock, err = sqlmock.New()
+	require.NoError(s.T(), err)
+
+	dialector := postgres.New(postgres.Config{
+		DSN:                  "sqlmock_db_0",
+		DriverName:           "postgres",
+		Conn:                 db,
+		PreferSimpleProtocol: true,
+	})
+	s.DB, err = gorm.Open(dialector, &gorm.Config{})
+	require.NoError(s.T(), err)
+
+	s.repository = &RecommendationRepository{db: s.DB}
+}
+
+var weights = dtos.RelatedWeights{Author: 4, Genre: 2, Tag: 1, CoBorrowed: 1, CoListed: 1}
+
+func (s *Suite) Test_repository_Get_Related_Books() {
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT related.book_id, SUM(related.weight) AS score, `+
+			`COUNT(*) FILTER (WHERE related.reason = 'author') AS shared_authors, `+
+			`COUNT(*) FILTER (WHERE related.reason = 'genre') AS shared_genres, `+
+			`COUNT(*) FILTER (WHERE related.reason = 'tag') AS shared_tags, `+
+			`COUNT(*) FILTER (WHERE related.reason = 'co_borrowed') AS co_borrowed, `+
+			`COUNT(*) FILTER (WHERE related.reason = 'co_listed') AS co_listed FROM (`)+
+		`.*`+regexp.QuoteMeta(
+		`) related JOIN books ON books.id = related.book_id `+
+			`WHERE books.work_id IS NULL OR books.work_id IS DISTINCT FROM (SELECT work_id FROM books WHERE id = $14) `+
+			`GROUP BY related.book_id, books.id ORDER BY score DESC, books.rating_average DESC, related.book_id ASC LIMIT $15 OFFSET $16`)).
+		WithArgs(4, dtos.RoleAuthor, 7, dtos.RoleAuthor, 2, 7, 1, 7, 1, 7, 1, dtos.ListVisibilityPublic, 7, 7, 10, 10).
+		WillReturnRows(sqlmock.NewRows([]string{"book_id", "score", "shared_authors", "shared_genres", "shared_tags", "co_borrowed", "co_listed"}).
+			AddRow(3, 9, 1, 2, 1, 0, 0).
+			AddRow(5, 2, 0, 0, 0, 1, 1))
+
+	scores, err := s.repository.GetRelatedBooks(7, weights, dtos.Pagination{Page: 2, Limit: 10})
+
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), []dtos.RelatedBookScore{
+		{BookId: 3, Score: 9, SharedAuthors: 1, SharedGenres: 2, SharedTags: 1},
+		{BookId: 5, Score: 2, CoBorrowed: 1, CoListed: 1},
+	}, scores)
+}
+
+func (s *Suite) Test_repository_Get_Related_Books_Error() {
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT related.book_id, SUM(related.weight) AS score`)).
+		WillReturnError(gorm.ErrInvalidDB)
+
+	_, err := s.repository.GetRelatedBooks(7, weights, dtos.Pagination{Page: 1, Limit: 10})
+
+	require.ErrorIs(s.T(), err, gorm.ErrInvalidDB)
+}
@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"github/brunojoenk/golang-test/models/dtos"
+	"github/brunojoenk/golang-test/models/entities"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type ReviewRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *ReviewRepositoryMock) CreateReview(review entities.Review) (entities.Review, error) {
+	args := m.Called(review)
+	return args.Get(0).(entities.Review), args.Error(1)
+}
+
+func (m *ReviewRepositoryMock) GetReview(id int) (entities.Review, error) {
+	args := m.Called(id)
+	return args.Get(0).(entities.Review), args.Error(1)
+}
+
+func (m *ReviewRepositoryMock) GetPatronReview(bookId, patronId int) (entities.Review, error) {
+	args := m.Called(bookId, patronId)
+	return args.Get(0).(entities.Review), args.Error(1)
+}
+
+func (m *ReviewRepositoryMock) GetBookReviews(bookId int, filter dtos.GetReviewsFilter) ([]entities.Review, error) {
+	args := m.Called(bookId, filter)
+	return args.Get(0).([]entities.Review), args.Error(1)
+}
+
+func (m *ReviewRepositoryMock) UpdateReview(review entities.Review) (entities.Review, error) {
+	args := m.Called(review)
+	return args.Get(0).(entities.Review), args.Error(1)
+}
+
+func (m *ReviewRepositoryMock) DeleteReview(review entities.Review) error {
+	args := m.Called(review)
+	return args.Error(0)
+}
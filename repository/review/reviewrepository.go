@@ -0,0 +1,166 @@
+package repository
+
+import (
+	"github/brunojoenk/golang-test/models/dtos"
+	"github/brunojoenk/golang-test/models/entities"
+
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type IReviewRepository interface {
+	CreateReview(review entities.Review) (entities.Review, error)
+	GetReview(id int) (entities.Review, error)
+	GetPatronReview(bookId, patronId int) (entities.Review, error)
+	GetBookReviews(bookId int, filter dtos.GetReviewsFilter) ([]entities.Review, error)
+	UpdateReview(review entities.Review) (entities.Review, error)
+	DeleteReview(review entities.Review) error
+}
+
+// ReviewRepository Reviews Repository
+type ReviewRepository struct {
+	db *gorm.DB
+}
+
+// NewReviewRepository Repository Constructor
+func NewReviewRepository(db *gorm.DB) IReviewRepository {
+	return &ReviewRepository{db: db}
+}
+
+// updateRatingsSQL recounts the average rating and the count of the reviews of the books
+const updateRatingsSQL = "UPDATE books SET " +
+	"rating_count = (SELECT COUNT(*) FROM reviews WHERE reviews.book_id = books.id), " +
+	"rating_average = COALESCE((SELECT ROUND(AVG(rating), 2) FROM reviews WHERE reviews.book_id = books.id), 0) " +
+	"WHERE books.id IN ?"
+
+// LockBooks locks the rows of the books until the transaction ends. Transactions that change the reviews
+// of a book take the lock before writing, so each recount sees the reviews committed before it
+func LockBooks(tx *gorm.DB, bookIds []int) error {
+	return tx.Exec("SELECT id FROM books WHERE id IN ? ORDER BY id FOR UPDATE", bookIds).Error
+}
+
+// UpdateRatings recounts the rating of the books in the transaction. The repositories that move or remove
+// reviews of other entities, e.g. a merged book or a deleted patron, recount with it too
+func UpdateRatings(tx *gorm.DB, bookIds []int) error {
+	return tx.Exec(updateRatingsSQL, bookIds).Error
+}
+
+// CreateReview saves the review and the new rating of the book in one transaction
+func (r *ReviewRepository) CreateReview(review entities.Review) (entities.Review, error) {
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := lockBook(tx, review.BookId); err != nil {
+			return err
+		}
+
+		if result := tx.Omit("Book", "Patron").Create(&review); result.Error != nil {
+			log.Error("Error on create review: ", result.Error.Error())
+			return result.Error
+		}
+
+		return updateRating(tx, review.BookId)
+	})
+	if err != nil {
+		return entities.Review{}, err
+	}
+
+	return review, nil
+}
+
+func (r *ReviewRepository) GetReview(id int) (entities.Review, error) {
+	var review entities.Review
+
+	if result := r.db.Preload("Patron").First(&review, id); result.Error != nil {
+		log.Error("Error on get review: ", result.Error.Error())
+		return review, result.Error
+	}
+
+	return review, nil
+}
+
+func (r *ReviewRepository) GetPatronReview(bookId, patronId int) (entities.Review, error) {
+	var review entities.Review
+
+	if result := r.db.Where("book_id = ? AND patron_id = ?", bookId, patronId).First(&review); result.Error != nil {
+		return review, result.Error
+	}
+
+	return review, nil
+}
+
+// GetBookReviews returns the reviews of the book with the rating of the filter, if any, the newest first
+func (r *ReviewRepository) GetBookReviews(bookId int, filter dtos.GetReviewsFilter) ([]entities.Review, error) {
+
+	reviews := make([]entities.Review, 0)
+	toExec := r.db.Where("book_id = ?", bookId)
+
+	if filter.Rating > 0 {
+		toExec = toExec.Where("rating = ?", filter.Rating)
+	}
+
+	toExec = toExec.Offset((filter.Page - 1) * filter.Limit).Limit(filter.Limit).Order("created_at desc, id desc")
+
+	if result := toExec.Preload("Patron").Find(&reviews); result.Error != nil {
+		log.Error("Error on get reviews of book: ", result.Error.Error())
+		return nil, result.Error
+	}
+
+	return reviews, nil
+}
+
+// UpdateReview saves the rating and text of the review and the new rating of the book in one transaction
+func (r *ReviewRepository) UpdateReview(review entities.Review) (entities.Review, error) {
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := lockBook(tx, review.BookId); err != nil {
+			return err
+		}
+
+		if result := tx.Model(&review).Select("rating", "text", "updated_at").Updates(&review); result.Error != nil {
+			log.Error("Error on update review: ", result.Error.Error())
+			return result.Error
+		}
+
+		return updateRating(tx, review.BookId)
+	})
+	if err != nil {
+		return entities.Review{}, err
+	}
+
+	return review, nil
+}
+
+// DeleteReview deletes the review and saves the new rating of the book in one transaction
+func (r *ReviewRepository) DeleteReview(review entities.Review) error {
+
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := lockBook(tx, review.BookId); err != nil {
+			return err
+		}
+
+		if result := tx.Delete(&entities.Review{}, review.Id); result.Error != nil {
+			log.Error("Error on delete review: ", result.Error.Error())
+			return result.Error
+		}
+
+		return updateRating(tx, review.BookId)
+	})
+}
+
+func lockBook(tx *gorm.DB, bookId int) error {
+	if err := LockBooks(tx, []int{bookId}); err != nil {
+		log.Error("Error on lock book: ", err.Error())
+		return err
+	}
+
+	return nil
+}
+
+func updateRating(tx *gorm.DB, bookId int) error {
+	if err := UpdateRatings(tx, []int{bookId}); err != nil {
+		log.Error("Error on update rating of book: ", err.Error())
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,74 @@
+//go:build integration
+
+package repository
+
+import (
+	"github/brunojoenk/golang-test/models/entities"
+	"os"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/logger"
+)
+
+// Several patrons review the same book at the same time against a real database. Each transaction locks
+// the book before adding its review, so every recount sees the reviews committed before it and the book
+// ends up counting all of them
+func TestCreateReviewConcurrently(t *testing.T) {
+	dataSourceName := os.Getenv("TEST_DATABASE_URL")
+	if dataSourceName == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+	db, err := gorm.Open(postgres.Open(dataSourceName), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&entities.Author{}, &entities.Publisher{}, &entities.Genre{}, &entities.Series{}, &entities.Tag{}, &entities.Work{}, &entities.Book{}, &entities.AuthorBook{}, &entities.Patron{}, &entities.Review{}))
+
+	suffix := time.Now().Format("20060102150405.000000000")
+	book := entities.Book{Name: "Concurrent reviews " + suffix}
+	require.NoError(t, db.Omit(clause.Associations).Create(&book).Error)
+	patrons := make([]entities.Patron, 8)
+	for i := range patrons {
+		patrons[i] = entities.Patron{Name: "Reviewer " + strconv.Itoa(i), Email: "reviewer-" + strconv.Itoa(i) + "-" + suffix + "@example.com"}
+	}
+	require.NoError(t, db.Create(&patrons).Error)
+	t.Cleanup(func() {
+		db.Where("book_id = ?", book.Id).Delete(&entities.Review{})
+		db.Delete(&patrons)
+		db.Delete(&book)
+	})
+
+	repository := NewReviewRepository(db)
+	var (
+		wg    sync.WaitGroup
+		start = make(chan struct{})
+		errs  = make([]error, len(patrons))
+	)
+	for i := range patrons {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			<-start
+			now := time.Now()
+			_, errs[i] = repository.CreateReview(entities.Review{BookId: book.Id, PatronId: patrons[i].Id, Rating: i%5 + 1, CreatedAt: now, UpdatedAt: now})
+		}(i)
+	}
+	close(start)
+	wg.Wait()
+
+	for _, err := range errs {
+		require.NoError(t, err)
+	}
+
+	var rated entities.Book
+	require.NoError(t, db.First(&rated, book.Id).Error)
+	require.Equal(t, len(patrons), rated.RatingCount)
+	require.Equal(t, 2.63, rated.RatingAverage)
+}
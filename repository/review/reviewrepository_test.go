@@ -0,0 +1,190 @@
+package repository
+
+import (
+	"database/sql"
+	"github/brunojoenk/golang-test/models/dtos"
+	"github/brunojoenk/golang-test/models/entities"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+type Suite struct {
+	suite.Suite
+	DB   *gorm.DB
+	mock sqlmock.Sqlmock
+
+	repository *ReviewRepository
+}
+
+func (s *Suite) AfterTest(_, _ string) {
+	require.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func TestInit(t *testing.T) {
+	suite.Run(t, new(Suite))
+}
+
+func (s *Suite) SetupSuite() {
+	var (
+		db  *sql.DB
+		err error
+	)
+
+	db, s.mock, err = sqlmock.New()
+	require.NoError(s.T(), err)
+
+	dialector := postgres.New(postgres.Config{
+		DSN:                  "sqlmock_db_0",
+		DriverName:           "postgres",
+		Conn:                 db,
+		PreferSimpleProtocol: true,
+	})
+	s.DB, err = gorm.Open(dialector, &gorm.Config{})
+	require.NoError(s.T(), err)
+
+	s.repository = &ReviewRepository{db: s.DB}
+}
+
+const updateRatingQuery = `UPDATE books SET rating_count = (SELECT COUNT(*) FROM reviews WHERE reviews.book_id = books.id), ` +
+	`rating_average = COALESCE((SELECT ROUND(AVG(rating), 2) FROM reviews WHERE reviews.book_id = books.id), 0) WHERE books.id IN ($1)`
+
+const lockBookQuery = `SELECT id FROM books WHERE id IN ($1) ORDER BY id FOR UPDATE`
+
+var reviewedAt = time.Date(2022, 10, 3, 14, 30, 0, 0, time.UTC)
+
+func (s *Suite) Test_repository_Create_Review() {
+	s.mock.ExpectBegin()
+
+	s.mock.ExpectExec(regexp.QuoteMeta(lockBookQuery)).
+		WithArgs(1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`INSERT INTO "reviews" ("book_id","patron_id","owner","rating","text","created_at","updated_at") VALUES ($1,$2,$3,$4,$5,$6,$7) RETURNING "id"`)).
+		WithArgs(1, 3, "ana", 4, "Great", reviewedAt, reviewedAt).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).
+			AddRow(5))
+
+	s.mock.ExpectExec(regexp.QuoteMeta(updateRatingQuery)).
+		WithArgs(1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	s.mock.ExpectCommit()
+
+	review, err := s.repository.CreateReview(entities.Review{BookId: 1, PatronId: 3, Owner: "ana", Rating: 4, Text: "Great", CreatedAt: reviewedAt, UpdatedAt: reviewedAt})
+
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), 5, review.Id)
+}
+
+func (s *Suite) Test_repository_Create_Review_Error_On_Rating() {
+	s.mock.ExpectBegin()
+
+	s.mock.ExpectExec(regexp.QuoteMeta(lockBookQuery)).
+		WithArgs(1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`INSERT INTO "reviews" ("book_id","patron_id","owner","rating","text","created_at","updated_at") VALUES ($1,$2,$3,$4,$5,$6,$7) RETURNING "id"`)).
+		WithArgs(1, 3, "ana", 4, "Great", reviewedAt, reviewedAt).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).
+			AddRow(5))
+
+	s.mock.ExpectExec(regexp.QuoteMeta(updateRatingQuery)).
+		WithArgs(1).
+		WillReturnError(gorm.ErrInvalidDB)
+
+	s.mock.ExpectRollback()
+
+	_, err := s.repository.CreateReview(entities.Review{BookId: 1, PatronId: 3, Owner: "ana", Rating: 4, Text: "Great", CreatedAt: reviewedAt, UpdatedAt: reviewedAt})
+
+	require.ErrorIs(s.T(), err, gorm.ErrInvalidDB)
+}
+
+func (s *Suite) Test_repository_Create_Review_Error_On_Lock() {
+	s.mock.ExpectBegin()
+
+	s.mock.ExpectExec(regexp.QuoteMeta(lockBookQuery)).
+		WithArgs(1).
+		WillReturnError(gorm.ErrInvalidDB)
+
+	s.mock.ExpectRollback()
+
+	_, err := s.repository.CreateReview(entities.Review{BookId: 1, PatronId: 3, Owner: "ana", Rating: 4, Text: "Great", CreatedAt: reviewedAt, UpdatedAt: reviewedAt})
+
+	require.ErrorIs(s.T(), err, gorm.ErrInvalidDB)
+}
+
+func (s *Suite) Test_repository_Get_Book_Reviews() {
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "reviews" WHERE book_id = $1 AND rating = $2 ORDER BY created_at desc, id desc LIMIT 10`)).
+		WithArgs(1, 5).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "book_id", "patron_id", "rating", "text"}).
+			AddRow(5, 1, 3, 5, "Great"))
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "patrons" WHERE "patrons"."id" = $1`)).
+		WithArgs(3).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).
+			AddRow(3, "Ana"))
+
+	reviews, err := s.repository.GetBookReviews(1, dtos.GetReviewsFilter{Rating: 5, Pagination: dtos.Pagination{Page: 1, Limit: 10}})
+
+	require.NoError(s.T(), err)
+	require.Len(s.T(), reviews, 1)
+	require.Equal(s.T(), "Ana", reviews[0].Patron.Name)
+}
+
+func (s *Suite) Test_repository_Update_Review() {
+	s.mock.ExpectBegin()
+
+	s.mock.ExpectExec(regexp.QuoteMeta(lockBookQuery)).
+		WithArgs(1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	s.mock.ExpectExec(regexp.QuoteMeta(
+		`UPDATE "reviews" SET "rating"=$1,"text"=$2,"updated_at"=$3 WHERE "id" = $4`)).
+		WithArgs(2, "Not that great", sqlmock.AnyArg(), 5).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	s.mock.ExpectExec(regexp.QuoteMeta(updateRatingQuery)).
+		WithArgs(1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	s.mock.ExpectCommit()
+
+	review, err := s.repository.UpdateReview(entities.Review{Id: 5, BookId: 1, PatronId: 3, Rating: 2, Text: "Not that great"})
+
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), 2, review.Rating)
+}
+
+func (s *Suite) Test_repository_Delete_Review() {
+	s.mock.ExpectBegin()
+
+	s.mock.ExpectExec(regexp.QuoteMeta(lockBookQuery)).
+		WithArgs(1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	s.mock.ExpectExec(regexp.QuoteMeta(
+		`DELETE FROM "reviews" WHERE "reviews"."id" = $1`)).
+		WithArgs(5).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	s.mock.ExpectExec(regexp.QuoteMeta(updateRatingQuery)).
+		WithArgs(1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	s.mock.ExpectCommit()
+
+	err := s.repository.DeleteReview(entities.Review{Id: 5, BookId: 1})
+
+	require.NoError(s.T(), err)
+}
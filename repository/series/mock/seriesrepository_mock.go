@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"github/brunojoenk/golang-test/models/dtos"
+	"github/brunojoenk/golang-test/models/entities"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type SeriesRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *SeriesRepositoryMock) CreateSeries(series entities.Series) (entities.Series, error) {
+	args := m.Called(series)
+	return args.Get(0).(entities.Series), args.Error(1)
+}
+
+func (m *SeriesRepositoryMock) GetSeries(id int) (entities.Series, error) {
+	args := m.Called(id)
+	return args.Get(0).(entities.Series), args.Error(1)
+}
+
+func (m *SeriesRepositoryMock) GetSeriesByNormalizedName(normalizedName string) (entities.Series, error) {
+	args := m.Called(normalizedName)
+	return args.Get(0).(entities.Series), args.Error(1)
+}
+
+func (m *SeriesRepositoryMock) GetAllSeries(filter dtos.GetSeriesFilter) ([]entities.Series, error) {
+	args := m.Called(filter)
+	return args.Get(0).([]entities.Series), args.Error(1)
+}
+
+func (m *SeriesRepositoryMock) UpdateSeries(series entities.Series) (entities.Series, error) {
+	args := m.Called(series)
+	return args.Get(0).(entities.Series), args.Error(1)
+}
+
+func (m *SeriesRepositoryMock) DeleteSeries(id int) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *SeriesRepositoryMock) CountBooks(id int) (int64, error) {
+	args := m.Called(id)
+	return args.Get(0).(int64), args.Error(1)
+}
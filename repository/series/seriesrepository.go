@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"github/brunojoenk/golang-test/models/dtos"
+	"github/brunojoenk/golang-test/models/entities"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type ISeriesRepository interface {
+	CreateSeries(series entities.Series) (entities.Series, error)
+	GetSeries(id int) (entities.Series, error)
+	GetSeriesByNormalizedName(normalizedName string) (entities.Series, error)
+	GetAllSeries(filter dtos.GetSeriesFilter) ([]entities.Series, error)
+	UpdateSeries(series entities.Series) (entities.Series, error)
+	DeleteSeries(id int) error
+	CountBooks(id int) (int64, error)
+}
+
+// SeriesRepository Series Repository
+type SeriesRepository struct {
+	db *gorm.DB
+}
+
+// NewSeriesRepository Repository Constructor
+func NewSeriesRepository(db *gorm.DB) ISeriesRepository {
+	return &SeriesRepository{db: db}
+}
+
+func (s *SeriesRepository) CreateSeries(series entities.Series) (entities.Series, error) {
+
+	if result := s.db.Create(&series); result.Error != nil {
+		log.Error("Error on create series: ", result.Error.Error())
+		return entities.Series{}, result.Error
+	}
+
+	return series, nil
+}
+
+func (s *SeriesRepository) GetSeries(id int) (entities.Series, error) {
+	var series entities.Series
+
+	if result := s.db.First(&series, id); result.Error != nil {
+		log.Error("Error on get series: ", result.Error.Error())
+		return series, result.Error
+	}
+
+	return series, nil
+}
+
+func (s *SeriesRepository) GetSeriesByNormalizedName(normalizedName string) (entities.Series, error) {
+	var series entities.Series
+
+	if result := s.db.Where("normalized_name = ?", normalizedName).First(&series); result.Error != nil {
+		return series, result.Error
+	}
+
+	return series, nil
+}
+
+func (s *SeriesRepository) GetAllSeries(filter dtos.GetSeriesFilter) ([]entities.Series, error) {
+
+	var series []entities.Series
+	toExec := s.db
+
+	if strings.TrimSpace(filter.Name) != "" {
+		toExec = toExec.Where("LOWER(name) LIKE ?", "%"+strings.ToLower(filter.Name)+"%")
+	}
+
+	toExec = toExec.Offset((filter.Page - 1) * filter.Limit).Limit(filter.Limit).Order("name asc")
+
+	if result := toExec.Find(&series); result.Error != nil {
+		log.Error("Error on get all series: ", result.Error.Error())
+		return nil, result.Error
+	}
+
+	return series, nil
+}
+
+func (s *SeriesRepository) UpdateSeries(series entities.Series) (entities.Series, error) {
+
+	if result := s.db.Save(&series); result.Error != nil {
+		log.Error("Error on update series: ", result.Error.Error())
+		return entities.Series{}, result.Error
+	}
+
+	return series, nil
+}
+
+func (s *SeriesRepository) DeleteSeries(id int) error {
+
+	if result := s.db.Delete(&entities.Series{}, id); result.Error != nil {
+		log.Error("Error on delete series: ", result.Error.Error())
+		return result.Error
+	}
+
+	return nil
+}
+
+// CountBooks returns how many books belong to the series
+func (s *SeriesRepository) CountBooks(id int) (int64, error) {
+	var count int64
+
+	if result := s.db.Model(&entities.Book{}).Where("series_id = ?", id).Count(&count); result.Error != nil {
+		log.Error("Error on count books of series: ", result.Error.Error())
+		return 0, result.Error
+	}
+
+	return count, nil
+}
@@ -0,0 +1,184 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"github/brunojoenk/golang-test/models/dtos"
+	"github/brunojoenk/golang-test/models/entities"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-test/deep"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+type Suite struct {
+	suite.Suite
+	DB   *gorm.DB
+	mock sqlmock.Sqlmock
+
+	repository *SeriesRepository
+}
+
+func (s *Suite) AfterTest(_, _ string) {
+	require.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func TestInit(t *testing.T) {
+	suite.Run(t, new(Suite))
+}
+
+func (s *Suite) SetupSuite() {
+	var (
+		db  *sql.DB
+		err error
+	)
+
+	db, s.mock, err = sqlmock.New()
+	require.NoError(s.T(), err)
+
+	dialector := postgres.New(postgres.Config{
+		DSN:                  "sqlmock_db_0",
+		DriverName:           "postgres",
+		Conn:                 db,
+		PreferSimpleProtocol: true,
+	})
+	s.DB, err = gorm.Open(dialector, &gorm.Config{})
+	require.NoError(s.T(), err)
+
+	s.repository = &SeriesRepository{db: s.DB}
+}
+func (s *Suite) Test_repository_Create_Series() {
+	var (
+		id   = 1
+		name = "The Lord of the Rings"
+	)
+
+	s.mock.ExpectBegin()
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`INSERT INTO "series" ("name","normalized_name") VALUES ($1,$2) RETURNING "id"`)).
+		WithArgs(name, "the lord of the rings").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).
+			AddRow(id))
+
+	s.mock.ExpectCommit()
+
+	series, err := s.repository.CreateSeries(entities.Series{Name: name, NormalizedName: "the lord of the rings"})
+
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), id, series.Id)
+}
+
+func (s *Suite) Test_repository_Create_Series_Error() {
+	s.mock.ExpectBegin()
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`INSERT INTO "series"`)).
+		WillReturnError(context.Canceled)
+
+	s.mock.ExpectRollback()
+
+	_, err := s.repository.CreateSeries(entities.Series{Name: "The Lord of the Rings"})
+
+	require.Error(s.T(), err)
+}
+
+func (s *Suite) Test_repository_Get_Series() {
+	var (
+		id   = 1
+		name = "The Lord of the Rings"
+	)
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "series" WHERE "series"."id" = $1 ORDER BY "series"."id" LIMIT 1`)).
+		WithArgs(id).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).
+			AddRow(id, name))
+
+	res, err := s.repository.GetSeries(id)
+
+	require.NoError(s.T(), err)
+	require.Nil(s.T(), deep.Equal(entities.Series{Id: id, Name: name}, res))
+}
+
+func (s *Suite) Test_repository_Get_Series_By_Normalized_Name_Not_Found() {
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "series" WHERE normalized_name = $1 ORDER BY "series"."id" LIMIT 1`)).
+		WithArgs("discworld").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	_, err := s.repository.GetSeriesByNormalizedName("discworld")
+
+	require.ErrorIs(s.T(), err, gorm.ErrRecordNotFound)
+}
+
+func (s *Suite) Test_repository_Get_All_Series() {
+	var (
+		id   = 1
+		name = "The Lord of the Rings"
+	)
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "series" WHERE LOWER(name) LIKE $1 ORDER BY name asc LIMIT 10`)).
+		WithArgs("%rings%").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).
+			AddRow(id, name))
+
+	res, err := s.repository.GetAllSeries(dtos.GetSeriesFilter{Name: "Rings", Pagination: dtos.Pagination{Page: 1, Limit: 10}})
+
+	require.NoError(s.T(), err)
+	require.Nil(s.T(), deep.Equal([]entities.Series{{Id: id, Name: name}}, res))
+}
+
+func (s *Suite) Test_repository_Update_Series() {
+	var (
+		id   = 1
+		name = "Discworld"
+	)
+
+	s.mock.ExpectBegin()
+
+	s.mock.ExpectExec(regexp.QuoteMeta(
+		`UPDATE "series" SET "name"=$1,"normalized_name"=$2 WHERE "id" = $3`)).
+		WithArgs(name, "discworld", id).
+		WillReturnResult(sqlmock.NewResult(int64(id), 1))
+
+	s.mock.ExpectCommit()
+
+	_, err := s.repository.UpdateSeries(entities.Series{Id: id, Name: name, NormalizedName: "discworld"})
+
+	require.NoError(s.T(), err)
+}
+
+func (s *Suite) Test_repository_Delete_Series() {
+	s.mock.ExpectBegin()
+
+	s.mock.ExpectExec(regexp.QuoteMeta(
+		`DELETE FROM "series" WHERE "series"."id" = $1`)).
+		WithArgs(1).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	s.mock.ExpectCommit()
+
+	err := s.repository.DeleteSeries(1)
+
+	require.NoError(s.T(), err)
+}
+
+func (s *Suite) Test_repository_Count_Books() {
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT count(*) FROM "books" WHERE series_id = $1`)).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).
+			AddRow(3))
+
+	count, err := s.repository.CountBooks(1)
+
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), int64(3), count)
+}
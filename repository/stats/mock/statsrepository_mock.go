@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"github/brunojoenk/golang-test/models/dtos"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type StatsRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *StatsRepositoryMock) GetPublicationYearCounts(bucketSize int) ([]dtos.YearBookCount, error) {
+	args := m.Called(bucketSize)
+	return args.Get(0).([]dtos.YearBookCount), args.Error(1)
+}
+
+func (m *StatsRepositoryMock) GetProlificAuthors(pagination dtos.Pagination) ([]dtos.AuthorBookCount, error) {
+	args := m.Called(pagination)
+	return args.Get(0).([]dtos.AuthorBookCount), args.Error(1)
+}
+
+func (m *StatsRepositoryMock) GetBooksWithoutAuthors(pagination dtos.Pagination) ([]dtos.StatsBook, error) {
+	args := m.Called(pagination)
+	return args.Get(0).([]dtos.StatsBook), args.Error(1)
+}
+
+func (m *StatsRepositoryMock) GetAuthorsWithoutBooks(pagination dtos.Pagination) ([]dtos.StatsAuthor, error) {
+	args := m.Called(pagination)
+	return args.Get(0).([]dtos.StatsAuthor), args.Error(1)
+}
+
+func (m *StatsRepositoryMock) GetImportVolumes(kind, interval string) ([]dtos.ImportVolume, error) {
+	args := m.Called(kind, interval)
+	return args.Get(0).([]dtos.ImportVolume), args.Error(1)
+}
@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"github/brunojoenk/golang-test/models/dtos"
+	"github/brunojoenk/golang-test/models/entities"
+
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type IStatsRepository interface {
+	GetPublicationYearCounts(bucketSize int) ([]dtos.YearBookCount, error)
+	GetProlificAuthors(pagination dtos.Pagination) ([]dtos.AuthorBookCount, error)
+	GetBooksWithoutAuthors(pagination dtos.Pagination) ([]dtos.StatsBook, error)
+	GetAuthorsWithoutBooks(pagination dtos.Pagination) ([]dtos.StatsAuthor, error)
+	GetImportVolumes(kind, interval string) ([]dtos.ImportVolume, error)
+}
+
+// StatsRepository Catalog Statistics Repository
+type StatsRepository struct {
+	db *gorm.DB
+}
+
+// NewStatsRepository Repository Constructor
+func NewStatsRepository(db *gorm.DB) IStatsRepository {
+	return &StatsRepository{db: db}
+}
+
+// GetPublicationYearCounts counts the books per publication year rounded down to a multiple of the bucket size,
+// e.g. 10 for decades. Books without a publication year are left out
+func (s *StatsRepository) GetPublicationYearCounts(bucketSize int) ([]dtos.YearBookCount, error) {
+	counts := make([]dtos.YearBookCount, 0)
+
+	if result := s.db.Model(&entities.Book{}).
+		Select("publication_year / ? * ? AS year, COUNT(*) AS books", bucketSize, bucketSize).
+		Where("publication_year > 0").
+		Group("year").
+		Order("year").
+		Scan(&counts); result.Error != nil {
+		log.Error("Error on get publication year counts: ", result.Error.Error())
+		return nil, result.Error
+	}
+
+	return counts, nil
+}
+
+// GetProlificAuthors lists the authors with the most books written, ties by name
+func (s *StatsRepository) GetProlificAuthors(pagination dtos.Pagination) ([]dtos.AuthorBookCount, error) {
+	authors := make([]dtos.AuthorBookCount, 0)
+
+	if result := s.db.Model(&entities.Author{}).
+		Select("authors.id AS author_id, authors.name, COUNT(*) AS books").
+		Joins("JOIN author_book ON author_book.author_id = authors.id AND author_book.role = ?", dtos.RoleAuthor).
+		Group("authors.id").
+		Order("books DESC, authors.name ASC").
+		Offset((pagination.Page - 1) * pagination.Limit).
+		Limit(pagination.Limit).
+		Scan(&authors); result.Error != nil {
+		log.Error("Error on get prolific authors: ", result.Error.Error())
+		return nil, result.Error
+	}
+
+	return authors, nil
+}
+
+// GetBooksWithoutAuthors lists the books nobody is credited as author of, even when they have a translator
+func (s *StatsRepository) GetBooksWithoutAuthors(pagination dtos.Pagination) ([]dtos.StatsBook, error) {
+	books := make([]dtos.StatsBook, 0)
+
+	if result := s.db.Model(&entities.Book{}).
+		Select("id, name, publication_year").
+		Where("NOT EXISTS (SELECT 1 FROM author_book WHERE author_book.book_id = books.id AND author_book.role = ?)", dtos.RoleAuthor).
+		Order("id").
+		Offset((pagination.Page - 1) * pagination.Limit).
+		Limit(pagination.Limit).
+		Scan(&books); result.Error != nil {
+		log.Error("Error on get books without authors: ", result.Error.Error())
+		return nil, result.Error
+	}
+
+	return books, nil
+}
+
+// GetAuthorsWithoutBooks lists the authors not credited on any book, in any role
+func (s *StatsRepository) GetAuthorsWithoutBooks(pagination dtos.Pagination) ([]dtos.StatsAuthor, error) {
+	authors := make([]dtos.StatsAuthor, 0)
+
+	if result := s.db.Model(&entities.Author{}).
+		Select("id, name").
+		Where("NOT EXISTS (SELECT 1 FROM author_book WHERE author_book.author_id = authors.id)").
+		Order("name, id").
+		Offset((pagination.Page - 1) * pagination.Limit).
+		Limit(pagination.Limit).
+		Scan(&authors); result.Error != nil {
+		log.Error("Error on get authors without books: ", result.Error.Error())
+		return nil, result.Error
+	}
+
+	return authors, nil
+}
+
+// GetImportVolumes sums up the import runs per day, week, month or year, the oldest period first. An empty
+// kind sums up the runs of every kind
+func (s *StatsRepository) GetImportVolumes(kind, interval string) ([]dtos.ImportVolume, error) {
+	volumes := make([]dtos.ImportVolume, 0)
+
+	toExec := s.db.Model(&entities.ImportRun{}).
+		Select("date_trunc(?, finished_at) AS period, COUNT(*) AS runs, SUM(added) AS added", interval)
+	if kind != "" {
+		toExec = toExec.Where("kind = ?", kind)
+	}
+
+	if result := toExec.Group("period").Order("period").Scan(&volumes); result.Error != nil {
+		log.Error("Error on get import volumes: ", result.Error.Error())
+		return nil, result.Error
+	}
+
+	return volumes, nil
+}
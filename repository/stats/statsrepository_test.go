@@ -0,0 +1,199 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"github/brunojoenk/golang-test/models/dtos"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+type Suite struct {
+	suite.Suite
+	DB   *gorm.DB
+	mock sqlmock.Sqlmock
+
+	repository *StatsRepository
+}
+
+func (s *Suite) AfterTest(_, _ string) {
+	require.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func TestInit(t *testing.T) {
+	suite.Run(t, new(Suite))
+}
+
+func (s *Suite) SetupSuite() {
+	var (
+		db  *sql.DB
+		err error
+	)
+
+	db, s.mock, err = sqlmock.New()
+	require.NoError(s.T(), err)
+
+	dialector := postgres.New(postgres.Config{
+		DSN:                  "sqlmock_db_0",
+		DriverName:           "postgres",
+		Conn:                 db,
+		PreferSimpleProtocol: true,
+	})
+	s.DB, err = gorm.Open(dialector, &gorm.Config{})
+	require.NoError(s.T(), err)
+
+	s.repository = &StatsRepository{db: s.DB}
+}
+
+func (s *Suite) Test_repository_Get_Publication_Year_Counts() {
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT publication_year / $1 * $2 AS year, COUNT(*) AS books FROM "books" WHERE publication_year > 0 GROUP BY "year" ORDER BY year`)).
+		WithArgs(10, 10).
+		WillReturnRows(sqlmock.NewRows([]string{"year", "books"}).
+			AddRow(1990, 3).
+			AddRow(2000, 5))
+
+	counts, err := s.repository.GetPublicationYearCounts(10)
+
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), []dtos.YearBookCount{{Year: 1990, Books: 3}, {Year: 2000, Books: 5}}, counts)
+}
+
+func (s *Suite) Test_repository_Get_Publication_Year_Counts_Error() {
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT publication_year`)).
+		WillReturnError(context.Canceled)
+
+	_, err := s.repository.GetPublicationYearCounts(1)
+
+	require.Error(s.T(), err)
+}
+
+func (s *Suite) Test_repository_Get_Prolific_Authors() {
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT authors.id AS author_id, authors.name, COUNT(*) AS books FROM "authors" ` +
+			`JOIN author_book ON author_book.author_id = authors.id AND author_book.role = $1 ` +
+			`GROUP BY "authors"."id" ORDER BY books DESC, authors.name ASC LIMIT 10 OFFSET 10`)).
+		WithArgs(dtos.RoleAuthor).
+		WillReturnRows(sqlmock.NewRows([]string{"author_id", "name", "books"}).
+			AddRow(3, "Stephen King", 12).
+			AddRow(1, "J. K. Rowling", 7))
+
+	authors, err := s.repository.GetProlificAuthors(dtos.Pagination{Page: 2, Limit: 10})
+
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), []dtos.AuthorBookCount{
+		{AuthorId: 3, Name: "Stephen King", Books: 12},
+		{AuthorId: 1, Name: "J. K. Rowling", Books: 7},
+	}, authors)
+}
+
+func (s *Suite) Test_repository_Get_Prolific_Authors_Error() {
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT authors.id AS author_id`)).
+		WillReturnError(context.Canceled)
+
+	_, err := s.repository.GetProlificAuthors(dtos.Pagination{Page: 1, Limit: 10})
+
+	require.Error(s.T(), err)
+}
+
+func (s *Suite) Test_repository_Get_Books_Without_Authors() {
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT id, name, publication_year FROM "books" ` +
+			`WHERE NOT EXISTS (SELECT 1 FROM author_book WHERE author_book.book_id = books.id AND author_book.role = $1) ` +
+			`ORDER BY id LIMIT 10`)).
+		WithArgs(dtos.RoleAuthor).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "publication_year"}).
+			AddRow(4, "Beowulf", 1000))
+
+	books, err := s.repository.GetBooksWithoutAuthors(dtos.Pagination{Page: 1, Limit: 10})
+
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), []dtos.StatsBook{{Id: 4, Name: "Beowulf", PublicationYear: 1000}}, books)
+}
+
+func (s *Suite) Test_repository_Get_Books_Without_Authors_Error() {
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT id, name, publication_year FROM "books"`)).
+		WillReturnError(context.Canceled)
+
+	_, err := s.repository.GetBooksWithoutAuthors(dtos.Pagination{Page: 1, Limit: 10})
+
+	require.Error(s.T(), err)
+}
+
+func (s *Suite) Test_repository_Get_Authors_Without_Books() {
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT id, name FROM "authors" ` +
+			`WHERE NOT EXISTS (SELECT 1 FROM author_book WHERE author_book.author_id = authors.id) ` +
+			`ORDER BY name, id LIMIT 5 OFFSET 10`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).
+			AddRow(8, "Richard Bachman"))
+
+	authors, err := s.repository.GetAuthorsWithoutBooks(dtos.Pagination{Page: 3, Limit: 5})
+
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), []dtos.StatsAuthor{{Id: 8, Name: "Richard Bachman"}}, authors)
+}
+
+func (s *Suite) Test_repository_Get_Authors_Without_Books_Error() {
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT id, name FROM "authors"`)).
+		WillReturnError(context.Canceled)
+
+	_, err := s.repository.GetAuthorsWithoutBooks(dtos.Pagination{Page: 1, Limit: 10})
+
+	require.Error(s.T(), err)
+}
+
+func (s *Suite) Test_repository_Get_Import_Volumes() {
+	october := time.Date(2022, 10, 1, 0, 0, 0, 0, time.UTC)
+	november := time.Date(2022, 11, 1, 0, 0, 0, 0, time.UTC)
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT date_trunc($1, finished_at) AS period, COUNT(*) AS runs, SUM(added) AS added FROM "import_runs" `+
+			`WHERE kind = $2 GROUP BY "period" ORDER BY period`)).
+		WithArgs(dtos.StatsIntervalMonth, dtos.ImportKindAuthors).
+		WillReturnRows(sqlmock.NewRows([]string{"period", "runs", "added"}).
+			AddRow(october, 2, 120).
+			AddRow(november, 1, 6))
+
+	volumes, err := s.repository.GetImportVolumes(dtos.ImportKindAuthors, dtos.StatsIntervalMonth)
+
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), []dtos.ImportVolume{
+		{Period: october, Runs: 2, Added: 120},
+		{Period: november, Runs: 1, Added: 6},
+	}, volumes)
+}
+
+func (s *Suite) Test_repository_Get_Import_Volumes_Every_Kind() {
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT date_trunc($1, finished_at) AS period, COUNT(*) AS runs, SUM(added) AS added FROM "import_runs" ` +
+			`GROUP BY "period" ORDER BY period`)).
+		WithArgs(dtos.StatsIntervalDay).
+		WillReturnRows(sqlmock.NewRows([]string{"period", "runs", "added"}))
+
+	volumes, err := s.repository.GetImportVolumes("", dtos.StatsIntervalDay)
+
+	require.NoError(s.T(), err)
+	require.Empty(s.T(), volumes)
+}
+
+func (s *Suite) Test_repository_Get_Import_Volumes_Error() {
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT date_trunc`)).
+		WillReturnError(context.Canceled)
+
+	_, err := s.repository.GetImportVolumes("", dtos.StatsIntervalMonth)
+
+	require.Error(s.T(), err)
+}
@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"github/brunojoenk/golang-test/models/dtos"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type TagRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *TagRepositoryMock) GetTagCounts(pagination dtos.Pagination) ([]dtos.TagResponse, error) {
+	args := m.Called(pagination)
+	return args.Get(0).([]dtos.TagResponse), args.Error(1)
+}
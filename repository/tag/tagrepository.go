@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"github/brunojoenk/golang-test/models/dtos"
+	"github/brunojoenk/golang-test/models/entities"
+
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type ITagRepository interface {
+	GetTagCounts(pagination dtos.Pagination) ([]dtos.TagResponse, error)
+}
+
+// TagRepository Tags Repository
+type TagRepository struct {
+	db *gorm.DB
+}
+
+// NewTagRepository Repository Constructor
+func NewTagRepository(db *gorm.DB) ITagRepository {
+	return &TagRepository{db: db}
+}
+
+// GetTagCounts returns the tags used by at least one book, most used first
+func (t *TagRepository) GetTagCounts(pagination dtos.Pagination) ([]dtos.TagResponse, error) {
+	tags := make([]dtos.TagResponse, 0)
+
+	if result := t.db.Model(&entities.Tag{}).
+		Select("tags.name, COUNT(*) AS count").
+		Joins("JOIN book_tag ON book_tag.tag_id = tags.id").
+		Group("tags.id, tags.name").
+		Order("count desc, tags.name asc").
+		Offset((pagination.Page - 1) * pagination.Limit).Limit(pagination.Limit).
+		Scan(&tags); result.Error != nil {
+		log.Error("Error on get tag counts: ", result.Error.Error())
+		return nil, result.Error
+	}
+
+	return tags, nil
+}
@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"github/brunojoenk/golang-test/models/dtos"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+type Suite struct {
+	suite.Suite
+	DB   *gorm.DB
+	mock sqlmock.Sqlmock
+
+	repository *TagRepository
+}
+
+func (s *Suite) AfterTest(_, _ string) {
+	require.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func TestInit(t *testing.T) {
+	suite.Run(t, new(Suite))
+}
+
+func (s *Suite) SetupSuite() {
+	var (
+		db  *sql.DB
+		err error
+	)
+
+	db, s.mock, err = sqlmock.New()
+	require.NoError(s.T(), err)
+
+	dialector := postgres.New(postgres.Config{
+		DSN:                  "sqlmock_db_0",
+		DriverName:           "postgres",
+		Conn:                 db,
+		PreferSimpleProtocol: true,
+	})
+	s.DB, err = gorm.Open(dialector, &gorm.Config{})
+	require.NoError(s.T(), err)
+
+	s.repository = &TagRepository{db: s.DB}
+}
+
+func (s *Suite) Test_repository_Get_Tag_Counts() {
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT tags.name, COUNT(*) AS count FROM "tags" JOIN book_tag ON book_tag.tag_id = tags.id ` +
+			`GROUP BY tags.id, tags.name ORDER BY count desc, tags.name asc LIMIT 10`)).
+		WillReturnRows(sqlmock.NewRows([]string{"name", "count"}).
+			AddRow("dystopia", 3).
+			AddRow("classic", 1))
+
+	res, err := s.repository.GetTagCounts(dtos.Pagination{Page: 1, Limit: 10})
+
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), []dtos.TagResponse{{Name: "dystopia", Count: 3}, {Name: "classic", Count: 1}}, res)
+}
+
+func (s *Suite) Test_repository_Get_Tag_Counts_Error() {
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT tags.name, COUNT(*) AS count FROM "tags"`)).
+		WillReturnError(context.Canceled)
+
+	_, err := s.repository.GetTagCounts(dtos.Pagination{Page: 2, Limit: 10})
+
+	require.Error(s.T(), err)
+}
@@ -0,0 +1,32 @@
+package repository
+
+import (
+	"github/brunojoenk/golang-test/models/dtos"
+	"github/brunojoenk/golang-test/models/entities"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type WorkRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *WorkRepositoryMock) CreateWork(work entities.Work) (entities.Work, error) {
+	args := m.Called(work)
+	return args.Get(0).(entities.Work), args.Error(1)
+}
+
+func (m *WorkRepositoryMock) GetWork(id int) (entities.Work, error) {
+	args := m.Called(id)
+	return args.Get(0).(entities.Work), args.Error(1)
+}
+
+func (m *WorkRepositoryMock) GetWorksByNormalizedName(normalizedName string) ([]entities.Work, error) {
+	args := m.Called(normalizedName)
+	return args.Get(0).([]entities.Work), args.Error(1)
+}
+
+func (m *WorkRepositoryMock) GetAllWorks(filter dtos.GetWorksFilter) ([]entities.Work, error) {
+	args := m.Called(filter)
+	return args.Get(0).([]entities.Work), args.Error(1)
+}
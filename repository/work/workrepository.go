@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"github/brunojoenk/golang-test/models/dtos"
+	"github/brunojoenk/golang-test/models/entities"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type IWorkRepository interface {
+	CreateWork(work entities.Work) (entities.Work, error)
+	GetWork(id int) (entities.Work, error)
+	GetWorksByNormalizedName(normalizedName string) ([]entities.Work, error)
+	GetAllWorks(filter dtos.GetWorksFilter) ([]entities.Work, error)
+}
+
+// WorkRepository Works Repository
+type WorkRepository struct {
+	db *gorm.DB
+}
+
+// NewWorkRepository Repository Constructor
+func NewWorkRepository(db *gorm.DB) IWorkRepository {
+	return &WorkRepository{db: db}
+}
+
+// CreateWork creates the work and links it to its authors, which must already exist
+func (w *WorkRepository) CreateWork(work entities.Work) (entities.Work, error) {
+
+	if result := w.db.Omit("Authors.*").Create(&work); result.Error != nil {
+		log.Error("Error on create work: ", result.Error.Error())
+		return entities.Work{}, result.Error
+	}
+
+	return work, nil
+}
+
+func (w *WorkRepository) GetWork(id int) (entities.Work, error) {
+	var work entities.Work
+
+	if result := w.db.Preload("Authors").First(&work, id); result.Error != nil {
+		log.Error("Error on get work: ", result.Error.Error())
+		return work, result.Error
+	}
+
+	return work, nil
+}
+
+func (w *WorkRepository) GetWorksByNormalizedName(normalizedName string) ([]entities.Work, error) {
+	var works []entities.Work
+
+	if result := w.db.Where("normalized_name = ?", normalizedName).Order("id asc").
+		Preload("Authors").Find(&works); result.Error != nil {
+		log.Error("Error on get works by normalized name: ", result.Error.Error())
+		return nil, result.Error
+	}
+
+	return works, nil
+}
+
+func (w *WorkRepository) GetAllWorks(filter dtos.GetWorksFilter) ([]entities.Work, error) {
+
+	var works []entities.Work
+	toExec := w.db
+
+	if strings.TrimSpace(filter.Name) != "" {
+		toExec = toExec.Where("LOWER(name) LIKE ?", "%"+strings.ToLower(filter.Name)+"%")
+	}
+
+	toExec = toExec.Offset((filter.Page - 1) * filter.Limit).Limit(filter.Limit).Order("name asc")
+
+	if result := toExec.Preload("Authors").Find(&works); result.Error != nil {
+		log.Error("Error on get all works: ", result.Error.Error())
+		return nil, result.Error
+	}
+
+	return works, nil
+}
@@ -0,0 +1,194 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"github/brunojoenk/golang-test/models/dtos"
+	"github/brunojoenk/golang-test/models/entities"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-test/deep"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+type Suite struct {
+	suite.Suite
+	DB   *gorm.DB
+	mock sqlmock.Sqlmock
+
+	repository *WorkRepository
+}
+
+func (s *Suite) AfterTest(_, _ string) {
+	require.NoError(s.T(), s.mock.ExpectationsWereMet())
+}
+
+func TestInit(t *testing.T) {
+	suite.Run(t, new(Suite))
+}
+
+func (s *Suite) SetupSuite() {
+	var (
+		db  *sql.DB
+		err error
+	)
+
+	db, s.mock, err = sqlmock.New()
+	require.NoError(s.T(), err)
+
+	dialector := postgres.New(postgres.Config{
+		DSN:                  "sqlmock_db_0",
+		DriverName:           "postgres",
+		Conn:                 db,
+		PreferSimpleProtocol: true,
+	})
+	s.DB, err = gorm.Open(dialector, &gorm.Config{})
+	require.NoError(s.T(), err)
+
+	s.repository = &WorkRepository{db: s.DB}
+}
+
+func (s *Suite) Test_repository_Create_Work() {
+	var (
+		id       = 1
+		name     = "The Hobbit"
+		authorId = 5
+	)
+
+	s.mock.ExpectBegin()
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`INSERT INTO "works" ("name","normalized_name") VALUES ($1,$2) RETURNING "id"`)).
+		WithArgs(name, "the hobbit").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).
+			AddRow(id))
+
+	s.mock.ExpectExec(regexp.QuoteMeta(
+		`INSERT INTO "author_work" ("work_id","author_id") VALUES ($1,$2) ON CONFLICT DO NOTHING`)).
+		WithArgs(id, authorId).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	s.mock.ExpectCommit()
+
+	work, err := s.repository.CreateWork(entities.Work{Name: name, NormalizedName: "the hobbit", Authors: []entities.Author{{Id: authorId, Name: "J. R. R. Tolkien"}}})
+
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), id, work.Id)
+}
+
+func (s *Suite) Test_repository_Create_Work_Error() {
+	s.mock.ExpectBegin()
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`INSERT INTO "works" ("name","normalized_name") VALUES ($1,$2) RETURNING "id"`)).
+		WithArgs("The Hobbit", "the hobbit").
+		WillReturnError(context.Canceled)
+
+	s.mock.ExpectRollback()
+
+	_, err := s.repository.CreateWork(entities.Work{Name: "The Hobbit", NormalizedName: "the hobbit"})
+
+	require.Error(s.T(), err)
+}
+
+func (s *Suite) Test_repository_Get_Work() {
+	var (
+		id       = 1
+		authorId = 5
+	)
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "works" WHERE "works"."id" = $1 ORDER BY "works"."id" LIMIT 1`)).
+		WithArgs(id).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).
+			AddRow(id, "The Hobbit"))
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "author_work" WHERE "author_work"."work_id" = $1`)).
+		WithArgs(id).
+		WillReturnRows(sqlmock.NewRows([]string{"work_id", "author_id"}).
+			AddRow(id, authorId))
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "authors" WHERE "authors"."id" = $1`)).
+		WithArgs(authorId).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).
+			AddRow(authorId, "J. R. R. Tolkien"))
+
+	work, err := s.repository.GetWork(id)
+
+	require.NoError(s.T(), err)
+	require.Nil(s.T(), deep.Equal(entities.Work{Id: id, Name: "The Hobbit", Authors: []entities.Author{{Id: authorId, Name: "J. R. R. Tolkien"}}}, work))
+}
+
+func (s *Suite) Test_repository_Get_Work_Error() {
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "works" WHERE "works"."id" = $1 ORDER BY "works"."id" LIMIT 1`)).
+		WithArgs(1).
+		WillReturnError(gorm.ErrRecordNotFound)
+
+	_, err := s.repository.GetWork(1)
+
+	require.ErrorIs(s.T(), err, gorm.ErrRecordNotFound)
+}
+
+func (s *Suite) Test_repository_Get_Works_By_Normalized_Name() {
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "works" WHERE normalized_name = $1 ORDER BY id asc`)).
+		WithArgs("the hobbit").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).
+			AddRow(1, "The Hobbit").
+			AddRow(2, "The Hobbit"))
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "author_work" WHERE "author_work"."work_id" IN ($1,$2)`)).
+		WithArgs(1, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"work_id", "author_id"}).
+			AddRow(1, 5))
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "authors" WHERE "authors"."id" = $1`)).
+		WithArgs(5).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).
+			AddRow(5, "J. R. R. Tolkien"))
+
+	works, err := s.repository.GetWorksByNormalizedName("the hobbit")
+
+	require.NoError(s.T(), err)
+	require.Nil(s.T(), deep.Equal([]entities.Work{
+		{Id: 1, Name: "The Hobbit", Authors: []entities.Author{{Id: 5, Name: "J. R. R. Tolkien"}}},
+		{Id: 2, Name: "The Hobbit", Authors: []entities.Author{}}}, works))
+}
+
+func (s *Suite) Test_repository_Get_All_Works() {
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "works" WHERE LOWER(name) LIKE $1 ORDER BY name asc LIMIT 10`)).
+		WithArgs("%hobbit%").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).
+			AddRow(1, "The Hobbit"))
+
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "author_work" WHERE "author_work"."work_id" = $1`)).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"work_id", "author_id"}))
+
+	works, err := s.repository.GetAllWorks(dtos.GetWorksFilter{Name: "Hobbit", Pagination: dtos.Pagination{Page: 1, Limit: 10}})
+
+	require.NoError(s.T(), err)
+	require.Nil(s.T(), deep.Equal([]entities.Work{{Id: 1, Name: "The Hobbit", Authors: []entities.Author{}}}, works))
+}
+
+func (s *Suite) Test_repository_Get_All_Works_Error() {
+	s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT * FROM "works" ORDER BY name asc LIMIT 10`)).
+		WillReturnError(context.Canceled)
+
+	_, err := s.repository.GetAllWorks(dtos.GetWorksFilter{Pagination: dtos.Pagination{Page: 1, Limit: 10}})
+
+	require.Error(s.T(), err)
+}
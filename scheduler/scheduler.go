@@ -0,0 +1,61 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Job is a task the scheduler runs periodically, with the time of the run
+type Job struct {
+	Name string
+	Run  func(now time.Time) error
+}
+
+// Scheduler runs its jobs in the process, one after the other, once at start and then on every
+// interval. A job that fails or panics is logged and tried again on the next run
+type Scheduler struct {
+	interval time.Duration
+	jobs     []Job
+}
+
+// New Scheduler Constructor
+func New(interval time.Duration, jobs ...Job) *Scheduler {
+	return &Scheduler{interval: interval, jobs: jobs}
+}
+
+// Run blocks running the jobs until the context is done
+func (s *Scheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		s.RunOnce(time.Now())
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce runs every job once, for the time now
+func (s *Scheduler) RunOnce(now time.Time) {
+	for _, job := range s.jobs {
+		if err := runJob(job, now); err != nil {
+			log.Errorf("Error on scheduled job %s: %s", job.Name, err.Error())
+		}
+	}
+}
+
+func runJob(job Job, now time.Time) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+
+	return job.Run(now)
+}
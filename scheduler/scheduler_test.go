@@ -0,0 +1,66 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunOnce(t *testing.T) {
+	now := time.Now()
+	var ran []string
+	s := New(time.Hour,
+		Job{Name: "fails", Run: func(time.Time) error {
+			ran = append(ran, "fails")
+			return errors.New("generic error")
+		}},
+		Job{Name: "panics", Run: func(time.Time) error {
+			ran = append(ran, "panics")
+			panic("boom")
+		}},
+		Job{Name: "runs", Run: func(jobNow time.Time) error {
+			require.Equal(t, now, jobNow)
+			ran = append(ran, "runs")
+			return nil
+		}},
+	)
+
+	s.RunOnce(now)
+	require.Equal(t, []string{"fails", "panics", "runs"}, ran)
+}
+
+func TestRun(t *testing.T) {
+	var (
+		mu   sync.Mutex
+		runs int
+	)
+	ctx, cancel := context.WithCancel(context.Background())
+	s := New(10*time.Millisecond, Job{Name: "counts", Run: func(time.Time) error {
+		mu.Lock()
+		defer mu.Unlock()
+		runs++
+		if runs == 3 {
+			cancel()
+		}
+		return nil
+	}})
+
+	done := make(chan struct{})
+	go func() {
+		s.Run(ctx)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("scheduler did not stop")
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	require.GreaterOrEqual(t, runs, 3)
+}
@@ -0,0 +1,186 @@
+package services
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"github/brunojoenk/golang-test/config"
+	"github/brunojoenk/golang-test/models/dtos"
+	"github/brunojoenk/golang-test/models/entities"
+	apikeyrepo "github/brunojoenk/golang-test/repository/apikey"
+	"github/brunojoenk/golang-test/utils"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+const (
+	API_KEY_PREFIX      = "gt_"
+	API_KEY_RANDOM_SIZE = 32
+	API_KEY_PREFIX_SIZE = 8
+
+	// API_KEY_TOUCH_INTERVAL is how old the last use of a key must be to be updated
+	API_KEY_TOUCH_INTERVAL = time.Minute
+)
+
+var validScopes = map[string]bool{dtos.ScopeRead: true, dtos.ScopeWrite: true, dtos.ScopeAdmin: true}
+
+type IApiKeyService interface {
+	CreateApiKey(apiKeyRequestCreate dtos.ApiKeyRequestCreate) (dtos.ApiKeyCreatedResponse, error)
+	GetAllApiKeys(filter dtos.GetApiKeysFilter) (dtos.ApiKeyResponseMetadata, error)
+	RevokeApiKey(id int) error
+	Authenticate(key string) (dtos.ApiKeyPrincipal, error)
+}
+
+type apiKeyService struct {
+	apiKeyDb    apikeyrepo.IApiKeyRepository
+	adminApiKey string
+}
+
+// NewApiKeyService Service Constructor
+func NewApiKeyService(db *gorm.DB, cfg *config.Config) IApiKeyService {
+	return &apiKeyService{
+		apiKeyDb:    apikeyrepo.NewApiKeyRepository(db),
+		adminApiKey: cfg.AdminApiKey,
+	}
+}
+
+func (a *apiKeyService) CreateApiKey(apiKeyRequestCreate dtos.ApiKeyRequestCreate) (dtos.ApiKeyCreatedResponse, error) {
+	scopes := apiKeyRequestCreate.Scopes
+	if len(scopes) == 0 {
+		scopes = []string{dtos.ScopeRead}
+	}
+	for _, scope := range scopes {
+		if !validScopes[scope] {
+			return dtos.ApiKeyCreatedResponse{}, utils.ErrApiKeyScope
+		}
+	}
+
+	key, err := generateKey()
+	if err != nil {
+		log.Error("Error on generate api key: ", err.Error())
+		return dtos.ApiKeyCreatedResponse{}, err
+	}
+
+	apiKey := entities.ApiKey{
+		Name:      strings.TrimSpace(apiKeyRequestCreate.Name),
+		Owner:     strings.TrimSpace(apiKeyRequestCreate.Owner),
+		Prefix:    key[:len(API_KEY_PREFIX)+API_KEY_PREFIX_SIZE],
+		KeyHash:   hashKey(key),
+		Scopes:    strings.Join(scopes, ","),
+		ExpiresAt: apiKeyRequestCreate.ExpiresAt,
+	}
+
+	createdApiKey, err := a.apiKeyDb.CreateApiKey(apiKey)
+	if err != nil {
+		log.Error("Error on create api key from repo: ", err.Error())
+		return dtos.ApiKeyCreatedResponse{}, err
+	}
+
+	return dtos.ApiKeyCreatedResponse{ApiKeyResponse: toApiKeyResponse(createdApiKey), Key: key}, nil
+}
+
+func (a *apiKeyService) GetAllApiKeys(filter dtos.GetApiKeysFilter) (dtos.ApiKeyResponseMetadata, error) {
+
+	filter.Pagination.ValidValuesAndSetDefault()
+	apiKeys, err := a.apiKeyDb.GetAllApiKeys(filter)
+	if err != nil {
+		log.Error("Error on get all api keys from repo: ", err.Error())
+		return dtos.ApiKeyResponseMetadata{}, err
+	}
+
+	apiKeysResponse := make([]dtos.ApiKeyResponse, len(apiKeys))
+	for i, apiKey := range apiKeys {
+		apiKeysResponse[i] = toApiKeyResponse(apiKey)
+	}
+
+	return dtos.ApiKeyResponseMetadata{
+		ApiKeys:    apiKeysResponse,
+		Pagination: filter.Pagination,
+	}, nil
+}
+
+func (a *apiKeyService) RevokeApiKey(id int) error {
+	apiKey, err := a.apiKeyDb.GetApiKey(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return utils.ErrApiKeyIdNotFound
+		}
+		log.Error("Error on get api key from repo: ", err.Error())
+		return err
+	}
+
+	if apiKey.RevokedAt != nil {
+		return nil
+	}
+
+	return a.apiKeyDb.RevokeApiKey(id, time.Now())
+}
+
+func (a *apiKeyService) Authenticate(key string) (dtos.ApiKeyPrincipal, error) {
+	if a.adminApiKey != "" && subtle.ConstantTimeCompare([]byte(key), []byte(a.adminApiKey)) == 1 {
+		return dtos.ApiKeyPrincipal{Owner: "admin", Scopes: []string{dtos.ScopeAdmin}}, nil
+	}
+
+	apiKey, err := a.apiKeyDb.GetApiKeyByHash(hashKey(key))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return dtos.ApiKeyPrincipal{}, utils.ErrApiKeyInvalid
+		}
+		log.Error("Error on get api key by hash from repo: ", err.Error())
+		return dtos.ApiKeyPrincipal{}, err
+	}
+
+	now := time.Now()
+	if apiKey.RevokedAt != nil || (apiKey.ExpiresAt != nil && !apiKey.ExpiresAt.After(now)) {
+		return dtos.ApiKeyPrincipal{}, utils.ErrApiKeyInvalid
+	}
+
+	staleBefore := now.Add(-API_KEY_TOUCH_INTERVAL)
+	if apiKey.LastUsedAt == nil || apiKey.LastUsedAt.Before(staleBefore) {
+		if err := a.apiKeyDb.TouchApiKey(apiKey.Id, now, staleBefore); err != nil {
+			log.Warn("Error on update api key last used from repo: ", err.Error())
+		}
+	}
+
+	return dtos.ApiKeyPrincipal{Id: apiKey.Id, Owner: apiKey.Owner, Scopes: splitScopes(apiKey.Scopes)}, nil
+}
+
+func generateKey() (string, error) {
+	random := make([]byte, API_KEY_RANDOM_SIZE)
+	if _, err := rand.Read(random); err != nil {
+		return "", err
+	}
+	return API_KEY_PREFIX + base64.RawURLEncoding.EncodeToString(random), nil
+}
+
+func hashKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+func splitScopes(scopes string) []string {
+	if scopes == "" {
+		return []string{}
+	}
+	return strings.Split(scopes, ",")
+}
+
+func toApiKeyResponse(apiKey entities.ApiKey) dtos.ApiKeyResponse {
+	return dtos.ApiKeyResponse{
+		Id:         apiKey.Id,
+		Name:       apiKey.Name,
+		Owner:      apiKey.Owner,
+		Prefix:     apiKey.Prefix,
+		Scopes:     splitScopes(apiKey.Scopes),
+		ExpiresAt:  apiKey.ExpiresAt,
+		LastUsedAt: apiKey.LastUsedAt,
+		RevokedAt:  apiKey.RevokedAt,
+		CreatedAt:  apiKey.CreatedAt,
+	}
+}
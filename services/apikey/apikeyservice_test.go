@@ -0,0 +1,192 @@
+package services
+
+import (
+	"errors"
+	"github/brunojoenk/golang-test/models/dtos"
+	"github/brunojoenk/golang-test/models/entities"
+	apikeyrepomock "github/brunojoenk/golang-test/repository/apikey/mock"
+	"github/brunojoenk/golang-test/utils"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+var errGeneric = errors.New("generic error")
+
+func TestCreateApiKey(t *testing.T) {
+	tests := map[string]struct {
+		scopes                      []string
+		expectedScopes              string
+		expectedErrorOnCreateApiKey error
+		expectedErrorResponse       error
+	}{
+		"success on create api key": {
+			scopes:         []string{dtos.ScopeRead, dtos.ScopeWrite},
+			expectedScopes: "read,write",
+		},
+		"success on create api key (default scope)": {
+			expectedScopes: "read",
+		},
+		"error occurred on create api key (invalid scope)": {
+			scopes:                []string{"root"},
+			expectedErrorResponse: utils.ErrApiKeyScope,
+		},
+		"error occurred on create api key (create api key)": {
+			expectedScopes:              "read",
+			expectedErrorOnCreateApiKey: errGeneric,
+			expectedErrorResponse:       errGeneric,
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			apiKeyDbMock := new(apikeyrepomock.ApiKeyRepositoryMock)
+			apiKeyDbMock.On("CreateApiKey", mock.MatchedBy(func(apiKey entities.ApiKey) bool {
+				return apiKey.Owner == "jobs" && apiKey.Scopes == tc.expectedScopes && len(apiKey.KeyHash) == 64
+			})).Return(entities.ApiKey{Id: 1, Owner: "jobs", Scopes: tc.expectedScopes}, tc.expectedErrorOnCreateApiKey)
+
+			apiKeyServiceTest := apiKeyService{apiKeyDb: apiKeyDbMock}
+			resp, err := apiKeyServiceTest.CreateApiKey(dtos.ApiKeyRequestCreate{Name: "batch", Owner: " jobs ", Scopes: tc.scopes})
+			if tc.expectedErrorResponse != nil {
+				require.ErrorIs(t, err, tc.expectedErrorResponse)
+			} else {
+				require.NoError(t, err)
+				require.True(t, strings.HasPrefix(resp.Key, API_KEY_PREFIX))
+				require.Equal(t, strings.Split(tc.expectedScopes, ","), resp.Scopes)
+			}
+		})
+	}
+}
+
+func TestRevokeApiKey(t *testing.T) {
+	revokedAt := time.Now()
+	tests := map[string]struct {
+		apiKey                   entities.ApiKey
+		expectedErrorOnGetApiKey error
+		expectedErrorOnRevoke    error
+		expectedErrorResponse    error
+	}{
+		"success on revoke api key": {
+			apiKey: entities.ApiKey{Id: 1},
+		},
+		"success on revoke api key (already revoked)": {
+			apiKey: entities.ApiKey{Id: 1, RevokedAt: &revokedAt},
+		},
+		"error occurred on revoke api key (not found)": {
+			expectedErrorOnGetApiKey: gorm.ErrRecordNotFound,
+			expectedErrorResponse:    utils.ErrApiKeyIdNotFound,
+		},
+		"error occurred on revoke api key (revoke)": {
+			apiKey:                entities.ApiKey{Id: 1},
+			expectedErrorOnRevoke: errGeneric,
+			expectedErrorResponse: errGeneric,
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			apiKeyDbMock := new(apikeyrepomock.ApiKeyRepositoryMock)
+			apiKeyDbMock.On("GetApiKey", 1).Return(tc.apiKey, tc.expectedErrorOnGetApiKey)
+			apiKeyDbMock.On("RevokeApiKey", 1, mock.Anything).Return(tc.expectedErrorOnRevoke)
+
+			apiKeyServiceTest := apiKeyService{apiKeyDb: apiKeyDbMock}
+			err := apiKeyServiceTest.RevokeApiKey(1)
+			if tc.expectedErrorResponse != nil {
+				require.ErrorIs(t, err, tc.expectedErrorResponse)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestAuthenticate(t *testing.T) {
+	var (
+		key      = "gt_secret"
+		recent   = time.Now().Add(-time.Second)
+		past     = time.Now().Add(-time.Hour)
+		future   = time.Now().Add(time.Hour)
+		adminKey = "bootstrap"
+	)
+	tests := map[string]struct {
+		key                   string
+		apiKey                entities.ApiKey
+		expectedErrorOnGet    error
+		expectedErrorOnTouch  error
+		expectedTouch         bool
+		expectedPrincipal     dtos.ApiKeyPrincipal
+		expectedErrorResponse error
+	}{
+		"success on authenticate": {
+			key:               key,
+			apiKey:            entities.ApiKey{Id: 3, Owner: "jobs", Scopes: "read", ExpiresAt: &future},
+			expectedTouch:     true,
+			expectedPrincipal: dtos.ApiKeyPrincipal{Id: 3, Owner: "jobs", Scopes: []string{"read"}},
+		},
+		"success on authenticate (last used long ago)": {
+			key:               key,
+			apiKey:            entities.ApiKey{Id: 3, Owner: "jobs", Scopes: "read", LastUsedAt: &past},
+			expectedTouch:     true,
+			expectedPrincipal: dtos.ApiKeyPrincipal{Id: 3, Owner: "jobs", Scopes: []string{"read"}},
+		},
+		"success on authenticate (last used recently)": {
+			key:               key,
+			apiKey:            entities.ApiKey{Id: 3, Owner: "jobs", Scopes: "read", LastUsedAt: &recent},
+			expectedPrincipal: dtos.ApiKeyPrincipal{Id: 3, Owner: "jobs", Scopes: []string{"read"}},
+		},
+		"success on authenticate (touch fails)": {
+			key:                  key,
+			apiKey:               entities.ApiKey{Id: 3, Owner: "jobs", Scopes: "read"},
+			expectedErrorOnTouch: errGeneric,
+			expectedTouch:        true,
+			expectedPrincipal:    dtos.ApiKeyPrincipal{Id: 3, Owner: "jobs", Scopes: []string{"read"}},
+		},
+		"success on authenticate (admin bootstrap key)": {
+			key:               adminKey,
+			expectedPrincipal: dtos.ApiKeyPrincipal{Owner: "admin", Scopes: []string{dtos.ScopeAdmin}},
+		},
+		"error occurred on authenticate (unknown key)": {
+			key:                   key,
+			expectedErrorOnGet:    gorm.ErrRecordNotFound,
+			expectedErrorResponse: utils.ErrApiKeyInvalid,
+		},
+		"error occurred on authenticate (expired key)": {
+			key:                   key,
+			apiKey:                entities.ApiKey{Id: 3, ExpiresAt: &past},
+			expectedErrorResponse: utils.ErrApiKeyInvalid,
+		},
+		"error occurred on authenticate (revoked key)": {
+			key:                   key,
+			apiKey:                entities.ApiKey{Id: 3, RevokedAt: &past},
+			expectedErrorResponse: utils.ErrApiKeyInvalid,
+		},
+		"error occurred on authenticate (get by hash)": {
+			key:                   key,
+			expectedErrorOnGet:    errGeneric,
+			expectedErrorResponse: errGeneric,
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			apiKeyDbMock := new(apikeyrepomock.ApiKeyRepositoryMock)
+			apiKeyDbMock.On("GetApiKeyByHash", hashKey(tc.key)).Return(tc.apiKey, tc.expectedErrorOnGet)
+			apiKeyDbMock.On("TouchApiKey", tc.apiKey.Id, mock.Anything, mock.Anything).Return(tc.expectedErrorOnTouch)
+
+			apiKeyServiceTest := apiKeyService{apiKeyDb: apiKeyDbMock, adminApiKey: adminKey}
+			principal, err := apiKeyServiceTest.Authenticate(tc.key)
+			if tc.expectedErrorResponse != nil {
+				require.ErrorIs(t, err, tc.expectedErrorResponse)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tc.expectedPrincipal, principal)
+			}
+			if tc.expectedTouch {
+				apiKeyDbMock.AssertCalled(t, "TouchApiKey", tc.apiKey.Id, mock.Anything, mock.Anything)
+			} else {
+				apiKeyDbMock.AssertNotCalled(t, "TouchApiKey", mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package services
+
+import (
+	"github/brunojoenk/golang-test/models/dtos"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type ApiKeyServiceMock struct {
+	mock.Mock
+}
+
+func (m *ApiKeyServiceMock) CreateApiKey(apiKeyRequestCreate dtos.ApiKeyRequestCreate) (dtos.ApiKeyCreatedResponse, error) {
+	args := m.Called(apiKeyRequestCreate)
+	return args.Get(0).(dtos.ApiKeyCreatedResponse), args.Error(1)
+}
+
+func (m *ApiKeyServiceMock) GetAllApiKeys(filter dtos.GetApiKeysFilter) (dtos.ApiKeyResponseMetadata, error) {
+	args := m.Called(filter)
+	return args.Get(0).(dtos.ApiKeyResponseMetadata), args.Error(1)
+}
+
+func (m *ApiKeyServiceMock) RevokeApiKey(id int) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *ApiKeyServiceMock) Authenticate(key string) (dtos.ApiKeyPrincipal, error) {
+	args := m.Called(key)
+	return args.Get(0).(dtos.ApiKeyPrincipal), args.Error(1)
+}
@@ -2,10 +2,15 @@ package services
 
 import (
 	"encoding/csv"
+	"errors"
 	"github/brunojoenk/golang-test/models/dtos"
 	"github/brunojoenk/golang-test/models/entities"
 	authorrepo "github/brunojoenk/golang-test/repository/author"
+	"github/brunojoenk/golang-test/utils"
 	"os"
+	"sort"
+	"strings"
+	"time"
 
 	log "github.com/sirupsen/logrus"
 	"gorm.io/gorm"
@@ -13,9 +18,24 @@ import (
 
 var BATCH_SIZE_LIMIT = 2000
 
+// NAME_COLUMN in the first line of the csv file means one author per line, with the profile
+// fields in the other named columns. Otherwise every value of the file is an author name
+var NAME_COLUMN = "name"
+var BACKFILL_BATCH_SIZE = 500
+
+// DEFAULT_DUPLICATE_MIN_SCORE is the similarity from which two authors are reported as probable duplicates
+var DEFAULT_DUPLICATE_MIN_SCORE = 0.85
+
 type IAuthorService interface {
 	GetAllAuthors(filter dtos.GetAuthorsFilter) (dtos.AuthorResponseMetadata, error)
 	ImportAuthorsFromCSVFile(file string) (int, error)
+	GetDuplicateAuthors(filter dtos.GetAuthorDuplicatesFilter) (dtos.AuthorDuplicatesResponseMetadata, error)
+	MergeAuthors(targetId, sourceId int) (dtos.AuthorResponse, error)
+	BackfillNormalizedNames() error
+	GetAuthorAliases(authorId int) ([]dtos.AuthorAliasResponse, error)
+	CreateAuthorAlias(authorId int, aliasRequest dtos.AuthorAliasRequest) (dtos.AuthorAliasResponse, error)
+	UpdateAuthorAlias(authorId, aliasId int, aliasRequest dtos.AuthorAliasRequest) (dtos.AuthorAliasResponse, error)
+	DeleteAuthorAlias(authorId, aliasId int) error
 }
 
 type authorService struct {
@@ -30,6 +50,10 @@ func NewAuthorService(db *gorm.DB) IAuthorService {
 func (a *authorService) GetAllAuthors(filter dtos.GetAuthorsFilter) (dtos.AuthorResponseMetadata, error) {
 
 	filter.Pagination.ValidValuesAndSetDefault()
+	if err := a.validateFilter(&filter); err != nil {
+		return dtos.AuthorResponseMetadata{}, err
+	}
+
 	authors, err := a.authorDb.GetAllAuthors(filter)
 	if err != nil {
 		log.Error("Error on get all authors from repositoriy: ", err.Error())
@@ -38,11 +62,7 @@ func (a *authorService) GetAllAuthors(filter dtos.GetAuthorsFilter) (dtos.Author
 
 	authorsResponse := make([]dtos.AuthorResponse, len(authors))
 	for i, a := range authors {
-		authorResponse := dtos.AuthorResponse{
-			Id:   a.Id,
-			Name: a.Name,
-		}
-		authorsResponse[i] = authorResponse
+		authorsResponse[i] = toAuthorResponse(a)
 	}
 
 	authorResponseMetada := dtos.AuthorResponseMetadata{
@@ -55,6 +75,23 @@ func (a *authorService) GetAllAuthors(filter dtos.GetAuthorsFilter) (dtos.Author
 
 func (a *authorService) ImportAuthorsFromCSVFile(file string) (int, error) {
 
+	startedAt := time.Now()
+	totalAuthors, err := a.importAuthorsFromCSVFile(file)
+	if err != nil {
+		return totalAuthors, err
+	}
+
+	// The authors are already imported, so a failure to record the run is only logged
+	run := entities.ImportRun{Kind: dtos.ImportKindAuthors, Source: file, Added: totalAuthors, StartedAt: startedAt, FinishedAt: time.Now()}
+	if err := a.authorDb.CreateImportRun(run); err != nil {
+		log.Error("Error on create import run from repo: ", err.Error())
+	}
+
+	return totalAuthors, nil
+}
+
+func (a *authorService) importAuthorsFromCSVFile(file string) (int, error) {
+
 	f, err := os.Open(file)
 
 	if err != nil {
@@ -66,6 +103,7 @@ func (a *authorService) ImportAuthorsFromCSVFile(file string) (int, error) {
 
 	r := csv.NewReader(f)
 	r.Comma = ';'
+	r.FieldsPerRecord = -1
 
 	records, err := r.ReadAll()
 
@@ -74,6 +112,10 @@ func (a *authorService) ImportAuthorsFromCSVFile(file string) (int, error) {
 		return 0, err
 	}
 
+	if columns, ok := profileColumns(records); ok {
+		return a.importAuthorProfiles(records[1:], columns)
+	}
+
 	authorsAddedMap := make(map[string]bool, 0)
 	totalAuthors := 0
 	for _, record := range records {
@@ -91,17 +133,42 @@ func (a *authorService) processRecord(record []string, authorsAddedMap map[strin
 	totalAuthors := 0
 	batchToCreate := make([]entities.Author, 0)
 	for index, name := range record {
-		if a.isAuthorNotAdded(authorsAddedMap, name) {
-			authorsAddedMap[name] = true
-			batchToCreate = append(batchToCreate, entities.Author{Name: name})
+		name = utils.CleanAuthorName(name)
+		normalizedName := utils.NormalizeName(name)
+		if normalizedName != "" && a.isAuthorNotAdded(authorsAddedMap, normalizedName) {
+			authorsAddedMap[normalizedName] = true
+			batchToCreate = append(batchToCreate, entities.Author{Name: name, NormalizedName: normalizedName})
 		}
 		if a.canCreateInBatch(index, len(record), len(batchToCreate)) {
-			err := a.authorDb.CreateAuthorInBatch(batchToCreate, len(batchToCreate))
+			numAuthorsCreated, err := a.createNewAuthors(batchToCreate)
 			if err != nil {
-				log.Error("Error on create author in batch repository: ", err.Error())
 				return totalAuthors, err
 			}
-			totalAuthors += len(batchToCreate)
+			totalAuthors += numAuthorsCreated
+			batchToCreate = make([]entities.Author, 0)
+		}
+	}
+
+	return totalAuthors, nil
+}
+
+// importAuthorProfiles imports one author per row, reading the profile fields from the named columns
+func (a *authorService) importAuthorProfiles(rows [][]string, columns map[string]int) (int, error) {
+	authorsAddedMap := make(map[string]bool, 0)
+	totalAuthors := 0
+	batchToCreate := make([]entities.Author, 0)
+	for index, row := range rows {
+		author := authorFromRow(row, columns)
+		if author.NormalizedName != "" && a.isAuthorNotAdded(authorsAddedMap, author.NormalizedName) {
+			authorsAddedMap[author.NormalizedName] = true
+			batchToCreate = append(batchToCreate, author)
+		}
+		if a.canCreateInBatch(index, len(rows), len(batchToCreate)) {
+			numAuthorsCreated, err := a.createNewAuthors(batchToCreate)
+			if err != nil {
+				return 0, err
+			}
+			totalAuthors += numAuthorsCreated
 			batchToCreate = make([]entities.Author, 0)
 		}
 	}
@@ -109,6 +176,52 @@ func (a *authorService) processRecord(record []string, authorsAddedMap map[strin
 	return totalAuthors, nil
 }
 
+// createNewAuthors creates the authors of batch whose normalized name is not the name or an alias of an author yet
+func (a *authorService) createNewAuthors(batch []entities.Author) (int, error) {
+	normalizedNames := make([]string, len(batch))
+	for i, author := range batch {
+		normalizedNames[i] = author.NormalizedName
+	}
+
+	existingAuthors, err := a.authorDb.GetAuthorsByNormalizedNames(normalizedNames)
+	if err != nil {
+		log.Error("Error on get authors by normalized names from repository: ", err.Error())
+		return 0, err
+	}
+
+	existingAliases, err := a.authorDb.GetAliasesByNormalizedNames(normalizedNames)
+	if err != nil {
+		log.Error("Error on get author aliases by normalized names from repository: ", err.Error())
+		return 0, err
+	}
+
+	existing := make(map[string]bool, len(existingAuthors)+len(existingAliases))
+	for _, author := range existingAuthors {
+		existing[author.NormalizedName] = true
+	}
+	for _, alias := range existingAliases {
+		existing[alias.NormalizedName] = true
+	}
+
+	newAuthors := make([]entities.Author, 0, len(batch))
+	for _, author := range batch {
+		if !existing[author.NormalizedName] {
+			newAuthors = append(newAuthors, author)
+		}
+	}
+
+	if len(newAuthors) == 0 {
+		return 0, nil
+	}
+
+	if err := a.authorDb.CreateAuthorInBatch(newAuthors, len(newAuthors)); err != nil {
+		log.Error("Error on create author in batch repository: ", err.Error())
+		return 0, err
+	}
+
+	return len(newAuthors), nil
+}
+
 func (a *authorService) canCreateInBatch(index, recordSize, batchSize int) bool {
 	return a.isCounterEqualBatchSize(batchSize) || a.isLastItemToProcess(index, recordSize)
 }
@@ -124,3 +237,426 @@ func (a *authorService) isLastItemToProcess(index, recordSize int) bool {
 func (a *authorService) isAuthorNotAdded(authorsAddedMap map[string]bool, name string) bool {
 	return !authorsAddedMap[name]
 }
+
+// GetDuplicateAuthors compares the authors sharing a last name and returns the pairs whose
+// names are similar enough to be the same person, the most similar first
+func (a *authorService) GetDuplicateAuthors(filter dtos.GetAuthorDuplicatesFilter) (dtos.AuthorDuplicatesResponseMetadata, error) {
+
+	filter.Pagination.ValidValuesAndSetDefault()
+	if filter.MinScore <= 0 {
+		filter.MinScore = DEFAULT_DUPLICATE_MIN_SCORE
+	}
+
+	authors, err := a.authorDb.GetAuthorsWithSharedLastName()
+	if err != nil {
+		log.Error("Error on get authors with shared last name from repository: ", err.Error())
+		return dtos.AuthorDuplicatesResponseMetadata{}, err
+	}
+
+	candidates := make([]dtos.AuthorDuplicateCandidate, 0)
+	for start := 0; start < len(authors); {
+		end := start + 1
+		for end < len(authors) && lastName(authors[end].NormalizedName) == lastName(authors[start].NormalizedName) {
+			end++
+		}
+		for i := start; i < end; i++ {
+			for j := i + 1; j < end; j++ {
+				score := nameSimilarity(authors[i].NormalizedName, authors[j].NormalizedName)
+				if score >= filter.MinScore {
+					candidates = append(candidates, dtos.AuthorDuplicateCandidate{
+						Author: toAuthorResponse(authors[i]),
+						Other:  toAuthorResponse(authors[j]),
+						Score:  score,
+					})
+				}
+			}
+		}
+		start = end
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].Score > candidates[j].Score
+	})
+
+	first := (filter.Page - 1) * filter.Limit
+	if first > len(candidates) {
+		first = len(candidates)
+	}
+	last := first + filter.Limit
+	if last > len(candidates) {
+		last = len(candidates)
+	}
+
+	return dtos.AuthorDuplicatesResponseMetadata{
+		Candidates: candidates[first:last],
+		Pagination: filter.Pagination,
+	}, nil
+}
+
+// MergeAuthors folds the source author into the target one: the books of the source are linked
+// to the target, the source name becomes an alias of the target and the source is deleted
+func (a *authorService) MergeAuthors(targetId, sourceId int) (dtos.AuthorResponse, error) {
+	if targetId == sourceId {
+		return dtos.AuthorResponse{}, utils.ErrAuthorMergeSameId
+	}
+
+	target, err := a.getAuthor(targetId)
+	if err != nil {
+		return dtos.AuthorResponse{}, err
+	}
+
+	source, err := a.getAuthor(sourceId)
+	if err != nil {
+		return dtos.AuthorResponse{}, err
+	}
+
+	target.NormalizedName = utils.NormalizeAuthorName(target.Name)
+	source.NormalizedName = utils.NormalizeAuthorName(source.Name)
+	if err := a.authorDb.MergeAuthors(target, source); err != nil {
+		log.Error("Error on merge authors from repository: ", err.Error())
+		return dtos.AuthorResponse{}, err
+	}
+
+	return toAuthorResponse(target), nil
+}
+
+// BackfillNormalizedNames fills the normalized name of authors created before it existed
+func (a *authorService) BackfillNormalizedNames() error {
+	lastId := 0
+	for {
+		authors, err := a.authorDb.GetAuthorsWithoutNormalizedName(lastId, BACKFILL_BATCH_SIZE)
+		if err != nil {
+			log.Error("Error on get authors without normalized name from repository: ", err.Error())
+			return err
+		}
+
+		for _, author := range authors {
+			lastId = author.Id
+			normalizedName := utils.NormalizeAuthorName(author.Name)
+			if normalizedName == "" {
+				continue
+			}
+			if err := a.authorDb.UpdateNormalizedName(author.Id, normalizedName); err != nil {
+				log.Error("Error on update author normalized name from repository: ", err.Error())
+				return err
+			}
+		}
+
+		if len(authors) < BACKFILL_BATCH_SIZE {
+			return nil
+		}
+	}
+}
+
+func (a *authorService) GetAuthorAliases(authorId int) ([]dtos.AuthorAliasResponse, error) {
+	if _, err := a.getAuthor(authorId); err != nil {
+		return nil, err
+	}
+
+	aliases, err := a.authorDb.GetAliases(authorId)
+	if err != nil {
+		log.Error("Error on get author aliases from repository: ", err.Error())
+		return nil, err
+	}
+
+	aliasesResponse := make([]dtos.AuthorAliasResponse, len(aliases))
+	for i, alias := range aliases {
+		aliasesResponse[i] = toAuthorAliasResponse(alias)
+	}
+
+	return aliasesResponse, nil
+}
+
+func (a *authorService) CreateAuthorAlias(authorId int, aliasRequest dtos.AuthorAliasRequest) (dtos.AuthorAliasResponse, error) {
+	author, err := a.getAuthor(authorId)
+	if err != nil {
+		return dtos.AuthorAliasResponse{}, err
+	}
+
+	alias := entities.AuthorAlias{AuthorId: authorId}
+	if err := a.setAliasName(author, &alias, aliasRequest.Name); err != nil {
+		return dtos.AuthorAliasResponse{}, err
+	}
+
+	alias, err = a.authorDb.CreateAlias(alias)
+	if err != nil {
+		log.Error("Error on create author alias from repository: ", err.Error())
+		return dtos.AuthorAliasResponse{}, err
+	}
+
+	return toAuthorAliasResponse(alias), nil
+}
+
+func (a *authorService) UpdateAuthorAlias(authorId, aliasId int, aliasRequest dtos.AuthorAliasRequest) (dtos.AuthorAliasResponse, error) {
+	author, err := a.getAuthor(authorId)
+	if err != nil {
+		return dtos.AuthorAliasResponse{}, err
+	}
+
+	alias, err := a.getAlias(authorId, aliasId)
+	if err != nil {
+		return dtos.AuthorAliasResponse{}, err
+	}
+
+	if err := a.setAliasName(author, &alias, aliasRequest.Name); err != nil {
+		return dtos.AuthorAliasResponse{}, err
+	}
+
+	alias, err = a.authorDb.UpdateAlias(alias)
+	if err != nil {
+		log.Error("Error on update author alias from repository: ", err.Error())
+		return dtos.AuthorAliasResponse{}, err
+	}
+
+	return toAuthorAliasResponse(alias), nil
+}
+
+func (a *authorService) DeleteAuthorAlias(authorId, aliasId int) error {
+	alias, err := a.getAlias(authorId, aliasId)
+	if err != nil {
+		return err
+	}
+
+	if err := a.authorDb.DeleteAlias(alias.Id); err != nil {
+		log.Error("Error on delete author alias from repository: ", err.Error())
+		return err
+	}
+
+	return nil
+}
+
+// setAliasName sets the name of the alias, rejecting names equal to the name or to another alias
+// of the author, and names of other authors, which would make the import resolve them ambiguously
+func (a *authorService) setAliasName(author entities.Author, alias *entities.AuthorAlias, name string) error {
+	name = utils.CleanAuthorName(name)
+	normalizedName := utils.NormalizeName(name)
+	if normalizedName == "" {
+		return utils.ErrAuthorAliasNameEmpty
+	}
+
+	authors, err := a.authorDb.GetAuthorsByNormalizedNames([]string{normalizedName})
+	if err != nil {
+		log.Error("Error on get authors by normalized names from repository: ", err.Error())
+		return err
+	}
+	if len(authors) > 0 || utils.NormalizeAuthorName(author.Name) == normalizedName {
+		return utils.ErrAuthorAliasConflict
+	}
+
+	aliases, err := a.authorDb.GetAliasesByNormalizedNames([]string{normalizedName})
+	if err != nil {
+		log.Error("Error on get author aliases by normalized names from repository: ", err.Error())
+		return err
+	}
+	for _, existing := range aliases {
+		if existing.AuthorId == author.Id && existing.Id != alias.Id {
+			return utils.ErrAuthorAliasConflict
+		}
+	}
+
+	alias.Name = name
+	alias.NormalizedName = normalizedName
+	return nil
+}
+
+func (a *authorService) getAlias(authorId, aliasId int) (entities.AuthorAlias, error) {
+	alias, err := a.authorDb.GetAlias(authorId, aliasId)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return entities.AuthorAlias{}, utils.ErrAuthorAliasIdNotFound
+		}
+		log.Error("Error on get author alias from repository: ", err.Error())
+		return entities.AuthorAlias{}, err
+	}
+	return alias, nil
+}
+
+func (a *authorService) getAuthor(id int) (entities.Author, error) {
+	author, err := a.authorDb.GetAuthor(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return entities.Author{}, utils.ErrAuthorIdNotFound
+		}
+		log.Error("Error on get author from repository: ", err.Error())
+		return entities.Author{}, err
+	}
+	return author, nil
+}
+
+// validateFilter checks the profile filters and turns the birth dates into the bounds compared by the repository
+func (a *authorService) validateFilter(filter *dtos.GetAuthorsFilter) error {
+	if strings.TrimSpace(filter.Nationality) != "" {
+		nationality, err := utils.NormalizeCountryCode(filter.Nationality)
+		if err != nil {
+			return err
+		}
+		filter.Nationality = nationality
+	}
+
+	if strings.TrimSpace(filter.BornBefore) != "" {
+		bornBefore, err := utils.ParseDate(filter.BornBefore)
+		if err != nil {
+			return err
+		}
+		filter.BornBefore = bornBefore.Format(utils.DATE_LAYOUT)
+	}
+
+	if strings.TrimSpace(filter.BornAfter) != "" {
+		bornAfter, err := utils.ParseDate(filter.BornAfter)
+		if err != nil {
+			return err
+		}
+		// born after a year means born after its last day
+		if len(strings.TrimSpace(filter.BornAfter)) == len("2006") {
+			bornAfter = bornAfter.AddDate(1, 0, -1)
+		}
+		filter.BornAfter = bornAfter.Format(utils.DATE_LAYOUT)
+	}
+
+	return nil
+}
+
+func profileColumns(records [][]string) (map[string]int, bool) {
+	if len(records) == 0 {
+		return nil, false
+	}
+
+	columns := make(map[string]int, len(records[0]))
+	for i, column := range records[0] {
+		columns[strings.ToLower(strings.TrimSpace(column))] = i
+	}
+
+	_, ok := columns[NAME_COLUMN]
+	return columns, ok
+}
+
+// authorFromRow maps a csv row to an author. Invalid profile fields are logged and left blank,
+// so a typo in a column does not stop the import
+func authorFromRow(row []string, columns map[string]int) entities.Author {
+	value := func(column string) string {
+		if i, ok := columns[column]; ok && i < len(row) {
+			return strings.TrimSpace(row[i])
+		}
+		return ""
+	}
+
+	name := utils.CleanAuthorName(value(NAME_COLUMN))
+	author := entities.Author{Name: name, NormalizedName: utils.NormalizeName(name), Biography: value("biography")}
+
+	setField := func(column string, normalize func(string) (string, error), field *string) {
+		if raw := value(column); raw != "" {
+			normalized, err := normalize(raw)
+			if err != nil {
+				log.Warn("Ignoring ", column, " of author ", name, ": ", err.Error())
+				return
+			}
+			*field = normalized
+		}
+	}
+	setField("nationality", utils.NormalizeCountryCode, &author.Nationality)
+	setField("website", utils.NormalizeWebsite, &author.Website)
+	setField("orcid", utils.NormalizeOrcid, &author.Orcid)
+	setField("viaf", utils.NormalizeViaf, &author.Viaf)
+	setField("wikidata_id", utils.NormalizeWikidataId, &author.WikidataId)
+
+	setDate := func(column string, field **time.Time) {
+		if raw := value(column); raw != "" {
+			date, err := utils.ParseDate(raw)
+			if err != nil {
+				log.Warn("Ignoring ", column, " of author ", name, ": ", err.Error())
+				return
+			}
+			*field = &date
+		}
+	}
+	setDate("birth_date", &author.BirthDate)
+	setDate("death_date", &author.DeathDate)
+
+	return author
+}
+
+func toAuthorResponse(author entities.Author) dtos.AuthorResponse {
+	return dtos.AuthorResponse{
+		Id:          author.Id,
+		Name:        author.Name,
+		BirthDate:   formatDate(author.BirthDate),
+		DeathDate:   formatDate(author.DeathDate),
+		Nationality: author.Nationality,
+		Biography:   author.Biography,
+		Website:     author.Website,
+		Orcid:       author.Orcid,
+		Viaf:        author.Viaf,
+		WikidataId:  author.WikidataId,
+	}
+}
+
+func formatDate(date *time.Time) string {
+	if date == nil {
+		return ""
+	}
+	return date.Format(utils.DATE_LAYOUT)
+}
+
+func toAuthorAliasResponse(alias entities.AuthorAlias) dtos.AuthorAliasResponse {
+	return dtos.AuthorAliasResponse{
+		Id:       alias.Id,
+		AuthorId: alias.AuthorId,
+		Name:     alias.Name,
+	}
+}
+
+func lastName(normalizedName string) string {
+	return normalizedName[strings.LastIndex(normalizedName, " ")+1:]
+}
+
+// nameSimilarity scores two normalized names sharing the last name. Besides the Jaro-Winkler
+// similarity, first names written as initials count as matching ("j r r tolkien" and
+// "john ronald reuel tolkien"), a bit less when some of the middle names are missing
+func nameSimilarity(a, b string) float64 {
+	if a == b {
+		return 1
+	}
+
+	score := utils.JaroWinkler(a, b)
+
+	firstA, firstB := strings.Fields(a), strings.Fields(b)
+	firstA, firstB = firstA[:len(firstA)-1], firstB[:len(firstB)-1]
+	if len(firstA) > len(firstB) {
+		firstA, firstB = firstB, firstA
+	}
+	if len(firstA) == 0 || !sameInitial(firstA[0], firstB[0]) {
+		return score
+	}
+
+	// every first name of the shorter name must match, in order, a first name of the longer one
+	matched := 1
+	for _, name := range firstB[1:] {
+		if matched < len(firstA) && sameInitial(firstA[matched], name) {
+			matched++
+		}
+	}
+	if matched < len(firstA) {
+		return score
+	}
+
+	initialsScore := 0.95
+	if len(firstA) != len(firstB) {
+		initialsScore = 0.85
+	}
+
+	if initialsScore > score {
+		return initialsScore
+	}
+	return score
+}
+
+// sameInitial reports whether two first names are equal or one is the initial of the other
+func sameInitial(a, b string) bool {
+	if a == b {
+		return true
+	}
+	if len([]rune(a)) == 1 || len([]rune(b)) == 1 {
+		return []rune(a)[0] == []rune(b)[0]
+	}
+	return false
+}
@@ -4,11 +4,14 @@ import (
 	"errors"
 	"github/brunojoenk/golang-test/models/dtos"
 	"github/brunojoenk/golang-test/models/entities"
+	"github/brunojoenk/golang-test/utils"
 	"testing"
+	"time"
 
 	"github.com/go-test/deep"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
 
 	authorrepomock "github/brunojoenk/golang-test/repository/author/mock"
 )
@@ -51,20 +54,98 @@ func TestGetAllAuthors(t *testing.T) {
 	}
 }
 
+func TestGetAllAuthorsFilterProfile(t *testing.T) {
+	tests := map[string]struct {
+		filter                dtos.GetAuthorsFilter
+		expectedRepoFilter    dtos.GetAuthorsFilter
+		expectedErrorResponse error
+	}{
+		"success with nationality and full dates": {
+			filter:             dtos.GetAuthorsFilter{Nationality: "br", BornBefore: "1950-06-30", BornAfter: "1900-01-31"},
+			expectedRepoFilter: dtos.GetAuthorsFilter{Nationality: "BR", BornBefore: "1950-06-30", BornAfter: "1900-01-31"},
+		},
+		"success with years": {
+			filter:             dtos.GetAuthorsFilter{BornBefore: "1950", BornAfter: "1900"},
+			expectedRepoFilter: dtos.GetAuthorsFilter{BornBefore: "1950-01-01", BornAfter: "1900-12-31"},
+		},
+		"error on invalid nationality": {
+			filter:                dtos.GetAuthorsFilter{Nationality: "Brazil"},
+			expectedErrorResponse: utils.ErrInvalidCountryCode,
+		},
+		"error on invalid born before": {
+			filter:                dtos.GetAuthorsFilter{BornBefore: "30/06/1950"},
+			expectedErrorResponse: utils.ErrInvalidDate,
+		},
+		"error on invalid born after": {
+			filter:                dtos.GetAuthorsFilter{BornAfter: "19th century"},
+			expectedErrorResponse: utils.ErrInvalidDate,
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			authorDbMock := new(authorrepomock.AuthorRepositoryMock)
+
+			birthDate := time.Date(1920, time.May, 2, 0, 0, 0, 0, time.UTC)
+			tc.expectedRepoFilter.Pagination = dtos.Pagination{Page: 1, Limit: 10}
+			authorDbMock.On("GetAllAuthors", tc.expectedRepoFilter).
+				Return([]entities.Author{{Id: 5, Name: "Joenk", BirthDate: &birthDate, Nationality: "BR"}}, nil)
+
+			authorServiceTest := authorService{authorDb: authorDbMock}
+
+			resp, err := authorServiceTest.GetAllAuthors(tc.filter)
+			if tc.expectedErrorResponse != nil {
+				require.ErrorIs(t, err, tc.expectedErrorResponse)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, []dtos.AuthorResponse{{Id: 5, Name: "Joenk", BirthDate: "1920-05-02", Nationality: "BR"}}, resp.Authors)
+			}
+		})
+	}
+}
+
 func TestImportAuthorsFromCSVFile2(t *testing.T) {
 	filePath := "../../data/authorsreduced.csv"
 	tests := map[string]struct {
 		filePath                         string
+		existingAuthors                  []entities.Author
+		existingAliases                  []entities.AuthorAlias
 		totalAuthorsExpected             int
+		expectedErrorGetAuthorsByNames   error
+		expectedErrorGetAliasesByNames   error
 		expectedErrorCreateAuthorInBatch error
 		expectedErrorResponse            error
 	}{
 		"success on import all authors": {
 			filePath:             filePath,
+			existingAuthors:      []entities.Author{},
 			totalAuthorsExpected: 6,
 		},
+		"success on import authors skipping the ones already created": {
+			filePath:             filePath,
+			existingAuthors:      []entities.Author{{Id: 1, Name: "Rowling, J.K", NormalizedName: "j k rowling"}},
+			totalAuthorsExpected: 5,
+		},
+		"success on import authors skipping the ones known by an alias": {
+			filePath:             filePath,
+			existingAuthors:      []entities.Author{},
+			existingAliases:      []entities.AuthorAlias{{Id: 1, AuthorId: 7, Name: "David M. Beazley", NormalizedName: "david beazley"}},
+			totalAuthorsExpected: 5,
+		},
+		"error occurred on import all authors (get aliases by normalized names)": {
+			filePath:                       filePath,
+			existingAuthors:                []entities.Author{},
+			expectedErrorGetAliasesByNames: errGeneric,
+			expectedErrorResponse:          errGeneric,
+		},
+		"error occurred on import all authors (get authors by normalized names)": {
+			filePath:                       filePath,
+			existingAuthors:                []entities.Author{},
+			expectedErrorGetAuthorsByNames: errGeneric,
+			expectedErrorResponse:          errGeneric,
+		},
 		"error occurred on import all authors (create author in batch)": {
 			filePath:                         filePath,
+			existingAuthors:                  []entities.Author{},
 			totalAuthorsExpected:             0,
 			expectedErrorCreateAuthorInBatch: errGeneric,
 			expectedErrorResponse:            errGeneric,
@@ -74,7 +155,11 @@ func TestImportAuthorsFromCSVFile2(t *testing.T) {
 		t.Run(testName, func(t *testing.T) {
 			authorDbMock := new(authorrepomock.AuthorRepositoryMock)
 
+			authorDbMock.On("GetAuthorsByNormalizedNames", mock.Anything).Return(tc.existingAuthors, tc.expectedErrorGetAuthorsByNames)
+			authorDbMock.On("GetAliasesByNormalizedNames", mock.Anything).Return(tc.existingAliases, tc.expectedErrorGetAliasesByNames)
+			authorDbMock.On("CreateAuthorInBatch", mock.Anything, tc.totalAuthorsExpected).Return(tc.expectedErrorCreateAuthorInBatch)
 			authorDbMock.On("CreateAuthorInBatch", mock.Anything, 6).Return(tc.expectedErrorCreateAuthorInBatch)
+			authorDbMock.On("CreateImportRun", mock.Anything).Return(nil)
 
 			authorServiceTest := authorService{authorDb: authorDbMock}
 
@@ -89,6 +174,98 @@ func TestImportAuthorsFromCSVFile2(t *testing.T) {
 	}
 }
 
+func TestImportAuthorsFromCSVFileNormalizesNames(t *testing.T) {
+	authorDbMock := new(authorrepomock.AuthorRepositoryMock)
+
+	authorDbMock.On("GetAuthorsByNormalizedNames", mock.Anything).Return([]entities.Author{}, nil)
+	authorDbMock.On("GetAliasesByNormalizedNames", mock.Anything).Return([]entities.AuthorAlias{}, nil)
+	authorDbMock.On("CreateAuthorInBatch", mock.Anything, 6).Return(nil)
+	authorDbMock.On("CreateImportRun", mock.Anything).Return(nil)
+
+	authorServiceTest := authorService{authorDb: authorDbMock}
+
+	_, err := authorServiceTest.ImportAuthorsFromCSVFile("../../data/authorsreduced.csv")
+	require.NoError(t, err)
+
+	created := authorDbMock.Calls[2].Arguments.Get(0).([]entities.Author)
+	require.Contains(t, created, entities.Author{Name: "J. K. Rowling", NormalizedName: "j k rowling"})
+	require.Contains(t, created, entities.Author{Name: "Brian K. Jones", NormalizedName: "brian k jones"})
+}
+
+func TestImportAuthorsFromCSVFileWithProfiles(t *testing.T) {
+	authorDbMock := new(authorrepomock.AuthorRepositoryMock)
+
+	authorDbMock.On("GetAuthorsByNormalizedNames", mock.Anything).Return([]entities.Author{}, nil)
+	authorDbMock.On("GetAliasesByNormalizedNames", mock.Anything).Return([]entities.AuthorAlias{}, nil)
+	authorDbMock.On("CreateAuthorInBatch", mock.Anything, 4).Return(nil)
+	authorDbMock.On("CreateImportRun", mock.Anything).Return(nil)
+
+	authorServiceTest := authorService{authorDb: authorDbMock}
+
+	total, err := authorServiceTest.ImportAuthorsFromCSVFile("../../data/authorsprofile.csv")
+	require.NoError(t, err)
+	require.Equal(t, 4, total)
+
+	date := func(year int, month time.Month, day int) *time.Time {
+		d := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+		return &d
+	}
+	created := authorDbMock.Calls[2].Arguments.Get(0).([]entities.Author)
+	require.Nil(t, deep.Equal([]entities.Author{
+		{
+			Name:           "J. R. R. Tolkien",
+			NormalizedName: "j r r tolkien",
+			BirthDate:      date(1892, time.January, 3),
+			DeathDate:      date(1973, time.September, 2),
+			Nationality:    "GB",
+			Biography:      "English writer; philologist",
+			Website:        "https://www.tolkienestate.com",
+			Viaf:           "95218067",
+			WikidataId:     "Q892",
+		},
+		{Name: "Luciano Ramalho", NormalizedName: "luciano ramalho", Nationality: "BR"},
+		{
+			Name:           "Machado De Assis",
+			NormalizedName: "machado de assis",
+			BirthDate:      date(1839, time.June, 21),
+			DeathDate:      date(1908, time.September, 29),
+			Nationality:    "BR",
+			WikidataId:     "Q311145",
+		},
+		{Name: "Invalid Fields", NormalizedName: "invalid fields", BirthDate: date(1900, time.January, 1)},
+	}, created))
+}
+
+func TestImportAuthorsFromCSVFileRecordsRun(t *testing.T) {
+	tests := map[string]struct {
+		errorCreateImportRun error
+	}{
+		"success on record the import run":                   {},
+		"import succeeds when the run could not be recorded": {errorCreateImportRun: errGeneric},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			authorDbMock := new(authorrepomock.AuthorRepositoryMock)
+
+			authorDbMock.On("GetAuthorsByNormalizedNames", mock.Anything).Return([]entities.Author{}, nil)
+			authorDbMock.On("GetAliasesByNormalizedNames", mock.Anything).Return([]entities.AuthorAlias{}, nil)
+			authorDbMock.On("CreateAuthorInBatch", mock.Anything, 6).Return(nil)
+			authorDbMock.On("CreateImportRun", mock.MatchedBy(func(run entities.ImportRun) bool {
+				return run.Kind == dtos.ImportKindAuthors && run.Source == "../../data/authorsreduced.csv" &&
+					run.Added == 6 && !run.FinishedAt.Before(run.StartedAt)
+			})).Return(tc.errorCreateImportRun)
+
+			authorServiceTest := authorService{authorDb: authorDbMock}
+
+			total, err := authorServiceTest.ImportAuthorsFromCSVFile("../../data/authorsreduced.csv")
+
+			require.NoError(t, err)
+			require.Equal(t, 6, total)
+			authorDbMock.AssertCalled(t, "CreateImportRun", mock.Anything)
+		})
+	}
+}
+
 func TestImportAuthorsFromCSVFileError(t *testing.T) {
 	authorDbMock := new(authorrepomock.AuthorRepositoryMock)
 
@@ -97,3 +274,346 @@ func TestImportAuthorsFromCSVFileError(t *testing.T) {
 	_, err := authorServiceTest.ImportAuthorsFromCSVFile("anyfile")
 	require.Error(t, err)
 }
+
+func TestGetDuplicateAuthors(t *testing.T) {
+	authors := []entities.Author{
+		{Id: 5, Name: "Brian K. Jones", NormalizedName: "brian k jones"},
+		{Id: 6, Name: "Brian Jones", NormalizedName: "brian jones"},
+		{Id: 1, Name: "J. R. R. Tolkien", NormalizedName: "j r r tolkien"},
+		{Id: 2, Name: "John Ronald Reuel Tolkien", NormalizedName: "john ronald reuel tolkien"},
+		{Id: 3, Name: "Christopher Tolkien", NormalizedName: "christopher tolkien"},
+		{Id: 4, Name: "J. Tolkien", NormalizedName: "j tolkien"},
+	}
+
+	tests := map[string]struct {
+		filter                    dtos.GetAuthorDuplicatesFilter
+		expectedErrorOnGetAuthors error
+		expectedErrorResponse     error
+		expectedPairs             [][2]int
+	}{
+		"success with default min score": {
+			expectedPairs: [][2]int{{5, 6}, {1, 2}, {1, 4}, {2, 4}},
+		},
+		"success with a higher min score": {
+			filter:        dtos.GetAuthorDuplicatesFilter{MinScore: 0.9},
+			expectedPairs: [][2]int{{5, 6}, {1, 2}, {1, 4}},
+		},
+		"success on second page": {
+			filter:        dtos.GetAuthorDuplicatesFilter{Pagination: dtos.Pagination{Page: 2, Limit: 3}},
+			expectedPairs: [][2]int{{2, 4}},
+		},
+		"error occurred on get authors with shared last name": {
+			expectedErrorOnGetAuthors: errGeneric,
+			expectedErrorResponse:     errGeneric,
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			authorDbMock := new(authorrepomock.AuthorRepositoryMock)
+			authorDbMock.On("GetAuthorsWithSharedLastName").Return(authors, tc.expectedErrorOnGetAuthors)
+
+			authorServiceTest := authorService{authorDb: authorDbMock}
+
+			resp, err := authorServiceTest.GetDuplicateAuthors(tc.filter)
+			if tc.expectedErrorResponse != nil {
+				require.Equal(t, tc.expectedErrorResponse, err)
+				return
+			}
+
+			require.NoError(t, err)
+			pairs := make([][2]int, len(resp.Candidates))
+			for i, candidate := range resp.Candidates {
+				pairs[i] = [2]int{candidate.Author.Id, candidate.Other.Id}
+			}
+			require.Equal(t, tc.expectedPairs, pairs)
+		})
+	}
+}
+
+func TestNameSimilarity(t *testing.T) {
+	require.Equal(t, 1.0, nameSimilarity("j r r tolkien", "j r r tolkien"))
+	require.Equal(t, 0.95, nameSimilarity("j r r tolkien", "john ronald reuel tolkien"))
+	require.Equal(t, 0.85, nameSimilarity("j tolkien", "john ronald reuel tolkien"))
+	require.Less(t, nameSimilarity("christopher tolkien", "j r r tolkien"), DEFAULT_DUPLICATE_MIN_SCORE)
+}
+
+func TestMergeAuthors(t *testing.T) {
+	target := entities.Author{Id: 1, Name: "J. R. R. Tolkien", NormalizedName: "j r r tolkien"}
+	source := entities.Author{Id: 2, Name: "Tolkien, J.R.R."}
+
+	tests := map[string]struct {
+		targetId                 int
+		sourceId                 int
+		expectedErrorOnGetTarget error
+		expectedErrorOnGetSource error
+		expectedErrorOnMerge     error
+		expectedErrorResponse    error
+	}{
+		"success on merge authors": {
+			targetId: 1,
+			sourceId: 2,
+		},
+		"error when merging an author into itself": {
+			targetId:              1,
+			sourceId:              1,
+			expectedErrorResponse: utils.ErrAuthorMergeSameId,
+		},
+		"error when target author is not found": {
+			targetId:                 1,
+			sourceId:                 2,
+			expectedErrorOnGetTarget: gorm.ErrRecordNotFound,
+			expectedErrorResponse:    utils.ErrAuthorIdNotFound,
+		},
+		"error when source author is not found": {
+			targetId:                 1,
+			sourceId:                 2,
+			expectedErrorOnGetSource: gorm.ErrRecordNotFound,
+			expectedErrorResponse:    utils.ErrAuthorIdNotFound,
+		},
+		"error occurred on merge authors": {
+			targetId:              1,
+			sourceId:              2,
+			expectedErrorOnMerge:  errGeneric,
+			expectedErrorResponse: errGeneric,
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			authorDbMock := new(authorrepomock.AuthorRepositoryMock)
+			authorDbMock.On("GetAuthor", target.Id).Return(target, tc.expectedErrorOnGetTarget)
+			authorDbMock.On("GetAuthor", source.Id).Return(source, tc.expectedErrorOnGetSource)
+			authorDbMock.On("MergeAuthors", target, entities.Author{Id: 2, Name: "Tolkien, J.R.R.", NormalizedName: "j r r tolkien"}).
+				Return(tc.expectedErrorOnMerge)
+
+			authorServiceTest := authorService{authorDb: authorDbMock}
+
+			resp, err := authorServiceTest.MergeAuthors(tc.targetId, tc.sourceId)
+			if tc.expectedErrorResponse != nil {
+				require.ErrorIs(t, err, tc.expectedErrorResponse)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, dtos.AuthorResponse{Id: 1, Name: "J. R. R. Tolkien"}, resp)
+			}
+		})
+	}
+}
+
+func TestBackfillNormalizedNames(t *testing.T) {
+	tests := map[string]struct {
+		expectedErrorOnGetAuthors error
+		expectedErrorOnUpdate     error
+		expectedErrorResponse     error
+	}{
+		"success on backfill normalized names": {},
+		"error occurred on get authors without normalized name": {
+			expectedErrorOnGetAuthors: errGeneric,
+			expectedErrorResponse:     errGeneric,
+		},
+		"error occurred on update normalized name": {
+			expectedErrorOnUpdate: errGeneric,
+			expectedErrorResponse: errGeneric,
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			authorDbMock := new(authorrepomock.AuthorRepositoryMock)
+			authorDbMock.On("GetAuthorsWithoutNormalizedName", 0, BACKFILL_BATCH_SIZE).
+				Return([]entities.Author{{Id: 3, Name: "Tolkien, J.R.R."}, {Id: 4, Name: "..."}}, tc.expectedErrorOnGetAuthors)
+			authorDbMock.On("UpdateNormalizedName", 3, "j r r tolkien").Return(tc.expectedErrorOnUpdate)
+
+			authorServiceTest := authorService{authorDb: authorDbMock}
+
+			err := authorServiceTest.BackfillNormalizedNames()
+			require.Equal(t, tc.expectedErrorResponse, err)
+			if tc.expectedErrorResponse == nil {
+				authorDbMock.AssertNumberOfCalls(t, "UpdateNormalizedName", 1)
+			}
+		})
+	}
+}
+
+func TestGetAuthorAliases(t *testing.T) {
+	tests := map[string]struct {
+		expectedErrorOnGetAuthor  error
+		expectedErrorOnGetAliases error
+		expectedErrorResponse     error
+	}{
+		"success on get author aliases": {},
+		"error when author is not found": {
+			expectedErrorOnGetAuthor: gorm.ErrRecordNotFound,
+			expectedErrorResponse:    utils.ErrAuthorIdNotFound,
+		},
+		"error occurred on get aliases": {
+			expectedErrorOnGetAliases: errGeneric,
+			expectedErrorResponse:     errGeneric,
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			authorDbMock := new(authorrepomock.AuthorRepositoryMock)
+			authorDbMock.On("GetAuthor", 1).Return(entities.Author{Id: 1, Name: "Stephen King"}, tc.expectedErrorOnGetAuthor)
+			authorDbMock.On("GetAliases", 1).Return([]entities.AuthorAlias{{Id: 3, AuthorId: 1, Name: "Richard Bachman"}}, tc.expectedErrorOnGetAliases)
+
+			authorServiceTest := authorService{authorDb: authorDbMock}
+
+			resp, err := authorServiceTest.GetAuthorAliases(1)
+			if tc.expectedErrorResponse != nil {
+				require.ErrorIs(t, err, tc.expectedErrorResponse)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, []dtos.AuthorAliasResponse{{Id: 3, AuthorId: 1, Name: "Richard Bachman"}}, resp)
+			}
+		})
+	}
+}
+
+func TestCreateAuthorAlias(t *testing.T) {
+	author := entities.Author{Id: 1, Name: "Stephen King", NormalizedName: "stephen king"}
+	alias := entities.AuthorAlias{AuthorId: 1, Name: "Richard Bachman", NormalizedName: "richard bachman"}
+
+	tests := map[string]struct {
+		name                       string
+		authorsWithName            []entities.Author
+		aliasesWithName            []entities.AuthorAlias
+		expectedErrorOnGetAuthor   error
+		expectedErrorOnCreateAlias error
+		expectedErrorResponse      error
+	}{
+		"success on create author alias": {
+			name: "  BACHMAN, RICHARD ",
+		},
+		"success on create an alias shared with another author": {
+			name:            "Richard Bachman",
+			aliasesWithName: []entities.AuthorAlias{{Id: 9, AuthorId: 2, Name: "Richard Bachman", NormalizedName: "richard bachman"}},
+		},
+		"error when author is not found": {
+			name:                     "Richard Bachman",
+			expectedErrorOnGetAuthor: gorm.ErrRecordNotFound,
+			expectedErrorResponse:    utils.ErrAuthorIdNotFound,
+		},
+		"error when name is empty": {
+			name:                  " .. ",
+			expectedErrorResponse: utils.ErrAuthorAliasNameEmpty,
+		},
+		"error when name is the author name": {
+			name:                  "King, Stephen",
+			expectedErrorResponse: utils.ErrAuthorAliasConflict,
+		},
+		"error when name is the name of another author": {
+			name:                  "Richard Bachman",
+			authorsWithName:       []entities.Author{{Id: 2, Name: "Richard Bachman", NormalizedName: "richard bachman"}},
+			expectedErrorResponse: utils.ErrAuthorAliasConflict,
+		},
+		"error when name is already an alias of the author": {
+			name:                  "Richard Bachman",
+			aliasesWithName:       []entities.AuthorAlias{{Id: 9, AuthorId: 1, Name: "Richard Bachman", NormalizedName: "richard bachman"}},
+			expectedErrorResponse: utils.ErrAuthorAliasConflict,
+		},
+		"error occurred on create alias": {
+			name:                       "Richard Bachman",
+			expectedErrorOnCreateAlias: errGeneric,
+			expectedErrorResponse:      errGeneric,
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			authorDbMock := new(authorrepomock.AuthorRepositoryMock)
+			authorDbMock.On("GetAuthor", 1).Return(author, tc.expectedErrorOnGetAuthor)
+			authorDbMock.On("GetAuthorsByNormalizedNames", mock.Anything).Return(tc.authorsWithName, nil)
+			authorDbMock.On("GetAliasesByNormalizedNames", mock.Anything).Return(tc.aliasesWithName, nil)
+			created := alias
+			created.Id = 3
+			authorDbMock.On("CreateAlias", alias).Return(created, tc.expectedErrorOnCreateAlias)
+
+			authorServiceTest := authorService{authorDb: authorDbMock}
+
+			resp, err := authorServiceTest.CreateAuthorAlias(1, dtos.AuthorAliasRequest{Name: tc.name})
+			if tc.expectedErrorResponse != nil {
+				require.ErrorIs(t, err, tc.expectedErrorResponse)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, dtos.AuthorAliasResponse{Id: 3, AuthorId: 1, Name: "Richard Bachman"}, resp)
+			}
+		})
+	}
+}
+
+func TestUpdateAuthorAlias(t *testing.T) {
+	author := entities.Author{Id: 1, Name: "Stephen King", NormalizedName: "stephen king"}
+	alias := entities.AuthorAlias{Id: 3, AuthorId: 1, Name: "Richard Bachmann", NormalizedName: "richard bachmann"}
+	updated := entities.AuthorAlias{Id: 3, AuthorId: 1, Name: "Richard Bachman", NormalizedName: "richard bachman"}
+
+	tests := map[string]struct {
+		aliasesWithName            []entities.AuthorAlias
+		expectedErrorOnGetAlias    error
+		expectedErrorOnUpdateAlias error
+		expectedErrorResponse      error
+	}{
+		"success on update author alias": {},
+		"success on update an alias to its own name": {
+			aliasesWithName: []entities.AuthorAlias{updated},
+		},
+		"error when alias is not found": {
+			expectedErrorOnGetAlias: gorm.ErrRecordNotFound,
+			expectedErrorResponse:   utils.ErrAuthorAliasIdNotFound,
+		},
+		"error occurred on update alias": {
+			expectedErrorOnUpdateAlias: errGeneric,
+			expectedErrorResponse:      errGeneric,
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			authorDbMock := new(authorrepomock.AuthorRepositoryMock)
+			authorDbMock.On("GetAuthor", 1).Return(author, nil)
+			authorDbMock.On("GetAlias", 1, 3).Return(alias, tc.expectedErrorOnGetAlias)
+			authorDbMock.On("GetAuthorsByNormalizedNames", []string{"richard bachman"}).Return([]entities.Author{}, nil)
+			authorDbMock.On("GetAliasesByNormalizedNames", []string{"richard bachman"}).Return(tc.aliasesWithName, nil)
+			authorDbMock.On("UpdateAlias", updated).Return(updated, tc.expectedErrorOnUpdateAlias)
+
+			authorServiceTest := authorService{authorDb: authorDbMock}
+
+			resp, err := authorServiceTest.UpdateAuthorAlias(1, 3, dtos.AuthorAliasRequest{Name: "Richard Bachman"})
+			if tc.expectedErrorResponse != nil {
+				require.ErrorIs(t, err, tc.expectedErrorResponse)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, dtos.AuthorAliasResponse{Id: 3, AuthorId: 1, Name: "Richard Bachman"}, resp)
+			}
+		})
+	}
+}
+
+func TestDeleteAuthorAlias(t *testing.T) {
+	tests := map[string]struct {
+		expectedErrorOnGetAlias    error
+		expectedErrorOnDeleteAlias error
+		expectedErrorResponse      error
+	}{
+		"success on delete author alias": {},
+		"error when alias is not found": {
+			expectedErrorOnGetAlias: gorm.ErrRecordNotFound,
+			expectedErrorResponse:   utils.ErrAuthorAliasIdNotFound,
+		},
+		"error occurred on get alias": {
+			expectedErrorOnGetAlias: errGeneric,
+			expectedErrorResponse:   errGeneric,
+		},
+		"error occurred on delete alias": {
+			expectedErrorOnDeleteAlias: errGeneric,
+			expectedErrorResponse:      errGeneric,
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			authorDbMock := new(authorrepomock.AuthorRepositoryMock)
+			authorDbMock.On("GetAlias", 1, 3).Return(entities.AuthorAlias{Id: 3, AuthorId: 1}, tc.expectedErrorOnGetAlias)
+			authorDbMock.On("DeleteAlias", 3).Return(tc.expectedErrorOnDeleteAlias)
+
+			authorServiceTest := authorService{authorDb: authorDbMock}
+
+			err := authorServiceTest.DeleteAuthorAlias(1, 3)
+			require.ErrorIs(t, err, tc.expectedErrorResponse)
+		})
+	}
+}
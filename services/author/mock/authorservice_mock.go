@@ -19,3 +19,38 @@ func (m *AuthorServiceyMock) ImportAuthorsFromCSVFile(file string) (int, error)
 	args := m.Called(file)
 	return args.Get(0).(int), args.Error(1)
 }
+
+func (m *AuthorServiceyMock) GetDuplicateAuthors(filter dtos.GetAuthorDuplicatesFilter) (dtos.AuthorDuplicatesResponseMetadata, error) {
+	args := m.Called(filter)
+	return args.Get(0).(dtos.AuthorDuplicatesResponseMetadata), args.Error(1)
+}
+
+func (m *AuthorServiceyMock) MergeAuthors(targetId, sourceId int) (dtos.AuthorResponse, error) {
+	args := m.Called(targetId, sourceId)
+	return args.Get(0).(dtos.AuthorResponse), args.Error(1)
+}
+
+func (m *AuthorServiceyMock) BackfillNormalizedNames() error {
+	args := m.Called()
+	return args.Error(0)
+}
+
+func (m *AuthorServiceyMock) GetAuthorAliases(authorId int) ([]dtos.AuthorAliasResponse, error) {
+	args := m.Called(authorId)
+	return args.Get(0).([]dtos.AuthorAliasResponse), args.Error(1)
+}
+
+func (m *AuthorServiceyMock) CreateAuthorAlias(authorId int, aliasRequest dtos.AuthorAliasRequest) (dtos.AuthorAliasResponse, error) {
+	args := m.Called(authorId, aliasRequest)
+	return args.Get(0).(dtos.AuthorAliasResponse), args.Error(1)
+}
+
+func (m *AuthorServiceyMock) UpdateAuthorAlias(authorId, aliasId int, aliasRequest dtos.AuthorAliasRequest) (dtos.AuthorAliasResponse, error) {
+	args := m.Called(authorId, aliasId, aliasRequest)
+	return args.Get(0).(dtos.AuthorAliasResponse), args.Error(1)
+}
+
+func (m *AuthorServiceyMock) DeleteAuthorAlias(authorId, aliasId int) error {
+	args := m.Called(authorId, aliasId)
+	return args.Error(0)
+}
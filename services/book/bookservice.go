@@ -1,81 +1,193 @@
 package services
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
+	"github/brunojoenk/golang-test/config"
 	"github/brunojoenk/golang-test/models/dtos"
 	"github/brunojoenk/golang-test/models/entities"
 	authorrepo "github/brunojoenk/golang-test/repository/author"
 	bookrepo "github/brunojoenk/golang-test/repository/book"
+	bookfilerepo "github/brunojoenk/golang-test/repository/bookfile"
+	coverrepo "github/brunojoenk/golang-test/repository/cover"
+	genrerepo "github/brunojoenk/golang-test/repository/genre"
+	publisherrepo "github/brunojoenk/golang-test/repository/publisher"
+	seriesrepo "github/brunojoenk/golang-test/repository/series"
+	workrepo "github/brunojoenk/golang-test/repository/work"
+	coverservice "github/brunojoenk/golang-test/services/cover"
+	"github/brunojoenk/golang-test/storage"
 	"github/brunojoenk/golang-test/utils"
+	"sort"
+	"strings"
 
 	log "github.com/sirupsen/logrus"
 	"gorm.io/gorm"
 )
 
+const (
+	DUPLICATE_POLICY_REJECT = "reject"
+	DUPLICATE_POLICY_ALLOW  = "allow"
+
+	BOOK_NATURAL_KEY_INDEX = "idx_book_natural_key"
+	BOOK_ISBN13_INDEX      = "idx_book_isbn13"
+)
+
+var BACKFILL_BATCH_SIZE = 500
+
+var validRoles = map[string]bool{
+	dtos.RoleAuthor:      true,
+	dtos.RoleEditor:      true,
+	dtos.RoleTranslator:  true,
+	dtos.RoleIllustrator: true,
+}
+
 type IBookService interface {
 	CreateBook(bookRequestCreate dtos.BookRequestCreate) (dtos.BookResponse, error)
 	GetAllBooks(filter dtos.GetBooksFilter) (dtos.BookResponseMetadata, error)
 	DeleteBook(id int) error
 	GetBook(id int) (dtos.BookResponse, error)
+	GetBookByIsbn(isbn string) (dtos.BookResponse, error)
 	UpdateBook(id int, bookRequestUpdate dtos.BookRequestUpdate) (dtos.BookResponse, error)
+	GetDuplicateBooks(pagination dtos.Pagination) (dtos.BookDuplicatesResponseMetadata, error)
+	MergeBooks(targetId, sourceId int) (dtos.BookResponse, error)
+	BackfillNormalizedNames() error
+	BackfillWorks() error
+	AddRoleToContributorsKey() error
+	AddBookTags(id int, bookTagsRequest dtos.BookTagsRequest) (dtos.BookResponse, error)
+	RemoveBookTag(id int, tag string) error
 }
 
 type bookService struct {
-	authorDb authorrepo.IAuthorRepository
-	bookDb   bookrepo.IBookRepository
+	authorDb        authorrepo.IAuthorRepository
+	bookDb          bookrepo.IBookRepository
+	publisherDb     publisherrepo.IPublisherRepository
+	seriesDb        seriesrepo.ISeriesRepository
+	genreDb         genrerepo.IGenreRepository
+	workDb          workrepo.IWorkRepository
+	coverDb         coverrepo.ICoverRepository
+	bookFileDb      bookfilerepo.IBookFileRepository
+	store           storage.BlobStore
+	duplicatePolicy string
 }
 
 // NewBookService Service Constructor
-func NewBookService(db *gorm.DB) IBookService {
+func NewBookService(db *gorm.DB, cfg *config.Config, store storage.BlobStore) IBookService {
 	authorRepo := authorrepo.NewAuthorRepository(db)
 	bookRepo := bookrepo.NewBookRepository(db)
+	publisherRepo := publisherrepo.NewPublisherRepository(db)
+	seriesRepo := seriesrepo.NewSeriesRepository(db)
+	genreRepo := genrerepo.NewGenreRepository(db)
+	workRepo := workrepo.NewWorkRepository(db)
+	coverRepo := coverrepo.NewCoverRepository(db)
+	bookFileRepo := bookfilerepo.NewBookFileRepository(db)
 	return &bookService{
-		authorDb: authorRepo,
-		bookDb:   bookRepo,
+		authorDb:        authorRepo,
+		bookDb:          bookRepo,
+		publisherDb:     publisherRepo,
+		seriesDb:        seriesRepo,
+		genreDb:         genreRepo,
+		workDb:          workRepo,
+		coverDb:         coverRepo,
+		bookFileDb:      bookFileRepo,
+		store:           store,
+		duplicatePolicy: cfg.BookDuplicatePolicy,
 	}
 }
 
 func (b *bookService) CreateBook(bookRequestCreate dtos.BookRequestCreate) (dtos.BookResponse, error) {
-	var authors []entities.Author
-	for _, authorId := range bookRequestCreate.Authors {
-		author, err := b.authorDb.GetAuthor(authorId)
-		if err != nil {
-			if errors.Is(err, gorm.ErrRecordNotFound) {
-				return dtos.BookResponse{}, utils.ErrAuthorIdNotFound
-			}
-			log.Error("Error on get author from repo: ", err.Error())
-			return dtos.BookResponse{}, err
-		}
-		authors = append(authors, author)
+	contributors, err := b.getContributors(bookRequestCreate.Authors)
+	if err != nil {
+		return dtos.BookResponse{}, err
+	}
+
+	genres, err := b.getGenres(bookRequestCreate.Genres)
+	if err != nil {
+		return dtos.BookResponse{}, err
 	}
 
 	book := entities.Book{
 		Name:            bookRequestCreate.Name,
+		NormalizedName:  utils.NormalizeName(bookRequestCreate.Name),
 		Edition:         bookRequestCreate.Edition,
 		PublicationYear: bookRequestCreate.PublicationYear,
-		Authors:         authors,
+		Contributors:    contributors,
+		Genres:          genres,
 	}
 
-	createdBook, err := b.bookDb.CreateBook(book)
-	if err != nil {
-		log.Error("Error on create book from repo: ", err.Error())
+	if err := b.setIsbn(&book, bookRequestCreate.Isbn); err != nil {
+		return dtos.BookResponse{}, err
+	}
+
+	if err := setLanguage(&book, bookRequestCreate.Language, bookRequestCreate.Titles); err != nil {
+		return dtos.BookResponse{}, err
+	}
+
+	if err := b.setPublisher(&book, bookRequestCreate.PublisherId); err != nil {
+		return dtos.BookResponse{}, err
+	}
+
+	if err := b.setSeries(&book, bookRequestCreate.SeriesId, bookRequestCreate.SeriesPosition); err != nil {
+		return dtos.BookResponse{}, err
+	}
+
+	if err := b.checkDuplicate(book); err != nil {
+		return dtos.BookResponse{}, err
+	}
+
+	book.NaturalKey = b.naturalKey(book)
+
+	if err := b.setWork(&book, bookRequestCreate.WorkId); err != nil {
 		return dtos.BookResponse{}, err
 	}
 
-	dtosBookResponse := dtos.BookResponse{
-		Id:              createdBook.Id,
-		Name:            createdBook.Name,
-		Edition:         createdBook.Edition,
-		PublicationYear: createdBook.PublicationYear,
+	createdBook, err := b.bookDb.CreateBook(book)
+	if err != nil {
+		log.Error("Error on create book from repo: ", err.Error())
+		return dtos.BookResponse{}, b.uniqueError(book, err)
 	}
 
-	return dtosBookResponse, nil
+	return ToBookResponse(createdBook), nil
 }
 
 func (b *bookService) GetAllBooks(filter dtos.GetBooksFilter) (dtos.BookResponseMetadata, error) {
 
 	filter.Pagination.ValidValuesAndSetDefault()
+	if strings.TrimSpace(filter.Isbn) != "" {
+		isbn13, err := utils.NormalizeIsbn(filter.Isbn)
+		if err != nil {
+			return dtos.BookResponseMetadata{}, err
+		}
+		filter.Isbn = isbn13
+	}
+	filter.Genre = utils.NormalizeName(filter.Genre)
+	if strings.TrimSpace(filter.Language) != "" {
+		language, err := utils.NormalizeLanguage(filter.Language)
+		if err != nil {
+			return dtos.BookResponseMetadata{}, err
+		}
+		filter.Language = language
+	}
+	if strings.TrimSpace(filter.Tags) != "" {
+		tags, err := normalizeTags(strings.Split(filter.Tags, ","))
+		if err != nil {
+			return dtos.BookResponseMetadata{}, err
+		}
+		filter.Tags = strings.Join(tags, ",")
+	}
+	filter.TagsMatch = strings.ToLower(strings.TrimSpace(filter.TagsMatch))
+	if filter.TagsMatch != "" && filter.TagsMatch != dtos.TagsMatchAny && filter.TagsMatch != dtos.TagsMatchAll {
+		return dtos.BookResponseMetadata{}, utils.ErrInvalidTagsMatch
+	}
+	if filter.MinRating < 0 || filter.MinRating > dtos.MaxRating {
+		return dtos.BookResponseMetadata{}, utils.ErrInvalidMinRating
+	}
+	filter.Sort = strings.ToLower(strings.TrimSpace(filter.Sort))
+	if filter.Sort != "" && filter.Sort != dtos.BookSortName && filter.Sort != dtos.BookSortRating {
+		return dtos.BookResponseMetadata{}, utils.ErrInvalidBookSort
+	}
+
 	books, err := b.bookDb.GetAllBooks(filter)
 	if err != nil {
 		log.Error("Error on get all books from repo: ", err.Error())
@@ -84,25 +196,7 @@ func (b *bookService) GetAllBooks(filter dtos.GetBooksFilter) (dtos.BookResponse
 
 	booksResponse := make([]dtos.BookResponse, len(books))
 	for i, book := range books {
-
-		var authors string
-		for i, author := range book.Authors {
-			if i == 0 {
-				authors = author.Name
-				continue
-			}
-			authors += fmt.Sprintf(" | %s", author.Name)
-		}
-
-		bookResponse := dtos.BookResponse{
-			Id:              book.Id,
-			Name:            book.Name,
-			Edition:         book.Edition,
-			PublicationYear: book.PublicationYear,
-			Authors:         authors,
-		}
-
-		booksResponse[i] = bookResponse
+		booksResponse[i] = ToBookResponse(book)
 	}
 
 	booksResponseMetadata := dtos.BookResponseMetadata{
@@ -113,8 +207,37 @@ func (b *bookService) GetAllBooks(filter dtos.GetBooksFilter) (dtos.BookResponse
 	return booksResponseMetadata, nil
 }
 
+// DeleteBook deletes a book the library holds no copies of
 func (b *bookService) DeleteBook(id int) error {
-	return b.bookDb.DeleteBook(id)
+	copies, err := b.bookDb.CountCopies(id)
+	if err != nil {
+		log.Error("Error on count copies of book from repo: ", err.Error())
+		return err
+	}
+	if copies > 0 {
+		return utils.ErrBookHasCopies
+	}
+
+	// The cover images and files are removed with the book, the keys are read while they are still there
+	keys, err := b.coverKeys(id)
+	if err != nil {
+		return err
+	}
+	files, err := b.bookFileDb.GetBookFiles(id)
+	if err != nil {
+		log.Error("Error on get files of book from repo: ", err.Error())
+		return err
+	}
+	for _, file := range files {
+		keys = append(keys, file.Key)
+	}
+
+	if err := b.bookDb.DeleteBook(id); err != nil {
+		return err
+	}
+
+	b.deleteBlobs(keys)
+	return nil
 }
 
 func (b *bookService) GetBook(id int) (dtos.BookResponse, error) {
@@ -128,24 +251,25 @@ func (b *bookService) GetBook(id int) (dtos.BookResponse, error) {
 		return dtos.BookResponse{}, err
 	}
 
-	var authors string
-	for i, author := range book.Authors {
-		if i == 0 {
-			authors = author.Name
-			continue
-		}
-		authors += fmt.Sprintf(" | %s", author.Name)
+	return ToBookResponse(book), nil
+}
+
+func (b *bookService) GetBookByIsbn(isbn string) (dtos.BookResponse, error) {
+	isbn13, err := utils.NormalizeIsbn(isbn)
+	if err != nil {
+		return dtos.BookResponse{}, err
 	}
 
-	bookResponse := dtos.BookResponse{
-		Id:              book.Id,
-		Name:            book.Name,
-		Edition:         book.Edition,
-		PublicationYear: book.PublicationYear,
-		Authors:         authors,
+	book, err := b.bookDb.GetBookByIsbn(isbn13)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return dtos.BookResponse{}, utils.ErrBookIsbnNotFound
+		}
+		log.Error("Error on get book by isbn from repo: ", err.Error())
+		return dtos.BookResponse{}, err
 	}
 
-	return bookResponse, nil
+	return ToBookResponse(book), nil
 }
 
 func (b *bookService) UpdateBook(id int, bookRequestUpdate dtos.BookRequestUpdate) (dtos.BookResponse, error) {
@@ -156,47 +280,746 @@ func (b *bookService) UpdateBook(id int, bookRequestUpdate dtos.BookRequestUpdat
 		return dtos.BookResponse{}, err
 	}
 
-	var authors []entities.Author
-	for _, authorId := range bookRequestUpdate.Authors {
-		author, err := b.authorDb.GetAuthor(authorId)
-		if err != nil {
-			if errors.Is(err, gorm.ErrRecordNotFound) {
-				return dtos.BookResponse{}, utils.ErrAuthorIdNotFound
-			}
-			log.Error("Error on get author from repo: ", err.Error())
-			return dtos.BookResponse{}, err
-		}
-		authors = append(authors, author)
+	contributors, err := b.getContributors(bookRequestUpdate.Authors)
+	if err != nil {
+		return dtos.BookResponse{}, err
+	}
+
+	genres, err := b.getGenres(bookRequestUpdate.Genres)
+	if err != nil {
+		return dtos.BookResponse{}, err
 	}
 
+	previousNormalizedName := book.NormalizedName
 	book.Name = bookRequestUpdate.Name
+	book.NormalizedName = utils.NormalizeName(bookRequestUpdate.Name)
 	book.Edition = bookRequestUpdate.Edition
 	book.PublicationYear = bookRequestUpdate.PublicationYear
+	if err := b.setIsbn(&book, bookRequestUpdate.Isbn); err != nil {
+		return dtos.BookResponse{}, err
+	}
+	if err := setLanguage(&book, bookRequestUpdate.Language, bookRequestUpdate.Titles); err != nil {
+		return dtos.BookResponse{}, err
+	}
+	if err := b.setPublisher(&book, bookRequestUpdate.PublisherId); err != nil {
+		return dtos.BookResponse{}, err
+	}
+	if err := b.setSeries(&book, bookRequestUpdate.SeriesId, bookRequestUpdate.SeriesPosition); err != nil {
+		return dtos.BookResponse{}, err
+	}
+
+	candidate := book
+	candidate.Contributors = contributors
+	// An edition whose name or authors change moves to the work that has them, unless a work is given
+	if bookRequestUpdate.WorkId != 0 || book.NormalizedName != previousNormalizedName ||
+		!sameIds(authorIds(workAuthors(contributors)), authorIds(workAuthors(book.Contributors))) {
+		if err := b.setWork(&candidate, bookRequestUpdate.WorkId); err != nil {
+			return dtos.BookResponse{}, err
+		}
+		book.WorkId, book.Work = candidate.WorkId, candidate.Work
+	}
+
+	if err := b.checkDuplicate(candidate); err != nil {
+		return dtos.BookResponse{}, err
+	}
+	book.NaturalKey = b.naturalKey(candidate)
 
-	updatedBook, err := b.bookDb.UpdateBook(book, authors)
+	updatedBook, err := b.bookDb.UpdateBook(book, contributors, genres)
 
 	if err != nil {
 		log.Error("Error on update book from repo: ", err.Error())
+		return dtos.BookResponse{}, b.uniqueError(candidate, err)
+	}
+
+	return ToBookResponse(updatedBook), nil
+
+}
+
+func (b *bookService) GetDuplicateBooks(pagination dtos.Pagination) (dtos.BookDuplicatesResponseMetadata, error) {
+
+	pagination.ValidValuesAndSetDefault()
+	books, err := b.bookDb.GetDuplicateBooks(pagination)
+	if err != nil {
+		log.Error("Error on get duplicate books from repo: ", err.Error())
+		return dtos.BookDuplicatesResponseMetadata{}, err
+	}
+
+	clusters := make([]dtos.BookDuplicateCluster, 0)
+	for _, book := range books {
+		last := len(clusters) - 1
+		if last < 0 || clusters[last].NormalizedName != book.NormalizedName {
+			clusters = append(clusters, dtos.BookDuplicateCluster{NormalizedName: book.NormalizedName})
+			last++
+		}
+		clusters[last].Books = append(clusters[last].Books, ToBookResponse(book))
+	}
+
+	return dtos.BookDuplicatesResponseMetadata{
+		Clusters:   clusters,
+		Pagination: pagination,
+	}, nil
+}
+
+// MergeBooks folds the source book into the target one. The target keeps its data, filling
+// only blank fields from the source, and receives all the links of the source
+func (b *bookService) MergeBooks(targetId, sourceId int) (dtos.BookResponse, error) {
+	if targetId == sourceId {
+		return dtos.BookResponse{}, utils.ErrBookMergeSameId
+	}
+
+	target, err := b.getBook(targetId)
+	if err != nil {
+		return dtos.BookResponse{}, err
+	}
+
+	source, err := b.getBook(sourceId)
+	if err != nil {
+		return dtos.BookResponse{}, err
+	}
+
+	if strings.TrimSpace(target.Edition) == "" {
+		target.Edition = source.Edition
+	}
+	if target.Language == "" {
+		target.Language = source.Language
+	}
+	if target.PublicationYear == 0 {
+		target.PublicationYear = source.PublicationYear
+	}
+	if target.Isbn13 == nil {
+		target.Isbn13 = source.Isbn13
+	}
+	if target.WorkId == nil {
+		target.WorkId = source.WorkId
+	}
+	if target.PublisherId == nil {
+		target.PublisherId = source.PublisherId
+	}
+	if target.SeriesId == nil {
+		target.SeriesId = source.SeriesId
+		target.SeriesPosition = source.SeriesPosition
+	}
+	// The authors of both books are kept, so the natural key of target is the one of both sets of authors.
+	// Source is deleted in the merge, so only another book can have it
+	candidate := target
+	candidate.Contributors = append(append([]entities.AuthorBook{}, target.Contributors...), source.Contributors...)
+	if err := b.checkDuplicate(candidate, sourceId); err != nil {
+		return dtos.BookResponse{}, err
+	}
+	target.NaturalKey = b.naturalKey(candidate)
+	target.Titles = nil
+	target.Contributors = nil
+	target.Genres = nil
+	target.Tags = nil
+	target.Work = nil
+	target.Publisher = nil
+	target.Series = nil
+
+	// The cover of source moves to target when target has none, otherwise it is removed with source
+	targetKeys, err := b.coverKeys(targetId)
+	if err != nil {
+		return dtos.BookResponse{}, err
+	}
+	sourceKeys, err := b.coverKeys(sourceId)
+	if err != nil {
+		return dtos.BookResponse{}, err
+	}
+
+	if err := b.bookDb.MergeBooks(target, sourceId); err != nil {
+		log.Error("Error on merge books from repo: ", err.Error())
+		return dtos.BookResponse{}, b.uniqueError(candidate, err, sourceId)
+	}
+
+	if len(targetKeys) > 0 {
+		b.deleteBlobs(sourceKeys)
+	}
+
+	return b.GetBook(targetId)
+}
+
+// coverKeys lists the keys of the cover images of the book on the blob store, none when it has no cover
+func (b *bookService) coverKeys(bookId int) ([]string, error) {
+	cover, err := b.coverDb.GetCover(bookId)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		log.Error("Error on get cover of book from repo: ", err.Error())
+		return nil, err
+	}
+	return coverservice.ImageKeys(cover), nil
+}
+
+// deleteBlobs removes the blobs of a book deleted or merged. A failure only leaves files behind, so it
+// is logged and not returned
+func (b *bookService) deleteBlobs(keys []string) {
+	for _, key := range keys {
+		if err := b.store.Delete(key); err != nil {
+			log.Error("Error on delete blob of book from blob store: ", err.Error())
+		}
+	}
+}
+
+// BackfillNormalizedNames fills the normalized name of books created before it existed
+func (b *bookService) BackfillNormalizedNames() error {
+	lastId := 0
+	for {
+		books, err := b.bookDb.GetBooksWithoutNormalizedName(lastId, BACKFILL_BATCH_SIZE)
+		if err != nil {
+			log.Error("Error on get books without normalized name from repo: ", err.Error())
+			return err
+		}
+
+		for _, book := range books {
+			lastId = book.Id
+			normalizedName := utils.NormalizeName(book.Name)
+			if normalizedName == "" {
+				continue
+			}
+			if err := b.bookDb.UpdateNormalizedName(book.Id, normalizedName); err != nil {
+				log.Error("Error on update normalized name from repo: ", err.Error())
+				return err
+			}
+		}
+
+		if len(books) < BACKFILL_BATCH_SIZE {
+			return nil
+		}
+	}
+}
+
+// AddRoleToContributorsKey lets an author have more than one role on the books created before it could
+func (b *bookService) AddRoleToContributorsKey() error {
+	if err := b.bookDb.AddRoleToContributorsKey(); err != nil {
+		log.Error("Error on add role to contributors key from repo: ", err.Error())
+		return err
+	}
+	return nil
+}
+
+// BackfillWorks makes the books created before works existed editions of a work, grouping the books
+// with the same normalized name and authors
+func (b *bookService) BackfillWorks() error {
+	lastId := 0
+	for {
+		books, err := b.bookDb.GetBooksWithoutWork(lastId, BACKFILL_BATCH_SIZE)
+		if err != nil {
+			log.Error("Error on get books without work from repo: ", err.Error())
+			return err
+		}
+
+		for _, book := range books {
+			lastId = book.Id
+			work, err := b.findOrCreateWork(book)
+			if err != nil {
+				return err
+			}
+			if err := b.bookDb.UpdateWork(book.Id, work.Id); err != nil {
+				log.Error("Error on update book work from repo: ", err.Error())
+				return err
+			}
+		}
+
+		if len(books) < BACKFILL_BATCH_SIZE {
+			return nil
+		}
+	}
+}
+
+// AddBookTags adds the tags to the book, creating the ones that do not exist yet
+func (b *bookService) AddBookTags(id int, bookTagsRequest dtos.BookTagsRequest) (dtos.BookResponse, error) {
+	tags, err := normalizeTags(bookTagsRequest.Tags)
+	if err != nil {
+		return dtos.BookResponse{}, err
+	}
+	if len(tags) == 0 {
+		return dtos.BookResponse{}, utils.ErrInvalidTag
+	}
+
+	if _, err := b.getBook(id); err != nil {
+		return dtos.BookResponse{}, err
+	}
+
+	if err := b.bookDb.AddBookTags(id, tags); err != nil {
+		log.Error("Error on add tags to book from repo: ", err.Error())
 		return dtos.BookResponse{}, err
 	}
 
-	var authorsString string
-	for i, author := range updatedBook.Authors {
-		if i == 0 {
-			authorsString = author.Name
+	return b.GetBook(id)
+}
+
+func (b *bookService) RemoveBookTag(id int, tag string) error {
+	tag, err := utils.NormalizeTag(tag)
+	if err != nil {
+		return err
+	}
+
+	if _, err := b.getBook(id); err != nil {
+		return err
+	}
+
+	if err := b.bookDb.RemoveBookTag(id, tag); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return utils.ErrBookTagNotFound
+		}
+		log.Error("Error on remove tag from book from repo: ", err.Error())
+		return err
+	}
+
+	return nil
+}
+
+func (b *bookService) getBook(id int) (entities.Book, error) {
+	book, err := b.bookDb.GetBook(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return entities.Book{}, utils.ErrBookIdNotFound
+		}
+		log.Error("Error on get book from repo: ", err.Error())
+		return entities.Book{}, err
+	}
+	return book, nil
+}
+
+// getContributors links the authors of a request to a book in the order they were listed. Authors
+// without a role are credited as author
+func (b *bookService) getContributors(requests []dtos.BookAuthorRequest) ([]entities.AuthorBook, error) {
+	var contributors []entities.AuthorBook
+	seen := make(map[entities.AuthorBook]bool)
+	for i, request := range requests {
+		role := request.Role
+		if role == "" {
+			role = dtos.RoleAuthor
+		}
+		if !validRoles[role] {
+			return nil, utils.ErrInvalidAuthorRole
+		}
+		// An author can have several roles on a book, each once
+		key := entities.AuthorBook{AuthorId: request.Id, Role: role}
+		if seen[key] {
+			return nil, utils.ErrBookAuthorRepeated
+		}
+		seen[key] = true
+
+		author, err := b.authorDb.GetAuthor(request.Id)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, utils.ErrAuthorIdNotFound
+			}
+			log.Error("Error on get author from repo: ", err.Error())
+			return nil, err
+		}
+		contributors = append(contributors, entities.AuthorBook{AuthorId: author.Id, Author: author, Role: role, Position: i})
+	}
+	return contributors, nil
+}
+
+func (b *bookService) getGenres(genreIds []int) ([]entities.Genre, error) {
+	var genres []entities.Genre
+	for _, genreId := range genreIds {
+		genre, err := b.genreDb.GetGenre(genreId)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, utils.ErrGenreIdNotFound
+			}
+			log.Error("Error on get genre from repo: ", err.Error())
+			return nil, err
+		}
+		genres = append(genres, genre)
+	}
+	return genres, nil
+}
+
+// setIsbn validates the isbn, stores it as ISBN-13 and rejects it when another book already has it.
+// An empty isbn removes the ISBN of the book
+func (b *bookService) setIsbn(book *entities.Book, isbn string) error {
+	if strings.TrimSpace(isbn) == "" {
+		book.Isbn13 = nil
+		return nil
+	}
+
+	isbn13, err := utils.NormalizeIsbn(isbn)
+	if err != nil {
+		return err
+	}
+
+	existing, err := b.bookDb.GetBookByIsbn(isbn13)
+	if err == nil && existing.Id != book.Id {
+		return utils.ErrBookIsbnExists
+	}
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		log.Error("Error on get book by isbn from repo: ", err.Error())
+		return err
+	}
+
+	book.Isbn13 = &isbn13
+	return nil
+}
+
+// setLanguage sets the ISO 639 language the name of the book is written in and its titles translated
+// to other locales. An empty language leaves the book without one
+func setLanguage(book *entities.Book, language string, titles map[string]string) error {
+	book.Language = ""
+	if strings.TrimSpace(language) != "" {
+		code, err := utils.NormalizeLanguage(language)
+		if err != nil {
+			return err
+		}
+		book.Language = code
+	}
+
+	book.Titles = nil
+	for locale, title := range titles {
+		locale, err := utils.NormalizeLocale(locale)
+		if err != nil {
+			return err
+		}
+		title = strings.TrimSpace(title)
+		if title == "" {
+			return utils.ErrBookTitleEmpty
+		}
+		for _, other := range book.Titles {
+			if other.Locale == locale {
+				return utils.ErrBookTitleRepeated
+			}
+		}
+		book.Titles = append(book.Titles, entities.BookTitle{BookId: book.Id, Locale: locale, Title: title})
+	}
+	sort.Slice(book.Titles, func(i, j int) bool { return book.Titles[i].Locale < book.Titles[j].Locale })
+	return nil
+}
+
+// setPublisher links the book to the publisher. A zero publisherId removes the publisher of the book
+func (b *bookService) setPublisher(book *entities.Book, publisherId int) error {
+	if publisherId == 0 {
+		book.PublisherId = nil
+		book.Publisher = nil
+		return nil
+	}
+
+	publisher, err := b.publisherDb.GetPublisher(publisherId)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return utils.ErrPublisherIdNotFound
+		}
+		log.Error("Error on get publisher from repo: ", err.Error())
+		return err
+	}
+
+	book.PublisherId = &publisher.Id
+	book.Publisher = &publisher
+	return nil
+}
+
+// setSeries puts the book in the series at the position. A zero seriesId removes the book from its series,
+// and a zero position keeps it in the series with no position, listed after the numbered books
+func (b *bookService) setSeries(book *entities.Book, seriesId int, position float64) error {
+	if position < 0 || (seriesId == 0 && position != 0) {
+		return utils.ErrInvalidSeriesPosition
+	}
+
+	if seriesId == 0 {
+		book.SeriesId = nil
+		book.Series = nil
+		book.SeriesPosition = nil
+		return nil
+	}
+
+	series, err := b.seriesDb.GetSeries(seriesId)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return utils.ErrSeriesIdNotFound
+		}
+		log.Error("Error on get series from repo: ", err.Error())
+		return err
+	}
+
+	book.SeriesId = &series.Id
+	book.Series = &series
+	book.SeriesPosition = nil
+	if position > 0 {
+		book.SeriesPosition = &position
+	}
+	return nil
+}
+
+// setWork makes the book an edition of the work. Without a workId, the book joins the work with its
+// normalized name and authors
+func (b *bookService) setWork(book *entities.Book, workId int) error {
+	if workId == 0 {
+		work, err := b.findWork(*book)
+		if err != nil {
+			return err
+		}
+		// A new work is created along with the book, so a book that fails leaves no work behind
+		if work.Id == 0 {
+			book.WorkId, book.Work = nil, &work
+		} else {
+			book.WorkId, book.Work = &work.Id, nil
+		}
+		return nil
+	}
+
+	work, err := b.workDb.GetWork(workId)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return utils.ErrWorkIdNotFound
+		}
+		log.Error("Error on get work from repo: ", err.Error())
+		return err
+	}
+
+	book.WorkId, book.Work = &work.Id, nil
+	return nil
+}
+
+// findOrCreateWork returns the work with the normalized name and authors of the book, creating it when
+// there is none yet
+func (b *bookService) findOrCreateWork(book entities.Book) (entities.Work, error) {
+	work, err := b.findWork(book)
+	if err != nil || work.Id != 0 {
+		return work, err
+	}
+
+	work, err = b.workDb.CreateWork(work)
+	if err != nil {
+		log.Error("Error on create work from repo: ", err.Error())
+		return entities.Work{}, err
+	}
+	return work, nil
+}
+
+// findWork returns the work with the normalized name and authors of the book, or a new one, not saved
+// yet, when there is none. Editors, translators and illustrators are credited on the edition, not on
+// the work
+func (b *bookService) findWork(book entities.Book) (entities.Work, error) {
+	authors := workAuthors(book.Contributors)
+
+	works, err := b.workDb.GetWorksByNormalizedName(book.NormalizedName)
+	if err != nil {
+		log.Error("Error on get works by normalized name from repo: ", err.Error())
+		return entities.Work{}, err
+	}
+	for _, work := range works {
+		if sameIds(authorIds(work.Authors), authorIds(authors)) {
+			return work, nil
+		}
+	}
+
+	return entities.Work{Name: book.Name, NormalizedName: book.NormalizedName, Authors: authors}, nil
+}
+
+// workAuthors are the contributors credited as authors, the ones that belong to the work
+func workAuthors(contributors []entities.AuthorBook) []entities.Author {
+	var authors []entities.Author
+	for _, contributor := range contributors {
+		if contributor.Role == dtos.RoleAuthor || contributor.Role == "" {
+			authors = append(authors, contributor.Author)
+		}
+	}
+	return authors
+}
+
+// checkDuplicate rejects, when the policy says so, a book whose normalized name, edition,
+// publication year and set of authors are equal to another book. The books merged into it are not
+// counted
+func (b *bookService) checkDuplicate(book entities.Book, mergedIds ...int) error {
+	if b.duplicatePolicy != DUPLICATE_POLICY_REJECT {
+		return nil
+	}
+
+	candidates, err := b.bookDb.GetBooksByNaturalKey(book.NormalizedName, book.Edition, book.PublicationYear)
+	if err != nil {
+		log.Error("Error on get books by natural key from repo: ", err.Error())
+		return err
+	}
+
+	for _, candidate := range candidates {
+		if candidate.Id == book.Id || containsId(mergedIds, candidate.Id) {
 			continue
 		}
-		authorsString += fmt.Sprintf(" | %s", author.Name)
+		if sameIds(contributorIds(candidate.Contributors), contributorIds(book.Contributors)) && !differentIsbn(candidate, book) {
+			return utils.BookDuplicatedError{ExistingBookId: candidate.Id}
+		}
+	}
+
+	return nil
+}
+
+// naturalKey is the hash of what checkDuplicate compares: the normalized name, edition, publication
+// year and set of authors. It is only kept on books without ISBN when duplicates are rejected, so
+// idx_book_natural_key rejects one saved at the same time as its duplicate, both passing checkDuplicate.
+// Books with ISBN are kept apart by idx_book_isbn13
+func (b *bookService) naturalKey(book entities.Book) *string {
+	if b.duplicatePolicy != DUPLICATE_POLICY_REJECT || book.Isbn13 != nil {
+		return nil
 	}
 
-	dtosBookResponse := dtos.BookResponse{
-		Id:              updatedBook.Id,
-		Name:            updatedBook.Name,
-		Edition:         updatedBook.Edition,
-		PublicationYear: updatedBook.PublicationYear,
-		Authors:         authorsString,
+	ids := []int{}
+	seen := make(map[int]bool)
+	for _, id := range contributorIds(book.Contributors) {
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
 	}
+	sort.Ints(ids)
 
-	return dtosBookResponse, nil
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s\x00%s\x00%d\x00%v", book.NormalizedName,
+		strings.ToLower(strings.TrimSpace(book.Edition)), book.PublicationYear, ids)))
+	key := hex.EncodeToString(sum[:])
+	return &key
+}
+
+// uniqueError maps the violation of a unique index by a book saved at the same time as another to the
+// error the checks before saving give once the other book is there
+func (b *bookService) uniqueError(book entities.Book, err error, mergedIds ...int) error {
+	switch {
+	case utils.IsUniqueViolation(err, BOOK_ISBN13_INDEX):
+		return utils.ErrBookIsbnExists
+	case utils.IsUniqueViolation(err, BOOK_NATURAL_KEY_INDEX):
+		if duplicated := b.checkDuplicate(book, mergedIds...); duplicated != nil {
+			return duplicated
+		}
+		return utils.BookDuplicatedError{}
+	}
+	return err
+}
+
+// normalizeTags normalizes the tags and drops the repeated ones, keeping their order
+func normalizeTags(tags []string) ([]string, error) {
+	var normalized []string
+	seen := make(map[string]bool)
+	for _, tag := range tags {
+		tag, err := utils.NormalizeTag(tag)
+		if err != nil {
+			return nil, err
+		}
+		if !seen[tag] {
+			seen[tag] = true
+			normalized = append(normalized, tag)
+		}
+	}
+	return normalized, nil
+}
+
+// differentIsbn tells printings that share name, edition and year but are told apart by their ISBN
+func differentIsbn(book, other entities.Book) bool {
+	return book.Isbn13 != nil && other.Isbn13 != nil && *book.Isbn13 != *other.Isbn13
+}
+
+func sameIds(ids, others []int) bool {
+	set := make(map[int]bool, len(ids))
+	for _, id := range ids {
+		set[id] = true
+	}
+	otherSet := make(map[int]bool, len(others))
+	for _, id := range others {
+		if !set[id] {
+			return false
+		}
+		otherSet[id] = true
+	}
+	return len(set) == len(otherSet)
+}
+
+func containsId(ids []int, id int) bool {
+	for _, other := range ids {
+		if other == id {
+			return true
+		}
+	}
+	return false
+}
+
+func contributorIds(contributors []entities.AuthorBook) []int {
+	ids := make([]int, len(contributors))
+	for i, contributor := range contributors {
+		ids[i] = contributor.AuthorId
+	}
+	return ids
+}
+
+func authorIds(authors []entities.Author) []int {
+	ids := make([]int, len(authors))
+	for i, author := range authors {
+		ids[i] = author.Id
+	}
+	return ids
+}
+
+// ToBookResponse maps a book entity to the response returned by the book endpoints. Authors lists only
+// the contributors with the role author, all of them are in Contributors grouped by role
+func ToBookResponse(book entities.Book) dtos.BookResponse {
+	var contributors dtos.BookContributorsResponse
+	for _, contributor := range book.Contributors {
+		switch contributor.Role {
+		case dtos.RoleEditor:
+			contributors.Editors = append(contributors.Editors, contributor.Author.Name)
+		case dtos.RoleTranslator:
+			contributors.Translators = append(contributors.Translators, contributor.Author.Name)
+		case dtos.RoleIllustrator:
+			contributors.Illustrators = append(contributors.Illustrators, contributor.Author.Name)
+		default:
+			contributors.Authors = append(contributors.Authors, contributor.Author.Name)
+		}
+	}
+
+	genres := make([]string, len(book.Genres))
+	for i, genre := range book.Genres {
+		genres[i] = genre.Name
+	}
+
+	bookResponse := dtos.BookResponse{
+		Id:              book.Id,
+		Name:            book.Name,
+		Language:        book.Language,
+		Edition:         book.Edition,
+		PublicationYear: book.PublicationYear,
+		Authors:         strings.Join(contributors.Authors, " | "),
+		Contributors:    contributors,
+		Genres:          strings.Join(genres, " | "),
+		Rating:          dtos.BookRatingResponse{Average: book.RatingAverage, Count: book.RatingCount},
+	}
+	if book.Isbn13 != nil {
+		bookResponse.Isbn13 = *book.Isbn13
+		bookResponse.Isbn10 = utils.Isbn13To10(*book.Isbn13)
+	}
+	if book.WorkId != nil {
+		bookResponse.WorkId = *book.WorkId
+	}
+	if book.Publisher != nil {
+		bookResponse.PublisherId = book.Publisher.Id
+		bookResponse.Publisher = book.Publisher.Name
+	}
+	if book.Series != nil {
+		bookResponse.SeriesId = book.Series.Id
+		bookResponse.Series = book.Series.Name
+	}
+	if book.SeriesPosition != nil {
+		bookResponse.SeriesPosition = *book.SeriesPosition
+	}
+	for _, tag := range book.Tags {
+		bookResponse.Tags = append(bookResponse.Tags, tag.Name)
+	}
+	if len(book.Titles) > 0 {
+		bookResponse.Titles = make(map[string]string, len(book.Titles))
+		for _, title := range book.Titles {
+			bookResponse.Titles[title.Locale] = title.Title
+		}
+	}
+	for _, bookCopy := range book.Copies {
+		switch bookCopy.Status {
+		case dtos.CopyStatusAvailable:
+			bookResponse.Availability.Copies++
+			bookResponse.Availability.Available++
+		case dtos.CopyStatusOnHold:
+			bookResponse.Availability.Copies++
+			bookResponse.Availability.OnHold++
+		case dtos.CopyStatusOnLoan:
+			bookResponse.Availability.Copies++
+		}
+	}
 
+	return bookResponse
 }
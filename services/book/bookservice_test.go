@@ -6,15 +6,53 @@ import (
 	"github/brunojoenk/golang-test/models/entities"
 	authorrepomock "github/brunojoenk/golang-test/repository/author/mock"
 	bookrepomock "github/brunojoenk/golang-test/repository/book/mock"
+	bookfilerepomock "github/brunojoenk/golang-test/repository/bookfile/mock"
+	coverrepomock "github/brunojoenk/golang-test/repository/cover/mock"
+	genrerepomock "github/brunojoenk/golang-test/repository/genre/mock"
+	publisherrepomock "github/brunojoenk/golang-test/repository/publisher/mock"
+	seriesrepomock "github/brunojoenk/golang-test/repository/series/mock"
+	workrepomock "github/brunojoenk/golang-test/repository/work/mock"
+	storagemock "github/brunojoenk/golang-test/storage/mock"
 	"github/brunojoenk/golang-test/utils"
 	"testing"
 
+	"github.com/jackc/pgconn"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 	"gorm.io/gorm"
 )
 
 var errGeneric = errors.New("generic error")
 
+// newCoverDbMock has no cover for any book, so no image is removed from the store
+func newCoverDbMock() *coverrepomock.CoverRepositoryMock {
+	coverDbMock := new(coverrepomock.CoverRepositoryMock)
+	coverDbMock.On("GetCover", mock.Anything).Return(entities.BookCover{}, gorm.ErrRecordNotFound)
+	return coverDbMock
+}
+
+// newWorkDbMock has no work with the name of the books created, so each one gets a new work
+func newWorkDbMock() *workrepomock.WorkRepositoryMock {
+	workDbMock := new(workrepomock.WorkRepositoryMock)
+	workDbMock.On("GetWorksByNormalizedName", mock.Anything).Return([]entities.Work{}, nil)
+	return workDbMock
+}
+
+// withNewWork is the book along with the new work it is created with, one with its name and authors
+func withNewWork(book entities.Book) entities.Book {
+	book.Work = &entities.Work{Name: book.Name, NormalizedName: book.NormalizedName, Authors: workAuthors(book.Contributors)}
+	return book
+}
+
+// contributors credits the authors with the role author, in the given order
+func contributors(authors ...entities.Author) []entities.AuthorBook {
+	var contributors []entities.AuthorBook
+	for i, author := range authors {
+		contributors = append(contributors, entities.AuthorBook{AuthorId: author.Id, Author: author, Role: dtos.RoleAuthor, Position: i})
+	}
+	return contributors
+}
+
 func TestCreateBook(t *testing.T) {
 	var (
 		name            = "book"
@@ -24,7 +62,7 @@ func TestCreateBook(t *testing.T) {
 		authorName      = "author"
 	)
 	authors := []entities.Author{{Id: authorId, Name: authorName}}
-	book := entities.Book{Name: name, Edition: edition, PublicationYear: publicationYear, Authors: authors}
+	book := withNewWork(entities.Book{Name: name, NormalizedName: name, Edition: edition, PublicationYear: publicationYear, Contributors: contributors(authors...)})
 	tests := map[string]struct {
 		book                      entities.Book
 		authors                   []entities.Author
@@ -63,8 +101,8 @@ func TestCreateBook(t *testing.T) {
 			bookDbMock := new(bookrepomock.BookRepositoryMock)
 			bookDbMock.On("CreateBook", tc.book).Return(entities.Book{}, tc.expectedErrorOnCreateBook)
 
-			bookServiceTest := bookService{authorDb: authorDbMock, bookDb: bookDbMock}
-			_, err := bookServiceTest.CreateBook(dtos.BookRequestCreate{Name: name, Edition: edition, PublicationYear: publicationYear, Authors: []int{authorId}})
+			bookServiceTest := bookService{authorDb: authorDbMock, bookDb: bookDbMock, workDb: newWorkDbMock()}
+			_, err := bookServiceTest.CreateBook(dtos.BookRequestCreate{Name: name, Edition: edition, PublicationYear: publicationYear, Authors: []dtos.BookAuthorRequest{{Id: authorId}}})
 			if tc.expectedErrorResponse != nil {
 				require.ErrorIs(t, err, tc.expectedErrorResponse)
 			} else {
@@ -84,7 +122,7 @@ func TestGetAllBooks(t *testing.T) {
 		anotherAuthorId   = 7
 		anotherAuthorName = "bruno"
 		authors           = []entities.Author{{Id: authorId, Name: authorName}, {Id: anotherAuthorId, Name: anotherAuthorName}}
-		book1             = entities.Book{Name: bookName, Edition: edition, PublicationYear: publicationYear, Authors: authors}
+		book1             = entities.Book{Name: bookName, Edition: edition, PublicationYear: publicationYear, Contributors: contributors(authors...)}
 	)
 	tests := map[string]struct {
 		booksExpected              []entities.Book
@@ -121,16 +159,72 @@ func TestGetAllBooks(t *testing.T) {
 
 }
 
+func TestGetAllBooksLimitIsCapped(t *testing.T) {
+	bookDbMock := new(bookrepomock.BookRepositoryMock)
+	bookDbMock.On("GetAllBooks", dtos.GetBooksFilter{Pagination: dtos.Pagination{Page: 1, Limit: dtos.MAX_PAGE_LIMIT}}).Return([]entities.Book{}, nil)
+
+	bookServiceTest := bookService{bookDb: bookDbMock}
+	resp, err := bookServiceTest.GetAllBooks(dtos.GetBooksFilter{Pagination: dtos.Pagination{Page: 1, Limit: 100000}})
+
+	require.NoError(t, err)
+	require.Equal(t, dtos.MAX_PAGE_LIMIT, resp.Pagination.Limit)
+}
+
 func TestDeleteBook(t *testing.T) {
 	var (
 		bookId = 2
+		cover  = entities.BookCover{BookId: bookId, Key: "covers/2/abc"}
+		files  = []entities.BookFile{{Id: 4, BookId: bookId, Key: "files/2/def"}, {Id: 5, BookId: bookId, Key: "files/2/ghi"}}
 	)
 	tests := map[string]struct {
-		expectedErrorOnDeleteBook error
-		expectedErrorResponse     error
+		copies                     int64
+		hasCover                   bool
+		hasFiles                   bool
+		expectedErrorOnCountCopies error
+		expectedErrorOnGetCover    error
+		expectedErrorOnGetFiles    error
+		expectedErrorOnDeleteBook  error
+		expectedErrorOnDeleteBlob  error
+		expectedDeletedBlobs       int
+		expectedErrorResponse      error
 	}{
 		"success on delete book": {},
+		"success on delete book with cover": {
+			hasCover:             true,
+			expectedDeletedBlobs: 3,
+		},
+		"success on delete book with files": {
+			hasFiles:             true,
+			expectedDeletedBlobs: 2,
+		},
+		"success on delete book with cover and files": {
+			hasCover:             true,
+			hasFiles:             true,
+			expectedDeletedBlobs: 5,
+		},
+		"success on delete book with cover (error on delete blob)": {
+			hasCover:                  true,
+			expectedErrorOnDeleteBlob: errGeneric,
+			expectedDeletedBlobs:      3,
+		},
+		"error when book has copies": {
+			copies:                2,
+			expectedErrorResponse: utils.ErrBookHasCopies,
+		},
+		"error occurred on count copies": {
+			expectedErrorOnCountCopies: errGeneric,
+			expectedErrorResponse:      errGeneric,
+		},
+		"error occurred on get cover": {
+			expectedErrorOnGetCover: errGeneric,
+			expectedErrorResponse:   errGeneric,
+		},
+		"error occurred on get files": {
+			expectedErrorOnGetFiles: errGeneric,
+			expectedErrorResponse:   errGeneric,
+		},
 		"error occurred on delete book": {
+			hasCover:                  true,
 			expectedErrorOnDeleteBook: errGeneric,
 			expectedErrorResponse:     errGeneric,
 		},
@@ -138,15 +232,42 @@ func TestDeleteBook(t *testing.T) {
 	for testName, tc := range tests {
 		t.Run(testName, func(t *testing.T) {
 			bookDbMock := new(bookrepomock.BookRepositoryMock)
+			bookDbMock.On("CountCopies", bookId).Return(tc.copies, tc.expectedErrorOnCountCopies)
 			bookDbMock.On("DeleteBook", bookId).Return(tc.expectedErrorOnDeleteBook)
 
-			bookServiceTest := bookService{bookDb: bookDbMock}
+			coverDbMock := new(coverrepomock.CoverRepositoryMock)
+			if tc.hasCover {
+				coverDbMock.On("GetCover", bookId).Return(cover, nil)
+			} else if tc.expectedErrorOnGetCover != nil {
+				coverDbMock.On("GetCover", bookId).Return(entities.BookCover{}, tc.expectedErrorOnGetCover)
+			} else {
+				coverDbMock.On("GetCover", bookId).Return(entities.BookCover{}, gorm.ErrRecordNotFound)
+			}
+
+			bookFileDbMock := new(bookfilerepomock.BookFileRepositoryMock)
+			if tc.hasFiles {
+				bookFileDbMock.On("GetBookFiles", bookId).Return(files, nil)
+			} else {
+				bookFileDbMock.On("GetBookFiles", bookId).Return([]entities.BookFile{}, tc.expectedErrorOnGetFiles)
+			}
+
+			storeMock := new(storagemock.BlobStoreMock)
+			storeMock.On("Delete", mock.Anything).Return(tc.expectedErrorOnDeleteBlob)
+
+			bookServiceTest := bookService{bookDb: bookDbMock, coverDb: coverDbMock, bookFileDb: bookFileDbMock, store: storeMock}
 			err := bookServiceTest.DeleteBook(bookId)
 			if tc.expectedErrorResponse != nil {
 				require.ErrorIs(t, err, tc.expectedErrorResponse)
 			} else {
 				require.NoError(t, err)
 			}
+			storeMock.AssertNumberOfCalls(t, "Delete", tc.expectedDeletedBlobs)
+			if tc.hasCover && tc.expectedDeletedBlobs > 0 {
+				storeMock.AssertCalled(t, "Delete", cover.Key+"/"+dtos.CoverSizeOriginal)
+			}
+			if tc.hasFiles && tc.expectedDeletedBlobs > 0 {
+				storeMock.AssertCalled(t, "Delete", files[1].Key)
+			}
 		})
 	}
 }
@@ -162,7 +283,7 @@ func TestGetBook(t *testing.T) {
 		anotherAuthorId   = 7
 		anotherAuthorName = "bruno"
 		authors           = []entities.Author{{Id: authorId, Name: authorName}, {Id: anotherAuthorId, Name: anotherAuthorName}}
-		book              = entities.Book{Id: bookId, Name: bookName, Edition: edition, PublicationYear: publicationYear, Authors: authors}
+		book              = entities.Book{Id: bookId, Name: bookName, Edition: edition, PublicationYear: publicationYear, Contributors: contributors(authors...)}
 	)
 	tests := map[string]struct {
 		bookExpected           entities.Book
@@ -210,7 +331,7 @@ func TestUpdateBook(t *testing.T) {
 		authorId        = 5
 		authorName      = "joenk"
 		authors         = []entities.Author{{Id: authorId, Name: authorName}}
-		book            = entities.Book{Id: bookId, Name: bookName, Edition: edition, PublicationYear: publicationYear, Authors: authors}
+		book            = entities.Book{Id: bookId, Name: bookName, NormalizedName: bookName, Edition: edition, PublicationYear: publicationYear, Contributors: contributors(authors...)}
 	)
 	tests := map[string]struct {
 		bookExpected             entities.Book
@@ -243,18 +364,1247 @@ func TestUpdateBook(t *testing.T) {
 		t.Run(testName, func(t *testing.T) {
 			bookDbMock := new(bookrepomock.BookRepositoryMock)
 			bookDbMock.On("GetBook", bookId).Return(book, tc.expectedErrorOnGetBook)
-			bookDbMock.On("UpdateBook", book, authors).Return(book, tc.expectedErrorOnUpdate)
+			bookDbMock.On("UpdateBook", book, contributors(authors...), ([]entities.Genre)(nil)).Return(book, tc.expectedErrorOnUpdate)
 
 			authorDbMock := new(authorrepomock.AuthorRepositoryMock)
 			authorDbMock.On("GetAuthor", authorId).Return(authors[0], tc.expectedErrorOnGetAuthor)
 
 			bookServiceTest := bookService{bookDb: bookDbMock, authorDb: authorDbMock}
-			_, err := bookServiceTest.UpdateBook(bookId, dtos.BookRequestUpdate{Name: bookName, Edition: edition, PublicationYear: publicationYear, Authors: []int{authorId}})
+			_, err := bookServiceTest.UpdateBook(bookId, dtos.BookRequestUpdate{Name: bookName, Edition: edition, PublicationYear: publicationYear, Authors: []dtos.BookAuthorRequest{{Id: authorId}}})
+			if tc.expectedErrorResponse != nil {
+				require.ErrorIs(t, err, tc.expectedErrorResponse)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestUpdateBookWork(t *testing.T) {
+	var (
+		workId      = 3
+		otherId     = 4
+		tolkien     = entities.Author{Id: 5, Name: "J. R. R. Tolkien"}
+		christopher = entities.Author{Id: 6, Name: "Christopher Tolkien"}
+	)
+	tests := map[string]struct {
+		request         dtos.BookRequestUpdate
+		works           []entities.Work
+		expectedWorkId  *int
+		expectedNewWork bool
+	}{
+		"keeps its work when name and authors are the same": {
+			request:        dtos.BookRequestUpdate{Name: "The Hobbit", Edition: "second", Authors: []dtos.BookAuthorRequest{{Id: 5}}},
+			expectedWorkId: &workId,
+		},
+		"moves to the work with its new name": {
+			request:        dtos.BookRequestUpdate{Name: "The Silmarillion", Authors: []dtos.BookAuthorRequest{{Id: 5}}},
+			works:          []entities.Work{{Id: otherId, Authors: []entities.Author{tolkien}}},
+			expectedWorkId: &otherId,
+		},
+		"gets a new work with its new authors": {
+			request:         dtos.BookRequestUpdate{Name: "The Hobbit", Authors: []dtos.BookAuthorRequest{{Id: 5}, {Id: 6}}},
+			works:           []entities.Work{{Id: workId, Authors: []entities.Author{tolkien}}},
+			expectedNewWork: true,
+		},
+		"keeps the work given": {
+			request:        dtos.BookRequestUpdate{Name: "The Silmarillion", Authors: []dtos.BookAuthorRequest{{Id: 5}}, WorkId: workId},
+			expectedWorkId: &workId,
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			book := entities.Book{Id: 9, Name: "The Hobbit", NormalizedName: "the hobbit", WorkId: &workId, Contributors: contributors(tolkien)}
+
+			authorDbMock := new(authorrepomock.AuthorRepositoryMock)
+			authorDbMock.On("GetAuthor", 5).Return(tolkien, nil)
+			authorDbMock.On("GetAuthor", 6).Return(christopher, nil)
+
+			workDbMock := new(workrepomock.WorkRepositoryMock)
+			workDbMock.On("GetWork", workId).Return(entities.Work{Id: workId}, nil)
+			workDbMock.On("GetWorksByNormalizedName", mock.Anything).Return(tc.works, nil)
+
+			var updated entities.Book
+			bookDbMock := new(bookrepomock.BookRepositoryMock)
+			bookDbMock.On("GetBook", 9).Return(book, nil)
+			bookDbMock.On("UpdateBook", mock.Anything, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+				updated = args.Get(0).(entities.Book)
+			}).Return(book, nil)
+
+			bookServiceTest := bookService{authorDb: authorDbMock, bookDb: bookDbMock, workDb: workDbMock}
+			_, err := bookServiceTest.UpdateBook(9, tc.request)
+
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedWorkId, updated.WorkId)
+			if tc.expectedNewWork {
+				require.Equal(t, &entities.Work{Name: "The Hobbit", NormalizedName: "the hobbit", Authors: []entities.Author{tolkien, christopher}}, updated.Work)
+			} else {
+				require.Nil(t, updated.Work)
+			}
+		})
+	}
+}
+
+func TestCreateBookDuplicatePolicy(t *testing.T) {
+	var (
+		authors = []entities.Author{{Id: 5, Name: "joenk"}}
+		request = dtos.BookRequestCreate{Name: "Harry Potter!", Edition: "first", PublicationYear: 2022, Authors: []dtos.BookAuthorRequest{{Id: 5}}}
+		book    = withNewWork(entities.Book{Name: "Harry Potter!", NormalizedName: "harry potter", Edition: "first", PublicationYear: 2022, Contributors: contributors(authors...)})
+	)
+	tests := map[string]struct {
+		policy                    string
+		candidates                []entities.Book
+		candidatesAfterCreate     []entities.Book
+		expectedErrorOnNaturalKey error
+		expectedErrorOnCreate     error
+		expectedDuplicatedBookId  int
+		expectedErrorResponse     error
+	}{
+		"reject policy without candidates": {
+			policy:     DUPLICATE_POLICY_REJECT,
+			candidates: []entities.Book{},
+		},
+		"reject policy with candidate of other authors": {
+			policy:     DUPLICATE_POLICY_REJECT,
+			candidates: []entities.Book{{Id: 9, Contributors: contributors(entities.Author{Id: 5}, entities.Author{Id: 6})}},
+		},
+		"reject policy with duplicated book": {
+			policy:                   DUPLICATE_POLICY_REJECT,
+			candidates:               []entities.Book{{Id: 9, Contributors: contributors(entities.Author{Id: 5})}},
+			expectedDuplicatedBookId: 9,
+		},
+		"reject policy with error on natural key": {
+			policy:                    DUPLICATE_POLICY_REJECT,
+			expectedErrorOnNaturalKey: errGeneric,
+			expectedErrorResponse:     errGeneric,
+		},
+		"allow policy with duplicated book": {
+			policy:     DUPLICATE_POLICY_ALLOW,
+			candidates: []entities.Book{{Id: 9, Contributors: contributors(entities.Author{Id: 5})}},
+		},
+		"reject policy with duplicated book created at the same time": {
+			policy:                   DUPLICATE_POLICY_REJECT,
+			candidates:               []entities.Book{},
+			candidatesAfterCreate:    []entities.Book{{Id: 9, Contributors: contributors(entities.Author{Id: 5})}},
+			expectedErrorOnCreate:    &pgconn.PgError{Code: "23505", ConstraintName: BOOK_NATURAL_KEY_INDEX},
+			expectedDuplicatedBookId: 9,
+		},
+		"reject policy with isbn created at the same time": {
+			policy:                DUPLICATE_POLICY_REJECT,
+			candidates:            []entities.Book{},
+			expectedErrorOnCreate: &pgconn.PgError{Code: "23505", ConstraintName: BOOK_ISBN13_INDEX},
+			expectedErrorResponse: utils.ErrBookIsbnExists,
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			authorDbMock := new(authorrepomock.AuthorRepositoryMock)
+			authorDbMock.On("GetAuthor", 5).Return(authors[0], nil)
+
+			bookDbMock := new(bookrepomock.BookRepositoryMock)
+			bookDbMock.On("GetBooksByNaturalKey", "harry potter", "first", 2022).Return(tc.candidates, tc.expectedErrorOnNaturalKey).Once()
+			bookDbMock.On("GetBooksByNaturalKey", "harry potter", "first", 2022).Return(tc.candidatesAfterCreate, nil)
+			bookDbMock.On("CreateBook", mock.Anything).Return(entities.Book{Id: 10}, tc.expectedErrorOnCreate)
+
+			bookServiceTest := bookService{authorDb: authorDbMock, bookDb: bookDbMock, duplicatePolicy: tc.policy, workDb: newWorkDbMock()}
+			resp, err := bookServiceTest.CreateBook(request)
+
+			expectedBook := book
+			expectedBook.NaturalKey = bookServiceTest.naturalKey(book)
+			switch {
+			case tc.expectedDuplicatedBookId != 0:
+				require.Equal(t, utils.BookDuplicatedError{ExistingBookId: tc.expectedDuplicatedBookId}, err)
+				if tc.expectedErrorOnCreate == nil {
+					bookDbMock.AssertNotCalled(t, "CreateBook", mock.Anything)
+				}
+			case tc.expectedErrorResponse != nil:
+				require.ErrorIs(t, err, tc.expectedErrorResponse)
+			default:
+				require.NoError(t, err)
+				require.Equal(t, 10, resp.Id)
+				bookDbMock.AssertCalled(t, "CreateBook", expectedBook)
+			}
+		})
+	}
+}
+
+func TestNaturalKey(t *testing.T) {
+	isbn13 := "9780261103207"
+	bookServiceTest := bookService{duplicatePolicy: DUPLICATE_POLICY_REJECT}
+	book := entities.Book{NormalizedName: "harry potter", Edition: "First", PublicationYear: 2022, Contributors: contributors(entities.Author{Id: 5}, entities.Author{Id: 6})}
+	key := bookServiceTest.naturalKey(book)
+	require.NotNil(t, key)
+
+	same := book
+	same.Edition = " first "
+	same.Contributors = contributors(entities.Author{Id: 6}, entities.Author{Id: 5}, entities.Author{Id: 5})
+	require.Equal(t, key, bookServiceTest.naturalKey(same))
+
+	otherYear := book
+	otherYear.PublicationYear = 2023
+	require.NotEqual(t, key, bookServiceTest.naturalKey(otherYear))
+
+	otherAuthors := book
+	otherAuthors.Contributors = contributors(entities.Author{Id: 5})
+	require.NotEqual(t, key, bookServiceTest.naturalKey(otherAuthors))
+
+	withIsbn := book
+	withIsbn.Isbn13 = &isbn13
+	require.Nil(t, bookServiceTest.naturalKey(withIsbn))
+
+	bookServiceTest.duplicatePolicy = DUPLICATE_POLICY_ALLOW
+	require.Nil(t, bookServiceTest.naturalKey(book))
+}
+
+func TestUpdateBookDuplicatePolicyIgnoresItself(t *testing.T) {
+	var (
+		authors = []entities.Author{{Id: 5, Name: "joenk"}}
+		book    = entities.Book{Id: 3, Name: "book", NormalizedName: "book", Edition: "first", PublicationYear: 2022, Contributors: contributors(authors...)}
+	)
+
+	authorDbMock := new(authorrepomock.AuthorRepositoryMock)
+	authorDbMock.On("GetAuthor", 5).Return(authors[0], nil)
+
+	bookDbMock := new(bookrepomock.BookRepositoryMock)
+	bookDbMock.On("GetBook", 3).Return(book, nil)
+	bookDbMock.On("GetBooksByNaturalKey", "book", "first", 2022).Return([]entities.Book{book}, nil)
+
+	bookServiceTest := bookService{authorDb: authorDbMock, bookDb: bookDbMock, duplicatePolicy: DUPLICATE_POLICY_REJECT}
+	updated := book
+	updated.NaturalKey = bookServiceTest.naturalKey(book)
+	bookDbMock.On("UpdateBook", updated, contributors(authors...), ([]entities.Genre)(nil)).Return(updated, nil)
+
+	_, err := bookServiceTest.UpdateBook(3, dtos.BookRequestUpdate{Name: "book", Edition: "first", PublicationYear: 2022, Authors: []dtos.BookAuthorRequest{{Id: 5}}})
+
+	require.NoError(t, err)
+}
+
+func TestGetDuplicateBooks(t *testing.T) {
+	books := []entities.Book{
+		{Id: 1, Name: "Harry Potter", NormalizedName: "harry potter"},
+		{Id: 2, Name: "harry potter!", NormalizedName: "harry potter"},
+		{Id: 3, Name: "Clean Code", NormalizedName: "clean code"},
+		{Id: 4, Name: "clean  code", NormalizedName: "clean code"},
+	}
+	tests := map[string]struct {
+		books                 []entities.Book
+		expectedErrorOnGet    error
+		expectedClusters      []dtos.BookDuplicateCluster
+		expectedErrorResponse error
+	}{
+		"success on get duplicate books": {
+			books: books,
+			expectedClusters: []dtos.BookDuplicateCluster{
+				{NormalizedName: "harry potter", Books: []dtos.BookResponse{{Id: 1, Name: "Harry Potter"}, {Id: 2, Name: "harry potter!"}}},
+				{NormalizedName: "clean code", Books: []dtos.BookResponse{{Id: 3, Name: "Clean Code"}, {Id: 4, Name: "clean  code"}}},
+			},
+		},
+		"success on get duplicate books (none)": {
+			books:            []entities.Book{},
+			expectedClusters: []dtos.BookDuplicateCluster{},
+		},
+		"error occurred on get duplicate books": {
+			books:                 []entities.Book{},
+			expectedErrorOnGet:    errGeneric,
+			expectedErrorResponse: errGeneric,
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			bookDbMock := new(bookrepomock.BookRepositoryMock)
+			bookDbMock.On("GetDuplicateBooks", dtos.Pagination{Page: 1, Limit: 10}).Return(tc.books, tc.expectedErrorOnGet)
+
+			bookServiceTest := bookService{bookDb: bookDbMock}
+			resp, err := bookServiceTest.GetDuplicateBooks(dtos.Pagination{})
+			if tc.expectedErrorResponse != nil {
+				require.ErrorIs(t, err, tc.expectedErrorResponse)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tc.expectedClusters, resp.Clusters)
+			}
+		})
+	}
+}
+
+func TestMergeBooks(t *testing.T) {
+	var (
+		target = entities.Book{Id: 1, Name: "Harry Potter", NormalizedName: "harry potter", Contributors: contributors(entities.Author{Id: 5})}
+		source = entities.Book{Id: 2, Name: "harry potter!", NormalizedName: "harry potter", Edition: "first", PublicationYear: 2001}
+		merged = entities.Book{Id: 1, Name: "Harry Potter", NormalizedName: "harry potter", Edition: "first", PublicationYear: 2001}
+		cover  = entities.BookCover{BookId: 2, Key: "covers/2/abc"}
+	)
+	tests := map[string]struct {
+		targetId               int
+		targetHasCover         bool
+		sourceHasCover         bool
+		expectedDeletedBlobs   int
+		expectedErrorOnGetBook error
+		expectedErrorOnMerge   error
+		expectedErrorResponse  error
+	}{
+		"success on merge books": {
+			targetId: 1,
+		},
+		"success on merge books moving the cover of source": {
+			targetId:       1,
+			sourceHasCover: true,
+		},
+		"success on merge books removing the cover of source": {
+			targetId:             1,
+			targetHasCover:       true,
+			sourceHasCover:       true,
+			expectedDeletedBlobs: 3,
+		},
+		"error occurred on merge books (same id)": {
+			targetId:              2,
+			expectedErrorResponse: utils.ErrBookMergeSameId,
+		},
+		"error occurred on merge books (book not found)": {
+			targetId:               1,
+			expectedErrorOnGetBook: gorm.ErrRecordNotFound,
+			expectedErrorResponse:  utils.ErrBookIdNotFound,
+		},
+		"error occurred on merge books (merge)": {
+			targetId:              1,
+			targetHasCover:        true,
+			sourceHasCover:        true,
+			expectedErrorOnMerge:  errGeneric,
+			expectedErrorResponse: errGeneric,
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			bookDbMock := new(bookrepomock.BookRepositoryMock)
+			bookDbMock.On("GetBook", 1).Return(target, tc.expectedErrorOnGetBook)
+			bookDbMock.On("GetBook", 2).Return(source, nil)
+			bookDbMock.On("MergeBooks", merged, 2).Return(tc.expectedErrorOnMerge)
+
+			coverDbMock := new(coverrepomock.CoverRepositoryMock)
+			if tc.targetHasCover {
+				coverDbMock.On("GetCover", 1).Return(entities.BookCover{BookId: 1, Key: "covers/1/def"}, nil)
+			} else {
+				coverDbMock.On("GetCover", 1).Return(entities.BookCover{}, gorm.ErrRecordNotFound)
+			}
+			if tc.sourceHasCover {
+				coverDbMock.On("GetCover", 2).Return(cover, nil)
+			} else {
+				coverDbMock.On("GetCover", 2).Return(entities.BookCover{}, gorm.ErrRecordNotFound)
+			}
+
+			storeMock := new(storagemock.BlobStoreMock)
+			storeMock.On("Delete", mock.Anything).Return(nil)
+
+			bookServiceTest := bookService{bookDb: bookDbMock, coverDb: coverDbMock, store: storeMock}
+			resp, err := bookServiceTest.MergeBooks(tc.targetId, 2)
+			if tc.expectedErrorResponse != nil {
+				require.ErrorIs(t, err, tc.expectedErrorResponse)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, 1, resp.Id)
+				bookDbMock.AssertCalled(t, "MergeBooks", merged, 2)
+			}
+			storeMock.AssertNumberOfCalls(t, "Delete", tc.expectedDeletedBlobs)
+			if tc.expectedDeletedBlobs > 0 {
+				storeMock.AssertCalled(t, "Delete", cover.Key+"/"+dtos.CoverSizeOriginal)
+			}
+		})
+	}
+}
+
+func TestBackfillNormalizedNames(t *testing.T) {
+	BACKFILL_BATCH_SIZE = 2
+	defer func() { BACKFILL_BATCH_SIZE = 500 }()
+
+	bookDbMock := new(bookrepomock.BookRepositoryMock)
+	bookDbMock.On("GetBooksWithoutNormalizedName", 0, 2).Return([]entities.Book{{Id: 1, Name: "Harry Potter"}, {Id: 2, Name: "!!"}}, nil)
+	bookDbMock.On("GetBooksWithoutNormalizedName", 2, 2).Return([]entities.Book{{Id: 3, Name: "Clean Code"}}, nil)
+	bookDbMock.On("UpdateNormalizedName", 1, "harry potter").Return(nil)
+	bookDbMock.On("UpdateNormalizedName", 3, "clean code").Return(nil)
+
+	bookServiceTest := bookService{bookDb: bookDbMock}
+	err := bookServiceTest.BackfillNormalizedNames()
+
+	require.NoError(t, err)
+	bookDbMock.AssertNumberOfCalls(t, "UpdateNormalizedName", 2)
+}
+
+func TestBackfillWorks(t *testing.T) {
+	BACKFILL_BATCH_SIZE = 2
+	defer func() { BACKFILL_BATCH_SIZE = 500 }()
+
+	var (
+		tolkien    = entities.Author{Id: 5, Name: "J. R. R. Tolkien"}
+		translator = entities.Author{Id: 6, Name: "Reinhold Stumpf"}
+		hobbit     = entities.Work{Name: "The Hobbit", NormalizedName: "the hobbit", Authors: []entities.Author{tolkien}}
+	)
+	first := entities.Book{Id: 1, Name: "The Hobbit", NormalizedName: "the hobbit", Contributors: contributors(tolkien)}
+	translated := entities.Book{Id: 2, Name: "The Hobbit", NormalizedName: "the hobbit", Contributors: append(contributors(tolkien),
+		entities.AuthorBook{AuthorId: 6, Author: translator, Role: dtos.RoleTranslator, Position: 1})}
+	other := entities.Book{Id: 3, Name: "The Hobbit", NormalizedName: "the hobbit"}
+
+	bookDbMock := new(bookrepomock.BookRepositoryMock)
+	bookDbMock.On("GetBooksWithoutWork", 0, 2).Return([]entities.Book{first, translated}, nil)
+	bookDbMock.On("GetBooksWithoutWork", 2, 2).Return([]entities.Book{other}, nil)
+	bookDbMock.On("UpdateWork", mock.Anything, mock.Anything).Return(nil)
+
+	workDbMock := new(workrepomock.WorkRepositoryMock)
+	workDbMock.On("GetWorksByNormalizedName", "the hobbit").Return([]entities.Work{}, nil).Once()
+	workDbMock.On("CreateWork", hobbit).Return(entities.Work{Id: 7, Authors: []entities.Author{tolkien}}, nil).Once()
+	workDbMock.On("GetWorksByNormalizedName", "the hobbit").Return([]entities.Work{{Id: 7, Authors: []entities.Author{tolkien}}}, nil)
+	workDbMock.On("CreateWork", entities.Work{Name: "The Hobbit", NormalizedName: "the hobbit"}).Return(entities.Work{Id: 8}, nil)
+
+	bookServiceTest := bookService{bookDb: bookDbMock, workDb: workDbMock}
+	err := bookServiceTest.BackfillWorks()
+
+	require.NoError(t, err)
+	bookDbMock.AssertCalled(t, "UpdateWork", 1, 7)
+	bookDbMock.AssertCalled(t, "UpdateWork", 2, 7)
+	bookDbMock.AssertCalled(t, "UpdateWork", 3, 8)
+}
+
+func TestCreateBookWork(t *testing.T) {
+	var (
+		authors = []entities.Author{{Id: 5, Name: "joenk"}}
+		workId  = 7
+	)
+	tests := map[string]struct {
+		workId                int
+		works                 []entities.Work
+		expectedErrorOnGet    error
+		expectedNewWork       bool
+		expectedErrorResponse error
+	}{
+		"success on create book joining the work with the same authors": {
+			works: []entities.Work{{Id: 3, Authors: []entities.Author{{Id: 6}}}, {Id: workId, Authors: authors}},
+		},
+		"success on create book creating its work": {
+			works:           []entities.Work{{Id: 3, Authors: []entities.Author{{Id: 6}}}},
+			expectedNewWork: true,
+		},
+		"success on create book with work id": {
+			workId: workId,
+		},
+		"error occurred on create book (work not found)": {
+			workId:                workId,
+			expectedErrorOnGet:    gorm.ErrRecordNotFound,
+			expectedErrorResponse: utils.ErrWorkIdNotFound,
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			book := entities.Book{Name: "book", NormalizedName: "book", WorkId: &workId, Contributors: contributors(authors...)}
+			createdBook := book
+			if tc.expectedNewWork {
+				book = withNewWork(entities.Book{Name: "book", NormalizedName: "book", Contributors: contributors(authors...)})
+			}
+
+			authorDbMock := new(authorrepomock.AuthorRepositoryMock)
+			authorDbMock.On("GetAuthor", 5).Return(authors[0], nil)
+
+			workDbMock := new(workrepomock.WorkRepositoryMock)
+			workDbMock.On("GetWork", workId).Return(entities.Work{Id: workId}, tc.expectedErrorOnGet)
+			workDbMock.On("GetWorksByNormalizedName", "book").Return(tc.works, nil)
+
+			bookDbMock := new(bookrepomock.BookRepositoryMock)
+			bookDbMock.On("CreateBook", book).Return(createdBook, nil)
+
+			bookServiceTest := bookService{authorDb: authorDbMock, bookDb: bookDbMock, workDb: workDbMock}
+			resp, err := bookServiceTest.CreateBook(dtos.BookRequestCreate{Name: "book", Authors: []dtos.BookAuthorRequest{{Id: 5}}, WorkId: tc.workId})
+			if tc.expectedErrorResponse != nil {
+				require.ErrorIs(t, err, tc.expectedErrorResponse)
+				bookDbMock.AssertNotCalled(t, "CreateBook", book)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, workId, resp.WorkId)
+			// A new work is left to the repository, to be created with the book
+			workDbMock.AssertNotCalled(t, "CreateWork", mock.Anything)
+		})
+	}
+}
+
+func TestCreateBookIsbn(t *testing.T) {
+	var (
+		authors = []entities.Author{{Id: 5, Name: "joenk"}}
+		isbn13  = "9780261103207"
+	)
+	tests := map[string]struct {
+		isbn                   string
+		existing               entities.Book
+		expectedErrorOnGetIsbn error
+		expectedIsbn13         *string
+		expectedErrorResponse  error
+	}{
+		"success on create book with isbn-10": {
+			isbn:                   "0-261-10320-2",
+			expectedErrorOnGetIsbn: gorm.ErrRecordNotFound,
+			expectedIsbn13:         &isbn13,
+		},
+		"success on create book without isbn": {
+			isbn: "  ",
+		},
+		"error occurred on create book (invalid isbn)": {
+			isbn:                  "978-0-261-10320-8",
+			expectedErrorResponse: utils.ErrInvalidIsbn,
+		},
+		"error occurred on create book (isbn of another book)": {
+			isbn:                  isbn13,
+			existing:              entities.Book{Id: 9},
+			expectedErrorResponse: utils.ErrBookIsbnExists,
+		},
+		"error occurred on create book (get book by isbn)": {
+			isbn:                   isbn13,
+			expectedErrorOnGetIsbn: errGeneric,
+			expectedErrorResponse:  errGeneric,
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			book := withNewWork(entities.Book{Name: "book", NormalizedName: "book", Edition: "first", PublicationYear: 2022, Contributors: contributors(authors...), Isbn13: tc.expectedIsbn13})
+
+			authorDbMock := new(authorrepomock.AuthorRepositoryMock)
+			authorDbMock.On("GetAuthor", 5).Return(authors[0], nil)
+
+			bookDbMock := new(bookrepomock.BookRepositoryMock)
+			bookDbMock.On("GetBookByIsbn", isbn13).Return(tc.existing, tc.expectedErrorOnGetIsbn)
+			bookDbMock.On("CreateBook", book).Return(book, nil)
+
+			bookServiceTest := bookService{authorDb: authorDbMock, bookDb: bookDbMock, workDb: newWorkDbMock()}
+			resp, err := bookServiceTest.CreateBook(dtos.BookRequestCreate{Name: "book", Edition: "first", PublicationYear: 2022, Authors: []dtos.BookAuthorRequest{{Id: 5}}, Isbn: tc.isbn})
 			if tc.expectedErrorResponse != nil {
 				require.ErrorIs(t, err, tc.expectedErrorResponse)
+				bookDbMock.AssertNotCalled(t, "CreateBook", book)
 			} else {
 				require.NoError(t, err)
+				bookDbMock.AssertCalled(t, "CreateBook", book)
+				if tc.expectedIsbn13 != nil {
+					require.Equal(t, isbn13, resp.Isbn13)
+					require.Equal(t, "0261103202", resp.Isbn10)
+				}
 			}
 		})
 	}
 }
+
+func TestUpdateBookKeepsItsOwnIsbn(t *testing.T) {
+	var (
+		authors = []entities.Author{{Id: 5, Name: "joenk"}}
+		isbn13  = "9780261103207"
+		book    = entities.Book{Id: 3, Name: "book", NormalizedName: "book", Edition: "first", PublicationYear: 2022, Contributors: contributors(authors...), Isbn13: &isbn13}
+	)
+
+	authorDbMock := new(authorrepomock.AuthorRepositoryMock)
+	authorDbMock.On("GetAuthor", 5).Return(authors[0], nil)
+
+	bookDbMock := new(bookrepomock.BookRepositoryMock)
+	bookDbMock.On("GetBook", 3).Return(book, nil)
+	bookDbMock.On("GetBookByIsbn", isbn13).Return(book, nil)
+	bookDbMock.On("UpdateBook", book, contributors(authors...), ([]entities.Genre)(nil)).Return(book, nil)
+
+	bookServiceTest := bookService{authorDb: authorDbMock, bookDb: bookDbMock}
+	_, err := bookServiceTest.UpdateBook(3, dtos.BookRequestUpdate{Name: "book", Edition: "first", PublicationYear: 2022, Authors: []dtos.BookAuthorRequest{{Id: 5}}, Isbn: "978-0-261-10320-7"})
+
+	require.NoError(t, err)
+}
+
+func TestGetBookByIsbn(t *testing.T) {
+	isbn13 := "9780261103207"
+	tests := map[string]struct {
+		isbn                  string
+		expectedErrorOnGet    error
+		expectedErrorResponse error
+	}{
+		"success on get book by isbn-13": {
+			isbn: "978-0-261-10320-7",
+		},
+		"success on get book by isbn-10": {
+			isbn: "0261103202",
+		},
+		"error occurred on get book by isbn (invalid isbn)": {
+			isbn:                  "123",
+			expectedErrorResponse: utils.ErrInvalidIsbn,
+		},
+		"error occurred on get book by isbn (not found)": {
+			isbn:                  isbn13,
+			expectedErrorOnGet:    gorm.ErrRecordNotFound,
+			expectedErrorResponse: utils.ErrBookIsbnNotFound,
+		},
+		"error occurred on get book by isbn (get book)": {
+			isbn:                  isbn13,
+			expectedErrorOnGet:    errGeneric,
+			expectedErrorResponse: errGeneric,
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			bookDbMock := new(bookrepomock.BookRepositoryMock)
+			bookDbMock.On("GetBookByIsbn", isbn13).Return(entities.Book{Id: 1, Isbn13: &isbn13}, tc.expectedErrorOnGet)
+
+			bookServiceTest := bookService{bookDb: bookDbMock}
+			resp, err := bookServiceTest.GetBookByIsbn(tc.isbn)
+			if tc.expectedErrorResponse != nil {
+				require.ErrorIs(t, err, tc.expectedErrorResponse)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, 1, resp.Id)
+				require.Equal(t, isbn13, resp.Isbn13)
+			}
+		})
+	}
+}
+
+func TestGetAllBooksFilterIsbn(t *testing.T) {
+	bookDbMock := new(bookrepomock.BookRepositoryMock)
+	bookDbMock.On("GetAllBooks", dtos.GetBooksFilter{Isbn: "9780261103207", Pagination: dtos.Pagination{Page: 1, Limit: 10}}).Return([]entities.Book{}, nil)
+
+	bookServiceTest := bookService{bookDb: bookDbMock}
+	_, err := bookServiceTest.GetAllBooks(dtos.GetBooksFilter{Isbn: "0-261-10320-2"})
+	require.NoError(t, err)
+
+	_, err = bookServiceTest.GetAllBooks(dtos.GetBooksFilter{Isbn: "0-261-10320-3"})
+	require.ErrorIs(t, err, utils.ErrInvalidIsbn)
+}
+
+func TestCreateBookDuplicateWithDifferentIsbn(t *testing.T) {
+	var (
+		authors = []entities.Author{{Id: 5, Name: "joenk"}}
+		isbn13  = "9780261103207"
+		other   = "9780547928227"
+		book    = withNewWork(entities.Book{Name: "book", NormalizedName: "book", Edition: "first", PublicationYear: 2022, Contributors: contributors(authors...), Isbn13: &isbn13})
+	)
+
+	authorDbMock := new(authorrepomock.AuthorRepositoryMock)
+	authorDbMock.On("GetAuthor", 5).Return(authors[0], nil)
+
+	bookDbMock := new(bookrepomock.BookRepositoryMock)
+	bookDbMock.On("GetBookByIsbn", isbn13).Return(entities.Book{}, gorm.ErrRecordNotFound)
+	bookDbMock.On("GetBooksByNaturalKey", "book", "first", 2022).Return([]entities.Book{{Id: 9, Contributors: contributors(authors...), Isbn13: &other}}, nil)
+	bookDbMock.On("CreateBook", book).Return(entities.Book{Id: 10}, nil)
+
+	bookServiceTest := bookService{authorDb: authorDbMock, bookDb: bookDbMock, duplicatePolicy: DUPLICATE_POLICY_REJECT, workDb: newWorkDbMock()}
+	resp, err := bookServiceTest.CreateBook(dtos.BookRequestCreate{Name: "book", Edition: "first", PublicationYear: 2022, Authors: []dtos.BookAuthorRequest{{Id: 5}}, Isbn: isbn13})
+
+	require.NoError(t, err)
+	require.Equal(t, 10, resp.Id)
+}
+
+func TestMergeBooksTakesIsbnOfSource(t *testing.T) {
+	var (
+		isbn13 = "9780261103207"
+		target = entities.Book{Id: 1, Name: "book", NormalizedName: "book", Edition: "first", PublicationYear: 2022}
+		source = entities.Book{Id: 2, Name: "book!", NormalizedName: "book", Isbn13: &isbn13}
+		merged = entities.Book{Id: 1, Name: "book", NormalizedName: "book", Edition: "first", PublicationYear: 2022, Isbn13: &isbn13}
+	)
+
+	bookDbMock := new(bookrepomock.BookRepositoryMock)
+	bookDbMock.On("GetBook", 1).Return(target, nil)
+	bookDbMock.On("GetBook", 2).Return(source, nil)
+	bookDbMock.On("MergeBooks", merged, 2).Return(nil)
+
+	bookServiceTest := bookService{bookDb: bookDbMock, coverDb: newCoverDbMock()}
+	_, err := bookServiceTest.MergeBooks(1, 2)
+
+	require.NoError(t, err)
+	bookDbMock.AssertCalled(t, "MergeBooks", merged, 2)
+}
+
+func TestMergeBooksNaturalKey(t *testing.T) {
+	var (
+		target = entities.Book{Id: 1, Name: "book", NormalizedName: "book", Edition: "first", PublicationYear: 2022, Contributors: contributors(entities.Author{Id: 5})}
+		source = entities.Book{Id: 2, Name: "book!", NormalizedName: "book", Edition: "first", PublicationYear: 2022, Contributors: contributors(entities.Author{Id: 6})}
+		both   = contributors(entities.Author{Id: 5}, entities.Author{Id: 6})
+	)
+	tests := map[string]struct {
+		candidates               []entities.Book
+		expectedErrorOnMerge     error
+		expectedDuplicatedBookId int
+		expectedErrorResponse    error
+	}{
+		"success on merge books keeping the natural key of both sets of authors": {
+			candidates: []entities.Book{target, source, {Id: 3, Contributors: contributors(entities.Author{Id: 5})}},
+		},
+		"success on merge books when source has the authors of both": {
+			candidates: []entities.Book{target, {Id: 2, Contributors: both}},
+		},
+		"error occurred on merge books (another book has the authors of both)": {
+			candidates:               []entities.Book{target, source, {Id: 3, Contributors: both}},
+			expectedDuplicatedBookId: 3,
+		},
+		"error occurred on merge books (natural key saved at the same time)": {
+			candidates:            []entities.Book{target, source},
+			expectedErrorOnMerge:  &pgconn.PgError{Code: "23505", ConstraintName: BOOK_NATURAL_KEY_INDEX},
+			expectedErrorResponse: utils.BookDuplicatedError{},
+		},
+		"error occurred on merge books (isbn saved at the same time)": {
+			candidates:            []entities.Book{target, source},
+			expectedErrorOnMerge:  &pgconn.PgError{Code: "23505", ConstraintName: BOOK_ISBN13_INDEX},
+			expectedErrorResponse: utils.ErrBookIsbnExists,
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			bookServiceTest := bookService{coverDb: newCoverDbMock(), duplicatePolicy: DUPLICATE_POLICY_REJECT}
+			merged := target
+			merged.Contributors = nil
+			merged.NaturalKey = bookServiceTest.naturalKey(entities.Book{NormalizedName: "book", Edition: "first", PublicationYear: 2022, Contributors: both})
+
+			bookDbMock := new(bookrepomock.BookRepositoryMock)
+			bookDbMock.On("GetBook", 1).Return(target, nil)
+			bookDbMock.On("GetBook", 2).Return(source, nil)
+			bookDbMock.On("GetBooksByNaturalKey", "book", "first", 2022).Return(tc.candidates, nil)
+			bookDbMock.On("MergeBooks", merged, 2).Return(tc.expectedErrorOnMerge)
+			bookServiceTest.bookDb = bookDbMock
+
+			_, err := bookServiceTest.MergeBooks(1, 2)
+			switch {
+			case tc.expectedDuplicatedBookId != 0:
+				require.Equal(t, utils.BookDuplicatedError{ExistingBookId: tc.expectedDuplicatedBookId}, err)
+				bookDbMock.AssertNotCalled(t, "MergeBooks", mock.Anything, mock.Anything)
+			case tc.expectedErrorResponse != nil:
+				require.Equal(t, tc.expectedErrorResponse, err)
+			default:
+				require.NoError(t, err)
+				bookDbMock.AssertCalled(t, "MergeBooks", merged, 2)
+			}
+		})
+	}
+}
+
+func TestCreateBookPublisher(t *testing.T) {
+	var (
+		authors     = []entities.Author{{Id: 5, Name: "joenk"}}
+		publisherId = 7
+		publisher   = entities.Publisher{Id: publisherId, Name: "Allen & Unwin"}
+	)
+	tests := map[string]struct {
+		publisherId             int
+		expectedErrorOnGet      error
+		expectedPublisher       string
+		expectedErrorResponse   error
+		expectedBookPublisherId *int
+	}{
+		"success on create book with publisher": {
+			publisherId:             publisherId,
+			expectedPublisher:       "Allen & Unwin",
+			expectedBookPublisherId: &publisherId,
+		},
+		"success on create book without publisher": {},
+		"error occurred on create book (publisher not found)": {
+			publisherId:           publisherId,
+			expectedErrorOnGet:    gorm.ErrRecordNotFound,
+			expectedErrorResponse: utils.ErrPublisherIdNotFound,
+		},
+		"error occurred on create book (get publisher)": {
+			publisherId:           publisherId,
+			expectedErrorOnGet:    errGeneric,
+			expectedErrorResponse: errGeneric,
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			book := withNewWork(entities.Book{Name: "book", NormalizedName: "book", Edition: "first", PublicationYear: 2022, Contributors: contributors(authors...), PublisherId: tc.expectedBookPublisherId})
+			if tc.expectedBookPublisherId != nil {
+				book.Publisher = &publisher
+			}
+
+			authorDbMock := new(authorrepomock.AuthorRepositoryMock)
+			authorDbMock.On("GetAuthor", 5).Return(authors[0], nil)
+
+			publisherDbMock := new(publisherrepomock.PublisherRepositoryMock)
+			publisherDbMock.On("GetPublisher", publisherId).Return(publisher, tc.expectedErrorOnGet)
+
+			bookDbMock := new(bookrepomock.BookRepositoryMock)
+			bookDbMock.On("CreateBook", book).Return(book, nil)
+
+			bookServiceTest := bookService{authorDb: authorDbMock, bookDb: bookDbMock, publisherDb: publisherDbMock, workDb: newWorkDbMock()}
+			resp, err := bookServiceTest.CreateBook(dtos.BookRequestCreate{Name: "book", Edition: "first", PublicationYear: 2022, Authors: []dtos.BookAuthorRequest{{Id: 5}}, PublisherId: tc.publisherId})
+			if tc.expectedErrorResponse != nil {
+				require.ErrorIs(t, err, tc.expectedErrorResponse)
+				bookDbMock.AssertNotCalled(t, "CreateBook", book)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tc.expectedPublisher, resp.Publisher)
+			}
+		})
+	}
+}
+
+func TestMergeBooksTakesPublisherOfSource(t *testing.T) {
+	var (
+		publisherId = 7
+		target      = entities.Book{Id: 1, Name: "book", NormalizedName: "book", Edition: "first", PublicationYear: 2022}
+		source      = entities.Book{Id: 2, Name: "book!", NormalizedName: "book", PublisherId: &publisherId, Publisher: &entities.Publisher{Id: publisherId}}
+		merged      = entities.Book{Id: 1, Name: "book", NormalizedName: "book", Edition: "first", PublicationYear: 2022, PublisherId: &publisherId}
+	)
+
+	bookDbMock := new(bookrepomock.BookRepositoryMock)
+	bookDbMock.On("GetBook", 1).Return(target, nil)
+	bookDbMock.On("GetBook", 2).Return(source, nil)
+	bookDbMock.On("MergeBooks", merged, 2).Return(nil)
+
+	bookServiceTest := bookService{bookDb: bookDbMock, coverDb: newCoverDbMock()}
+	_, err := bookServiceTest.MergeBooks(1, 2)
+
+	require.NoError(t, err)
+	bookDbMock.AssertCalled(t, "MergeBooks", merged, 2)
+}
+
+func TestCreateBookGenres(t *testing.T) {
+	var (
+		authors = []entities.Author{{Id: 5, Name: "joenk"}}
+		genres  = []entities.Genre{{Id: 3, Name: "Fantasy"}, {Id: 4, Name: "Adventure"}}
+	)
+	tests := map[string]struct {
+		expectedErrorOnGet    error
+		expectedGenres        string
+		expectedErrorResponse error
+	}{
+		"success on create book with genres": {
+			expectedGenres: "Fantasy | Adventure",
+		},
+		"error occurred on create book (genre not found)": {
+			expectedErrorOnGet:    gorm.ErrRecordNotFound,
+			expectedErrorResponse: utils.ErrGenreIdNotFound,
+		},
+		"error occurred on create book (get genre)": {
+			expectedErrorOnGet:    errGeneric,
+			expectedErrorResponse: errGeneric,
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			book := withNewWork(entities.Book{Name: "book", NormalizedName: "book", Edition: "first", PublicationYear: 2022, Contributors: contributors(authors...), Genres: genres})
+
+			authorDbMock := new(authorrepomock.AuthorRepositoryMock)
+			authorDbMock.On("GetAuthor", 5).Return(authors[0], nil)
+
+			genreDbMock := new(genrerepomock.GenreRepositoryMock)
+			genreDbMock.On("GetGenre", 3).Return(genres[0], tc.expectedErrorOnGet)
+			genreDbMock.On("GetGenre", 4).Return(genres[1], tc.expectedErrorOnGet)
+
+			bookDbMock := new(bookrepomock.BookRepositoryMock)
+			bookDbMock.On("CreateBook", book).Return(book, nil)
+
+			bookServiceTest := bookService{authorDb: authorDbMock, bookDb: bookDbMock, genreDb: genreDbMock, workDb: newWorkDbMock()}
+			resp, err := bookServiceTest.CreateBook(dtos.BookRequestCreate{Name: "book", Edition: "first", PublicationYear: 2022, Authors: []dtos.BookAuthorRequest{{Id: 5}}, Genres: []int{3, 4}})
+			if tc.expectedErrorResponse != nil {
+				require.ErrorIs(t, err, tc.expectedErrorResponse)
+				bookDbMock.AssertNotCalled(t, "CreateBook", book)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tc.expectedGenres, resp.Genres)
+			}
+		})
+	}
+}
+
+func TestCreateBookContributors(t *testing.T) {
+	var (
+		tolkien  = entities.Author{Id: 5, Name: "J. R. R. Tolkien"}
+		lee      = entities.Author{Id: 6, Name: "Alan Lee"}
+		anderson = entities.Author{Id: 7, Name: "Douglas A. Anderson"}
+	)
+	tests := map[string]struct {
+		authors               []dtos.BookAuthorRequest
+		expectedContributors  []entities.AuthorBook
+		expectedResponse      dtos.BookContributorsResponse
+		expectedErrorResponse error
+	}{
+		"success on create book with roles": {
+			authors: []dtos.BookAuthorRequest{{Id: 5}, {Id: 6, Role: "illustrator"}, {Id: 7, Role: "editor"}},
+			expectedContributors: []entities.AuthorBook{
+				{AuthorId: 5, Author: tolkien, Role: dtos.RoleAuthor, Position: 0},
+				{AuthorId: 6, Author: lee, Role: dtos.RoleIllustrator, Position: 1},
+				{AuthorId: 7, Author: anderson, Role: dtos.RoleEditor, Position: 2}},
+			expectedResponse: dtos.BookContributorsResponse{
+				Authors: []string{"J. R. R. Tolkien"}, Editors: []string{"Douglas A. Anderson"}, Illustrators: []string{"Alan Lee"}},
+		},
+		"error occurred on create book (invalid role)": {
+			authors:               []dtos.BookAuthorRequest{{Id: 5, Role: "narrator"}},
+			expectedErrorResponse: utils.ErrInvalidAuthorRole,
+		},
+		"success on create book with an author in two roles": {
+			authors: []dtos.BookAuthorRequest{{Id: 5}, {Id: 5, Role: "illustrator"}},
+			expectedContributors: []entities.AuthorBook{
+				{AuthorId: 5, Author: tolkien, Role: dtos.RoleAuthor, Position: 0},
+				{AuthorId: 5, Author: tolkien, Role: dtos.RoleIllustrator, Position: 1}},
+			expectedResponse: dtos.BookContributorsResponse{
+				Authors: []string{"J. R. R. Tolkien"}, Illustrators: []string{"J. R. R. Tolkien"}},
+		},
+		"error occurred on create book (repeated author)": {
+			authors:               []dtos.BookAuthorRequest{{Id: 5}, {Id: 5, Role: "author"}},
+			expectedErrorResponse: utils.ErrBookAuthorRepeated,
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			book := withNewWork(entities.Book{Name: "book", NormalizedName: "book", Contributors: tc.expectedContributors})
+
+			authorDbMock := new(authorrepomock.AuthorRepositoryMock)
+			authorDbMock.On("GetAuthor", 5).Return(tolkien, nil)
+			authorDbMock.On("GetAuthor", 6).Return(lee, nil)
+			authorDbMock.On("GetAuthor", 7).Return(anderson, nil)
+
+			bookDbMock := new(bookrepomock.BookRepositoryMock)
+			bookDbMock.On("CreateBook", book).Return(book, nil)
+
+			bookServiceTest := bookService{authorDb: authorDbMock, bookDb: bookDbMock, workDb: newWorkDbMock()}
+			resp, err := bookServiceTest.CreateBook(dtos.BookRequestCreate{Name: "book", Authors: tc.authors})
+			if tc.expectedErrorResponse != nil {
+				require.ErrorIs(t, err, tc.expectedErrorResponse)
+				bookDbMock.AssertNotCalled(t, "CreateBook", book)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, "J. R. R. Tolkien", resp.Authors)
+				require.Equal(t, tc.expectedResponse, resp.Contributors)
+			}
+		})
+	}
+}
+
+func TestGetAllBooksFilterGenre(t *testing.T) {
+	bookDbMock := new(bookrepomock.BookRepositoryMock)
+	bookDbMock.On("GetAllBooks", dtos.GetBooksFilter{Genre: "science fiction", Pagination: dtos.Pagination{Page: 1, Limit: 10}}).Return([]entities.Book{}, nil)
+
+	bookServiceTest := bookService{bookDb: bookDbMock}
+	_, err := bookServiceTest.GetAllBooks(dtos.GetBooksFilter{Genre: " Science-Fiction "})
+
+	require.NoError(t, err)
+}
+
+func TestAddBookTags(t *testing.T) {
+	book := entities.Book{Id: 1, Name: "1984", Tags: []entities.Tag{{Id: 8, Name: "dystopia"}, {Id: 9, Name: "classic"}}}
+	tests := map[string]struct {
+		request               dtos.BookTagsRequest
+		expectedTags          []string
+		expectedErrorOnGet    error
+		expectedErrorOnAdd    error
+		expectedErrorResponse error
+	}{
+		"success on add tags": {
+			request:      dtos.BookTagsRequest{Tags: []string{" Dystopia", "CLASSIC", "dystopia "}},
+			expectedTags: []string{"dystopia", "classic"},
+		},
+		"error occurred on add tags (no tags)": {
+			request:               dtos.BookTagsRequest{},
+			expectedErrorResponse: utils.ErrInvalidTag,
+		},
+		"error occurred on add tags (invalid tag)": {
+			request:               dtos.BookTagsRequest{Tags: []string{"dystopia", "a,b"}},
+			expectedErrorResponse: utils.ErrInvalidTag,
+		},
+		"error occurred on add tags (book not found)": {
+			request:               dtos.BookTagsRequest{Tags: []string{"dystopia", "classic"}},
+			expectedTags:          []string{"dystopia", "classic"},
+			expectedErrorOnGet:    gorm.ErrRecordNotFound,
+			expectedErrorResponse: utils.ErrBookIdNotFound,
+		},
+		"error occurred on add tags (add)": {
+			request:               dtos.BookTagsRequest{Tags: []string{"dystopia", "classic"}},
+			expectedTags:          []string{"dystopia", "classic"},
+			expectedErrorOnAdd:    errGeneric,
+			expectedErrorResponse: errGeneric,
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			bookDbMock := new(bookrepomock.BookRepositoryMock)
+			bookDbMock.On("GetBook", 1).Return(book, tc.expectedErrorOnGet)
+			bookDbMock.On("AddBookTags", 1, tc.expectedTags).Return(tc.expectedErrorOnAdd)
+
+			bookServiceTest := bookService{bookDb: bookDbMock}
+			resp, err := bookServiceTest.AddBookTags(1, tc.request)
+			if tc.expectedErrorResponse != nil {
+				require.ErrorIs(t, err, tc.expectedErrorResponse)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, []string{"dystopia", "classic"}, resp.Tags)
+				bookDbMock.AssertCalled(t, "AddBookTags", 1, tc.expectedTags)
+			}
+		})
+	}
+}
+
+func TestRemoveBookTag(t *testing.T) {
+	tests := map[string]struct {
+		tag                   string
+		expectedErrorOnGet    error
+		expectedErrorOnRemove error
+		expectedErrorResponse error
+	}{
+		"success on remove tag": {
+			tag: " Dystopia ",
+		},
+		"error occurred on remove tag (invalid tag)": {
+			tag:                   " ",
+			expectedErrorResponse: utils.ErrInvalidTag,
+		},
+		"error occurred on remove tag (book not found)": {
+			tag:                   "dystopia",
+			expectedErrorOnGet:    gorm.ErrRecordNotFound,
+			expectedErrorResponse: utils.ErrBookIdNotFound,
+		},
+		"error occurred on remove tag (book does not have it)": {
+			tag:                   "dystopia",
+			expectedErrorOnRemove: gorm.ErrRecordNotFound,
+			expectedErrorResponse: utils.ErrBookTagNotFound,
+		},
+		"error occurred on remove tag (remove)": {
+			tag:                   "dystopia",
+			expectedErrorOnRemove: errGeneric,
+			expectedErrorResponse: errGeneric,
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			bookDbMock := new(bookrepomock.BookRepositoryMock)
+			bookDbMock.On("GetBook", 1).Return(entities.Book{Id: 1}, tc.expectedErrorOnGet)
+			bookDbMock.On("RemoveBookTag", 1, "dystopia").Return(tc.expectedErrorOnRemove)
+
+			bookServiceTest := bookService{bookDb: bookDbMock}
+			err := bookServiceTest.RemoveBookTag(1, tc.tag)
+			require.ErrorIs(t, err, tc.expectedErrorResponse)
+		})
+	}
+}
+
+func TestGetAllBooksFilterTags(t *testing.T) {
+	bookDbMock := new(bookrepomock.BookRepositoryMock)
+	bookDbMock.On("GetAllBooks", dtos.GetBooksFilter{Tags: "dystopia,classic", TagsMatch: dtos.TagsMatchAll, Pagination: dtos.Pagination{Page: 1, Limit: 10}}).Return([]entities.Book{}, nil)
+
+	bookServiceTest := bookService{bookDb: bookDbMock}
+	_, err := bookServiceTest.GetAllBooks(dtos.GetBooksFilter{Tags: "Dystopia, classic,dystopia", TagsMatch: " ALL"})
+	require.NoError(t, err)
+
+	_, err = bookServiceTest.GetAllBooks(dtos.GetBooksFilter{Tags: "dystopia", TagsMatch: "some"})
+	require.ErrorIs(t, err, utils.ErrInvalidTagsMatch)
+
+	_, err = bookServiceTest.GetAllBooks(dtos.GetBooksFilter{Tags: "dystopia,,classic"})
+	require.ErrorIs(t, err, utils.ErrInvalidTag)
+}
+
+func TestGetAllBooksRating(t *testing.T) {
+	bookDbMock := new(bookrepomock.BookRepositoryMock)
+	bookDbMock.On("GetAllBooks", dtos.GetBooksFilter{MinRating: 4, Sort: dtos.BookSortRating, Pagination: dtos.Pagination{Page: 1, Limit: 10}}).
+		Return([]entities.Book{{Id: 1, Name: "Dom Casmurro", RatingAverage: 4.5, RatingCount: 2}}, nil)
+
+	bookServiceTest := bookService{bookDb: bookDbMock}
+	resp, err := bookServiceTest.GetAllBooks(dtos.GetBooksFilter{MinRating: 4, Sort: " Rating"})
+	require.NoError(t, err)
+	require.Equal(t, dtos.BookRatingResponse{Average: 4.5, Count: 2}, resp.Books[0].Rating)
+
+	_, err = bookServiceTest.GetAllBooks(dtos.GetBooksFilter{MinRating: 5.5})
+	require.ErrorIs(t, err, utils.ErrInvalidMinRating)
+
+	_, err = bookServiceTest.GetAllBooks(dtos.GetBooksFilter{Sort: "year"})
+	require.ErrorIs(t, err, utils.ErrInvalidBookSort)
+}
+
+func TestCreateBookSeries(t *testing.T) {
+	var (
+		authors  = []entities.Author{{Id: 5, Name: "joenk"}}
+		seriesId = 4
+		series   = entities.Series{Id: seriesId, Name: "The Lord of the Rings"}
+		position = 2.5
+	)
+	tests := map[string]struct {
+		seriesId              int
+		position              float64
+		expectedErrorOnGet    error
+		expectedSeries        string
+		expectedPosition      float64
+		expectedErrorResponse error
+		expectedBookSeriesId  *int
+		expectedBookPosition  *float64
+	}{
+		"success on create book in series": {
+			seriesId:             seriesId,
+			position:             position,
+			expectedSeries:       "The Lord of the Rings",
+			expectedPosition:     position,
+			expectedBookSeriesId: &seriesId,
+			expectedBookPosition: &position,
+		},
+		"success on create book in series without position": {
+			seriesId:             seriesId,
+			expectedSeries:       "The Lord of the Rings",
+			expectedBookSeriesId: &seriesId,
+		},
+		"success on create book without series": {},
+		"error occurred on create book (position without series)": {
+			position:              position,
+			expectedErrorResponse: utils.ErrInvalidSeriesPosition,
+		},
+		"error occurred on create book (negative position)": {
+			seriesId:              seriesId,
+			position:              -1,
+			expectedErrorResponse: utils.ErrInvalidSeriesPosition,
+		},
+		"error occurred on create book (series not found)": {
+			seriesId:              seriesId,
+			expectedErrorOnGet:    gorm.ErrRecordNotFound,
+			expectedErrorResponse: utils.ErrSeriesIdNotFound,
+		},
+		"error occurred on create book (get series)": {
+			seriesId:              seriesId,
+			expectedErrorOnGet:    errGeneric,
+			expectedErrorResponse: errGeneric,
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			book := withNewWork(entities.Book{Name: "book", NormalizedName: "book", Edition: "first", PublicationYear: 2022, Contributors: contributors(authors...),
+				SeriesId: tc.expectedBookSeriesId, SeriesPosition: tc.expectedBookPosition})
+			if tc.expectedBookSeriesId != nil {
+				book.Series = &series
+			}
+
+			authorDbMock := new(authorrepomock.AuthorRepositoryMock)
+			authorDbMock.On("GetAuthor", 5).Return(authors[0], nil)
+
+			seriesDbMock := new(seriesrepomock.SeriesRepositoryMock)
+			seriesDbMock.On("GetSeries", seriesId).Return(series, tc.expectedErrorOnGet)
+
+			bookDbMock := new(bookrepomock.BookRepositoryMock)
+			bookDbMock.On("CreateBook", book).Return(book, nil)
+
+			bookServiceTest := bookService{authorDb: authorDbMock, bookDb: bookDbMock, seriesDb: seriesDbMock, workDb: newWorkDbMock()}
+			resp, err := bookServiceTest.CreateBook(dtos.BookRequestCreate{Name: "book", Edition: "first", PublicationYear: 2022, Authors: []dtos.BookAuthorRequest{{Id: 5}},
+				SeriesId: tc.seriesId, SeriesPosition: tc.position})
+			if tc.expectedErrorResponse != nil {
+				require.ErrorIs(t, err, tc.expectedErrorResponse)
+				bookDbMock.AssertNotCalled(t, "CreateBook", book)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tc.expectedSeries, resp.Series)
+				require.Equal(t, tc.expectedPosition, resp.SeriesPosition)
+			}
+		})
+	}
+}
+
+func TestMergeBooksTakesSeriesOfSource(t *testing.T) {
+	var (
+		seriesId = 4
+		position = 3.0
+		target   = entities.Book{Id: 1, Name: "book", NormalizedName: "book", Edition: "first", PublicationYear: 2022}
+		source   = entities.Book{Id: 2, Name: "book!", NormalizedName: "book", SeriesId: &seriesId, Series: &entities.Series{Id: seriesId}, SeriesPosition: &position}
+		merged   = entities.Book{Id: 1, Name: "book", NormalizedName: "book", Edition: "first", PublicationYear: 2022, SeriesId: &seriesId, SeriesPosition: &position}
+	)
+
+	bookDbMock := new(bookrepomock.BookRepositoryMock)
+	bookDbMock.On("GetBook", 1).Return(target, nil)
+	bookDbMock.On("GetBook", 2).Return(source, nil)
+	bookDbMock.On("MergeBooks", merged, 2).Return(nil)
+
+	bookServiceTest := bookService{bookDb: bookDbMock, coverDb: newCoverDbMock()}
+	_, err := bookServiceTest.MergeBooks(1, 2)
+
+	require.NoError(t, err)
+	bookDbMock.AssertCalled(t, "MergeBooks", merged, 2)
+}
+
+func TestCreateBookLanguage(t *testing.T) {
+	tests := map[string]struct {
+		language              string
+		titles                map[string]string
+		expectedLanguage      string
+		expectedTitles        []entities.BookTitle
+		expectedErrorResponse error
+	}{
+		"success on create book with language and titles": {
+			language:         " ENG",
+			titles:           map[string]string{"pt_br": " O Hobbit ", "es": "El Hobbit"},
+			expectedLanguage: "en",
+			expectedTitles:   []entities.BookTitle{{Locale: "es", Title: "El Hobbit"}, {Locale: "pt-BR", Title: "O Hobbit"}},
+		},
+		"success on create book without language": {},
+		"error occurred on create book (invalid language)": {
+			language:              "english",
+			expectedErrorResponse: utils.ErrInvalidLanguage,
+		},
+		"error occurred on create book (invalid locale)": {
+			titles:                map[string]string{"brazil": "O Hobbit"},
+			expectedErrorResponse: utils.ErrInvalidLocale,
+		},
+		"error occurred on create book (empty title)": {
+			titles:                map[string]string{"pt-BR": " "},
+			expectedErrorResponse: utils.ErrBookTitleEmpty,
+		},
+		"error occurred on create book (repeated locale)": {
+			titles:                map[string]string{"pt-BR": "O Hobbit", "pt-br": "O Hobbit"},
+			expectedErrorResponse: utils.ErrBookTitleRepeated,
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			book := withNewWork(entities.Book{Name: "The Hobbit", NormalizedName: "the hobbit", Language: tc.expectedLanguage, Titles: tc.expectedTitles})
+
+			bookDbMock := new(bookrepomock.BookRepositoryMock)
+			bookDbMock.On("CreateBook", book).Return(book, nil)
+
+			bookServiceTest := bookService{bookDb: bookDbMock, workDb: newWorkDbMock()}
+			resp, err := bookServiceTest.CreateBook(dtos.BookRequestCreate{Name: "The Hobbit", Language: tc.language, Titles: tc.titles})
+			if tc.expectedErrorResponse != nil {
+				require.ErrorIs(t, err, tc.expectedErrorResponse)
+				bookDbMock.AssertNotCalled(t, "CreateBook", mock.Anything)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedLanguage, resp.Language)
+			if tc.expectedTitles != nil {
+				require.Equal(t, map[string]string{"es": "El Hobbit", "pt-BR": "O Hobbit"}, resp.Titles)
+			} else {
+				require.Nil(t, resp.Titles)
+			}
+		})
+	}
+}
+
+func TestGetAllBooksFilterLanguage(t *testing.T) {
+	bookDbMock := new(bookrepomock.BookRepositoryMock)
+	bookDbMock.On("GetAllBooks", dtos.GetBooksFilter{Language: "pt", Pagination: dtos.Pagination{Page: 1, Limit: 10}}).Return([]entities.Book{}, nil)
+
+	bookServiceTest := bookService{bookDb: bookDbMock}
+	_, err := bookServiceTest.GetAllBooks(dtos.GetBooksFilter{Language: "por"})
+	require.NoError(t, err)
+
+	_, err = bookServiceTest.GetAllBooks(dtos.GetBooksFilter{Language: "portuguese"})
+	require.ErrorIs(t, err, utils.ErrInvalidLanguage)
+}
+
+func TestMergeBooksTakesLanguageOfSource(t *testing.T) {
+	var (
+		target = entities.Book{Id: 1, Name: "book", NormalizedName: "book", Titles: []entities.BookTitle{{BookId: 1, Locale: "es", Title: "libro"}}}
+		source = entities.Book{Id: 2, Name: "book", NormalizedName: "book", Language: "en"}
+		merged = entities.Book{Id: 1, Name: "book", NormalizedName: "book", Language: "en"}
+	)
+
+	bookDbMock := new(bookrepomock.BookRepositoryMock)
+	bookDbMock.On("GetBook", 1).Return(target, nil)
+	bookDbMock.On("GetBook", 2).Return(source, nil)
+	bookDbMock.On("MergeBooks", merged, 2).Return(nil)
+
+	bookServiceTest := bookService{bookDb: bookDbMock, coverDb: newCoverDbMock()}
+	_, err := bookServiceTest.MergeBooks(1, 2)
+
+	require.NoError(t, err)
+	bookDbMock.AssertCalled(t, "MergeBooks", merged, 2)
+}
+
+func TestGetBookAvailability(t *testing.T) {
+	bookDbMock := new(bookrepomock.BookRepositoryMock)
+	bookDbMock.On("GetBook", 1).Return(entities.Book{Id: 1, Name: "book", Copies: []entities.Copy{
+		{Id: 1, BookId: 1, Status: dtos.CopyStatusAvailable},
+		{Id: 2, BookId: 1, Status: dtos.CopyStatusAvailable},
+		{Id: 3, BookId: 1, Status: dtos.CopyStatusOnLoan},
+		{Id: 4, BookId: 1, Status: dtos.CopyStatusLost},
+		{Id: 5, BookId: 1, Status: dtos.CopyStatusWithdrawn},
+		{Id: 6, BookId: 1, Status: dtos.CopyStatusOnHold}}}, nil)
+
+	bookServiceTest := bookService{bookDb: bookDbMock}
+	resp, err := bookServiceTest.GetBook(1)
+
+	require.NoError(t, err)
+	require.Equal(t, dtos.BookAvailabilityResponse{Copies: 4, Available: 2, OnHold: 1}, resp.Availability)
+}
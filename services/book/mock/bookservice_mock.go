@@ -42,3 +42,43 @@ func (m *BookServiceMock) UpdateBook(id int, bookRequestUpdate dtos.BookRequestU
 	args := m.Called(id, bookRequestUpdate)
 	return args.Get(0).(dtos.BookResponse), args.Error(1)
 }
+
+func (m *BookServiceMock) GetDuplicateBooks(pagination dtos.Pagination) (dtos.BookDuplicatesResponseMetadata, error) {
+	args := m.Called(pagination)
+	return args.Get(0).(dtos.BookDuplicatesResponseMetadata), args.Error(1)
+}
+
+func (m *BookServiceMock) MergeBooks(targetId, sourceId int) (dtos.BookResponse, error) {
+	args := m.Called(targetId, sourceId)
+	return args.Get(0).(dtos.BookResponse), args.Error(1)
+}
+
+func (m *BookServiceMock) BackfillNormalizedNames() error {
+	args := m.Called()
+	return args.Error(0)
+}
+
+func (m *BookServiceMock) BackfillWorks() error {
+	args := m.Called()
+	return args.Error(0)
+}
+
+func (m *BookServiceMock) AddRoleToContributorsKey() error {
+	args := m.Called()
+	return args.Error(0)
+}
+
+func (m *BookServiceMock) GetBookByIsbn(isbn string) (dtos.BookResponse, error) {
+	args := m.Called(isbn)
+	return args.Get(0).(dtos.BookResponse), args.Error(1)
+}
+
+func (m *BookServiceMock) AddBookTags(id int, bookTagsRequest dtos.BookTagsRequest) (dtos.BookResponse, error) {
+	args := m.Called(id, bookTagsRequest)
+	return args.Get(0).(dtos.BookResponse), args.Error(1)
+}
+
+func (m *BookServiceMock) RemoveBookTag(id int, tag string) error {
+	args := m.Called(id, tag)
+	return args.Error(0)
+}
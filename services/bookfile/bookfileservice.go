@@ -0,0 +1,411 @@
+package services
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github/brunojoenk/golang-test/config"
+	"github/brunojoenk/golang-test/models/dtos"
+	"github/brunojoenk/golang-test/models/entities"
+	bookrepo "github/brunojoenk/golang-test/repository/book"
+	bookfilerepo "github/brunojoenk/golang-test/repository/bookfile"
+	"github/brunojoenk/golang-test/storage"
+	"github/brunojoenk/golang-test/utils"
+	"io"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// MAX_BOOK_FILE_NAME_LENGTH is the most characters kept of the name of an uploaded file, extension included
+var MAX_BOOK_FILE_NAME_LENGTH = 150
+
+var bookFileContentTypes = map[string]string{
+	dtos.BookFileFormatPdf:  "application/pdf",
+	dtos.BookFileFormatEpub: "application/epub+zip",
+}
+
+type IBookFileService interface {
+	CreateBookFile(bookId int, name string, body io.Reader, prefill bool) (dtos.BookFileUploadResponse, error)
+	GetBookFiles(bookId int) ([]dtos.BookFileResponse, error)
+	GetBookFile(bookId, id int) (dtos.BookFileResponse, error)
+	DeleteBookFile(bookId, id int) error
+	GetDownloadLink(bookId, id int) (dtos.DownloadLinkResponse, error)
+	OpenDownload(id int, expires, signature string) (dtos.BookFileDownload, error)
+}
+
+type bookFileService struct {
+	bookFileDb   bookfilerepo.IBookFileRepository
+	bookDb       bookrepo.IBookRepository
+	store        storage.BlobStore
+	maxBytes     int
+	linkSecret   []byte
+	linkDuration time.Duration
+	now          func() time.Time
+}
+
+// NewBookFileService Service Constructor. Without a download link secret configured a random one is
+// used, and the links stop working when the app restarts
+func NewBookFileService(db *gorm.DB, cfg *config.Config, store storage.BlobStore) IBookFileService {
+	secret := []byte(cfg.DownloadLinkSecret)
+	if len(secret) == 0 {
+		log.Warn("DOWNLOAD_LINK_SECRET is not set, download links will not survive a restart")
+		secret = make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			log.Fatal("Error on generate download link secret: ", err.Error())
+		}
+	}
+
+	return &bookFileService{
+		bookFileDb:   bookfilerepo.NewBookFileRepository(db),
+		bookDb:       bookrepo.NewBookRepository(db),
+		store:        store,
+		maxBytes:     cfg.BookFileMaxBytes,
+		linkSecret:   secret,
+		linkDuration: time.Duration(cfg.DownloadLinkMinutes) * time.Minute,
+		now:          time.Now,
+	}
+}
+
+// CreateBookFile stores a PDF or EPUB file of the book with the metadata read from it, and compares the
+// metadata with the book. With prefill, the language and ISBN the book has empty are filled from the file
+func (s *bookFileService) CreateBookFile(bookId int, name string, body io.Reader, prefill bool) (dtos.BookFileUploadResponse, error) {
+	book, err := s.bookDb.GetBook(bookId)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return dtos.BookFileUploadResponse{}, utils.ErrBookIdNotFound
+		}
+		log.Error("Error on get book from repo: ", err.Error())
+		return dtos.BookFileUploadResponse{}, err
+	}
+
+	data, err := io.ReadAll(io.LimitReader(body, int64(s.maxBytes)+1))
+	if err != nil {
+		log.Error("Error on read book file: ", err.Error())
+		return dtos.BookFileUploadResponse{}, err
+	}
+	if len(data) > s.maxBytes {
+		return dtos.BookFileUploadResponse{}, utils.ErrBookFileTooLarge
+	}
+
+	format, metadata, err := readMetadata(data)
+	if err != nil {
+		return dtos.BookFileUploadResponse{}, err
+	}
+
+	now := s.now()
+	file := entities.BookFile{
+		BookId:    bookId,
+		Key:       fmt.Sprintf("files/%d/%d.%s", bookId, now.UnixNano(), format),
+		Name:      fileName(name, bookId, format),
+		Format:    format,
+		Size:      int64(len(data)),
+		Pages:     metadata.Pages,
+		Title:     metadata.Title,
+		Authors:   strings.Join(metadata.Authors, "\n"),
+		Language:  metadata.Language,
+		Isbn13:    metadata.Isbn13,
+		CreatedAt: now,
+	}
+
+	if err := s.store.Put(file.Key, bytes.NewReader(data), file.Size, bookFileContentTypes[format]); err != nil {
+		log.Error("Error on put book file on blob store: ", err.Error())
+		return dtos.BookFileUploadResponse{}, err
+	}
+
+	created, err := s.bookFileDb.CreateBookFile(file)
+	if err != nil {
+		log.Error("Error on create book file from repo: ", err.Error())
+		s.deleteBlob(file.Key)
+		return dtos.BookFileUploadResponse{}, err
+	}
+
+	mismatches, fields := compareBook(book, metadata)
+	prefilled := []string{}
+	if prefill {
+		prefilled = s.prefillBook(bookId, fields)
+	}
+
+	return dtos.BookFileUploadResponse{
+		BookFileResponse: toBookFileResponse(created),
+		Mismatches:       mismatches,
+		Prefilled:        prefilled,
+	}, nil
+}
+
+func (s *bookFileService) GetBookFiles(bookId int) ([]dtos.BookFileResponse, error) {
+	if err := s.checkBook(bookId); err != nil {
+		return nil, err
+	}
+
+	files, err := s.bookFileDb.GetBookFiles(bookId)
+	if err != nil {
+		log.Error("Error on get book files from repo: ", err.Error())
+		return nil, err
+	}
+
+	response := make([]dtos.BookFileResponse, 0, len(files))
+	for _, file := range files {
+		response = append(response, toBookFileResponse(file))
+	}
+	return response, nil
+}
+
+func (s *bookFileService) GetBookFile(bookId, id int) (dtos.BookFileResponse, error) {
+	file, err := s.getBookFile(bookId, id)
+	if err != nil {
+		return dtos.BookFileResponse{}, err
+	}
+	return toBookFileResponse(file), nil
+}
+
+// DeleteBookFile removes the file of the book and its content from the store
+func (s *bookFileService) DeleteBookFile(bookId, id int) error {
+	file, err := s.getBookFile(bookId, id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.bookFileDb.DeleteBookFile(id); err != nil {
+		log.Error("Error on delete book file from repo: ", err.Error())
+		return err
+	}
+
+	s.deleteBlob(file.Key)
+	return nil
+}
+
+// GetDownloadLink returns a link to download the file of the book, signed so anyone holding it can
+// download the file, without an api key, until it expires
+func (s *bookFileService) GetDownloadLink(bookId, id int) (dtos.DownloadLinkResponse, error) {
+	if _, err := s.getBookFile(bookId, id); err != nil {
+		return dtos.DownloadLinkResponse{}, err
+	}
+
+	expiresAt := s.now().Add(s.linkDuration).Truncate(time.Second)
+	expires := expiresAt.Unix()
+	return dtos.DownloadLinkResponse{
+		Url:       fmt.Sprintf("/downloads/%d?expires=%d&signature=%s", id, expires, s.sign(id, expires)),
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+// OpenDownload checks the signature and the expiry of a download link and opens the file it points to
+func (s *bookFileService) OpenDownload(id int, expires, signature string) (dtos.BookFileDownload, error) {
+	expiresAt, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || s.now().Unix() > expiresAt ||
+		!hmac.Equal([]byte(s.sign(id, expiresAt)), []byte(strings.ToLower(signature))) {
+		return dtos.BookFileDownload{}, utils.ErrInvalidDownloadLink
+	}
+
+	file, err := s.bookFileDb.GetBookFile(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return dtos.BookFileDownload{}, utils.ErrBookFileNotFound
+		}
+		log.Error("Error on get book file from repo: ", err.Error())
+		return dtos.BookFileDownload{}, err
+	}
+
+	return dtos.BookFileDownload{
+		Body:        storage.NewBlobReader(s.store, file.Key, file.Size),
+		Name:        file.Name,
+		ContentType: bookFileContentTypes[file.Format],
+		ModTime:     file.CreatedAt,
+	}, nil
+}
+
+// sign is the HMAC-SHA256 of the file and the expiry of a download link
+func (s *bookFileService) sign(id int, expires int64) string {
+	mac := hmac.New(sha256.New, s.linkSecret)
+	fmt.Fprintf(mac, "%d:%d", id, expires)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// prefillBook fills the empty fields of the book read from its file and returns their names. An ISBN
+// another book already has is left out, and a failure is only logged, the file being already stored
+func (s *bookFileService) prefillBook(bookId int, fields entities.Book) []string {
+	if fields.Isbn13 != nil {
+		if _, err := s.bookDb.GetBookByIsbn(*fields.Isbn13); !errors.Is(err, gorm.ErrRecordNotFound) {
+			if err != nil {
+				log.Error("Error on get book by isbn from repo: ", err.Error())
+			}
+			fields.Isbn13 = nil
+		}
+	}
+
+	prefilled := []string{}
+	if fields.Language != "" {
+		prefilled = append(prefilled, "language")
+	}
+	if fields.Isbn13 != nil {
+		prefilled = append(prefilled, "isbn13")
+	}
+	if len(prefilled) == 0 {
+		return prefilled
+	}
+
+	if err := s.bookDb.PrefillBook(bookId, fields); err != nil {
+		log.Error("Error on prefill book from repo: ", err.Error())
+		return []string{}
+	}
+	return prefilled
+}
+
+func (s *bookFileService) checkBook(bookId int) error {
+	if _, err := s.bookDb.GetBook(bookId); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return utils.ErrBookIdNotFound
+		}
+		log.Error("Error on get book from repo: ", err.Error())
+		return err
+	}
+	return nil
+}
+
+// getBookFile returns the file if it belongs to the book
+func (s *bookFileService) getBookFile(bookId, id int) (entities.BookFile, error) {
+	if err := s.checkBook(bookId); err != nil {
+		return entities.BookFile{}, err
+	}
+
+	file, err := s.bookFileDb.GetBookFile(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return entities.BookFile{}, utils.ErrBookFileNotFound
+		}
+		log.Error("Error on get book file from repo: ", err.Error())
+		return entities.BookFile{}, err
+	}
+	if file.BookId != bookId {
+		return entities.BookFile{}, utils.ErrBookFileNotFound
+	}
+	return file, nil
+}
+
+// deleteBlob removes the content of a file from the store. A failure only leaves it behind, so it is
+// logged and not returned
+func (s *bookFileService) deleteBlob(key string) {
+	if err := s.store.Delete(key); err != nil {
+		log.Error("Error on delete book file from blob store: ", err.Error())
+	}
+}
+
+// compareBook compares the metadata read from a file with the book. It returns the fields that differ,
+// and a book with the fields the book has empty set to their values on the file
+func compareBook(book entities.Book, metadata fileMetadata) ([]dtos.BookFileMismatch, entities.Book) {
+	mismatches := []dtos.BookFileMismatch{}
+	var fields entities.Book
+
+	if metadata.Title != "" && !hasTitle(book, metadata.Title) {
+		mismatches = append(mismatches, dtos.BookFileMismatch{Field: "title", Book: book.Name, File: metadata.Title})
+	}
+
+	if len(metadata.Authors) > 0 {
+		var bookAuthors []string
+		for _, contributor := range book.Contributors {
+			if contributor.Role == dtos.RoleAuthor || contributor.Role == "" {
+				bookAuthors = append(bookAuthors, contributor.Author.Name)
+			}
+		}
+		if !sameAuthors(bookAuthors, metadata.Authors) {
+			mismatches = append(mismatches, dtos.BookFileMismatch{Field: "authors",
+				Book: strings.Join(bookAuthors, "; "), File: strings.Join(metadata.Authors, "; ")})
+		}
+	}
+
+	if book.Language == "" {
+		fields.Language = metadata.Language
+	} else if metadata.Language != "" && metadata.Language != book.Language {
+		mismatches = append(mismatches, dtos.BookFileMismatch{Field: "language", Book: book.Language, File: metadata.Language})
+	}
+
+	if book.Isbn13 == nil || *book.Isbn13 == "" {
+		if metadata.Isbn13 != "" {
+			isbn13 := metadata.Isbn13
+			fields.Isbn13 = &isbn13
+		}
+	} else if metadata.Isbn13 != "" && metadata.Isbn13 != *book.Isbn13 {
+		mismatches = append(mismatches, dtos.BookFileMismatch{Field: "isbn13", Book: *book.Isbn13, File: metadata.Isbn13})
+	}
+
+	return mismatches, fields
+}
+
+// hasTitle tells if the title is the name of the book or one of its translated titles, ignoring case,
+// accents and punctuation
+func hasTitle(book entities.Book, title string) bool {
+	normalized := utils.NormalizeName(title)
+	if utils.NormalizeName(book.Name) == normalized {
+		return true
+	}
+	for _, bookTitle := range book.Titles {
+		if utils.NormalizeName(bookTitle.Title) == normalized {
+			return true
+		}
+	}
+	return false
+}
+
+// sameAuthors tells if both lists have the same authors, in any order and however their names are written
+func sameAuthors(a, b []string) bool {
+	normalize := func(names []string) []string {
+		normalized := make([]string, 0, len(names))
+		for _, name := range names {
+			normalized = append(normalized, utils.NormalizeAuthorName(name))
+		}
+		sort.Strings(normalized)
+		return normalized
+	}
+	return strings.Join(normalize(a), "\n") == strings.Join(normalize(b), "\n")
+}
+
+// fileName is the name a file is downloaded as: the base name of the uploaded file, or book-{id}, with
+// the extension of its format
+func fileName(name string, bookId int, format string) string {
+	name = strings.TrimSpace(path.Base(strings.ReplaceAll(name, "\\", "/")))
+	if name == "." || name == "/" || name == "" {
+		name = fmt.Sprintf("book-%d", bookId)
+	}
+	if strings.EqualFold(path.Ext(name), "."+format) {
+		name = name[:len(name)-len(format)-1]
+	}
+
+	extension := "." + format
+	if utf8.RuneCountInString(name) > MAX_BOOK_FILE_NAME_LENGTH-len(extension) {
+		name = string([]rune(name)[:MAX_BOOK_FILE_NAME_LENGTH-len(extension)])
+	}
+	return name + extension
+}
+
+func toBookFileResponse(file entities.BookFile) dtos.BookFileResponse {
+	authors := []string{}
+	if file.Authors != "" {
+		authors = strings.Split(file.Authors, "\n")
+	}
+
+	return dtos.BookFileResponse{
+		Id:          file.Id,
+		BookId:      file.BookId,
+		Name:        file.Name,
+		Format:      file.Format,
+		ContentType: bookFileContentTypes[file.Format],
+		Size:        file.Size,
+		Pages:       file.Pages,
+		Title:       file.Title,
+		Authors:     authors,
+		Language:    file.Language,
+		Isbn13:      file.Isbn13,
+		CreatedAt:   file.CreatedAt,
+	}
+}
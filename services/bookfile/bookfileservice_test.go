@@ -0,0 +1,492 @@
+package services
+
+import (
+	"bytes"
+	"errors"
+	"github/brunojoenk/golang-test/models/dtos"
+	"github/brunojoenk/golang-test/models/entities"
+	bookrepomock "github/brunojoenk/golang-test/repository/book/mock"
+	bookfilerepomock "github/brunojoenk/golang-test/repository/bookfile/mock"
+	"github/brunojoenk/golang-test/storage"
+	storagemock "github/brunojoenk/golang-test/storage/mock"
+	"github/brunojoenk/golang-test/utils"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+var errGeneric = errors.New("generic error")
+
+var now = time.Date(2022, 10, 1, 10, 0, 0, 0, time.UTC)
+
+func newTestBookFileService(bookFileDb *bookfilerepomock.BookFileRepositoryMock, bookDb *bookrepomock.BookRepositoryMock,
+	store storage.BlobStore) *bookFileService {
+	return &bookFileService{
+		bookFileDb:   bookFileDb,
+		bookDb:       bookDb,
+		store:        store,
+		maxBytes:     1 << 20,
+		linkSecret:   []byte("secret"),
+		linkDuration: 15 * time.Minute,
+		now:          func() time.Time { return now },
+	}
+}
+
+func testBook() entities.Book {
+	isbn13 := ""
+	return entities.Book{
+		Id:   7,
+		Name: "Orgulho e Preconceito",
+		Titles: []entities.BookTitle{
+			{BookId: 7, Locale: "en", Title: "Pride and Prejudice"},
+		},
+		Isbn13: &isbn13,
+		Contributors: []entities.AuthorBook{
+			{AuthorId: 1, Author: entities.Author{Id: 1, Name: "Austen, Jane"}, Role: dtos.RoleAuthor},
+			{AuthorId: 2, Author: entities.Author{Id: 2, Name: "Hugh Thomson"}, Role: dtos.RoleIllustrator},
+		},
+	}
+}
+
+func TestCreateBookFile(t *testing.T) {
+	epub := testEpub(t, "application/epub+zip", map[string]string{
+		"META-INF/container.xml": testContainer,
+		"OEBPS/content.opf":      testPackage,
+	})
+	pdf := testPdf(t)
+
+	tests := map[string]struct {
+		data                   []byte
+		name                   string
+		book                   entities.Book
+		prefill                bool
+		expectedErrorOnGetBook error
+		expectedErrorOnIsbn    error
+		expectedResponse       dtos.BookFileUploadResponse
+		expectedPrefill        *entities.Book
+		expectedErrorResponse  error
+	}{
+		"success on create epub file": {
+			data: epub,
+			name: "pride-and-prejudice.EPUB",
+			book: testBook(),
+			expectedResponse: dtos.BookFileUploadResponse{
+				BookFileResponse: dtos.BookFileResponse{Id: 3, BookId: 7, Name: "pride-and-prejudice.epub", Format: "epub",
+					ContentType: "application/epub+zip", Size: int64(len(epub)), Title: "Pride and Prejudice",
+					Authors: []string{"Jane Austen"}, Language: "en", Isbn13: "9780141439518", CreatedAt: now},
+				Mismatches: []dtos.BookFileMismatch{},
+				Prefilled:  []string{},
+			},
+		},
+		"success on create epub file with prefill": {
+			data:                epub,
+			book:                testBook(),
+			prefill:             true,
+			expectedErrorOnIsbn: gorm.ErrRecordNotFound,
+			expectedResponse: dtos.BookFileUploadResponse{
+				BookFileResponse: dtos.BookFileResponse{Id: 3, BookId: 7, Name: "book-7.epub", Format: "epub",
+					ContentType: "application/epub+zip", Size: int64(len(epub)), Title: "Pride and Prejudice",
+					Authors: []string{"Jane Austen"}, Language: "en", Isbn13: "9780141439518", CreatedAt: now},
+				Mismatches: []dtos.BookFileMismatch{},
+				Prefilled:  []string{"language", "isbn13"},
+			},
+			expectedPrefill: &entities.Book{Language: "en", Isbn13: func() *string { isbn13 := "9780141439518"; return &isbn13 }()},
+		},
+		"success on create epub file with prefill (isbn of another book)": {
+			data:    epub,
+			book:    testBook(),
+			prefill: true,
+			expectedResponse: dtos.BookFileUploadResponse{
+				BookFileResponse: dtos.BookFileResponse{Id: 3, BookId: 7, Name: "book-7.epub", Format: "epub",
+					ContentType: "application/epub+zip", Size: int64(len(epub)), Title: "Pride and Prejudice",
+					Authors: []string{"Jane Austen"}, Language: "en", Isbn13: "9780141439518", CreatedAt: now},
+				Mismatches: []dtos.BookFileMismatch{},
+				Prefilled:  []string{"language"},
+			},
+			expectedPrefill: &entities.Book{Language: "en"},
+		},
+		"success on create epub file (mismatches)": {
+			data: epub,
+			name: "../../etc/emma",
+			book: func() entities.Book {
+				isbn13 := "9780141439587"
+				return entities.Book{Id: 7, Name: "Emma", Language: "pt", Isbn13: &isbn13}
+			}(),
+			prefill: true,
+			expectedResponse: dtos.BookFileUploadResponse{
+				BookFileResponse: dtos.BookFileResponse{Id: 3, BookId: 7, Name: "emma.epub", Format: "epub",
+					ContentType: "application/epub+zip", Size: int64(len(epub)), Title: "Pride and Prejudice",
+					Authors: []string{"Jane Austen"}, Language: "en", Isbn13: "9780141439518", CreatedAt: now},
+				Mismatches: []dtos.BookFileMismatch{
+					{Field: "title", Book: "Emma", File: "Pride and Prejudice"},
+					{Field: "authors", Book: "", File: "Jane Austen"},
+					{Field: "language", Book: "pt", File: "en"},
+					{Field: "isbn13", Book: "9780141439587", File: "9780141439518"},
+				},
+				Prefilled: []string{},
+			},
+		},
+		"success on create pdf file": {
+			data: pdf,
+			name: "scan.pdf",
+			book: testBook(),
+			expectedResponse: dtos.BookFileUploadResponse{
+				BookFileResponse: dtos.BookFileResponse{Id: 3, BookId: 7, Name: "scan.pdf", Format: "pdf",
+					ContentType: "application/pdf", Size: int64(len(pdf)), Pages: 4, Authors: []string{}, CreatedAt: now},
+				Mismatches: []dtos.BookFileMismatch{},
+				Prefilled:  []string{},
+			},
+		},
+		"error on create book file (book not found)": {
+			data:                   pdf,
+			expectedErrorOnGetBook: gorm.ErrRecordNotFound,
+			expectedErrorResponse:  utils.ErrBookIdNotFound,
+		},
+		"error on create book file (get book)": {
+			data:                   pdf,
+			expectedErrorOnGetBook: errGeneric,
+			expectedErrorResponse:  errGeneric,
+		},
+		"error on create book file (too large)": {
+			data:                  append(pdf, make([]byte, 1<<20)...),
+			expectedErrorResponse: utils.ErrBookFileTooLarge,
+		},
+		"error on create book file (not a pdf or epub)": {
+			data:                  []byte("<html></html>"),
+			expectedErrorResponse: utils.ErrInvalidBookFileType,
+		},
+		"error on create book file (corrupted)": {
+			data:                  epub[:len(epub)/2],
+			expectedErrorResponse: utils.ErrInvalidBookFile,
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			bookDbMock := new(bookrepomock.BookRepositoryMock)
+			bookDbMock.On("GetBook", 7).Return(tc.book, tc.expectedErrorOnGetBook)
+			bookDbMock.On("GetBookByIsbn", "9780141439518").Return(entities.Book{Id: 9}, tc.expectedErrorOnIsbn)
+			bookDbMock.On("PrefillBook", 7, mock.Anything).Return(nil)
+			expected := tc.expectedResponse
+			file := entities.BookFile{BookId: 7, Key: "files/7/" + strconv.FormatInt(now.UnixNano(), 10) + "." + expected.Format,
+				Name: expected.Name, Format: expected.Format, Size: expected.Size, Pages: expected.Pages, Title: expected.Title,
+				Authors: strings.Join(expected.Authors, "\n"), Language: expected.Language, Isbn13: expected.Isbn13, CreatedAt: now}
+			created := file
+			created.Id = 3
+			bookFileDbMock := new(bookfilerepomock.BookFileRepositoryMock)
+			bookFileDbMock.On("CreateBookFile", file).Return(created, nil)
+			store := &storage.LocalBlobStore{Root: t.TempDir()}
+
+			bookFileServiceTest := newTestBookFileService(bookFileDbMock, bookDbMock, store)
+			response, err := bookFileServiceTest.CreateBookFile(7, tc.name, bytes.NewReader(tc.data), tc.prefill)
+
+			if tc.expectedErrorResponse != nil {
+				require.ErrorIs(t, err, tc.expectedErrorResponse)
+				bookFileDbMock.AssertNotCalled(t, "CreateBookFile", mock.Anything)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedResponse, response)
+
+			blob, err := store.Get(file.Key)
+			require.NoError(t, err)
+			stored, _ := io.ReadAll(blob.Body)
+			blob.Body.Close()
+			require.Equal(t, tc.data, stored)
+
+			if tc.expectedPrefill != nil {
+				bookDbMock.AssertCalled(t, "PrefillBook", 7, *tc.expectedPrefill)
+			} else {
+				bookDbMock.AssertNotCalled(t, "PrefillBook", mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestCreateBookFileErrors(t *testing.T) {
+	pdf := testPdf(t)
+
+	t.Run("error on create book file (blob store)", func(t *testing.T) {
+		bookDbMock := new(bookrepomock.BookRepositoryMock)
+		bookDbMock.On("GetBook", 7).Return(testBook(), nil)
+		bookFileDbMock := new(bookfilerepomock.BookFileRepositoryMock)
+		storeMock := new(storagemock.BlobStoreMock)
+		storeMock.On("Put", mock.Anything, mock.Anything, int64(len(pdf)), "application/pdf").Return(errGeneric)
+
+		_, err := newTestBookFileService(bookFileDbMock, bookDbMock, storeMock).CreateBookFile(7, "", bytes.NewReader(pdf), false)
+
+		require.ErrorIs(t, err, errGeneric)
+		bookFileDbMock.AssertNotCalled(t, "CreateBookFile", mock.Anything)
+	})
+
+	t.Run("error on create book file (repository)", func(t *testing.T) {
+		bookDbMock := new(bookrepomock.BookRepositoryMock)
+		bookDbMock.On("GetBook", 7).Return(testBook(), nil)
+		bookFileDbMock := new(bookfilerepomock.BookFileRepositoryMock)
+		bookFileDbMock.On("CreateBookFile", mock.Anything).Return(entities.BookFile{}, errGeneric)
+		store := &storage.LocalBlobStore{Root: t.TempDir()}
+
+		_, err := newTestBookFileService(bookFileDbMock, bookDbMock, store).CreateBookFile(7, "", bytes.NewReader(pdf), false)
+
+		require.ErrorIs(t, err, errGeneric)
+		created := bookFileDbMock.Calls[0].Arguments.Get(0).(entities.BookFile)
+		_, err = store.Get(created.Key)
+		require.ErrorIs(t, err, storage.ErrBlobNotFound)
+	})
+
+	t.Run("success on create book file (prefill fails)", func(t *testing.T) {
+		epub := testEpub(t, "application/epub+zip", map[string]string{
+			"META-INF/container.xml": testContainer,
+			"OEBPS/content.opf":      testPackage,
+		})
+		bookDbMock := new(bookrepomock.BookRepositoryMock)
+		bookDbMock.On("GetBook", 7).Return(testBook(), nil)
+		bookDbMock.On("GetBookByIsbn", "9780141439518").Return(entities.Book{}, gorm.ErrRecordNotFound)
+		bookDbMock.On("PrefillBook", 7, mock.Anything).Return(errGeneric)
+		bookFileDbMock := new(bookfilerepomock.BookFileRepositoryMock)
+		bookFileDbMock.On("CreateBookFile", mock.Anything).Return(entities.BookFile{Id: 3, BookId: 7, Format: "epub"}, nil)
+		store := &storage.LocalBlobStore{Root: t.TempDir()}
+
+		response, err := newTestBookFileService(bookFileDbMock, bookDbMock, store).CreateBookFile(7, "", bytes.NewReader(epub), true)
+
+		require.NoError(t, err)
+		require.Equal(t, 3, response.Id)
+		require.Empty(t, response.Prefilled)
+	})
+}
+
+func TestGetBookFiles(t *testing.T) {
+	tests := map[string]struct {
+		expectedErrorOnGetBook  error
+		expectedErrorOnGetFiles error
+		expectedResponse        []dtos.BookFileResponse
+		expectedErrorResponse   error
+	}{
+		"success on get book files": {
+			expectedResponse: []dtos.BookFileResponse{
+				{Id: 3, BookId: 7, Name: "book.pdf", Format: "pdf", ContentType: "application/pdf", Size: 10, Pages: 2,
+					Authors: []string{}, CreatedAt: now},
+				{Id: 4, BookId: 7, Name: "book.epub", Format: "epub", ContentType: "application/epub+zip", Size: 20,
+					Title: "Good Omens", Authors: []string{"Terry Pratchett", "Neil Gaiman"}, CreatedAt: now},
+			},
+		},
+		"error on get book files (book not found)": {
+			expectedErrorOnGetBook: gorm.ErrRecordNotFound,
+			expectedErrorResponse:  utils.ErrBookIdNotFound,
+		},
+		"error on get book files (repository)": {
+			expectedErrorOnGetFiles: errGeneric,
+			expectedErrorResponse:   errGeneric,
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			bookDbMock := new(bookrepomock.BookRepositoryMock)
+			bookDbMock.On("GetBook", 7).Return(entities.Book{Id: 7}, tc.expectedErrorOnGetBook)
+			bookFileDbMock := new(bookfilerepomock.BookFileRepositoryMock)
+			bookFileDbMock.On("GetBookFiles", 7).Return([]entities.BookFile{
+				{Id: 3, BookId: 7, Key: "files/7/1.pdf", Name: "book.pdf", Format: "pdf", Size: 10, Pages: 2, CreatedAt: now},
+				{Id: 4, BookId: 7, Key: "files/7/2.epub", Name: "book.epub", Format: "epub", Size: 20, Title: "Good Omens",
+					Authors: "Terry Pratchett\nNeil Gaiman", CreatedAt: now},
+			}, tc.expectedErrorOnGetFiles)
+
+			response, err := newTestBookFileService(bookFileDbMock, bookDbMock, nil).GetBookFiles(7)
+
+			require.ErrorIs(t, err, tc.expectedErrorResponse)
+			require.Equal(t, tc.expectedResponse, response)
+		})
+	}
+}
+
+func TestGetBookFile(t *testing.T) {
+	tests := map[string]struct {
+		bookId                 int
+		expectedErrorOnGetBook error
+		expectedErrorOnGetFile error
+		expectedErrorResponse  error
+	}{
+		"success on get book file": {
+			bookId: 7,
+		},
+		"error on get book file (book not found)": {
+			bookId:                 7,
+			expectedErrorOnGetBook: gorm.ErrRecordNotFound,
+			expectedErrorResponse:  utils.ErrBookIdNotFound,
+		},
+		"error on get book file (file not found)": {
+			bookId:                 7,
+			expectedErrorOnGetFile: gorm.ErrRecordNotFound,
+			expectedErrorResponse:  utils.ErrBookFileNotFound,
+		},
+		"error on get book file (file of another book)": {
+			bookId:                8,
+			expectedErrorResponse: utils.ErrBookFileNotFound,
+		},
+		"error on get book file (repository)": {
+			bookId:                 7,
+			expectedErrorOnGetFile: errGeneric,
+			expectedErrorResponse:  errGeneric,
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			bookDbMock := new(bookrepomock.BookRepositoryMock)
+			bookDbMock.On("GetBook", tc.bookId).Return(entities.Book{Id: tc.bookId}, tc.expectedErrorOnGetBook)
+			bookFileDbMock := new(bookfilerepomock.BookFileRepositoryMock)
+			bookFileDbMock.On("GetBookFile", 3).Return(entities.BookFile{Id: 3, BookId: 7, Name: "book.pdf", Format: "pdf", Size: 10},
+				tc.expectedErrorOnGetFile)
+
+			response, err := newTestBookFileService(bookFileDbMock, bookDbMock, nil).GetBookFile(tc.bookId, 3)
+
+			require.ErrorIs(t, err, tc.expectedErrorResponse)
+			if tc.expectedErrorResponse == nil {
+				require.Equal(t, dtos.BookFileResponse{Id: 3, BookId: 7, Name: "book.pdf", Format: "pdf",
+					ContentType: "application/pdf", Size: 10, Authors: []string{}}, response)
+			}
+		})
+	}
+}
+
+func TestDeleteBookFile(t *testing.T) {
+	tests := map[string]struct {
+		expectedErrorOnGetFile error
+		expectedErrorOnDelete  error
+		expectedErrorResponse  error
+	}{
+		"success on delete book file": {},
+		"error on delete book file (file not found)": {
+			expectedErrorOnGetFile: gorm.ErrRecordNotFound,
+			expectedErrorResponse:  utils.ErrBookFileNotFound,
+		},
+		"error on delete book file (repository)": {
+			expectedErrorOnDelete: errGeneric,
+			expectedErrorResponse: errGeneric,
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			store := &storage.LocalBlobStore{Root: t.TempDir()}
+			require.NoError(t, store.Put("files/7/1.pdf", strings.NewReader("%PDF"), 4, "application/pdf"))
+			bookDbMock := new(bookrepomock.BookRepositoryMock)
+			bookDbMock.On("GetBook", 7).Return(entities.Book{Id: 7}, nil)
+			bookFileDbMock := new(bookfilerepomock.BookFileRepositoryMock)
+			bookFileDbMock.On("GetBookFile", 3).Return(entities.BookFile{Id: 3, BookId: 7, Key: "files/7/1.pdf"}, tc.expectedErrorOnGetFile)
+			bookFileDbMock.On("DeleteBookFile", 3).Return(tc.expectedErrorOnDelete)
+
+			err := newTestBookFileService(bookFileDbMock, bookDbMock, store).DeleteBookFile(7, 3)
+
+			require.ErrorIs(t, err, tc.expectedErrorResponse)
+			_, err = store.Get("files/7/1.pdf")
+			if tc.expectedErrorResponse == nil {
+				require.ErrorIs(t, err, storage.ErrBlobNotFound)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestDownloadLink(t *testing.T) {
+	store := &storage.LocalBlobStore{Root: t.TempDir()}
+	require.NoError(t, store.Put("files/7/1.pdf", strings.NewReader("%PDF-1.7 content"), 16, "application/pdf"))
+	bookDbMock := new(bookrepomock.BookRepositoryMock)
+	bookDbMock.On("GetBook", 7).Return(entities.Book{Id: 7}, nil)
+	bookFileDbMock := new(bookfilerepomock.BookFileRepositoryMock)
+	bookFileDbMock.On("GetBookFile", 3).Return(entities.BookFile{Id: 3, BookId: 7, Key: "files/7/1.pdf", Name: "book.pdf",
+		Format: "pdf", Size: 16, CreatedAt: now}, nil)
+	bookFileDbMock.On("GetBookFile", 4).Return(entities.BookFile{}, gorm.ErrRecordNotFound)
+	bookFileServiceTest := newTestBookFileService(bookFileDbMock, bookDbMock, store)
+
+	link, err := bookFileServiceTest.GetDownloadLink(7, 3)
+	require.NoError(t, err)
+	require.Equal(t, now.Add(15*time.Minute), link.ExpiresAt)
+	linkUrl, err := url.Parse(link.Url)
+	require.NoError(t, err)
+	require.Equal(t, "/downloads/3", linkUrl.Path)
+	expires, signature := linkUrl.Query().Get("expires"), linkUrl.Query().Get("signature")
+	require.Equal(t, strconv.FormatInt(now.Add(15*time.Minute).Unix(), 10), expires)
+
+	download, err := bookFileServiceTest.OpenDownload(3, expires, signature)
+	require.NoError(t, err)
+	content, err := io.ReadAll(download.Body)
+	require.NoError(t, err)
+	require.NoError(t, download.Body.Close())
+	require.Equal(t, "%PDF-1.7 content", string(content))
+	require.Equal(t, "book.pdf", download.Name)
+	require.Equal(t, "application/pdf", download.ContentType)
+	require.Equal(t, now, download.ModTime)
+
+	_, err = bookFileServiceTest.OpenDownload(3, expires, strings.ToUpper(signature))
+	require.NoError(t, err)
+
+	tests := map[string]struct {
+		id            int
+		expires       string
+		signature     string
+		expectedError error
+	}{
+		"tampered signature":      {id: 3, expires: expires, signature: signature[:len(signature)-1] + "0", expectedError: utils.ErrInvalidDownloadLink},
+		"missing signature":       {id: 3, expires: expires, expectedError: utils.ErrInvalidDownloadLink},
+		"extended expiry":         {id: 3, expires: expires + "0", signature: signature, expectedError: utils.ErrInvalidDownloadLink},
+		"invalid expiry":          {id: 3, expires: "tomorrow", signature: signature, expectedError: utils.ErrInvalidDownloadLink},
+		"signature of other file": {id: 4, expires: expires, signature: signature, expectedError: utils.ErrInvalidDownloadLink},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			_, err := bookFileServiceTest.OpenDownload(tc.id, tc.expires, tc.signature)
+			require.ErrorIs(t, err, tc.expectedError)
+		})
+	}
+
+	t.Run("expired link", func(t *testing.T) {
+		bookFileServiceTest.now = func() time.Time { return now.Add(16 * time.Minute) }
+		defer func() { bookFileServiceTest.now = func() time.Time { return now } }()
+
+		_, err := bookFileServiceTest.OpenDownload(3, expires, signature)
+		require.ErrorIs(t, err, utils.ErrInvalidDownloadLink)
+	})
+
+	t.Run("deleted file", func(t *testing.T) {
+		link, err := bookFileServiceTest.GetDownloadLink(7, 3)
+		require.NoError(t, err)
+		deletedUrl, _ := url.Parse(strings.Replace(link.Url, "/downloads/3", "/downloads/4", 1))
+		expires := deletedUrl.Query().Get("expires")
+
+		_, err = bookFileServiceTest.OpenDownload(4, expires, bookFileServiceTest.sign(4, now.Add(15*time.Minute).Unix()))
+		require.ErrorIs(t, err, utils.ErrBookFileNotFound)
+	})
+
+	t.Run("link of file of another book", func(t *testing.T) {
+		bookDbMock.On("GetBook", 8).Return(entities.Book{Id: 8}, nil)
+
+		_, err := bookFileServiceTest.GetDownloadLink(8, 3)
+		require.ErrorIs(t, err, utils.ErrBookFileNotFound)
+	})
+}
+
+func TestFileName(t *testing.T) {
+	tests := map[string]struct {
+		name     string
+		format   string
+		expected string
+	}{
+		"name with extension":       {name: "Pride and Prejudice.epub", format: "epub", expected: "Pride and Prejudice.epub"},
+		"name without extension":    {name: "scan", format: "pdf", expected: "scan.pdf"},
+		"name with other extension": {name: "book.txt", format: "pdf", expected: "book.txt.pdf"},
+		"path":                      {name: `C:\Users\me\Dom Casmurro.pdf`, format: "pdf", expected: "Dom Casmurro.pdf"},
+		"empty name":                {name: " ", format: "epub", expected: "book-7.epub"},
+		"dot":                       {name: ".", format: "pdf", expected: "book-7.pdf"},
+		"long name":                 {name: strings.Repeat("á", 200) + ".pdf", format: "pdf", expected: strings.Repeat("á", 146) + ".pdf"},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			require.Equal(t, tc.expected, fileName(tc.name, 7, tc.format))
+		})
+	}
+}
@@ -0,0 +1,265 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/zlib"
+	"encoding/xml"
+	"fmt"
+	"github/brunojoenk/golang-test/models/dtos"
+	"github/brunojoenk/golang-test/utils"
+	"io"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// maxEpubEntryBytes caps what is read of the mimetype, container and package documents of an EPUB, and
+// maxPdfStreamBytes what is inflated of each object stream of a PDF, so a small file can not expand to
+// more than the memory it is worth
+const (
+	maxEpubEntryBytes = 1 << 20
+	maxPdfStreamBytes = 16 << 20
+)
+
+var (
+	pdfObjectRegexp = regexp.MustCompile(`(?s)(\d+)\s+\d+\s+obj\b(.*?)\bendobj`)
+	pdfStreamRegexp = regexp.MustCompile(`stream\r?\n`)
+	pdfPageRegexp   = regexp.MustCompile(`/Type\s*/Page\b`)
+	pdfObjStmRegexp = regexp.MustCompile(`/Type\s*/ObjStm\b`)
+	pdfFirstRegexp  = regexp.MustCompile(`/First\s+(\d+)`)
+)
+
+// fileMetadata is what is read from a book file. Fields not found on the file are left empty
+type fileMetadata struct {
+	Pages    int
+	Title    string
+	Authors  []string
+	Language string
+	Isbn13   string
+}
+
+// readMetadata tells the format of a book file from its content and reads its metadata: the page count
+// of a PDF, and the title, authors, language and ISBN of an EPUB
+func readMetadata(data []byte) (string, fileMetadata, error) {
+	switch {
+	case bytes.HasPrefix(data, []byte("%PDF-")):
+		metadata, err := readPdfMetadata(data)
+		return dtos.BookFileFormatPdf, metadata, err
+	case bytes.HasPrefix(data, []byte("PK\x03\x04")):
+		metadata, err := readEpubMetadata(data)
+		return dtos.BookFileFormatEpub, metadata, err
+	}
+	return "", fileMetadata{}, utils.ErrInvalidBookFileType
+}
+
+// readPdfMetadata counts the page objects of a PDF, including those packed in compressed object
+// streams. An object redefined by an incremental update is counted once. Encrypted files, whose object
+// streams can not be read, may count fewer pages
+func readPdfMetadata(data []byte) (fileMetadata, error) {
+	tail := data
+	if len(tail) > 1024 {
+		tail = tail[len(tail)-1024:]
+	}
+	if !bytes.Contains(tail, []byte("%%EOF")) {
+		return fileMetadata{}, utils.ErrInvalidBookFile
+	}
+
+	pages := make(map[int]bool)
+	for _, match := range pdfObjectRegexp.FindAllSubmatch(data, -1) {
+		number, _ := strconv.Atoi(string(match[1]))
+		body := match[2]
+
+		dictionary, stream := body, []byte(nil)
+		if loc := pdfStreamRegexp.FindIndex(body); loc != nil {
+			dictionary, stream = body[:loc[0]], body[loc[1]:]
+		}
+		pages[number] = pdfPageRegexp.Match(dictionary)
+
+		if pdfObjStmRegexp.Match(dictionary) {
+			readPdfObjectStream(dictionary, stream, pages)
+		}
+	}
+
+	count := 0
+	for _, page := range pages {
+		if page {
+			count++
+		}
+	}
+	return fileMetadata{Pages: count}, nil
+}
+
+// readPdfObjectStream marks the pages among the objects packed in an object stream. The stream starts
+// with pairs of object number and offset, the offsets counting from /First
+func readPdfObjectStream(dictionary, stream []byte, pages map[int]bool) {
+	if !bytes.Contains(dictionary, []byte("/FlateDecode")) {
+		return
+	}
+	first := pdfFirstRegexp.FindSubmatch(dictionary)
+	if first == nil {
+		return
+	}
+	firstOffset, err := strconv.Atoi(string(first[1]))
+	if err != nil || firstOffset < 0 || firstOffset > maxPdfStreamBytes {
+		return
+	}
+
+	reader, err := zlib.NewReader(bytes.NewReader(stream))
+	if err != nil {
+		return
+	}
+	defer reader.Close()
+	// The stream ends before endstream, and anything read after its end is an error to be ignored
+	content, _ := io.ReadAll(io.LimitReader(reader, maxPdfStreamBytes))
+	if firstOffset > len(content) {
+		return
+	}
+	// Offsets come from the file, so they are compared with what is left after /First rather than
+	// added to it, which could overflow
+	remaining := len(content) - firstOffset
+
+	header := strings.Fields(string(content[:firstOffset]))
+	for i := 0; i+1 < len(header); i += 2 {
+		number, err := strconv.Atoi(header[i])
+		if err != nil {
+			return
+		}
+		start, err := strconv.Atoi(header[i+1])
+		if err != nil || start < 0 || start > remaining {
+			return
+		}
+		end := len(content)
+		if i+3 < len(header) {
+			next, err := strconv.Atoi(header[i+3])
+			if err == nil && next < 0 {
+				return
+			}
+			if err == nil && next >= start && next <= remaining {
+				end = firstOffset + next
+			}
+		}
+		pages[number] = pdfPageRegexp.Match(content[firstOffset+start : end])
+	}
+}
+
+type epubContainer struct {
+	Rootfiles []struct {
+		FullPath string `xml:"full-path,attr"`
+	} `xml:"rootfiles>rootfile"`
+}
+
+// epubPackage is the metadata of the package document of an EPUB. Dublin Core elements are matched by
+// their local name, e.g. dc:title as title
+type epubPackage struct {
+	Metadata struct {
+		Titles   []string `xml:"title"`
+		Creators []struct {
+			Id   string `xml:"id,attr"`
+			Role string `xml:"role,attr"`
+			Name string `xml:",chardata"`
+		} `xml:"creator"`
+		Languages   []string `xml:"language"`
+		Identifiers []string `xml:"identifier"`
+		Metas       []struct {
+			Refines  string `xml:"refines,attr"`
+			Property string `xml:"property,attr"`
+			Value    string `xml:",chardata"`
+		} `xml:"meta"`
+	} `xml:"metadata"`
+}
+
+// readEpubMetadata reads the title, authors, language and ISBN of an EPUB from its package document. A
+// creator is an author when its role, EPUB 2 opf:role or EPUB 3 refining meta, is aut or not given
+func readEpubMetadata(data []byte) (fileMetadata, error) {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return fileMetadata{}, utils.ErrInvalidBookFile
+	}
+
+	mimetype, err := readZipEntry(archive, "mimetype")
+	if err != nil || strings.TrimSpace(string(mimetype)) != "application/epub+zip" {
+		return fileMetadata{}, utils.ErrInvalidBookFileType
+	}
+
+	var container epubContainer
+	if err := decodeZipXml(archive, "META-INF/container.xml", &container); err != nil || len(container.Rootfiles) == 0 {
+		return fileMetadata{}, utils.ErrInvalidBookFile
+	}
+	var pkg epubPackage
+	if err := decodeZipXml(archive, container.Rootfiles[0].FullPath, &pkg); err != nil {
+		return fileMetadata{}, utils.ErrInvalidBookFile
+	}
+
+	metadata := fileMetadata{}
+	if len(pkg.Metadata.Titles) > 0 {
+		metadata.Title = collapseSpaces(pkg.Metadata.Titles[0])
+	}
+
+	roles := make(map[string]string)
+	for _, meta := range pkg.Metadata.Metas {
+		if meta.Property == "role" && strings.HasPrefix(meta.Refines, "#") {
+			roles[meta.Refines[1:]] = strings.TrimSpace(meta.Value)
+		}
+	}
+	for _, creator := range pkg.Metadata.Creators {
+		role := creator.Role
+		if refined, ok := roles[creator.Id]; ok && creator.Id != "" {
+			role = refined
+		}
+		name := collapseSpaces(creator.Name)
+		if name != "" && (role == "" || role == "aut") {
+			metadata.Authors = append(metadata.Authors, name)
+		}
+	}
+
+	if len(pkg.Metadata.Languages) > 0 {
+		// A language tag like en-US is kept as its language, en
+		language := strings.SplitN(strings.ReplaceAll(pkg.Metadata.Languages[0], "_", "-"), "-", 2)[0]
+		metadata.Language, _ = utils.NormalizeLanguage(language)
+	}
+
+	for _, identifier := range pkg.Metadata.Identifiers {
+		identifier = strings.TrimSpace(identifier)
+		if len(identifier) > 9 && strings.EqualFold(identifier[:9], "urn:isbn:") {
+			identifier = identifier[9:]
+		}
+		if isbn13, err := utils.NormalizeIsbn(identifier); err == nil {
+			metadata.Isbn13 = isbn13
+			break
+		}
+	}
+
+	return metadata, nil
+}
+
+func readZipEntry(archive *zip.Reader, name string) ([]byte, error) {
+	file, err := archive.Open(path.Clean(name))
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, maxEpubEntryBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxEpubEntryBytes {
+		return nil, fmt.Errorf("zip entry %s is too large", name)
+	}
+	return data, nil
+}
+
+func decodeZipXml(archive *zip.Reader, name string, v interface{}) error {
+	data, err := readZipEntry(archive, name)
+	if err != nil {
+		return err
+	}
+	// Only UTF-8 documents are read, as EPUB requires
+	return xml.Unmarshal(data, v)
+}
+
+func collapseSpaces(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
@@ -0,0 +1,218 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"github/brunojoenk/golang-test/models/dtos"
+	"github/brunojoenk/golang-test/utils"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// testPdf is a PDF with two pages written as plain objects, one of them redefined by an incremental
+// update, and two more packed in a compressed object stream
+func testPdf(t *testing.T) []byte {
+	var objects bytes.Buffer
+	header := "3 0 4 40 "
+	objects.WriteString(header)
+	objects.WriteString("<< /Type /Page /Parent 2 0 R >>\n")
+	for objects.Len() < len(header)+40 {
+		objects.WriteString(" ")
+	}
+	objects.WriteString("<< /Type/Page/Parent 2 0 R >>")
+
+	compressed := deflate(t, objects.Bytes())
+
+	var pdf bytes.Buffer
+	pdf.WriteString("%PDF-1.7\n")
+	pdf.WriteString("1 0 obj\n<< /Type /Catalog /Pages 2 0 R >>\nendobj\n")
+	pdf.WriteString("2 0 obj\n<< /Type /Pages /Kids [3 0 R 4 0 R 5 0 R 6 0 R] /Count 4 >>\nendobj\n")
+	pdf.WriteString("5 0 obj\n<< /Type /Page /Parent 2 0 R /Contents 7 0 R >>\nendobj\n")
+	pdf.WriteString("6 0 obj\n<< /Type /Page /Parent 2 0 R >>\nendobj\n")
+	pdf.WriteString("7 0 obj\n<< /Length 8 >>\nstream\nBT ET q\nendstream\nendobj\n")
+	pdf.WriteString(fmt.Sprintf("8 0 obj\n<< /Type /ObjStm /N 2 /First %d /Filter /FlateDecode /Length %d >>\nstream\n", len(header), len(compressed)))
+	pdf.Write(compressed)
+	pdf.WriteString("\nendstream\nendobj\n")
+	pdf.WriteString("trailer\n<< /Root 1 0 R >>\n%%EOF\n")
+	pdf.WriteString("6 0 obj\n<< /Type /Page /Parent 2 0 R /Rotate 90 >>\nendobj\n")
+	pdf.WriteString("trailer\n<< /Root 1 0 R >>\n%%EOF\n")
+	return pdf.Bytes()
+}
+
+// testMalformedPdf is a PDF with one page and an object stream whose decoded content is given as is
+func testMalformedPdf(t *testing.T, first int, content string) []byte {
+	compressed := deflate(t, []byte(content))
+
+	var pdf bytes.Buffer
+	pdf.WriteString("%PDF-1.7\n")
+	pdf.WriteString("1 0 obj\n<< /Type /Page >>\nendobj\n")
+	pdf.WriteString(fmt.Sprintf("2 0 obj\n<< /Type /ObjStm /N 2 /First %d /Filter /FlateDecode /Length %d >>\nstream\n", first, len(compressed)))
+	pdf.Write(compressed)
+	pdf.WriteString("\nendstream\nendobj\n")
+	pdf.WriteString("trailer\n<< >>\n%%EOF\n")
+	return pdf.Bytes()
+}
+
+func deflate(t *testing.T, data []byte) []byte {
+	var compressed bytes.Buffer
+	writer := zlib.NewWriter(&compressed)
+	_, err := writer.Write(data)
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+	return compressed.Bytes()
+}
+
+const testContainer = `<?xml version="1.0" encoding="UTF-8"?>
+<container version="1.0" xmlns="urn:oasis:names:tc:opendocument:xmlns:container">
+  <rootfiles>
+    <rootfile full-path="OEBPS/content.opf" media-type="application/oebps-package+xml"/>
+  </rootfiles>
+</container>`
+
+const testPackage = `<?xml version="1.0" encoding="UTF-8"?>
+<package xmlns="http://www.idpf.org/2007/opf" version="3.0" unique-identifier="id">
+  <metadata xmlns:dc="http://purl.org/dc/elements/1.1/" xmlns:opf="http://www.idpf.org/2007/opf">
+    <dc:identifier id="id">urn:uuid:2b4e8a2c-6a51-4c46-b4f1-6d0a1c2b3e4f</dc:identifier>
+    <dc:identifier>urn:isbn:0-14-143951-3</dc:identifier>
+    <dc:title>Pride and
+      Prejudice</dc:title>
+    <dc:creator id="author">Jane Austen</dc:creator>
+    <meta refines="#author" property="role" scheme="marc:relators">aut</meta>
+    <dc:creator id="illustrator">Hugh Thomson</dc:creator>
+    <meta refines="#illustrator" property="role" scheme="marc:relators">ill</meta>
+    <dc:creator opf:role="edt">Vivien Jones</dc:creator>
+    <dc:language>en-GB</dc:language>
+  </metadata>
+</package>`
+
+// testEpub is an EPUB with the given files besides its mimetype
+func testEpub(t *testing.T, mimetype string, files map[string]string) []byte {
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+
+	writer, err := archive.CreateHeader(&zip.FileHeader{Name: "mimetype", Method: zip.Store})
+	require.NoError(t, err)
+	_, err = writer.Write([]byte(mimetype))
+	require.NoError(t, err)
+
+	for name, content := range files {
+		writer, err := archive.Create(name)
+		require.NoError(t, err)
+		_, err = writer.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, archive.Close())
+	return buf.Bytes()
+}
+
+func TestReadMetadata(t *testing.T) {
+	validEpub := testEpub(t, "application/epub+zip", map[string]string{
+		"META-INF/container.xml": testContainer,
+		"OEBPS/content.opf":      testPackage,
+	})
+
+	tests := map[string]struct {
+		data             []byte
+		expectedFormat   string
+		expectedMetadata fileMetadata
+		expectedError    error
+	}{
+		"pdf": {
+			data:             testPdf(t),
+			expectedFormat:   dtos.BookFileFormatPdf,
+			expectedMetadata: fileMetadata{Pages: 4},
+		},
+		"pdf without pages": {
+			data:             []byte("%PDF-1.4\n1 0 obj\n<< /Type /Catalog >>\nendobj\n%%EOF"),
+			expectedFormat:   dtos.BookFileFormatPdf,
+			expectedMetadata: fileMetadata{},
+		},
+		"pdf with negative offset in object stream": {
+			data:             testMalformedPdf(t, 4, "1 -9<< /Type /Page >>"),
+			expectedFormat:   dtos.BookFileFormatPdf,
+			expectedMetadata: fileMetadata{Pages: 1},
+		},
+		"pdf with negative next offset in object stream": {
+			data:             testMalformedPdf(t, 8, "3 0 4 -2<< /Type /Page >>"),
+			expectedFormat:   dtos.BookFileFormatPdf,
+			expectedMetadata: fileMetadata{Pages: 1},
+		},
+		"pdf with overflowing offset in object stream": {
+			data:             testMalformedPdf(t, 22, "1 9223372036854775800 << /Type /Page >>"),
+			expectedFormat:   dtos.BookFileFormatPdf,
+			expectedMetadata: fileMetadata{Pages: 1},
+		},
+		"pdf with overflowing next offset in object stream": {
+			data:             testMalformedPdf(t, 26, "3 0 4 9223372036854775800 << /Type /Page >>"),
+			expectedFormat:   dtos.BookFileFormatPdf,
+			expectedMetadata: fileMetadata{Pages: 2},
+		},
+		"pdf with oversized first offset in object stream": {
+			data:             testMalformedPdf(t, 9223372036854775800, "1 0 << /Type /Page >>"),
+			expectedFormat:   dtos.BookFileFormatPdf,
+			expectedMetadata: fileMetadata{Pages: 1},
+		},
+		"truncated pdf": {
+			data:           testPdf(t)[:200],
+			expectedFormat: dtos.BookFileFormatPdf,
+			expectedError:  utils.ErrInvalidBookFile,
+		},
+		"epub": {
+			data:           validEpub,
+			expectedFormat: dtos.BookFileFormatEpub,
+			expectedMetadata: fileMetadata{
+				Title:    "Pride and Prejudice",
+				Authors:  []string{"Jane Austen"},
+				Language: "en",
+				Isbn13:   "9780141439518",
+			},
+		},
+		"epub 2 roles": {
+			data: testEpub(t, "application/epub+zip", map[string]string{
+				"META-INF/container.xml": testContainer,
+				"OEBPS/content.opf": `<package><metadata><dc:title>Emma</dc:title>
+					<dc:creator opf:role="aut">Jane Austen</dc:creator><dc:creator>Anonymous</dc:creator>
+					<dc:creator opf:role="trl">Translator</dc:creator><dc:language>xx-invalid</dc:language>
+					</metadata></package>`,
+			}),
+			expectedFormat:   dtos.BookFileFormatEpub,
+			expectedMetadata: fileMetadata{Title: "Emma", Authors: []string{"Jane Austen", "Anonymous"}},
+		},
+		"zip that is not an epub": {
+			data:           testEpub(t, "application/vnd.oasis.opendocument.text", map[string]string{"content.xml": "<office/>"}),
+			expectedFormat: dtos.BookFileFormatEpub,
+			expectedError:  utils.ErrInvalidBookFileType,
+		},
+		"epub without package document": {
+			data:           testEpub(t, "application/epub+zip", map[string]string{"META-INF/container.xml": testContainer}),
+			expectedFormat: dtos.BookFileFormatEpub,
+			expectedError:  utils.ErrInvalidBookFile,
+		},
+		"epub with invalid container": {
+			data:           testEpub(t, "application/epub+zip", map[string]string{"META-INF/container.xml": "<container"}),
+			expectedFormat: dtos.BookFileFormatEpub,
+			expectedError:  utils.ErrInvalidBookFile,
+		},
+		"truncated epub": {
+			data:           validEpub[:len(validEpub)-10],
+			expectedFormat: dtos.BookFileFormatEpub,
+			expectedError:  utils.ErrInvalidBookFile,
+		},
+		"other file": {
+			data:          []byte("plain text"),
+			expectedError: utils.ErrInvalidBookFileType,
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			format, metadata, err := readMetadata(tc.data)
+
+			require.Equal(t, tc.expectedFormat, format)
+			require.ErrorIs(t, err, tc.expectedError)
+			require.Equal(t, tc.expectedMetadata, metadata)
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package services
+
+import (
+	"github/brunojoenk/golang-test/models/dtos"
+	"io"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type BookFileServiceMock struct {
+	mock.Mock
+}
+
+func (m *BookFileServiceMock) CreateBookFile(bookId int, name string, body io.Reader, prefill bool) (dtos.BookFileUploadResponse, error) {
+	args := m.Called(bookId, name, body, prefill)
+	return args.Get(0).(dtos.BookFileUploadResponse), args.Error(1)
+}
+
+func (m *BookFileServiceMock) GetBookFiles(bookId int) ([]dtos.BookFileResponse, error) {
+	args := m.Called(bookId)
+	return args.Get(0).([]dtos.BookFileResponse), args.Error(1)
+}
+
+func (m *BookFileServiceMock) GetBookFile(bookId, id int) (dtos.BookFileResponse, error) {
+	args := m.Called(bookId, id)
+	return args.Get(0).(dtos.BookFileResponse), args.Error(1)
+}
+
+func (m *BookFileServiceMock) DeleteBookFile(bookId, id int) error {
+	args := m.Called(bookId, id)
+	return args.Error(0)
+}
+
+func (m *BookFileServiceMock) GetDownloadLink(bookId, id int) (dtos.DownloadLinkResponse, error) {
+	args := m.Called(bookId, id)
+	return args.Get(0).(dtos.DownloadLinkResponse), args.Error(1)
+}
+
+func (m *BookFileServiceMock) OpenDownload(id int, expires, signature string) (dtos.BookFileDownload, error) {
+	args := m.Called(id, expires, signature)
+	return args.Get(0).(dtos.BookFileDownload), args.Error(1)
+}
@@ -0,0 +1,261 @@
+package services
+
+import (
+	"errors"
+	"github/brunojoenk/golang-test/config"
+	"github/brunojoenk/golang-test/models/dtos"
+	"github/brunojoenk/golang-test/models/entities"
+	bookrepo "github/brunojoenk/golang-test/repository/book"
+	copyrepo "github/brunojoenk/golang-test/repository/copy"
+	holdservice "github/brunojoenk/golang-test/services/hold"
+	"github/brunojoenk/golang-test/utils"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+var validStatuses = map[string]bool{
+	dtos.CopyStatusAvailable: true,
+	dtos.CopyStatusOnLoan:    true,
+	dtos.CopyStatusOnHold:    true,
+	dtos.CopyStatusLost:      true,
+	dtos.CopyStatusWithdrawn: true,
+}
+
+var validConditions = map[string]bool{
+	dtos.CopyConditionNew:  true,
+	dtos.CopyConditionGood: true,
+	dtos.CopyConditionFair: true,
+	dtos.CopyConditionPoor: true,
+}
+
+type ICopyService interface {
+	CreateCopy(bookId int, copyRequest dtos.CopyRequest) (dtos.CopyResponse, error)
+	GetBookCopies(bookId int, filter dtos.GetCopiesFilter) (dtos.CopyResponseMetadata, error)
+	GetCopy(bookId, id int) (dtos.CopyResponse, error)
+	UpdateCopy(bookId, id int, copyRequest dtos.CopyRequest) (dtos.CopyResponse, error)
+	DeleteCopy(bookId, id int) error
+}
+
+type copyService struct {
+	copyDb      copyrepo.ICopyRepository
+	bookDb      bookrepo.IBookRepository
+	holdService holdservice.IHoldService
+}
+
+// NewCopyService Service Constructor
+func NewCopyService(db *gorm.DB, cfg *config.Config) ICopyService {
+	return &copyService{
+		copyDb:      copyrepo.NewCopyRepository(db),
+		bookDb:      bookrepo.NewBookRepository(db),
+		holdService: holdservice.NewHoldService(db, cfg),
+	}
+}
+
+// CreateCopy adds a copy to the book. A new copy is available and in good condition unless told otherwise,
+// and goes on hold right away when patrons are waiting for the book
+func (c *copyService) CreateCopy(bookId int, copyRequest dtos.CopyRequest) (dtos.CopyResponse, error) {
+	if err := c.checkBook(bookId); err != nil {
+		return dtos.CopyResponse{}, err
+	}
+
+	bookCopy := entities.Copy{BookId: bookId, Status: dtos.CopyStatusAvailable, Condition: dtos.CopyConditionGood}
+	if err := c.setFields(&bookCopy, copyRequest); err != nil {
+		return dtos.CopyResponse{}, err
+	}
+
+	bookCopy, err := c.copyDb.CreateCopy(bookCopy)
+	if err != nil {
+		log.Error("Error on create copy from repo: ", err.Error())
+		return dtos.CopyResponse{}, err
+	}
+
+	c.processHolds(bookId)
+	return toCopyResponse(bookCopy), nil
+}
+
+func (c *copyService) GetBookCopies(bookId int, filter dtos.GetCopiesFilter) (dtos.CopyResponseMetadata, error) {
+	if filter.Status != "" && !validStatuses[filter.Status] {
+		return dtos.CopyResponseMetadata{}, utils.ErrInvalidCopyStatus
+	}
+
+	if err := c.checkBook(bookId); err != nil {
+		return dtos.CopyResponseMetadata{}, err
+	}
+
+	filter.Pagination.ValidValuesAndSetDefault()
+	copies, err := c.copyDb.GetBookCopies(bookId, filter)
+	if err != nil {
+		log.Error("Error on get copies of book from repo: ", err.Error())
+		return dtos.CopyResponseMetadata{}, err
+	}
+
+	copiesResponse := make([]dtos.CopyResponse, len(copies))
+	for i, bookCopy := range copies {
+		copiesResponse[i] = toCopyResponse(bookCopy)
+	}
+
+	return dtos.CopyResponseMetadata{
+		Copies:     copiesResponse,
+		Pagination: filter.Pagination,
+	}, nil
+}
+
+func (c *copyService) GetCopy(bookId, id int) (dtos.CopyResponse, error) {
+	bookCopy, err := c.getCopy(bookId, id)
+	if err != nil {
+		return dtos.CopyResponse{}, err
+	}
+
+	return toCopyResponse(bookCopy), nil
+}
+
+// UpdateCopy replaces the fields of the copy. An empty status or condition keeps the current one. A copy
+// on hold that is lost or withdrawn sends its hold back to the queue
+func (c *copyService) UpdateCopy(bookId, id int, copyRequest dtos.CopyRequest) (dtos.CopyResponse, error) {
+	bookCopy, err := c.getCopy(bookId, id)
+	if err != nil {
+		return dtos.CopyResponse{}, err
+	}
+
+	if err := c.setFields(&bookCopy, copyRequest); err != nil {
+		return dtos.CopyResponse{}, err
+	}
+
+	bookCopy, err = c.copyDb.UpdateCopy(bookCopy)
+	if err != nil {
+		log.Error("Error on update copy from repo: ", err.Error())
+		return dtos.CopyResponse{}, err
+	}
+
+	c.processHolds(bookId)
+	return toCopyResponse(bookCopy), nil
+}
+
+// DeleteCopy deletes a copy that is not on loan, the loans history of the copy goes with it
+func (c *copyService) DeleteCopy(bookId, id int) error {
+	bookCopy, err := c.getCopy(bookId, id)
+	if err != nil {
+		return err
+	}
+	if bookCopy.Status == dtos.CopyStatusOnLoan {
+		return utils.ErrCopyOnLoan
+	}
+
+	if err := c.copyDb.DeleteCopy(id); err != nil {
+		log.Error("Error on delete copy from repo: ", err.Error())
+		return err
+	}
+
+	c.processHolds(bookId)
+	return nil
+}
+
+// setFields validates the request and copies it to the copy. The barcode must be unique
+func (c *copyService) setFields(bookCopy *entities.Copy, copyRequest dtos.CopyRequest) error {
+	barcode := strings.TrimSpace(copyRequest.Barcode)
+	if barcode == "" || len(barcode) > 50 {
+		return utils.ErrInvalidCopyBarcode
+	}
+
+	if copyRequest.Status != "" && !validStatuses[copyRequest.Status] {
+		return utils.ErrInvalidCopyStatus
+	}
+
+	// Only checking a copy out and in moves it to and from on_loan, so it always matches an open loan
+	if copyRequest.Status != "" && (copyRequest.Status == dtos.CopyStatusOnLoan) != (bookCopy.Status == dtos.CopyStatusOnLoan) {
+		return utils.ErrCopyStatusOnLoan
+	}
+
+	// Only the hold queue puts a copy on hold, for the patron first in line
+	if copyRequest.Status == dtos.CopyStatusOnHold && bookCopy.Status != dtos.CopyStatusOnHold {
+		return utils.ErrCopyStatusOnHold
+	}
+
+	if copyRequest.Condition != "" && !validConditions[copyRequest.Condition] {
+		return utils.ErrInvalidCopyCondition
+	}
+
+	var acquiredAt *time.Time
+	if strings.TrimSpace(copyRequest.AcquiredAt) != "" {
+		date, err := utils.ParseDate(copyRequest.AcquiredAt)
+		if err != nil {
+			return err
+		}
+		acquiredAt = &date
+	}
+
+	existing, err := c.copyDb.GetCopyByBarcode(barcode)
+	if err == nil && existing.Id != bookCopy.Id {
+		return utils.ErrCopyBarcodeExists
+	}
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		log.Error("Error on get copy by barcode from repo: ", err.Error())
+		return err
+	}
+
+	bookCopy.Barcode = barcode
+	bookCopy.Branch = strings.TrimSpace(copyRequest.Branch)
+	bookCopy.Location = strings.TrimSpace(copyRequest.Location)
+	bookCopy.AcquiredAt = acquiredAt
+	if copyRequest.Status != "" {
+		bookCopy.Status = copyRequest.Status
+	}
+	if copyRequest.Condition != "" {
+		bookCopy.Condition = copyRequest.Condition
+	}
+	return nil
+}
+
+// processHolds gives the hold queue of the book a chance to take a copy that changed. The copy change
+// stands even when the queue can not be processed now
+func (c *copyService) processHolds(bookId int) {
+	if err := c.holdService.ProcessQueue(bookId); err != nil {
+		log.Error("Error on process holds after copy change: ", err.Error())
+	}
+}
+
+func (c *copyService) checkBook(bookId int) error {
+	if _, err := c.bookDb.GetBook(bookId); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return utils.ErrBookIdNotFound
+		}
+		log.Error("Error on get book from repo: ", err.Error())
+		return err
+	}
+	return nil
+}
+
+// getCopy returns the copy when it belongs to the book, so a copy is never reached through another book
+func (c *copyService) getCopy(bookId, id int) (entities.Copy, error) {
+	bookCopy, err := c.copyDb.GetCopy(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return entities.Copy{}, utils.ErrCopyIdNotFound
+		}
+		log.Error("Error on get copy from repo: ", err.Error())
+		return entities.Copy{}, err
+	}
+	if bookCopy.BookId != bookId {
+		return entities.Copy{}, utils.ErrCopyIdNotFound
+	}
+	return bookCopy, nil
+}
+
+func toCopyResponse(bookCopy entities.Copy) dtos.CopyResponse {
+	copyResponse := dtos.CopyResponse{
+		Id:        bookCopy.Id,
+		BookId:    bookCopy.BookId,
+		Barcode:   bookCopy.Barcode,
+		Branch:    bookCopy.Branch,
+		Location:  bookCopy.Location,
+		Condition: bookCopy.Condition,
+		Status:    bookCopy.Status,
+	}
+	if bookCopy.AcquiredAt != nil {
+		copyResponse.AcquiredAt = bookCopy.AcquiredAt.Format(utils.DATE_LAYOUT)
+	}
+	return copyResponse
+}
@@ -0,0 +1,267 @@
+package services
+
+import (
+	"errors"
+	"github/brunojoenk/golang-test/models/dtos"
+	"github/brunojoenk/golang-test/models/entities"
+	bookrepomock "github/brunojoenk/golang-test/repository/book/mock"
+	copyrepomock "github/brunojoenk/golang-test/repository/copy/mock"
+	holdservicemock "github/brunojoenk/golang-test/services/hold/mock"
+	"github/brunojoenk/golang-test/utils"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+var errGeneric = errors.New("generic error")
+
+func TestCreateCopy(t *testing.T) {
+	acquiredAt := time.Date(2021, 3, 14, 0, 0, 0, 0, time.UTC)
+	tests := map[string]struct {
+		request                     dtos.CopyRequest
+		expectedErrorOnGetBook      error
+		existing                    entities.Copy
+		expectedErrorOnGetByBarcode error
+		expectedCopy                entities.Copy
+		expectedErrorOnCreate       error
+		expectedErrorResponse       error
+	}{
+		"success on create copy with defaults": {
+			request:                     dtos.CopyRequest{Barcode: " 0001 "},
+			expectedErrorOnGetByBarcode: gorm.ErrRecordNotFound,
+			expectedCopy:                entities.Copy{BookId: 1, Barcode: "0001", Condition: dtos.CopyConditionGood, Status: dtos.CopyStatusAvailable},
+		},
+		"success on create copy": {
+			request:                     dtos.CopyRequest{Barcode: "0001", Branch: "Central", Location: "A-12", Condition: dtos.CopyConditionNew, AcquiredAt: "2021-03-14", Status: dtos.CopyStatusWithdrawn},
+			expectedErrorOnGetByBarcode: gorm.ErrRecordNotFound,
+			expectedCopy:                entities.Copy{BookId: 1, Barcode: "0001", Branch: "Central", Location: "A-12", Condition: dtos.CopyConditionNew, AcquiredAt: &acquiredAt, Status: dtos.CopyStatusWithdrawn},
+		},
+		"error occurred on create copy (book not found)": {
+			request:                dtos.CopyRequest{Barcode: "0001"},
+			expectedErrorOnGetBook: gorm.ErrRecordNotFound,
+			expectedErrorResponse:  utils.ErrBookIdNotFound,
+		},
+		"error occurred on create copy (empty barcode)": {
+			request:               dtos.CopyRequest{Barcode: "  "},
+			expectedErrorResponse: utils.ErrInvalidCopyBarcode,
+		},
+		"error occurred on create copy (invalid status)": {
+			request:               dtos.CopyRequest{Barcode: "0001", Status: "borrowed"},
+			expectedErrorResponse: utils.ErrInvalidCopyStatus,
+		},
+		"error occurred on create copy (on loan)": {
+			request:               dtos.CopyRequest{Barcode: "0001", Status: dtos.CopyStatusOnLoan},
+			expectedErrorResponse: utils.ErrCopyStatusOnLoan,
+		},
+		"error occurred on create copy (invalid condition)": {
+			request:               dtos.CopyRequest{Barcode: "0001", Condition: "mint"},
+			expectedErrorResponse: utils.ErrInvalidCopyCondition,
+		},
+		"error occurred on create copy (invalid acquisition date)": {
+			request:               dtos.CopyRequest{Barcode: "0001", AcquiredAt: "14/03/2021"},
+			expectedErrorResponse: utils.ErrInvalidDate,
+		},
+		"error occurred on create copy (barcode exists)": {
+			request:               dtos.CopyRequest{Barcode: "0001"},
+			existing:              entities.Copy{Id: 3, BookId: 2, Barcode: "0001"},
+			expectedErrorResponse: utils.ErrCopyBarcodeExists,
+		},
+		"error occurred on create copy (create)": {
+			request:                     dtos.CopyRequest{Barcode: "0001"},
+			expectedErrorOnGetByBarcode: gorm.ErrRecordNotFound,
+			expectedCopy:                entities.Copy{BookId: 1, Barcode: "0001", Condition: dtos.CopyConditionGood, Status: dtos.CopyStatusAvailable},
+			expectedErrorOnCreate:       errGeneric,
+			expectedErrorResponse:       errGeneric,
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			bookDbMock := new(bookrepomock.BookRepositoryMock)
+			bookDbMock.On("GetBook", 1).Return(entities.Book{Id: 1}, tc.expectedErrorOnGetBook)
+			copyDbMock := new(copyrepomock.CopyRepositoryMock)
+			copyDbMock.On("GetCopyByBarcode", "0001").Return(tc.existing, tc.expectedErrorOnGetByBarcode)
+			created := tc.expectedCopy
+			created.Id = 5
+			copyDbMock.On("CreateCopy", tc.expectedCopy).Return(created, tc.expectedErrorOnCreate)
+
+			holdServiceMock := new(holdservicemock.HoldServiceMock)
+			holdServiceMock.On("ProcessQueue", 1).Return(nil)
+
+			copyServiceTest := copyService{copyDb: copyDbMock, bookDb: bookDbMock, holdService: holdServiceMock}
+			resp, err := copyServiceTest.CreateCopy(1, tc.request)
+			if tc.expectedErrorResponse != nil {
+				require.ErrorIs(t, err, tc.expectedErrorResponse)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, toCopyResponse(created), resp)
+			}
+		})
+	}
+}
+
+func TestGetBookCopies(t *testing.T) {
+	bookDbMock := new(bookrepomock.BookRepositoryMock)
+	bookDbMock.On("GetBook", 1).Return(entities.Book{Id: 1}, nil)
+	bookDbMock.On("GetBook", 2).Return(entities.Book{}, gorm.ErrRecordNotFound)
+	copyDbMock := new(copyrepomock.CopyRepositoryMock)
+	copyDbMock.On("GetBookCopies", 1, dtos.GetCopiesFilter{Status: dtos.CopyStatusAvailable, Pagination: dtos.Pagination{Page: 1, Limit: 10}}).
+		Return([]entities.Copy{{Id: 5, BookId: 1, Barcode: "0001", Status: dtos.CopyStatusAvailable}}, nil)
+
+	copyServiceTest := copyService{copyDb: copyDbMock, bookDb: bookDbMock}
+
+	resp, err := copyServiceTest.GetBookCopies(1, dtos.GetCopiesFilter{Status: dtos.CopyStatusAvailable})
+	require.NoError(t, err)
+	require.Equal(t, []dtos.CopyResponse{{Id: 5, BookId: 1, Barcode: "0001", Status: dtos.CopyStatusAvailable}}, resp.Copies)
+
+	_, err = copyServiceTest.GetBookCopies(2, dtos.GetCopiesFilter{})
+	require.ErrorIs(t, err, utils.ErrBookIdNotFound)
+
+	_, err = copyServiceTest.GetBookCopies(1, dtos.GetCopiesFilter{Status: "borrowed"})
+	require.ErrorIs(t, err, utils.ErrInvalidCopyStatus)
+}
+
+func TestGetCopy(t *testing.T) {
+	tests := map[string]struct {
+		bookId                 int
+		copyExpected           entities.Copy
+		expectedErrorOnGetCopy error
+		expectedErrorResponse  error
+	}{
+		"success on get copy": {
+			bookId:       1,
+			copyExpected: entities.Copy{Id: 5, BookId: 1, Barcode: "0001"},
+		},
+		"error occurred on get copy (copy of another book)": {
+			bookId:                2,
+			copyExpected:          entities.Copy{Id: 5, BookId: 1, Barcode: "0001"},
+			expectedErrorResponse: utils.ErrCopyIdNotFound,
+		},
+		"error occurred on get copy (not found)": {
+			bookId:                 1,
+			expectedErrorOnGetCopy: gorm.ErrRecordNotFound,
+			expectedErrorResponse:  utils.ErrCopyIdNotFound,
+		},
+		"error occurred on get copy": {
+			bookId:                 1,
+			expectedErrorOnGetCopy: errGeneric,
+			expectedErrorResponse:  errGeneric,
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			copyDbMock := new(copyrepomock.CopyRepositoryMock)
+			copyDbMock.On("GetCopy", 5).Return(tc.copyExpected, tc.expectedErrorOnGetCopy)
+
+			copyServiceTest := copyService{copyDb: copyDbMock}
+			resp, err := copyServiceTest.GetCopy(tc.bookId, 5)
+			if tc.expectedErrorResponse != nil {
+				require.ErrorIs(t, err, tc.expectedErrorResponse)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, "0001", resp.Barcode)
+			}
+		})
+	}
+}
+
+func TestUpdateCopy(t *testing.T) {
+	current := entities.Copy{Id: 5, BookId: 1, Barcode: "0001", Condition: dtos.CopyConditionGood, Status: dtos.CopyStatusOnLoan}
+	tests := map[string]struct {
+		request               dtos.CopyRequest
+		existing              entities.Copy
+		expectedCopy          entities.Copy
+		expectedErrorOnUpdate error
+		expectedErrorResponse error
+	}{
+		"success on update copy keeping status and condition": {
+			request:      dtos.CopyRequest{Barcode: "0001", Branch: "East"},
+			existing:     current,
+			expectedCopy: entities.Copy{Id: 5, BookId: 1, Barcode: "0001", Branch: "East", Condition: dtos.CopyConditionGood, Status: dtos.CopyStatusOnLoan},
+		},
+		"success on update copy keeping it on loan": {
+			request:      dtos.CopyRequest{Barcode: "0001", Condition: dtos.CopyConditionPoor, Status: dtos.CopyStatusOnLoan},
+			existing:     current,
+			expectedCopy: entities.Copy{Id: 5, BookId: 1, Barcode: "0001", Condition: dtos.CopyConditionPoor, Status: dtos.CopyStatusOnLoan},
+		},
+		"error occurred on update copy (status of a copy on loan)": {
+			request:               dtos.CopyRequest{Barcode: "0001", Status: dtos.CopyStatusAvailable},
+			existing:              current,
+			expectedErrorResponse: utils.ErrCopyStatusOnLoan,
+		},
+		"error occurred on update copy (barcode of another copy)": {
+			request:               dtos.CopyRequest{Barcode: "0001"},
+			existing:              entities.Copy{Id: 6, BookId: 1, Barcode: "0001"},
+			expectedErrorResponse: utils.ErrCopyBarcodeExists,
+		},
+		"error occurred on update copy (update)": {
+			request:               dtos.CopyRequest{Barcode: "0001"},
+			existing:              current,
+			expectedCopy:          current,
+			expectedErrorOnUpdate: errGeneric,
+			expectedErrorResponse: errGeneric,
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			copyDbMock := new(copyrepomock.CopyRepositoryMock)
+			copyDbMock.On("GetCopy", 5).Return(current, nil)
+			copyDbMock.On("GetCopyByBarcode", "0001").Return(tc.existing, nil)
+			copyDbMock.On("UpdateCopy", tc.expectedCopy).Return(tc.expectedCopy, tc.expectedErrorOnUpdate)
+
+			holdServiceMock := new(holdservicemock.HoldServiceMock)
+			holdServiceMock.On("ProcessQueue", 1).Return(nil)
+
+			copyServiceTest := copyService{copyDb: copyDbMock, holdService: holdServiceMock}
+			resp, err := copyServiceTest.UpdateCopy(1, 5, tc.request)
+			if tc.expectedErrorResponse != nil {
+				require.ErrorIs(t, err, tc.expectedErrorResponse)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, toCopyResponse(tc.expectedCopy), resp)
+			}
+		})
+	}
+}
+
+// A copy on hold can leave circulation, its hold goes back to the queue, but only the queue puts a copy on hold
+func TestUpdateCopyOnHold(t *testing.T) {
+	copyDbMock := new(copyrepomock.CopyRepositoryMock)
+	copyDbMock.On("GetCopy", 5).Return(entities.Copy{Id: 5, BookId: 1, Barcode: "0001", Status: dtos.CopyStatusOnHold}, nil)
+	copyDbMock.On("GetCopy", 6).Return(entities.Copy{Id: 6, BookId: 1, Barcode: "0002", Status: dtos.CopyStatusAvailable}, nil)
+	copyDbMock.On("GetCopyByBarcode", "0001").Return(entities.Copy{Id: 5}, nil)
+	copyDbMock.On("GetCopyByBarcode", "0002").Return(entities.Copy{Id: 6}, nil)
+	withdrawn := entities.Copy{Id: 5, BookId: 1, Barcode: "0001", Status: dtos.CopyStatusWithdrawn}
+	copyDbMock.On("UpdateCopy", withdrawn).Return(withdrawn, nil)
+	holdServiceMock := new(holdservicemock.HoldServiceMock)
+	holdServiceMock.On("ProcessQueue", 1).Return(errGeneric)
+
+	copyServiceTest := copyService{copyDb: copyDbMock, holdService: holdServiceMock}
+
+	resp, err := copyServiceTest.UpdateCopy(1, 5, dtos.CopyRequest{Barcode: "0001", Status: dtos.CopyStatusWithdrawn})
+	require.NoError(t, err)
+	require.Equal(t, dtos.CopyStatusWithdrawn, resp.Status)
+	holdServiceMock.AssertNumberOfCalls(t, "ProcessQueue", 1)
+
+	_, err = copyServiceTest.UpdateCopy(1, 6, dtos.CopyRequest{Barcode: "0002", Status: dtos.CopyStatusOnHold})
+	require.ErrorIs(t, err, utils.ErrCopyStatusOnHold)
+	holdServiceMock.AssertNumberOfCalls(t, "ProcessQueue", 1)
+}
+
+func TestDeleteCopy(t *testing.T) {
+	copyDbMock := new(copyrepomock.CopyRepositoryMock)
+	copyDbMock.On("GetCopy", 5).Return(entities.Copy{Id: 5, BookId: 1, Status: dtos.CopyStatusLost}, nil)
+	copyDbMock.On("GetCopy", 6).Return(entities.Copy{Id: 6, BookId: 1, Status: dtos.CopyStatusOnLoan}, nil)
+	copyDbMock.On("DeleteCopy", 5).Return(nil)
+	holdServiceMock := new(holdservicemock.HoldServiceMock)
+	holdServiceMock.On("ProcessQueue", 1).Return(nil)
+
+	copyServiceTest := copyService{copyDb: copyDbMock, holdService: holdServiceMock}
+
+	require.NoError(t, copyServiceTest.DeleteCopy(1, 5))
+	require.ErrorIs(t, copyServiceTest.DeleteCopy(2, 5), utils.ErrCopyIdNotFound)
+	require.ErrorIs(t, copyServiceTest.DeleteCopy(1, 6), utils.ErrCopyOnLoan)
+	copyDbMock.AssertNumberOfCalls(t, "DeleteCopy", 1)
+	holdServiceMock.AssertNumberOfCalls(t, "ProcessQueue", 1)
+}
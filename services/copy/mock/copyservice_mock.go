@@ -0,0 +1,36 @@
+package services
+
+import (
+	"github/brunojoenk/golang-test/models/dtos"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type CopyServiceMock struct {
+	mock.Mock
+}
+
+func (m *CopyServiceMock) CreateCopy(bookId int, copyRequest dtos.CopyRequest) (dtos.CopyResponse, error) {
+	args := m.Called(bookId, copyRequest)
+	return args.Get(0).(dtos.CopyResponse), args.Error(1)
+}
+
+func (m *CopyServiceMock) GetBookCopies(bookId int, filter dtos.GetCopiesFilter) (dtos.CopyResponseMetadata, error) {
+	args := m.Called(bookId, filter)
+	return args.Get(0).(dtos.CopyResponseMetadata), args.Error(1)
+}
+
+func (m *CopyServiceMock) GetCopy(bookId, id int) (dtos.CopyResponse, error) {
+	args := m.Called(bookId, id)
+	return args.Get(0).(dtos.CopyResponse), args.Error(1)
+}
+
+func (m *CopyServiceMock) UpdateCopy(bookId, id int, copyRequest dtos.CopyRequest) (dtos.CopyResponse, error) {
+	args := m.Called(bookId, id, copyRequest)
+	return args.Get(0).(dtos.CopyResponse), args.Error(1)
+}
+
+func (m *CopyServiceMock) DeleteCopy(bookId, id int) error {
+	args := m.Called(bookId, id)
+	return args.Error(0)
+}
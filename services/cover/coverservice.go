@@ -0,0 +1,299 @@
+package services
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"github/brunojoenk/golang-test/config"
+	"github/brunojoenk/golang-test/models/dtos"
+	"github/brunojoenk/golang-test/models/entities"
+	bookrepo "github/brunojoenk/golang-test/repository/book"
+	coverrepo "github/brunojoenk/golang-test/repository/cover"
+	"github/brunojoenk/golang-test/storage"
+	"github/brunojoenk/golang-test/utils"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+	"gorm.io/gorm"
+)
+
+// MAX_COVER_DIMENSION caps the width and the height of a cover, so a small file can not expand to an
+// image too large to be decoded in memory
+var MAX_COVER_DIMENSION = 6000
+
+// thumbnailSizes is the longest side of each thumbnail, in pixels. Smaller covers are not enlarged
+var thumbnailSizes = map[string]int{
+	dtos.CoverSizeSmall:  160,
+	dtos.CoverSizeMedium: 480,
+}
+
+var coverContentTypes = map[string]bool{
+	"image/jpeg": true,
+	"image/png":  true,
+	"image/webp": true,
+}
+
+type ICoverService interface {
+	SaveCover(bookId int, body io.Reader) (dtos.CoverResponse, error)
+	GetCover(bookId int, size string) (dtos.CoverImage, error)
+	DeleteCover(bookId int) error
+}
+
+type coverService struct {
+	coverDb  coverrepo.ICoverRepository
+	bookDb   bookrepo.IBookRepository
+	store    storage.BlobStore
+	maxBytes int
+}
+
+// NewCoverService Service Constructor
+func NewCoverService(db *gorm.DB, cfg *config.Config, store storage.BlobStore) ICoverService {
+	return &coverService{
+		coverDb:  coverrepo.NewCoverRepository(db),
+		bookDb:   bookrepo.NewBookRepository(db),
+		store:    store,
+		maxBytes: cfg.CoverMaxBytes,
+	}
+}
+
+// SaveCover validates the image, stores it with its thumbnails and makes it the cover of the book. The
+// previous cover, if any, is removed from the store
+func (s *coverService) SaveCover(bookId int, body io.Reader) (dtos.CoverResponse, error) {
+	if err := s.checkBook(bookId); err != nil {
+		return dtos.CoverResponse{}, err
+	}
+
+	data, err := io.ReadAll(io.LimitReader(body, int64(s.maxBytes)+1))
+	if err != nil {
+		log.Error("Error on read cover image: ", err.Error())
+		return dtos.CoverResponse{}, err
+	}
+	if len(data) > s.maxBytes {
+		return dtos.CoverResponse{}, utils.ErrCoverTooLarge
+	}
+
+	contentType := http.DetectContentType(data)
+	if !coverContentTypes[contentType] {
+		return dtos.CoverResponse{}, utils.ErrInvalidCoverType
+	}
+	imageConfig, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil || imageConfig.Width > MAX_COVER_DIMENSION || imageConfig.Height > MAX_COVER_DIMENSION {
+		return dtos.CoverResponse{}, utils.ErrInvalidCoverImage
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return dtos.CoverResponse{}, utils.ErrInvalidCoverImage
+	}
+
+	previous, err := s.coverDb.GetCover(bookId)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		log.Error("Error on get book cover from repo: ", err.Error())
+		return dtos.CoverResponse{}, err
+	}
+
+	now := time.Now()
+	cover := entities.BookCover{
+		BookId:      bookId,
+		Key:         fmt.Sprintf("covers/%d/%d", bookId, now.UnixNano()),
+		ContentType: contentType,
+		Width:       imageConfig.Width,
+		Height:      imageConfig.Height,
+		Size:        int64(len(data)),
+		UpdatedAt:   now,
+	}
+
+	if err := s.putImages(cover, data, img); err != nil {
+		s.deleteImages(cover)
+		return dtos.CoverResponse{}, err
+	}
+
+	if err := s.coverDb.SaveCover(cover); err != nil {
+		log.Error("Error on save book cover from repo: ", err.Error())
+		s.deleteImages(cover)
+		return dtos.CoverResponse{}, err
+	}
+
+	if previous.Key != "" {
+		s.deleteImages(previous)
+	}
+
+	return toCoverResponse(cover), nil
+}
+
+// GetCover opens the original cover of the book or one of its thumbnails
+func (s *coverService) GetCover(bookId int, size string) (dtos.CoverImage, error) {
+	size = strings.ToLower(strings.TrimSpace(size))
+	if size == "" {
+		size = dtos.CoverSizeOriginal
+	}
+	if _, ok := thumbnailSizes[size]; !ok && size != dtos.CoverSizeOriginal {
+		return dtos.CoverImage{}, utils.ErrInvalidCoverSize
+	}
+
+	cover, err := s.getCover(bookId)
+	if err != nil {
+		return dtos.CoverImage{}, err
+	}
+
+	blob, err := s.store.Get(blobKey(cover, size))
+	if err != nil {
+		if errors.Is(err, storage.ErrBlobNotFound) {
+			log.Errorf("Cover %s of book %d is missing on the blob store", blobKey(cover, size), bookId)
+			return dtos.CoverImage{}, utils.ErrCoverNotFound
+		}
+		log.Error("Error on get cover image from blob store: ", err.Error())
+		return dtos.CoverImage{}, err
+	}
+
+	return dtos.CoverImage{
+		Body:        blob.Body,
+		ContentType: imageContentType(cover, size),
+		Size:        blob.Size,
+		ETag:        fmt.Sprintf(`"%s-%s"`, cover.Key[strings.LastIndex(cover.Key, "/")+1:], size),
+	}, nil
+}
+
+// DeleteCover removes the cover of the book
+func (s *coverService) DeleteCover(bookId int) error {
+	cover, err := s.getCover(bookId)
+	if err != nil {
+		return err
+	}
+
+	if err := s.coverDb.DeleteCover(bookId); err != nil {
+		log.Error("Error on delete book cover from repo: ", err.Error())
+		return err
+	}
+
+	s.deleteImages(cover)
+	return nil
+}
+
+func (s *coverService) checkBook(bookId int) error {
+	if _, err := s.bookDb.GetBook(bookId); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return utils.ErrBookIdNotFound
+		}
+		log.Error("Error on get book from repo: ", err.Error())
+		return err
+	}
+	return nil
+}
+
+func (s *coverService) getCover(bookId int) (entities.BookCover, error) {
+	if err := s.checkBook(bookId); err != nil {
+		return entities.BookCover{}, err
+	}
+
+	cover, err := s.coverDb.GetCover(bookId)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return entities.BookCover{}, utils.ErrCoverNotFound
+		}
+		log.Error("Error on get book cover from repo: ", err.Error())
+		return entities.BookCover{}, err
+	}
+	return cover, nil
+}
+
+// putImages stores the original image as uploaded and a thumbnail of each size
+func (s *coverService) putImages(cover entities.BookCover, data []byte, img image.Image) error {
+	if err := s.store.Put(blobKey(cover, dtos.CoverSizeOriginal), bytes.NewReader(data), int64(len(data)), cover.ContentType); err != nil {
+		log.Error("Error on put cover image on blob store: ", err.Error())
+		return err
+	}
+
+	for size, longestSide := range thumbnailSizes {
+		thumbnail, err := encodeThumbnail(img, longestSide, imageContentType(cover, size))
+		if err != nil {
+			log.Error("Error on encode cover thumbnail: ", err.Error())
+			return err
+		}
+		if err := s.store.Put(blobKey(cover, size), bytes.NewReader(thumbnail), int64(len(thumbnail)), imageContentType(cover, size)); err != nil {
+			log.Error("Error on put cover thumbnail on blob store: ", err.Error())
+			return err
+		}
+	}
+	return nil
+}
+
+// deleteImages removes the images of a cover from the store. A failure only leaves files behind, so it
+// is logged and not returned
+func (s *coverService) deleteImages(cover entities.BookCover) {
+	for _, key := range ImageKeys(cover) {
+		if err := s.store.Delete(key); err != nil {
+			log.Error("Error on delete cover image from blob store: ", err.Error())
+		}
+	}
+}
+
+// ImageKeys lists the keys of the original image of a cover and of its thumbnails on the store
+func ImageKeys(cover entities.BookCover) []string {
+	keys := []string{blobKey(cover, dtos.CoverSizeOriginal)}
+	for size := range thumbnailSizes {
+		keys = append(keys, blobKey(cover, size))
+	}
+	return keys
+}
+
+func blobKey(cover entities.BookCover, size string) string {
+	return cover.Key + "/" + size
+}
+
+// imageContentType is the type of the original image, or of its thumbnail: JPEG covers have JPEG
+// thumbnails, and PNG and WebP covers, which may be transparent, PNG thumbnails
+func imageContentType(cover entities.BookCover, size string) string {
+	if size == dtos.CoverSizeOriginal || cover.ContentType == "image/jpeg" {
+		return cover.ContentType
+	}
+	return "image/png"
+}
+
+func encodeThumbnail(img image.Image, longestSide int, contentType string) ([]byte, error) {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if width > longestSide || height > longestSide {
+		if width >= height {
+			width, height = longestSide, (height*longestSide+width-1)/width
+		} else {
+			width, height = (width*longestSide+height-1)/height, longestSide
+		}
+	}
+
+	thumbnail := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.CatmullRom.Scale(thumbnail, thumbnail.Bounds(), img, bounds, draw.Src, nil)
+
+	var buf bytes.Buffer
+	var err error
+	if contentType == "image/jpeg" {
+		err = jpeg.Encode(&buf, thumbnail, &jpeg.Options{Quality: 85})
+	} else {
+		err = png.Encode(&buf, thumbnail)
+	}
+	return buf.Bytes(), err
+}
+
+func toCoverResponse(cover entities.BookCover) dtos.CoverResponse {
+	urls := map[string]string{dtos.CoverSizeOriginal: fmt.Sprintf("/books/%d/cover", cover.BookId)}
+	for size := range thumbnailSizes {
+		urls[size] = fmt.Sprintf("/books/%d/cover?size=%s", cover.BookId, size)
+	}
+
+	return dtos.CoverResponse{
+		BookId:      cover.BookId,
+		ContentType: cover.ContentType,
+		Width:       cover.Width,
+		Height:      cover.Height,
+		Size:        cover.Size,
+		Urls:        urls,
+		UpdatedAt:   cover.UpdatedAt,
+	}
+}
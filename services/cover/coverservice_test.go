@@ -0,0 +1,355 @@
+package services
+
+import (
+	"bytes"
+	"errors"
+	"github/brunojoenk/golang-test/models/entities"
+	bookrepomock "github/brunojoenk/golang-test/repository/book/mock"
+	coverrepomock "github/brunojoenk/golang-test/repository/cover/mock"
+	"github/brunojoenk/golang-test/storage"
+	storagemock "github/brunojoenk/golang-test/storage/mock"
+	"github/brunojoenk/golang-test/utils"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+var errGeneric = errors.New("generic error")
+
+func encodedImage(t *testing.T, width, height int, contentType string) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for x := 0; x < width; x++ {
+		img.Set(x, 0, color.RGBA{R: 200, A: 255})
+	}
+	var buf bytes.Buffer
+	if contentType == "image/jpeg" {
+		require.NoError(t, jpeg.Encode(&buf, img, nil))
+	} else {
+		require.NoError(t, png.Encode(&buf, img))
+	}
+	return buf.Bytes()
+}
+
+func storedImage(t *testing.T, store storage.BlobStore, key string) (image.Config, string) {
+	blob, err := store.Get(key)
+	require.NoError(t, err)
+	defer blob.Body.Close()
+	config, format, err := image.DecodeConfig(blob.Body)
+	require.NoError(t, err)
+	return config, format
+}
+
+func TestSaveCover(t *testing.T) {
+	tests := map[string]struct {
+		data                   []byte
+		file                   string
+		maxBytes               int
+		expectedErrorOnGetBook error
+		expectedContentType    string
+		expectedWidth          int
+		expectedHeight         int
+		expectedThumbnail      string
+		expectedSmall          image.Point
+		expectedErrorResponse  error
+	}{
+		"success on save jpeg cover": {
+			data:                encodedImage(t, 600, 900, "image/jpeg"),
+			expectedContentType: "image/jpeg",
+			expectedWidth:       600,
+			expectedHeight:      900,
+			expectedThumbnail:   "jpeg",
+			expectedSmall:       image.Point{X: 107, Y: 160},
+		},
+		"success on save png cover": {
+			data:                encodedImage(t, 1000, 500, "image/png"),
+			expectedContentType: "image/png",
+			expectedWidth:       1000,
+			expectedHeight:      500,
+			expectedThumbnail:   "png",
+			expectedSmall:       image.Point{X: 160, Y: 80},
+		},
+		"success on save webp cover": {
+			file:                "../../data/cover.webp",
+			expectedContentType: "image/webp",
+			expectedWidth:       150,
+			expectedHeight:      100,
+			expectedThumbnail:   "png",
+			expectedSmall:       image.Point{X: 150, Y: 100},
+		},
+		"error on save cover (book not found)": {
+			data:                   encodedImage(t, 10, 10, "image/png"),
+			expectedErrorOnGetBook: gorm.ErrRecordNotFound,
+			expectedErrorResponse:  utils.ErrBookIdNotFound,
+		},
+		"error on save cover (too large)": {
+			data:                  encodedImage(t, 100, 100, "image/png"),
+			maxBytes:              100,
+			expectedErrorResponse: utils.ErrCoverTooLarge,
+		},
+		"error on save cover (not an image)": {
+			data:                  []byte("%PDF-1.4 not a cover"),
+			expectedErrorResponse: utils.ErrInvalidCoverType,
+		},
+		"error on save cover (empty)": {
+			data:                  []byte{},
+			expectedErrorResponse: utils.ErrInvalidCoverType,
+		},
+		"error on save cover (corrupted)": {
+			data:                  encodedImage(t, 40, 40, "image/png")[:60],
+			expectedErrorResponse: utils.ErrInvalidCoverImage,
+		},
+		"error on save cover (dimensions too large)": {
+			data:                  encodedImage(t, 6001, 1, "image/png"),
+			expectedErrorResponse: utils.ErrInvalidCoverImage,
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			data := tc.data
+			if tc.file != "" {
+				var err error
+				data, err = os.ReadFile(tc.file)
+				require.NoError(t, err)
+			}
+
+			bookDbMock := new(bookrepomock.BookRepositoryMock)
+			bookDbMock.On("GetBook", 7).Return(entities.Book{Id: 7}, tc.expectedErrorOnGetBook)
+			coverDbMock := new(coverrepomock.CoverRepositoryMock)
+			coverDbMock.On("GetCover", 7).Return(entities.BookCover{}, gorm.ErrRecordNotFound)
+			coverDbMock.On("SaveCover", mock.Anything).Return(nil)
+			store := &storage.LocalBlobStore{Root: t.TempDir()}
+
+			maxBytes := tc.maxBytes
+			if maxBytes == 0 {
+				maxBytes = 5 << 20
+			}
+
+			coverServiceTest := coverService{coverDb: coverDbMock, bookDb: bookDbMock, store: store, maxBytes: maxBytes}
+			cover, err := coverServiceTest.SaveCover(7, bytes.NewReader(data))
+
+			if tc.expectedErrorResponse != nil {
+				require.ErrorIs(t, err, tc.expectedErrorResponse)
+				coverDbMock.AssertNotCalled(t, "SaveCover", mock.Anything)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, 7, cover.BookId)
+			require.Equal(t, tc.expectedContentType, cover.ContentType)
+			require.Equal(t, tc.expectedWidth, cover.Width)
+			require.Equal(t, tc.expectedHeight, cover.Height)
+			require.Equal(t, int64(len(data)), cover.Size)
+			require.Equal(t, map[string]string{
+				"original": "/books/7/cover",
+				"small":    "/books/7/cover?size=small",
+				"medium":   "/books/7/cover?size=medium",
+			}, cover.Urls)
+
+			saved := coverDbMock.Calls[1].Arguments.Get(0).(entities.BookCover)
+			require.True(t, strings.HasPrefix(saved.Key, "covers/7/"))
+
+			original, err := store.Get(saved.Key + "/original")
+			require.NoError(t, err)
+			stored, _ := io.ReadAll(original.Body)
+			original.Body.Close()
+			require.Equal(t, data, stored)
+
+			small, format := storedImage(t, store, saved.Key+"/small")
+			require.Equal(t, tc.expectedThumbnail, format)
+			require.Equal(t, tc.expectedSmall, image.Point{X: small.Width, Y: small.Height})
+			medium, _ := storedImage(t, store, saved.Key+"/medium")
+			require.LessOrEqual(t, medium.Width, 480)
+			require.LessOrEqual(t, medium.Height, 480)
+		})
+	}
+}
+
+func TestSaveCoverReplacesPrevious(t *testing.T) {
+	store := &storage.LocalBlobStore{Root: t.TempDir()}
+	previous := entities.BookCover{BookId: 7, Key: "covers/7/1", ContentType: "image/png"}
+	for _, size := range []string{"original", "small", "medium"} {
+		require.NoError(t, store.Put("covers/7/1/"+size, strings.NewReader("old"), 3, "image/png"))
+	}
+
+	bookDbMock := new(bookrepomock.BookRepositoryMock)
+	bookDbMock.On("GetBook", 7).Return(entities.Book{Id: 7}, nil)
+	coverDbMock := new(coverrepomock.CoverRepositoryMock)
+	coverDbMock.On("GetCover", 7).Return(previous, nil)
+	coverDbMock.On("SaveCover", mock.Anything).Return(nil)
+
+	coverServiceTest := coverService{coverDb: coverDbMock, bookDb: bookDbMock, store: store, maxBytes: 5 << 20}
+	_, err := coverServiceTest.SaveCover(7, bytes.NewReader(encodedImage(t, 20, 30, "image/png")))
+
+	require.NoError(t, err)
+	for _, size := range []string{"original", "small", "medium"} {
+		_, err := store.Get("covers/7/1/" + size)
+		require.ErrorIs(t, err, storage.ErrBlobNotFound)
+	}
+}
+
+func TestSaveCoverErrors(t *testing.T) {
+	tests := map[string]struct {
+		expectedErrorOnGetCover  error
+		expectedErrorOnPut       error
+		expectedErrorOnSaveCover error
+		expectedDeletes          int
+	}{
+		"error on save cover (get previous cover)": {
+			expectedErrorOnGetCover: errGeneric,
+		},
+		"error on save cover (blob store)": {
+			expectedErrorOnPut: errGeneric,
+			expectedDeletes:    3,
+		},
+		"error on save cover (repository)": {
+			expectedErrorOnSaveCover: errGeneric,
+			expectedDeletes:          3,
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			bookDbMock := new(bookrepomock.BookRepositoryMock)
+			bookDbMock.On("GetBook", 7).Return(entities.Book{Id: 7}, nil)
+			coverDbMock := new(coverrepomock.CoverRepositoryMock)
+			coverDbMock.On("GetCover", 7).Return(entities.BookCover{}, tc.expectedErrorOnGetCover)
+			coverDbMock.On("SaveCover", mock.Anything).Return(tc.expectedErrorOnSaveCover)
+			storeMock := new(storagemock.BlobStoreMock)
+			storeMock.On("Put", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(tc.expectedErrorOnPut)
+			storeMock.On("Delete", mock.Anything).Return(nil)
+
+			coverServiceTest := coverService{coverDb: coverDbMock, bookDb: bookDbMock, store: storeMock, maxBytes: 5 << 20}
+			_, err := coverServiceTest.SaveCover(7, bytes.NewReader(encodedImage(t, 20, 30, "image/png")))
+
+			require.ErrorIs(t, err, errGeneric)
+			storeMock.AssertNumberOfCalls(t, "Delete", tc.expectedDeletes)
+		})
+	}
+}
+
+func TestGetCover(t *testing.T) {
+	cover := entities.BookCover{BookId: 7, Key: "covers/7/1664618400", ContentType: "image/webp"}
+	tests := map[string]struct {
+		size                    string
+		expectedErrorOnGetBook  error
+		expectedErrorOnGetCover error
+		expectedErrorOnGetBlob  error
+		expectedKey             string
+		expectedContentType     string
+		expectedETag            string
+		expectedErrorResponse   error
+	}{
+		"success on get original cover": {
+			expectedKey:         "covers/7/1664618400/original",
+			expectedContentType: "image/webp",
+			expectedETag:        `"1664618400-original"`,
+		},
+		"success on get small cover": {
+			size:                "Small",
+			expectedKey:         "covers/7/1664618400/small",
+			expectedContentType: "image/png",
+			expectedETag:        `"1664618400-small"`,
+		},
+		"error on get cover (invalid size)": {
+			size:                  "huge",
+			expectedErrorResponse: utils.ErrInvalidCoverSize,
+		},
+		"error on get cover (book not found)": {
+			expectedErrorOnGetBook: gorm.ErrRecordNotFound,
+			expectedErrorResponse:  utils.ErrBookIdNotFound,
+		},
+		"error on get cover (book without cover)": {
+			expectedErrorOnGetCover: gorm.ErrRecordNotFound,
+			expectedErrorResponse:   utils.ErrCoverNotFound,
+		},
+		"error on get cover (image missing on the store)": {
+			expectedKey:            "covers/7/1664618400/original",
+			expectedErrorOnGetBlob: storage.ErrBlobNotFound,
+			expectedErrorResponse:  utils.ErrCoverNotFound,
+		},
+		"error on get cover (blob store)": {
+			expectedKey:            "covers/7/1664618400/original",
+			expectedErrorOnGetBlob: errGeneric,
+			expectedErrorResponse:  errGeneric,
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			bookDbMock := new(bookrepomock.BookRepositoryMock)
+			bookDbMock.On("GetBook", 7).Return(entities.Book{Id: 7}, tc.expectedErrorOnGetBook)
+			coverDbMock := new(coverrepomock.CoverRepositoryMock)
+			coverDbMock.On("GetCover", 7).Return(cover, tc.expectedErrorOnGetCover)
+			storeMock := new(storagemock.BlobStoreMock)
+			storeMock.On("Get", tc.expectedKey).Return(storage.Blob{Body: io.NopCloser(strings.NewReader("image")), Size: 5}, tc.expectedErrorOnGetBlob)
+
+			coverServiceTest := coverService{coverDb: coverDbMock, bookDb: bookDbMock, store: storeMock}
+			coverImage, err := coverServiceTest.GetCover(7, tc.size)
+
+			if tc.expectedErrorResponse != nil {
+				require.ErrorIs(t, err, tc.expectedErrorResponse)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedContentType, coverImage.ContentType)
+			require.Equal(t, int64(5), coverImage.Size)
+			require.Equal(t, tc.expectedETag, coverImage.ETag)
+		})
+	}
+}
+
+func TestDeleteCover(t *testing.T) {
+	cover := entities.BookCover{BookId: 7, Key: "covers/7/1664618400", ContentType: "image/png"}
+	tests := map[string]struct {
+		expectedErrorOnGetCover error
+		expectedErrorOnDelete   error
+		expectedErrorOnBlob     error
+		expectedDeletes         int
+		expectedErrorResponse   error
+	}{
+		"success on delete cover": {
+			expectedDeletes: 3,
+		},
+		"success on delete cover (blob store fails)": {
+			expectedErrorOnBlob: errGeneric,
+			expectedDeletes:     3,
+		},
+		"error on delete cover (book without cover)": {
+			expectedErrorOnGetCover: gorm.ErrRecordNotFound,
+			expectedErrorResponse:   utils.ErrCoverNotFound,
+		},
+		"error on delete cover (repository)": {
+			expectedErrorOnDelete: errGeneric,
+			expectedErrorResponse: errGeneric,
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			bookDbMock := new(bookrepomock.BookRepositoryMock)
+			bookDbMock.On("GetBook", 7).Return(entities.Book{Id: 7}, nil)
+			coverDbMock := new(coverrepomock.CoverRepositoryMock)
+			coverDbMock.On("GetCover", 7).Return(cover, tc.expectedErrorOnGetCover)
+			coverDbMock.On("DeleteCover", 7).Return(tc.expectedErrorOnDelete)
+			storeMock := new(storagemock.BlobStoreMock)
+			storeMock.On("Delete", mock.MatchedBy(func(key string) bool {
+				return strings.HasPrefix(key, "covers/7/1664618400/")
+			})).Return(tc.expectedErrorOnBlob)
+
+			coverServiceTest := coverService{coverDb: coverDbMock, bookDb: bookDbMock, store: storeMock}
+			err := coverServiceTest.DeleteCover(7)
+
+			if tc.expectedErrorResponse != nil {
+				require.ErrorIs(t, err, tc.expectedErrorResponse)
+			} else {
+				require.NoError(t, err)
+			}
+			storeMock.AssertNumberOfCalls(t, "Delete", tc.expectedDeletes)
+		})
+	}
+}
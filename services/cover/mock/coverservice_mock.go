@@ -0,0 +1,27 @@
+package services
+
+import (
+	"github/brunojoenk/golang-test/models/dtos"
+	"io"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type CoverServiceMock struct {
+	mock.Mock
+}
+
+func (m *CoverServiceMock) SaveCover(bookId int, body io.Reader) (dtos.CoverResponse, error) {
+	args := m.Called(bookId, body)
+	return args.Get(0).(dtos.CoverResponse), args.Error(1)
+}
+
+func (m *CoverServiceMock) GetCover(bookId int, size string) (dtos.CoverImage, error) {
+	args := m.Called(bookId, size)
+	return args.Get(0).(dtos.CoverImage), args.Error(1)
+}
+
+func (m *CoverServiceMock) DeleteCover(bookId int) error {
+	args := m.Called(bookId)
+	return args.Error(0)
+}
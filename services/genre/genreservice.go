@@ -0,0 +1,192 @@
+package services
+
+import (
+	"errors"
+	"github/brunojoenk/golang-test/models/dtos"
+	"github/brunojoenk/golang-test/models/entities"
+	genrerepo "github/brunojoenk/golang-test/repository/genre"
+	"github/brunojoenk/golang-test/utils"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type IGenreService interface {
+	CreateGenre(genreRequest dtos.GenreRequest) (dtos.GenreResponse, error)
+	GetGenreTree() ([]dtos.GenreResponse, error)
+	UpdateGenre(id int, genreRequest dtos.GenreRequest) (dtos.GenreResponse, error)
+	DeleteGenre(id int) error
+}
+
+type genreService struct {
+	genreDb genrerepo.IGenreRepository
+}
+
+// NewGenreService Service Constructor
+func NewGenreService(db *gorm.DB) IGenreService {
+	return &genreService{genreDb: genrerepo.NewGenreRepository(db)}
+}
+
+func (g *genreService) CreateGenre(genreRequest dtos.GenreRequest) (dtos.GenreResponse, error) {
+	var genre entities.Genre
+	if err := g.setFields(&genre, genreRequest); err != nil {
+		return dtos.GenreResponse{}, err
+	}
+
+	genre, err := g.genreDb.CreateGenre(genre)
+	if err != nil {
+		log.Error("Error on create genre from repo: ", err.Error())
+		return dtos.GenreResponse{}, err
+	}
+
+	return toGenreResponse(genre), nil
+}
+
+// GetGenreTree returns the root genres, each with its subgenres, sorted by name on every level
+func (g *genreService) GetGenreTree() ([]dtos.GenreResponse, error) {
+	genres, err := g.genreDb.GetAllGenres()
+	if err != nil {
+		log.Error("Error on get all genres from repo: ", err.Error())
+		return nil, err
+	}
+
+	children := make(map[int][]entities.Genre)
+	var roots []entities.Genre
+	for _, genre := range genres {
+		if genre.ParentId == nil {
+			roots = append(roots, genre)
+			continue
+		}
+		children[*genre.ParentId] = append(children[*genre.ParentId], genre)
+	}
+
+	return buildTree(roots, children), nil
+}
+
+func (g *genreService) UpdateGenre(id int, genreRequest dtos.GenreRequest) (dtos.GenreResponse, error) {
+	genre, err := g.getGenre(id)
+	if err != nil {
+		return dtos.GenreResponse{}, err
+	}
+
+	if err := g.setFields(&genre, genreRequest); err != nil {
+		return dtos.GenreResponse{}, err
+	}
+
+	genre, err = g.genreDb.UpdateGenre(genre)
+	if err != nil {
+		log.Error("Error on update genre from repo: ", err.Error())
+		return dtos.GenreResponse{}, err
+	}
+
+	return toGenreResponse(genre), nil
+}
+
+// DeleteGenre deletes a genre without subgenres, unlinking it from its books
+func (g *genreService) DeleteGenre(id int) error {
+	if _, err := g.getGenre(id); err != nil {
+		return err
+	}
+
+	children, err := g.genreDb.CountChildren(id)
+	if err != nil {
+		log.Error("Error on count subgenres from repo: ", err.Error())
+		return err
+	}
+	if children > 0 {
+		return utils.ErrGenreHasChildren
+	}
+
+	if err := g.genreDb.DeleteGenre(id); err != nil {
+		log.Error("Error on delete genre from repo: ", err.Error())
+		return err
+	}
+
+	return nil
+}
+
+// setFields validates the request and copies it to the genre. Names are unique among the
+// children of the same parent, and a genre can not be moved under one of its subgenres
+func (g *genreService) setFields(genre *entities.Genre, genreRequest dtos.GenreRequest) error {
+	name := strings.TrimSpace(genreRequest.Name)
+	normalizedName := utils.NormalizeName(name)
+	if normalizedName == "" {
+		return utils.ErrGenreNameEmpty
+	}
+
+	var parentId *int
+	if genreRequest.ParentId != 0 {
+		if err := g.checkParent(genre.Id, genreRequest.ParentId); err != nil {
+			return err
+		}
+		parentId = &genreRequest.ParentId
+	}
+
+	existing, err := g.genreDb.GetGenreByName(parentId, normalizedName)
+	if err == nil && existing.Id != genre.Id {
+		return utils.ErrGenreNameExists
+	}
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		log.Error("Error on get genre by name from repo: ", err.Error())
+		return err
+	}
+
+	genre.Name = name
+	genre.NormalizedName = normalizedName
+	genre.ParentId = parentId
+	return nil
+}
+
+// checkParent walks up from the parent to the root, failing when it goes through the genre itself
+func (g *genreService) checkParent(id, parentId int) error {
+	for ancestorId := &parentId; ancestorId != nil; {
+		if *ancestorId == id {
+			return utils.ErrGenreCycle
+		}
+		ancestor, err := g.genreDb.GetGenre(*ancestorId)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return utils.ErrGenreParentIdNotFound
+			}
+			log.Error("Error on get parent genre from repo: ", err.Error())
+			return err
+		}
+		ancestorId = ancestor.ParentId
+	}
+	return nil
+}
+
+func (g *genreService) getGenre(id int) (entities.Genre, error) {
+	genre, err := g.genreDb.GetGenre(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return entities.Genre{}, utils.ErrGenreIdNotFound
+		}
+		log.Error("Error on get genre from repo: ", err.Error())
+		return entities.Genre{}, err
+	}
+	return genre, nil
+}
+
+func buildTree(genres []entities.Genre, children map[int][]entities.Genre) []dtos.GenreResponse {
+	tree := make([]dtos.GenreResponse, len(genres))
+	for i, genre := range genres {
+		tree[i] = toGenreResponse(genre)
+		if subgenres, ok := children[genre.Id]; ok {
+			tree[i].Children = buildTree(subgenres, children)
+		}
+	}
+	return tree
+}
+
+func toGenreResponse(genre entities.Genre) dtos.GenreResponse {
+	genreResponse := dtos.GenreResponse{
+		Id:   genre.Id,
+		Name: genre.Name,
+	}
+	if genre.ParentId != nil {
+		genreResponse.ParentId = *genre.ParentId
+	}
+	return genreResponse
+}
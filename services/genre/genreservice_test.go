@@ -0,0 +1,251 @@
+package services
+
+import (
+	"errors"
+	"github/brunojoenk/golang-test/models/dtos"
+	"github/brunojoenk/golang-test/models/entities"
+	genrerepomock "github/brunojoenk/golang-test/repository/genre/mock"
+	"github/brunojoenk/golang-test/utils"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+var errGeneric = errors.New("generic error")
+
+func TestCreateGenre(t *testing.T) {
+	parentId := 1
+	tests := map[string]struct {
+		request                  dtos.GenreRequest
+		expectedErrorOnGetParent error
+		existing                 entities.Genre
+		expectedErrorOnGetByName error
+		expectedGenre            entities.Genre
+		expectedErrorOnCreate    error
+		expectedErrorResponse    error
+	}{
+		"success on create genre": {
+			request:                  dtos.GenreRequest{Name: " Fantasy ", ParentId: parentId},
+			expectedErrorOnGetByName: gorm.ErrRecordNotFound,
+			expectedGenre:            entities.Genre{Name: "Fantasy", NormalizedName: "fantasy", ParentId: &parentId},
+		},
+		"error occurred on create genre (empty name)": {
+			request:               dtos.GenreRequest{Name: " - "},
+			expectedErrorResponse: utils.ErrGenreNameEmpty,
+		},
+		"error occurred on create genre (parent not found)": {
+			request:                  dtos.GenreRequest{Name: "Fantasy", ParentId: parentId},
+			expectedErrorOnGetParent: gorm.ErrRecordNotFound,
+			expectedErrorResponse:    utils.ErrGenreParentIdNotFound,
+		},
+		"error occurred on create genre (get parent)": {
+			request:                  dtos.GenreRequest{Name: "Fantasy", ParentId: parentId},
+			expectedErrorOnGetParent: errGeneric,
+			expectedErrorResponse:    errGeneric,
+		},
+		"error occurred on create genre (name exists)": {
+			request:               dtos.GenreRequest{Name: "fantasy", ParentId: parentId},
+			existing:              entities.Genre{Id: 2},
+			expectedErrorResponse: utils.ErrGenreNameExists,
+		},
+		"error occurred on create genre (get by name)": {
+			request:                  dtos.GenreRequest{Name: "Fantasy", ParentId: parentId},
+			expectedErrorOnGetByName: errGeneric,
+			expectedErrorResponse:    errGeneric,
+		},
+		"error occurred on create genre (create)": {
+			request:                  dtos.GenreRequest{Name: "Fantasy", ParentId: parentId},
+			expectedErrorOnGetByName: gorm.ErrRecordNotFound,
+			expectedGenre:            entities.Genre{Name: "Fantasy", NormalizedName: "fantasy", ParentId: &parentId},
+			expectedErrorOnCreate:    errGeneric,
+			expectedErrorResponse:    errGeneric,
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			genreDbMock := new(genrerepomock.GenreRepositoryMock)
+			genreDbMock.On("GetGenre", parentId).Return(entities.Genre{Id: parentId, Name: "Fiction"}, tc.expectedErrorOnGetParent)
+			genreDbMock.On("GetGenreByName", &parentId, "fantasy").Return(tc.existing, tc.expectedErrorOnGetByName)
+			created := tc.expectedGenre
+			created.Id = 2
+			genreDbMock.On("CreateGenre", tc.expectedGenre).Return(created, tc.expectedErrorOnCreate)
+
+			genreServiceTest := genreService{genreDb: genreDbMock}
+			resp, err := genreServiceTest.CreateGenre(tc.request)
+			if tc.expectedErrorResponse != nil {
+				require.ErrorIs(t, err, tc.expectedErrorResponse)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, dtos.GenreResponse{Id: 2, Name: "Fantasy", ParentId: parentId}, resp)
+			}
+		})
+	}
+}
+
+func TestGetGenreTree(t *testing.T) {
+	var (
+		fiction = 1
+		fantasy = 3
+	)
+	tests := map[string]struct {
+		genres                []entities.Genre
+		expectedErrorOnGetAll error
+		expectedTree          []dtos.GenreResponse
+	}{
+		"success on get genre tree": {
+			genres: []entities.Genre{
+				{Id: 2, Name: "Biography"},
+				{Id: fantasy, Name: "Fantasy", ParentId: &fiction},
+				{Id: fiction, Name: "Fiction"},
+				{Id: 4, Name: "High Fantasy", ParentId: &fantasy},
+				{Id: 5, Name: "Horror", ParentId: &fiction},
+			},
+			expectedTree: []dtos.GenreResponse{
+				{Id: 2, Name: "Biography"},
+				{Id: fiction, Name: "Fiction", Children: []dtos.GenreResponse{
+					{Id: fantasy, Name: "Fantasy", ParentId: fiction, Children: []dtos.GenreResponse{
+						{Id: 4, Name: "High Fantasy", ParentId: fantasy},
+					}},
+					{Id: 5, Name: "Horror", ParentId: fiction},
+				}},
+			},
+		},
+		"success on get genre tree (empty)": {
+			genres:       []entities.Genre{},
+			expectedTree: []dtos.GenreResponse{},
+		},
+		"error occurred on get genre tree": {
+			genres:                []entities.Genre{},
+			expectedErrorOnGetAll: errGeneric,
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			genreDbMock := new(genrerepomock.GenreRepositoryMock)
+			genreDbMock.On("GetAllGenres").Return(tc.genres, tc.expectedErrorOnGetAll)
+
+			genreServiceTest := genreService{genreDb: genreDbMock}
+			resp, err := genreServiceTest.GetGenreTree()
+			if tc.expectedErrorOnGetAll != nil {
+				require.ErrorIs(t, err, tc.expectedErrorOnGetAll)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tc.expectedTree, resp)
+			}
+		})
+	}
+}
+
+func TestUpdateGenre(t *testing.T) {
+	var (
+		fiction = 1
+		fantasy = 3
+		high    = 4
+	)
+	tests := map[string]struct {
+		id                    int
+		request               dtos.GenreRequest
+		expectedErrorOnGet    error
+		expectedGenre         entities.Genre
+		expectedErrorOnUpdate error
+		expectedErrorResponse error
+	}{
+		"success on update genre (rename)": {
+			id:            fantasy,
+			request:       dtos.GenreRequest{Name: "Fantasy Fiction", ParentId: fiction},
+			expectedGenre: entities.Genre{Id: fantasy, Name: "Fantasy Fiction", NormalizedName: "fantasy fiction", ParentId: &fiction},
+		},
+		"success on update genre (move to root)": {
+			id:            fantasy,
+			request:       dtos.GenreRequest{Name: "Fantasy"},
+			expectedGenre: entities.Genre{Id: fantasy, Name: "Fantasy", NormalizedName: "fantasy"},
+		},
+		"error occurred on update genre (not found)": {
+			id:                    fantasy,
+			request:               dtos.GenreRequest{Name: "Fantasy"},
+			expectedErrorOnGet:    gorm.ErrRecordNotFound,
+			expectedErrorResponse: utils.ErrGenreIdNotFound,
+		},
+		"error occurred on update genre (parent is itself)": {
+			id:                    fantasy,
+			request:               dtos.GenreRequest{Name: "Fantasy", ParentId: fantasy},
+			expectedErrorResponse: utils.ErrGenreCycle,
+		},
+		"error occurred on update genre (parent is a subgenre)": {
+			id:                    fiction,
+			request:               dtos.GenreRequest{Name: "Fiction", ParentId: high},
+			expectedErrorResponse: utils.ErrGenreCycle,
+		},
+		"error occurred on update genre (update)": {
+			id:                    fantasy,
+			request:               dtos.GenreRequest{Name: "Fantasy"},
+			expectedGenre:         entities.Genre{Id: fantasy, Name: "Fantasy", NormalizedName: "fantasy"},
+			expectedErrorOnUpdate: errGeneric,
+			expectedErrorResponse: errGeneric,
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			genreDbMock := new(genrerepomock.GenreRepositoryMock)
+			genreDbMock.On("GetGenre", fiction).Return(entities.Genre{Id: fiction, Name: "Fiction", NormalizedName: "fiction"}, nil)
+			genreDbMock.On("GetGenre", fantasy).Return(entities.Genre{Id: fantasy, Name: "Fantasy", NormalizedName: "fantasy", ParentId: &fiction}, tc.expectedErrorOnGet)
+			genreDbMock.On("GetGenre", high).Return(entities.Genre{Id: high, Name: "High Fantasy", NormalizedName: "high fantasy", ParentId: &fantasy}, nil)
+			genreDbMock.On("GetGenreByName", tc.expectedGenre.ParentId, tc.expectedGenre.NormalizedName).Return(entities.Genre{Id: fantasy}, nil)
+			genreDbMock.On("UpdateGenre", tc.expectedGenre).Return(tc.expectedGenre, tc.expectedErrorOnUpdate)
+
+			genreServiceTest := genreService{genreDb: genreDbMock}
+			resp, err := genreServiceTest.UpdateGenre(tc.id, tc.request)
+			if tc.expectedErrorResponse != nil {
+				require.ErrorIs(t, err, tc.expectedErrorResponse)
+				if tc.expectedErrorOnUpdate == nil {
+					genreDbMock.AssertNotCalled(t, "UpdateGenre", tc.expectedGenre)
+				}
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tc.request.Name, resp.Name)
+				require.Equal(t, tc.request.ParentId, resp.ParentId)
+			}
+		})
+	}
+}
+
+func TestDeleteGenre(t *testing.T) {
+	tests := map[string]struct {
+		expectedErrorOnGet      error
+		children                int64
+		expectedErrorOnChildren error
+		expectedErrorOnDelete   error
+		expectedErrorResponse   error
+	}{
+		"success on delete genre": {},
+		"error occurred on delete genre (not found)": {
+			expectedErrorOnGet:    gorm.ErrRecordNotFound,
+			expectedErrorResponse: utils.ErrGenreIdNotFound,
+		},
+		"error occurred on delete genre (has subgenres)": {
+			children:              2,
+			expectedErrorResponse: utils.ErrGenreHasChildren,
+		},
+		"error occurred on delete genre (count subgenres)": {
+			expectedErrorOnChildren: errGeneric,
+			expectedErrorResponse:   errGeneric,
+		},
+		"error occurred on delete genre (delete)": {
+			expectedErrorOnDelete: errGeneric,
+			expectedErrorResponse: errGeneric,
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			genreDbMock := new(genrerepomock.GenreRepositoryMock)
+			genreDbMock.On("GetGenre", 1).Return(entities.Genre{Id: 1}, tc.expectedErrorOnGet)
+			genreDbMock.On("CountChildren", 1).Return(tc.children, tc.expectedErrorOnChildren)
+			genreDbMock.On("DeleteGenre", 1).Return(tc.expectedErrorOnDelete)
+
+			genreServiceTest := genreService{genreDb: genreDbMock}
+			err := genreServiceTest.DeleteGenre(1)
+			require.ErrorIs(t, err, tc.expectedErrorResponse)
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package services
+
+import (
+	"github/brunojoenk/golang-test/models/dtos"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type GenreServiceMock struct {
+	mock.Mock
+}
+
+func (m *GenreServiceMock) CreateGenre(genreRequest dtos.GenreRequest) (dtos.GenreResponse, error) {
+	args := m.Called(genreRequest)
+	return args.Get(0).(dtos.GenreResponse), args.Error(1)
+}
+
+func (m *GenreServiceMock) GetGenreTree() ([]dtos.GenreResponse, error) {
+	args := m.Called()
+	return args.Get(0).([]dtos.GenreResponse), args.Error(1)
+}
+
+func (m *GenreServiceMock) UpdateGenre(id int, genreRequest dtos.GenreRequest) (dtos.GenreResponse, error) {
+	args := m.Called(id, genreRequest)
+	return args.Get(0).(dtos.GenreResponse), args.Error(1)
+}
+
+func (m *GenreServiceMock) DeleteGenre(id int) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
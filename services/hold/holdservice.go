@@ -0,0 +1,386 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"github/brunojoenk/golang-test/config"
+	"github/brunojoenk/golang-test/models/dtos"
+	"github/brunojoenk/golang-test/models/entities"
+	bookrepo "github/brunojoenk/golang-test/repository/book"
+	holdrepo "github/brunojoenk/golang-test/repository/hold"
+	outboxrepo "github/brunojoenk/golang-test/repository/outbox"
+	patronrepo "github/brunojoenk/golang-test/repository/patron"
+	"github/brunojoenk/golang-test/utils"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// queueAttempts is how many times the queue of a book is processed when a copy changes meanwhile
+const queueAttempts = 3
+
+// HoldNotifier tells a patron that a copy of the book they hold was put aside for them
+type HoldNotifier interface {
+	NotifyHoldReady(hold entities.Hold, patron entities.Patron) error
+}
+
+// OutboxHoldNotifier queues the notifications in the outbox, which the scheduler delivers
+type OutboxHoldNotifier struct {
+	outboxDb outboxrepo.IOutboxRepository
+	bookDb   bookrepo.IBookRepository
+}
+
+// NotifyHoldReady queues one message each time the hold gets ready, a hold that goes back to waiting
+// and gets ready again is notified again
+func (o OutboxHoldNotifier) NotifyHoldReady(hold entities.Hold, patron entities.Patron) error {
+	books, err := o.bookDb.GetBookNames([]int{hold.BookId})
+	if err != nil {
+		return err
+	}
+	bookName := fmt.Sprintf("Book %d", hold.BookId)
+	if len(books) > 0 {
+		bookName = books[0].Name
+	}
+
+	_, err = o.outboxDb.EnqueueMessage(entities.OutboxMessage{
+		Kind:      dtos.NotificationHoldReady,
+		Recipient: patron.Email,
+		Subject:   fmt.Sprintf("Ready for pickup: %s", bookName),
+		Body: fmt.Sprintf("Hello %s,\n\n%s is waiting for you. Pick it up by %s or the hold expires.",
+			patron.Name, bookName, hold.ExpiresAt.Format(utils.DATE_LAYOUT)),
+		DedupKey:      fmt.Sprintf("%s:%d:%d", dtos.NotificationHoldReady, hold.Id, hold.ReadyAt.Unix()),
+		Status:        dtos.OutboxStatusPending,
+		NextAttemptAt: time.Now(),
+	})
+	return err
+}
+
+type IHoldService interface {
+	PlaceHold(bookId int, holdRequest dtos.HoldRequest) (dtos.HoldResponse, error)
+	GetBookHolds(bookId int) (dtos.HoldsResponse, error)
+	GetHold(id int) (dtos.HoldResponse, error)
+	CancelHold(id int) (dtos.HoldResponse, error)
+	ProcessQueue(bookId int) error
+	ExpireHolds(now time.Time) error
+}
+
+type holdService struct {
+	holdDb     holdrepo.IHoldRepository
+	bookDb     bookrepo.IBookRepository
+	patronDb   patronrepo.IPatronRepository
+	notifier   HoldNotifier
+	pickupDays int
+}
+
+// NewHoldService Service Constructor
+func NewHoldService(db *gorm.DB, cfg *config.Config) IHoldService {
+	return &holdService{
+		holdDb:     holdrepo.NewHoldRepository(db),
+		bookDb:     bookrepo.NewBookRepository(db),
+		patronDb:   patronrepo.NewPatronRepository(db),
+		notifier:   OutboxHoldNotifier{outboxDb: outboxrepo.NewOutboxRepository(db), bookDb: bookrepo.NewBookRepository(db)},
+		pickupDays: cfg.HoldPickupDays,
+	}
+}
+
+// PlaceHold puts the patron at the end of the queue of the book. When a copy is free already the
+// hold is ready right away
+func (h *holdService) PlaceHold(bookId int, holdRequest dtos.HoldRequest) (dtos.HoldResponse, error) {
+	if err := h.checkBook(bookId); err != nil {
+		return dtos.HoldResponse{}, err
+	}
+
+	if _, err := h.patronDb.GetPatron(holdRequest.PatronId); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return dtos.HoldResponse{}, utils.ErrPatronIdNotFound
+		}
+		log.Error("Error on get patron from repo: ", err.Error())
+		return dtos.HoldResponse{}, err
+	}
+
+	_, err := h.holdDb.GetOpenHold(bookId, holdRequest.PatronId)
+	if err == nil {
+		return dtos.HoldResponse{}, utils.ErrHoldExists
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		log.Error("Error on get open hold from repo: ", err.Error())
+		return dtos.HoldResponse{}, err
+	}
+
+	hold, err := h.holdDb.CreateHold(entities.Hold{
+		BookId:   bookId,
+		PatronId: holdRequest.PatronId,
+		Status:   dtos.HoldStatusWaiting,
+		PlacedAt: time.Now(),
+	})
+	if err != nil {
+		log.Error("Error on create hold from repo: ", err.Error())
+		return dtos.HoldResponse{}, err
+	}
+
+	// The hold is placed even when the queue can not be processed now, it is processed again later
+	_ = h.ProcessQueue(bookId)
+
+	return h.holdResponse(hold.Id)
+}
+
+// GetBookHolds lists the queue of the book: the ready holds and the waiting ones with their position
+func (h *holdService) GetBookHolds(bookId int) (dtos.HoldsResponse, error) {
+	if err := h.checkBook(bookId); err != nil {
+		return dtos.HoldsResponse{}, err
+	}
+
+	// Pickups that expired are let go first, so the queue is listed as it stands
+	_ = h.ProcessQueue(bookId)
+
+	holds, err := h.holdDb.GetBookHolds(bookId)
+	if err != nil {
+		log.Error("Error on get holds of book from repo: ", err.Error())
+		return dtos.HoldsResponse{}, err
+	}
+
+	return dtos.HoldsResponse{Holds: toHoldsResponse(holds)}, nil
+}
+
+func (h *holdService) GetHold(id int) (dtos.HoldResponse, error) {
+	hold, err := h.getHold(id)
+	if err != nil {
+		return dtos.HoldResponse{}, err
+	}
+
+	_ = h.ProcessQueue(hold.BookId)
+
+	return h.holdResponse(id)
+}
+
+// CancelHold takes the hold out of the queue. A copy put aside for it goes to the next in line
+func (h *holdService) CancelHold(id int) (dtos.HoldResponse, error) {
+	hold, err := h.getHold(id)
+	if err != nil {
+		return dtos.HoldResponse{}, err
+	}
+
+	if err := h.holdDb.CancelHold(id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return dtos.HoldResponse{}, utils.ErrHoldClosed
+		}
+		log.Error("Error on cancel hold from repo: ", err.Error())
+		return dtos.HoldResponse{}, err
+	}
+
+	_ = h.ProcessQueue(hold.BookId)
+
+	hold.Status = dtos.HoldStatusCancelled
+	hold.CopyId = nil
+	return toHoldResponse(hold, 0), nil
+}
+
+// ProcessQueue lets go of the expired pickups and of the holds whose copy left circulation, and puts
+// the free copies of the book aside for the holds first in line, notifying their patrons. It runs
+// whenever a copy may have freed up; errors are logged and returned for callers that care
+func (h *holdService) ProcessQueue(bookId int) error {
+	var (
+		changed []entities.Hold
+		err     error
+	)
+	for attempt := 0; attempt < queueAttempts; attempt++ {
+		now := time.Now()
+		changed, err = h.holdDb.ProcessQueue(bookId, func(holds []entities.Hold, copies []entities.Copy) ([]entities.Hold, []entities.Copy) {
+			return planQueue(holds, copies, now, h.pickupDays)
+		})
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			break
+		}
+	}
+	if err != nil {
+		log.Error("Error on process holds of book from repo: ", err.Error())
+		return err
+	}
+
+	for _, hold := range changed {
+		if hold.Status != dtos.HoldStatusReady {
+			continue
+		}
+		patron, err := h.patronDb.GetPatron(hold.PatronId)
+		if err != nil {
+			log.Error("Error on get patron to notify from repo: ", err.Error())
+			continue
+		}
+		if err := h.notifier.NotifyHoldReady(hold, patron); err != nil {
+			log.Error("Error on notify patron of ready hold: ", err.Error())
+		}
+	}
+
+	return nil
+}
+
+// ExpireHolds processes the queues of the books with a ready hold whose pickup expired by now, so the
+// copy goes to the next in line without waiting for the queue to be touched. It runs periodically, a
+// book that fails is retried on the next run
+func (h *holdService) ExpireHolds(now time.Time) error {
+	bookIds, err := h.holdDb.GetBooksWithExpiredHolds(now)
+	if err != nil {
+		log.Error("Error on get books with expired holds from repo: ", err.Error())
+		return err
+	}
+
+	var firstErr error
+	for _, bookId := range bookIds {
+		if err := h.ProcessQueue(bookId); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	return firstErr
+}
+
+// planQueue works out the next state of the queue of a book at the time now, from its open holds,
+// oldest first, and its copies:
+//   - a ready hold past its pickup expiry expires, and its copy is freed
+//   - a ready hold whose copy is no longer on hold (lost, withdrawn or deleted) goes back to waiting,
+//     keeping its place in line
+//   - a copy on hold for no ready hold, e.g. of a cancelled hold, is freed
+//   - the free copies go to the waiting holds in the order they were placed
+//
+// It returns the holds and copies that changed, the holds giving a copy up first
+func planQueue(holds []entities.Hold, copies []entities.Copy, now time.Time, pickupDays int) ([]entities.Hold, []entities.Copy) {
+	statuses := make(map[int]string, len(copies))
+	for _, bookCopy := range copies {
+		statuses[bookCopy.Id] = bookCopy.Status
+	}
+
+	var released, waiting []entities.Hold
+	held := make(map[int]bool)
+	for _, hold := range holds {
+		switch hold.Status {
+		case dtos.HoldStatusWaiting:
+			waiting = append(waiting, hold)
+		case dtos.HoldStatusReady:
+			switch {
+			case hold.ExpiresAt != nil && hold.ExpiresAt.Before(now):
+				hold.Status, hold.CopyId = dtos.HoldStatusExpired, nil
+				released = append(released, hold)
+			case hold.CopyId == nil || statuses[*hold.CopyId] != dtos.CopyStatusOnHold:
+				hold.Status, hold.CopyId, hold.ReadyAt, hold.ExpiresAt = dtos.HoldStatusWaiting, nil, nil, nil
+				released = append(released, hold)
+				waiting = append(waiting, hold)
+			default:
+				held[*hold.CopyId] = true
+			}
+		}
+	}
+
+	var free []int
+	for _, bookCopy := range copies {
+		if bookCopy.Status == dtos.CopyStatusOnHold && !held[bookCopy.Id] {
+			statuses[bookCopy.Id] = dtos.CopyStatusAvailable
+		}
+		if statuses[bookCopy.Id] == dtos.CopyStatusAvailable {
+			free = append(free, bookCopy.Id)
+		}
+	}
+
+	// Holds keep the order they were placed in, a hold back to waiting is still ahead of newer ones
+	var taken []entities.Hold
+	retaken := make(map[int]bool)
+	for i := 0; i < len(waiting) && i < len(free); i++ {
+		hold, copyId := waiting[i], free[i]
+		readyAt, expiresAt := now, now.AddDate(0, 0, pickupDays)
+		hold.Status, hold.CopyId, hold.ReadyAt, hold.ExpiresAt = dtos.HoldStatusReady, &copyId, &readyAt, &expiresAt
+		statuses[copyId] = dtos.CopyStatusOnHold
+		taken = append(taken, hold)
+		retaken[hold.Id] = true
+	}
+
+	var changedHolds []entities.Hold
+	for _, hold := range released {
+		if !retaken[hold.Id] {
+			changedHolds = append(changedHolds, hold)
+		}
+	}
+	changedHolds = append(changedHolds, taken...)
+
+	var changedCopies []entities.Copy
+	for _, bookCopy := range copies {
+		if statuses[bookCopy.Id] != bookCopy.Status {
+			bookCopy.Status = statuses[bookCopy.Id]
+			changedCopies = append(changedCopies, bookCopy)
+		}
+	}
+
+	return changedHolds, changedCopies
+}
+
+// holdResponse returns the hold with its current position in the queue of the book
+func (h *holdService) holdResponse(id int) (dtos.HoldResponse, error) {
+	hold, err := h.getHold(id)
+	if err != nil {
+		return dtos.HoldResponse{}, err
+	}
+
+	holds, err := h.holdDb.GetBookHolds(hold.BookId)
+	if err != nil {
+		log.Error("Error on get holds of book from repo: ", err.Error())
+		return dtos.HoldResponse{}, err
+	}
+
+	for _, holdResponse := range toHoldsResponse(holds) {
+		if holdResponse.Id == id {
+			return holdResponse, nil
+		}
+	}
+	return toHoldResponse(hold, 0), nil
+}
+
+func (h *holdService) checkBook(bookId int) error {
+	if _, err := h.bookDb.GetBook(bookId); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return utils.ErrBookIdNotFound
+		}
+		log.Error("Error on get book from repo: ", err.Error())
+		return err
+	}
+	return nil
+}
+
+func (h *holdService) getHold(id int) (entities.Hold, error) {
+	hold, err := h.holdDb.GetHold(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return entities.Hold{}, utils.ErrHoldIdNotFound
+		}
+		log.Error("Error on get hold from repo: ", err.Error())
+		return entities.Hold{}, err
+	}
+	return hold, nil
+}
+
+// toHoldsResponse numbers the waiting holds of a queue from 1, in the order they were placed
+func toHoldsResponse(holds []entities.Hold) []dtos.HoldResponse {
+	holdsResponse := make([]dtos.HoldResponse, len(holds))
+	position := 0
+	for i, hold := range holds {
+		if hold.Status == dtos.HoldStatusWaiting {
+			position++
+			holdsResponse[i] = toHoldResponse(hold, position)
+		} else {
+			holdsResponse[i] = toHoldResponse(hold, 0)
+		}
+	}
+	return holdsResponse
+}
+
+func toHoldResponse(hold entities.Hold, position int) dtos.HoldResponse {
+	return dtos.HoldResponse{
+		Id:        hold.Id,
+		BookId:    hold.BookId,
+		PatronId:  hold.PatronId,
+		Status:    hold.Status,
+		Position:  position,
+		CopyId:    hold.CopyId,
+		PlacedAt:  hold.PlacedAt,
+		ReadyAt:   hold.ReadyAt,
+		ExpiresAt: hold.ExpiresAt,
+	}
+}
@@ -0,0 +1,341 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"github/brunojoenk/golang-test/models/dtos"
+	"github/brunojoenk/golang-test/models/entities"
+	bookrepomock "github/brunojoenk/golang-test/repository/book/mock"
+	holdrepomock "github/brunojoenk/golang-test/repository/hold/mock"
+	outboxrepomock "github/brunojoenk/golang-test/repository/outbox/mock"
+	patronrepomock "github/brunojoenk/golang-test/repository/patron/mock"
+	"github/brunojoenk/golang-test/utils"
+	"testing"
+	"time"
+
+	"github.com/go-test/deep"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+var errGeneric = errors.New("generic error")
+
+type notifierMock struct {
+	mock.Mock
+}
+
+func (m *notifierMock) NotifyHoldReady(hold entities.Hold, patron entities.Patron) error {
+	args := m.Called(hold, patron)
+	return args.Error(0)
+}
+
+func intPointer(i int) *int {
+	return &i
+}
+
+func timePointer(t time.Time) *time.Time {
+	return &t
+}
+
+func TestPlanQueue(t *testing.T) {
+	var (
+		now       = time.Date(2022, 10, 5, 9, 0, 0, 0, time.UTC)
+		pickupBy  = now.AddDate(0, 0, 3)
+		yesterday = now.AddDate(0, 0, -1)
+		readyAt   = now.AddDate(0, 0, -2)
+	)
+	waiting := func(id int) entities.Hold {
+		return entities.Hold{Id: id, BookId: 1, PatronId: id * 10, Status: dtos.HoldStatusWaiting}
+	}
+	ready := func(id, copyId int, expiresAt time.Time) entities.Hold {
+		return entities.Hold{Id: id, BookId: 1, PatronId: id * 10, Status: dtos.HoldStatusReady,
+			CopyId: intPointer(copyId), ReadyAt: timePointer(readyAt), ExpiresAt: timePointer(expiresAt)}
+	}
+	readyNow := func(id, copyId int) entities.Hold {
+		return entities.Hold{Id: id, BookId: 1, PatronId: id * 10, Status: dtos.HoldStatusReady,
+			CopyId: intPointer(copyId), ReadyAt: timePointer(now), ExpiresAt: timePointer(pickupBy)}
+	}
+	bookCopy := func(id int, status string) entities.Copy {
+		return entities.Copy{Id: id, BookId: 1, Status: status}
+	}
+
+	tests := map[string]struct {
+		holds                 []entities.Hold
+		copies                []entities.Copy
+		expectedChangedHolds  []entities.Hold
+		expectedChangedCopies []entities.Copy
+	}{
+		"no holds leaves the copies on the shelf": {
+			copies: []entities.Copy{bookCopy(5, dtos.CopyStatusAvailable)},
+		},
+		"no free copy keeps everybody waiting": {
+			holds:  []entities.Hold{waiting(1), waiting(2)},
+			copies: []entities.Copy{bookCopy(5, dtos.CopyStatusOnLoan), bookCopy(6, dtos.CopyStatusLost)},
+		},
+		"a free copy goes to the first in line only": {
+			holds:                 []entities.Hold{waiting(1), waiting(2)},
+			copies:                []entities.Copy{bookCopy(5, dtos.CopyStatusOnLoan), bookCopy(6, dtos.CopyStatusAvailable)},
+			expectedChangedHolds:  []entities.Hold{readyNow(1, 6)},
+			expectedChangedCopies: []entities.Copy{bookCopy(6, dtos.CopyStatusOnHold)},
+		},
+		"more free copies than holds": {
+			holds:                 []entities.Hold{waiting(1)},
+			copies:                []entities.Copy{bookCopy(5, dtos.CopyStatusAvailable), bookCopy(6, dtos.CopyStatusAvailable)},
+			expectedChangedHolds:  []entities.Hold{readyNow(1, 5)},
+			expectedChangedCopies: []entities.Copy{bookCopy(5, dtos.CopyStatusOnHold)},
+		},
+		"a ready hold keeps its copy until the pickup expires": {
+			holds:  []entities.Hold{ready(1, 5, pickupBy), waiting(2)},
+			copies: []entities.Copy{bookCopy(5, dtos.CopyStatusOnHold)},
+		},
+		"an expired pickup passes the copy to the next in line": {
+			holds:  []entities.Hold{ready(1, 5, yesterday), waiting(2)},
+			copies: []entities.Copy{bookCopy(5, dtos.CopyStatusOnHold)},
+			expectedChangedHolds: []entities.Hold{
+				{Id: 1, BookId: 1, PatronId: 10, Status: dtos.HoldStatusExpired, ReadyAt: timePointer(readyAt), ExpiresAt: timePointer(yesterday)},
+				readyNow(2, 5),
+			},
+		},
+		"an expired pickup with nobody waiting puts the copy back on the shelf": {
+			holds:  []entities.Hold{ready(1, 5, yesterday)},
+			copies: []entities.Copy{bookCopy(5, dtos.CopyStatusOnHold)},
+			expectedChangedHolds: []entities.Hold{
+				{Id: 1, BookId: 1, PatronId: 10, Status: dtos.HoldStatusExpired, ReadyAt: timePointer(readyAt), ExpiresAt: timePointer(yesterday)},
+			},
+			expectedChangedCopies: []entities.Copy{bookCopy(5, dtos.CopyStatusAvailable)},
+		},
+		"a copy withdrawn while on hold sends the hold back to its place in line": {
+			holds:                []entities.Hold{ready(1, 5, pickupBy), waiting(2)},
+			copies:               []entities.Copy{bookCopy(5, dtos.CopyStatusWithdrawn), bookCopy(6, dtos.CopyStatusOnLoan)},
+			expectedChangedHolds: []entities.Hold{waiting(1)},
+		},
+		"a copy lost while on hold is replaced by another free copy": {
+			holds:                 []entities.Hold{waiting(1), ready(2, 5, pickupBy), waiting(3)},
+			copies:                []entities.Copy{bookCopy(5, dtos.CopyStatusLost), bookCopy(6, dtos.CopyStatusAvailable)},
+			expectedChangedHolds:  []entities.Hold{waiting(2), readyNow(1, 6)},
+			expectedChangedCopies: []entities.Copy{bookCopy(6, dtos.CopyStatusOnHold)},
+		},
+		"a deleted copy on hold sends the hold back to waiting": {
+			holds:                []entities.Hold{{Id: 1, BookId: 1, PatronId: 10, Status: dtos.HoldStatusReady, ReadyAt: timePointer(readyAt), ExpiresAt: timePointer(pickupBy)}},
+			expectedChangedHolds: []entities.Hold{waiting(1)},
+		},
+		"a copy of a cancelled hold goes to the next in line": {
+			holds:                []entities.Hold{waiting(2)},
+			copies:               []entities.Copy{bookCopy(5, dtos.CopyStatusOnHold)},
+			expectedChangedHolds: []entities.Hold{readyNow(2, 5)},
+		},
+		"a copy of a cancelled hold with nobody waiting goes back on the shelf": {
+			copies:                []entities.Copy{bookCopy(5, dtos.CopyStatusOnHold)},
+			expectedChangedCopies: []entities.Copy{bookCopy(5, dtos.CopyStatusAvailable)},
+		},
+		"a copy made available while on hold goes again to the first in line": {
+			holds:                 []entities.Hold{ready(1, 5, pickupBy), waiting(2)},
+			copies:                []entities.Copy{bookCopy(5, dtos.CopyStatusAvailable)},
+			expectedChangedHolds:  []entities.Hold{readyNow(1, 5)},
+			expectedChangedCopies: []entities.Copy{bookCopy(5, dtos.CopyStatusOnHold)},
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			changedHolds, changedCopies := planQueue(tc.holds, tc.copies, now, 3)
+
+			require.Nil(t, deep.Equal(tc.expectedChangedHolds, changedHolds))
+			require.Nil(t, deep.Equal(tc.expectedChangedCopies, changedCopies))
+		})
+	}
+}
+
+func TestPlaceHold(t *testing.T) {
+	tests := map[string]struct {
+		expectedErrorOnGetBook     error
+		expectedErrorOnGetPatron   error
+		expectedErrorOnGetOpenHold error
+		expectedErrorOnCreate      error
+		expectedErrorResponse      error
+	}{
+		"success on place hold": {
+			expectedErrorOnGetOpenHold: gorm.ErrRecordNotFound,
+		},
+		"error occurred on place hold (book not found)": {
+			expectedErrorOnGetBook: gorm.ErrRecordNotFound,
+			expectedErrorResponse:  utils.ErrBookIdNotFound,
+		},
+		"error occurred on place hold (patron not found)": {
+			expectedErrorOnGetPatron: gorm.ErrRecordNotFound,
+			expectedErrorResponse:    utils.ErrPatronIdNotFound,
+		},
+		"error occurred on place hold (already holding the book)": {
+			expectedErrorResponse: utils.ErrHoldExists,
+		},
+		"error occurred on place hold": {
+			expectedErrorOnGetOpenHold: gorm.ErrRecordNotFound,
+			expectedErrorOnCreate:      errGeneric,
+			expectedErrorResponse:      errGeneric,
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			bookDbMock := new(bookrepomock.BookRepositoryMock)
+			bookDbMock.On("GetBook", 1).Return(entities.Book{Id: 1}, tc.expectedErrorOnGetBook)
+			patronDbMock := new(patronrepomock.PatronRepositoryMock)
+			patronDbMock.On("GetPatron", 30).Return(entities.Patron{Id: 30}, tc.expectedErrorOnGetPatron)
+			holdDbMock := new(holdrepomock.HoldRepositoryMock)
+			holdDbMock.On("GetOpenHold", 1, 30).Return(entities.Hold{Id: 2}, tc.expectedErrorOnGetOpenHold)
+			holdDbMock.On("CreateHold", mock.MatchedBy(func(hold entities.Hold) bool {
+				return hold.BookId == 1 && hold.PatronId == 30 && hold.Status == dtos.HoldStatusWaiting
+			})).Return(entities.Hold{Id: 3, BookId: 1, PatronId: 30, Status: dtos.HoldStatusWaiting}, tc.expectedErrorOnCreate)
+			queue := []entities.Hold{
+				{Id: 1, BookId: 1, PatronId: 10, Status: dtos.HoldStatusReady, CopyId: intPointer(5)},
+				{Id: 2, BookId: 1, PatronId: 20, Status: dtos.HoldStatusWaiting},
+				{Id: 3, BookId: 1, PatronId: 30, Status: dtos.HoldStatusWaiting},
+			}
+			holdDbMock.On("ProcessQueue", 1).Return(queue, []entities.Copy{{Id: 5, Status: dtos.CopyStatusOnHold}}, nil)
+			holdDbMock.On("GetHold", 3).Return(queue[2], nil)
+			holdDbMock.On("GetBookHolds", 1).Return(queue, nil)
+
+			holdServiceTest := holdService{holdDb: holdDbMock, bookDb: bookDbMock, patronDb: patronDbMock, notifier: new(notifierMock), pickupDays: 3}
+			resp, err := holdServiceTest.PlaceHold(1, dtos.HoldRequest{PatronId: 30})
+			if tc.expectedErrorResponse != nil {
+				require.ErrorIs(t, err, tc.expectedErrorResponse)
+				holdDbMock.AssertNotCalled(t, "ProcessQueue", 1)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, dtos.HoldResponse{Id: 3, BookId: 1, PatronId: 30, Status: dtos.HoldStatusWaiting, Position: 2}, resp)
+			}
+		})
+	}
+}
+
+// The copy on hold for the expired pickup goes to the next patron, who is notified
+func TestProcessQueueNotifies(t *testing.T) {
+	expired := time.Now().AddDate(0, 0, -1)
+	holdDbMock := new(holdrepomock.HoldRepositoryMock)
+	holdDbMock.On("ProcessQueue", 1).Return([]entities.Hold{
+		{Id: 1, BookId: 1, PatronId: 10, Status: dtos.HoldStatusReady, CopyId: intPointer(5), ExpiresAt: &expired},
+		{Id: 2, BookId: 1, PatronId: 20, Status: dtos.HoldStatusWaiting},
+	}, []entities.Copy{{Id: 5, BookId: 1, Status: dtos.CopyStatusOnHold}}, nil)
+	patronDbMock := new(patronrepomock.PatronRepositoryMock)
+	patronDbMock.On("GetPatron", 20).Return(entities.Patron{Id: 20, Email: "ana@example.com"}, nil)
+	notifier := new(notifierMock)
+	notifier.On("NotifyHoldReady", mock.MatchedBy(func(hold entities.Hold) bool {
+		return hold.Id == 2 && hold.Status == dtos.HoldStatusReady && *hold.CopyId == 5
+	}), entities.Patron{Id: 20, Email: "ana@example.com"}).Return(nil)
+
+	holdServiceTest := holdService{holdDb: holdDbMock, patronDb: patronDbMock, notifier: notifier, pickupDays: 3}
+	err := holdServiceTest.ProcessQueue(1)
+
+	require.NoError(t, err)
+	notifier.AssertNumberOfCalls(t, "NotifyHoldReady", 1)
+	patronDbMock.AssertNotCalled(t, "GetPatron", 10)
+}
+
+func TestNotifyHoldReady(t *testing.T) {
+	readyAt := time.Date(2022, 3, 10, 14, 0, 0, 0, time.UTC)
+	expiresAt := readyAt.AddDate(0, 0, 3)
+	bookDbMock := new(bookrepomock.BookRepositoryMock)
+	bookDbMock.On("GetBookNames", []int{1}).Return([]entities.Book{{Id: 1, Name: "Dom Casmurro"}}, nil)
+	outboxDbMock := new(outboxrepomock.OutboxRepositoryMock)
+	outboxDbMock.On("EnqueueMessage", mock.MatchedBy(func(message entities.OutboxMessage) bool {
+		return message.Kind == dtos.NotificationHoldReady &&
+			message.Recipient == "ana@example.com" &&
+			message.Subject == "Ready for pickup: Dom Casmurro" &&
+			message.DedupKey == fmt.Sprintf("hold_ready:2:%d", readyAt.Unix()) &&
+			message.Status == dtos.OutboxStatusPending
+	})).Return(true, nil)
+
+	notifier := OutboxHoldNotifier{outboxDb: outboxDbMock, bookDb: bookDbMock}
+	err := notifier.NotifyHoldReady(
+		entities.Hold{Id: 2, BookId: 1, PatronId: 20, Status: dtos.HoldStatusReady, ReadyAt: &readyAt, ExpiresAt: &expiresAt},
+		entities.Patron{Id: 20, Name: "Ana", Email: "ana@example.com"})
+
+	require.NoError(t, err)
+	outboxDbMock.AssertNumberOfCalls(t, "EnqueueMessage", 1)
+}
+
+func TestProcessQueueError(t *testing.T) {
+	holdDbMock := new(holdrepomock.HoldRepositoryMock)
+	holdDbMock.On("ProcessQueue", 1).Return(nil, nil, gorm.ErrRecordNotFound)
+
+	holdServiceTest := holdService{holdDb: holdDbMock, notifier: new(notifierMock)}
+	err := holdServiceTest.ProcessQueue(1)
+
+	require.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	holdDbMock.AssertNumberOfCalls(t, "ProcessQueue", queueAttempts)
+}
+
+func TestExpireHolds(t *testing.T) {
+	now := time.Now()
+	tests := map[string]struct {
+		expectedErrorOnGetBooks error
+		expectedErrorOnProcess  error
+		expectedErrorResponse   error
+	}{
+		"success on expire holds": {},
+		"error occurred on expire holds (get books)": {
+			expectedErrorOnGetBooks: errGeneric,
+			expectedErrorResponse:   errGeneric,
+		},
+		"error occurred on expire holds (process queue)": {
+			expectedErrorOnProcess: errGeneric,
+			expectedErrorResponse:  errGeneric,
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			expired := now.AddDate(0, 0, -1)
+			holdDbMock := new(holdrepomock.HoldRepositoryMock)
+			holdDbMock.On("GetBooksWithExpiredHolds", now).Return([]int{1, 2}, tc.expectedErrorOnGetBooks)
+			holdDbMock.On("ProcessQueue", 1).Return([]entities.Hold{
+				{Id: 1, BookId: 1, PatronId: 10, Status: dtos.HoldStatusReady, CopyId: intPointer(5), ExpiresAt: &expired},
+			}, []entities.Copy{{Id: 5, BookId: 1, Status: dtos.CopyStatusOnHold}}, tc.expectedErrorOnProcess)
+			holdDbMock.On("ProcessQueue", 2).Return([]entities.Hold{}, []entities.Copy{}, nil)
+
+			holdServiceTest := holdService{holdDb: holdDbMock, notifier: new(notifierMock), pickupDays: 3}
+			err := holdServiceTest.ExpireHolds(now)
+
+			require.ErrorIs(t, err, tc.expectedErrorResponse)
+			if tc.expectedErrorOnGetBooks == nil {
+				// A book that fails does not stop the others
+				holdDbMock.AssertCalled(t, "ProcessQueue", 2)
+			}
+		})
+	}
+}
+
+func TestCancelHold(t *testing.T) {
+	tests := map[string]struct {
+		expectedErrorOnGet    error
+		expectedErrorOnCancel error
+		expectedErrorResponse error
+	}{
+		"success on cancel hold": {},
+		"error occurred on cancel hold (not found)": {
+			expectedErrorOnGet:    gorm.ErrRecordNotFound,
+			expectedErrorResponse: utils.ErrHoldIdNotFound,
+		},
+		"error occurred on cancel hold (already closed)": {
+			expectedErrorOnCancel: gorm.ErrRecordNotFound,
+			expectedErrorResponse: utils.ErrHoldClosed,
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			holdDbMock := new(holdrepomock.HoldRepositoryMock)
+			holdDbMock.On("GetHold", 1).Return(entities.Hold{Id: 1, BookId: 1, PatronId: 10, Status: dtos.HoldStatusReady, CopyId: intPointer(5)}, tc.expectedErrorOnGet)
+			holdDbMock.On("CancelHold", 1).Return(tc.expectedErrorOnCancel)
+			holdDbMock.On("ProcessQueue", 1).Return([]entities.Hold{}, []entities.Copy{{Id: 5, BookId: 1, Status: dtos.CopyStatusOnHold}}, nil)
+
+			holdServiceTest := holdService{holdDb: holdDbMock, notifier: new(notifierMock)}
+			resp, err := holdServiceTest.CancelHold(1)
+			if tc.expectedErrorResponse != nil {
+				require.ErrorIs(t, err, tc.expectedErrorResponse)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, dtos.HoldStatusCancelled, resp.Status)
+				require.Nil(t, resp.CopyId)
+				holdDbMock.AssertCalled(t, "ProcessQueue", 1)
+			}
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package services
+
+import (
+	"github/brunojoenk/golang-test/models/dtos"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type HoldServiceMock struct {
+	mock.Mock
+}
+
+func (m *HoldServiceMock) PlaceHold(bookId int, holdRequest dtos.HoldRequest) (dtos.HoldResponse, error) {
+	args := m.Called(bookId, holdRequest)
+	return args.Get(0).(dtos.HoldResponse), args.Error(1)
+}
+
+func (m *HoldServiceMock) GetBookHolds(bookId int) (dtos.HoldsResponse, error) {
+	args := m.Called(bookId)
+	return args.Get(0).(dtos.HoldsResponse), args.Error(1)
+}
+
+func (m *HoldServiceMock) GetHold(id int) (dtos.HoldResponse, error) {
+	args := m.Called(id)
+	return args.Get(0).(dtos.HoldResponse), args.Error(1)
+}
+
+func (m *HoldServiceMock) CancelHold(id int) (dtos.HoldResponse, error) {
+	args := m.Called(id)
+	return args.Get(0).(dtos.HoldResponse), args.Error(1)
+}
+
+func (m *HoldServiceMock) ProcessQueue(bookId int) error {
+	args := m.Called(bookId)
+	return args.Error(0)
+}
+
+func (m *HoldServiceMock) ExpireHolds(now time.Time) error {
+	args := m.Called(now)
+	return args.Error(0)
+}
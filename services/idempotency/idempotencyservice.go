@@ -0,0 +1,114 @@
+package services
+
+import (
+	"github/brunojoenk/golang-test/config"
+	"github/brunojoenk/golang-test/models/dtos"
+	"github/brunojoenk/golang-test/models/entities"
+	idempotencyrepo "github/brunojoenk/golang-test/repository/idempotency"
+	"github/brunojoenk/golang-test/utils"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type IIdempotencyService interface {
+	Begin(scope, key, requestHash string) (dtos.IdempotentResponse, bool, error)
+	Complete(scope, key string, response dtos.IdempotentResponse) error
+	Release(scope, key string) error
+	DeleteExpiredKeys(now time.Time) error
+}
+
+type idempotencyService struct {
+	idempotencyDb idempotencyrepo.IIdempotencyRepository
+	ttl           time.Duration
+	lease         time.Duration
+}
+
+// NewIdempotencyService Service Constructor
+func NewIdempotencyService(db *gorm.DB, cfg *config.Config) IIdempotencyService {
+	return &idempotencyService{
+		idempotencyDb: idempotencyrepo.NewIdempotencyRepository(db),
+		ttl:           time.Duration(cfg.IdempotencyKeyTTLHours) * time.Hour,
+		lease:         time.Duration(cfg.IdempotencyLeaseMinutes) * time.Minute,
+	}
+}
+
+// Begin reserves the key for a new request. The reservation is a lease: a request that stops without
+// completing or releasing the key, e.g. on a crash, leaves it to be taken over once the lease is over.
+// When the key was already used, it returns the stored response to be replayed (second value true), or
+// an error if the request differs or is still running
+func (i *idempotencyService) Begin(scope, key, requestHash string) (dtos.IdempotentResponse, bool, error) {
+	now := time.Now()
+	reservation := entities.IdempotencyKey{
+		Scope:       scope,
+		Key:         key,
+		RequestHash: requestHash,
+		ExpiresAt:   now.Add(i.lease),
+	}
+
+	created, err := i.idempotencyDb.CreateIdempotencyKey(reservation)
+	if err != nil {
+		log.Error("Error on create idempotency key from repo: ", err.Error())
+		return dtos.IdempotentResponse{}, false, err
+	}
+	if created {
+		return dtos.IdempotentResponse{}, false, nil
+	}
+
+	takenOver, err := i.idempotencyDb.TakeOverIdempotencyKey(reservation, now)
+	if err != nil {
+		log.Error("Error on take over idempotency key from repo: ", err.Error())
+		return dtos.IdempotentResponse{}, false, err
+	}
+	if takenOver {
+		return dtos.IdempotentResponse{}, false, nil
+	}
+
+	stored, err := i.idempotencyDb.GetIdempotencyKey(scope, key)
+	if err != nil {
+		log.Error("Error on get idempotency key from repo: ", err.Error())
+		return dtos.IdempotentResponse{}, false, err
+	}
+
+	if stored.RequestHash != requestHash {
+		return dtos.IdempotentResponse{}, false, utils.ErrIdempotencyKeyReused
+	}
+
+	if stored.StatusCode == 0 {
+		return dtos.IdempotentResponse{}, false, utils.ErrIdempotencyKeyInProgress
+	}
+
+	return dtos.IdempotentResponse{
+		StatusCode:  stored.StatusCode,
+		ContentType: stored.ContentType,
+		Body:        stored.Body,
+	}, true, nil
+}
+
+// Complete stores the response of the request, replayed for the key until its TTL is over
+func (i *idempotencyService) Complete(scope, key string, response dtos.IdempotentResponse) error {
+	return i.idempotencyDb.SaveIdempotencyResponse(entities.IdempotencyKey{
+		Scope:       scope,
+		Key:         key,
+		StatusCode:  response.StatusCode,
+		ContentType: response.ContentType,
+		Body:        response.Body,
+		ExpiresAt:   time.Now().Add(i.ttl),
+	})
+}
+
+// Release frees the key so a failed request can be retried with it
+func (i *idempotencyService) Release(scope, key string) error {
+	return i.idempotencyDb.DeleteIdempotencyKey(scope, key)
+}
+
+// DeleteExpiredKeys deletes the keys expired by now. It runs periodically, a key expired but not deleted
+// yet is taken over by the next request with it
+func (i *idempotencyService) DeleteExpiredKeys(now time.Time) error {
+	if err := i.idempotencyDb.DeleteExpiredIdempotencyKeys(now); err != nil {
+		log.Error("Error on delete expired idempotency keys from repo: ", err.Error())
+		return err
+	}
+	return nil
+}
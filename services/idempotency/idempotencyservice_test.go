@@ -0,0 +1,124 @@
+package services
+
+import (
+	"errors"
+	"github/brunojoenk/golang-test/models/dtos"
+	"github/brunojoenk/golang-test/models/entities"
+	idempotencyrepomock "github/brunojoenk/golang-test/repository/idempotency/mock"
+	"github/brunojoenk/golang-test/utils"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+var errGeneric = errors.New("generic error")
+
+func TestBegin(t *testing.T) {
+	var (
+		scope = "ip-1:POST /book"
+		key   = "key"
+		hash  = "hash"
+	)
+	tests := map[string]struct {
+		created                 bool
+		takenOver               bool
+		stored                  entities.IdempotencyKey
+		expectedErrorOnCreate   error
+		expectedErrorOnTakeOver error
+		expectedErrorOnGet      error
+		expectedReplay          bool
+		expectedResponse        dtos.IdempotentResponse
+		expectedErrorResponse   error
+	}{
+		"success on begin (new key)": {
+			created: true,
+		},
+		"success on begin (expired key taken over)": {
+			takenOver: true,
+		},
+		"success on begin (replay)": {
+			stored:           entities.IdempotencyKey{RequestHash: hash, StatusCode: 201, ContentType: "application/json", Body: []byte(`{"id":1}`)},
+			expectedReplay:   true,
+			expectedResponse: dtos.IdempotentResponse{StatusCode: 201, ContentType: "application/json", Body: []byte(`{"id":1}`)},
+		},
+		"error occurred on begin (different request)": {
+			stored:                entities.IdempotencyKey{RequestHash: "other", StatusCode: 201},
+			expectedErrorResponse: utils.ErrIdempotencyKeyReused,
+		},
+		"error occurred on begin (in progress)": {
+			stored:                entities.IdempotencyKey{RequestHash: hash},
+			expectedErrorResponse: utils.ErrIdempotencyKeyInProgress,
+		},
+		"error occurred on begin (take over)": {
+			expectedErrorOnTakeOver: errGeneric,
+			expectedErrorResponse:   errGeneric,
+		},
+		"error occurred on begin (create)": {
+			expectedErrorOnCreate: errGeneric,
+			expectedErrorResponse: errGeneric,
+		},
+		"error occurred on begin (get)": {
+			expectedErrorOnGet:    errGeneric,
+			expectedErrorResponse: errGeneric,
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			idempotencyDbMock := new(idempotencyrepomock.IdempotencyRepositoryMock)
+			reservation := mock.MatchedBy(func(i entities.IdempotencyKey) bool {
+				return i.Scope == scope && i.Key == key && i.RequestHash == hash &&
+					i.ExpiresAt.After(time.Now()) && i.ExpiresAt.Before(time.Now().Add(time.Hour))
+			})
+			idempotencyDbMock.On("CreateIdempotencyKey", reservation).Return(tc.created, tc.expectedErrorOnCreate)
+			idempotencyDbMock.On("TakeOverIdempotencyKey", reservation, mock.Anything).Return(tc.takenOver, tc.expectedErrorOnTakeOver)
+			idempotencyDbMock.On("GetIdempotencyKey", scope, key).Return(tc.stored, tc.expectedErrorOnGet)
+
+			idempotencyServiceTest := idempotencyService{idempotencyDb: idempotencyDbMock, ttl: 24 * time.Hour, lease: 5 * time.Minute}
+			resp, replay, err := idempotencyServiceTest.Begin(scope, key, hash)
+			if tc.expectedErrorResponse != nil {
+				require.ErrorIs(t, err, tc.expectedErrorResponse)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tc.expectedReplay, replay)
+				require.Equal(t, tc.expectedResponse, resp)
+			}
+		})
+	}
+}
+
+func TestComplete(t *testing.T) {
+	idempotencyDbMock := new(idempotencyrepomock.IdempotencyRepositoryMock)
+	idempotencyDbMock.On("SaveIdempotencyResponse", mock.MatchedBy(func(i entities.IdempotencyKey) bool {
+		return i.Scope == "scope" && i.Key == "key" && i.StatusCode == 201 && string(i.Body) == "1" &&
+			i.ExpiresAt.After(time.Now().Add(23*time.Hour))
+	})).Return(nil)
+
+	idempotencyServiceTest := idempotencyService{idempotencyDb: idempotencyDbMock, ttl: 24 * time.Hour, lease: 5 * time.Minute}
+	err := idempotencyServiceTest.Complete("scope", "key", dtos.IdempotentResponse{StatusCode: 201, Body: []byte("1")})
+
+	require.NoError(t, err)
+	idempotencyDbMock.AssertExpectations(t)
+}
+
+func TestRelease(t *testing.T) {
+	idempotencyDbMock := new(idempotencyrepomock.IdempotencyRepositoryMock)
+	idempotencyDbMock.On("DeleteIdempotencyKey", "scope", "key").Return(errGeneric)
+
+	idempotencyServiceTest := idempotencyService{idempotencyDb: idempotencyDbMock}
+	err := idempotencyServiceTest.Release("scope", "key")
+
+	require.ErrorIs(t, err, errGeneric)
+}
+
+func TestDeleteExpiredKeys(t *testing.T) {
+	now := time.Now()
+	idempotencyDbMock := new(idempotencyrepomock.IdempotencyRepositoryMock)
+	idempotencyDbMock.On("DeleteExpiredIdempotencyKeys", now).Return(errGeneric)
+
+	idempotencyServiceTest := idempotencyService{idempotencyDb: idempotencyDbMock}
+	err := idempotencyServiceTest.DeleteExpiredKeys(now)
+
+	require.ErrorIs(t, err, errGeneric)
+}
@@ -0,0 +1,32 @@
+package services
+
+import (
+	"github/brunojoenk/golang-test/models/dtos"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type IdempotencyServiceMock struct {
+	mock.Mock
+}
+
+func (m *IdempotencyServiceMock) Begin(scope, key, requestHash string) (dtos.IdempotentResponse, bool, error) {
+	args := m.Called(scope, key, requestHash)
+	return args.Get(0).(dtos.IdempotentResponse), args.Bool(1), args.Error(2)
+}
+
+func (m *IdempotencyServiceMock) Complete(scope, key string, response dtos.IdempotentResponse) error {
+	args := m.Called(scope, key, response)
+	return args.Error(0)
+}
+
+func (m *IdempotencyServiceMock) Release(scope, key string) error {
+	args := m.Called(scope, key)
+	return args.Error(0)
+}
+
+func (m *IdempotencyServiceMock) DeleteExpiredKeys(now time.Time) error {
+	args := m.Called(now)
+	return args.Error(0)
+}
@@ -0,0 +1,234 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"github/brunojoenk/golang-test/config"
+	"github/brunojoenk/golang-test/models/dtos"
+	"github/brunojoenk/golang-test/models/entities"
+	ledgerrepo "github/brunojoenk/golang-test/repository/ledger"
+	loanrepo "github/brunojoenk/golang-test/repository/loan"
+	patronrepo "github/brunojoenk/golang-test/repository/patron"
+	"github/brunojoenk/golang-test/utils"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// assessBatchSize is how many overdue loans are read at a time when fines are assessed
+const assessBatchSize = 100
+
+// FinePolicy is what a loan costs for each day it is overdue. The first GraceDays overdue days are
+// free, and the fine of a loan never goes over CapCents, when it is greater than zero
+type FinePolicy struct {
+	PerDayCents int64
+	GraceDays   int
+	CapCents    int64
+}
+
+// Fine is the fine of a copy due on dueAt and returned, or still out, on until
+func (p FinePolicy) Fine(dueAt, until time.Time) int64 {
+	year, month, day := until.Date()
+	untilDate := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	year, month, day = dueAt.Date()
+	dueDate := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+
+	chargedDays := int(untilDate.Sub(dueDate).Hours()/24) - p.GraceDays
+	if chargedDays <= 0 {
+		return 0
+	}
+
+	fine := int64(chargedDays) * p.PerDayCents
+	if p.CapCents > 0 && fine > p.CapCents {
+		return p.CapCents
+	}
+	return fine
+}
+
+type ILedgerService interface {
+	LoanFine(loan entities.Loan, until time.Time) entities.LedgerEntry
+	ChargeFine(loan entities.Loan, until time.Time) error
+	AssessFines(now time.Time) error
+	GetPatronLedger(patronId int, pagination dtos.Pagination) (dtos.LedgerResponse, error)
+	RecordPayment(patronId int, creditRequest dtos.LedgerCreditRequest) (dtos.LedgerEntryResponse, error)
+	RecordWaiver(patronId int, creditRequest dtos.LedgerCreditRequest) (dtos.LedgerEntryResponse, error)
+}
+
+type ledgerService struct {
+	ledgerDb ledgerrepo.ILedgerRepository
+	loanDb   loanrepo.ILoanRepository
+	patronDb patronrepo.IPatronRepository
+	policy   FinePolicy
+}
+
+// NewLedgerService Service Constructor
+func NewLedgerService(db *gorm.DB, cfg *config.Config) ILedgerService {
+	return &ledgerService{
+		ledgerDb: ledgerrepo.NewLedgerRepository(db),
+		loanDb:   loanrepo.NewLoanRepository(db),
+		patronDb: patronrepo.NewPatronRepository(db),
+		policy: FinePolicy{
+			PerDayCents: int64(cfg.FinePerDayCents),
+			GraceDays:   cfg.FineGraceDays,
+			CapCents:    int64(cfg.FineCapCents),
+		},
+	}
+}
+
+// LoanFine is the fine entry of the loan for what its fine grew to by until, with a zero amount when
+// there is no fine. Check in charges it along with closing the loan
+func (l *ledgerService) LoanFine(loan entities.Loan, until time.Time) entities.LedgerEntry {
+	fine := l.policy.Fine(loan.DueAt, until)
+	if fine == 0 {
+		return entities.LedgerEntry{}
+	}
+
+	loanId := loan.Id
+	return entities.LedgerEntry{
+		PatronId:    loan.PatronId,
+		LoanId:      &loanId,
+		Kind:        dtos.LedgerKindFine,
+		AmountCents: fine,
+		Note:        fmt.Sprintf("Overdue fine for copy %s due on %s", loan.Copy.Barcode, loan.DueAt.Format(utils.DATE_LAYOUT)),
+	}
+}
+
+// ChargeFine charges the patron what the fine of the loan grew to by until. Charging again for the
+// same day charges nothing
+func (l *ledgerService) ChargeFine(loan entities.Loan, until time.Time) error {
+	fine := l.LoanFine(loan, until)
+	if fine.AmountCents == 0 {
+		return nil
+	}
+
+	charged, err := l.ledgerDb.ChargeLoanFine(loan, fine.AmountCents, fine.Note)
+	if err != nil {
+		log.Error("Error on charge fine of loan from repo: ", err.Error())
+		return err
+	}
+	if charged > 0 {
+		log.Infof("Charged %d cents to patron %d for loan %d", charged, loan.PatronId, loan.Id)
+	}
+
+	return nil
+}
+
+// AssessFines charges the fines the loans still out have grown to by now. It runs periodically, a
+// loan that fails is retried on the next run. Returned loans are charged by their check in
+func (l *ledgerService) AssessFines(now time.Time) error {
+	year, month, day := now.Date()
+	today := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+
+	var firstErr error
+	for afterId := 0; ; {
+		loans, err := l.loanDb.GetOpenLoansDueBefore(today, afterId, assessBatchSize)
+		if err != nil {
+			log.Error("Error on get overdue loans from repo: ", err.Error())
+			return err
+		}
+
+		for _, loan := range loans {
+			if err := l.ChargeFine(loan, today); err != nil && firstErr == nil {
+				firstErr = err
+			}
+		}
+
+		if len(loans) < assessBatchSize {
+			return firstErr
+		}
+		afterId = loans[len(loans)-1].Id
+	}
+}
+
+// GetPatronLedger returns the balance of the patron and a page of their ledger, newest first
+func (l *ledgerService) GetPatronLedger(patronId int, pagination dtos.Pagination) (dtos.LedgerResponse, error) {
+	if err := l.checkPatron(patronId); err != nil {
+		return dtos.LedgerResponse{}, err
+	}
+
+	balance, err := l.ledgerDb.GetBalance(patronId)
+	if err != nil {
+		log.Error("Error on get balance from repo: ", err.Error())
+		return dtos.LedgerResponse{}, err
+	}
+
+	pagination.ValidValuesAndSetDefault()
+	entries, err := l.ledgerDb.GetPatronEntries(patronId, pagination)
+	if err != nil {
+		log.Error("Error on get ledger entries from repo: ", err.Error())
+		return dtos.LedgerResponse{}, err
+	}
+
+	entriesResponse := make([]dtos.LedgerEntryResponse, len(entries))
+	for i, entry := range entries {
+		entriesResponse[i] = toLedgerEntryResponse(entry)
+	}
+
+	return dtos.LedgerResponse{
+		PatronId:     patronId,
+		BalanceCents: balance,
+		Entries:      entriesResponse,
+		Pagination:   pagination,
+	}, nil
+}
+
+// RecordPayment lowers the balance of the patron by the amount paid
+func (l *ledgerService) RecordPayment(patronId int, creditRequest dtos.LedgerCreditRequest) (dtos.LedgerEntryResponse, error) {
+	return l.credit(patronId, dtos.LedgerKindPayment, creditRequest)
+}
+
+// RecordWaiver lowers the balance of the patron by the amount forgiven
+func (l *ledgerService) RecordWaiver(patronId int, creditRequest dtos.LedgerCreditRequest) (dtos.LedgerEntryResponse, error) {
+	return l.credit(patronId, dtos.LedgerKindWaiver, creditRequest)
+}
+
+// credit saves a payment or waiver. Neither can take the balance below zero
+func (l *ledgerService) credit(patronId int, kind string, creditRequest dtos.LedgerCreditRequest) (dtos.LedgerEntryResponse, error) {
+	if creditRequest.AmountCents <= 0 {
+		return dtos.LedgerEntryResponse{}, utils.ErrInvalidAmount
+	}
+
+	if err := l.checkPatron(patronId); err != nil {
+		return dtos.LedgerEntryResponse{}, err
+	}
+
+	entry, err := l.ledgerDb.CreateCredit(entities.LedgerEntry{
+		PatronId:    patronId,
+		Kind:        kind,
+		AmountCents: -creditRequest.AmountCents,
+		Note:        strings.TrimSpace(creditRequest.Note),
+	})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return dtos.LedgerEntryResponse{}, utils.ErrAmountExceedsBalance
+		}
+		log.Error("Error on create credit from repo: ", err.Error())
+		return dtos.LedgerEntryResponse{}, err
+	}
+
+	return toLedgerEntryResponse(entry), nil
+}
+
+func (l *ledgerService) checkPatron(patronId int) error {
+	if _, err := l.patronDb.GetPatron(patronId); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return utils.ErrPatronIdNotFound
+		}
+		log.Error("Error on get patron from repo: ", err.Error())
+		return err
+	}
+	return nil
+}
+
+func toLedgerEntryResponse(entry entities.LedgerEntry) dtos.LedgerEntryResponse {
+	return dtos.LedgerEntryResponse{
+		Id:          entry.Id,
+		LoanId:      entry.LoanId,
+		Kind:        entry.Kind,
+		AmountCents: entry.AmountCents,
+		Note:        entry.Note,
+		CreatedAt:   entry.CreatedAt,
+	}
+}
@@ -0,0 +1,261 @@
+package services
+
+import (
+	"errors"
+	"github/brunojoenk/golang-test/models/dtos"
+	"github/brunojoenk/golang-test/models/entities"
+	ledgerrepomock "github/brunojoenk/golang-test/repository/ledger/mock"
+	loanrepomock "github/brunojoenk/golang-test/repository/loan/mock"
+	patronrepomock "github/brunojoenk/golang-test/repository/patron/mock"
+	"github/brunojoenk/golang-test/utils"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+var errGeneric = errors.New("generic error")
+
+var policy = FinePolicy{PerDayCents: 25, GraceDays: 2, CapCents: 1000}
+
+func TestFine(t *testing.T) {
+	dueAt := time.Date(2022, 3, 10, 0, 0, 0, 0, time.UTC)
+	tests := map[string]struct {
+		policy       FinePolicy
+		until        time.Time
+		expectedFine int64
+	}{
+		"no fine before the due date": {
+			policy: policy,
+			until:  dueAt.AddDate(0, 0, -1),
+		},
+		"no fine on the due date": {
+			policy: policy,
+			until:  dueAt.Add(23 * time.Hour),
+		},
+		"no fine within the grace days": {
+			policy: policy,
+			until:  dueAt.AddDate(0, 0, 2).Add(20 * time.Hour),
+		},
+		"fine for the days after the grace days": {
+			policy:       policy,
+			until:        dueAt.AddDate(0, 0, 5),
+			expectedFine: 75,
+		},
+		"fine capped": {
+			policy:       policy,
+			until:        dueAt.AddDate(1, 0, 0),
+			expectedFine: 1000,
+		},
+		"fine not capped": {
+			policy:       FinePolicy{PerDayCents: 25},
+			until:        dueAt.AddDate(0, 0, 100),
+			expectedFine: 2500,
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			require.Equal(t, tc.expectedFine, tc.policy.Fine(dueAt, tc.until))
+		})
+	}
+}
+
+func TestLoanFine(t *testing.T) {
+	dueAt := time.Date(2022, 3, 10, 0, 0, 0, 0, time.UTC)
+	loan := entities.Loan{Id: 9, PatronId: 3, DueAt: dueAt, Copy: entities.Copy{Barcode: "0001"}}
+	ledgerServiceTest := ledgerService{policy: policy}
+
+	loanId := 9
+	require.Equal(t, entities.LedgerEntry{
+		PatronId:    3,
+		LoanId:      &loanId,
+		Kind:        dtos.LedgerKindFine,
+		AmountCents: 50,
+		Note:        "Overdue fine for copy 0001 due on 2022-03-10",
+	}, ledgerServiceTest.LoanFine(loan, dueAt.AddDate(0, 0, 4)))
+	require.Equal(t, entities.LedgerEntry{}, ledgerServiceTest.LoanFine(loan, dueAt.AddDate(0, 0, 1)))
+}
+
+func TestChargeFine(t *testing.T) {
+	dueAt := time.Date(2022, 3, 10, 0, 0, 0, 0, time.UTC)
+	loan := entities.Loan{Id: 9, PatronId: 3, DueAt: dueAt, Copy: entities.Copy{Barcode: "0001"}}
+	tests := map[string]struct {
+		until                 time.Time
+		expectedFine          int64
+		expectedErrorOnCharge error
+		expectedErrorResponse error
+	}{
+		"success on charge fine": {
+			until:        dueAt.AddDate(0, 0, 4),
+			expectedFine: 50,
+		},
+		"success on charge fine (no fine)": {
+			until: dueAt.AddDate(0, 0, 1),
+		},
+		"error occurred on charge fine": {
+			until:                 dueAt.AddDate(0, 0, 4),
+			expectedFine:          50,
+			expectedErrorOnCharge: errGeneric,
+			expectedErrorResponse: errGeneric,
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			ledgerDbMock := new(ledgerrepomock.LedgerRepositoryMock)
+			ledgerDbMock.On("ChargeLoanFine", loan, tc.expectedFine, "Overdue fine for copy 0001 due on 2022-03-10").Return(tc.expectedFine, tc.expectedErrorOnCharge)
+
+			ledgerServiceTest := ledgerService{ledgerDb: ledgerDbMock, policy: policy}
+			err := ledgerServiceTest.ChargeFine(loan, tc.until)
+			require.ErrorIs(t, err, tc.expectedErrorResponse)
+			if tc.expectedFine == 0 {
+				ledgerDbMock.AssertNotCalled(t, "ChargeLoanFine", mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestAssessFines(t *testing.T) {
+	now := time.Date(2022, 3, 20, 15, 30, 0, 0, time.UTC)
+	today := time.Date(2022, 3, 20, 0, 0, 0, 0, time.UTC)
+	dueAt := time.Date(2022, 3, 10, 0, 0, 0, 0, time.UTC)
+
+	firstBatch := make([]entities.Loan, assessBatchSize)
+	for i := range firstBatch {
+		firstBatch[i] = entities.Loan{Id: i + 1, PatronId: 3, DueAt: dueAt}
+	}
+	lastBatch := []entities.Loan{{Id: assessBatchSize + 1, PatronId: 4, DueAt: dueAt}}
+
+	ledgerDbMock := new(ledgerrepomock.LedgerRepositoryMock)
+	ledgerDbMock.On("ChargeLoanFine", mock.MatchedBy(func(loan entities.Loan) bool { return loan.Id != 2 }), int64(200), mock.Anything).Return(int64(25), nil)
+	ledgerDbMock.On("ChargeLoanFine", mock.MatchedBy(func(loan entities.Loan) bool { return loan.Id == 2 }), int64(200), mock.Anything).Return(int64(0), errGeneric)
+	loanDbMock := new(loanrepomock.LoanRepositoryMock)
+	loanDbMock.On("GetOpenLoansDueBefore", today, 0, assessBatchSize).Return(firstBatch, nil)
+	loanDbMock.On("GetOpenLoansDueBefore", today, assessBatchSize, assessBatchSize).Return(lastBatch, nil)
+
+	ledgerServiceTest := ledgerService{ledgerDb: ledgerDbMock, loanDb: loanDbMock, policy: policy}
+	err := ledgerServiceTest.AssessFines(now)
+	require.ErrorIs(t, err, errGeneric)
+	ledgerDbMock.AssertNumberOfCalls(t, "ChargeLoanFine", assessBatchSize+1)
+}
+
+func TestGetPatronLedger(t *testing.T) {
+	createdAt := time.Now()
+	tests := map[string]struct {
+		expectedErrorOnGetPatron  error
+		expectedErrorOnGetBalance error
+		expectedErrorOnGetEntries error
+		expectedErrorResponse     error
+	}{
+		"success on get patron ledger": {},
+		"error occurred on get patron ledger (patron not found)": {
+			expectedErrorOnGetPatron: gorm.ErrRecordNotFound,
+			expectedErrorResponse:    utils.ErrPatronIdNotFound,
+		},
+		"error occurred on get patron ledger (balance)": {
+			expectedErrorOnGetBalance: errGeneric,
+			expectedErrorResponse:     errGeneric,
+		},
+		"error occurred on get patron ledger (entries)": {
+			expectedErrorOnGetEntries: errGeneric,
+			expectedErrorResponse:     errGeneric,
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			loanId := 9
+			patronDbMock := new(patronrepomock.PatronRepositoryMock)
+			patronDbMock.On("GetPatron", 3).Return(entities.Patron{Id: 3}, tc.expectedErrorOnGetPatron)
+			ledgerDbMock := new(ledgerrepomock.LedgerRepositoryMock)
+			ledgerDbMock.On("GetBalance", 3).Return(int64(50), tc.expectedErrorOnGetBalance)
+			ledgerDbMock.On("GetPatronEntries", 3, dtos.Pagination{Page: 1, Limit: 10}).Return([]entities.LedgerEntry{
+				{Id: 2, PatronId: 3, Kind: dtos.LedgerKindPayment, AmountCents: -25, CreatedAt: createdAt},
+				{Id: 1, PatronId: 3, LoanId: &loanId, Kind: dtos.LedgerKindFine, AmountCents: 75, CreatedAt: createdAt},
+			}, tc.expectedErrorOnGetEntries)
+
+			ledgerServiceTest := ledgerService{ledgerDb: ledgerDbMock, patronDb: patronDbMock}
+			resp, err := ledgerServiceTest.GetPatronLedger(3, dtos.Pagination{})
+			if tc.expectedErrorResponse != nil {
+				require.ErrorIs(t, err, tc.expectedErrorResponse)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, int64(50), resp.BalanceCents)
+			require.Equal(t, dtos.Pagination{Page: 1, Limit: 10}, resp.Pagination)
+			require.Len(t, resp.Entries, 2)
+			require.Equal(t, int64(-25), resp.Entries[0].AmountCents)
+			require.Equal(t, &loanId, resp.Entries[1].LoanId)
+		})
+	}
+}
+
+func TestRecordPayment(t *testing.T) {
+	tests := map[string]struct {
+		request                  dtos.LedgerCreditRequest
+		expectedErrorOnGetPatron error
+		expectedErrorOnCreate    error
+		expectedErrorResponse    error
+	}{
+		"success on record payment": {
+			request: dtos.LedgerCreditRequest{AmountCents: 50, Note: " cash "},
+		},
+		"error occurred on record payment (zero amount)": {
+			request:               dtos.LedgerCreditRequest{},
+			expectedErrorResponse: utils.ErrInvalidAmount,
+		},
+		"error occurred on record payment (negative amount)": {
+			request:               dtos.LedgerCreditRequest{AmountCents: -50},
+			expectedErrorResponse: utils.ErrInvalidAmount,
+		},
+		"error occurred on record payment (patron not found)": {
+			request:                  dtos.LedgerCreditRequest{AmountCents: 50, Note: "cash"},
+			expectedErrorOnGetPatron: gorm.ErrRecordNotFound,
+			expectedErrorResponse:    utils.ErrPatronIdNotFound,
+		},
+		"error occurred on record payment (more than the balance)": {
+			request:               dtos.LedgerCreditRequest{AmountCents: 50, Note: "cash"},
+			expectedErrorOnCreate: gorm.ErrRecordNotFound,
+			expectedErrorResponse: utils.ErrAmountExceedsBalance,
+		},
+		"error occurred on record payment": {
+			request:               dtos.LedgerCreditRequest{AmountCents: 50, Note: "cash"},
+			expectedErrorOnCreate: errGeneric,
+			expectedErrorResponse: errGeneric,
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			entry := entities.LedgerEntry{PatronId: 3, Kind: dtos.LedgerKindPayment, AmountCents: -50, Note: "cash"}
+			patronDbMock := new(patronrepomock.PatronRepositoryMock)
+			patronDbMock.On("GetPatron", 3).Return(entities.Patron{Id: 3}, tc.expectedErrorOnGetPatron)
+			ledgerDbMock := new(ledgerrepomock.LedgerRepositoryMock)
+			created := entry
+			created.Id = 7
+			ledgerDbMock.On("CreateCredit", entry).Return(created, tc.expectedErrorOnCreate)
+
+			ledgerServiceTest := ledgerService{ledgerDb: ledgerDbMock, patronDb: patronDbMock}
+			resp, err := ledgerServiceTest.RecordPayment(3, tc.request)
+			if tc.expectedErrorResponse != nil {
+				require.ErrorIs(t, err, tc.expectedErrorResponse)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, 7, resp.Id)
+			require.Equal(t, int64(-50), resp.AmountCents)
+		})
+	}
+}
+
+func TestRecordWaiver(t *testing.T) {
+	entry := entities.LedgerEntry{PatronId: 3, Kind: dtos.LedgerKindWaiver, AmountCents: -75, Note: "first time"}
+	patronDbMock := new(patronrepomock.PatronRepositoryMock)
+	patronDbMock.On("GetPatron", 3).Return(entities.Patron{Id: 3}, nil)
+	ledgerDbMock := new(ledgerrepomock.LedgerRepositoryMock)
+	ledgerDbMock.On("CreateCredit", entry).Return(entry, nil)
+
+	ledgerServiceTest := ledgerService{ledgerDb: ledgerDbMock, patronDb: patronDbMock}
+	resp, err := ledgerServiceTest.RecordWaiver(3, dtos.LedgerCreditRequest{AmountCents: 75, Note: "first time"})
+	require.NoError(t, err)
+	require.Equal(t, dtos.LedgerKindWaiver, resp.Kind)
+}
@@ -0,0 +1,43 @@
+package services
+
+import (
+	"github/brunojoenk/golang-test/models/dtos"
+	"github/brunojoenk/golang-test/models/entities"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type LedgerServiceMock struct {
+	mock.Mock
+}
+
+func (m *LedgerServiceMock) LoanFine(loan entities.Loan, until time.Time) entities.LedgerEntry {
+	args := m.Called(loan, until)
+	return args.Get(0).(entities.LedgerEntry)
+}
+
+func (m *LedgerServiceMock) ChargeFine(loan entities.Loan, until time.Time) error {
+	args := m.Called(loan, until)
+	return args.Error(0)
+}
+
+func (m *LedgerServiceMock) AssessFines(now time.Time) error {
+	args := m.Called(now)
+	return args.Error(0)
+}
+
+func (m *LedgerServiceMock) GetPatronLedger(patronId int, pagination dtos.Pagination) (dtos.LedgerResponse, error) {
+	args := m.Called(patronId, pagination)
+	return args.Get(0).(dtos.LedgerResponse), args.Error(1)
+}
+
+func (m *LedgerServiceMock) RecordPayment(patronId int, creditRequest dtos.LedgerCreditRequest) (dtos.LedgerEntryResponse, error) {
+	args := m.Called(patronId, creditRequest)
+	return args.Get(0).(dtos.LedgerEntryResponse), args.Error(1)
+}
+
+func (m *LedgerServiceMock) RecordWaiver(patronId int, creditRequest dtos.LedgerCreditRequest) (dtos.LedgerEntryResponse, error) {
+	args := m.Called(patronId, creditRequest)
+	return args.Get(0).(dtos.LedgerEntryResponse), args.Error(1)
+}
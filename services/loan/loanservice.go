@@ -0,0 +1,262 @@
+package services
+
+import (
+	"errors"
+	"github/brunojoenk/golang-test/config"
+	"github/brunojoenk/golang-test/models/dtos"
+	"github/brunojoenk/golang-test/models/entities"
+	copyrepo "github/brunojoenk/golang-test/repository/copy"
+	holdrepo "github/brunojoenk/golang-test/repository/hold"
+	loanrepo "github/brunojoenk/golang-test/repository/loan"
+	patronrepo "github/brunojoenk/golang-test/repository/patron"
+	holdservice "github/brunojoenk/golang-test/services/hold"
+	ledgerservice "github/brunojoenk/golang-test/services/ledger"
+	"github/brunojoenk/golang-test/utils"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type ILoanService interface {
+	CheckOut(checkOutRequest dtos.CheckOutRequest) (dtos.LoanResponse, error)
+	CheckIn(id int) (dtos.LoanResponse, error)
+	Renew(id int) (dtos.LoanResponse, error)
+	GetLoan(id int) (dtos.LoanResponse, error)
+	GetPatronLoans(patronId int, filter dtos.GetLoansFilter) (dtos.LoanResponseMetadata, error)
+}
+
+type loanService struct {
+	loanDb        loanrepo.ILoanRepository
+	copyDb        copyrepo.ICopyRepository
+	patronDb      patronrepo.IPatronRepository
+	holdDb        holdrepo.IHoldRepository
+	holdService   holdservice.IHoldService
+	ledgerService ledgerservice.ILedgerService
+	loanPeriod    int
+	renewalLimit  int
+}
+
+// NewLoanService Service Constructor
+func NewLoanService(db *gorm.DB, cfg *config.Config) ILoanService {
+	return &loanService{
+		loanDb:        loanrepo.NewLoanRepository(db),
+		copyDb:        copyrepo.NewCopyRepository(db),
+		patronDb:      patronrepo.NewPatronRepository(db),
+		holdDb:        holdrepo.NewHoldRepository(db),
+		holdService:   holdservice.NewHoldService(db, cfg),
+		ledgerService: ledgerservice.NewLedgerService(db, cfg),
+		loanPeriod:    cfg.LoanPeriodDays,
+		renewalLimit:  cfg.LoanRenewalLimit,
+	}
+}
+
+// CheckOut lends an available copy to a patron until the due date of the request, or for the loan period.
+// A copy on hold is lent only to the patron it is on hold for, fulfilling the hold
+func (l *loanService) CheckOut(checkOutRequest dtos.CheckOutRequest) (dtos.LoanResponse, error) {
+	if _, err := l.getPatron(checkOutRequest.PatronId); err != nil {
+		return dtos.LoanResponse{}, err
+	}
+
+	bookCopy, err := l.copyDb.GetCopyByBarcode(strings.TrimSpace(checkOutRequest.Barcode))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return dtos.LoanResponse{}, utils.ErrCopyBarcodeNotFound
+		}
+		log.Error("Error on get copy by barcode from repo: ", err.Error())
+		return dtos.LoanResponse{}, err
+	}
+	holdId := 0
+	switch bookCopy.Status {
+	case dtos.CopyStatusAvailable:
+	case dtos.CopyStatusOnHold:
+		// A hold whose pickup expired keeps the copy until its queue is processed, but no longer lets its patron take it
+		hold, err := l.holdDb.GetReadyHoldByCopy(bookCopy.Id, time.Now())
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			log.Error("Error on get hold of copy from repo: ", err.Error())
+			return dtos.LoanResponse{}, err
+		}
+		if err != nil || hold.PatronId != checkOutRequest.PatronId {
+			return dtos.LoanResponse{}, utils.ErrCopyOnHold
+		}
+		holdId = hold.Id
+	default:
+		return dtos.LoanResponse{}, utils.ErrCopyNotAvailable
+	}
+
+	today := today()
+	dueAt := today.AddDate(0, 0, l.loanPeriod)
+	if strings.TrimSpace(checkOutRequest.DueAt) != "" {
+		dueAt, err = utils.ParseDate(checkOutRequest.DueAt)
+		if err != nil || !dueAt.After(today) {
+			return dtos.LoanResponse{}, utils.ErrInvalidDueDate
+		}
+	}
+
+	// The copy may have been checked out since it was read, the repository only takes it while available
+	loan, err := l.loanDb.CheckOut(entities.Loan{
+		CopyId:   bookCopy.Id,
+		PatronId: checkOutRequest.PatronId,
+		LoanedAt: time.Now(),
+		DueAt:    dueAt,
+	}, holdId)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return dtos.LoanResponse{}, utils.ErrCopyNotAvailable
+		}
+		log.Error("Error on check out copy from repo: ", err.Error())
+		return dtos.LoanResponse{}, err
+	}
+
+	loan.Copy = bookCopy
+	return toLoanResponse(loan, today), nil
+}
+
+// CheckIn closes the loan, charges its fine when it is returned late and makes the copy available
+// again, for the first in the hold queue of the book if anyone is waiting
+func (l *loanService) CheckIn(id int) (dtos.LoanResponse, error) {
+	loan, err := l.getLoan(id)
+	if err != nil {
+		return dtos.LoanResponse{}, err
+	}
+	if loan.ReturnedAt != nil {
+		return dtos.LoanResponse{}, utils.ErrLoanReturned
+	}
+
+	returnedAt := time.Now()
+	loan.ReturnedAt = &returnedAt
+	// The fine is charged with the check in, fines are only assessed later on loans still out
+	if err := l.loanDb.CheckIn(loan, l.ledgerService.LoanFine(loan, returnedAt)); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return dtos.LoanResponse{}, utils.ErrLoanReturned
+		}
+		log.Error("Error on check in copy from repo: ", err.Error())
+		return dtos.LoanResponse{}, err
+	}
+
+	loan.Copy.Status = dtos.CopyStatusAvailable
+	if err := l.holdService.ProcessQueue(loan.Copy.BookId); err != nil {
+		log.Error("Error on process holds after check in: ", err.Error())
+	}
+
+	return toLoanResponse(loan, today()), nil
+}
+
+// Renew extends an open loan by the loan period, counted from the due date or from today when the
+// loan is overdue. A loan can be renewed up to the renewal limit
+func (l *loanService) Renew(id int) (dtos.LoanResponse, error) {
+	loan, err := l.getLoan(id)
+	if err != nil {
+		return dtos.LoanResponse{}, err
+	}
+	if loan.ReturnedAt != nil {
+		return dtos.LoanResponse{}, utils.ErrLoanReturned
+	}
+	if loan.Renewals >= l.renewalLimit {
+		return dtos.LoanResponse{}, utils.ErrLoanRenewalLimit
+	}
+
+	today := today()
+	from := loan.DueAt
+	if from.Before(today) {
+		from = today
+	}
+	loan.DueAt = from.AddDate(0, 0, l.loanPeriod)
+	loan.Renewals++
+
+	if err := l.loanDb.Renew(loan); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return dtos.LoanResponse{}, utils.ErrLoanChanged
+		}
+		log.Error("Error on renew loan from repo: ", err.Error())
+		return dtos.LoanResponse{}, err
+	}
+
+	return toLoanResponse(loan, today), nil
+}
+
+func (l *loanService) GetLoan(id int) (dtos.LoanResponse, error) {
+	loan, err := l.getLoan(id)
+	if err != nil {
+		return dtos.LoanResponse{}, err
+	}
+
+	return toLoanResponse(loan, today()), nil
+}
+
+// GetPatronLoans lists the loans of a patron, the active ones (overdue included) by default
+func (l *loanService) GetPatronLoans(patronId int, filter dtos.GetLoansFilter) (dtos.LoanResponseMetadata, error) {
+	switch filter.Status {
+	case "", dtos.LoanStatusActive, dtos.LoanStatusOverdue, dtos.LoanStatusReturned:
+	default:
+		return dtos.LoanResponseMetadata{}, utils.ErrInvalidLoanStatus
+	}
+
+	if _, err := l.getPatron(patronId); err != nil {
+		return dtos.LoanResponseMetadata{}, err
+	}
+
+	today := today()
+	filter.Pagination.ValidValuesAndSetDefault()
+	loans, err := l.loanDb.GetPatronLoans(patronId, filter, today)
+	if err != nil {
+		log.Error("Error on get loans of patron from repo: ", err.Error())
+		return dtos.LoanResponseMetadata{}, err
+	}
+
+	loansResponse := make([]dtos.LoanResponse, len(loans))
+	for i, loan := range loans {
+		loansResponse[i] = toLoanResponse(loan, today)
+	}
+
+	return dtos.LoanResponseMetadata{
+		Loans:      loansResponse,
+		Pagination: filter.Pagination,
+	}, nil
+}
+
+func (l *loanService) getLoan(id int) (entities.Loan, error) {
+	loan, err := l.loanDb.GetLoan(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return entities.Loan{}, utils.ErrLoanIdNotFound
+		}
+		log.Error("Error on get loan from repo: ", err.Error())
+		return entities.Loan{}, err
+	}
+	return loan, nil
+}
+
+func (l *loanService) getPatron(id int) (entities.Patron, error) {
+	patron, err := l.patronDb.GetPatron(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return entities.Patron{}, utils.ErrPatronIdNotFound
+		}
+		log.Error("Error on get patron from repo: ", err.Error())
+		return entities.Patron{}, err
+	}
+	return patron, nil
+}
+
+// today is the current date. A loan becomes overdue the day after its due date
+func today() time.Time {
+	year, month, day := time.Now().Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func toLoanResponse(loan entities.Loan, today time.Time) dtos.LoanResponse {
+	return dtos.LoanResponse{
+		Id:         loan.Id,
+		CopyId:     loan.CopyId,
+		BookId:     loan.Copy.BookId,
+		Barcode:    loan.Copy.Barcode,
+		PatronId:   loan.PatronId,
+		LoanedAt:   loan.LoanedAt,
+		DueAt:      loan.DueAt.Format(utils.DATE_LAYOUT),
+		ReturnedAt: loan.ReturnedAt,
+		Renewals:   loan.Renewals,
+		Overdue:    loan.ReturnedAt == nil && loan.DueAt.Before(today),
+	}
+}
@@ -0,0 +1,300 @@
+package services
+
+import (
+	"errors"
+	"github/brunojoenk/golang-test/models/dtos"
+	"github/brunojoenk/golang-test/models/entities"
+	copyrepomock "github/brunojoenk/golang-test/repository/copy/mock"
+	holdrepomock "github/brunojoenk/golang-test/repository/hold/mock"
+	loanrepomock "github/brunojoenk/golang-test/repository/loan/mock"
+	patronrepomock "github/brunojoenk/golang-test/repository/patron/mock"
+	holdservicemock "github/brunojoenk/golang-test/services/hold/mock"
+	ledgerservicemock "github/brunojoenk/golang-test/services/ledger/mock"
+	"github/brunojoenk/golang-test/utils"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+var errGeneric = errors.New("generic error")
+
+func TestCheckOut(t *testing.T) {
+	var (
+		available = entities.Copy{Id: 5, BookId: 1, Barcode: "0001", Status: dtos.CopyStatusAvailable}
+		onHold    = entities.Copy{Id: 5, BookId: 1, Barcode: "0001", Status: dtos.CopyStatusOnHold}
+		tomorrow  = today().AddDate(0, 0, 1)
+	)
+	tests := map[string]struct {
+		request                     dtos.CheckOutRequest
+		expectedErrorOnGetPatron    error
+		copyExpected                entities.Copy
+		expectedErrorOnGetByBarcode error
+		holdExpected                entities.Hold
+		expectedErrorOnGetHold      error
+		expectedHoldId              int
+		expectedDueAt               time.Time
+		expectedErrorOnCheckOut     error
+		expectedErrorResponse       error
+	}{
+		"success on check out for the loan period": {
+			request:       dtos.CheckOutRequest{Barcode: " 0001 ", PatronId: 3},
+			copyExpected:  available,
+			expectedDueAt: today().AddDate(0, 0, 21),
+		},
+		"success on check out with a due date": {
+			request:       dtos.CheckOutRequest{Barcode: "0001", PatronId: 3, DueAt: tomorrow.Format(utils.DATE_LAYOUT)},
+			copyExpected:  available,
+			expectedDueAt: tomorrow,
+		},
+		"success on check out of the copy on hold for the patron": {
+			request:        dtos.CheckOutRequest{Barcode: "0001", PatronId: 3},
+			copyExpected:   onHold,
+			holdExpected:   entities.Hold{Id: 4, PatronId: 3, Status: dtos.HoldStatusReady},
+			expectedHoldId: 4,
+			expectedDueAt:  today().AddDate(0, 0, 21),
+		},
+		"error occurred on check out (copy on hold for another patron)": {
+			request:               dtos.CheckOutRequest{Barcode: "0001", PatronId: 3},
+			copyExpected:          onHold,
+			holdExpected:          entities.Hold{Id: 4, PatronId: 8, Status: dtos.HoldStatusReady},
+			expectedErrorResponse: utils.ErrCopyOnHold,
+		},
+		"error occurred on check out (copy on hold for a cancelled hold)": {
+			request:                dtos.CheckOutRequest{Barcode: "0001", PatronId: 3},
+			copyExpected:           onHold,
+			expectedErrorOnGetHold: gorm.ErrRecordNotFound,
+			expectedErrorResponse:  utils.ErrCopyOnHold,
+		},
+		"error occurred on check out (hold expired meanwhile)": {
+			request:                 dtos.CheckOutRequest{Barcode: "0001", PatronId: 3},
+			copyExpected:            onHold,
+			holdExpected:            entities.Hold{Id: 4, PatronId: 3, Status: dtos.HoldStatusReady},
+			expectedHoldId:          4,
+			expectedDueAt:           today().AddDate(0, 0, 21),
+			expectedErrorOnCheckOut: gorm.ErrRecordNotFound,
+			expectedErrorResponse:   utils.ErrCopyNotAvailable,
+		},
+		"error occurred on check out (patron not found)": {
+			request:                  dtos.CheckOutRequest{Barcode: "0001", PatronId: 3},
+			expectedErrorOnGetPatron: gorm.ErrRecordNotFound,
+			expectedErrorResponse:    utils.ErrPatronIdNotFound,
+		},
+		"error occurred on check out (barcode not found)": {
+			request:                     dtos.CheckOutRequest{Barcode: "0001", PatronId: 3},
+			expectedErrorOnGetByBarcode: gorm.ErrRecordNotFound,
+			expectedErrorResponse:       utils.ErrCopyBarcodeNotFound,
+		},
+		"error occurred on check out (copy on loan)": {
+			request:               dtos.CheckOutRequest{Barcode: "0001", PatronId: 3},
+			copyExpected:          entities.Copy{Id: 5, Barcode: "0001", Status: dtos.CopyStatusOnLoan},
+			expectedErrorResponse: utils.ErrCopyNotAvailable,
+		},
+		"error occurred on check out (copy lost)": {
+			request:               dtos.CheckOutRequest{Barcode: "0001", PatronId: 3},
+			copyExpected:          entities.Copy{Id: 5, Barcode: "0001", Status: dtos.CopyStatusLost},
+			expectedErrorResponse: utils.ErrCopyNotAvailable,
+		},
+		"error occurred on check out (due date today)": {
+			request:               dtos.CheckOutRequest{Barcode: "0001", PatronId: 3, DueAt: today().Format(utils.DATE_LAYOUT)},
+			copyExpected:          available,
+			expectedErrorResponse: utils.ErrInvalidDueDate,
+		},
+		"error occurred on check out (invalid due date)": {
+			request:               dtos.CheckOutRequest{Barcode: "0001", PatronId: 3, DueAt: "next week"},
+			copyExpected:          available,
+			expectedErrorResponse: utils.ErrInvalidDueDate,
+		},
+		"error occurred on check out (copy taken meanwhile)": {
+			request:                 dtos.CheckOutRequest{Barcode: "0001", PatronId: 3},
+			copyExpected:            available,
+			expectedDueAt:           today().AddDate(0, 0, 21),
+			expectedErrorOnCheckOut: gorm.ErrRecordNotFound,
+			expectedErrorResponse:   utils.ErrCopyNotAvailable,
+		},
+		"error occurred on check out": {
+			request:                 dtos.CheckOutRequest{Barcode: "0001", PatronId: 3},
+			copyExpected:            available,
+			expectedDueAt:           today().AddDate(0, 0, 21),
+			expectedErrorOnCheckOut: errGeneric,
+			expectedErrorResponse:   errGeneric,
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			patronDbMock := new(patronrepomock.PatronRepositoryMock)
+			patronDbMock.On("GetPatron", 3).Return(entities.Patron{Id: 3}, tc.expectedErrorOnGetPatron)
+			copyDbMock := new(copyrepomock.CopyRepositoryMock)
+			copyDbMock.On("GetCopyByBarcode", "0001").Return(tc.copyExpected, tc.expectedErrorOnGetByBarcode)
+			holdDbMock := new(holdrepomock.HoldRepositoryMock)
+			holdDbMock.On("GetReadyHoldByCopy", 5, mock.AnythingOfType("time.Time")).Return(tc.holdExpected, tc.expectedErrorOnGetHold)
+			loanDbMock := new(loanrepomock.LoanRepositoryMock)
+			loanDbMock.On("CheckOut", mock.MatchedBy(func(loan entities.Loan) bool {
+				return loan.CopyId == 5 && loan.PatronId == 3 && loan.DueAt.Equal(tc.expectedDueAt)
+			}), tc.expectedHoldId).Return(entities.Loan{Id: 9, CopyId: 5, PatronId: 3, DueAt: tc.expectedDueAt}, tc.expectedErrorOnCheckOut)
+
+			loanServiceTest := loanService{loanDb: loanDbMock, copyDb: copyDbMock, patronDb: patronDbMock, holdDb: holdDbMock, loanPeriod: 21, renewalLimit: 2}
+			resp, err := loanServiceTest.CheckOut(tc.request)
+			if tc.expectedErrorResponse != nil {
+				require.ErrorIs(t, err, tc.expectedErrorResponse)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, 9, resp.Id)
+				require.Equal(t, "0001", resp.Barcode)
+				require.Equal(t, tc.expectedDueAt.Format(utils.DATE_LAYOUT), resp.DueAt)
+				require.False(t, resp.Overdue)
+			}
+		})
+	}
+}
+
+func TestCheckIn(t *testing.T) {
+	returnedAt := time.Now()
+	tests := map[string]struct {
+		loanExpected                entities.Loan
+		fineExpected                entities.LedgerEntry
+		expectedErrorOnGetLoan      error
+		expectedErrorOnCheckIn      error
+		expectedErrorOnProcessQueue error
+		expectedErrorResponse       error
+	}{
+		"success on check in": {
+			loanExpected: entities.Loan{Id: 9, CopyId: 5, Copy: entities.Copy{Id: 5, BookId: 1, Status: dtos.CopyStatusOnLoan}},
+		},
+		"success on check in (holds not processed)": {
+			loanExpected:                entities.Loan{Id: 9, CopyId: 5, Copy: entities.Copy{Id: 5, BookId: 1, Status: dtos.CopyStatusOnLoan}},
+			expectedErrorOnProcessQueue: errGeneric,
+		},
+		"success on check in with fine": {
+			loanExpected: entities.Loan{Id: 9, CopyId: 5, Copy: entities.Copy{Id: 5, BookId: 1, Status: dtos.CopyStatusOnLoan}},
+			fineExpected: entities.LedgerEntry{PatronId: 3, Kind: dtos.LedgerKindFine, AmountCents: 75},
+		},
+		"error occurred on check in (not found)": {
+			expectedErrorOnGetLoan: gorm.ErrRecordNotFound,
+			expectedErrorResponse:  utils.ErrLoanIdNotFound,
+		},
+		"error occurred on check in (returned)": {
+			loanExpected:          entities.Loan{Id: 9, CopyId: 5, ReturnedAt: &returnedAt},
+			expectedErrorResponse: utils.ErrLoanReturned,
+		},
+		"error occurred on check in (returned meanwhile)": {
+			loanExpected:           entities.Loan{Id: 9, CopyId: 5},
+			expectedErrorOnCheckIn: gorm.ErrRecordNotFound,
+			expectedErrorResponse:  utils.ErrLoanReturned,
+		},
+		"error occurred on check in": {
+			loanExpected:           entities.Loan{Id: 9, CopyId: 5},
+			expectedErrorOnCheckIn: errGeneric,
+			expectedErrorResponse:  errGeneric,
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			loanDbMock := new(loanrepomock.LoanRepositoryMock)
+			loanDbMock.On("GetLoan", 9).Return(tc.loanExpected, tc.expectedErrorOnGetLoan)
+			loanDbMock.On("CheckIn", mock.MatchedBy(func(loan entities.Loan) bool {
+				return loan.Id == 9 && loan.CopyId == 5 && loan.ReturnedAt != nil
+			}), tc.fineExpected).Return(tc.expectedErrorOnCheckIn)
+			holdServiceMock := new(holdservicemock.HoldServiceMock)
+			holdServiceMock.On("ProcessQueue", 1).Return(tc.expectedErrorOnProcessQueue)
+			ledgerServiceMock := new(ledgerservicemock.LedgerServiceMock)
+			ledgerServiceMock.On("LoanFine", mock.Anything, mock.Anything).Return(tc.fineExpected)
+
+			loanServiceTest := loanService{loanDb: loanDbMock, holdService: holdServiceMock, ledgerService: ledgerServiceMock}
+			resp, err := loanServiceTest.CheckIn(9)
+			if tc.expectedErrorResponse != nil {
+				require.ErrorIs(t, err, tc.expectedErrorResponse)
+				holdServiceMock.AssertNotCalled(t, "ProcessQueue", 1)
+			} else {
+				require.NoError(t, err)
+				holdServiceMock.AssertCalled(t, "ProcessQueue", 1)
+				loanDbMock.AssertCalled(t, "CheckIn", mock.Anything, tc.fineExpected)
+				require.NotNil(t, resp.ReturnedAt)
+				require.False(t, resp.Overdue)
+			}
+		})
+	}
+}
+
+func TestRenew(t *testing.T) {
+	var (
+		returnedAt = time.Now()
+		dueAt      = today().AddDate(0, 0, 3)
+		overdueAt  = today().AddDate(0, 0, -3)
+	)
+	tests := map[string]struct {
+		loanExpected          entities.Loan
+		expectedLoan          entities.Loan
+		expectedErrorOnRenew  error
+		expectedErrorResponse error
+	}{
+		"success on renew from the due date": {
+			loanExpected: entities.Loan{Id: 9, DueAt: dueAt, Renewals: 1},
+			expectedLoan: entities.Loan{Id: 9, DueAt: dueAt.AddDate(0, 0, 21), Renewals: 2},
+		},
+		"success on renew an overdue loan from today": {
+			loanExpected: entities.Loan{Id: 9, DueAt: overdueAt},
+			expectedLoan: entities.Loan{Id: 9, DueAt: today().AddDate(0, 0, 21), Renewals: 1},
+		},
+		"error occurred on renew (returned)": {
+			loanExpected:          entities.Loan{Id: 9, DueAt: dueAt, ReturnedAt: &returnedAt},
+			expectedErrorResponse: utils.ErrLoanReturned,
+		},
+		"error occurred on renew (limit)": {
+			loanExpected:          entities.Loan{Id: 9, DueAt: dueAt, Renewals: 2},
+			expectedErrorResponse: utils.ErrLoanRenewalLimit,
+		},
+		"error occurred on renew (changed meanwhile)": {
+			loanExpected:          entities.Loan{Id: 9, DueAt: dueAt},
+			expectedLoan:          entities.Loan{Id: 9, DueAt: dueAt.AddDate(0, 0, 21), Renewals: 1},
+			expectedErrorOnRenew:  gorm.ErrRecordNotFound,
+			expectedErrorResponse: utils.ErrLoanChanged,
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			loanDbMock := new(loanrepomock.LoanRepositoryMock)
+			loanDbMock.On("GetLoan", 9).Return(tc.loanExpected, nil)
+			loanDbMock.On("Renew", tc.expectedLoan).Return(tc.expectedErrorOnRenew)
+
+			loanServiceTest := loanService{loanDb: loanDbMock, loanPeriod: 21, renewalLimit: 2}
+			resp, err := loanServiceTest.Renew(9)
+			if tc.expectedErrorResponse != nil {
+				require.ErrorIs(t, err, tc.expectedErrorResponse)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tc.expectedLoan.DueAt.Format(utils.DATE_LAYOUT), resp.DueAt)
+				require.Equal(t, tc.expectedLoan.Renewals, resp.Renewals)
+				require.False(t, resp.Overdue)
+			}
+		})
+	}
+}
+
+func TestGetPatronLoans(t *testing.T) {
+	patronDbMock := new(patronrepomock.PatronRepositoryMock)
+	patronDbMock.On("GetPatron", 3).Return(entities.Patron{Id: 3}, nil)
+	patronDbMock.On("GetPatron", 4).Return(entities.Patron{}, gorm.ErrRecordNotFound)
+	loanDbMock := new(loanrepomock.LoanRepositoryMock)
+	loanDbMock.On("GetPatronLoans", 3, dtos.GetLoansFilter{Pagination: dtos.Pagination{Page: 1, Limit: 10}}, today()).
+		Return([]entities.Loan{
+			{Id: 9, CopyId: 5, PatronId: 3, DueAt: today().AddDate(0, 0, -1), Copy: entities.Copy{Id: 5, BookId: 1, Barcode: "0001"}},
+			{Id: 10, CopyId: 6, PatronId: 3, DueAt: today(), Copy: entities.Copy{Id: 6, BookId: 2, Barcode: "0002"}}}, nil)
+
+	loanServiceTest := loanService{loanDb: loanDbMock, patronDb: patronDbMock}
+
+	resp, err := loanServiceTest.GetPatronLoans(3, dtos.GetLoansFilter{})
+	require.NoError(t, err)
+	require.Len(t, resp.Loans, 2)
+	require.True(t, resp.Loans[0].Overdue)
+	require.False(t, resp.Loans[1].Overdue)
+	require.Equal(t, "0002", resp.Loans[1].Barcode)
+
+	_, err = loanServiceTest.GetPatronLoans(4, dtos.GetLoansFilter{})
+	require.ErrorIs(t, err, utils.ErrPatronIdNotFound)
+
+	_, err = loanServiceTest.GetPatronLoans(3, dtos.GetLoansFilter{Status: "late"})
+	require.ErrorIs(t, err, utils.ErrInvalidLoanStatus)
+}
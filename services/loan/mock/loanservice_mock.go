@@ -0,0 +1,36 @@
+package services
+
+import (
+	"github/brunojoenk/golang-test/models/dtos"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type LoanServiceMock struct {
+	mock.Mock
+}
+
+func (m *LoanServiceMock) CheckOut(checkOutRequest dtos.CheckOutRequest) (dtos.LoanResponse, error) {
+	args := m.Called(checkOutRequest)
+	return args.Get(0).(dtos.LoanResponse), args.Error(1)
+}
+
+func (m *LoanServiceMock) CheckIn(id int) (dtos.LoanResponse, error) {
+	args := m.Called(id)
+	return args.Get(0).(dtos.LoanResponse), args.Error(1)
+}
+
+func (m *LoanServiceMock) Renew(id int) (dtos.LoanResponse, error) {
+	args := m.Called(id)
+	return args.Get(0).(dtos.LoanResponse), args.Error(1)
+}
+
+func (m *LoanServiceMock) GetLoan(id int) (dtos.LoanResponse, error) {
+	args := m.Called(id)
+	return args.Get(0).(dtos.LoanResponse), args.Error(1)
+}
+
+func (m *LoanServiceMock) GetPatronLoans(patronId int, filter dtos.GetLoansFilter) (dtos.LoanResponseMetadata, error) {
+	args := m.Called(patronId, filter)
+	return args.Get(0).(dtos.LoanResponseMetadata), args.Error(1)
+}
@@ -0,0 +1,27 @@
+package services
+
+import (
+	"github/brunojoenk/golang-test/models/dtos"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type NotificationServiceMock struct {
+	mock.Mock
+}
+
+func (m *NotificationServiceMock) QueueReminders(now time.Time) error {
+	args := m.Called(now)
+	return args.Error(0)
+}
+
+func (m *NotificationServiceMock) DeliverOutbox(now time.Time) error {
+	args := m.Called(now)
+	return args.Error(0)
+}
+
+func (m *NotificationServiceMock) GetOutbox(filter dtos.GetOutboxFilter) (dtos.OutboxResponseMetadata, error) {
+	args := m.Called(filter)
+	return args.Get(0).(dtos.OutboxResponseMetadata), args.Error(1)
+}
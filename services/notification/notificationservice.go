@@ -0,0 +1,241 @@
+package services
+
+import (
+	"fmt"
+	"github/brunojoenk/golang-test/config"
+	"github/brunojoenk/golang-test/models/dtos"
+	"github/brunojoenk/golang-test/models/entities"
+	bookrepo "github/brunojoenk/golang-test/repository/book"
+	loanrepo "github/brunojoenk/golang-test/repository/loan"
+	outboxrepo "github/brunojoenk/golang-test/repository/outbox"
+	"github/brunojoenk/golang-test/utils"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+const (
+	// reminderBatchSize is how many loans are read at a time when reminders are queued
+	reminderBatchSize = 100
+	// deliveryBatchSize is how many messages are claimed at a time for delivery
+	deliveryBatchSize = 50
+	// deliveryLease is how long a claimed message is left alone by other deliveries
+	deliveryLease = 5 * time.Minute
+	// retryBackoff is the wait before the first retry of a failed delivery, doubled on each retry
+	retryBackoff = time.Minute
+	// maxRetryBackoff is the longest wait between retries, however many attempts failed
+	maxRetryBackoff = 24 * time.Hour
+	// lastErrorSize is the size of the last_error column
+	lastErrorSize = 500
+)
+
+type INotificationService interface {
+	QueueReminders(now time.Time) error
+	DeliverOutbox(now time.Time) error
+	GetOutbox(filter dtos.GetOutboxFilter) (dtos.OutboxResponseMetadata, error)
+}
+
+type notificationService struct {
+	outboxDb     outboxrepo.IOutboxRepository
+	loanDb       loanrepo.ILoanRepository
+	bookDb       bookrepo.IBookRepository
+	sender       Sender
+	reminderDays int
+	maxAttempts  int
+}
+
+// NewNotificationService Service Constructor. The sender delivers the outbox, it can be nil when
+// the service is used only to list it
+func NewNotificationService(db *gorm.DB, cfg *config.Config, sender Sender) INotificationService {
+	return &notificationService{
+		outboxDb:     outboxrepo.NewOutboxRepository(db),
+		loanDb:       loanrepo.NewLoanRepository(db),
+		bookDb:       bookrepo.NewBookRepository(db),
+		sender:       sender,
+		reminderDays: cfg.ReminderDaysBeforeDue,
+		maxAttempts:  cfg.OutboxMaxAttempts,
+	}
+}
+
+// QueueReminders queues a reminder for each open loan due within the reminder days and an overdue
+// notice for each open loan past its due date. Each is queued once per due date, so running it
+// again queues nothing new, and a renewed loan is reminded of its new due date
+func (n *notificationService) QueueReminders(now time.Time) error {
+	year, month, day := now.Date()
+	today := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	dueBefore := today.AddDate(0, 0, n.reminderDays+1)
+
+	for afterId := 0; ; {
+		loans, err := n.loanDb.GetOpenLoansDueBefore(dueBefore, afterId, reminderBatchSize)
+		if err != nil {
+			log.Error("Error on get loans to remind from repo: ", err.Error())
+			return err
+		}
+
+		bookNames, err := n.getBookNames(loans)
+		if err != nil {
+			return err
+		}
+
+		for _, loan := range loans {
+			if loan.Patron.Email == "" {
+				continue
+			}
+			message := reminderMessage(loan, bookNames[loan.Copy.BookId], today)
+			if _, err := n.outboxDb.EnqueueMessage(message); err != nil {
+				log.Error("Error on enqueue reminder from repo: ", err.Error())
+				return err
+			}
+		}
+
+		if len(loans) < reminderBatchSize {
+			return nil
+		}
+		afterId = loans[len(loans)-1].Id
+	}
+}
+
+// DeliverOutbox sends the pending messages that are due. A failed delivery is retried with an
+// exponential backoff until the max attempts, then the message is left failed
+func (n *notificationService) DeliverOutbox(now time.Time) error {
+	for {
+		messages, err := n.outboxDb.ClaimDueMessages(now, deliveryBatchSize, deliveryLease)
+		if err != nil {
+			log.Error("Error on claim outbox messages from repo: ", err.Error())
+			return err
+		}
+
+		for _, message := range messages {
+			if err := n.deliver(message, now); err != nil {
+				return err
+			}
+		}
+
+		if len(messages) < deliveryBatchSize {
+			return nil
+		}
+	}
+}
+
+func (n *notificationService) deliver(message entities.OutboxMessage, now time.Time) error {
+	sendErr := n.sender.Send(message)
+	if sendErr == nil {
+		if err := n.outboxDb.MarkSent(message.Id, now); err != nil {
+			log.Error("Error on mark outbox message sent from repo: ", err.Error())
+			return err
+		}
+		return nil
+	}
+
+	log.Errorf("Error on send outbox message %d: %s", message.Id, sendErr.Error())
+	message.Attempts++
+	message.LastError = sendErr.Error()
+	if len(message.LastError) > lastErrorSize {
+		message.LastError = message.LastError[:lastErrorSize]
+	}
+	if message.Attempts >= n.maxAttempts {
+		message.Status = dtos.OutboxStatusFailed
+	} else {
+		message.NextAttemptAt = now.Add(retryDelay(message.Attempts))
+	}
+
+	if err := n.outboxDb.MarkFailed(message); err != nil {
+		log.Error("Error on mark outbox message failed from repo: ", err.Error())
+		return err
+	}
+	return nil
+}
+
+// retryDelay is the wait after the given number of failed attempts, doubled on each one up to
+// maxRetryBackoff. Shifting retryBackoff by the attempts would overflow with a large OUTBOX_MAX_ATTEMPTS
+func retryDelay(attempts int) time.Duration {
+	delay := retryBackoff
+	for i := 1; i < attempts && delay < maxRetryBackoff; i++ {
+		delay *= 2
+	}
+	if delay > maxRetryBackoff {
+		return maxRetryBackoff
+	}
+	return delay
+}
+
+// GetOutbox lists the messages of the outbox, the newest first
+func (n *notificationService) GetOutbox(filter dtos.GetOutboxFilter) (dtos.OutboxResponseMetadata, error) {
+	switch filter.Status {
+	case "", dtos.OutboxStatusPending, dtos.OutboxStatusSent, dtos.OutboxStatusFailed:
+	default:
+		return dtos.OutboxResponseMetadata{}, utils.ErrInvalidOutboxStatus
+	}
+
+	filter.Pagination.ValidValuesAndSetDefault()
+	messages, err := n.outboxDb.GetMessages(filter)
+	if err != nil {
+		log.Error("Error on get outbox messages from repo: ", err.Error())
+		return dtos.OutboxResponseMetadata{}, err
+	}
+
+	messagesResponse := make([]dtos.OutboxMessageResponse, len(messages))
+	for i, message := range messages {
+		messagesResponse[i] = dtos.OutboxMessageResponse{
+			Id:            message.Id,
+			Kind:          message.Kind,
+			Recipient:     message.Recipient,
+			Subject:       message.Subject,
+			Status:        message.Status,
+			Attempts:      message.Attempts,
+			LastError:     message.LastError,
+			NextAttemptAt: message.NextAttemptAt,
+			CreatedAt:     message.CreatedAt,
+			SentAt:        message.SentAt,
+		}
+	}
+
+	return dtos.OutboxResponseMetadata{Messages: messagesResponse, Pagination: filter.Pagination}, nil
+}
+
+func (n *notificationService) getBookNames(loans []entities.Loan) (map[int]string, error) {
+	names := make(map[int]string)
+	if len(loans) == 0 {
+		return names, nil
+	}
+
+	ids := make([]int, 0, len(loans))
+	for _, loan := range loans {
+		ids = append(ids, loan.Copy.BookId)
+	}
+	books, err := n.bookDb.GetBookNames(ids)
+	if err != nil {
+		log.Error("Error on get book names from repo: ", err.Error())
+		return nil, err
+	}
+	for _, book := range books {
+		names[book.Id] = book.Name
+	}
+
+	return names, nil
+}
+
+func reminderMessage(loan entities.Loan, bookName string, today time.Time) entities.OutboxMessage {
+	dueAt := loan.DueAt.Format(utils.DATE_LAYOUT)
+	message := entities.OutboxMessage{
+		Recipient:     loan.Patron.Email,
+		Status:        dtos.OutboxStatusPending,
+		NextAttemptAt: today,
+	}
+
+	if loan.DueAt.Before(today) {
+		message.Kind = dtos.NotificationOverdue
+		message.Subject = fmt.Sprintf("Overdue: %s", bookName)
+		message.Body = fmt.Sprintf("Hello %s,\n\n%s (copy %s) was due on %s. Please return it, overdue books are fined.",
+			loan.Patron.Name, bookName, loan.Copy.Barcode, dueAt)
+	} else {
+		message.Kind = dtos.NotificationDueSoon
+		message.Subject = fmt.Sprintf("Due on %s: %s", dueAt, bookName)
+		message.Body = fmt.Sprintf("Hello %s,\n\n%s (copy %s) is due on %s. Return or renew it by then.",
+			loan.Patron.Name, bookName, loan.Copy.Barcode, dueAt)
+	}
+	message.DedupKey = fmt.Sprintf("%s:%d:%s", message.Kind, loan.Id, dueAt)
+
+	return message
+}
@@ -0,0 +1,195 @@
+package services
+
+import (
+	"errors"
+	"github/brunojoenk/golang-test/models/dtos"
+	"github/brunojoenk/golang-test/models/entities"
+	bookrepomock "github/brunojoenk/golang-test/repository/book/mock"
+	loanrepomock "github/brunojoenk/golang-test/repository/loan/mock"
+	outboxrepomock "github/brunojoenk/golang-test/repository/outbox/mock"
+	"github/brunojoenk/golang-test/utils"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+var errGeneric = errors.New("generic error")
+
+type senderMock struct {
+	mock.Mock
+}
+
+func (m *senderMock) Send(message entities.OutboxMessage) error {
+	args := m.Called(message)
+	return args.Error(0)
+}
+
+func TestQueueReminders(t *testing.T) {
+	now := time.Date(2022, 3, 20, 15, 30, 0, 0, time.UTC)
+	today := time.Date(2022, 3, 20, 0, 0, 0, 0, time.UTC)
+	patron := entities.Patron{Id: 3, Name: "Ana", Email: "ana@example.com"}
+	loans := []entities.Loan{
+		{Id: 1, DueAt: today.AddDate(0, 0, -3), Patron: patron, Copy: entities.Copy{BookId: 7, Barcode: "0001"}},
+		{Id: 2, DueAt: today, Patron: patron, Copy: entities.Copy{BookId: 8, Barcode: "0002"}},
+		{Id: 3, DueAt: today.AddDate(0, 0, 2), Patron: entities.Patron{Id: 4}, Copy: entities.Copy{BookId: 8, Barcode: "0003"}},
+	}
+
+	loanDbMock := new(loanrepomock.LoanRepositoryMock)
+	loanDbMock.On("GetOpenLoansDueBefore", today.AddDate(0, 0, 3), 0, reminderBatchSize).Return(loans, nil)
+	bookDbMock := new(bookrepomock.BookRepositoryMock)
+	bookDbMock.On("GetBookNames", []int{7, 8, 8}).Return([]entities.Book{{Id: 7, Name: "Dom Casmurro"}, {Id: 8, Name: "Iracema"}}, nil)
+	outboxDbMock := new(outboxrepomock.OutboxRepositoryMock)
+	outboxDbMock.On("EnqueueMessage", mock.Anything).Return(true, nil)
+
+	notificationServiceTest := notificationService{outboxDb: outboxDbMock, loanDb: loanDbMock, bookDb: bookDbMock, reminderDays: 2}
+	err := notificationServiceTest.QueueReminders(now)
+
+	require.NoError(t, err)
+	// The patron of the third loan has no email to be reminded at
+	outboxDbMock.AssertNumberOfCalls(t, "EnqueueMessage", 2)
+	overdue := outboxDbMock.Calls[0].Arguments.Get(0).(entities.OutboxMessage)
+	require.Equal(t, dtos.NotificationOverdue, overdue.Kind)
+	require.Equal(t, "overdue:1:2022-03-17", overdue.DedupKey)
+	require.Equal(t, "Overdue: Dom Casmurro", overdue.Subject)
+	require.Equal(t, "ana@example.com", overdue.Recipient)
+	dueSoon := outboxDbMock.Calls[1].Arguments.Get(0).(entities.OutboxMessage)
+	require.Equal(t, dtos.NotificationDueSoon, dueSoon.Kind)
+	require.Equal(t, "due_soon:2:2022-03-20", dueSoon.DedupKey)
+	require.Equal(t, "Due on 2022-03-20: Iracema", dueSoon.Subject)
+}
+
+func TestQueueRemindersError(t *testing.T) {
+	loanDbMock := new(loanrepomock.LoanRepositoryMock)
+	loanDbMock.On("GetOpenLoansDueBefore", mock.Anything, 0, reminderBatchSize).Return([]entities.Loan{}, errGeneric)
+
+	notificationServiceTest := notificationService{loanDb: loanDbMock}
+	err := notificationServiceTest.QueueReminders(time.Now())
+	require.ErrorIs(t, err, errGeneric)
+}
+
+func TestDeliverOutbox(t *testing.T) {
+	now := time.Date(2022, 3, 20, 15, 30, 0, 0, time.UTC)
+	tests := map[string]struct {
+		message                entities.OutboxMessage
+		expectedErrorOnSend    error
+		expectedStatus         string
+		expectedAttempts       int
+		expectedNextAttemptAt  time.Time
+		expectedErrorOnMark    error
+		expectedErrorResponse  error
+		expectedMarkedFailed   bool
+		expectedLastErrorLimit bool
+		maxAttempts            int
+	}{
+		"success on deliver outbox": {
+			message: entities.OutboxMessage{Id: 1, Status: dtos.OutboxStatusPending},
+		},
+		"success on deliver outbox (first failure retried in a minute)": {
+			message:               entities.OutboxMessage{Id: 1, Status: dtos.OutboxStatusPending},
+			expectedErrorOnSend:   errGeneric,
+			expectedMarkedFailed:  true,
+			expectedStatus:        dtos.OutboxStatusPending,
+			expectedAttempts:      1,
+			expectedNextAttemptAt: now.Add(time.Minute),
+		},
+		"success on deliver outbox (third failure retried in four minutes)": {
+			message:               entities.OutboxMessage{Id: 1, Status: dtos.OutboxStatusPending, Attempts: 2},
+			expectedErrorOnSend:   errors.New(strings.Repeat("x", 600)),
+			expectedMarkedFailed:  true,
+			expectedStatus:        dtos.OutboxStatusPending,
+			expectedAttempts:      3,
+			expectedNextAttemptAt: now.Add(4 * time.Minute),
+		},
+		"success on deliver outbox (many failures retried in a day at most)": {
+			message:               entities.OutboxMessage{Id: 1, Status: dtos.OutboxStatusPending, Attempts: 100},
+			expectedErrorOnSend:   errGeneric,
+			expectedMarkedFailed:  true,
+			expectedStatus:        dtos.OutboxStatusPending,
+			expectedAttempts:      101,
+			expectedNextAttemptAt: now.Add(maxRetryBackoff),
+			maxAttempts:           200,
+		},
+		"success on deliver outbox (last attempt failed)": {
+			message:              entities.OutboxMessage{Id: 1, Status: dtos.OutboxStatusPending, Attempts: 4},
+			expectedErrorOnSend:  errGeneric,
+			expectedMarkedFailed: true,
+			expectedStatus:       dtos.OutboxStatusFailed,
+			expectedAttempts:     5,
+		},
+		"error occurred on deliver outbox (mark sent)": {
+			message:               entities.OutboxMessage{Id: 1, Status: dtos.OutboxStatusPending},
+			expectedErrorOnMark:   errGeneric,
+			expectedErrorResponse: errGeneric,
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			outboxDbMock := new(outboxrepomock.OutboxRepositoryMock)
+			outboxDbMock.On("ClaimDueMessages", now, deliveryBatchSize, deliveryLease).Return([]entities.OutboxMessage{tc.message}, nil)
+			outboxDbMock.On("MarkSent", 1, now).Return(tc.expectedErrorOnMark)
+			outboxDbMock.On("MarkFailed", mock.Anything).Return(nil)
+			sender := new(senderMock)
+			sender.On("Send", tc.message).Return(tc.expectedErrorOnSend)
+
+			maxAttempts := 5
+			if tc.maxAttempts != 0 {
+				maxAttempts = tc.maxAttempts
+			}
+
+			notificationServiceTest := notificationService{outboxDb: outboxDbMock, sender: sender, maxAttempts: maxAttempts}
+			err := notificationServiceTest.DeliverOutbox(now)
+			require.ErrorIs(t, err, tc.expectedErrorResponse)
+			if !tc.expectedMarkedFailed {
+				outboxDbMock.AssertNotCalled(t, "MarkFailed", mock.Anything)
+				return
+			}
+			outboxDbMock.AssertNotCalled(t, "MarkSent", 1, now)
+			failed := outboxDbMock.Calls[1].Arguments.Get(0).(entities.OutboxMessage)
+			require.Equal(t, tc.expectedStatus, failed.Status)
+			require.Equal(t, tc.expectedAttempts, failed.Attempts)
+			require.LessOrEqual(t, len(failed.LastError), lastErrorSize)
+			if tc.expectedStatus == dtos.OutboxStatusPending {
+				require.Equal(t, tc.expectedNextAttemptAt, failed.NextAttemptAt)
+			}
+		})
+	}
+}
+
+func TestGetOutbox(t *testing.T) {
+	tests := map[string]struct {
+		filter                  dtos.GetOutboxFilter
+		expectedErrorOnGet      error
+		expectedErrorResponse   error
+		expectedMessagesCounter int
+	}{
+		"success on get outbox": {
+			filter:                  dtos.GetOutboxFilter{Status: dtos.OutboxStatusFailed},
+			expectedMessagesCounter: 1,
+		},
+		"error occurred on get outbox (invalid status)": {
+			filter:                dtos.GetOutboxFilter{Status: "lost"},
+			expectedErrorResponse: utils.ErrInvalidOutboxStatus,
+		},
+		"error occurred on get outbox": {
+			filter:                dtos.GetOutboxFilter{},
+			expectedErrorOnGet:    errGeneric,
+			expectedErrorResponse: errGeneric,
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			outboxDbMock := new(outboxrepomock.OutboxRepositoryMock)
+			outboxDbMock.On("GetMessages", mock.Anything).Return([]entities.OutboxMessage{
+				{Id: 1, Kind: dtos.NotificationOverdue, Status: dtos.OutboxStatusFailed, Attempts: 5, LastError: "timeout"},
+			}, tc.expectedErrorOnGet)
+
+			notificationServiceTest := notificationService{outboxDb: outboxDbMock}
+			resp, err := notificationServiceTest.GetOutbox(tc.filter)
+			require.ErrorIs(t, err, tc.expectedErrorResponse)
+			require.Len(t, resp.Messages, tc.expectedMessagesCounter)
+		})
+	}
+}
@@ -0,0 +1,122 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github/brunojoenk/golang-test/config"
+	"github/brunojoenk/golang-test/models/entities"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// mailFrom is the sender address of the messages written to the mailbox
+const mailFrom = "Library <library@localhost>"
+
+// Sender delivers a message of the outbox. A message whose delivery fails is retried later, so
+// senders should be safe to call again for the same message
+type Sender interface {
+	Send(message entities.OutboxMessage) error
+}
+
+// NewSender returns the sender picked in the configuration: log, smtp or webhook
+func NewSender(cfg *config.Config) (Sender, error) {
+	switch cfg.NotificationSender {
+	case "log":
+		return LogSender{}, nil
+	case "smtp":
+		return &MailboxSender{Path: cfg.NotificationMailboxPath}, nil
+	case "webhook":
+		if cfg.NotificationWebhookURL == "" {
+			return nil, fmt.Errorf("notification webhook URL is empty")
+		}
+		return &WebhookSender{URL: cfg.NotificationWebhookURL, Client: &http.Client{Timeout: 10 * time.Second}}, nil
+	default:
+		return nil, fmt.Errorf("unknown notification sender %q, must be log, smtp or webhook", cfg.NotificationSender)
+	}
+}
+
+// LogSender writes the messages to the log
+type LogSender struct{}
+
+func (LogSender) Send(message entities.OutboxMessage) error {
+	log.Infof("Notification %d to %s: %s", message.Id, message.Recipient, message.Subject)
+	return nil
+}
+
+// MailboxSender stands in for an SMTP server: it appends each message, as an RFC 5322 email, to the
+// mailbox file
+type MailboxSender struct {
+	Path string
+	mu   sync.Mutex
+}
+
+func (m *MailboxSender) Send(message entities.OutboxMessage) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(m.Path), 0o755); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(m.Path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	var email bytes.Buffer
+	fmt.Fprintf(&email, "From: %s\r\n", mailFrom)
+	fmt.Fprintf(&email, "To: %s\r\n", message.Recipient)
+	fmt.Fprintf(&email, "Subject: %s\r\n", message.Subject)
+	fmt.Fprintf(&email, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	fmt.Fprintf(&email, "Message-ID: <outbox-%d@localhost>\r\n", message.Id)
+	fmt.Fprintf(&email, "Content-Type: text/plain; charset=utf-8\r\n\r\n")
+	fmt.Fprintf(&email, "%s\r\n\r\n", message.Body)
+
+	_, err = file.Write(email.Bytes())
+	return err
+}
+
+// WebhookSender posts each message as JSON to the URL. Any status other than 2xx is a failure. The
+// dedup key goes along so the receiver can drop a message delivered twice
+type WebhookSender struct {
+	URL    string
+	Client *http.Client
+}
+
+type webhookPayload struct {
+	Kind      string `json:"kind"`
+	Recipient string `json:"recipient"`
+	Subject   string `json:"subject"`
+	Body      string `json:"body"`
+	DedupKey  string `json:"dedup_key"`
+}
+
+func (w *WebhookSender) Send(message entities.OutboxMessage) error {
+	payload, err := json.Marshal(webhookPayload{
+		Kind:      message.Kind,
+		Recipient: message.Recipient,
+		Subject:   message.Subject,
+		Body:      message.Body,
+		DedupKey:  message.DedupKey,
+	})
+	if err != nil {
+		return err
+	}
+
+	resp, err := w.Client.Post(w.URL, "application/json", bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook answered %s", resp.Status)
+	}
+	return nil
+}
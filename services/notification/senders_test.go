@@ -0,0 +1,74 @@
+package services
+
+import (
+	"encoding/json"
+	"github/brunojoenk/golang-test/config"
+	"github/brunojoenk/golang-test/models/entities"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewSender(t *testing.T) {
+	tests := map[string]struct {
+		cfg           config.Config
+		expectedError bool
+	}{
+		"log":                     {cfg: config.Config{NotificationSender: "log"}},
+		"smtp":                    {cfg: config.Config{NotificationSender: "smtp", NotificationMailboxPath: "mailbox.txt"}},
+		"webhook":                 {cfg: config.Config{NotificationSender: "webhook", NotificationWebhookURL: "http://localhost/hook"}},
+		"webhook without the URL": {cfg: config.Config{NotificationSender: "webhook"}, expectedError: true},
+		"unknown":                 {cfg: config.Config{NotificationSender: "pigeon"}, expectedError: true},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			sender, err := NewSender(&tc.cfg)
+			if tc.expectedError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.NotNil(t, sender)
+		})
+	}
+}
+
+func TestMailboxSender(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "mail", "mailbox.txt")
+	sender := &MailboxSender{Path: path}
+
+	require.NoError(t, sender.Send(entities.OutboxMessage{Id: 1, Recipient: "ana@example.com", Subject: "Overdue: Iracema", Body: "Hello Ana"}))
+	require.NoError(t, sender.Send(entities.OutboxMessage{Id: 2, Recipient: "rui@example.com", Subject: "Due soon", Body: "Hello Rui"}))
+
+	mailbox, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Contains(t, string(mailbox), "To: ana@example.com\r\nSubject: Overdue: Iracema\r\n")
+	require.Contains(t, string(mailbox), "To: rui@example.com\r\n")
+	require.Equal(t, 2, strings.Count(string(mailbox), "Message-ID: "))
+}
+
+func TestWebhookSender(t *testing.T) {
+	var received webhookPayload
+	status := http.StatusNoContent
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	sender := &WebhookSender{URL: server.URL, Client: server.Client()}
+	message := entities.OutboxMessage{Id: 1, Kind: "overdue", Recipient: "ana@example.com", Subject: "Overdue", Body: "Hello", DedupKey: "overdue:1:2022-03-17"}
+
+	require.NoError(t, sender.Send(message))
+	require.Equal(t, "overdue:1:2022-03-17", received.DedupKey)
+	require.Equal(t, "ana@example.com", received.Recipient)
+
+	status = http.StatusBadGateway
+	require.Error(t, sender.Send(message))
+}
@@ -0,0 +1,36 @@
+package services
+
+import (
+	"github/brunojoenk/golang-test/models/dtos"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type PatronServiceMock struct {
+	mock.Mock
+}
+
+func (m *PatronServiceMock) CreatePatron(patronRequest dtos.PatronRequest) (dtos.PatronResponse, error) {
+	args := m.Called(patronRequest)
+	return args.Get(0).(dtos.PatronResponse), args.Error(1)
+}
+
+func (m *PatronServiceMock) GetAllPatrons(filter dtos.GetPatronsFilter) (dtos.PatronResponseMetadata, error) {
+	args := m.Called(filter)
+	return args.Get(0).(dtos.PatronResponseMetadata), args.Error(1)
+}
+
+func (m *PatronServiceMock) GetPatron(id int) (dtos.PatronResponse, error) {
+	args := m.Called(id)
+	return args.Get(0).(dtos.PatronResponse), args.Error(1)
+}
+
+func (m *PatronServiceMock) UpdatePatron(id int, patronRequest dtos.PatronRequest) (dtos.PatronResponse, error) {
+	args := m.Called(id, patronRequest)
+	return args.Get(0).(dtos.PatronResponse), args.Error(1)
+}
+
+func (m *PatronServiceMock) DeletePatron(id int) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
@@ -0,0 +1,165 @@
+package services
+
+import (
+	"errors"
+	"github/brunojoenk/golang-test/models/dtos"
+	"github/brunojoenk/golang-test/models/entities"
+	patronrepo "github/brunojoenk/golang-test/repository/patron"
+	"github/brunojoenk/golang-test/utils"
+	"net/mail"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type IPatronService interface {
+	CreatePatron(patronRequest dtos.PatronRequest) (dtos.PatronResponse, error)
+	GetAllPatrons(filter dtos.GetPatronsFilter) (dtos.PatronResponseMetadata, error)
+	GetPatron(id int) (dtos.PatronResponse, error)
+	UpdatePatron(id int, patronRequest dtos.PatronRequest) (dtos.PatronResponse, error)
+	DeletePatron(id int) error
+}
+
+type patronService struct {
+	patronDb patronrepo.IPatronRepository
+}
+
+// NewPatronService Service Constructor
+func NewPatronService(db *gorm.DB) IPatronService {
+	return &patronService{
+		patronDb: patronrepo.NewPatronRepository(db),
+	}
+}
+
+func (p *patronService) CreatePatron(patronRequest dtos.PatronRequest) (dtos.PatronResponse, error) {
+	var patron entities.Patron
+	if err := p.setFields(&patron, patronRequest); err != nil {
+		return dtos.PatronResponse{}, err
+	}
+
+	patron, err := p.patronDb.CreatePatron(patron)
+	if err != nil {
+		log.Error("Error on create patron from repo: ", err.Error())
+		return dtos.PatronResponse{}, err
+	}
+
+	return toPatronResponse(patron), nil
+}
+
+func (p *patronService) GetAllPatrons(filter dtos.GetPatronsFilter) (dtos.PatronResponseMetadata, error) {
+
+	filter.Pagination.ValidValuesAndSetDefault()
+	patrons, err := p.patronDb.GetAllPatrons(filter)
+	if err != nil {
+		log.Error("Error on get all patrons from repo: ", err.Error())
+		return dtos.PatronResponseMetadata{}, err
+	}
+
+	patronsResponse := make([]dtos.PatronResponse, len(patrons))
+	for i, patron := range patrons {
+		patronsResponse[i] = toPatronResponse(patron)
+	}
+
+	return dtos.PatronResponseMetadata{
+		Patrons:    patronsResponse,
+		Pagination: filter.Pagination,
+	}, nil
+}
+
+func (p *patronService) GetPatron(id int) (dtos.PatronResponse, error) {
+	patron, err := p.getPatron(id)
+	if err != nil {
+		return dtos.PatronResponse{}, err
+	}
+
+	return toPatronResponse(patron), nil
+}
+
+func (p *patronService) UpdatePatron(id int, patronRequest dtos.PatronRequest) (dtos.PatronResponse, error) {
+	patron, err := p.getPatron(id)
+	if err != nil {
+		return dtos.PatronResponse{}, err
+	}
+
+	if err := p.setFields(&patron, patronRequest); err != nil {
+		return dtos.PatronResponse{}, err
+	}
+
+	patron, err = p.patronDb.UpdatePatron(patron)
+	if err != nil {
+		log.Error("Error on update patron from repo: ", err.Error())
+		return dtos.PatronResponse{}, err
+	}
+
+	return toPatronResponse(patron), nil
+}
+
+// DeletePatron deletes a patron without copies on loan, so no copy is left on loan to nobody
+func (p *patronService) DeletePatron(id int) error {
+	if _, err := p.getPatron(id); err != nil {
+		return err
+	}
+
+	loans, err := p.patronDb.CountOpenLoans(id)
+	if err != nil {
+		log.Error("Error on count open loans of patron from repo: ", err.Error())
+		return err
+	}
+	if loans > 0 {
+		return utils.ErrPatronHasLoans
+	}
+
+	if err := p.patronDb.DeletePatron(id); err != nil {
+		log.Error("Error on delete patron from repo: ", err.Error())
+		return err
+	}
+
+	return nil
+}
+
+// setFields validates the request and copies it to the patron. The email must be unique ignoring case
+func (p *patronService) setFields(patron *entities.Patron, patronRequest dtos.PatronRequest) error {
+	name := strings.TrimSpace(patronRequest.Name)
+	if name == "" {
+		return utils.ErrPatronNameEmpty
+	}
+
+	email := strings.ToLower(strings.TrimSpace(patronRequest.Email))
+	if address, err := mail.ParseAddress(email); err != nil || address.Address != email {
+		return utils.ErrInvalidEmail
+	}
+
+	existing, err := p.patronDb.GetPatronByEmail(email)
+	if err == nil && existing.Id != patron.Id {
+		return utils.ErrPatronEmailExists
+	}
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		log.Error("Error on get patron by email from repo: ", err.Error())
+		return err
+	}
+
+	patron.Name = name
+	patron.Email = email
+	return nil
+}
+
+func (p *patronService) getPatron(id int) (entities.Patron, error) {
+	patron, err := p.patronDb.GetPatron(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return entities.Patron{}, utils.ErrPatronIdNotFound
+		}
+		log.Error("Error on get patron from repo: ", err.Error())
+		return entities.Patron{}, err
+	}
+	return patron, nil
+}
+
+func toPatronResponse(patron entities.Patron) dtos.PatronResponse {
+	return dtos.PatronResponse{
+		Id:    patron.Id,
+		Name:  patron.Name,
+		Email: patron.Email,
+	}
+}
@@ -0,0 +1,141 @@
+package services
+
+import (
+	"errors"
+	"github/brunojoenk/golang-test/models/dtos"
+	"github/brunojoenk/golang-test/models/entities"
+	patronrepomock "github/brunojoenk/golang-test/repository/patron/mock"
+	"github/brunojoenk/golang-test/utils"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+var errGeneric = errors.New("generic error")
+
+func TestCreatePatron(t *testing.T) {
+	tests := map[string]struct {
+		request                  dtos.PatronRequest
+		existing                 entities.Patron
+		expectedErrorOnGetByMail error
+		expectedPatron           entities.Patron
+		expectedErrorOnCreate    error
+		expectedErrorResponse    error
+	}{
+		"success on create patron": {
+			request:                  dtos.PatronRequest{Name: " Ana Lima ", Email: " Ana@Example.com "},
+			expectedErrorOnGetByMail: gorm.ErrRecordNotFound,
+			expectedPatron:           entities.Patron{Name: "Ana Lima", Email: "ana@example.com"},
+		},
+		"error occurred on create patron (empty name)": {
+			request:               dtos.PatronRequest{Name: " ", Email: "ana@example.com"},
+			expectedErrorResponse: utils.ErrPatronNameEmpty,
+		},
+		"error occurred on create patron (invalid email)": {
+			request:               dtos.PatronRequest{Name: "Ana Lima", Email: "Ana <ana@example.com>"},
+			expectedErrorResponse: utils.ErrInvalidEmail,
+		},
+		"error occurred on create patron (email exists)": {
+			request:               dtos.PatronRequest{Name: "Ana Lima", Email: "ana@example.com"},
+			existing:              entities.Patron{Id: 4},
+			expectedErrorResponse: utils.ErrPatronEmailExists,
+		},
+		"error occurred on create patron (get by email)": {
+			request:                  dtos.PatronRequest{Name: "Ana Lima", Email: "ana@example.com"},
+			expectedErrorOnGetByMail: errGeneric,
+			expectedErrorResponse:    errGeneric,
+		},
+		"error occurred on create patron (create)": {
+			request:                  dtos.PatronRequest{Name: "Ana Lima", Email: "ana@example.com"},
+			expectedErrorOnGetByMail: gorm.ErrRecordNotFound,
+			expectedPatron:           entities.Patron{Name: "Ana Lima", Email: "ana@example.com"},
+			expectedErrorOnCreate:    errGeneric,
+			expectedErrorResponse:    errGeneric,
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			patronDbMock := new(patronrepomock.PatronRepositoryMock)
+			patronDbMock.On("GetPatronByEmail", "ana@example.com").Return(tc.existing, tc.expectedErrorOnGetByMail)
+			created := tc.expectedPatron
+			created.Id = 3
+			patronDbMock.On("CreatePatron", tc.expectedPatron).Return(created, tc.expectedErrorOnCreate)
+
+			patronServiceTest := patronService{patronDb: patronDbMock}
+			resp, err := patronServiceTest.CreatePatron(tc.request)
+			if tc.expectedErrorResponse != nil {
+				require.ErrorIs(t, err, tc.expectedErrorResponse)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, dtos.PatronResponse{Id: 3, Name: "Ana Lima", Email: "ana@example.com"}, resp)
+			}
+		})
+	}
+}
+
+func TestGetAllPatrons(t *testing.T) {
+	patronDbMock := new(patronrepomock.PatronRepositoryMock)
+	patronDbMock.On("GetAllPatrons", dtos.GetPatronsFilter{Name: "ana", Pagination: dtos.Pagination{Page: 1, Limit: 10}}).
+		Return([]entities.Patron{{Id: 3, Name: "Ana Lima", Email: "ana@example.com"}}, nil)
+
+	patronServiceTest := patronService{patronDb: patronDbMock}
+	resp, err := patronServiceTest.GetAllPatrons(dtos.GetPatronsFilter{Name: "ana"})
+
+	require.NoError(t, err)
+	require.Equal(t, []dtos.PatronResponse{{Id: 3, Name: "Ana Lima", Email: "ana@example.com"}}, resp.Patrons)
+}
+
+func TestUpdatePatronKeepsItsOwnEmail(t *testing.T) {
+	patron := entities.Patron{Id: 3, Name: "Ana Lima", Email: "ana@example.com"}
+	patronDbMock := new(patronrepomock.PatronRepositoryMock)
+	patronDbMock.On("GetPatron", 3).Return(patron, nil)
+	patronDbMock.On("GetPatronByEmail", "ana@example.com").Return(patron, nil)
+	patronDbMock.On("UpdatePatron", entities.Patron{Id: 3, Name: "Ana Souza Lima", Email: "ana@example.com"}).
+		Return(entities.Patron{Id: 3, Name: "Ana Souza Lima", Email: "ana@example.com"}, nil)
+
+	patronServiceTest := patronService{patronDb: patronDbMock}
+	resp, err := patronServiceTest.UpdatePatron(3, dtos.PatronRequest{Name: "Ana Souza Lima", Email: "ana@example.com"})
+
+	require.NoError(t, err)
+	require.Equal(t, "Ana Souza Lima", resp.Name)
+}
+
+func TestDeletePatron(t *testing.T) {
+	tests := map[string]struct {
+		expectedErrorOnGet    error
+		openLoans             int64
+		expectedErrorOnDelete error
+		expectedErrorResponse error
+	}{
+		"success on delete patron": {},
+		"error occurred on delete patron (not found)": {
+			expectedErrorOnGet:    gorm.ErrRecordNotFound,
+			expectedErrorResponse: utils.ErrPatronIdNotFound,
+		},
+		"error occurred on delete patron (copies on loan)": {
+			openLoans:             1,
+			expectedErrorResponse: utils.ErrPatronHasLoans,
+		},
+		"error occurred on delete patron (delete)": {
+			expectedErrorOnDelete: errGeneric,
+			expectedErrorResponse: errGeneric,
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			patronDbMock := new(patronrepomock.PatronRepositoryMock)
+			patronDbMock.On("GetPatron", 3).Return(entities.Patron{Id: 3}, tc.expectedErrorOnGet)
+			patronDbMock.On("CountOpenLoans", 3).Return(tc.openLoans, nil)
+			patronDbMock.On("DeletePatron", 3).Return(tc.expectedErrorOnDelete)
+
+			patronServiceTest := patronService{patronDb: patronDbMock}
+			err := patronServiceTest.DeletePatron(3)
+			if tc.expectedErrorResponse != nil {
+				require.ErrorIs(t, err, tc.expectedErrorResponse)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,41 @@
+package services
+
+import (
+	"github/brunojoenk/golang-test/models/dtos"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type PublisherServiceMock struct {
+	mock.Mock
+}
+
+func (m *PublisherServiceMock) CreatePublisher(publisherRequest dtos.PublisherRequest) (dtos.PublisherResponse, error) {
+	args := m.Called(publisherRequest)
+	return args.Get(0).(dtos.PublisherResponse), args.Error(1)
+}
+
+func (m *PublisherServiceMock) GetAllPublishers(filter dtos.GetPublishersFilter) (dtos.PublisherResponseMetadata, error) {
+	args := m.Called(filter)
+	return args.Get(0).(dtos.PublisherResponseMetadata), args.Error(1)
+}
+
+func (m *PublisherServiceMock) GetPublisher(id int) (dtos.PublisherResponse, error) {
+	args := m.Called(id)
+	return args.Get(0).(dtos.PublisherResponse), args.Error(1)
+}
+
+func (m *PublisherServiceMock) UpdatePublisher(id int, publisherRequest dtos.PublisherRequest) (dtos.PublisherResponse, error) {
+	args := m.Called(id, publisherRequest)
+	return args.Get(0).(dtos.PublisherResponse), args.Error(1)
+}
+
+func (m *PublisherServiceMock) DeletePublisher(id int) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *PublisherServiceMock) GetPublisherBooks(id int, pagination dtos.Pagination) (dtos.BookResponseMetadata, error) {
+	args := m.Called(id, pagination)
+	return args.Get(0).(dtos.BookResponseMetadata), args.Error(1)
+}
@@ -0,0 +1,214 @@
+package services
+
+import (
+	"errors"
+	"github/brunojoenk/golang-test/models/dtos"
+	"github/brunojoenk/golang-test/models/entities"
+	bookrepo "github/brunojoenk/golang-test/repository/book"
+	publisherrepo "github/brunojoenk/golang-test/repository/publisher"
+	bookservice "github/brunojoenk/golang-test/services/book"
+	"github/brunojoenk/golang-test/utils"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type IPublisherService interface {
+	CreatePublisher(publisherRequest dtos.PublisherRequest) (dtos.PublisherResponse, error)
+	GetAllPublishers(filter dtos.GetPublishersFilter) (dtos.PublisherResponseMetadata, error)
+	GetPublisher(id int) (dtos.PublisherResponse, error)
+	UpdatePublisher(id int, publisherRequest dtos.PublisherRequest) (dtos.PublisherResponse, error)
+	DeletePublisher(id int) error
+	GetPublisherBooks(id int, pagination dtos.Pagination) (dtos.BookResponseMetadata, error)
+}
+
+type publisherService struct {
+	publisherDb publisherrepo.IPublisherRepository
+	bookDb      bookrepo.IBookRepository
+}
+
+// NewPublisherService Service Constructor
+func NewPublisherService(db *gorm.DB) IPublisherService {
+	return &publisherService{
+		publisherDb: publisherrepo.NewPublisherRepository(db),
+		bookDb:      bookrepo.NewBookRepository(db),
+	}
+}
+
+func (p *publisherService) CreatePublisher(publisherRequest dtos.PublisherRequest) (dtos.PublisherResponse, error) {
+	var publisher entities.Publisher
+	if err := p.setFields(&publisher, publisherRequest); err != nil {
+		return dtos.PublisherResponse{}, err
+	}
+
+	publisher, err := p.publisherDb.CreatePublisher(publisher)
+	if err != nil {
+		log.Error("Error on create publisher from repo: ", err.Error())
+		return dtos.PublisherResponse{}, err
+	}
+
+	return toPublisherResponse(publisher), nil
+}
+
+func (p *publisherService) GetAllPublishers(filter dtos.GetPublishersFilter) (dtos.PublisherResponseMetadata, error) {
+
+	filter.Pagination.ValidValuesAndSetDefault()
+	if strings.TrimSpace(filter.Country) != "" {
+		country, err := utils.NormalizeCountryCode(filter.Country)
+		if err != nil {
+			return dtos.PublisherResponseMetadata{}, err
+		}
+		filter.Country = country
+	}
+
+	publishers, err := p.publisherDb.GetAllPublishers(filter)
+	if err != nil {
+		log.Error("Error on get all publishers from repo: ", err.Error())
+		return dtos.PublisherResponseMetadata{}, err
+	}
+
+	publishersResponse := make([]dtos.PublisherResponse, len(publishers))
+	for i, publisher := range publishers {
+		publishersResponse[i] = toPublisherResponse(publisher)
+	}
+
+	return dtos.PublisherResponseMetadata{
+		Publishers: publishersResponse,
+		Pagination: filter.Pagination,
+	}, nil
+}
+
+func (p *publisherService) GetPublisher(id int) (dtos.PublisherResponse, error) {
+	publisher, err := p.getPublisher(id)
+	if err != nil {
+		return dtos.PublisherResponse{}, err
+	}
+
+	return toPublisherResponse(publisher), nil
+}
+
+func (p *publisherService) UpdatePublisher(id int, publisherRequest dtos.PublisherRequest) (dtos.PublisherResponse, error) {
+	publisher, err := p.getPublisher(id)
+	if err != nil {
+		return dtos.PublisherResponse{}, err
+	}
+
+	if err := p.setFields(&publisher, publisherRequest); err != nil {
+		return dtos.PublisherResponse{}, err
+	}
+
+	publisher, err = p.publisherDb.UpdatePublisher(publisher)
+	if err != nil {
+		log.Error("Error on update publisher from repo: ", err.Error())
+		return dtos.PublisherResponse{}, err
+	}
+
+	return toPublisherResponse(publisher), nil
+}
+
+// DeletePublisher deletes a publisher without books, so no book loses its publisher silently
+func (p *publisherService) DeletePublisher(id int) error {
+	if _, err := p.getPublisher(id); err != nil {
+		return err
+	}
+
+	books, err := p.publisherDb.CountBooks(id)
+	if err != nil {
+		log.Error("Error on count books of publisher from repo: ", err.Error())
+		return err
+	}
+	if books > 0 {
+		return utils.ErrPublisherHasBooks
+	}
+
+	if err := p.publisherDb.DeletePublisher(id); err != nil {
+		log.Error("Error on delete publisher from repo: ", err.Error())
+		return err
+	}
+
+	return nil
+}
+
+func (p *publisherService) GetPublisherBooks(id int, pagination dtos.Pagination) (dtos.BookResponseMetadata, error) {
+	if _, err := p.getPublisher(id); err != nil {
+		return dtos.BookResponseMetadata{}, err
+	}
+
+	pagination.ValidValuesAndSetDefault()
+	books, err := p.bookDb.GetAllBooks(dtos.GetBooksFilter{PublisherId: id, Pagination: pagination})
+	if err != nil {
+		log.Error("Error on get books of publisher from repo: ", err.Error())
+		return dtos.BookResponseMetadata{}, err
+	}
+
+	booksResponse := make([]dtos.BookResponse, len(books))
+	for i, book := range books {
+		booksResponse[i] = bookservice.ToBookResponse(book)
+	}
+
+	return dtos.BookResponseMetadata{
+		Books:      booksResponse,
+		Pagination: pagination,
+	}, nil
+}
+
+// setFields validates the request and copies it to the publisher. The name must be unique
+// ignoring case, accents and punctuation
+func (p *publisherService) setFields(publisher *entities.Publisher, publisherRequest dtos.PublisherRequest) error {
+	name := strings.TrimSpace(publisherRequest.Name)
+	normalizedName := utils.NormalizeName(name)
+	if normalizedName == "" {
+		return utils.ErrPublisherNameEmpty
+	}
+
+	existing, err := p.publisherDb.GetPublisherByNormalizedName(normalizedName)
+	if err == nil && existing.Id != publisher.Id {
+		return utils.ErrPublisherNameExists
+	}
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		log.Error("Error on get publisher by name from repo: ", err.Error())
+		return err
+	}
+
+	country := ""
+	if strings.TrimSpace(publisherRequest.Country) != "" {
+		if country, err = utils.NormalizeCountryCode(publisherRequest.Country); err != nil {
+			return err
+		}
+	}
+
+	website := ""
+	if strings.TrimSpace(publisherRequest.Website) != "" {
+		if website, err = utils.NormalizeWebsite(publisherRequest.Website); err != nil {
+			return err
+		}
+	}
+
+	publisher.Name = name
+	publisher.NormalizedName = normalizedName
+	publisher.Country = country
+	publisher.Website = website
+	return nil
+}
+
+func (p *publisherService) getPublisher(id int) (entities.Publisher, error) {
+	publisher, err := p.publisherDb.GetPublisher(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return entities.Publisher{}, utils.ErrPublisherIdNotFound
+		}
+		log.Error("Error on get publisher from repo: ", err.Error())
+		return entities.Publisher{}, err
+	}
+	return publisher, nil
+}
+
+func toPublisherResponse(publisher entities.Publisher) dtos.PublisherResponse {
+	return dtos.PublisherResponse{
+		Id:      publisher.Id,
+		Name:    publisher.Name,
+		Country: publisher.Country,
+		Website: publisher.Website,
+	}
+}
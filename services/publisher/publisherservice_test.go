@@ -0,0 +1,229 @@
+package services
+
+import (
+	"errors"
+	"github/brunojoenk/golang-test/models/dtos"
+	"github/brunojoenk/golang-test/models/entities"
+	bookrepomock "github/brunojoenk/golang-test/repository/book/mock"
+	publisherrepomock "github/brunojoenk/golang-test/repository/publisher/mock"
+	"github/brunojoenk/golang-test/utils"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+var errGeneric = errors.New("generic error")
+
+func TestCreatePublisher(t *testing.T) {
+	tests := map[string]struct {
+		request                  dtos.PublisherRequest
+		existing                 entities.Publisher
+		expectedErrorOnGetByName error
+		expectedPublisher        entities.Publisher
+		expectedErrorOnCreate    error
+		expectedErrorResponse    error
+	}{
+		"success on create publisher": {
+			request:                  dtos.PublisherRequest{Name: " Allen & Unwin ", Country: "gb", Website: "https://www.allenandunwin.com"},
+			expectedErrorOnGetByName: gorm.ErrRecordNotFound,
+			expectedPublisher:        entities.Publisher{Name: "Allen & Unwin", NormalizedName: "allen unwin", Country: "GB", Website: "https://www.allenandunwin.com"},
+		},
+		"error occurred on create publisher (empty name)": {
+			request:               dtos.PublisherRequest{Name: " & "},
+			expectedErrorResponse: utils.ErrPublisherNameEmpty,
+		},
+		"error occurred on create publisher (name exists)": {
+			request:               dtos.PublisherRequest{Name: "allen and unwin"},
+			existing:              entities.Publisher{Id: 3},
+			expectedErrorResponse: utils.ErrPublisherNameExists,
+		},
+		"error occurred on create publisher (get by name)": {
+			request:                  dtos.PublisherRequest{Name: "Allen & Unwin"},
+			expectedErrorOnGetByName: errGeneric,
+			expectedErrorResponse:    errGeneric,
+		},
+		"error occurred on create publisher (invalid country)": {
+			request:                  dtos.PublisherRequest{Name: "Allen & Unwin", Country: "XX"},
+			expectedErrorOnGetByName: gorm.ErrRecordNotFound,
+			expectedErrorResponse:    utils.ErrInvalidCountryCode,
+		},
+		"error occurred on create publisher (invalid website)": {
+			request:                  dtos.PublisherRequest{Name: "Allen & Unwin", Website: "allenandunwin"},
+			expectedErrorOnGetByName: gorm.ErrRecordNotFound,
+			expectedErrorResponse:    utils.ErrInvalidWebsite,
+		},
+		"error occurred on create publisher (create)": {
+			request:                  dtos.PublisherRequest{Name: "Allen & Unwin"},
+			expectedErrorOnGetByName: gorm.ErrRecordNotFound,
+			expectedPublisher:        entities.Publisher{Name: "Allen & Unwin", NormalizedName: "allen unwin"},
+			expectedErrorOnCreate:    errGeneric,
+			expectedErrorResponse:    errGeneric,
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			publisherDbMock := new(publisherrepomock.PublisherRepositoryMock)
+			publisherDbMock.On("GetPublisherByNormalizedName", utils.NormalizeName(tc.request.Name)).Return(tc.existing, tc.expectedErrorOnGetByName)
+			created := tc.expectedPublisher
+			created.Id = 1
+			publisherDbMock.On("CreatePublisher", tc.expectedPublisher).Return(created, tc.expectedErrorOnCreate)
+
+			publisherServiceTest := publisherService{publisherDb: publisherDbMock}
+			resp, err := publisherServiceTest.CreatePublisher(tc.request)
+			if tc.expectedErrorResponse != nil {
+				require.ErrorIs(t, err, tc.expectedErrorResponse)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, dtos.PublisherResponse{Id: 1, Name: "Allen & Unwin", Country: "GB", Website: "https://www.allenandunwin.com"}, resp)
+			}
+		})
+	}
+}
+
+func TestGetAllPublishers(t *testing.T) {
+	publisherDbMock := new(publisherrepomock.PublisherRepositoryMock)
+	publisherDbMock.On("GetAllPublishers", dtos.GetPublishersFilter{Country: "BR", Pagination: dtos.Pagination{Page: 1, Limit: 10}}).
+		Return([]entities.Publisher{{Id: 1, Name: "Rocco", Country: "BR"}}, nil)
+
+	publisherServiceTest := publisherService{publisherDb: publisherDbMock}
+	resp, err := publisherServiceTest.GetAllPublishers(dtos.GetPublishersFilter{Country: "br"})
+
+	require.NoError(t, err)
+	require.Equal(t, []dtos.PublisherResponse{{Id: 1, Name: "Rocco", Country: "BR"}}, resp.Publishers)
+
+	_, err = publisherServiceTest.GetAllPublishers(dtos.GetPublishersFilter{Country: "XX"})
+	require.ErrorIs(t, err, utils.ErrInvalidCountryCode)
+}
+
+func TestGetPublisher(t *testing.T) {
+	tests := map[string]struct {
+		expectedErrorOnGet    error
+		expectedErrorResponse error
+	}{
+		"success on get publisher": {},
+		"error occurred on get publisher (not found)": {
+			expectedErrorOnGet:    gorm.ErrRecordNotFound,
+			expectedErrorResponse: utils.ErrPublisherIdNotFound,
+		},
+		"error occurred on get publisher": {
+			expectedErrorOnGet:    errGeneric,
+			expectedErrorResponse: errGeneric,
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			publisherDbMock := new(publisherrepomock.PublisherRepositoryMock)
+			publisherDbMock.On("GetPublisher", 1).Return(entities.Publisher{Id: 1, Name: "Rocco"}, tc.expectedErrorOnGet)
+
+			publisherServiceTest := publisherService{publisherDb: publisherDbMock}
+			resp, err := publisherServiceTest.GetPublisher(1)
+			if tc.expectedErrorResponse != nil {
+				require.ErrorIs(t, err, tc.expectedErrorResponse)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, "Rocco", resp.Name)
+			}
+		})
+	}
+}
+
+func TestUpdatePublisherKeepsItsOwnName(t *testing.T) {
+	publisher := entities.Publisher{Id: 1, Name: "Rocco", NormalizedName: "rocco"}
+	updated := entities.Publisher{Id: 1, Name: "ROCCO", NormalizedName: "rocco", Country: "BR"}
+
+	publisherDbMock := new(publisherrepomock.PublisherRepositoryMock)
+	publisherDbMock.On("GetPublisher", 1).Return(publisher, nil)
+	publisherDbMock.On("GetPublisherByNormalizedName", "rocco").Return(publisher, nil)
+	publisherDbMock.On("UpdatePublisher", updated).Return(updated, nil)
+
+	publisherServiceTest := publisherService{publisherDb: publisherDbMock}
+	resp, err := publisherServiceTest.UpdatePublisher(1, dtos.PublisherRequest{Name: "ROCCO", Country: "BR"})
+
+	require.NoError(t, err)
+	require.Equal(t, "ROCCO", resp.Name)
+}
+
+func TestDeletePublisher(t *testing.T) {
+	tests := map[string]struct {
+		expectedErrorOnGet    error
+		books                 int64
+		expectedErrorOnCount  error
+		expectedErrorOnDelete error
+		expectedErrorResponse error
+	}{
+		"success on delete publisher": {},
+		"error occurred on delete publisher (not found)": {
+			expectedErrorOnGet:    gorm.ErrRecordNotFound,
+			expectedErrorResponse: utils.ErrPublisherIdNotFound,
+		},
+		"error occurred on delete publisher (has books)": {
+			books:                 2,
+			expectedErrorResponse: utils.ErrPublisherHasBooks,
+		},
+		"error occurred on delete publisher (count books)": {
+			expectedErrorOnCount:  errGeneric,
+			expectedErrorResponse: errGeneric,
+		},
+		"error occurred on delete publisher (delete)": {
+			expectedErrorOnDelete: errGeneric,
+			expectedErrorResponse: errGeneric,
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			publisherDbMock := new(publisherrepomock.PublisherRepositoryMock)
+			publisherDbMock.On("GetPublisher", 1).Return(entities.Publisher{Id: 1}, tc.expectedErrorOnGet)
+			publisherDbMock.On("CountBooks", 1).Return(tc.books, tc.expectedErrorOnCount)
+			publisherDbMock.On("DeletePublisher", 1).Return(tc.expectedErrorOnDelete)
+
+			publisherServiceTest := publisherService{publisherDb: publisherDbMock}
+			err := publisherServiceTest.DeletePublisher(1)
+			if tc.expectedErrorResponse != nil {
+				require.ErrorIs(t, err, tc.expectedErrorResponse)
+			} else {
+				require.NoError(t, err)
+				publisherDbMock.AssertCalled(t, "DeletePublisher", 1)
+			}
+		})
+	}
+}
+
+func TestGetPublisherBooks(t *testing.T) {
+	tests := map[string]struct {
+		expectedErrorOnGet      error
+		expectedErrorOnGetBooks error
+		expectedErrorResponse   error
+	}{
+		"success on get publisher books": {},
+		"error occurred on get publisher books (publisher not found)": {
+			expectedErrorOnGet:    gorm.ErrRecordNotFound,
+			expectedErrorResponse: utils.ErrPublisherIdNotFound,
+		},
+		"error occurred on get publisher books (get books)": {
+			expectedErrorOnGetBooks: errGeneric,
+			expectedErrorResponse:   errGeneric,
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			publisher := entities.Publisher{Id: 1, Name: "Rocco"}
+			publisherDbMock := new(publisherrepomock.PublisherRepositoryMock)
+			publisherDbMock.On("GetPublisher", 1).Return(publisher, tc.expectedErrorOnGet)
+
+			bookDbMock := new(bookrepomock.BookRepositoryMock)
+			bookDbMock.On("GetAllBooks", dtos.GetBooksFilter{PublisherId: 1, Pagination: dtos.Pagination{Page: 1, Limit: 10}}).
+				Return([]entities.Book{{Id: 3, Name: "book", PublisherId: &publisher.Id, Publisher: &publisher}}, tc.expectedErrorOnGetBooks)
+
+			publisherServiceTest := publisherService{publisherDb: publisherDbMock, bookDb: bookDbMock}
+			resp, err := publisherServiceTest.GetPublisherBooks(1, dtos.Pagination{})
+			if tc.expectedErrorResponse != nil {
+				require.ErrorIs(t, err, tc.expectedErrorResponse)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, []dtos.BookResponse{{Id: 3, Name: "book", PublisherId: 1, Publisher: "Rocco"}}, resp.Books)
+				require.Equal(t, dtos.Pagination{Page: 1, Limit: 10}, resp.Pagination)
+			}
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package services
+
+import (
+	"github/brunojoenk/golang-test/models/dtos"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type ReadingListServiceMock struct {
+	mock.Mock
+}
+
+func (m *ReadingListServiceMock) CreateList(owner string, listRequest dtos.ReadingListRequest) (dtos.ReadingListResponse, error) {
+	args := m.Called(owner, listRequest)
+	return args.Get(0).(dtos.ReadingListResponse), args.Error(1)
+}
+
+func (m *ReadingListServiceMock) GetLists(owner string, filter dtos.GetReadingListsFilter) (dtos.ReadingListResponseMetadata, error) {
+	args := m.Called(owner, filter)
+	return args.Get(0).(dtos.ReadingListResponseMetadata), args.Error(1)
+}
+
+func (m *ReadingListServiceMock) GetList(owner string, id int) (dtos.ReadingListResponse, error) {
+	args := m.Called(owner, id)
+	return args.Get(0).(dtos.ReadingListResponse), args.Error(1)
+}
+
+func (m *ReadingListServiceMock) UpdateList(owner string, id int, listRequest dtos.ReadingListRequest) (dtos.ReadingListResponse, error) {
+	args := m.Called(owner, id, listRequest)
+	return args.Get(0).(dtos.ReadingListResponse), args.Error(1)
+}
+
+func (m *ReadingListServiceMock) DeleteList(owner string, id int) error {
+	args := m.Called(owner, id)
+	return args.Error(0)
+}
+
+func (m *ReadingListServiceMock) AddBook(owner string, id int, bookRequest dtos.ReadingListBookRequest) (dtos.ReadingListResponse, error) {
+	args := m.Called(owner, id, bookRequest)
+	return args.Get(0).(dtos.ReadingListResponse), args.Error(1)
+}
+
+func (m *ReadingListServiceMock) UpdateBookNote(owner string, id, bookId int, noteRequest dtos.ReadingListNoteRequest) (dtos.ReadingListResponse, error) {
+	args := m.Called(owner, id, bookId, noteRequest)
+	return args.Get(0).(dtos.ReadingListResponse), args.Error(1)
+}
+
+func (m *ReadingListServiceMock) RemoveBook(owner string, id, bookId int) error {
+	args := m.Called(owner, id, bookId)
+	return args.Error(0)
+}
+
+func (m *ReadingListServiceMock) ReorderBooks(owner string, id int, orderRequest dtos.ReadingListOrderRequest) (dtos.ReadingListResponse, error) {
+	args := m.Called(owner, id, orderRequest)
+	return args.Get(0).(dtos.ReadingListResponse), args.Error(1)
+}
+
+func (m *ReadingListServiceMock) ExportList(owner string, id int, format string) ([]byte, error) {
+	args := m.Called(owner, id, format)
+	data, _ := args.Get(0).([]byte)
+	return data, args.Error(1)
+}
@@ -0,0 +1,394 @@
+package services
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"github/brunojoenk/golang-test/models/dtos"
+	"github/brunojoenk/golang-test/models/entities"
+	bookrepo "github/brunojoenk/golang-test/repository/book"
+	readinglistrepo "github/brunojoenk/golang-test/repository/readinglist"
+	bookservice "github/brunojoenk/golang-test/services/book"
+	"github/brunojoenk/golang-test/utils"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// exportColumns are the columns of a reading list exported as csv, one book per line
+var exportColumns = []string{"position", "book_id", "name", "authors", "isbn13", "publication_year", "note", "added_at"}
+
+// IReadingListService manages the reading lists of the users. Owner is the user calling, the owner of the
+// api key of the request, and empty when the request has none
+type IReadingListService interface {
+	CreateList(owner string, listRequest dtos.ReadingListRequest) (dtos.ReadingListResponse, error)
+	GetLists(owner string, filter dtos.GetReadingListsFilter) (dtos.ReadingListResponseMetadata, error)
+	GetList(owner string, id int) (dtos.ReadingListResponse, error)
+	UpdateList(owner string, id int, listRequest dtos.ReadingListRequest) (dtos.ReadingListResponse, error)
+	DeleteList(owner string, id int) error
+	AddBook(owner string, id int, bookRequest dtos.ReadingListBookRequest) (dtos.ReadingListResponse, error)
+	UpdateBookNote(owner string, id, bookId int, noteRequest dtos.ReadingListNoteRequest) (dtos.ReadingListResponse, error)
+	RemoveBook(owner string, id, bookId int) error
+	ReorderBooks(owner string, id int, orderRequest dtos.ReadingListOrderRequest) (dtos.ReadingListResponse, error)
+	ExportList(owner string, id int, format string) ([]byte, error)
+}
+
+type readingListService struct {
+	readingListDb readinglistrepo.IReadingListRepository
+	bookDb        bookrepo.IBookRepository
+}
+
+// NewReadingListService Service Constructor
+func NewReadingListService(db *gorm.DB) IReadingListService {
+	return &readingListService{
+		readingListDb: readinglistrepo.NewReadingListRepository(db),
+		bookDb:        bookrepo.NewBookRepository(db),
+	}
+}
+
+func (r *readingListService) CreateList(owner string, listRequest dtos.ReadingListRequest) (dtos.ReadingListResponse, error) {
+	if owner == "" {
+		return dtos.ReadingListResponse{}, utils.ErrListOwnerRequired
+	}
+
+	list := entities.ReadingList{Owner: owner}
+	if err := setList(&list, listRequest); err != nil {
+		return dtos.ReadingListResponse{}, err
+	}
+	list.CreatedAt = time.Now()
+	list.UpdatedAt = list.CreatedAt
+
+	list, err := r.readingListDb.CreateList(list)
+	if err != nil {
+		log.Error("Error on create reading list from repo: ", err.Error())
+		return dtos.ReadingListResponse{}, err
+	}
+
+	return toReadingListResponse(list), nil
+}
+
+// GetLists lists the public reading lists and the private ones of the owner, the last changed first
+func (r *readingListService) GetLists(owner string, filter dtos.GetReadingListsFilter) (dtos.ReadingListResponseMetadata, error) {
+	filter.Owner = strings.TrimSpace(filter.Owner)
+	filter.Pagination.ValidValuesAndSetDefault()
+
+	lists, err := r.readingListDb.GetLists(owner, filter)
+	if err != nil {
+		log.Error("Error on get reading lists from repo: ", err.Error())
+		return dtos.ReadingListResponseMetadata{}, err
+	}
+
+	listsResponse := make([]dtos.ReadingListResponse, len(lists))
+	for i, list := range lists {
+		listsResponse[i] = toReadingListResponse(list)
+	}
+
+	return dtos.ReadingListResponseMetadata{Lists: listsResponse, Pagination: filter.Pagination}, nil
+}
+
+// GetList returns the reading list with its books in order
+func (r *readingListService) GetList(owner string, id int) (dtos.ReadingListResponse, error) {
+	list, err := r.getVisibleList(owner, id)
+	if err != nil {
+		return dtos.ReadingListResponse{}, err
+	}
+
+	return toReadingListResponse(list), nil
+}
+
+func (r *readingListService) UpdateList(owner string, id int, listRequest dtos.ReadingListRequest) (dtos.ReadingListResponse, error) {
+	list, err := r.getOwnList(owner, id)
+	if err != nil {
+		return dtos.ReadingListResponse{}, err
+	}
+
+	if err := setList(&list, listRequest); err != nil {
+		return dtos.ReadingListResponse{}, err
+	}
+	list.UpdatedAt = time.Now()
+
+	list, err = r.readingListDb.UpdateList(list)
+	if err != nil {
+		log.Error("Error on update reading list from repo: ", err.Error())
+		return dtos.ReadingListResponse{}, err
+	}
+
+	return toReadingListResponse(list), nil
+}
+
+func (r *readingListService) DeleteList(owner string, id int) error {
+	if _, err := r.getOwnList(owner, id); err != nil {
+		return err
+	}
+
+	if err := r.readingListDb.DeleteList(id); err != nil {
+		log.Error("Error on delete reading list from repo: ", err.Error())
+		return err
+	}
+
+	return nil
+}
+
+// AddBook puts a book on the reading list, at the end unless the request has a position on the list
+func (r *readingListService) AddBook(owner string, id int, bookRequest dtos.ReadingListBookRequest) (dtos.ReadingListResponse, error) {
+	note, err := validNote(bookRequest.Note)
+	if err != nil {
+		return dtos.ReadingListResponse{}, err
+	}
+
+	if _, err := r.getOwnList(owner, id); err != nil {
+		return dtos.ReadingListResponse{}, err
+	}
+
+	if _, err := r.bookDb.GetBook(bookRequest.BookId); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return dtos.ReadingListResponse{}, utils.ErrBookIdNotFound
+		}
+		log.Error("Error on get book from repo: ", err.Error())
+		return dtos.ReadingListResponse{}, err
+	}
+
+	_, err = r.readingListDb.GetListEntry(id, bookRequest.BookId)
+	if err == nil {
+		return dtos.ReadingListResponse{}, utils.ErrListBookExists
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		log.Error("Error on get book of reading list from repo: ", err.Error())
+		return dtos.ReadingListResponse{}, err
+	}
+
+	_, err = r.readingListDb.AddEntry(entities.ReadingListEntry{
+		ListId:   id,
+		BookId:   bookRequest.BookId,
+		Position: bookRequest.Position,
+		Note:     note,
+		AddedAt:  time.Now(),
+	})
+	if err != nil {
+		log.Error("Error on add book to reading list from repo: ", err.Error())
+		return dtos.ReadingListResponse{}, err
+	}
+
+	return r.GetList(owner, id)
+}
+
+func (r *readingListService) UpdateBookNote(owner string, id, bookId int, noteRequest dtos.ReadingListNoteRequest) (dtos.ReadingListResponse, error) {
+	note, err := validNote(noteRequest.Note)
+	if err != nil {
+		return dtos.ReadingListResponse{}, err
+	}
+
+	entry, err := r.getOwnEntry(owner, id, bookId)
+	if err != nil {
+		return dtos.ReadingListResponse{}, err
+	}
+
+	entry.Note = note
+	if err := r.readingListDb.UpdateEntryNote(entry); err != nil {
+		log.Error("Error on update note of reading list book from repo: ", err.Error())
+		return dtos.ReadingListResponse{}, err
+	}
+
+	return r.GetList(owner, id)
+}
+
+func (r *readingListService) RemoveBook(owner string, id, bookId int) error {
+	entry, err := r.getOwnEntry(owner, id, bookId)
+	if err != nil {
+		return err
+	}
+
+	if err := r.readingListDb.RemoveEntry(entry); err != nil {
+		log.Error("Error on remove book from reading list from repo: ", err.Error())
+		return err
+	}
+
+	return nil
+}
+
+// ReorderBooks puts the books of the reading list in the order of the request, which must have each of them once
+func (r *readingListService) ReorderBooks(owner string, id int, orderRequest dtos.ReadingListOrderRequest) (dtos.ReadingListResponse, error) {
+	list, err := r.getOwnList(owner, id)
+	if err != nil {
+		return dtos.ReadingListResponse{}, err
+	}
+
+	if len(orderRequest.BookIds) != len(list.Entries) {
+		return dtos.ReadingListResponse{}, utils.ErrInvalidListOrder
+	}
+	onList := make(map[int]bool, len(list.Entries))
+	for _, entry := range list.Entries {
+		onList[entry.BookId] = true
+	}
+	for _, bookId := range orderRequest.BookIds {
+		if !onList[bookId] {
+			return dtos.ReadingListResponse{}, utils.ErrInvalidListOrder
+		}
+		delete(onList, bookId)
+	}
+
+	if err := r.readingListDb.ReorderEntries(id, orderRequest.BookIds); err != nil {
+		log.Error("Error on reorder reading list from repo: ", err.Error())
+		return dtos.ReadingListResponse{}, err
+	}
+
+	return r.GetList(owner, id)
+}
+
+// ExportList returns the reading list as a json document or as csv, with a line per book
+func (r *readingListService) ExportList(owner string, id int, format string) ([]byte, error) {
+	format = strings.ToLower(strings.TrimSpace(format))
+	if format != dtos.ExportFormatCsv && format != dtos.ExportFormatJson {
+		return nil, utils.ErrInvalidExportFormat
+	}
+
+	list, err := r.getVisibleList(owner, id)
+	if err != nil {
+		return nil, err
+	}
+	listResponse := toReadingListResponse(list)
+
+	if format == dtos.ExportFormatJson {
+		return json.Marshal(listResponse)
+	}
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.Write(exportColumns); err != nil {
+		return nil, err
+	}
+	for _, listBook := range listResponse.Books {
+		record := []string{
+			strconv.Itoa(listBook.Position),
+			strconv.Itoa(listBook.Book.Id),
+			listBook.Book.Name,
+			listBook.Book.Authors,
+			listBook.Book.Isbn13,
+			strconv.Itoa(listBook.Book.PublicationYear),
+			listBook.Note,
+			listBook.AddedAt.UTC().Format(time.RFC3339),
+		}
+		if err := w.Write(record); err != nil {
+			return nil, err
+		}
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// getVisibleList returns the reading list when the owner can see it. A private list of someone else is not found
+func (r *readingListService) getVisibleList(owner string, id int) (entities.ReadingList, error) {
+	list, err := r.readingListDb.GetList(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return entities.ReadingList{}, utils.ErrListIdNotFound
+		}
+		log.Error("Error on get reading list from repo: ", err.Error())
+		return entities.ReadingList{}, err
+	}
+	if list.Visibility != dtos.ListVisibilityPublic && (owner == "" || list.Owner != owner) {
+		return entities.ReadingList{}, utils.ErrListIdNotFound
+	}
+
+	return list, nil
+}
+
+// getOwnList returns the reading list when it is of the owner, who is the only one to change it
+func (r *readingListService) getOwnList(owner string, id int) (entities.ReadingList, error) {
+	if owner == "" {
+		return entities.ReadingList{}, utils.ErrListOwnerRequired
+	}
+
+	list, err := r.getVisibleList(owner, id)
+	if err != nil {
+		return entities.ReadingList{}, err
+	}
+	if list.Owner != owner {
+		return entities.ReadingList{}, utils.ErrListNotOwner
+	}
+
+	return list, nil
+}
+
+func (r *readingListService) getOwnEntry(owner string, id, bookId int) (entities.ReadingListEntry, error) {
+	list, err := r.getOwnList(owner, id)
+	if err != nil {
+		return entities.ReadingListEntry{}, err
+	}
+
+	for _, entry := range list.Entries {
+		if entry.BookId == bookId {
+			return entry, nil
+		}
+	}
+
+	return entities.ReadingListEntry{}, utils.ErrListBookNotFound
+}
+
+// setList validates the request and sets its name, description and visibility on the list
+func setList(list *entities.ReadingList, listRequest dtos.ReadingListRequest) error {
+	name := strings.TrimSpace(listRequest.Name)
+	if name == "" || utf8.RuneCountInString(name) > dtos.ListNameMaxLength {
+		return utils.ErrInvalidListName
+	}
+
+	visibility := strings.ToLower(strings.TrimSpace(listRequest.Visibility))
+	switch visibility {
+	case "":
+		visibility = dtos.ListVisibilityPrivate
+	case dtos.ListVisibilityPrivate, dtos.ListVisibilityPublic:
+	default:
+		return utils.ErrInvalidListVisibility
+	}
+
+	list.Name = name
+	list.Description = strings.TrimSpace(listRequest.Description)
+	list.Visibility = visibility
+	return nil
+}
+
+func validNote(note string) (string, error) {
+	note = strings.TrimSpace(note)
+	if utf8.RuneCountInString(note) > dtos.ListNoteMaxLength {
+		return "", utils.ErrListNoteTooLong
+	}
+	return note, nil
+}
+
+// toReadingListResponse maps the list and its books, numbered from 1 in order, using the book response of the book endpoints
+func toReadingListResponse(list entities.ReadingList) dtos.ReadingListResponse {
+	listResponse := dtos.ReadingListResponse{
+		Id:          list.Id,
+		Owner:       list.Owner,
+		Name:        list.Name,
+		Description: list.Description,
+		Visibility:  list.Visibility,
+		BookCount:   list.BookCount,
+		CreatedAt:   list.CreatedAt,
+		UpdatedAt:   list.UpdatedAt,
+	}
+
+	if list.Entries != nil {
+		listResponse.BookCount = len(list.Entries)
+		listResponse.Books = make([]dtos.ReadingListBookResponse, len(list.Entries))
+		for i, entry := range list.Entries {
+			listResponse.Books[i] = dtos.ReadingListBookResponse{
+				Position: i + 1,
+				Note:     entry.Note,
+				AddedAt:  entry.AddedAt,
+				Book:     bookservice.ToBookResponse(entry.Book),
+			}
+		}
+	}
+
+	return listResponse
+}
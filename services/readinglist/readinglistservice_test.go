@@ -0,0 +1,353 @@
+package services
+
+import (
+	"errors"
+	"github/brunojoenk/golang-test/models/dtos"
+	"github/brunojoenk/golang-test/models/entities"
+	bookrepomock "github/brunojoenk/golang-test/repository/book/mock"
+	readinglistrepomock "github/brunojoenk/golang-test/repository/readinglist/mock"
+	"github/brunojoenk/golang-test/utils"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+var errGeneric = errors.New("generic error")
+
+var addedAt = time.Date(2022, 10, 8, 10, 0, 0, 0, time.UTC)
+
+func isbnPointer(isbn string) *string {
+	return &isbn
+}
+
+// readingList is a list of ana with the books 3 and 1, in this order
+func readingList(visibility string) entities.ReadingList {
+	return entities.ReadingList{Id: 4, Owner: "ana", Name: "To read", Visibility: visibility, Entries: []entities.ReadingListEntry{
+		{Id: 8, ListId: 4, BookId: 3, Position: 1, AddedAt: addedAt,
+			Book: entities.Book{Id: 3, Name: "Dune", PublicationYear: 1965, Isbn13: isbnPointer("9780441013593"),
+				Contributors: []entities.AuthorBook{{Role: dtos.RoleAuthor, Author: entities.Author{Name: "Frank Herbert"}}}}},
+		{Id: 9, ListId: 4, BookId: 1, Position: 2, Note: "Chapter 2, \"Riddles\"", AddedAt: addedAt,
+			Book: entities.Book{Id: 1, Name: "The Hobbit", PublicationYear: 1937}},
+	}}
+}
+
+func TestCreateList(t *testing.T) {
+	tests := map[string]struct {
+		owner                 string
+		request               dtos.ReadingListRequest
+		expectedVisibility    string
+		expectedErrorOnCreate error
+		expectedErrorResponse error
+	}{
+		"success on create reading list (private by default)": {
+			owner:              "ana",
+			request:            dtos.ReadingListRequest{Name: " To read "},
+			expectedVisibility: dtos.ListVisibilityPrivate,
+		},
+		"success on create reading list (public)": {
+			owner:              "ana",
+			request:            dtos.ReadingListRequest{Name: "Course syllabus", Visibility: "Public"},
+			expectedVisibility: dtos.ListVisibilityPublic,
+		},
+		"error occurred on create reading list (no owner)": {
+			request:               dtos.ReadingListRequest{Name: "To read"},
+			expectedErrorResponse: utils.ErrListOwnerRequired,
+		},
+		"error occurred on create reading list (empty name)": {
+			owner:                 "ana",
+			request:               dtos.ReadingListRequest{Name: "  "},
+			expectedErrorResponse: utils.ErrInvalidListName,
+		},
+		"error occurred on create reading list (name too long)": {
+			owner:                 "ana",
+			request:               dtos.ReadingListRequest{Name: strings.Repeat("a", dtos.ListNameMaxLength+1)},
+			expectedErrorResponse: utils.ErrInvalidListName,
+		},
+		"error occurred on create reading list (invalid visibility)": {
+			owner:                 "ana",
+			request:               dtos.ReadingListRequest{Name: "To read", Visibility: "friends"},
+			expectedErrorResponse: utils.ErrInvalidListVisibility,
+		},
+		"error occurred on create reading list (generic error)": {
+			owner:                 "ana",
+			request:               dtos.ReadingListRequest{Name: "To read"},
+			expectedErrorOnCreate: errGeneric,
+			expectedErrorResponse: errGeneric,
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			var created entities.ReadingList
+			readingListDbMock := new(readinglistrepomock.ReadingListRepositoryMock)
+			readingListDbMock.On("CreateList", mock.AnythingOfType("entities.ReadingList")).Run(func(args mock.Arguments) {
+				created = args.Get(0).(entities.ReadingList)
+			}).Return(entities.ReadingList{Id: 4, Owner: tc.owner, Name: strings.TrimSpace(tc.request.Name), Visibility: tc.expectedVisibility}, tc.expectedErrorOnCreate)
+
+			readingListServiceTest := readingListService{readingListDb: readingListDbMock}
+			resp, err := readingListServiceTest.CreateList(tc.owner, tc.request)
+			if tc.expectedErrorResponse != nil {
+				require.ErrorIs(t, err, tc.expectedErrorResponse)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, 4, resp.Id)
+			require.Equal(t, tc.owner, created.Owner)
+			require.Equal(t, strings.TrimSpace(tc.request.Name), created.Name)
+			require.Equal(t, tc.expectedVisibility, created.Visibility)
+		})
+	}
+}
+
+func TestGetList(t *testing.T) {
+	tests := map[string]struct {
+		owner                 string
+		visibility            string
+		expectedErrorOnGet    error
+		expectedErrorResponse error
+	}{
+		"success on get reading list (own private list)": {
+			owner:      "ana",
+			visibility: dtos.ListVisibilityPrivate,
+		},
+		"success on get reading list (public list of someone else)": {
+			owner:      "bob",
+			visibility: dtos.ListVisibilityPublic,
+		},
+		"success on get reading list (public list without api key)": {
+			visibility: dtos.ListVisibilityPublic,
+		},
+		"error occurred on get reading list (private list of someone else)": {
+			owner:                 "bob",
+			visibility:            dtos.ListVisibilityPrivate,
+			expectedErrorResponse: utils.ErrListIdNotFound,
+		},
+		"error occurred on get reading list (private list without api key)": {
+			visibility:            dtos.ListVisibilityPrivate,
+			expectedErrorResponse: utils.ErrListIdNotFound,
+		},
+		"error occurred on get reading list (not found)": {
+			owner:                 "ana",
+			expectedErrorOnGet:    gorm.ErrRecordNotFound,
+			expectedErrorResponse: utils.ErrListIdNotFound,
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			readingListDbMock := new(readinglistrepomock.ReadingListRepositoryMock)
+			readingListDbMock.On("GetList", 4).Return(readingList(tc.visibility), tc.expectedErrorOnGet)
+
+			readingListServiceTest := readingListService{readingListDb: readingListDbMock}
+			resp, err := readingListServiceTest.GetList(tc.owner, 4)
+			if tc.expectedErrorResponse != nil {
+				require.ErrorIs(t, err, tc.expectedErrorResponse)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, 2, resp.BookCount)
+			require.Equal(t, 1, resp.Books[0].Position)
+			require.Equal(t, "Dune", resp.Books[0].Book.Name)
+			require.Equal(t, "Frank Herbert", resp.Books[0].Book.Authors)
+			require.Equal(t, 2, resp.Books[1].Position)
+		})
+	}
+}
+
+func TestGetLists(t *testing.T) {
+	filter := dtos.GetReadingListsFilter{Owner: "ana", Pagination: dtos.Pagination{Page: 1, Limit: 10}}
+	readingListDbMock := new(readinglistrepomock.ReadingListRepositoryMock)
+	readingListDbMock.On("GetLists", "bob", filter).Return([]entities.ReadingList{
+		{Id: 4, Owner: "ana", Name: "Course syllabus", Visibility: dtos.ListVisibilityPublic, BookCount: 3},
+	}, nil)
+
+	readingListServiceTest := readingListService{readingListDb: readingListDbMock}
+	resp, err := readingListServiceTest.GetLists("bob", dtos.GetReadingListsFilter{Owner: " ana "})
+
+	require.NoError(t, err)
+	require.Len(t, resp.Lists, 1)
+	require.Equal(t, 3, resp.Lists[0].BookCount)
+	require.Nil(t, resp.Lists[0].Books)
+	require.Equal(t, filter.Pagination, resp.Pagination)
+}
+
+func TestUpdateList(t *testing.T) {
+	tests := map[string]struct {
+		owner                 string
+		expectedErrorResponse error
+	}{
+		"success on update reading list": {
+			owner: "ana",
+		},
+		"error occurred on update reading list (no owner)": {
+			expectedErrorResponse: utils.ErrListOwnerRequired,
+		},
+		"error occurred on update reading list (list of someone else)": {
+			owner:                 "bob",
+			expectedErrorResponse: utils.ErrListNotOwner,
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			readingListDbMock := new(readinglistrepomock.ReadingListRepositoryMock)
+			readingListDbMock.On("GetList", 4).Return(readingList(dtos.ListVisibilityPublic), nil)
+			readingListDbMock.On("UpdateList", mock.MatchedBy(func(list entities.ReadingList) bool {
+				return list.Id == 4 && list.Owner == "ana" && list.Name == "Course syllabus" && list.Visibility == dtos.ListVisibilityPrivate
+			})).Return(entities.ReadingList{Id: 4, Owner: "ana", Name: "Course syllabus", Visibility: dtos.ListVisibilityPrivate}, nil)
+
+			readingListServiceTest := readingListService{readingListDb: readingListDbMock}
+			resp, err := readingListServiceTest.UpdateList(tc.owner, 4, dtos.ReadingListRequest{Name: "Course syllabus", Visibility: dtos.ListVisibilityPrivate})
+			if tc.expectedErrorResponse != nil {
+				require.ErrorIs(t, err, tc.expectedErrorResponse)
+				readingListDbMock.AssertNotCalled(t, "UpdateList", mock.Anything)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, "Course syllabus", resp.Name)
+		})
+	}
+}
+
+func TestAddBook(t *testing.T) {
+	tests := map[string]struct {
+		request                dtos.ReadingListBookRequest
+		expectedErrorOnGetBook error
+		expectedErrorOnEntry   error
+		expectedErrorOnAdd     error
+		expectedErrorResponse  error
+	}{
+		"success on add book to reading list": {
+			request:              dtos.ReadingListBookRequest{BookId: 2, Note: " For the exam ", Position: 1},
+			expectedErrorOnEntry: gorm.ErrRecordNotFound,
+		},
+		"error occurred on add book to reading list (note too long)": {
+			request:               dtos.ReadingListBookRequest{BookId: 2, Note: strings.Repeat("a", dtos.ListNoteMaxLength+1)},
+			expectedErrorResponse: utils.ErrListNoteTooLong,
+		},
+		"error occurred on add book to reading list (book not found)": {
+			request:                dtos.ReadingListBookRequest{BookId: 2},
+			expectedErrorOnGetBook: gorm.ErrRecordNotFound,
+			expectedErrorResponse:  utils.ErrBookIdNotFound,
+		},
+		"error occurred on add book to reading list (already on the list)": {
+			request:               dtos.ReadingListBookRequest{BookId: 2},
+			expectedErrorResponse: utils.ErrListBookExists,
+		},
+		"error occurred on add book to reading list (generic error)": {
+			request:               dtos.ReadingListBookRequest{BookId: 2},
+			expectedErrorOnEntry:  gorm.ErrRecordNotFound,
+			expectedErrorOnAdd:    errGeneric,
+			expectedErrorResponse: errGeneric,
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			bookDbMock := new(bookrepomock.BookRepositoryMock)
+			bookDbMock.On("GetBook", 2).Return(entities.Book{Id: 2}, tc.expectedErrorOnGetBook)
+			readingListDbMock := new(readinglistrepomock.ReadingListRepositoryMock)
+			readingListDbMock.On("GetList", 4).Return(readingList(dtos.ListVisibilityPrivate), nil)
+			readingListDbMock.On("GetListEntry", 4, 2).Return(entities.ReadingListEntry{Id: 10}, tc.expectedErrorOnEntry)
+			readingListDbMock.On("AddEntry", mock.MatchedBy(func(entry entities.ReadingListEntry) bool {
+				return entry.ListId == 4 && entry.BookId == 2 && entry.Position == tc.request.Position && entry.Note == strings.TrimSpace(tc.request.Note)
+			})).Return(entities.ReadingListEntry{Id: 10}, tc.expectedErrorOnAdd)
+
+			readingListServiceTest := readingListService{readingListDb: readingListDbMock, bookDb: bookDbMock}
+			_, err := readingListServiceTest.AddBook("ana", 4, tc.request)
+			if tc.expectedErrorResponse != nil {
+				require.ErrorIs(t, err, tc.expectedErrorResponse)
+				return
+			}
+			require.NoError(t, err)
+			readingListDbMock.AssertNumberOfCalls(t, "AddEntry", 1)
+		})
+	}
+}
+
+func TestRemoveBook(t *testing.T) {
+	tests := map[string]struct {
+		bookId                int
+		expectedErrorResponse error
+	}{
+		"success on remove book from reading list": {
+			bookId: 1,
+		},
+		"error occurred on remove book from reading list (not on the list)": {
+			bookId:                2,
+			expectedErrorResponse: utils.ErrListBookNotFound,
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			list := readingList(dtos.ListVisibilityPrivate)
+			readingListDbMock := new(readinglistrepomock.ReadingListRepositoryMock)
+			readingListDbMock.On("GetList", 4).Return(list, nil)
+			readingListDbMock.On("RemoveEntry", list.Entries[1]).Return(nil)
+
+			readingListServiceTest := readingListService{readingListDb: readingListDbMock}
+			err := readingListServiceTest.RemoveBook("ana", 4, tc.bookId)
+			require.ErrorIs(t, err, tc.expectedErrorResponse)
+		})
+	}
+}
+
+func TestReorderBooks(t *testing.T) {
+	tests := map[string]struct {
+		bookIds               []int
+		expectedErrorResponse error
+	}{
+		"success on reorder reading list": {
+			bookIds: []int{1, 3},
+		},
+		"error occurred on reorder reading list (book missing)": {
+			bookIds:               []int{1},
+			expectedErrorResponse: utils.ErrInvalidListOrder,
+		},
+		"error occurred on reorder reading list (book repeated)": {
+			bookIds:               []int{1, 1},
+			expectedErrorResponse: utils.ErrInvalidListOrder,
+		},
+		"error occurred on reorder reading list (book not on the list)": {
+			bookIds:               []int{1, 2},
+			expectedErrorResponse: utils.ErrInvalidListOrder,
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			readingListDbMock := new(readinglistrepomock.ReadingListRepositoryMock)
+			readingListDbMock.On("GetList", 4).Return(readingList(dtos.ListVisibilityPrivate), nil)
+			readingListDbMock.On("ReorderEntries", 4, tc.bookIds).Return(nil)
+
+			readingListServiceTest := readingListService{readingListDb: readingListDbMock}
+			_, err := readingListServiceTest.ReorderBooks("ana", 4, dtos.ReadingListOrderRequest{BookIds: tc.bookIds})
+			if tc.expectedErrorResponse != nil {
+				require.ErrorIs(t, err, tc.expectedErrorResponse)
+				readingListDbMock.AssertNotCalled(t, "ReorderEntries", mock.Anything, mock.Anything)
+				return
+			}
+			require.NoError(t, err)
+			readingListDbMock.AssertCalled(t, "ReorderEntries", 4, tc.bookIds)
+		})
+	}
+}
+
+func TestExportList(t *testing.T) {
+	readingListDbMock := new(readinglistrepomock.ReadingListRepositoryMock)
+	readingListDbMock.On("GetList", 4).Return(readingList(dtos.ListVisibilityPublic), nil)
+	readingListServiceTest := readingListService{readingListDb: readingListDbMock}
+
+	data, err := readingListServiceTest.ExportList("", 4, "CSV")
+	require.NoError(t, err)
+	require.Equal(t, "position,book_id,name,authors,isbn13,publication_year,note,added_at\n"+
+		"1,3,Dune,Frank Herbert,9780441013593,1965,,2022-10-08T10:00:00Z\n"+
+		"2,1,The Hobbit,,,1937,\"Chapter 2, \"\"Riddles\"\"\",2022-10-08T10:00:00Z\n", string(data))
+
+	data, err = readingListServiceTest.ExportList("", 4, dtos.ExportFormatJson)
+	require.NoError(t, err)
+	require.Contains(t, string(data), `"name":"To read"`)
+	require.Contains(t, string(data), `"position":2`)
+
+	_, err = readingListServiceTest.ExportList("", 4, "xml")
+	require.ErrorIs(t, err, utils.ErrInvalidExportFormat)
+}
@@ -0,0 +1,16 @@
+package services
+
+import (
+	"github/brunojoenk/golang-test/models/dtos"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type RecommendationServiceMock struct {
+	mock.Mock
+}
+
+func (m *RecommendationServiceMock) GetRelatedBooks(bookId int, pagination dtos.Pagination) (dtos.RelatedBookResponseMetadata, error) {
+	args := m.Called(bookId, pagination)
+	return args.Get(0).(dtos.RelatedBookResponseMetadata), args.Error(1)
+}
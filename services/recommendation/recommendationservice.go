@@ -0,0 +1,88 @@
+package services
+
+import (
+	"errors"
+	"github/brunojoenk/golang-test/models/dtos"
+	"github/brunojoenk/golang-test/models/entities"
+	bookrepo "github/brunojoenk/golang-test/repository/book"
+	bookservice "github/brunojoenk/golang-test/services/book"
+	"github/brunojoenk/golang-test/utils"
+
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type IRecommendationService interface {
+	GetRelatedBooks(bookId int, pagination dtos.Pagination) (dtos.RelatedBookResponseMetadata, error)
+}
+
+type recommendationService struct {
+	recommender Recommender
+	bookDb      bookrepo.IBookRepository
+}
+
+// NewRecommendationService Service Constructor
+func NewRecommendationService(db *gorm.DB) IRecommendationService {
+	return &recommendationService{
+		recommender: NewSqlRecommender(db, DefaultRelatedWeights),
+		bookDb:      bookrepo.NewBookRepository(db),
+	}
+}
+
+// GetRelatedBooks returns a page of the books related to the book, in the order of the recommender
+func (r *recommendationService) GetRelatedBooks(bookId int, pagination dtos.Pagination) (dtos.RelatedBookResponseMetadata, error) {
+	if _, err := r.bookDb.GetBook(bookId); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return dtos.RelatedBookResponseMetadata{}, utils.ErrBookIdNotFound
+		}
+		log.Error("Error on get book from repo: ", err.Error())
+		return dtos.RelatedBookResponseMetadata{}, err
+	}
+
+	pagination.ValidValuesAndSetDefault()
+	scores, err := r.recommender.RelatedBooks(bookId, pagination)
+	if err != nil {
+		log.Error("Error on get related books from recommender: ", err.Error())
+		return dtos.RelatedBookResponseMetadata{}, err
+	}
+
+	relatedBooks := make([]dtos.RelatedBookResponse, 0, len(scores))
+	if len(scores) == 0 {
+		return dtos.RelatedBookResponseMetadata{Books: relatedBooks, Pagination: pagination}, nil
+	}
+
+	ids := make([]int, len(scores))
+	for i, score := range scores {
+		ids[i] = score.BookId
+	}
+	books, err := r.bookDb.GetBooks(ids)
+	if err != nil {
+		log.Error("Error on get related books from repo: ", err.Error())
+		return dtos.RelatedBookResponseMetadata{}, err
+	}
+	booksById := make(map[int]entities.Book, len(books))
+	for _, book := range books {
+		booksById[book.Id] = book
+	}
+
+	// A book deleted since it was scored is left out
+	for _, score := range scores {
+		book, ok := booksById[score.BookId]
+		if !ok {
+			continue
+		}
+		relatedBooks = append(relatedBooks, dtos.RelatedBookResponse{
+			Score: score.Score,
+			Reasons: dtos.RelatedReasons{
+				SharedAuthors: score.SharedAuthors,
+				SharedGenres:  score.SharedGenres,
+				SharedTags:    score.SharedTags,
+				CoBorrowed:    score.CoBorrowed,
+				CoListed:      score.CoListed,
+			},
+			Book: bookservice.ToBookResponse(book),
+		})
+	}
+
+	return dtos.RelatedBookResponseMetadata{Books: relatedBooks, Pagination: pagination}, nil
+}
@@ -0,0 +1,130 @@
+package services
+
+import (
+	"errors"
+	"github/brunojoenk/golang-test/models/dtos"
+	"github/brunojoenk/golang-test/models/entities"
+	bookrepomock "github/brunojoenk/golang-test/repository/book/mock"
+	recommendationrepomock "github/brunojoenk/golang-test/repository/recommendation/mock"
+	"github/brunojoenk/golang-test/utils"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+var errGeneric = errors.New("generic error")
+
+func TestSqlRecommender(t *testing.T) {
+	pagination := dtos.Pagination{Page: 1, Limit: 10}
+	recommendationDbMock := new(recommendationrepomock.RecommendationRepositoryMock)
+	recommendationDbMock.On("GetRelatedBooks", 7, DefaultRelatedWeights, pagination).Return([]dtos.RelatedBookScore{{BookId: 3, Score: 4}}, nil)
+
+	recommender := SqlRecommender{recommendationDb: recommendationDbMock, Weights: DefaultRelatedWeights}
+	scores, err := recommender.RelatedBooks(7, pagination)
+
+	require.NoError(t, err)
+	require.Equal(t, []dtos.RelatedBookScore{{BookId: 3, Score: 4}}, scores)
+}
+
+func TestGetRelatedBooks(t *testing.T) {
+	pagination := dtos.Pagination{Page: 1, Limit: 10}
+	tests := map[string]struct {
+		scores                 []dtos.RelatedBookScore
+		expectedErrorOnGetBook error
+		expectedErrorOnScores  error
+		expectedErrorOnBooks   error
+		expectedIds            []int
+		expectedErrorResponse  error
+	}{
+		"success on get related books (in score order)": {
+			scores: []dtos.RelatedBookScore{
+				{BookId: 3, Score: 9, SharedAuthors: 1, SharedGenres: 2, SharedTags: 1},
+				{BookId: 1, Score: 2, CoBorrowed: 1, CoListed: 1},
+			},
+			expectedIds: []int{3, 1},
+		},
+		"success on get related books (book deleted since scored)": {
+			scores: []dtos.RelatedBookScore{
+				{BookId: 3, Score: 9},
+				{BookId: 8, Score: 4},
+				{BookId: 1, Score: 2},
+			},
+			expectedIds: []int{3, 1},
+		},
+		"success on get related books (none)": {
+			scores:      []dtos.RelatedBookScore{},
+			expectedIds: []int{},
+		},
+		"error occurred on get related books (book not found)": {
+			expectedErrorOnGetBook: gorm.ErrRecordNotFound,
+			expectedErrorResponse:  utils.ErrBookIdNotFound,
+		},
+		"error occurred on get related books (recommender error)": {
+			expectedErrorOnScores: errGeneric,
+			expectedErrorResponse: errGeneric,
+		},
+		"error occurred on get related books (books error)": {
+			scores:                []dtos.RelatedBookScore{{BookId: 3, Score: 9}},
+			expectedErrorOnBooks:  errGeneric,
+			expectedErrorResponse: errGeneric,
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			ids := make([]int, len(tc.scores))
+			for i, score := range tc.scores {
+				ids[i] = score.BookId
+			}
+			bookDbMock := new(bookrepomock.BookRepositoryMock)
+			bookDbMock.On("GetBook", 7).Return(entities.Book{Id: 7}, tc.expectedErrorOnGetBook)
+			bookDbMock.On("GetBooks", ids).Return([]entities.Book{{Id: 1, Name: "The Hobbit"}, {Id: 3, Name: "Dune"}}, tc.expectedErrorOnBooks)
+			recommendationDbMock := new(recommendationrepomock.RecommendationRepositoryMock)
+			recommendationDbMock.On("GetRelatedBooks", 7, DefaultRelatedWeights, pagination).Return(tc.scores, tc.expectedErrorOnScores)
+
+			recommendationServiceTest := recommendationService{
+				recommender: &SqlRecommender{recommendationDb: recommendationDbMock, Weights: DefaultRelatedWeights},
+				bookDb:      bookDbMock,
+			}
+			resp, err := recommendationServiceTest.GetRelatedBooks(7, dtos.Pagination{})
+			if tc.expectedErrorResponse != nil {
+				require.ErrorIs(t, err, tc.expectedErrorResponse)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, pagination, resp.Pagination)
+			require.NotNil(t, resp.Books)
+			gotIds := make([]int, len(resp.Books))
+			for i, related := range resp.Books {
+				gotIds[i] = related.Book.Id
+			}
+			require.Equal(t, tc.expectedIds, gotIds)
+			if len(tc.scores) == 0 {
+				bookDbMock.AssertNotCalled(t, "GetBooks", ids)
+			}
+		})
+	}
+
+	t.Run("scores of the first related book", func(t *testing.T) {
+		bookDbMock := new(bookrepomock.BookRepositoryMock)
+		bookDbMock.On("GetBook", 7).Return(entities.Book{Id: 7}, nil)
+		bookDbMock.On("GetBooks", []int{3}).Return([]entities.Book{{Id: 3, Name: "Dune"}}, nil)
+		recommendationDbMock := new(recommendationrepomock.RecommendationRepositoryMock)
+		recommendationDbMock.On("GetRelatedBooks", 7, DefaultRelatedWeights, pagination).Return([]dtos.RelatedBookScore{
+			{BookId: 3, Score: 9, SharedAuthors: 1, SharedGenres: 2, SharedTags: 1},
+		}, nil)
+
+		recommendationServiceTest := recommendationService{
+			recommender: &SqlRecommender{recommendationDb: recommendationDbMock, Weights: DefaultRelatedWeights},
+			bookDb:      bookDbMock,
+		}
+		resp, err := recommendationServiceTest.GetRelatedBooks(7, pagination)
+
+		require.NoError(t, err)
+		require.Equal(t, dtos.RelatedBookResponse{
+			Score:   9,
+			Reasons: dtos.RelatedReasons{SharedAuthors: 1, SharedGenres: 2, SharedTags: 1},
+			Book:    dtos.BookResponse{Id: 3, Name: "Dune"},
+		}, resp.Books[0])
+	})
+}
@@ -0,0 +1,33 @@
+package services
+
+import (
+	"github/brunojoenk/golang-test/models/dtos"
+	recommendationrepo "github/brunojoenk/golang-test/repository/recommendation"
+
+	"gorm.io/gorm"
+)
+
+// DefaultRelatedWeights score a shared author above a shared genre, and a shared genre above a shared tag, a
+// patron who borrowed both books or a public reading list with both
+var DefaultRelatedWeights = dtos.RelatedWeights{Author: 4, Genre: 2, Tag: 1, CoBorrowed: 1, CoListed: 1}
+
+// Recommender finds the books related to a book and scores them, the most related first. Implementations must
+// return the same order for the same data, so pages do not overlap
+type Recommender interface {
+	RelatedBooks(bookId int, pagination dtos.Pagination) ([]dtos.RelatedBookScore, error)
+}
+
+// SqlRecommender scores the books in the database by what they have in common with the book, adding up the
+// weight of each author, genre and tag, each patron who borrowed both books and each public reading list with both
+type SqlRecommender struct {
+	recommendationDb recommendationrepo.IRecommendationRepository
+	Weights          dtos.RelatedWeights
+}
+
+func NewSqlRecommender(db *gorm.DB, weights dtos.RelatedWeights) *SqlRecommender {
+	return &SqlRecommender{recommendationDb: recommendationrepo.NewRecommendationRepository(db), Weights: weights}
+}
+
+func (r *SqlRecommender) RelatedBooks(bookId int, pagination dtos.Pagination) ([]dtos.RelatedBookScore, error) {
+	return r.recommendationDb.GetRelatedBooks(bookId, r.Weights, pagination)
+}
@@ -0,0 +1,36 @@
+package services
+
+import (
+	"github/brunojoenk/golang-test/models/dtos"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type ReviewServiceMock struct {
+	mock.Mock
+}
+
+func (m *ReviewServiceMock) CreateReview(owner string, bookId int, reviewRequest dtos.ReviewRequest) (dtos.ReviewResponse, error) {
+	args := m.Called(owner, bookId, reviewRequest)
+	return args.Get(0).(dtos.ReviewResponse), args.Error(1)
+}
+
+func (m *ReviewServiceMock) GetBookReviews(bookId int, filter dtos.GetReviewsFilter) (dtos.ReviewResponseMetadata, error) {
+	args := m.Called(bookId, filter)
+	return args.Get(0).(dtos.ReviewResponseMetadata), args.Error(1)
+}
+
+func (m *ReviewServiceMock) GetReview(bookId, id int) (dtos.ReviewResponse, error) {
+	args := m.Called(bookId, id)
+	return args.Get(0).(dtos.ReviewResponse), args.Error(1)
+}
+
+func (m *ReviewServiceMock) UpdateReview(owner string, bookId, id int, reviewRequest dtos.ReviewRequest) (dtos.ReviewResponse, error) {
+	args := m.Called(owner, bookId, id, reviewRequest)
+	return args.Get(0).(dtos.ReviewResponse), args.Error(1)
+}
+
+func (m *ReviewServiceMock) DeleteReview(owner string, bookId, id int) error {
+	args := m.Called(owner, bookId, id)
+	return args.Error(0)
+}
@@ -0,0 +1,247 @@
+package services
+
+import (
+	"errors"
+	"github/brunojoenk/golang-test/models/dtos"
+	"github/brunojoenk/golang-test/models/entities"
+	bookrepo "github/brunojoenk/golang-test/repository/book"
+	patronrepo "github/brunojoenk/golang-test/repository/patron"
+	reviewrepo "github/brunojoenk/golang-test/repository/review"
+	"github/brunojoenk/golang-test/utils"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+const REVIEW_BOOK_PATRON_INDEX = "idx_review_book_patron"
+
+// IReviewService manages the reviews of the books. Owner is the user calling, the owner of the api key of
+// the request, and empty when the request has none; only the owner who wrote a review changes it
+type IReviewService interface {
+	CreateReview(owner string, bookId int, reviewRequest dtos.ReviewRequest) (dtos.ReviewResponse, error)
+	GetBookReviews(bookId int, filter dtos.GetReviewsFilter) (dtos.ReviewResponseMetadata, error)
+	GetReview(bookId, id int) (dtos.ReviewResponse, error)
+	UpdateReview(owner string, bookId, id int, reviewRequest dtos.ReviewRequest) (dtos.ReviewResponse, error)
+	DeleteReview(owner string, bookId, id int) error
+}
+
+type reviewService struct {
+	reviewDb reviewrepo.IReviewRepository
+	bookDb   bookrepo.IBookRepository
+	patronDb patronrepo.IPatronRepository
+}
+
+// NewReviewService Service Constructor
+func NewReviewService(db *gorm.DB) IReviewService {
+	return &reviewService{
+		reviewDb: reviewrepo.NewReviewRepository(db),
+		bookDb:   bookrepo.NewBookRepository(db),
+		patronDb: patronrepo.NewPatronRepository(db),
+	}
+}
+
+// CreateReview saves the review of the patron for the book. A patron reviews a book only once
+func (r *reviewService) CreateReview(owner string, bookId int, reviewRequest dtos.ReviewRequest) (dtos.ReviewResponse, error) {
+	if owner == "" {
+		return dtos.ReviewResponse{}, utils.ErrReviewOwnerRequired
+	}
+
+	text, err := validReview(reviewRequest)
+	if err != nil {
+		return dtos.ReviewResponse{}, err
+	}
+
+	if err := r.checkBook(bookId); err != nil {
+		return dtos.ReviewResponse{}, err
+	}
+
+	patron, err := r.patronDb.GetPatron(reviewRequest.PatronId)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return dtos.ReviewResponse{}, utils.ErrPatronIdNotFound
+		}
+		log.Error("Error on get patron from repo: ", err.Error())
+		return dtos.ReviewResponse{}, err
+	}
+
+	_, err = r.reviewDb.GetPatronReview(bookId, patron.Id)
+	if err == nil {
+		return dtos.ReviewResponse{}, utils.ErrReviewExists
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		log.Error("Error on get review of patron from repo: ", err.Error())
+		return dtos.ReviewResponse{}, err
+	}
+
+	now := time.Now()
+	review, err := r.reviewDb.CreateReview(entities.Review{
+		BookId:    bookId,
+		PatronId:  patron.Id,
+		Owner:     owner,
+		Rating:    reviewRequest.Rating,
+		Text:      text,
+		CreatedAt: now,
+		UpdatedAt: now,
+	})
+	if err != nil {
+		// A review of the patron for the book created at the same time
+		if utils.IsUniqueViolation(err, REVIEW_BOOK_PATRON_INDEX) {
+			return dtos.ReviewResponse{}, utils.ErrReviewExists
+		}
+		log.Error("Error on create review from repo: ", err.Error())
+		return dtos.ReviewResponse{}, err
+	}
+
+	review.Patron = patron
+	return toReviewResponse(review), nil
+}
+
+// GetBookReviews lists the reviews of the book, the newest first
+func (r *reviewService) GetBookReviews(bookId int, filter dtos.GetReviewsFilter) (dtos.ReviewResponseMetadata, error) {
+	if filter.Rating != 0 && (filter.Rating < dtos.MinRating || filter.Rating > dtos.MaxRating) {
+		return dtos.ReviewResponseMetadata{}, utils.ErrInvalidRating
+	}
+
+	if err := r.checkBook(bookId); err != nil {
+		return dtos.ReviewResponseMetadata{}, err
+	}
+
+	filter.Pagination.ValidValuesAndSetDefault()
+	reviews, err := r.reviewDb.GetBookReviews(bookId, filter)
+	if err != nil {
+		log.Error("Error on get reviews of book from repo: ", err.Error())
+		return dtos.ReviewResponseMetadata{}, err
+	}
+
+	reviewsResponse := make([]dtos.ReviewResponse, len(reviews))
+	for i, review := range reviews {
+		reviewsResponse[i] = toReviewResponse(review)
+	}
+
+	return dtos.ReviewResponseMetadata{Reviews: reviewsResponse, Pagination: filter.Pagination}, nil
+}
+
+func (r *reviewService) GetReview(bookId, id int) (dtos.ReviewResponse, error) {
+	review, err := r.getReview(bookId, id)
+	if err != nil {
+		return dtos.ReviewResponse{}, err
+	}
+
+	return toReviewResponse(review), nil
+}
+
+// UpdateReview changes the rating and text of the review
+func (r *reviewService) UpdateReview(owner string, bookId, id int, reviewRequest dtos.ReviewRequest) (dtos.ReviewResponse, error) {
+	text, err := validReview(reviewRequest)
+	if err != nil {
+		return dtos.ReviewResponse{}, err
+	}
+
+	review, err := r.getOwnReview(owner, bookId, id)
+	if err != nil {
+		return dtos.ReviewResponse{}, err
+	}
+	if reviewRequest.PatronId != 0 && reviewRequest.PatronId != review.PatronId {
+		return dtos.ReviewResponse{}, utils.ErrReviewPatron
+	}
+
+	review.Rating = reviewRequest.Rating
+	review.Text = text
+	review.UpdatedAt = time.Now()
+	updated, err := r.reviewDb.UpdateReview(review)
+	if err != nil {
+		log.Error("Error on update review from repo: ", err.Error())
+		return dtos.ReviewResponse{}, err
+	}
+
+	return toReviewResponse(updated), nil
+}
+
+func (r *reviewService) DeleteReview(owner string, bookId, id int) error {
+	review, err := r.getOwnReview(owner, bookId, id)
+	if err != nil {
+		return err
+	}
+
+	if err := r.reviewDb.DeleteReview(review); err != nil {
+		log.Error("Error on delete review from repo: ", err.Error())
+		return err
+	}
+
+	return nil
+}
+
+// getReview returns the review when it is of the book
+func (r *reviewService) getReview(bookId, id int) (entities.Review, error) {
+	review, err := r.reviewDb.GetReview(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return entities.Review{}, utils.ErrReviewIdNotFound
+		}
+		log.Error("Error on get review from repo: ", err.Error())
+		return entities.Review{}, err
+	}
+	if review.BookId != bookId {
+		return entities.Review{}, utils.ErrReviewIdNotFound
+	}
+
+	return review, nil
+}
+
+// getOwnReview returns the review of the book when the owner wrote it
+func (r *reviewService) getOwnReview(owner string, bookId, id int) (entities.Review, error) {
+	if owner == "" {
+		return entities.Review{}, utils.ErrReviewOwnerRequired
+	}
+
+	review, err := r.getReview(bookId, id)
+	if err != nil {
+		return entities.Review{}, err
+	}
+	if review.Owner != owner {
+		return entities.Review{}, utils.ErrReviewNotOwner
+	}
+
+	return review, nil
+}
+
+func (r *reviewService) checkBook(bookId int) error {
+	if _, err := r.bookDb.GetBook(bookId); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return utils.ErrBookIdNotFound
+		}
+		log.Error("Error on get book from repo: ", err.Error())
+		return err
+	}
+	return nil
+}
+
+// validReview checks the rating and length of the review and returns its trimmed text
+func validReview(reviewRequest dtos.ReviewRequest) (string, error) {
+	if reviewRequest.Rating < dtos.MinRating || reviewRequest.Rating > dtos.MaxRating {
+		return "", utils.ErrInvalidRating
+	}
+
+	text := strings.TrimSpace(reviewRequest.Text)
+	if utf8.RuneCountInString(text) > dtos.ReviewTextMaxLength {
+		return "", utils.ErrReviewTextTooLong
+	}
+
+	return text, nil
+}
+
+func toReviewResponse(review entities.Review) dtos.ReviewResponse {
+	return dtos.ReviewResponse{
+		Id:        review.Id,
+		BookId:    review.BookId,
+		PatronId:  review.PatronId,
+		Reviewer:  review.Patron.Name,
+		Rating:    review.Rating,
+		Text:      review.Text,
+		CreatedAt: review.CreatedAt,
+		UpdatedAt: review.UpdatedAt,
+	}
+}
@@ -0,0 +1,299 @@
+package services
+
+import (
+	"errors"
+	"github/brunojoenk/golang-test/models/dtos"
+	"github/brunojoenk/golang-test/models/entities"
+	bookrepomock "github/brunojoenk/golang-test/repository/book/mock"
+	patronrepomock "github/brunojoenk/golang-test/repository/patron/mock"
+	reviewrepomock "github/brunojoenk/golang-test/repository/review/mock"
+	"github/brunojoenk/golang-test/utils"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgconn"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+var errGeneric = errors.New("generic error")
+
+func TestCreateReview(t *testing.T) {
+	tests := map[string]struct {
+		owner                       string
+		request                     dtos.ReviewRequest
+		expectedErrorOnGetBook      error
+		expectedErrorOnGetPatron    error
+		expectedErrorOnPatronReview error
+		expectedErrorOnCreate       error
+		expectedErrorResponse       error
+	}{
+		"success on create review": {
+			owner:                       "ana",
+			request:                     dtos.ReviewRequest{PatronId: 10, Rating: 4, Text: "  Great read  "},
+			expectedErrorOnPatronReview: gorm.ErrRecordNotFound,
+		},
+		"error occurred on create review (no api key)": {
+			request:               dtos.ReviewRequest{PatronId: 10, Rating: 4},
+			expectedErrorResponse: utils.ErrReviewOwnerRequired,
+		},
+		"error occurred on create review (invalid rating)": {
+			owner:                 "ana",
+			request:               dtos.ReviewRequest{PatronId: 10, Rating: 6},
+			expectedErrorResponse: utils.ErrInvalidRating,
+		},
+		"error occurred on create review (text too long)": {
+			owner:                 "ana",
+			request:               dtos.ReviewRequest{PatronId: 10, Rating: 3, Text: strings.Repeat("é", dtos.ReviewTextMaxLength+1)},
+			expectedErrorResponse: utils.ErrReviewTextTooLong,
+		},
+		"error occurred on create review (book not found)": {
+			owner:                  "ana",
+			request:                dtos.ReviewRequest{PatronId: 10, Rating: 3},
+			expectedErrorOnGetBook: gorm.ErrRecordNotFound,
+			expectedErrorResponse:  utils.ErrBookIdNotFound,
+		},
+		"error occurred on create review (patron not found)": {
+			owner:                    "ana",
+			request:                  dtos.ReviewRequest{PatronId: 10, Rating: 3},
+			expectedErrorOnGetPatron: gorm.ErrRecordNotFound,
+			expectedErrorResponse:    utils.ErrPatronIdNotFound,
+		},
+		"error occurred on create review (already reviewed)": {
+			owner:                 "ana",
+			request:               dtos.ReviewRequest{PatronId: 10, Rating: 3},
+			expectedErrorResponse: utils.ErrReviewExists,
+		},
+		"error occurred on create review (reviewed at the same time)": {
+			owner:                       "ana",
+			request:                     dtos.ReviewRequest{PatronId: 10, Rating: 3},
+			expectedErrorOnPatronReview: gorm.ErrRecordNotFound,
+			expectedErrorOnCreate:       &pgconn.PgError{Code: "23505", ConstraintName: REVIEW_BOOK_PATRON_INDEX},
+			expectedErrorResponse:       utils.ErrReviewExists,
+		},
+		"error occurred on create review (generic error)": {
+			owner:                       "ana",
+			request:                     dtos.ReviewRequest{PatronId: 10, Rating: 3},
+			expectedErrorOnPatronReview: gorm.ErrRecordNotFound,
+			expectedErrorOnCreate:       errGeneric,
+			expectedErrorResponse:       errGeneric,
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			bookDbMock := new(bookrepomock.BookRepositoryMock)
+			bookDbMock.On("GetBook", 1).Return(entities.Book{Id: 1}, tc.expectedErrorOnGetBook)
+			patronDbMock := new(patronrepomock.PatronRepositoryMock)
+			patronDbMock.On("GetPatron", 10).Return(entities.Patron{Id: 10, Name: "Ana"}, tc.expectedErrorOnGetPatron)
+			reviewDbMock := new(reviewrepomock.ReviewRepositoryMock)
+			reviewDbMock.On("GetPatronReview", 1, 10).Return(entities.Review{Id: 7}, tc.expectedErrorOnPatronReview)
+			reviewDbMock.On("CreateReview", mock.MatchedBy(func(review entities.Review) bool {
+				return review.BookId == 1 && review.PatronId == 10 && review.Owner == "ana" && review.Text == strings.TrimSpace(tc.request.Text)
+			})).Return(entities.Review{Id: 8, BookId: 1, PatronId: 10, Rating: tc.request.Rating, Text: "Great read"}, tc.expectedErrorOnCreate)
+
+			reviewServiceTest := reviewService{reviewDb: reviewDbMock, bookDb: bookDbMock, patronDb: patronDbMock}
+			resp, err := reviewServiceTest.CreateReview(tc.owner, 1, tc.request)
+			if tc.expectedErrorResponse != nil {
+				require.ErrorIs(t, err, tc.expectedErrorResponse)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, 8, resp.Id)
+			require.Equal(t, "Ana", resp.Reviewer)
+			require.Equal(t, "Great read", resp.Text)
+			require.Equal(t, 4, resp.Rating)
+		})
+	}
+}
+
+func TestGetBookReviews(t *testing.T) {
+	tests := map[string]struct {
+		filter                 dtos.GetReviewsFilter
+		expectedErrorOnGetBook error
+		expectedErrorOnGet     error
+		expectedErrorResponse  error
+	}{
+		"success on get reviews of book": {
+			filter: dtos.GetReviewsFilter{Rating: 5},
+		},
+		"error occurred on get reviews of book (invalid rating)": {
+			filter:                dtos.GetReviewsFilter{Rating: 9},
+			expectedErrorResponse: utils.ErrInvalidRating,
+		},
+		"error occurred on get reviews of book (book not found)": {
+			expectedErrorOnGetBook: gorm.ErrRecordNotFound,
+			expectedErrorResponse:  utils.ErrBookIdNotFound,
+		},
+		"error occurred on get reviews of book (generic error)": {
+			expectedErrorOnGet:    errGeneric,
+			expectedErrorResponse: errGeneric,
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			bookDbMock := new(bookrepomock.BookRepositoryMock)
+			bookDbMock.On("GetBook", 1).Return(entities.Book{Id: 1}, tc.expectedErrorOnGetBook)
+			expectedFilter := tc.filter
+			expectedFilter.Pagination = dtos.Pagination{Page: 1, Limit: 10}
+			reviewDbMock := new(reviewrepomock.ReviewRepositoryMock)
+			reviewDbMock.On("GetBookReviews", 1, expectedFilter).Return([]entities.Review{
+				{Id: 8, BookId: 1, PatronId: 10, Rating: 5, Patron: entities.Patron{Id: 10, Name: "Ana"}},
+			}, tc.expectedErrorOnGet)
+
+			reviewServiceTest := reviewService{reviewDb: reviewDbMock, bookDb: bookDbMock}
+			resp, err := reviewServiceTest.GetBookReviews(1, tc.filter)
+			if tc.expectedErrorResponse != nil {
+				require.ErrorIs(t, err, tc.expectedErrorResponse)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, resp.Reviews, 1)
+			require.Equal(t, "Ana", resp.Reviews[0].Reviewer)
+			require.Equal(t, expectedFilter.Pagination, resp.Pagination)
+		})
+	}
+}
+
+func TestUpdateReview(t *testing.T) {
+	createdAt := time.Date(2022, 10, 1, 9, 0, 0, 0, time.UTC)
+	tests := map[string]struct {
+		owner                 string
+		bookId                int
+		request               dtos.ReviewRequest
+		expectedErrorOnGet    error
+		expectedErrorOnUpdate error
+		expectedErrorResponse error
+	}{
+		"success on update review": {
+			owner:   "ana",
+			bookId:  1,
+			request: dtos.ReviewRequest{Rating: 2, Text: "Changed my mind"},
+		},
+		"error occurred on update review (no api key)": {
+			bookId:                1,
+			request:               dtos.ReviewRequest{Rating: 2},
+			expectedErrorResponse: utils.ErrReviewOwnerRequired,
+		},
+		"error occurred on update review (review of someone else)": {
+			owner:                 "bob",
+			bookId:                1,
+			request:               dtos.ReviewRequest{Rating: 2},
+			expectedErrorResponse: utils.ErrReviewNotOwner,
+		},
+		"success on update review (same patron)": {
+			owner:   "ana",
+			bookId:  1,
+			request: dtos.ReviewRequest{PatronId: 10, Rating: 2},
+		},
+		"error occurred on update review (invalid rating)": {
+			owner:                 "ana",
+			bookId:                1,
+			request:               dtos.ReviewRequest{Rating: 0},
+			expectedErrorResponse: utils.ErrInvalidRating,
+		},
+		"error occurred on update review (not found)": {
+			owner:                 "ana",
+			bookId:                1,
+			request:               dtos.ReviewRequest{Rating: 2},
+			expectedErrorOnGet:    gorm.ErrRecordNotFound,
+			expectedErrorResponse: utils.ErrReviewIdNotFound,
+		},
+		"error occurred on update review (review of another book)": {
+			owner:                 "ana",
+			bookId:                2,
+			request:               dtos.ReviewRequest{Rating: 2},
+			expectedErrorResponse: utils.ErrReviewIdNotFound,
+		},
+		"error occurred on update review (another patron)": {
+			owner:                 "ana",
+			bookId:                1,
+			request:               dtos.ReviewRequest{PatronId: 11, Rating: 2},
+			expectedErrorResponse: utils.ErrReviewPatron,
+		},
+		"error occurred on update review (generic error)": {
+			owner:                 "ana",
+			bookId:                1,
+			request:               dtos.ReviewRequest{Rating: 2},
+			expectedErrorOnUpdate: errGeneric,
+			expectedErrorResponse: errGeneric,
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			reviewDbMock := new(reviewrepomock.ReviewRepositoryMock)
+			reviewDbMock.On("GetReview", 8).Return(entities.Review{Id: 8, BookId: 1, PatronId: 10, Owner: "ana", Rating: 5, CreatedAt: createdAt, UpdatedAt: createdAt}, tc.expectedErrorOnGet)
+			var updated entities.Review
+			reviewDbMock.On("UpdateReview", mock.AnythingOfType("entities.Review")).Run(func(args mock.Arguments) {
+				updated = args.Get(0).(entities.Review)
+			}).Return(entities.Review{}, tc.expectedErrorOnUpdate)
+
+			reviewServiceTest := reviewService{reviewDb: reviewDbMock}
+			_, err := reviewServiceTest.UpdateReview(tc.owner, tc.bookId, 8, tc.request)
+			if tc.expectedErrorResponse != nil {
+				require.ErrorIs(t, err, tc.expectedErrorResponse)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.request.Rating, updated.Rating)
+			require.Equal(t, tc.request.Text, updated.Text)
+			require.Equal(t, 10, updated.PatronId)
+			require.Equal(t, createdAt, updated.CreatedAt)
+			require.True(t, updated.UpdatedAt.After(createdAt))
+		})
+	}
+}
+
+func TestDeleteReview(t *testing.T) {
+	tests := map[string]struct {
+		owner                 string
+		bookId                int
+		expectedErrorOnGet    error
+		expectedErrorOnDelete error
+		expectedErrorResponse error
+	}{
+		"success on delete review": {
+			owner:  "ana",
+			bookId: 1,
+		},
+		"error occurred on delete review (no api key)": {
+			bookId:                1,
+			expectedErrorResponse: utils.ErrReviewOwnerRequired,
+		},
+		"error occurred on delete review (review of someone else)": {
+			owner:                 "bob",
+			bookId:                1,
+			expectedErrorResponse: utils.ErrReviewNotOwner,
+		},
+		"error occurred on delete review (not found)": {
+			owner:                 "ana",
+			bookId:                1,
+			expectedErrorOnGet:    gorm.ErrRecordNotFound,
+			expectedErrorResponse: utils.ErrReviewIdNotFound,
+		},
+		"error occurred on delete review (review of another book)": {
+			owner:                 "ana",
+			bookId:                2,
+			expectedErrorResponse: utils.ErrReviewIdNotFound,
+		},
+		"error occurred on delete review (generic error)": {
+			owner:                 "ana",
+			bookId:                1,
+			expectedErrorOnDelete: errGeneric,
+			expectedErrorResponse: errGeneric,
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			review := entities.Review{Id: 8, BookId: 1, PatronId: 10, Owner: "ana", Rating: 5}
+			reviewDbMock := new(reviewrepomock.ReviewRepositoryMock)
+			reviewDbMock.On("GetReview", 8).Return(review, tc.expectedErrorOnGet)
+			reviewDbMock.On("DeleteReview", review).Return(tc.expectedErrorOnDelete)
+
+			reviewServiceTest := reviewService{reviewDb: reviewDbMock}
+			err := reviewServiceTest.DeleteReview(tc.owner, tc.bookId, 8)
+			require.ErrorIs(t, err, tc.expectedErrorResponse)
+		})
+	}
+}
@@ -0,0 +1,36 @@
+package services
+
+import (
+	"github/brunojoenk/golang-test/models/dtos"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type SeriesServiceMock struct {
+	mock.Mock
+}
+
+func (m *SeriesServiceMock) CreateSeries(seriesRequest dtos.SeriesRequest) (dtos.SeriesResponse, error) {
+	args := m.Called(seriesRequest)
+	return args.Get(0).(dtos.SeriesResponse), args.Error(1)
+}
+
+func (m *SeriesServiceMock) GetAllSeries(filter dtos.GetSeriesFilter) (dtos.SeriesResponseMetadata, error) {
+	args := m.Called(filter)
+	return args.Get(0).(dtos.SeriesResponseMetadata), args.Error(1)
+}
+
+func (m *SeriesServiceMock) GetSeries(id int) (dtos.SeriesResponse, error) {
+	args := m.Called(id)
+	return args.Get(0).(dtos.SeriesResponse), args.Error(1)
+}
+
+func (m *SeriesServiceMock) UpdateSeries(id int, seriesRequest dtos.SeriesRequest) (dtos.SeriesResponse, error) {
+	args := m.Called(id, seriesRequest)
+	return args.Get(0).(dtos.SeriesResponse), args.Error(1)
+}
+
+func (m *SeriesServiceMock) DeleteSeries(id int) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
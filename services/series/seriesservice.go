@@ -0,0 +1,177 @@
+package services
+
+import (
+	"errors"
+	"github/brunojoenk/golang-test/models/dtos"
+	"github/brunojoenk/golang-test/models/entities"
+	bookrepo "github/brunojoenk/golang-test/repository/book"
+	seriesrepo "github/brunojoenk/golang-test/repository/series"
+	bookservice "github/brunojoenk/golang-test/services/book"
+	"github/brunojoenk/golang-test/utils"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type ISeriesService interface {
+	CreateSeries(seriesRequest dtos.SeriesRequest) (dtos.SeriesResponse, error)
+	GetAllSeries(filter dtos.GetSeriesFilter) (dtos.SeriesResponseMetadata, error)
+	GetSeries(id int) (dtos.SeriesResponse, error)
+	UpdateSeries(id int, seriesRequest dtos.SeriesRequest) (dtos.SeriesResponse, error)
+	DeleteSeries(id int) error
+}
+
+type seriesService struct {
+	seriesDb seriesrepo.ISeriesRepository
+	bookDb   bookrepo.IBookRepository
+}
+
+// NewSeriesService Service Constructor
+func NewSeriesService(db *gorm.DB) ISeriesService {
+	return &seriesService{
+		seriesDb: seriesrepo.NewSeriesRepository(db),
+		bookDb:   bookrepo.NewBookRepository(db),
+	}
+}
+
+func (s *seriesService) CreateSeries(seriesRequest dtos.SeriesRequest) (dtos.SeriesResponse, error) {
+	var series entities.Series
+	if err := s.setFields(&series, seriesRequest); err != nil {
+		return dtos.SeriesResponse{}, err
+	}
+
+	series, err := s.seriesDb.CreateSeries(series)
+	if err != nil {
+		log.Error("Error on create series from repo: ", err.Error())
+		return dtos.SeriesResponse{}, err
+	}
+
+	return toSeriesResponse(series), nil
+}
+
+func (s *seriesService) GetAllSeries(filter dtos.GetSeriesFilter) (dtos.SeriesResponseMetadata, error) {
+
+	filter.Pagination.ValidValuesAndSetDefault()
+	series, err := s.seriesDb.GetAllSeries(filter)
+	if err != nil {
+		log.Error("Error on get all series from repo: ", err.Error())
+		return dtos.SeriesResponseMetadata{}, err
+	}
+
+	seriesResponse := make([]dtos.SeriesResponse, len(series))
+	for i, one := range series {
+		seriesResponse[i] = toSeriesResponse(one)
+	}
+
+	return dtos.SeriesResponseMetadata{
+		Series:     seriesResponse,
+		Pagination: filter.Pagination,
+	}, nil
+}
+
+// GetSeries returns the series with its books in reading order
+func (s *seriesService) GetSeries(id int) (dtos.SeriesResponse, error) {
+	series, err := s.getSeries(id)
+	if err != nil {
+		return dtos.SeriesResponse{}, err
+	}
+
+	books, err := s.bookDb.GetSeriesBooks(id)
+	if err != nil {
+		log.Error("Error on get books of series from repo: ", err.Error())
+		return dtos.SeriesResponse{}, err
+	}
+
+	seriesResponse := toSeriesResponse(series)
+	seriesResponse.Books = make([]dtos.BookResponse, len(books))
+	for i, book := range books {
+		seriesResponse.Books[i] = bookservice.ToBookResponse(book)
+	}
+
+	return seriesResponse, nil
+}
+
+func (s *seriesService) UpdateSeries(id int, seriesRequest dtos.SeriesRequest) (dtos.SeriesResponse, error) {
+	series, err := s.getSeries(id)
+	if err != nil {
+		return dtos.SeriesResponse{}, err
+	}
+
+	if err := s.setFields(&series, seriesRequest); err != nil {
+		return dtos.SeriesResponse{}, err
+	}
+
+	series, err = s.seriesDb.UpdateSeries(series)
+	if err != nil {
+		log.Error("Error on update series from repo: ", err.Error())
+		return dtos.SeriesResponse{}, err
+	}
+
+	return toSeriesResponse(series), nil
+}
+
+// DeleteSeries deletes a series without books, so no book loses its position silently
+func (s *seriesService) DeleteSeries(id int) error {
+	if _, err := s.getSeries(id); err != nil {
+		return err
+	}
+
+	books, err := s.seriesDb.CountBooks(id)
+	if err != nil {
+		log.Error("Error on count books of series from repo: ", err.Error())
+		return err
+	}
+	if books > 0 {
+		return utils.ErrSeriesHasBooks
+	}
+
+	if err := s.seriesDb.DeleteSeries(id); err != nil {
+		log.Error("Error on delete series from repo: ", err.Error())
+		return err
+	}
+
+	return nil
+}
+
+// setFields validates the request and copies it to the series. The name must be unique
+// ignoring case, accents and punctuation
+func (s *seriesService) setFields(series *entities.Series, seriesRequest dtos.SeriesRequest) error {
+	name := strings.TrimSpace(seriesRequest.Name)
+	normalizedName := utils.NormalizeName(name)
+	if normalizedName == "" {
+		return utils.ErrSeriesNameEmpty
+	}
+
+	existing, err := s.seriesDb.GetSeriesByNormalizedName(normalizedName)
+	if err == nil && existing.Id != series.Id {
+		return utils.ErrSeriesNameExists
+	}
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		log.Error("Error on get series by name from repo: ", err.Error())
+		return err
+	}
+
+	series.Name = name
+	series.NormalizedName = normalizedName
+	return nil
+}
+
+func (s *seriesService) getSeries(id int) (entities.Series, error) {
+	series, err := s.seriesDb.GetSeries(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return entities.Series{}, utils.ErrSeriesIdNotFound
+		}
+		log.Error("Error on get series from repo: ", err.Error())
+		return entities.Series{}, err
+	}
+	return series, nil
+}
+
+func toSeriesResponse(series entities.Series) dtos.SeriesResponse {
+	return dtos.SeriesResponse{
+		Id:   series.Id,
+		Name: series.Name,
+	}
+}
@@ -0,0 +1,199 @@
+package services
+
+import (
+	"errors"
+	"github/brunojoenk/golang-test/models/dtos"
+	"github/brunojoenk/golang-test/models/entities"
+	bookrepomock "github/brunojoenk/golang-test/repository/book/mock"
+	seriesrepomock "github/brunojoenk/golang-test/repository/series/mock"
+	"github/brunojoenk/golang-test/utils"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+var errGeneric = errors.New("generic error")
+
+func TestCreateSeries(t *testing.T) {
+	tests := map[string]struct {
+		request                  dtos.SeriesRequest
+		existing                 entities.Series
+		expectedErrorOnGetByName error
+		expectedSeries           entities.Series
+		expectedErrorOnCreate    error
+		expectedErrorResponse    error
+	}{
+		"success on create series": {
+			request:                  dtos.SeriesRequest{Name: " The Lord of the Rings "},
+			expectedErrorOnGetByName: gorm.ErrRecordNotFound,
+			expectedSeries:           entities.Series{Name: "The Lord of the Rings", NormalizedName: "the lord of the rings"},
+		},
+		"error occurred on create series (empty name)": {
+			request:               dtos.SeriesRequest{Name: " : "},
+			expectedErrorResponse: utils.ErrSeriesNameEmpty,
+		},
+		"error occurred on create series (name exists)": {
+			request:               dtos.SeriesRequest{Name: "the lord of the rings"},
+			existing:              entities.Series{Id: 3},
+			expectedErrorResponse: utils.ErrSeriesNameExists,
+		},
+		"error occurred on create series (get by name)": {
+			request:                  dtos.SeriesRequest{Name: "The Lord of the Rings"},
+			expectedErrorOnGetByName: errGeneric,
+			expectedErrorResponse:    errGeneric,
+		},
+		"error occurred on create series (create)": {
+			request:                  dtos.SeriesRequest{Name: "The Lord of the Rings"},
+			expectedErrorOnGetByName: gorm.ErrRecordNotFound,
+			expectedSeries:           entities.Series{Name: "The Lord of the Rings", NormalizedName: "the lord of the rings"},
+			expectedErrorOnCreate:    errGeneric,
+			expectedErrorResponse:    errGeneric,
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			seriesDbMock := new(seriesrepomock.SeriesRepositoryMock)
+			seriesDbMock.On("GetSeriesByNormalizedName", utils.NormalizeName(tc.request.Name)).Return(tc.existing, tc.expectedErrorOnGetByName)
+			created := tc.expectedSeries
+			created.Id = 1
+			seriesDbMock.On("CreateSeries", tc.expectedSeries).Return(created, tc.expectedErrorOnCreate)
+
+			seriesServiceTest := seriesService{seriesDb: seriesDbMock}
+			resp, err := seriesServiceTest.CreateSeries(tc.request)
+			if tc.expectedErrorResponse != nil {
+				require.ErrorIs(t, err, tc.expectedErrorResponse)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, dtos.SeriesResponse{Id: 1, Name: "The Lord of the Rings"}, resp)
+			}
+		})
+	}
+}
+
+func TestGetAllSeries(t *testing.T) {
+	seriesDbMock := new(seriesrepomock.SeriesRepositoryMock)
+	seriesDbMock.On("GetAllSeries", dtos.GetSeriesFilter{Name: "rings", Pagination: dtos.Pagination{Page: 1, Limit: 10}}).
+		Return([]entities.Series{{Id: 1, Name: "The Lord of the Rings"}}, nil)
+	seriesDbMock.On("GetAllSeries", dtos.GetSeriesFilter{Name: "error", Pagination: dtos.Pagination{Page: 1, Limit: 10}}).
+		Return([]entities.Series{}, errGeneric)
+
+	seriesServiceTest := seriesService{seriesDb: seriesDbMock}
+	resp, err := seriesServiceTest.GetAllSeries(dtos.GetSeriesFilter{Name: "rings"})
+
+	require.NoError(t, err)
+	require.Equal(t, []dtos.SeriesResponse{{Id: 1, Name: "The Lord of the Rings"}}, resp.Series)
+
+	_, err = seriesServiceTest.GetAllSeries(dtos.GetSeriesFilter{Name: "error"})
+	require.ErrorIs(t, err, errGeneric)
+}
+
+func TestGetSeries(t *testing.T) {
+	var (
+		series  = entities.Series{Id: 4, Name: "The Lord of the Rings"}
+		first   = 1.0
+		novella = 1.5
+	)
+	tests := map[string]struct {
+		expectedErrorOnGet      error
+		expectedErrorOnGetBooks error
+		expectedErrorResponse   error
+	}{
+		"success on get series": {},
+		"error occurred on get series (not found)": {
+			expectedErrorOnGet:    gorm.ErrRecordNotFound,
+			expectedErrorResponse: utils.ErrSeriesIdNotFound,
+		},
+		"error occurred on get series (get books)": {
+			expectedErrorOnGetBooks: errGeneric,
+			expectedErrorResponse:   errGeneric,
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			seriesDbMock := new(seriesrepomock.SeriesRepositoryMock)
+			seriesDbMock.On("GetSeries", 4).Return(series, tc.expectedErrorOnGet)
+
+			bookDbMock := new(bookrepomock.BookRepositoryMock)
+			bookDbMock.On("GetSeriesBooks", 4).Return([]entities.Book{
+				{Id: 1, Name: "The Fellowship of the Ring", SeriesId: &series.Id, Series: &series, SeriesPosition: &first},
+				{Id: 3, Name: "The Adventures of Tom Bombadil", SeriesId: &series.Id, Series: &series, SeriesPosition: &novella},
+				{Id: 7, Name: "The Lord of the Rings Sketchbook", SeriesId: &series.Id, Series: &series},
+			}, tc.expectedErrorOnGetBooks)
+
+			seriesServiceTest := seriesService{seriesDb: seriesDbMock, bookDb: bookDbMock}
+			resp, err := seriesServiceTest.GetSeries(4)
+			if tc.expectedErrorResponse != nil {
+				require.ErrorIs(t, err, tc.expectedErrorResponse)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, dtos.SeriesResponse{Id: 4, Name: "The Lord of the Rings", Books: []dtos.BookResponse{
+					{Id: 1, Name: "The Fellowship of the Ring", SeriesId: 4, Series: "The Lord of the Rings", SeriesPosition: 1},
+					{Id: 3, Name: "The Adventures of Tom Bombadil", SeriesId: 4, Series: "The Lord of the Rings", SeriesPosition: 1.5},
+					{Id: 7, Name: "The Lord of the Rings Sketchbook", SeriesId: 4, Series: "The Lord of the Rings"},
+				}}, resp)
+			}
+		})
+	}
+}
+
+func TestUpdateSeriesKeepsItsOwnName(t *testing.T) {
+	series := entities.Series{Id: 1, Name: "Discworld", NormalizedName: "discworld"}
+	updated := entities.Series{Id: 1, Name: "DISCWORLD", NormalizedName: "discworld"}
+
+	seriesDbMock := new(seriesrepomock.SeriesRepositoryMock)
+	seriesDbMock.On("GetSeries", 1).Return(series, nil)
+	seriesDbMock.On("GetSeriesByNormalizedName", "discworld").Return(series, nil)
+	seriesDbMock.On("UpdateSeries", updated).Return(updated, nil)
+
+	seriesServiceTest := seriesService{seriesDb: seriesDbMock}
+	resp, err := seriesServiceTest.UpdateSeries(1, dtos.SeriesRequest{Name: "DISCWORLD"})
+
+	require.NoError(t, err)
+	require.Equal(t, "DISCWORLD", resp.Name)
+}
+
+func TestDeleteSeries(t *testing.T) {
+	tests := map[string]struct {
+		expectedErrorOnGet    error
+		books                 int64
+		expectedErrorOnCount  error
+		expectedErrorOnDelete error
+		expectedErrorResponse error
+	}{
+		"success on delete series": {},
+		"error occurred on delete series (not found)": {
+			expectedErrorOnGet:    gorm.ErrRecordNotFound,
+			expectedErrorResponse: utils.ErrSeriesIdNotFound,
+		},
+		"error occurred on delete series (has books)": {
+			books:                 2,
+			expectedErrorResponse: utils.ErrSeriesHasBooks,
+		},
+		"error occurred on delete series (count books)": {
+			expectedErrorOnCount:  errGeneric,
+			expectedErrorResponse: errGeneric,
+		},
+		"error occurred on delete series (delete)": {
+			expectedErrorOnDelete: errGeneric,
+			expectedErrorResponse: errGeneric,
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			seriesDbMock := new(seriesrepomock.SeriesRepositoryMock)
+			seriesDbMock.On("GetSeries", 1).Return(entities.Series{Id: 1}, tc.expectedErrorOnGet)
+			seriesDbMock.On("CountBooks", 1).Return(tc.books, tc.expectedErrorOnCount)
+			seriesDbMock.On("DeleteSeries", 1).Return(tc.expectedErrorOnDelete)
+
+			seriesServiceTest := seriesService{seriesDb: seriesDbMock}
+			err := seriesServiceTest.DeleteSeries(1)
+			if tc.expectedErrorResponse != nil {
+				require.ErrorIs(t, err, tc.expectedErrorResponse)
+			} else {
+				require.NoError(t, err)
+				seriesDbMock.AssertCalled(t, "DeleteSeries", 1)
+			}
+		})
+	}
+}
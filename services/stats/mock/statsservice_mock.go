@@ -0,0 +1,36 @@
+package services
+
+import (
+	"github/brunojoenk/golang-test/models/dtos"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type StatsServiceMock struct {
+	mock.Mock
+}
+
+func (m *StatsServiceMock) GetPublicationYearStats(filter dtos.GetPublicationYearStatsFilter) (dtos.PublicationYearStatsResponse, error) {
+	args := m.Called(filter)
+	return args.Get(0).(dtos.PublicationYearStatsResponse), args.Error(1)
+}
+
+func (m *StatsServiceMock) GetProlificAuthors(pagination dtos.Pagination) (dtos.ProlificAuthorsResponse, error) {
+	args := m.Called(pagination)
+	return args.Get(0).(dtos.ProlificAuthorsResponse), args.Error(1)
+}
+
+func (m *StatsServiceMock) GetBooksWithoutAuthors(pagination dtos.Pagination) (dtos.BooksWithoutAuthorsResponse, error) {
+	args := m.Called(pagination)
+	return args.Get(0).(dtos.BooksWithoutAuthorsResponse), args.Error(1)
+}
+
+func (m *StatsServiceMock) GetAuthorsWithoutBooks(pagination dtos.Pagination) (dtos.AuthorsWithoutBooksResponse, error) {
+	args := m.Called(pagination)
+	return args.Get(0).(dtos.AuthorsWithoutBooksResponse), args.Error(1)
+}
+
+func (m *StatsServiceMock) GetImportStats(filter dtos.GetImportStatsFilter) (dtos.ImportStatsResponse, error) {
+	args := m.Called(filter)
+	return args.Get(0).(dtos.ImportStatsResponse), args.Error(1)
+}
@@ -0,0 +1,55 @@
+package services
+
+import (
+	"sync"
+	"time"
+)
+
+type cacheEntry struct {
+	value     interface{}
+	expiresAt time.Time
+}
+
+// statsCache keeps each computed statistic in memory for ttl. A cache with no ttl keeps nothing, so every
+// request hits the database
+type statsCache struct {
+	ttl     time.Duration
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+	now     func() time.Time
+}
+
+func newStatsCache(ttl time.Duration) *statsCache {
+	return &statsCache{ttl: ttl, entries: make(map[string]cacheEntry), now: time.Now}
+}
+
+func (c *statsCache) get(key string) (interface{}, bool) {
+	if c.ttl <= 0 {
+		return nil, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok || !c.now().Before(entry.expiresAt) {
+		delete(c.entries, key)
+		return nil, false
+	}
+	return entry.value, true
+}
+
+func (c *statsCache) set(key string, value interface{}) {
+	if c.ttl <= 0 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	for k, entry := range c.entries {
+		if !now.Before(entry.expiresAt) {
+			delete(c.entries, k)
+		}
+	}
+	c.entries[key] = cacheEntry{value: value, expiresAt: now.Add(c.ttl)}
+}
@@ -0,0 +1,161 @@
+package services
+
+import (
+	"fmt"
+	"github/brunojoenk/golang-test/config"
+	"github/brunojoenk/golang-test/models/dtos"
+	statsrepo "github/brunojoenk/golang-test/repository/stats"
+	"github/brunojoenk/golang-test/utils"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type IStatsService interface {
+	GetPublicationYearStats(filter dtos.GetPublicationYearStatsFilter) (dtos.PublicationYearStatsResponse, error)
+	GetProlificAuthors(pagination dtos.Pagination) (dtos.ProlificAuthorsResponse, error)
+	GetBooksWithoutAuthors(pagination dtos.Pagination) (dtos.BooksWithoutAuthorsResponse, error)
+	GetAuthorsWithoutBooks(pagination dtos.Pagination) (dtos.AuthorsWithoutBooksResponse, error)
+	GetImportStats(filter dtos.GetImportStatsFilter) (dtos.ImportStatsResponse, error)
+}
+
+type statsService struct {
+	statsDb statsrepo.IStatsRepository
+	cache   *statsCache
+}
+
+// NewStatsService Service Constructor
+func NewStatsService(db *gorm.DB, cfg *config.Config) IStatsService {
+	return &statsService{
+		statsDb: statsrepo.NewStatsRepository(db),
+		cache:   newStatsCache(time.Duration(cfg.StatsCacheSeconds) * time.Second),
+	}
+}
+
+var bucketSizes = map[string]int{
+	dtos.StatsBucketYear:   1,
+	dtos.StatsBucketDecade: 10,
+}
+
+var importIntervals = map[string]bool{
+	dtos.StatsIntervalDay:   true,
+	dtos.StatsIntervalWeek:  true,
+	dtos.StatsIntervalMonth: true,
+	dtos.StatsIntervalYear:  true,
+}
+
+// GetPublicationYearStats counts the books per publication year, or per decade, the oldest first
+func (s *statsService) GetPublicationYearStats(filter dtos.GetPublicationYearStatsFilter) (dtos.PublicationYearStatsResponse, error) {
+	bucket := strings.ToLower(strings.TrimSpace(filter.Bucket))
+	if bucket == "" {
+		bucket = dtos.StatsBucketYear
+	}
+	bucketSize, ok := bucketSizes[bucket]
+	if !ok {
+		return dtos.PublicationYearStatsResponse{}, utils.ErrInvalidStatsBucket
+	}
+
+	key := "years:" + bucket
+	if cached, ok := s.cache.get(key); ok {
+		return cached.(dtos.PublicationYearStatsResponse), nil
+	}
+
+	years, err := s.statsDb.GetPublicationYearCounts(bucketSize)
+	if err != nil {
+		log.Error("Error on get publication year counts from repo: ", err.Error())
+		return dtos.PublicationYearStatsResponse{}, err
+	}
+
+	stats := dtos.PublicationYearStatsResponse{Bucket: bucket, Years: years}
+	s.cache.set(key, stats)
+	return stats, nil
+}
+
+// GetProlificAuthors lists the authors with the most books, the most prolific first
+func (s *statsService) GetProlificAuthors(pagination dtos.Pagination) (dtos.ProlificAuthorsResponse, error) {
+	pagination.ValidValuesAndSetDefault()
+
+	key := fmt.Sprintf("prolific-authors:%d:%d", pagination.Page, pagination.Limit)
+	if cached, ok := s.cache.get(key); ok {
+		return cached.(dtos.ProlificAuthorsResponse), nil
+	}
+
+	authors, err := s.statsDb.GetProlificAuthors(pagination)
+	if err != nil {
+		log.Error("Error on get prolific authors from repo: ", err.Error())
+		return dtos.ProlificAuthorsResponse{}, err
+	}
+
+	stats := dtos.ProlificAuthorsResponse{Authors: authors, Pagination: pagination}
+	s.cache.set(key, stats)
+	return stats, nil
+}
+
+// GetBooksWithoutAuthors lists the books nobody is credited as author of
+func (s *statsService) GetBooksWithoutAuthors(pagination dtos.Pagination) (dtos.BooksWithoutAuthorsResponse, error) {
+	pagination.ValidValuesAndSetDefault()
+
+	key := fmt.Sprintf("books-without-authors:%d:%d", pagination.Page, pagination.Limit)
+	if cached, ok := s.cache.get(key); ok {
+		return cached.(dtos.BooksWithoutAuthorsResponse), nil
+	}
+
+	books, err := s.statsDb.GetBooksWithoutAuthors(pagination)
+	if err != nil {
+		log.Error("Error on get books without authors from repo: ", err.Error())
+		return dtos.BooksWithoutAuthorsResponse{}, err
+	}
+
+	stats := dtos.BooksWithoutAuthorsResponse{Books: books, Pagination: pagination}
+	s.cache.set(key, stats)
+	return stats, nil
+}
+
+// GetAuthorsWithoutBooks lists the authors not credited on any book
+func (s *statsService) GetAuthorsWithoutBooks(pagination dtos.Pagination) (dtos.AuthorsWithoutBooksResponse, error) {
+	pagination.ValidValuesAndSetDefault()
+
+	key := fmt.Sprintf("authors-without-books:%d:%d", pagination.Page, pagination.Limit)
+	if cached, ok := s.cache.get(key); ok {
+		return cached.(dtos.AuthorsWithoutBooksResponse), nil
+	}
+
+	authors, err := s.statsDb.GetAuthorsWithoutBooks(pagination)
+	if err != nil {
+		log.Error("Error on get authors without books from repo: ", err.Error())
+		return dtos.AuthorsWithoutBooksResponse{}, err
+	}
+
+	stats := dtos.AuthorsWithoutBooksResponse{Authors: authors, Pagination: pagination}
+	s.cache.set(key, stats)
+	return stats, nil
+}
+
+// GetImportStats sums up the import runs of the kind, or of every kind, per day, week, month or year
+func (s *statsService) GetImportStats(filter dtos.GetImportStatsFilter) (dtos.ImportStatsResponse, error) {
+	kind := strings.ToLower(strings.TrimSpace(filter.Kind))
+	interval := strings.ToLower(strings.TrimSpace(filter.Interval))
+	if interval == "" {
+		interval = dtos.StatsIntervalMonth
+	}
+	if !importIntervals[interval] {
+		return dtos.ImportStatsResponse{}, utils.ErrInvalidStatsInterval
+	}
+
+	key := fmt.Sprintf("imports:%s:%s", kind, interval)
+	if cached, ok := s.cache.get(key); ok {
+		return cached.(dtos.ImportStatsResponse), nil
+	}
+
+	volumes, err := s.statsDb.GetImportVolumes(kind, interval)
+	if err != nil {
+		log.Error("Error on get import volumes from repo: ", err.Error())
+		return dtos.ImportStatsResponse{}, err
+	}
+
+	stats := dtos.ImportStatsResponse{Kind: kind, Interval: interval, Volumes: volumes}
+	s.cache.set(key, stats)
+	return stats, nil
+}
@@ -0,0 +1,235 @@
+package services
+
+import (
+	"errors"
+	"github/brunojoenk/golang-test/models/dtos"
+	statsrepomock "github/brunojoenk/golang-test/repository/stats/mock"
+	"github/brunojoenk/golang-test/utils"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+var errGeneric = errors.New("generic error")
+
+func TestGetPublicationYearStats(t *testing.T) {
+	years := []dtos.YearBookCount{{Year: 1990, Books: 3}, {Year: 2000, Books: 5}}
+	tests := map[string]struct {
+		bucket                string
+		expectedBucket        string
+		expectedBucketSize    int
+		expectedErrorOnRepo   error
+		expectedErrorResponse error
+	}{
+		"success on get books per year (default bucket)": {
+			expectedBucket:     dtos.StatsBucketYear,
+			expectedBucketSize: 1,
+		},
+		"success on get books per decade": {
+			bucket:             " Decade ",
+			expectedBucket:     dtos.StatsBucketDecade,
+			expectedBucketSize: 10,
+		},
+		"error on get books per century (invalid bucket)": {
+			bucket:                "century",
+			expectedErrorResponse: utils.ErrInvalidStatsBucket,
+		},
+		"error on get books per year (repository)": {
+			expectedBucketSize:    1,
+			expectedErrorOnRepo:   errGeneric,
+			expectedErrorResponse: errGeneric,
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			statsDbMock := new(statsrepomock.StatsRepositoryMock)
+			statsDbMock.On("GetPublicationYearCounts", tc.expectedBucketSize).Return(years, tc.expectedErrorOnRepo)
+
+			statsServiceTest := statsService{statsDb: statsDbMock, cache: newStatsCache(0)}
+			stats, err := statsServiceTest.GetPublicationYearStats(dtos.GetPublicationYearStatsFilter{Bucket: tc.bucket})
+
+			if tc.expectedErrorResponse != nil {
+				require.ErrorIs(t, err, tc.expectedErrorResponse)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, dtos.PublicationYearStatsResponse{Bucket: tc.expectedBucket, Years: years}, stats)
+			}
+		})
+	}
+}
+
+func TestGetProlificAuthors(t *testing.T) {
+	authors := []dtos.AuthorBookCount{{AuthorId: 3, Name: "Stephen King", Books: 12}}
+	tests := map[string]struct {
+		expectedErrorOnRepo   error
+		expectedErrorResponse error
+	}{
+		"success on get prolific authors": {},
+		"error on get prolific authors": {
+			expectedErrorOnRepo:   errGeneric,
+			expectedErrorResponse: errGeneric,
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			statsDbMock := new(statsrepomock.StatsRepositoryMock)
+			statsDbMock.On("GetProlificAuthors", dtos.Pagination{Page: 1, Limit: 10}).Return(authors, tc.expectedErrorOnRepo)
+
+			statsServiceTest := statsService{statsDb: statsDbMock, cache: newStatsCache(0)}
+			stats, err := statsServiceTest.GetProlificAuthors(dtos.Pagination{})
+
+			if tc.expectedErrorResponse != nil {
+				require.ErrorIs(t, err, tc.expectedErrorResponse)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, dtos.ProlificAuthorsResponse{Authors: authors, Pagination: dtos.Pagination{Page: 1, Limit: 10}}, stats)
+			}
+		})
+	}
+}
+
+func TestGetBooksWithoutAuthors(t *testing.T) {
+	books := []dtos.StatsBook{{Id: 4, Name: "Beowulf", PublicationYear: 1000}}
+	tests := map[string]struct {
+		expectedErrorOnRepo   error
+		expectedErrorResponse error
+	}{
+		"success on get books without authors": {},
+		"error on get books without authors": {
+			expectedErrorOnRepo:   errGeneric,
+			expectedErrorResponse: errGeneric,
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			statsDbMock := new(statsrepomock.StatsRepositoryMock)
+			statsDbMock.On("GetBooksWithoutAuthors", dtos.Pagination{Page: 2, Limit: 5}).Return(books, tc.expectedErrorOnRepo)
+
+			statsServiceTest := statsService{statsDb: statsDbMock, cache: newStatsCache(0)}
+			stats, err := statsServiceTest.GetBooksWithoutAuthors(dtos.Pagination{Page: 2, Limit: 5})
+
+			if tc.expectedErrorResponse != nil {
+				require.ErrorIs(t, err, tc.expectedErrorResponse)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, dtos.BooksWithoutAuthorsResponse{Books: books, Pagination: dtos.Pagination{Page: 2, Limit: 5}}, stats)
+			}
+		})
+	}
+}
+
+func TestGetAuthorsWithoutBooks(t *testing.T) {
+	authors := []dtos.StatsAuthor{{Id: 8, Name: "Richard Bachman"}}
+	tests := map[string]struct {
+		expectedErrorOnRepo   error
+		expectedErrorResponse error
+	}{
+		"success on get authors without books": {},
+		"error on get authors without books": {
+			expectedErrorOnRepo:   errGeneric,
+			expectedErrorResponse: errGeneric,
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			statsDbMock := new(statsrepomock.StatsRepositoryMock)
+			statsDbMock.On("GetAuthorsWithoutBooks", dtos.Pagination{Page: 1, Limit: 10}).Return(authors, tc.expectedErrorOnRepo)
+
+			statsServiceTest := statsService{statsDb: statsDbMock, cache: newStatsCache(0)}
+			stats, err := statsServiceTest.GetAuthorsWithoutBooks(dtos.Pagination{})
+
+			if tc.expectedErrorResponse != nil {
+				require.ErrorIs(t, err, tc.expectedErrorResponse)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, dtos.AuthorsWithoutBooksResponse{Authors: authors, Pagination: dtos.Pagination{Page: 1, Limit: 10}}, stats)
+			}
+		})
+	}
+}
+
+func TestGetImportStats(t *testing.T) {
+	volumes := []dtos.ImportVolume{{Period: time.Date(2022, 10, 1, 0, 0, 0, 0, time.UTC), Runs: 2, Added: 120}}
+	tests := map[string]struct {
+		filter                dtos.GetImportStatsFilter
+		expectedKind          string
+		expectedInterval      string
+		expectedErrorOnRepo   error
+		expectedErrorResponse error
+	}{
+		"success on get import volumes per month (default interval)": {
+			expectedInterval: dtos.StatsIntervalMonth,
+		},
+		"success on get author import volumes per week": {
+			filter:           dtos.GetImportStatsFilter{Kind: "Authors", Interval: "WEEK"},
+			expectedKind:     dtos.ImportKindAuthors,
+			expectedInterval: dtos.StatsIntervalWeek,
+		},
+		"error on get import volumes per hour (invalid interval)": {
+			filter:                dtos.GetImportStatsFilter{Interval: "hour"},
+			expectedErrorResponse: utils.ErrInvalidStatsInterval,
+		},
+		"error on get import volumes (repository)": {
+			expectedInterval:      dtos.StatsIntervalMonth,
+			expectedErrorOnRepo:   errGeneric,
+			expectedErrorResponse: errGeneric,
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			statsDbMock := new(statsrepomock.StatsRepositoryMock)
+			statsDbMock.On("GetImportVolumes", tc.expectedKind, tc.expectedInterval).Return(volumes, tc.expectedErrorOnRepo)
+
+			statsServiceTest := statsService{statsDb: statsDbMock, cache: newStatsCache(0)}
+			stats, err := statsServiceTest.GetImportStats(tc.filter)
+
+			if tc.expectedErrorResponse != nil {
+				require.ErrorIs(t, err, tc.expectedErrorResponse)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, dtos.ImportStatsResponse{Kind: tc.expectedKind, Interval: tc.expectedInterval, Volumes: volumes}, stats)
+			}
+		})
+	}
+}
+
+func TestStatsCache(t *testing.T) {
+	years := []dtos.YearBookCount{{Year: 1990, Books: 3}}
+	statsDbMock := new(statsrepomock.StatsRepositoryMock)
+	statsDbMock.On("GetPublicationYearCounts", 1).Return(years, nil)
+	statsDbMock.On("GetPublicationYearCounts", 10).Return(years, nil)
+
+	now := time.Date(2022, 10, 1, 10, 0, 0, 0, time.UTC)
+	cache := newStatsCache(time.Minute)
+	cache.now = func() time.Time { return now }
+	statsServiceTest := statsService{statsDb: statsDbMock, cache: cache}
+
+	for i := 0; i < 2; i++ {
+		_, err := statsServiceTest.GetPublicationYearStats(dtos.GetPublicationYearStatsFilter{})
+		require.NoError(t, err)
+	}
+	statsDbMock.AssertNumberOfCalls(t, "GetPublicationYearCounts", 1)
+
+	_, err := statsServiceTest.GetPublicationYearStats(dtos.GetPublicationYearStatsFilter{Bucket: dtos.StatsBucketDecade})
+	require.NoError(t, err)
+	statsDbMock.AssertNumberOfCalls(t, "GetPublicationYearCounts", 2)
+
+	now = now.Add(time.Minute)
+	_, err = statsServiceTest.GetPublicationYearStats(dtos.GetPublicationYearStatsFilter{})
+	require.NoError(t, err)
+	statsDbMock.AssertNumberOfCalls(t, "GetPublicationYearCounts", 3)
+}
+
+func TestStatsCacheDisabled(t *testing.T) {
+	statsDbMock := new(statsrepomock.StatsRepositoryMock)
+	statsDbMock.On("GetImportVolumes", "", dtos.StatsIntervalMonth).Return([]dtos.ImportVolume{}, nil)
+
+	statsServiceTest := statsService{statsDb: statsDbMock, cache: newStatsCache(0)}
+	for i := 0; i < 2; i++ {
+		_, err := statsServiceTest.GetImportStats(dtos.GetImportStatsFilter{})
+		require.NoError(t, err)
+	}
+
+	statsDbMock.AssertNumberOfCalls(t, "GetImportVolumes", 2)
+}
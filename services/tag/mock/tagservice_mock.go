@@ -0,0 +1,16 @@
+package services
+
+import (
+	"github/brunojoenk/golang-test/models/dtos"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type TagServiceMock struct {
+	mock.Mock
+}
+
+func (m *TagServiceMock) GetTags(pagination dtos.Pagination) (dtos.TagResponseMetadata, error) {
+	args := m.Called(pagination)
+	return args.Get(0).(dtos.TagResponseMetadata), args.Error(1)
+}
@@ -0,0 +1,39 @@
+package services
+
+import (
+	"github/brunojoenk/golang-test/models/dtos"
+	tagrepo "github/brunojoenk/golang-test/repository/tag"
+
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type ITagService interface {
+	GetTags(pagination dtos.Pagination) (dtos.TagResponseMetadata, error)
+}
+
+type tagService struct {
+	tagDb tagrepo.ITagRepository
+}
+
+// NewTagService Service Constructor
+func NewTagService(db *gorm.DB) ITagService {
+	return &tagService{tagDb: tagrepo.NewTagRepository(db)}
+}
+
+// GetTags returns the tags with the number of books that have them, for building tag clouds
+func (t *tagService) GetTags(pagination dtos.Pagination) (dtos.TagResponseMetadata, error) {
+
+	pagination.ValidValuesAndSetDefault()
+
+	tags, err := t.tagDb.GetTagCounts(pagination)
+	if err != nil {
+		log.Error("Error on get tag counts from repo: ", err.Error())
+		return dtos.TagResponseMetadata{}, err
+	}
+
+	return dtos.TagResponseMetadata{
+		Tags:       tags,
+		Pagination: pagination,
+	}, nil
+}
@@ -0,0 +1,50 @@
+package services
+
+import (
+	"errors"
+	"github/brunojoenk/golang-test/models/dtos"
+	tagrepomock "github/brunojoenk/golang-test/repository/tag/mock"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var errGeneric = errors.New("generic error")
+
+func TestGetTags(t *testing.T) {
+	tags := []dtos.TagResponse{{Name: "dystopia", Count: 3}, {Name: "classic", Count: 1}}
+	tests := map[string]struct {
+		pagination            dtos.Pagination
+		expectedPagination    dtos.Pagination
+		expectedErrorOnGet    error
+		expectedErrorResponse error
+	}{
+		"success on get tags": {
+			pagination:         dtos.Pagination{Page: 2, Limit: 50},
+			expectedPagination: dtos.Pagination{Page: 2, Limit: 50},
+		},
+		"success on get tags (default pagination)": {
+			expectedPagination: dtos.Pagination{Page: 1, Limit: 10},
+		},
+		"error occurred on get tags": {
+			expectedPagination:    dtos.Pagination{Page: 1, Limit: 10},
+			expectedErrorOnGet:    errGeneric,
+			expectedErrorResponse: errGeneric,
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			tagDbMock := new(tagrepomock.TagRepositoryMock)
+			tagDbMock.On("GetTagCounts", tc.expectedPagination).Return(tags, tc.expectedErrorOnGet)
+
+			tagServiceTest := tagService{tagDb: tagDbMock}
+			resp, err := tagServiceTest.GetTags(tc.pagination)
+			if tc.expectedErrorResponse != nil {
+				require.ErrorIs(t, err, tc.expectedErrorResponse)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, dtos.TagResponseMetadata{Tags: tags, Pagination: tc.expectedPagination}, resp)
+			}
+		})
+	}
+}
@@ -0,0 +1,26 @@
+package services
+
+import (
+	"github/brunojoenk/golang-test/models/dtos"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type WorkServiceMock struct {
+	mock.Mock
+}
+
+func (m *WorkServiceMock) GetAllWorks(filter dtos.GetWorksFilter) (dtos.WorkResponseMetadata, error) {
+	args := m.Called(filter)
+	return args.Get(0).(dtos.WorkResponseMetadata), args.Error(1)
+}
+
+func (m *WorkServiceMock) GetWork(id int) (dtos.WorkResponse, error) {
+	args := m.Called(id)
+	return args.Get(0).(dtos.WorkResponse), args.Error(1)
+}
+
+func (m *WorkServiceMock) GetWorkEditions(id int, pagination dtos.Pagination) (dtos.BookResponseMetadata, error) {
+	args := m.Called(id, pagination)
+	return args.Get(0).(dtos.BookResponseMetadata), args.Error(1)
+}
@@ -0,0 +1,112 @@
+package services
+
+import (
+	"errors"
+	"github/brunojoenk/golang-test/models/dtos"
+	"github/brunojoenk/golang-test/models/entities"
+	bookrepo "github/brunojoenk/golang-test/repository/book"
+	workrepo "github/brunojoenk/golang-test/repository/work"
+	bookservice "github/brunojoenk/golang-test/services/book"
+	"github/brunojoenk/golang-test/utils"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type IWorkService interface {
+	GetAllWorks(filter dtos.GetWorksFilter) (dtos.WorkResponseMetadata, error)
+	GetWork(id int) (dtos.WorkResponse, error)
+	GetWorkEditions(id int, pagination dtos.Pagination) (dtos.BookResponseMetadata, error)
+}
+
+type workService struct {
+	workDb workrepo.IWorkRepository
+	bookDb bookrepo.IBookRepository
+}
+
+// NewWorkService Service Constructor
+func NewWorkService(db *gorm.DB) IWorkService {
+	return &workService{
+		workDb: workrepo.NewWorkRepository(db),
+		bookDb: bookrepo.NewBookRepository(db),
+	}
+}
+
+func (w *workService) GetAllWorks(filter dtos.GetWorksFilter) (dtos.WorkResponseMetadata, error) {
+
+	filter.Pagination.ValidValuesAndSetDefault()
+	works, err := w.workDb.GetAllWorks(filter)
+	if err != nil {
+		log.Error("Error on get all works from repo: ", err.Error())
+		return dtos.WorkResponseMetadata{}, err
+	}
+
+	worksResponse := make([]dtos.WorkResponse, len(works))
+	for i, work := range works {
+		worksResponse[i] = toWorkResponse(work)
+	}
+
+	return dtos.WorkResponseMetadata{
+		Works:      worksResponse,
+		Pagination: filter.Pagination,
+	}, nil
+}
+
+func (w *workService) GetWork(id int) (dtos.WorkResponse, error) {
+	work, err := w.getWork(id)
+	if err != nil {
+		return dtos.WorkResponse{}, err
+	}
+
+	return toWorkResponse(work), nil
+}
+
+// GetWorkEditions returns the books of a work from the oldest publication year to the newest
+func (w *workService) GetWorkEditions(id int, pagination dtos.Pagination) (dtos.BookResponseMetadata, error) {
+	if _, err := w.getWork(id); err != nil {
+		return dtos.BookResponseMetadata{}, err
+	}
+
+	pagination.ValidValuesAndSetDefault()
+	books, err := w.bookDb.GetWorkEditions(id, pagination)
+	if err != nil {
+		log.Error("Error on get editions of work from repo: ", err.Error())
+		return dtos.BookResponseMetadata{}, err
+	}
+
+	booksResponse := make([]dtos.BookResponse, len(books))
+	for i, book := range books {
+		booksResponse[i] = bookservice.ToBookResponse(book)
+	}
+
+	return dtos.BookResponseMetadata{
+		Books:      booksResponse,
+		Pagination: pagination,
+	}, nil
+}
+
+func (w *workService) getWork(id int) (entities.Work, error) {
+	work, err := w.workDb.GetWork(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return entities.Work{}, utils.ErrWorkIdNotFound
+		}
+		log.Error("Error on get work from repo: ", err.Error())
+		return entities.Work{}, err
+	}
+	return work, nil
+}
+
+func toWorkResponse(work entities.Work) dtos.WorkResponse {
+	authors := make([]string, len(work.Authors))
+	for i, author := range work.Authors {
+		authors[i] = author.Name
+	}
+
+	return dtos.WorkResponse{
+		Id:      work.Id,
+		Name:    work.Name,
+		Authors: strings.Join(authors, " | "),
+	}
+}
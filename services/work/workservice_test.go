@@ -0,0 +1,116 @@
+package services
+
+import (
+	"errors"
+	"github/brunojoenk/golang-test/models/dtos"
+	"github/brunojoenk/golang-test/models/entities"
+	bookrepomock "github/brunojoenk/golang-test/repository/book/mock"
+	workrepomock "github/brunojoenk/golang-test/repository/work/mock"
+	"github/brunojoenk/golang-test/utils"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+var errGeneric = errors.New("generic error")
+
+func TestGetAllWorks(t *testing.T) {
+	workDbMock := new(workrepomock.WorkRepositoryMock)
+	workDbMock.On("GetAllWorks", dtos.GetWorksFilter{Name: "hobbit", Pagination: dtos.Pagination{Page: 1, Limit: 10}}).
+		Return([]entities.Work{{Id: 1, Name: "The Hobbit", Authors: []entities.Author{{Id: 2, Name: "J. R. R. Tolkien"}}}}, nil)
+	workDbMock.On("GetAllWorks", dtos.GetWorksFilter{Name: "error", Pagination: dtos.Pagination{Page: 1, Limit: 10}}).
+		Return([]entities.Work{}, errGeneric)
+
+	workServiceTest := workService{workDb: workDbMock}
+	resp, err := workServiceTest.GetAllWorks(dtos.GetWorksFilter{Name: "hobbit"})
+
+	require.NoError(t, err)
+	require.Equal(t, []dtos.WorkResponse{{Id: 1, Name: "The Hobbit", Authors: "J. R. R. Tolkien"}}, resp.Works)
+
+	_, err = workServiceTest.GetAllWorks(dtos.GetWorksFilter{Name: "error"})
+	require.ErrorIs(t, err, errGeneric)
+}
+
+func TestGetWork(t *testing.T) {
+	tests := map[string]struct {
+		expectedErrorOnGet    error
+		expectedErrorResponse error
+	}{
+		"success on get work": {},
+		"error occurred on get work (not found)": {
+			expectedErrorOnGet:    gorm.ErrRecordNotFound,
+			expectedErrorResponse: utils.ErrWorkIdNotFound,
+		},
+		"error occurred on get work (get)": {
+			expectedErrorOnGet:    errGeneric,
+			expectedErrorResponse: errGeneric,
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			workDbMock := new(workrepomock.WorkRepositoryMock)
+			workDbMock.On("GetWork", 1).Return(entities.Work{Id: 1, Name: "Good Omens", Authors: []entities.Author{
+				{Id: 2, Name: "Neil Gaiman"}, {Id: 3, Name: "Terry Pratchett"},
+			}}, tc.expectedErrorOnGet)
+
+			workServiceTest := workService{workDb: workDbMock}
+			resp, err := workServiceTest.GetWork(1)
+			if tc.expectedErrorResponse != nil {
+				require.ErrorIs(t, err, tc.expectedErrorResponse)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, dtos.WorkResponse{Id: 1, Name: "Good Omens", Authors: "Neil Gaiman | Terry Pratchett"}, resp)
+			}
+		})
+	}
+}
+
+func TestGetWorkEditions(t *testing.T) {
+	var (
+		workId     = 4
+		pagination = dtos.Pagination{Page: 1, Limit: 10}
+	)
+	tests := map[string]struct {
+		expectedErrorOnGet         error
+		expectedErrorOnGetEditions error
+		expectedErrorResponse      error
+	}{
+		"success on get work editions": {},
+		"error occurred on get work editions (work not found)": {
+			expectedErrorOnGet:    gorm.ErrRecordNotFound,
+			expectedErrorResponse: utils.ErrWorkIdNotFound,
+		},
+		"error occurred on get work editions (get editions)": {
+			expectedErrorOnGetEditions: errGeneric,
+			expectedErrorResponse:      errGeneric,
+		},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			workDbMock := new(workrepomock.WorkRepositoryMock)
+			workDbMock.On("GetWork", workId).Return(entities.Work{Id: workId, Name: "The Hobbit"}, tc.expectedErrorOnGet)
+
+			bookDbMock := new(bookrepomock.BookRepositoryMock)
+			bookDbMock.On("GetWorkEditions", workId, pagination).Return([]entities.Book{
+				{Id: 1, Name: "The Hobbit", Edition: "1", PublicationYear: 1937, WorkId: &workId},
+				{Id: 5, Name: "The Hobbit", Edition: "4", PublicationYear: 1978, WorkId: &workId},
+			}, tc.expectedErrorOnGetEditions)
+
+			workServiceTest := workService{workDb: workDbMock, bookDb: bookDbMock}
+			resp, err := workServiceTest.GetWorkEditions(workId, dtos.Pagination{})
+			if tc.expectedErrorResponse != nil {
+				require.ErrorIs(t, err, tc.expectedErrorResponse)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, dtos.BookResponseMetadata{
+					Books: []dtos.BookResponse{
+						{Id: 1, Name: "The Hobbit", Edition: "1", PublicationYear: 1937, WorkId: 4},
+						{Id: 5, Name: "The Hobbit", Edition: "4", PublicationYear: 1978, WorkId: 4},
+					},
+					Pagination: pagination,
+				}, resp)
+			}
+		})
+	}
+}
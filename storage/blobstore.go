@@ -0,0 +1,55 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"github/brunojoenk/golang-test/config"
+	"io"
+	"net/http"
+	"time"
+)
+
+var (
+	// ErrBlobNotFound is returned when no blob is stored under the key
+	ErrBlobNotFound = errors.New("blob not found")
+	// ErrInvalidBlobRange is returned when a range starts past the end of the blob or is empty
+	ErrInvalidBlobRange = errors.New("blob range not satisfiable")
+)
+
+// Blob is a stored object. The caller closes the body
+type Blob struct {
+	Body io.ReadCloser
+	Size int64
+}
+
+// BlobStore keeps binary objects, e.g. cover images, under slash separated keys like "covers/7/original".
+// Putting a key again replaces the object, and deleting a missing key is not an error. GetRange reads
+// length bytes from offset, fewer when the blob ends first
+type BlobStore interface {
+	Put(key string, body io.Reader, size int64, contentType string) error
+	Get(key string) (Blob, error)
+	GetRange(key string, offset, length int64) (Blob, error)
+	Delete(key string) error
+}
+
+// NewBlobStore returns the blob store picked in the configuration: local or s3
+func NewBlobStore(cfg *config.Config) (BlobStore, error) {
+	switch cfg.BlobStore {
+	case "local":
+		return &LocalBlobStore{Root: cfg.BlobStorePath}, nil
+	case "s3":
+		if cfg.S3Endpoint == "" || cfg.S3Bucket == "" {
+			return nil, fmt.Errorf("s3 endpoint and bucket must be set")
+		}
+		return &S3BlobStore{
+			Endpoint:  cfg.S3Endpoint,
+			Region:    cfg.S3Region,
+			Bucket:    cfg.S3Bucket,
+			AccessKey: cfg.S3AccessKey,
+			SecretKey: cfg.S3SecretKey,
+			Client:    &http.Client{Timeout: time.Minute},
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown blob store %q, must be local or s3", cfg.BlobStore)
+	}
+}
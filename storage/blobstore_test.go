@@ -0,0 +1,31 @@
+package storage
+
+import (
+	"github/brunojoenk/golang-test/config"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewBlobStore(t *testing.T) {
+	tests := map[string]struct {
+		cfg           config.Config
+		expectedError bool
+	}{
+		"local":                 {cfg: config.Config{BlobStore: "local", BlobStorePath: "./data/blobs"}},
+		"s3":                    {cfg: config.Config{BlobStore: "s3", S3Endpoint: "http://localhost:9000", S3Bucket: "library"}},
+		"s3 without the bucket": {cfg: config.Config{BlobStore: "s3", S3Endpoint: "http://localhost:9000"}, expectedError: true},
+		"unknown":               {cfg: config.Config{BlobStore: "floppy"}, expectedError: true},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			store, err := NewBlobStore(&tc.cfg)
+			if tc.expectedError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.NotNil(t, store)
+		})
+	}
+}
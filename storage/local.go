@@ -0,0 +1,114 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// LocalBlobStore keeps each blob as a file under the root directory, the key being its relative path
+type LocalBlobStore struct {
+	Root string
+}
+
+func (l *LocalBlobStore) Put(key string, body io.Reader, size int64, contentType string) error {
+	path, err := l.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	// Written aside and renamed, so a reader never sees half a blob
+	file, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+
+	written, err := io.Copy(file, body)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	if written != size {
+		return fmt.Errorf("blob %s has %d bytes, expected %d", key, written, size)
+	}
+
+	return os.Rename(file.Name(), path)
+}
+
+func (l *LocalBlobStore) Get(key string) (Blob, error) {
+	path, err := l.path(key)
+	if err != nil {
+		return Blob{}, err
+	}
+
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return Blob{}, ErrBlobNotFound
+	}
+	if err != nil {
+		return Blob{}, err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return Blob{}, err
+	}
+
+	return Blob{Body: file, Size: info.Size()}, nil
+}
+
+func (l *LocalBlobStore) GetRange(key string, offset, length int64) (Blob, error) {
+	blob, err := l.Get(key)
+	if err != nil {
+		return Blob{}, err
+	}
+	if offset < 0 || length <= 0 || offset >= blob.Size {
+		blob.Body.Close()
+		return Blob{}, ErrInvalidBlobRange
+	}
+
+	file := blob.Body.(*os.File)
+	if _, err := file.Seek(offset, io.SeekStart); err != nil {
+		file.Close()
+		return Blob{}, err
+	}
+	if offset+length > blob.Size {
+		length = blob.Size - offset
+	}
+
+	return Blob{Body: limitedReadCloser{Reader: io.LimitReader(file, length), Closer: file}, Size: length}, nil
+}
+
+func (l *LocalBlobStore) Delete(key string) error {
+	path, err := l.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// path is where the blob of the key is kept. Keys can not leave the root directory
+func (l *LocalBlobStore) path(key string) (string, error) {
+	cleaned := filepath.Clean("/" + key)
+	if key == "" || cleaned == "/" || strings.HasPrefix(key, "/") || cleaned != "/"+key {
+		return "", fmt.Errorf("invalid blob key %q", key)
+	}
+	return filepath.Join(l.Root, filepath.FromSlash(key)), nil
+}
+
+type limitedReadCloser struct {
+	io.Reader
+	io.Closer
+}
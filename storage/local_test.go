@@ -0,0 +1,82 @@
+package storage
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalBlobStore(t *testing.T) {
+	root := t.TempDir()
+	store := &LocalBlobStore{Root: root}
+
+	require.NoError(t, store.Put("covers/7/original", strings.NewReader("first"), 5, "image/png"))
+	require.NoError(t, store.Put("covers/7/original", strings.NewReader("second"), 6, "image/png"))
+
+	blob, err := store.Get("covers/7/original")
+	require.NoError(t, err)
+	data, err := io.ReadAll(blob.Body)
+	require.NoError(t, blob.Body.Close())
+	require.NoError(t, err)
+	require.Equal(t, "second", string(data))
+	require.Equal(t, int64(6), blob.Size)
+
+	entries, err := os.ReadDir(filepath.Join(root, "covers", "7"))
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+
+	require.NoError(t, store.Delete("covers/7/original"))
+	require.NoError(t, store.Delete("covers/7/original"))
+	_, err = store.Get("covers/7/original")
+	require.ErrorIs(t, err, ErrBlobNotFound)
+}
+
+func TestLocalBlobStoreGetRange(t *testing.T) {
+	store := &LocalBlobStore{Root: t.TempDir()}
+	require.NoError(t, store.Put("files/7/book.pdf", strings.NewReader("0123456789"), 10, "application/pdf"))
+
+	blob, err := store.GetRange("files/7/book.pdf", 2, 3)
+	require.NoError(t, err)
+	data, err := io.ReadAll(blob.Body)
+	require.NoError(t, blob.Body.Close())
+	require.NoError(t, err)
+	require.Equal(t, "234", string(data))
+	require.Equal(t, int64(3), blob.Size)
+
+	blob, err = store.GetRange("files/7/book.pdf", 8, 10)
+	require.NoError(t, err)
+	data, err = io.ReadAll(blob.Body)
+	require.NoError(t, blob.Body.Close())
+	require.NoError(t, err)
+	require.Equal(t, "89", string(data))
+	require.Equal(t, int64(2), blob.Size)
+
+	_, err = store.GetRange("files/7/book.pdf", 10, 1)
+	require.ErrorIs(t, err, ErrInvalidBlobRange)
+	_, err = store.GetRange("files/7/other.pdf", 0, 1)
+	require.ErrorIs(t, err, ErrBlobNotFound)
+}
+
+func TestLocalBlobStoreShortBody(t *testing.T) {
+	store := &LocalBlobStore{Root: t.TempDir()}
+
+	require.Error(t, store.Put("covers/7/original", strings.NewReader("abc"), 5, "image/png"))
+
+	_, err := store.Get("covers/7/original")
+	require.ErrorIs(t, err, ErrBlobNotFound)
+}
+
+func TestLocalBlobStoreInvalidKeys(t *testing.T) {
+	store := &LocalBlobStore{Root: t.TempDir()}
+
+	for _, key := range []string{"", "/etc/passwd", "../outside", "covers/../../outside", "covers//7", "covers/7/"} {
+		require.Error(t, store.Put(key, strings.NewReader("x"), 1, "image/png"), key)
+		_, err := store.Get(key)
+		require.Error(t, err, key)
+		require.Error(t, store.Delete(key), key)
+	}
+}
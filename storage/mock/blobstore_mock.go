@@ -0,0 +1,32 @@
+package storage
+
+import (
+	"github/brunojoenk/golang-test/storage"
+	"io"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type BlobStoreMock struct {
+	mock.Mock
+}
+
+func (m *BlobStoreMock) Put(key string, body io.Reader, size int64, contentType string) error {
+	args := m.Called(key, body, size, contentType)
+	return args.Error(0)
+}
+
+func (m *BlobStoreMock) Get(key string) (storage.Blob, error) {
+	args := m.Called(key)
+	return args.Get(0).(storage.Blob), args.Error(1)
+}
+
+func (m *BlobStoreMock) GetRange(key string, offset, length int64) (storage.Blob, error) {
+	args := m.Called(key, offset, length)
+	return args.Get(0).(storage.Blob), args.Error(1)
+}
+
+func (m *BlobStoreMock) Delete(key string) error {
+	args := m.Called(key)
+	return args.Error(0)
+}
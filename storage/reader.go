@@ -0,0 +1,73 @@
+package storage
+
+import (
+	"errors"
+	"io"
+)
+
+// blobReader reads a blob of a known size through ranged gets, so seeking costs nothing until the next
+// read. It lets http.ServeContent answer range requests without downloading the parts it skips
+type blobReader struct {
+	store  BlobStore
+	key    string
+	size   int64
+	offset int64
+	body   io.ReadCloser
+}
+
+// NewBlobReader returns a reader of the blob stored under the key, which is size bytes long. The blob
+// is only opened on the first read
+func NewBlobReader(store BlobStore, key string, size int64) io.ReadSeekCloser {
+	return &blobReader{store: store, key: key, size: size}
+}
+
+func (r *blobReader) Read(p []byte) (int, error) {
+	if r.offset >= r.size {
+		return 0, io.EOF
+	}
+	if r.body == nil {
+		blob, err := r.store.GetRange(r.key, r.offset, r.size-r.offset)
+		if err != nil {
+			return 0, err
+		}
+		r.body = blob.Body
+	}
+
+	n, err := r.body.Read(p)
+	r.offset += int64(n)
+	if errors.Is(err, io.EOF) && r.offset < r.size {
+		err = io.ErrUnexpectedEOF
+	}
+	return n, err
+}
+
+func (r *blobReader) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekCurrent:
+		offset += r.offset
+	case io.SeekEnd:
+		offset += r.size
+	}
+	if offset < 0 {
+		return 0, errors.New("seek before the start of the blob")
+	}
+
+	if offset != r.offset {
+		r.closeBody()
+		r.offset = offset
+	}
+	return offset, nil
+}
+
+func (r *blobReader) Close() error {
+	return r.closeBody()
+}
+
+func (r *blobReader) closeBody() error {
+	if r.body == nil {
+		return nil
+	}
+	err := r.body.Close()
+	r.body = nil
+	return err
+}
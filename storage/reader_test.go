@@ -0,0 +1,76 @@
+package storage
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlobReader(t *testing.T) {
+	store := &LocalBlobStore{Root: t.TempDir()}
+	require.NoError(t, store.Put("files/7/book.pdf", strings.NewReader("0123456789"), 10, "application/pdf"))
+	reader := NewBlobReader(store, "files/7/book.pdf", 10)
+	defer reader.Close()
+
+	size, err := reader.Seek(0, io.SeekEnd)
+	require.NoError(t, err)
+	require.Equal(t, int64(10), size)
+
+	_, err = reader.Seek(4, io.SeekStart)
+	require.NoError(t, err)
+	data := make([]byte, 3)
+	_, err = io.ReadFull(reader, data)
+	require.NoError(t, err)
+	require.Equal(t, "456", string(data))
+
+	_, err = reader.Seek(-2, io.SeekCurrent)
+	require.NoError(t, err)
+	rest, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	require.Equal(t, "56789", string(rest))
+
+	_, err = reader.Seek(-1, io.SeekStart)
+	require.Error(t, err)
+}
+
+func TestBlobReaderServeContent(t *testing.T) {
+	server, _ := newFakeS3(t)
+	store := newTestS3BlobStore(server.URL, testSecretKey)
+	require.NoError(t, store.Put("files/7/book.pdf", strings.NewReader("0123456789"), 10, "application/pdf"))
+
+	tests := map[string]struct {
+		rangeHeader    string
+		expectedStatus int
+		expectedBody   string
+	}{
+		"whole blob":         {expectedStatus: http.StatusOK, expectedBody: "0123456789"},
+		"range":              {rangeHeader: "bytes=2-5", expectedStatus: http.StatusPartialContent, expectedBody: "2345"},
+		"suffix range":       {rangeHeader: "bytes=-3", expectedStatus: http.StatusPartialContent, expectedBody: "789"},
+		"range past the end": {rangeHeader: "bytes=20-", expectedStatus: http.StatusRequestedRangeNotSatisfiable},
+		"open ended range":   {rangeHeader: "bytes=7-", expectedStatus: http.StatusPartialContent, expectedBody: "789"},
+		"range over the end": {rangeHeader: "bytes=8-30", expectedStatus: http.StatusPartialContent, expectedBody: "89"},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			reader := NewBlobReader(store, "files/7/book.pdf", 10)
+			defer reader.Close()
+			request := httptest.NewRequest(http.MethodGet, "/downloads/1", nil)
+			if tc.rangeHeader != "" {
+				request.Header.Set("Range", tc.rangeHeader)
+			}
+			recorder := httptest.NewRecorder()
+
+			http.ServeContent(recorder, request, "", time.Time{}, reader)
+
+			require.Equal(t, tc.expectedStatus, recorder.Code)
+			if tc.expectedBody != "" {
+				require.Equal(t, tc.expectedBody, recorder.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,229 @@
+package storage
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// unsignedPayload lets a body be streamed instead of hashed up front
+	unsignedPayload = "UNSIGNED-PAYLOAD"
+	amzDateFormat   = "20060102T150405Z"
+)
+
+// S3BlobStore keeps the blobs on a bucket of an S3 compatible server, e.g. AWS S3 or MinIO. Requests are
+// path style, http://endpoint/bucket/key, and signed with AWS Signature Version 4
+type S3BlobStore struct {
+	Endpoint  string
+	Region    string
+	Bucket    string
+	AccessKey string
+	SecretKey string
+	Client    *http.Client
+	now       func() time.Time
+}
+
+func (s *S3BlobStore) Put(key string, body io.Reader, size int64, contentType string) error {
+	req, err := s.newRequest(http.MethodPut, key, body)
+	if err != nil {
+		return err
+	}
+	req.ContentLength = size
+	req.Header.Set("Content-Type", contentType)
+
+	resp, err := s.do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return responseError(resp)
+	}
+	return nil
+}
+
+func (s *S3BlobStore) Get(key string) (Blob, error) {
+	req, err := s.newRequest(http.MethodGet, key, nil)
+	if err != nil {
+		return Blob{}, err
+	}
+
+	resp, err := s.do(req)
+	if err != nil {
+		return Blob{}, err
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		resp.Body.Close()
+		return Blob{}, ErrBlobNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		return Blob{}, responseError(resp)
+	}
+
+	return Blob{Body: resp.Body, Size: resp.ContentLength}, nil
+}
+
+func (s *S3BlobStore) GetRange(key string, offset, length int64) (Blob, error) {
+	if offset < 0 || length <= 0 {
+		return Blob{}, ErrInvalidBlobRange
+	}
+	req, err := s.newRequest(http.MethodGet, key, nil)
+	if err != nil {
+		return Blob{}, err
+	}
+	req.Header.Set("Range", fmt.Sprintf("bytes=%d-%d", offset, offset+length-1))
+
+	resp, err := s.do(req)
+	if err != nil {
+		return Blob{}, err
+	}
+	switch resp.StatusCode {
+	case http.StatusPartialContent:
+		return Blob{Body: resp.Body, Size: resp.ContentLength}, nil
+	case http.StatusNotFound:
+		resp.Body.Close()
+		return Blob{}, ErrBlobNotFound
+	case http.StatusRequestedRangeNotSatisfiable:
+		resp.Body.Close()
+		return Blob{}, ErrInvalidBlobRange
+	default:
+		defer resp.Body.Close()
+		return Blob{}, responseError(resp)
+	}
+}
+
+func (s *S3BlobStore) Delete(key string) error {
+	req, err := s.newRequest(http.MethodDelete, key, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := s.do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNotFound {
+		return responseError(resp)
+	}
+	return nil
+}
+
+func (s *S3BlobStore) newRequest(method, key string, body io.Reader) (*http.Request, error) {
+	if key == "" {
+		return nil, fmt.Errorf("invalid blob key %q", key)
+	}
+	target := strings.TrimRight(s.Endpoint, "/") + "/" + uriEncode(s.Bucket, true) + "/" + uriEncode(key, false)
+	return http.NewRequest(method, target, body)
+}
+
+func (s *S3BlobStore) do(req *http.Request) (*http.Response, error) {
+	now := time.Now
+	if s.now != nil {
+		now = s.now
+	}
+	req.Header.Set("X-Amz-Content-Sha256", unsignedPayload)
+	signV4(req, s.AccessKey, s.SecretKey, s.Region, now())
+	return s.Client.Do(req)
+}
+
+func responseError(resp *http.Response) error {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	return fmt.Errorf("s3 answered %s: %s", resp.Status, strings.TrimSpace(string(body)))
+}
+
+// signV4 signs the request for the s3 service with AWS Signature Version 4. The host, the range and
+// the x-amz-* headers are signed, and the X-Amz-Content-Sha256 header must already be set
+func signV4(req *http.Request, accessKey, secretKey, region string, now time.Time) {
+	amzDate := now.UTC().Format(amzDateFormat)
+	req.Header.Set("X-Amz-Date", amzDate)
+	scope := amzDate[:8] + "/" + region + "/s3/aws4_request"
+
+	host := req.Host
+	if host == "" {
+		host = req.URL.Host
+	}
+	headers := map[string]string{"host": host}
+	for name, values := range req.Header {
+		name = strings.ToLower(name)
+		if name == "range" || strings.HasPrefix(name, "x-amz-") {
+			headers[name] = strings.TrimSpace(strings.Join(values, ","))
+		}
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		canonicalQuery(req.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		req.Header.Get("X-Amz-Content-Sha256"),
+	}, "\n")
+	hash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(hash[:])
+
+	key := hmacSHA256([]byte("AWS4"+secretKey), amzDate[:8])
+	for _, part := range []string{region, "s3", "aws4_request"} {
+		key = hmacSHA256(key, part)
+	}
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		accessKey, scope, signedHeaders, signature))
+}
+
+func canonicalQuery(query url.Values) string {
+	params := make([]string, 0, len(query))
+	for name, values := range query {
+		for _, value := range values {
+			params = append(params, uriEncode(name, true)+"="+uriEncode(value, true))
+		}
+	}
+	sort.Strings(params)
+	return strings.Join(params, "&")
+}
+
+// uriEncode percent encodes everything but the unreserved characters of RFC 3986, and the slashes unless
+// encodeSlash, as Signature Version 4 expects
+func uriEncode(s string, encodeSlash bool) string {
+	var encoded strings.Builder
+	for _, b := range []byte(s) {
+		switch {
+		case 'A' <= b && b <= 'Z', 'a' <= b && b <= 'z', '0' <= b && b <= '9', b == '-', b == '_', b == '.', b == '~':
+			encoded.WriteByte(b)
+		case b == '/' && !encodeSlash:
+			encoded.WriteByte(b)
+		default:
+			fmt.Fprintf(&encoded, "%%%02X", b)
+		}
+	}
+	return encoded.String()
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
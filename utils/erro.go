@@ -1,8 +1,154 @@
 package utils
 
-import "github.com/pkg/errors"
+import (
+	"fmt"
+
+	"github.com/jackc/pgconn"
+	"github.com/pkg/errors"
+)
 
 var (
 	ErrAuthorIdNotFound = errors.New("Author ID not found")
 	ErrBookIdNotFound   = errors.New("Book ID not found")
+	ErrApiKeyIdNotFound = errors.New("Api key ID not found")
+	ErrApiKeyInvalid    = errors.New("Api key is invalid, expired or revoked")
+	ErrApiKeyScope      = errors.New("Api key scope is invalid")
+
+	ErrIdempotencyKeyReused     = errors.New("Idempotency key was already used with a different request")
+	ErrIdempotencyKeyInProgress = errors.New("A request with this idempotency key is still in progress")
+
+	ErrBookMergeSameId   = errors.New("A book can not be merged into itself")
+	ErrAuthorMergeSameId = errors.New("An author can not be merged into itself")
+
+	ErrAuthorAliasIdNotFound = errors.New("Author alias ID not found")
+	ErrAuthorAliasNameEmpty  = errors.New("Author alias name is empty")
+	ErrAuthorAliasConflict   = errors.New("Author alias is already the name or an alias of the author, or the name of another author")
+
+	ErrInvalidDate        = errors.New("Invalid date, use YYYY-MM-DD or YYYY")
+	ErrInvalidCountryCode = errors.New("Invalid country, use an ISO 3166-1 alpha-2 code")
+	ErrInvalidWebsite     = errors.New("Invalid website, use an http or https URL")
+	ErrInvalidOrcid       = errors.New("Invalid ORCID")
+	ErrInvalidViaf        = errors.New("Invalid VIAF ID")
+	ErrInvalidWikidataId  = errors.New("Invalid Wikidata QID")
+
+	ErrInvalidIsbn      = errors.New("Invalid ISBN, use an ISBN-10 or ISBN-13 with a valid check digit")
+	ErrBookIsbnExists   = errors.New("Another book already has this ISBN")
+	ErrBookIsbnNotFound = errors.New("Book ISBN not found")
+
+	ErrPublisherIdNotFound = errors.New("Publisher ID not found")
+	ErrPublisherNameEmpty  = errors.New("Publisher name is empty")
+	ErrPublisherNameExists = errors.New("Another publisher already has this name")
+	ErrPublisherHasBooks   = errors.New("Publisher has books, move them to another publisher before deleting it")
+
+	ErrGenreIdNotFound       = errors.New("Genre ID not found")
+	ErrGenreParentIdNotFound = errors.New("Parent genre ID not found")
+	ErrGenreNameEmpty        = errors.New("Genre name is empty")
+	ErrGenreNameExists       = errors.New("The parent genre already has a subgenre with this name")
+	ErrGenreCycle            = errors.New("A genre can not be moved under itself or one of its subgenres")
+	ErrGenreHasChildren      = errors.New("Genre has subgenres, delete or move them before deleting it")
+
+	ErrSeriesIdNotFound      = errors.New("Series ID not found")
+	ErrSeriesNameEmpty       = errors.New("Series name is empty")
+	ErrSeriesNameExists      = errors.New("Series name already exists")
+	ErrSeriesHasBooks        = errors.New("Series has books, remove them from the series before deleting it")
+	ErrInvalidSeriesPosition = errors.New("Series position must be greater than zero and needs a series")
+
+	ErrWorkIdNotFound = errors.New("Work ID not found")
+
+	ErrCopyIdNotFound       = errors.New("Copy ID not found")
+	ErrInvalidCopyBarcode   = errors.New("Copy barcode must have between 1 and 50 characters")
+	ErrCopyBarcodeExists    = errors.New("Another copy already has this barcode")
+	ErrInvalidCopyStatus    = errors.New("Copy status must be available, on_loan, on_hold, lost or withdrawn")
+	ErrInvalidCopyCondition = errors.New("Copy condition must be new, good, fair or poor")
+	ErrBookHasCopies        = errors.New("Book has copies, delete them before deleting the book")
+	ErrCopyOnLoan           = errors.New("Copy is on loan, check it in first")
+	ErrCopyStatusOnLoan     = errors.New("Copy status on_loan is set by checking the copy out and in")
+	ErrCopyStatusOnHold     = errors.New("Copy status on_hold is set by the hold queue of the book")
+
+	ErrPatronIdNotFound  = errors.New("Patron ID not found")
+	ErrPatronNameEmpty   = errors.New("Patron name is empty")
+	ErrInvalidEmail      = errors.New("Invalid email")
+	ErrPatronEmailExists = errors.New("Another patron already has this email")
+	ErrPatronHasLoans    = errors.New("Patron has copies on loan, check them in before deleting the patron")
+
+	ErrLoanIdNotFound      = errors.New("Loan ID not found")
+	ErrCopyNotAvailable    = errors.New("Copy is not available to check out")
+	ErrCopyBarcodeNotFound = errors.New("Copy barcode not found")
+	ErrInvalidDueDate      = errors.New("Due date must be a date after today, use YYYY-MM-DD")
+	ErrInvalidLoanStatus   = errors.New("Loan status must be active, overdue or returned")
+	ErrLoanReturned        = errors.New("Loan was already returned")
+	ErrLoanRenewalLimit    = errors.New("Loan reached the renewal limit")
+	ErrLoanChanged         = errors.New("Loan was changed by another request, try again")
+
+	ErrHoldIdNotFound = errors.New("Hold ID not found")
+	ErrHoldExists     = errors.New("Patron already has a hold on this book")
+	ErrHoldClosed     = errors.New("Hold was already fulfilled, cancelled or expired")
+	ErrCopyOnHold     = errors.New("Copy is on hold for another patron")
+
+	ErrListIdNotFound        = errors.New("Reading list ID not found")
+	ErrListOwnerRequired     = errors.New("Reading lists belong to the owner of an api key, send the X-API-Key header")
+	ErrListNotOwner          = errors.New("Only the owner of a reading list can change it")
+	ErrInvalidListName       = errors.New("Reading list name must have between 1 and 100 characters")
+	ErrInvalidListVisibility = errors.New("Reading list visibility must be public or private")
+	ErrListNoteTooLong       = errors.New("Note must have at most 1000 characters")
+	ErrListBookExists        = errors.New("Book is already on this reading list")
+	ErrListBookNotFound      = errors.New("Book is not on this reading list")
+	ErrInvalidListOrder      = errors.New("Order must have every book of the reading list once")
+	ErrInvalidExportFormat   = errors.New("Export format must be csv or json")
+
+	ErrReviewIdNotFound    = errors.New("Review ID not found")
+	ErrInvalidRating       = errors.New("Rating must be a whole number from 1 to 5")
+	ErrReviewTextTooLong   = errors.New("Review text must have at most 5000 characters")
+	ErrReviewExists        = errors.New("Patron already reviewed this book, update the review instead")
+	ErrReviewPatron        = errors.New("The patron of a review can not be changed")
+	ErrReviewOwnerRequired = errors.New("Reviews belong to the owner of an api key, send the X-API-Key header")
+	ErrReviewNotOwner      = errors.New("Only the owner of a review can change it")
+	ErrInvalidMinRating    = errors.New("Min rating must be from 0 to 5")
+	ErrInvalidBookSort     = errors.New("Book sort must be name or rating")
+
+	ErrInvalidAmount        = errors.New("Amount must be greater than zero, in cents")
+	ErrAmountExceedsBalance = errors.New("Amount is greater than the balance of the patron")
+	ErrInvalidOutboxStatus  = errors.New("Outbox status must be pending, sent or failed")
+
+	ErrInvalidLanguage   = errors.New("Invalid language, use an ISO 639 code")
+	ErrInvalidLocale     = errors.New("Invalid title locale, use a language tag like pt-BR")
+	ErrBookTitleEmpty    = errors.New("Translated title is empty")
+	ErrBookTitleRepeated = errors.New("A locale can have only one translated title")
+
+	ErrInvalidAuthorRole  = errors.New("Author role must be author, editor, translator or illustrator")
+	ErrBookAuthorRepeated = errors.New("An author can be listed only once with the same role on a book")
+
+	ErrInvalidTag       = errors.New("Tag must have between 1 and 50 characters and no commas")
+	ErrInvalidTagsMatch = errors.New("Tags match must be any or all")
+	ErrBookTagNotFound  = errors.New("Book does not have this tag")
+
+	ErrInvalidStatsBucket   = errors.New("Stats bucket must be year or decade")
+	ErrInvalidStatsInterval = errors.New("Stats interval must be day, week, month or year")
+
+	ErrCoverNotFound     = errors.New("Book has no cover")
+	ErrCoverTooLarge     = errors.New("Cover image is too large")
+	ErrInvalidCoverType  = errors.New("Cover must be a JPEG, PNG or WebP image")
+	ErrInvalidCoverImage = errors.New("Cover image is corrupted or its dimensions are too large")
+	ErrInvalidCoverSize  = errors.New("Cover size must be original, small or medium")
+
+	ErrBookFileNotFound    = errors.New("Book file ID not found")
+	ErrBookFileTooLarge    = errors.New("Book file is too large")
+	ErrInvalidBookFileType = errors.New("Book file must be a PDF or EPUB file")
+	ErrInvalidBookFile     = errors.New("Book file is corrupted")
+	ErrInvalidDownloadLink = errors.New("Download link is invalid or expired")
 )
+
+// BookDuplicatedError points at the book that already has the same name, edition, publication year and authors
+type BookDuplicatedError struct {
+	ExistingBookId int
+}
+
+func (e BookDuplicatedError) Error() string {
+	return fmt.Sprintf("Book duplicated. Book ID %d already has the same name, edition, publication year and authors", e.ExistingBookId)
+}
+
+// IsUniqueViolation tells an error of the database about a row that breaks the unique index or constraint
+func IsUniqueViolation(err error, constraint string) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505" && pgErr.ConstraintName == constraint
+}
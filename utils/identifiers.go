@@ -0,0 +1,105 @@
+package utils
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const DATE_LAYOUT = "2006-01-02"
+
+var (
+	orcidRegex    = regexp.MustCompile(`^\d{4}-\d{4}-\d{4}-\d{3}[\dX]$`)
+	viafRegex     = regexp.MustCompile(`^[1-9]\d{0,21}$`)
+	wikidataRegex = regexp.MustCompile(`^Q[1-9]\d*$`)
+)
+
+// countryCodes are the officially assigned ISO 3166-1 alpha-2 codes
+var countryCodes = strings.Fields(`
+	AD AE AF AG AI AL AM AO AQ AR AS AT AU AW AX AZ BA BB BD BE BF BG BH BI BJ BL BM BN BO BQ BR BS
+	BT BV BW BY BZ CA CC CD CF CG CH CI CK CL CM CN CO CR CU CV CW CX CY CZ DE DJ DK DM DO DZ EC EE
+	EG EH ER ES ET FI FJ FK FM FO FR GA GB GD GE GF GG GH GI GL GM GN GP GQ GR GS GT GU GW GY HK HM
+	HN HR HT HU ID IE IL IM IN IO IQ IR IS IT JE JM JO JP KE KG KH KI KM KN KP KR KW KY KZ LA LB LC
+	LI LK LR LS LT LU LV LY MA MC MD ME MF MG MH MK ML MM MN MO MP MQ MR MS MT MU MV MW MX MY MZ NA
+	NC NE NF NG NI NL NO NP NR NU NZ OM PA PE PF PG PH PK PL PM PN PR PS PT PW PY QA RE RO RS RU RW
+	SA SB SC SD SE SG SH SI SJ SK SL SM SN SO SR SS ST SV SX SY SZ TC TD TF TG TH TJ TK TL TM TN TO
+	TR TT TV TW TZ UA UG UM US UY UZ VA VC VE VG VI VN VU WF WS YE YT ZA ZM ZW`)
+
+// ParseDate parses a full date (YYYY-MM-DD) or only a year (YYYY), which is taken as January 1st
+func ParseDate(date string) (time.Time, error) {
+	date = strings.TrimSpace(date)
+	for _, layout := range []string{DATE_LAYOUT, "2006"} {
+		if parsed, err := time.Parse(layout, date); err == nil {
+			return parsed, nil
+		}
+	}
+	return time.Time{}, ErrInvalidDate
+}
+
+// NormalizeCountryCode returns the upper case ISO 3166-1 alpha-2 code, e.g. "br" -> "BR"
+func NormalizeCountryCode(code string) (string, error) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	for _, valid := range countryCodes {
+		if code == valid {
+			return code, nil
+		}
+	}
+	return "", ErrInvalidCountryCode
+}
+
+// NormalizeWebsite accepts absolute http and https URLs
+func NormalizeWebsite(website string) (string, error) {
+	website = strings.TrimSpace(website)
+	parsed, err := url.Parse(website)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return "", ErrInvalidWebsite
+	}
+	return website, nil
+}
+
+// NormalizeOrcid accepts an ORCID iD, bare or as https://orcid.org URL, checking its
+// ISO 7064 11,2 check digit. e.g. "https://orcid.org/0000-0002-1825-0097" -> "0000-0002-1825-0097"
+func NormalizeOrcid(orcid string) (string, error) {
+	orcid = strings.ToUpper(strings.TrimSpace(orcid))
+	orcid = strings.TrimPrefix(strings.TrimPrefix(orcid, "HTTPS://ORCID.ORG/"), "HTTP://ORCID.ORG/")
+	if !orcidRegex.MatchString(orcid) {
+		return "", ErrInvalidOrcid
+	}
+
+	total := 0
+	digits := strings.ReplaceAll(orcid, "-", "")
+	for _, digit := range digits[:len(digits)-1] {
+		total = (total + int(digit-'0')) * 2
+	}
+	check := (12 - total%11) % 11
+	expected := byte('0' + check)
+	if check == 10 {
+		expected = 'X'
+	}
+	if digits[len(digits)-1] != expected {
+		return "", ErrInvalidOrcid
+	}
+
+	return orcid, nil
+}
+
+// NormalizeViaf accepts a VIAF ID, bare or as https://viaf.org/viaf URL
+func NormalizeViaf(viaf string) (string, error) {
+	viaf = strings.TrimSuffix(strings.TrimSpace(viaf), "/")
+	viaf = viaf[strings.LastIndex(viaf, "/")+1:]
+	if !viafRegex.MatchString(viaf) {
+		return "", ErrInvalidViaf
+	}
+	return viaf, nil
+}
+
+// NormalizeWikidataId accepts a Wikidata item ID (QID), bare or as https://www.wikidata.org/wiki URL
+func NormalizeWikidataId(qid string) (string, error) {
+	qid = strings.ToUpper(strings.TrimSpace(qid))
+	qid = qid[strings.LastIndex(qid, "/")+1:]
+	if !wikidataRegex.MatchString(qid) {
+		return "", ErrInvalidWikidataId
+	}
+	return qid, nil
+}
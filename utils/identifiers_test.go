@@ -0,0 +1,92 @@
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDate(t *testing.T) {
+	date, err := ParseDate(" 1892-01-03 ")
+	require.NoError(t, err)
+	require.Equal(t, time.Date(1892, 1, 3, 0, 0, 0, 0, time.UTC), date)
+
+	date, err = ParseDate("1892")
+	require.NoError(t, err)
+	require.Equal(t, time.Date(1892, 1, 1, 0, 0, 0, 0, time.UTC), date)
+
+	_, err = ParseDate("03/01/1892")
+	require.ErrorIs(t, err, ErrInvalidDate)
+}
+
+func TestNormalizeCountryCode(t *testing.T) {
+	code, err := NormalizeCountryCode(" br ")
+	require.NoError(t, err)
+	require.Equal(t, "BR", code)
+
+	_, err = NormalizeCountryCode("BRA")
+	require.ErrorIs(t, err, ErrInvalidCountryCode)
+
+	_, err = NormalizeCountryCode("XX")
+	require.ErrorIs(t, err, ErrInvalidCountryCode)
+}
+
+func TestNormalizeWebsite(t *testing.T) {
+	website, err := NormalizeWebsite("https://www.tolkienestate.com/")
+	require.NoError(t, err)
+	require.Equal(t, "https://www.tolkienestate.com/", website)
+
+	_, err = NormalizeWebsite("www.tolkienestate.com")
+	require.ErrorIs(t, err, ErrInvalidWebsite)
+
+	_, err = NormalizeWebsite("ftp://tolkienestate.com")
+	require.ErrorIs(t, err, ErrInvalidWebsite)
+}
+
+func TestNormalizeOrcid(t *testing.T) {
+	tests := map[string]struct {
+		orcid    string
+		expected string
+		err      error
+	}{
+		"bare":              {orcid: "0000-0002-1825-0097", expected: "0000-0002-1825-0097"},
+		"url":               {orcid: "https://orcid.org/0000-0002-1825-0097", expected: "0000-0002-1825-0097"},
+		"check digit X":     {orcid: "0000-0002-1694-233x", expected: "0000-0002-1694-233X"},
+		"wrong check digit": {orcid: "0000-0002-1825-0098", err: ErrInvalidOrcid},
+		"wrong format":      {orcid: "0000000218250097", err: ErrInvalidOrcid},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			orcid, err := NormalizeOrcid(tc.orcid)
+			require.ErrorIs(t, err, tc.err)
+			require.Equal(t, tc.expected, orcid)
+		})
+	}
+}
+
+func TestNormalizeViaf(t *testing.T) {
+	viaf, err := NormalizeViaf("95218067")
+	require.NoError(t, err)
+	require.Equal(t, "95218067", viaf)
+
+	viaf, err = NormalizeViaf("https://viaf.org/viaf/95218067/")
+	require.NoError(t, err)
+	require.Equal(t, "95218067", viaf)
+
+	_, err = NormalizeViaf("v95218067")
+	require.ErrorIs(t, err, ErrInvalidViaf)
+}
+
+func TestNormalizeWikidataId(t *testing.T) {
+	qid, err := NormalizeWikidataId("q892")
+	require.NoError(t, err)
+	require.Equal(t, "Q892", qid)
+
+	qid, err = NormalizeWikidataId("https://www.wikidata.org/wiki/Q892")
+	require.NoError(t, err)
+	require.Equal(t, "Q892", qid)
+
+	_, err = NormalizeWikidataId("Q0892")
+	require.ErrorIs(t, err, ErrInvalidWikidataId)
+}
@@ -0,0 +1,67 @@
+package utils
+
+import "strings"
+
+// NormalizeIsbn validates the check digit of an ISBN-10 or ISBN-13, ignoring hyphens and spaces,
+// and returns it as ISBN-13. e.g. "0-261-10320-2" -> "9780261103207"
+func NormalizeIsbn(isbn string) (string, error) {
+	isbn = strings.ToUpper(strings.NewReplacer("-", "", " ", "").Replace(strings.TrimSpace(isbn)))
+
+	switch len(isbn) {
+	case 10:
+		if !isDigits(isbn[:9]) || isbn10CheckDigit(isbn[:9]) != isbn[9] {
+			return "", ErrInvalidIsbn
+		}
+		isbn13 := "978" + isbn[:9]
+		return isbn13 + string(isbn13CheckDigit(isbn13)), nil
+	case 13:
+		if !isDigits(isbn) || (!strings.HasPrefix(isbn, "978") && !strings.HasPrefix(isbn, "979")) ||
+			isbn13CheckDigit(isbn[:12]) != isbn[12] {
+			return "", ErrInvalidIsbn
+		}
+		return isbn, nil
+	}
+
+	return "", ErrInvalidIsbn
+}
+
+// Isbn13To10 returns the ISBN-10 of an ISBN-13, or "" for the 979 prefix, which has no ISBN-10
+func Isbn13To10(isbn13 string) string {
+	if len(isbn13) != 13 || !strings.HasPrefix(isbn13, "978") {
+		return ""
+	}
+	return isbn13[3:12] + string(isbn10CheckDigit(isbn13[3:12]))
+}
+
+func isbn10CheckDigit(digits string) byte {
+	total := 0
+	for i, digit := range digits {
+		total += (10 - i) * int(digit-'0')
+	}
+	check := (11 - total%11) % 11
+	if check == 10 {
+		return 'X'
+	}
+	return byte('0' + check)
+}
+
+func isbn13CheckDigit(digits string) byte {
+	total := 0
+	for i, digit := range digits {
+		weight := 1
+		if i%2 == 1 {
+			weight = 3
+		}
+		total += weight * int(digit-'0')
+	}
+	return byte('0' + (10-total%10)%10)
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
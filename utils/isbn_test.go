@@ -0,0 +1,40 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeIsbn(t *testing.T) {
+	tests := map[string]struct {
+		isbn     string
+		expected string
+		err      error
+	}{
+		"isbn-13":                  {isbn: "9780261103207", expected: "9780261103207"},
+		"isbn-13 with hyphens":     {isbn: "978-0-261-10320-7", expected: "9780261103207"},
+		"isbn-13 with 979 prefix":  {isbn: "979-10-90636-07-1", expected: "9791090636071"},
+		"isbn-10":                  {isbn: "0-261-10320-2", expected: "9780261103207"},
+		"isbn-10 with check X":     {isbn: "0-8044-2957-x", expected: "9780804429573"},
+		"isbn-13 wrong check":      {isbn: "9780261103208", err: ErrInvalidIsbn},
+		"isbn-13 wrong prefix":     {isbn: "9770261103200", err: ErrInvalidIsbn},
+		"isbn-10 wrong check":      {isbn: "0261103203", err: ErrInvalidIsbn},
+		"isbn-10 with letters":     {isbn: "02611A3202", err: ErrInvalidIsbn},
+		"wrong length":             {isbn: "97802611032", err: ErrInvalidIsbn},
+		"isbn-13 with check digit": {isbn: "978026110320X", err: ErrInvalidIsbn},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			isbn, err := NormalizeIsbn(tc.isbn)
+			require.ErrorIs(t, err, tc.err)
+			require.Equal(t, tc.expected, isbn)
+		})
+	}
+}
+
+func TestIsbn13To10(t *testing.T) {
+	require.Equal(t, "0261103202", Isbn13To10("9780261103207"))
+	require.Equal(t, "080442957X", Isbn13To10("9780804429573"))
+	require.Equal(t, "", Isbn13To10("9791090636071"))
+}
@@ -0,0 +1,47 @@
+package utils
+
+import (
+	"strings"
+
+	"golang.org/x/text/language"
+)
+
+// NormalizeLanguage returns the ISO 639 code of a language, the two letter one when it exists,
+// e.g. "POR" -> "pt"
+func NormalizeLanguage(code string) (string, error) {
+	base, err := language.ParseBase(strings.TrimSpace(code))
+	if err != nil || base.String() == "und" {
+		return "", ErrInvalidLanguage
+	}
+	return base.String(), nil
+}
+
+// NormalizeLocale returns the canonical form of a BCP 47 language tag, e.g. "pt_br" -> "pt-BR"
+func NormalizeLocale(locale string) (string, error) {
+	tag, err := language.Parse(strings.ReplaceAll(strings.TrimSpace(locale), "_", "-"))
+	if err != nil || tag == language.Und {
+		return "", ErrInvalidLocale
+	}
+	return tag.String(), nil
+}
+
+// BestLocale returns the index of the locale that best fits an Accept-Language header, or -1 when
+// the header is empty, invalid or fits none of them. A language may stand in for a close one, e.g.
+// "pt-BR" for "pt-PT"
+func BestLocale(acceptLanguage string, locales []string) int {
+	desired, _, err := language.ParseAcceptLanguage(acceptLanguage)
+	if err != nil || len(desired) == 0 || len(locales) == 0 {
+		return -1
+	}
+
+	supported := make([]language.Tag, len(locales))
+	for i, locale := range locales {
+		supported[i] = language.Make(locale)
+	}
+
+	_, index, confidence := language.NewMatcher(supported).Match(desired...)
+	if confidence == language.No {
+		return -1
+	}
+	return index
+}
@@ -0,0 +1,63 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeLanguage(t *testing.T) {
+	code, err := NormalizeLanguage(" POR ")
+	require.NoError(t, err)
+	require.Equal(t, "pt", code)
+
+	code, err = NormalizeLanguage("fil")
+	require.NoError(t, err)
+	require.Equal(t, "fil", code)
+
+	for _, invalid := range []string{"", "xx", "und", "english", "pt-BR"} {
+		_, err = NormalizeLanguage(invalid)
+		require.ErrorIs(t, err, ErrInvalidLanguage, invalid)
+	}
+}
+
+func TestNormalizeLocale(t *testing.T) {
+	locale, err := NormalizeLocale("pt_br")
+	require.NoError(t, err)
+	require.Equal(t, "pt-BR", locale)
+
+	locale, err = NormalizeLocale("zh-hant")
+	require.NoError(t, err)
+	require.Equal(t, "zh-Hant", locale)
+
+	for _, invalid := range []string{"", "und", "xx-YY", "pt-"} {
+		_, err = NormalizeLocale(invalid)
+		require.ErrorIs(t, err, ErrInvalidLocale, invalid)
+	}
+}
+
+func TestBestLocale(t *testing.T) {
+	locales := []string{"en", "pt-BR", "es"}
+	tests := map[string]struct {
+		acceptLanguage string
+		expected       int
+	}{
+		"exact":            {acceptLanguage: "es", expected: 2},
+		"language only":    {acceptLanguage: "pt", expected: 1},
+		"close region":     {acceptLanguage: "pt-PT", expected: 1},
+		"quality order":    {acceptLanguage: "fr, es;q=0.5, pt-BR;q=0.8", expected: 1},
+		"none fits":        {acceptLanguage: "fr, de;q=0.5", expected: -1},
+		"quality zero":     {acceptLanguage: "es;q=0", expected: -1},
+		"empty header":     {acceptLanguage: "", expected: -1},
+		"invalid header":   {acceptLanguage: "pt;q=x=", expected: -1},
+		"first of the tie": {acceptLanguage: "es, en", expected: 2},
+		"wildcard":         {acceptLanguage: "*", expected: -1},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			require.Equal(t, tc.expected, BestLocale(tc.acceptLanguage, locales))
+		})
+	}
+
+	require.Equal(t, -1, BestLocale("en", nil))
+}
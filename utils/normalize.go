@@ -0,0 +1,71 @@
+package utils
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// NormalizeName folds case, accents, punctuation and spacing, so names that differ only on
+// those details compare equal. e.g. "  O Senhor dos Anéis: " -> "o senhor dos aneis"
+func NormalizeName(name string) string {
+	var builder strings.Builder
+	for _, r := range norm.NFD.String(strings.ToLower(name)) {
+		switch {
+		case unicode.Is(unicode.Mn, r):
+			continue
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			builder.WriteRune(r)
+		default:
+			builder.WriteRune(' ')
+		}
+	}
+	return strings.Join(strings.Fields(builder.String()), " ")
+}
+
+// CleanAuthorName tidies an author name for display: spacing, "Last, First" order, initials
+// and the case of names written all in upper or lower case. e.g. "TOLKIEN, J.R.R." -> "J. R. R. Tolkien"
+func CleanAuthorName(name string) string {
+	name = strings.Join(strings.Fields(name), " ")
+
+	if parts := strings.Split(name, ","); len(parts) == 2 {
+		last, first := strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1])
+		if last != "" && first != "" {
+			name = first + " " + last
+		}
+	}
+
+	name = strings.ReplaceAll(name, ".", ". ")
+	tokens := strings.Fields(name)
+	fixCase := strings.ToUpper(name) == name || strings.ToLower(name) == name
+	for i, token := range tokens {
+		if fixCase {
+			runes := []rune(strings.ToLower(token))
+			runes[0] = unicode.ToUpper(runes[0])
+			token = string(runes)
+		}
+		if runes := []rune(token); len(runes) == 1 && unicode.IsLetter(runes[0]) {
+			token += "."
+		}
+		tokens[i] = token
+	}
+
+	return strings.Join(tokens, " ")
+}
+
+// NormalizeAuthorName is the key used to compare author names, e.g. "Tolkien, J.R.R." -> "j r r tolkien"
+func NormalizeAuthorName(name string) string {
+	return NormalizeName(CleanAuthorName(name))
+}
+
+// NormalizeTag lower cases a tag and collapses its spacing, e.g. " Space  Opera " -> "space opera". Unlike
+// NormalizeName it keeps punctuation, so "c++" and "c#" are different tags
+func NormalizeTag(tag string) (string, error) {
+	tag = strings.Join(strings.Fields(strings.ToLower(tag)), " ")
+	if tag == "" || utf8.RuneCountInString(tag) > 50 || strings.Contains(tag, ",") {
+		return "", ErrInvalidTag
+	}
+	return tag, nil
+}
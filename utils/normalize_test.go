@@ -0,0 +1,78 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeName(t *testing.T) {
+	tests := map[string]struct {
+		name     string
+		expected string
+	}{
+		"lower case":         {name: "Harry Potter", expected: "harry potter"},
+		"accents":            {name: "O Senhor dos Anéis", expected: "o senhor dos aneis"},
+		"punctuation":        {name: "Harry Potter: and the Philosopher's Stone!", expected: "harry potter and the philosopher s stone"},
+		"spaces":             {name: "  Clean   Code \t", expected: "clean code"},
+		"digits":             {name: "Python 3 - Fluent", expected: "python 3 fluent"},
+		"empty":              {name: " - ", expected: ""},
+		"non latin alphabet": {name: "Οδύσσεια", expected: "οδυσσεια"},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			require.Equal(t, tc.expected, NormalizeName(tc.name))
+		})
+	}
+}
+
+func TestCleanAuthorName(t *testing.T) {
+	tests := map[string]struct {
+		name     string
+		expected string
+	}{
+		"already clean":    {name: "Luciano Ramalho", expected: "Luciano Ramalho"},
+		"spaces":           {name: "  Luciano   Ramalho ", expected: "Luciano Ramalho"},
+		"last, first":      {name: "Tolkien, J.R.R.", expected: "J. R. R. Tolkien"},
+		"initials":         {name: "J.K Rowling", expected: "J. K. Rowling"},
+		"spaced initials":  {name: "J. R. R. Tolkien", expected: "J. R. R. Tolkien"},
+		"upper case":       {name: "STEPHEN KING", expected: "Stephen King"},
+		"lower case":       {name: "king, stephen", expected: "Stephen King"},
+		"mixed case":       {name: "Ursula K. Le Guin", expected: "Ursula K. Le Guin"},
+		"many commas kept": {name: "King, Stephen, Jr.", expected: "King, Stephen, Jr."},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			require.Equal(t, tc.expected, CleanAuthorName(tc.name))
+		})
+	}
+}
+
+func TestNormalizeAuthorName(t *testing.T) {
+	require.Equal(t, "j r r tolkien", NormalizeAuthorName("Tolkien, J.R.R."))
+	require.Equal(t, NormalizeAuthorName("J. R. R. Tolkien"), NormalizeAuthorName("Tolkien, J.R.R."))
+	require.Equal(t, NormalizeAuthorName("j.k rowling"), NormalizeAuthorName("Rowling, J. K."))
+}
+
+func TestNormalizeTag(t *testing.T) {
+	tests := map[string]struct {
+		tag           string
+		expected      string
+		expectedError error
+	}{
+		"lower case":  {tag: "Dystopia", expected: "dystopia"},
+		"spaces":      {tag: "  Space \t Opera ", expected: "space opera"},
+		"punctuation": {tag: "C++", expected: "c++"},
+		"empty":       {tag: "  ", expectedError: ErrInvalidTag},
+		"comma":       {tag: "a,b", expectedError: ErrInvalidTag},
+		"too long":    {tag: strings.Repeat("a", 51), expectedError: ErrInvalidTag},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			tag, err := NormalizeTag(tc.tag)
+			require.ErrorIs(t, err, tc.expectedError)
+			require.Equal(t, tc.expected, tag)
+		})
+	}
+}
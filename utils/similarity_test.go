@@ -0,0 +1,28 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestJaroWinkler(t *testing.T) {
+	tests := map[string]struct {
+		a        string
+		b        string
+		expected float64
+	}{
+		"equal":           {a: "tolkien", b: "tolkien", expected: 1},
+		"both empty":      {a: "", b: "", expected: 1},
+		"one empty":       {a: "tolkien", b: "", expected: 0},
+		"nothing shared":  {a: "abc", b: "xyz", expected: 0},
+		"martha":          {a: "martha", b: "marhta", expected: 0.961},
+		"dixon":           {a: "dixon", b: "dicksonx", expected: 0.813},
+		"transliteration": {a: "tchaikovsky", b: "tchaikowsky", expected: 0.963},
+	}
+	for testName, tc := range tests {
+		t.Run(testName, func(t *testing.T) {
+			require.InDelta(t, tc.expected, JaroWinkler(tc.a, tc.b), 0.001)
+		})
+	}
+}